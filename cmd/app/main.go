@@ -6,7 +6,12 @@ import (
 	"hub_management_service/internal/router"
 	"hub_management_service/internal/service"
 	"hub_management_service/pkg/database"
+	"hub_management_service/pkg/oidc"
 	"log"
+	"os"
+	"strconv"
+	"time"
+	_ "time/tzdata" // Embed the time zone database, the runtime image does not ship one
 )
 
 func main() {
@@ -16,15 +21,104 @@ func main() {
 	hubRepo := repository.NewHubRepository(db)
 	teamRepo := repository.NewTeamRepository(db)
 	userRepo := repository.NewUserRepository(db)
+	membershipRepo := repository.NewMembershipRepository(db)
+	mfaRepo := repository.NewMFARepository(db)
+	regionRepo := repository.NewRegionRepository(db)
+	countryRepo := repository.NewCountryRepository(db)
+	scheduleRepo := repository.NewScheduleRepository(db)
+	resourceRepo := repository.NewResourceRepository(db)
+	bookingRepo := repository.NewBookingRepository(db)
+	attendanceRepo := repository.NewAttendanceRepository(db)
+	// Services run their multi-step operations in a transaction
+	transactor := repository.NewTransactor(db)
 
-	hubService := service.NewHubService(hubRepo)
-	teamService := service.NewTeamService(teamRepo, hubRepo)
-	userService := service.NewUserService(userRepo, teamRepo)
+	// Reject moves into hubs whose headcount reaches HUB_CAPACITY_THRESHOLD times their capacity
+	threshold := service.DefaultCapacityThreshold
+	if value := os.Getenv("HUB_CAPACITY_THRESHOLD"); value != "" {
+		parsed, err := strconv.ParseFloat(value, 64)
+		if err != nil || parsed <= 0 {
+			log.Fatalf("invalid HUB_CAPACITY_THRESHOLD %q", value)
+		}
+		threshold = parsed
+	}
+	capacity := service.NewCapacityPolicy(hubRepo, teamRepo, threshold)
 
-	hubHandler := handler.NewHubHandler(hubService)
-	teamHandler := handler.NewTeamHandler(teamService)
-	userHandler := handler.NewUserHandler(userService)
+	// Keep raw check-in events for ATTENDANCE_RETENTION_DAYS, daily counts are kept for good
+	retentionDays := service.DefaultAttendanceRetentionDays
+	if value := os.Getenv("ATTENDANCE_RETENTION_DAYS"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed <= 0 {
+			log.Fatalf("invalid ATTENDANCE_RETENTION_DAYS %q", value)
+		}
+		retentionDays = parsed
+	}
 
-	r := router.NewRouter(hubHandler, teamHandler, userHandler)
+	// Replay the responses of create requests retried with the same Idempotency-Key for IDEMPOTENCY_KEY_TTL_HOURS
+	idempotencyTTL := service.DefaultIdempotencyKeyTTL
+	if value := os.Getenv("IDEMPOTENCY_KEY_TTL_HOURS"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed <= 0 {
+			log.Fatalf("invalid IDEMPOTENCY_KEY_TTL_HOURS %q", value)
+		}
+		idempotencyTTL = time.Duration(parsed) * time.Hour
+	}
+
+	hubService := service.NewHubService(transactor, hubRepo, countryRepo, capacity)
+	teamService := service.NewTeamService(transactor, teamRepo, hubRepo)
+	userService := service.NewUserService(transactor, userRepo, teamRepo, capacity)
+	membershipService := service.NewMembershipService(transactor, membershipRepo, userRepo, teamRepo, capacity)
+	mfaService := service.NewMFAService(transactor, mfaRepo)
+	regionService := service.NewRegionService(transactor, regionRepo, countryRepo, hubRepo)
+	scheduleService := service.NewScheduleService(transactor, scheduleRepo, hubRepo)
+	resourceService := service.NewResourceService(transactor, resourceRepo, hubRepo)
+	bookingService := service.NewBookingService(transactor, bookingRepo, resourceRepo, scheduleRepo, hubRepo, userRepo)
+	attendanceService := service.NewAttendanceService(transactor, attendanceRepo, userRepo, hubRepo, teamRepo, retentionDays)
+	expandService := service.NewExpandService(hubRepo, teamRepo, userRepo, countryRepo, resourceRepo)
+	idempotencyService := service.NewIdempotencyService(repository.NewIdempotencyRepository(db), idempotencyTTL)
+	go purgeDaily("expired visits", attendanceService.PurgeExpired)
+	go purgeDaily("expired idempotency keys", idempotencyService.PurgeExpired)
+
+	authHandler := handler.NewAuthHandler(mfaService)
+	hubHandler := handler.NewHubHandler(hubService, expandService)
+	teamHandler := handler.NewTeamHandler(teamService, expandService)
+	userHandler := handler.NewUserHandler(userService, expandService)
+	membershipHandler := handler.NewMembershipHandler(membershipService)
+	regionHandler := handler.NewRegionHandler(regionService, expandService)
+	scheduleHandler := handler.NewScheduleHandler(scheduleService)
+	resourceHandler := handler.NewResourceHandler(resourceService, expandService)
+	bookingHandler := handler.NewBookingHandler(bookingService, expandService)
+	attendanceHandler := handler.NewAttendanceHandler(attendanceService)
+	importHandler := handler.NewImportHandler(service.NewImportService(transactor, capacity))
+	batchHandler := handler.NewBatchHandler(service.NewBatchService(transactor, capacity))
+	exportHandler := handler.NewExportHandler(service.NewExportService(repository.NewExportRepository(db), hubRepo, teamRepo))
+	orgChartHandler := handler.NewOrgChartHandler(service.NewOrgChartService(hubRepo, teamRepo, userRepo))
+	searchHandler := handler.NewSearchHandler(service.NewSearchService(repository.NewSearchRepository(db)), expandService)
+	scimHandler := handler.NewSCIMHandler(service.NewSCIMService(transactor, userRepo, teamRepo, hubRepo, capacity))
+
+	// Delegate login to the corporate identity provider when configured
+	var oidcHandler *handler.OIDCHandler
+	if issuer := os.Getenv("OIDC_ISSUER"); issuer != "" {
+		oidcClient := oidc.NewClient(oidc.Config{
+			Issuer:       issuer,
+			ClientID:     os.Getenv("OIDC_CLIENT_ID"),
+			ClientSecret: os.Getenv("OIDC_CLIENT_SECRET"),
+			RedirectURL:  os.Getenv("OIDC_REDIRECT_URL"),
+		}, nil)
+		oidcHandler = handler.NewOIDCHandler(service.NewOIDCService(transactor, oidcClient, userRepo))
+	}
+
+	r := router.NewRouter(authHandler, oidcHandler, hubHandler, teamHandler, userHandler, membershipHandler, regionHandler, scheduleHandler, resourceHandler, bookingHandler, attendanceHandler, importHandler, batchHandler, exportHandler, orgChartHandler, searchHandler, scimHandler, idempotencyService)
 	log.Fatal(r.Run(":8080"))
 }
+
+// purgeDaily deletes expired records at startup and then once a day
+func purgeDaily(what string, purge func() (int64, error)) {
+	for {
+		if deleted, err := purge(); err != nil {
+			log.Printf("purging %s: %v", what, err)
+		} else if deleted > 0 {
+			log.Printf("purged %d %s", deleted, what)
+		}
+		time.Sleep(24 * time.Hour)
+	}
+}
@@ -0,0 +1,84 @@
+// Command import creates hubs, teams or users from a CSV or JSON Lines file in a single transaction.
+//
+//	import -kind users [-format csv|jsonl] [-dry-run] [-override-capacity] users.csv
+//
+// The file may be - for standard input. The report is printed as JSON; the exit status is 1 when rows were rejected.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"hub_management_service/internal/repository"
+	"hub_management_service/internal/service"
+	"hub_management_service/pkg/database"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+func main() {
+	kind := flag.String("kind", "", "records to create: hubs, teams or users")
+	format := flag.String("format", "", "csv or jsonl, from the file extension by default")
+	dryRun := flag.Bool("dry-run", false, "check every row and report the errors without keeping anything")
+	overrideCapacity := flag.Bool("override-capacity", false, "allow users into hubs at or above the capacity threshold")
+	flag.Parse()
+	if *kind == "" || flag.NArg() != 1 {
+		fmt.Fprintln(os.Stderr, "usage: import -kind hubs|teams|users [-format csv|jsonl] [-dry-run] [-override-capacity] FILE")
+		os.Exit(2)
+	}
+
+	path := flag.Arg(0)
+	var input io.Reader = os.Stdin
+	if path != "-" {
+		file, err := os.Open(path)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer file.Close()
+		input = file
+	}
+	if *format == "" {
+		switch strings.ToLower(filepath.Ext(path)) {
+		case ".jsonl", ".ndjson":
+			*format = string(service.ImportJSONL)
+		default:
+			*format = string(service.ImportCSV)
+		}
+	}
+
+	// Apply the same capacity threshold as the API
+	threshold := service.DefaultCapacityThreshold
+	if value := os.Getenv("HUB_CAPACITY_THRESHOLD"); value != "" {
+		parsed, err := strconv.ParseFloat(value, 64)
+		if err != nil || parsed <= 0 {
+			log.Fatalf("invalid HUB_CAPACITY_THRESHOLD %q", value)
+		}
+		threshold = parsed
+	}
+
+	db := database.InitDB()
+	defer database.CloseDB(db)
+	capacity := service.NewCapacityPolicy(repository.NewHubRepository(db), repository.NewTeamRepository(db), threshold)
+	importService := service.NewImportService(repository.NewTransactor(db), capacity)
+
+	report, err := importService.Import(service.ImportKind(*kind), input, service.ImportOptions{
+		Format:       service.ImportFormat(*format),
+		DryRun:       *dryRun,
+		WriteOptions: service.WriteOptions{OverrideCapacity: *overrideCapacity},
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	encoder.Encode(report)
+	if len(report.Errors) > 0 {
+		database.CloseDB(db)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,41 @@
+package entity
+
+import "time"
+
+// Visit is a stay of a user at a hub from check-in to check-out. Visits are the raw attendance events
+// and are only kept for the retention period, DailyAttendance keeps the counts.
+type Visit struct {
+	ID           uint       `gorm:"primaryKey" json:"id"`
+	UserID       uint       `gorm:"not null;index" json:"user_id"`
+	HubID        uint       `gorm:"not null;index:visits_hub_date_idx" json:"hub_id"`
+	TeamID       uint       `gorm:"not null" json:"team_id"`                                  // Primary team of the user at check-in
+	Date         Date       `gorm:"type:date;not null;index:visits_hub_date_idx" json:"date"` // Day of the check-in in the hub's time zone
+	CheckedInAt  time.Time  `gorm:"not null;index" json:"checked_in_at"`
+	CheckedOutAt *time.Time `json:"checked_out_at,omitempty"`
+	User         *User      `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"user,omitempty"`
+	Hub          *Hub       `gorm:"foreignKey:HubID;constraint:OnDelete:CASCADE" json:"-"`
+}
+
+// DailyAttendance counts the distinct users of a team who checked in at a hub on a day
+type DailyAttendance struct {
+	Date   Date  `gorm:"type:date;primaryKey" json:"date"`
+	HubID  uint  `gorm:"primaryKey" json:"hub_id"`
+	TeamID uint  `gorm:"primaryKey" json:"team_id"`
+	Users  int64 `gorm:"not null" json:"users"`
+}
+
+func (DailyAttendance) TableName() string {
+	return "attendance_daily"
+}
+
+// DailyVisitor records that a user checked in at a hub on a day. Its key lets only the first check-in of the day
+// count the user in DailyAttendance, even when two check-ins race. Kept as long as the visits.
+type DailyVisitor struct {
+	Date   Date `gorm:"type:date;primaryKey" json:"date"`
+	HubID  uint `gorm:"primaryKey" json:"hub_id"`
+	UserID uint `gorm:"primaryKey" json:"user_id"`
+}
+
+func (DailyVisitor) TableName() string {
+	return "attendance_visitors"
+}
@@ -0,0 +1,18 @@
+package entity
+
+import "time"
+
+// Booking reserves a desk or room for a user over a time range, starting at StartsAt and ending before EndsAt.
+// Active bookings of a resource never overlap; cancelled bookings are kept for history.
+type Booking struct {
+	ID          uint       `gorm:"primaryKey" json:"id"`
+	ResourceID  uint       `gorm:"not null;index:bookings_resource_time_idx" json:"resource_id"`
+	UserID      uint       `gorm:"not null;index" json:"user_id"` // Set from the account, administrators may book for any user
+	StartsAt    time.Time  `gorm:"not null;index:bookings_resource_time_idx" json:"starts_at" binding:"required"`
+	EndsAt      time.Time  `gorm:"not null" json:"ends_at" binding:"required"`
+	Title       string     `gorm:"size:255" json:"title,omitempty" binding:"max=255"`
+	CancelledAt *time.Time `json:"cancelled_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	Resource    *Resource  `gorm:"foreignKey:ResourceID;constraint:OnDelete:CASCADE" json:"resource,omitempty"`
+	User        *User      `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"user,omitempty"`
+}
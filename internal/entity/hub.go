@@ -1,8 +1,26 @@
 package entity
 
 type Hub struct {
-	ID       uint    `gorm:"primaryKey" json:"id,omitempty"`
-	Name     string  `gorm:"size:255;not null" json:"name" binding:"required,min=3,max=255"`
-	Location string  `gorm:"size:255;not null" json:"location" binding:"required,min=3,max=255"`
-	Teams    *[]Team `gorm:"foreignKey:HubID" json:"teams,omitempty"`
+	ID        uint        `gorm:"primaryKey" json:"id,omitempty"`
+	Name      string      `gorm:"size:255;not null" json:"name" binding:"required,min=3,max=255"`
+	Location  string      `gorm:"size:255;not null" json:"location" binding:"required,min=3,max=255"`
+	CountryID *uint       `gorm:"index" json:"country_id,omitempty"` // Places the hub in a country and, through it, a region
+	City      string      `gorm:"size:255" json:"city,omitempty" binding:"max=255"`
+	Address   Address     `gorm:"embedded;embeddedPrefix:address_" json:"address"`
+	Latitude  *float64    `gorm:"index:hubs_coordinates_idx" json:"latitude,omitempty"`
+	Longitude *float64    `gorm:"index:hubs_coordinates_idx" json:"longitude,omitempty"`
+	TimeZone  string      `gorm:"size:64" json:"time_zone,omitempty"`           // IANA time zone of the opening hours, UTC when empty
+	Capacity  *int        `json:"capacity,omitempty" binding:"omitempty,min=0"` // Seats, unlimited when null
+	Version   uint        `gorm:"not null;default:1" json:"-"`                  // Incremented by every update, sent as the ETag
+	Country   *Country    `gorm:"foreignKey:CountryID;constraint:OnDelete:RESTRICT" json:"country,omitempty"`
+	Teams     *[]Team     `gorm:"foreignKey:HubID" json:"teams,omitempty"`
+	Resources *[]Resource `gorm:"foreignKey:HubID" json:"resources,omitempty"` // Only loaded when requested
+}
+
+// Address is the street address of a hub, the city and country are kept on the hub itself
+type Address struct {
+	Street     string `gorm:"size:255" json:"street,omitempty" binding:"max=255"`
+	Street2    string `gorm:"size:255" json:"street2,omitempty" binding:"max=255"`
+	PostalCode string `gorm:"size:32" json:"postal_code,omitempty" binding:"max=32"`
+	State      string `gorm:"size:255" json:"state,omitempty" binding:"max=255"`
 }
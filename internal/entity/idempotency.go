@@ -0,0 +1,21 @@
+package entity
+
+import "time"
+
+// IdempotencyKey records a create request sent with an Idempotency-Key header and, once handled, its response,
+// so that a retry with the same key replays the response instead of creating again
+type IdempotencyKey struct {
+	Scope       string    `gorm:"size:255;primaryKey" json:"scope"` // Username of the client, keys of different clients never collide
+	Key         string    `gorm:"size:255;primaryKey" json:"key"`
+	Fingerprint string    `gorm:"size:64;not null" json:"-"`        // SHA-256 of the method, URL and body of the request
+	StatusCode  int       `gorm:"not null;default:0" json:"status"` // 0 while the request is being handled
+	Header      string    `gorm:"type:text" json:"-"`               // Replayed response headers as JSON
+	Body        []byte    `json:"-"`
+	CreatedAt   time.Time `gorm:"not null" json:"created_at"`
+	ExpiresAt   time.Time `gorm:"not null;index" json:"expires_at"`
+	// End of the lease of the request being handled, a retry takes the key over once it is over, as when the
+	// server crashed before recording the response
+	LockedUntil *time.Time `json:"locked_until,omitempty"`
+	// Random token of the request holding the lease, only that request may record the response or free the key
+	LeaseToken string `gorm:"size:32;not null;default:''" json:"-"`
+}
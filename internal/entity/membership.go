@@ -0,0 +1,38 @@
+package entity
+
+import "time"
+
+// Membership roles accepted in Membership.Role
+const (
+	RoleLead     = "lead"
+	RoleMember   = "member"
+	RoleObserver = "observer"
+)
+
+// Membership links a user to a team. Every user has exactly one primary membership,
+// which mirrors User.TeamID, and any number of additional ones such as guilds.
+// A user has at most one open membership, without an end date, per team; ended ones are kept as history.
+type Membership struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	UserID    uint      `gorm:"not null;uniqueIndex:team_memberships_open_key,where:end_date IS NULL" json:"user_id" binding:"required"`
+	TeamID    uint      `gorm:"not null;uniqueIndex:team_memberships_open_key,where:end_date IS NULL;index" json:"team_id"`
+	Role      string    `gorm:"size:32;not null" json:"role"`
+	StartDate *Date     `gorm:"type:date" json:"start_date"`
+	EndDate   *Date     `gorm:"type:date" json:"end_date"`
+	Primary   bool      `gorm:"column:is_primary;not null;default:false" json:"primary"`
+	CreatedAt time.Time `json:"created_at"`
+	User      *User     `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"user,omitempty"`
+	Team      *Team     `gorm:"foreignKey:TeamID;constraint:OnDelete:CASCADE" json:"team,omitempty"`
+}
+
+func (Membership) TableName() string {
+	return "team_memberships"
+}
+
+// IsActive reports whether the membership has started and not yet ended on the given day
+func (m *Membership) IsActive(day Date) bool {
+	if m.StartDate != nil && m.StartDate.After(day.Time) {
+		return false
+	}
+	return m.EndDate == nil || !m.EndDate.Before(day.Time)
+}
@@ -0,0 +1,24 @@
+package entity
+
+import "time"
+
+// MFAEnrollment holds the TOTP secret of an admin account
+type MFAEnrollment struct {
+	ID            uint           `gorm:"primaryKey" json:"id"`
+	Username      string         `gorm:"size:255;not null;uniqueIndex" json:"username"`
+	Secret        string         `gorm:"size:64;not null" json:"-"`
+	Confirmed     bool           `gorm:"not null;default:false" json:"confirmed"`
+	LastUsedStep  int64          `gorm:"not null;default:0" json:"-"` // TOTP step of the last accepted code, older codes are refused
+	FailedCodes   int            `gorm:"not null;default:0" json:"-"` // Invalid codes since the last valid one or lockout
+	LockedUntil   *time.Time     `json:"locked_until,omitempty"`      // Codes are refused until then after too many invalid ones
+	CreatedAt     time.Time      `json:"created_at"`
+	RecoveryCodes []RecoveryCode `gorm:"foreignKey:EnrollmentID;constraint:OnDelete:CASCADE" json:"-"`
+}
+
+// RecoveryCode is a single-use fallback code, stored as a SHA-256 hash
+type RecoveryCode struct {
+	ID           uint       `gorm:"primaryKey" json:"id"`
+	EnrollmentID uint       `gorm:"not null;index" json:"enrollment_id"`
+	CodeHash     string     `gorm:"size:64;not null" json:"-"`
+	UsedAt       *time.Time `json:"used_at,omitempty"`
+}
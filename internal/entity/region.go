@@ -0,0 +1,17 @@
+package entity
+
+// Region groups countries, e.g. EMEA or APAC
+type Region struct {
+	ID        uint      `gorm:"primaryKey" json:"id,omitempty"`
+	Name      string    `gorm:"size:255;not null" json:"name" binding:"required,min=2,max=255"` // Unique regardless of case
+	Countries []Country `gorm:"foreignKey:RegionID" json:"countries,omitempty"`
+}
+
+// Country belongs to a region and is identified by its ISO 3166-1 alpha-2 code
+type Country struct {
+	ID       uint    `gorm:"primaryKey" json:"id,omitempty"`
+	Code     string  `gorm:"size:2;not null;uniqueIndex" json:"code" binding:"required,len=2"`
+	Name     string  `gorm:"size:255;not null" json:"name" binding:"required,min=2,max=255"`
+	RegionID uint    `gorm:"not null;index" json:"region_id" binding:"required"`
+	Region   *Region `gorm:"foreignKey:RegionID;constraint:OnDelete:RESTRICT" json:"region,omitempty"`
+}
@@ -0,0 +1,51 @@
+package entity
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Types of resources a hub can hold
+const (
+	ResourceDesk = "desk"
+	ResourceRoom = "room"
+)
+
+// Resource is a desk or meeting room in a hub
+type Resource struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	HubID     uint      `gorm:"not null" json:"hub_id"`
+	Type      string    `gorm:"size:16;not null;index" json:"type" binding:"required,oneof=desk room"`
+	Name      string    `gorm:"size:255;not null" json:"name" binding:"required,max=255"` // Unique per hub regardless of case
+	Floor     string    `gorm:"size:32" json:"floor,omitempty" binding:"max=32"`
+	Capacity  *int      `gorm:"not null;default:1" json:"capacity" binding:"omitempty,min=0"` // Seats, 1 when omitted
+	Amenities []Amenity `gorm:"foreignKey:ResourceID;constraint:OnDelete:CASCADE" json:"amenities"`
+	Hub       *Hub      `gorm:"foreignKey:HubID;constraint:OnDelete:CASCADE" json:"hub,omitempty"`
+}
+
+func (Resource) TableName() string {
+	return "hub_resources"
+}
+
+// Amenity is a feature of a resource such as a monitor or a video screen, encoded as its name in JSON
+type Amenity struct {
+	ResourceID uint   `gorm:"primaryKey"`
+	Name       string `gorm:"primaryKey;size:64;index"`
+}
+
+func (Amenity) TableName() string {
+	return "hub_resource_amenities"
+}
+
+func (a Amenity) MarshalJSON() ([]byte, error) {
+	return json.Marshal(a.Name)
+}
+
+func (a *Amenity) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err != nil {
+		return fmt.Errorf("amenity must be a string")
+	}
+	a.Name = name
+	return nil
+}
@@ -0,0 +1,31 @@
+package entity
+
+import "time"
+
+// OpeningHours is one opening interval of a hub on a day of the week, in the hub's time zone.
+// A day may have several intervals, e.g. around a lunch break.
+type OpeningHours struct {
+	ID      uint         `gorm:"primaryKey" json:"-"`
+	HubID   uint         `gorm:"not null;index" json:"-"`
+	Weekday time.Weekday `gorm:"not null" json:"weekday"`                          // 0 is Sunday
+	Opens   string       `gorm:"size:5;not null" json:"opens" binding:"required"`  // HH:MM
+	Closes  string       `gorm:"size:5;not null" json:"closes" binding:"required"` // HH:MM, 24:00 for midnight
+	Hub     *Hub         `gorm:"foreignKey:HubID;constraint:OnDelete:CASCADE" json:"-"`
+}
+
+func (OpeningHours) TableName() string {
+	return "hub_opening_hours"
+}
+
+// Holiday is a day on which a hub is closed regardless of its opening hours
+type Holiday struct {
+	ID    uint   `gorm:"primaryKey" json:"id"`
+	HubID uint   `gorm:"not null;uniqueIndex:hub_holidays_hub_date_key" json:"hub_id"`
+	Date  Date   `gorm:"type:date;not null;uniqueIndex:hub_holidays_hub_date_key" json:"date"`
+	Name  string `gorm:"size:255;not null" json:"name" binding:"required,max=255"`
+	Hub   *Hub   `gorm:"foreignKey:HubID;constraint:OnDelete:CASCADE" json:"-"`
+}
+
+func (Holiday) TableName() string {
+	return "hub_holidays"
+}
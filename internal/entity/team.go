@@ -1,8 +1,11 @@
 package entity
 
 type Team struct {
-	ID    uint   `gorm:"primaryKey" json:"id,omitempty"`
-	Name  string `gorm:"size:255;not null" json:"name" binding:"required,min=3,max=255"`
-	HubID uint   `gorm:"not null" json:"hub_id" binding:"required"`
-	Hub   *Hub   `gorm:"foreignKey:HubID;constraint:OnDelete:CASCADE" json:"hub,omitempty"`
+	ID       uint    `gorm:"primaryKey" json:"id,omitempty"`
+	Name     string  `gorm:"size:255;not null" json:"name" binding:"required,min=3,max=255"`
+	HubID    uint    `gorm:"not null" json:"hub_id" binding:"required"`
+	ParentID *uint   `gorm:"index" json:"parent_id,omitempty"` // Optional parent team in the same hub
+	Version  uint    `gorm:"not null;default:1" json:"-"`      // Incremented by every update, sent as the ETag
+	Hub      *Hub    `gorm:"foreignKey:HubID;constraint:OnDelete:CASCADE" json:"hub,omitempty"`
+	Users    *[]User `gorm:"foreignKey:TeamID" json:"users,omitempty"` // Users whose primary team it is, only loaded when requested
 }
@@ -1,9 +1,32 @@
 package entity
 
+import "time"
+
+// Employment types accepted in User.EmploymentType
+const (
+	EmploymentFullTime   = "full_time"
+	EmploymentPartTime   = "part_time"
+	EmploymentContractor = "contractor"
+	EmploymentIntern     = "intern"
+	EmploymentTemporary  = "temporary"
+)
+
 type User struct {
-	ID     uint   `gorm:"primaryKey" json:"id"`
-	Name   string `gorm:"size:255;not null" json:"name" binding:"required"`
-	TeamID uint   `gorm:"not null" json:"team_id" binding:"required"`
-	Email  string `gorm:"not null" json:"email" binding:"required"`
-	Team   *Team  `gorm:"foreignKey:TeamID;constraint:OnDelete:CASCADE" json:"team,omitempty"`
+	ID             uint       `gorm:"primaryKey" json:"id"`
+	Name           string     `gorm:"size:255;not null" json:"name" binding:"required"`
+	TeamID         uint       `gorm:"not null" json:"team_id" binding:"required"`
+	Email          string     `gorm:"not null" json:"email" binding:"required"`
+	JobTitle       string     `gorm:"size:255" json:"job_title"`
+	Phone          string     `gorm:"size:32" json:"phone"`
+	EmployeeNumber *string    `gorm:"size:64;uniqueIndex" json:"employee_number"`
+	StartDate      *Date      `gorm:"type:date" json:"start_date"`
+	AvatarURL      string     `gorm:"size:2048" json:"avatar_url"`
+	Locale         string     `gorm:"size:35" json:"locale"`
+	TimeZone       string     `gorm:"size:64" json:"time_zone"`
+	EmploymentType string     `gorm:"size:32" json:"employment_type"`
+	ManagerID      *uint      `gorm:"index" json:"manager_id"`
+	OIDCSubject    *string    `gorm:"column:oidc_subject;size:255;uniqueIndex" json:"-"`
+	DeactivatedAt  *time.Time `json:"deactivated_at,omitempty"`
+	Version        uint       `gorm:"not null;default:1" json:"-"` // Incremented by every update, sent as the ETag
+	Team           *Team      `gorm:"foreignKey:TeamID;constraint:OnDelete:CASCADE" json:"team,omitempty"`
 }
@@ -0,0 +1,171 @@
+package handler
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"hub_management_service/internal/entity"
+	"hub_management_service/internal/middleware"
+	"hub_management_service/internal/service"
+	"net/http"
+	"strconv"
+)
+
+type AttendanceHandler struct {
+	service service.AttendanceService
+}
+
+func NewAttendanceHandler(service service.AttendanceService) *AttendanceHandler {
+	return &AttendanceHandler{service: service}
+}
+
+// CheckIn - Handler for the authenticated user checking in at a hub
+func (h *AttendanceHandler) CheckIn(c *gin.Context) {
+	hubID, ok := parseHubID(c)
+	if !ok {
+		return
+	}
+
+	visit, err := h.service.CheckIn(c.GetString(middleware.UsernameKey), hubID)
+	if err != nil {
+		writeAttendanceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"message": "Checked in successfully", "visit": visit})
+}
+
+// CheckOut - Handler for the authenticated user checking out of a hub
+func (h *AttendanceHandler) CheckOut(c *gin.Context) {
+	hubID, ok := parseHubID(c)
+	if !ok {
+		return
+	}
+
+	visit, err := h.service.CheckOut(c.GetString(middleware.UsernameKey), hubID)
+	if err != nil {
+		writeAttendanceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Checked out successfully", "visit": visit})
+}
+
+// FindPresence - Handler for who checked in at a hub on ?date=, today by default
+func (h *AttendanceHandler) FindPresence(c *gin.Context) {
+	hubID, ok := parseHubID(c)
+	if !ok {
+		return
+	}
+	date, ok := parseDateQuery(c, "date")
+	if !ok {
+		return
+	}
+
+	presence, err := h.service.FindPresence(hubID, date)
+	if err != nil {
+		writeAttendanceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, presence)
+}
+
+// FindHubAttendance - Handler for the daily attendance of a hub between ?from= and ?to=, of one team with ?team_id=
+func (h *AttendanceHandler) FindHubAttendance(c *gin.Context) {
+	hubID, ok := parseHubID(c)
+	if !ok {
+		return
+	}
+	query, ok := parseAttendanceQuery(c)
+	if !ok {
+		return
+	}
+	query.HubID = hubID
+	if value, ok := c.GetQuery("team_id"); ok {
+		teamID, err := strconv.Atoi(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid Team ID"})
+			return
+		}
+		query.TeamID = uint(teamID)
+	}
+
+	h.findAttendance(c, query)
+}
+
+// FindTeamAttendance - Handler for the daily attendance of a team between ?from= and ?to=, at one hub with ?hub_id=
+func (h *AttendanceHandler) FindTeamAttendance(c *gin.Context) {
+	teamID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid Team ID"})
+		return
+	}
+	query, ok := parseAttendanceQuery(c)
+	if !ok {
+		return
+	}
+	query.TeamID = uint(teamID)
+	if value, ok := c.GetQuery("hub_id"); ok {
+		hubID, err := strconv.Atoi(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid Hub ID"})
+			return
+		}
+		query.HubID = uint(hubID)
+	}
+
+	h.findAttendance(c, query)
+}
+
+func (h *AttendanceHandler) findAttendance(c *gin.Context, query service.AttendanceQuery) {
+	attendance, err := h.service.FindDailyAttendance(query)
+	if err != nil {
+		writeAttendanceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"attendance": attendance})
+}
+
+func parseAttendanceQuery(c *gin.Context) (service.AttendanceQuery, bool) {
+	var query service.AttendanceQuery
+	var ok bool
+	if query.From, ok = parseDateQuery(c, "from"); !ok {
+		return query, false
+	}
+	if query.To, ok = parseDateQuery(c, "to"); !ok {
+		return query, false
+	}
+	return query, true
+}
+
+// parseDateQuery parses an optional YYYY-MM-DD query parameter, the zero date when it is absent
+func parseDateQuery(c *gin.Context, name string) (entity.Date, bool) {
+	value, ok := c.GetQuery(name)
+	if !ok {
+		return entity.Date{}, true
+	}
+	date, err := entity.ParseDate(value)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": name + ": " + err.Error()})
+		return entity.Date{}, false
+	}
+	return date, true
+}
+
+func writeAttendanceError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrHubNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"message": "Hub not found"})
+	case errors.Is(err, service.ErrTeamNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"message": "Team not found"})
+	case errors.Is(err, service.ErrNotAUser), errors.Is(err, service.ErrUserDeactivated):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrInvalidDateRange):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrAlreadyCheckedIn), errors.Is(err, service.ErrNotCheckedIn):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
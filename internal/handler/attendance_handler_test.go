@@ -0,0 +1,82 @@
+package handler
+
+import (
+	"hub_management_service/internal/entity"
+	"hub_management_service/internal/middleware"
+	"hub_management_service/internal/service"
+	"hub_management_service/internal/service/mocks"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// newAttendanceRouter authenticates every request as the given username
+func newAttendanceRouter(mockService *mocks.AttendanceService, username string) *gin.Engine {
+	handler := NewAttendanceHandler(mockService)
+	router := gin.Default()
+	router.Use(func(c *gin.Context) { c.Set(middleware.UsernameKey, username) })
+	router.POST("/hubs/:id/check-in", handler.CheckIn)
+	router.GET("/hubs/:id/presence", handler.FindPresence)
+	router.GET("/teams/:id/attendance", handler.FindTeamAttendance)
+	return router
+}
+
+// TestCheckInHandler tests that the authenticated user is checked in, and that accounts without a user are forbidden
+func TestCheckInHandler(t *testing.T) {
+	mockService := new(mocks.AttendanceService)
+	mockService.On("CheckIn", "jane@example.com", uint(1)).Return(&entity.Visit{ID: 3, UserID: 7, HubID: 1}, nil)
+	mockService.On("CheckIn", "admin", uint(1)).Return(nil, service.ErrNotAUser)
+
+	req, _ := http.NewRequest("POST", "/hubs/1/check-in", nil)
+	resp := httptest.NewRecorder()
+	newAttendanceRouter(mockService, "jane@example.com").ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusCreated, resp.Code)
+	assert.Contains(t, resp.Body.String(), `"user_id":7`)
+
+	resp = httptest.NewRecorder()
+	newAttendanceRouter(mockService, "admin").ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusForbidden, resp.Code)
+}
+
+// TestFindPresenceHandler tests the parsing of ?date=
+func TestFindPresenceHandler(t *testing.T) {
+	mockService := new(mocks.AttendanceService)
+	router := newAttendanceRouter(mockService, "jane@example.com")
+	date := entity.NewDate(2030, time.July, 15)
+	mockService.On("FindPresence", uint(1), date).Return(&service.HubPresence{HubID: 1, Date: date, Users: []service.Presence{}}, nil)
+
+	req, _ := http.NewRequest("GET", "/hubs/1/presence?date=2030-07-15", nil)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	req, _ = http.NewRequest("GET", "/hubs/1/presence?date=15/07/2030", nil)
+	resp = httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+}
+
+// TestFindTeamAttendanceHandler tests the query filters and that invalid ranges return 400
+func TestFindTeamAttendanceHandler(t *testing.T) {
+	mockService := new(mocks.AttendanceService)
+	router := newAttendanceRouter(mockService, "")
+	mockService.On("FindDailyAttendance", service.AttendanceQuery{TeamID: 2, HubID: 1, From: entity.NewDate(2030, time.July, 1)}).
+		Return([]entity.DailyAttendance{{HubID: 1, TeamID: 2, Users: 4}}, nil)
+	mockService.On("FindDailyAttendance", mock.Anything).Return(nil, service.ErrInvalidDateRange)
+
+	req, _ := http.NewRequest("GET", "/teams/2/attendance?hub_id=1&from=2030-07-01", nil)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Contains(t, resp.Body.String(), `"users":4`)
+
+	req, _ = http.NewRequest("GET", "/teams/2/attendance?from=2030-07-02&to=2030-07-01", nil)
+	resp = httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+}
@@ -1,8 +1,10 @@
 package handler
 
 import (
+	"errors"
 	"github.com/gin-gonic/gin"
 	"hub_management_service/internal/middleware"
+	"hub_management_service/internal/service"
 	"net/http"
 )
 
@@ -12,8 +14,28 @@ type LoginRequest struct {
 	Password string `json:"password"`
 }
 
-// LoginHandler handles login requests and issues a JWT token if credentials are valid
-func LoginHandler(c *gin.Context) {
+// MFALoginRequest represents the second step of a login for accounts with MFA enabled
+type MFALoginRequest struct {
+	MFAToken string `json:"mfa_token" binding:"required"`
+	Code     string `json:"code" binding:"required"`
+}
+
+// MFACodeRequest carries a TOTP or recovery code for enrollment management
+type MFACodeRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+type AuthHandler struct {
+	mfaService service.MFAService
+}
+
+func NewAuthHandler(mfaService service.MFAService) *AuthHandler {
+	return &AuthHandler{mfaService: mfaService}
+}
+
+// Login handles login requests and issues a JWT token if credentials are valid.
+// Accounts with MFA enabled receive a short-lived challenge token instead.
+func (h *AuthHandler) Login(c *gin.Context) {
 	var req LoginRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
@@ -21,18 +43,120 @@ func LoginHandler(c *gin.Context) {
 	}
 
 	// Hardcoded username and password validation
-	if req.Username == "admin" && req.Password == "password" {
-		// Generate JWT token
-		token, err := middleware.GenerateJWT(req.Username)
+	if req.Username != "admin" || req.Password != "password" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid credentials"})
+		return
+	}
+
+	enabled, err := h.mfaService.IsEnabled(req.Username)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if enabled {
+		challenge, err := middleware.GenerateMFAChallenge(req.Username)
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate token"})
 			return
 		}
+		c.JSON(http.StatusOK, gin.H{"mfa_required": true, "mfa_token": challenge})
+		return
+	}
+
+	// Generate JWT token
+	token, err := middleware.GenerateJWT(req.Username)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate token"})
+		return
+	}
+
+	// Return the token
+	c.JSON(http.StatusOK, gin.H{"token": token})
+}
+
+// VerifyMFA exchanges an MFA challenge token and a valid code for a full JWT token
+func (h *AuthHandler) VerifyMFA(c *gin.Context) {
+	var req MFALoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 
-		// Return the token
-		c.JSON(http.StatusOK, gin.H{"token": token})
+	username, err := middleware.ParseMFAChallenge(req.MFAToken)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired MFA token"})
 		return
 	}
 
-	c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid credentials"})
+	if err := h.mfaService.Verify(username, req.Code); err != nil {
+		writeMFAError(c, err)
+		return
+	}
+
+	token, err := middleware.GenerateJWT(username)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate token"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"token": token})
+}
+
+// EnrollMFA starts TOTP enrollment for the authenticated account
+func (h *AuthHandler) EnrollMFA(c *gin.Context) {
+	result, err := h.mfaService.Enroll(c.GetString(middleware.UsernameKey))
+	if err != nil {
+		writeMFAError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Scan the provisioning URI and confirm with a code", "mfa": result})
+}
+
+// ConfirmMFA activates a pending enrollment with a code from the authenticator app
+func (h *AuthHandler) ConfirmMFA(c *gin.Context) {
+	var req MFACodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.mfaService.Confirm(c.GetString(middleware.UsernameKey), req.Code); err != nil {
+		writeMFAError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "MFA enabled successfully"})
+}
+
+// DisableMFA removes the enrollment of the authenticated account
+func (h *AuthHandler) DisableMFA(c *gin.Context) {
+	var req MFACodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.mfaService.Disable(c.GetString(middleware.UsernameKey), req.Code); err != nil {
+		writeMFAError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "MFA disabled successfully"})
+}
+
+func writeMFAError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrInvalidMFACode):
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrMFANotEnrolled):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrMFAEnabled):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrMFALocked):
+		c.JSON(http.StatusTooManyRequests, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
 }
@@ -3,6 +3,9 @@ package handler
 import (
 	"bytes"
 	"encoding/json"
+	"hub_management_service/internal/middleware"
+	"hub_management_service/internal/service"
+	"hub_management_service/internal/service/mocks"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -12,11 +15,14 @@ import (
 )
 
 func TestLoginHandler_Success(t *testing.T) {
+	mockService := new(mocks.MFAService)
+	handler := NewAuthHandler(mockService)
+
 	router := gin.Default()
+	router.POST("/login", handler.Login)
 
-	router.POST("/login", func(c *gin.Context) {
-		LoginHandler(c)
-	})
+	// MFA is not enabled for the account
+	mockService.On("IsEnabled", "admin").Return(false, nil)
 
 	loginReq := map[string]string{"username": "admin", "password": "password"}
 	reqBody, _ := json.Marshal(loginReq)
@@ -30,15 +36,15 @@ func TestLoginHandler_Success(t *testing.T) {
 	var response map[string]string
 	json.Unmarshal(resp.Body.Bytes(), &response)
 	assert.NotEmpty(t, response["token"])
-
+	mockService.AssertExpectations(t)
 }
 
 func TestLoginHandler_InvalidCredentials(t *testing.T) {
-	router := gin.Default()
+	mockService := new(mocks.MFAService)
+	handler := NewAuthHandler(mockService)
 
-	router.POST("/login", func(c *gin.Context) {
-		LoginHandler(c)
-	})
+	router := gin.Default()
+	router.POST("/login", handler.Login)
 
 	loginReq := map[string]string{"username": "wronguser", "password": "wrongpassword"}
 	reqBody, _ := json.Marshal(loginReq)
@@ -53,3 +59,95 @@ func TestLoginHandler_InvalidCredentials(t *testing.T) {
 	json.Unmarshal(resp.Body.Bytes(), &response)
 	assert.Equal(t, "Invalid credentials", response["error"])
 }
+
+// TestLoginHandler_MFARequired tests that an MFA-enabled account receives a challenge token instead of a JWT
+func TestLoginHandler_MFARequired(t *testing.T) {
+	mockService := new(mocks.MFAService)
+	handler := NewAuthHandler(mockService)
+
+	router := gin.Default()
+	router.POST("/login", handler.Login)
+	router.GET("/protected", middleware.AuthMiddleware(), func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	mockService.On("IsEnabled", "admin").Return(true, nil)
+
+	reqBody, _ := json.Marshal(map[string]string{"username": "admin", "password": "password"})
+	req, _ := http.NewRequest("POST", "/login", bytes.NewReader(reqBody))
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	var response map[string]interface{}
+	json.Unmarshal(resp.Body.Bytes(), &response)
+	assert.Equal(t, true, response["mfa_required"])
+	assert.Nil(t, response["token"])
+
+	// The challenge token must not grant access to protected routes
+	req, _ = http.NewRequest("GET", "/protected", nil)
+	req.Header.Set("Authorization", "Bearer "+response["mfa_token"].(string))
+	resp = httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusUnauthorized, resp.Code)
+	mockService.AssertExpectations(t)
+}
+
+// TestVerifyMFA tests exchanging a challenge token and a valid code for a JWT
+func TestVerifyMFA(t *testing.T) {
+	mockService := new(mocks.MFAService)
+	handler := NewAuthHandler(mockService)
+
+	router := gin.Default()
+	router.POST("/login/mfa", handler.VerifyMFA)
+
+	mockService.On("Verify", "admin", "123456").Return(nil)
+
+	challenge, _ := middleware.GenerateMFAChallenge("admin")
+	reqBody, _ := json.Marshal(map[string]string{"mfa_token": challenge, "code": "123456"})
+	req, _ := http.NewRequest("POST", "/login/mfa", bytes.NewReader(reqBody))
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	var response map[string]string
+	json.Unmarshal(resp.Body.Bytes(), &response)
+	assert.NotEmpty(t, response["token"])
+	mockService.AssertExpectations(t)
+}
+
+// TestVerifyMFA_InvalidCode tests that a wrong code is rejected with 401
+func TestVerifyMFA_InvalidCode(t *testing.T) {
+	mockService := new(mocks.MFAService)
+	handler := NewAuthHandler(mockService)
+
+	router := gin.Default()
+	router.POST("/login/mfa", handler.VerifyMFA)
+
+	mockService.On("Verify", "admin", "000000").Return(service.ErrInvalidMFACode)
+
+	challenge, _ := middleware.GenerateMFAChallenge("admin")
+	reqBody, _ := json.Marshal(map[string]string{"mfa_token": challenge, "code": "000000"})
+	req, _ := http.NewRequest("POST", "/login/mfa", bytes.NewReader(reqBody))
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusUnauthorized, resp.Code)
+	mockService.AssertExpectations(t)
+}
+
+// TestVerifyMFA_FullTokenRejected tests that a regular JWT cannot be used as a challenge token
+func TestVerifyMFA_FullTokenRejected(t *testing.T) {
+	mockService := new(mocks.MFAService)
+	handler := NewAuthHandler(mockService)
+
+	router := gin.Default()
+	router.POST("/login/mfa", handler.VerifyMFA)
+
+	token, _ := middleware.GenerateJWT("admin")
+	reqBody, _ := json.Marshal(map[string]string{"mfa_token": token, "code": "123456"})
+	req, _ := http.NewRequest("POST", "/login/mfa", bytes.NewReader(reqBody))
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusUnauthorized, resp.Code)
+	mockService.AssertNotCalled(t, "Verify", "admin", "123456")
+}
@@ -0,0 +1,117 @@
+package handler
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"hub_management_service/internal/service"
+	"net/http"
+)
+
+// maxBatchBytes bounds the size of a batch request
+const maxBatchBytes = 4 << 20
+
+type BatchHandler struct {
+	service service.BatchService
+}
+
+func NewBatchHandler(service service.BatchService) *BatchHandler {
+	return &BatchHandler{service: service}
+}
+
+// batchResult is the result of an operation along with the status its endpoint would have answered
+type batchResult struct {
+	Status int `json:"status"`
+	service.BatchResult
+}
+
+// Batch - Handler for running an ordered list of creates, updates and deletes of hubs, teams and users in one
+// transaction, all or nothing unless the mode is best_effort
+func (h *BatchHandler) Batch(c *gin.Context) {
+	opts, ok := parseWriteOptions(c)
+	if !ok {
+		return
+	}
+	var req service.BatchRequest
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxBatchBytes)
+	if err := c.ShouldBindJSON(&req); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "the batch must not exceed 4 MiB"})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	report, err := h.service.Execute(&req, opts)
+	if errors.Is(err, service.ErrInvalidBatch) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	results := make([]batchResult, len(report.Results))
+	for i, result := range report.Results {
+		results[i] = batchResult{Status: batchStatus(result), BatchResult: result}
+	}
+	status := http.StatusOK
+	switch {
+	case !report.Committed:
+		status = http.StatusUnprocessableEntity
+	case report.Failed > 0:
+		status = http.StatusMultiStatus
+	}
+	c.JSON(status, gin.H{
+		"mode":      report.Mode,
+		"committed": report.Committed,
+		"succeeded": report.Succeeded,
+		"failed":    report.Failed,
+		"results":   results,
+	})
+}
+
+// batchStatus is the status the endpoint of an operation answers with its outcome, or 424 Failed Dependency for an
+// operation that was skipped or referred to a failed one
+func batchStatus(result service.BatchResult) int {
+	switch result.Outcome {
+	case service.BatchSkipped:
+		return http.StatusFailedDependency
+	case service.BatchSucceeded:
+		switch result.Op {
+		case service.BatchCreate:
+			return http.StatusCreated
+		case service.BatchDelete:
+			return http.StatusNoContent
+		default:
+			return http.StatusOK
+		}
+	}
+
+	err := result.Err
+	var conflictErr *service.EmailConflictError
+	var fullErr *service.HubFullError
+	switch {
+	case errors.Is(err, service.ErrBatchDependency):
+		return http.StatusFailedDependency
+	case errors.Is(err, service.ErrHubNotFound) && result.Type == service.BatchHubs,
+		errors.Is(err, service.ErrTeamNotFound) && result.Type == service.BatchTeams,
+		errors.Is(err, service.ErrUserNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, service.ErrVersionMismatch):
+		return http.StatusPreconditionFailed
+	case errors.Is(err, service.ErrInvalidBatchOperation), errors.Is(err, service.ErrHubNotFound), errors.Is(err, service.ErrTeamNotFound),
+		errors.Is(err, service.ErrCountryNotFound), errors.Is(err, service.ErrInvalidCoordinates), errors.Is(err, service.ErrInvalidTimeZone),
+		errors.Is(err, service.ErrParentTeamNotFound), errors.Is(err, service.ErrParentHubMismatch), errors.Is(err, service.ErrInvalidEmail),
+		errors.Is(err, service.ErrInvalidProfile), errors.Is(err, service.ErrManagerNotFound):
+		return http.StatusBadRequest
+	case errors.Is(err, service.ErrHubNotEmpty), errors.Is(err, service.ErrTeamNotEmpty), errors.Is(err, service.ErrTeamCycle),
+		errors.Is(err, service.ErrEmployeeNumberTaken), errors.Is(err, service.ErrManagerCycle),
+		errors.As(err, &conflictErr), errors.As(err, &fullErr):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
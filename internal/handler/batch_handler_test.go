@@ -0,0 +1,81 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"hub_management_service/internal/service"
+	"hub_management_service/internal/service/mocks"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newBatchRouter(mockService *mocks.BatchService) *gin.Engine {
+	handler := NewBatchHandler(mockService)
+	router := gin.Default()
+	router.POST("/batch", handler.Batch)
+	return router
+}
+
+func postBatch(router *gin.Engine, query, body string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest("POST", "/batch"+query, bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	return resp
+}
+
+// TestBatchHandler tests the status of a batch and of each of its operations
+func TestBatchHandler(t *testing.T) {
+	mockService := new(mocks.BatchService)
+	router := newBatchRouter(mockService)
+	mockService.On("Execute", mock.MatchedBy(func(req *service.BatchRequest) bool { return req.Mode == service.BatchBestEffort }), service.WriteOptions{OverrideCapacity: true}).
+		Return(&service.BatchReport{Mode: service.BatchBestEffort, Committed: true, Succeeded: 2, Failed: 2, Results: []service.BatchResult{
+			{Index: 0, Op: service.BatchCreate, Type: service.BatchHubs, Outcome: service.BatchSucceeded, ID: 1},
+			{Index: 1, Op: service.BatchDelete, Type: service.BatchTeams, Outcome: service.BatchSucceeded, ID: 2},
+			{Index: 2, Op: service.BatchUpdate, Type: service.BatchTeams, Outcome: service.BatchFailed, Err: service.ErrTeamNotFound},
+			{Index: 3, Op: service.BatchCreate, Type: service.BatchUsers, Outcome: service.BatchFailed, Err: service.ErrTeamNotFound},
+		}}, nil)
+	mockService.On("Execute", mock.Anything, service.WriteOptions{}).
+		Return(&service.BatchReport{Mode: service.BatchAtomic, Failed: 1, Results: []service.BatchResult{
+			{Index: 0, Op: service.BatchUpdate, Type: service.BatchHubs, Outcome: service.BatchFailed, Err: fmt.Errorf("%w: hub is at version 2, not 1", service.ErrVersionMismatch)},
+			{Index: 1, Op: service.BatchDelete, Type: service.BatchUsers, Outcome: service.BatchSkipped},
+		}}, nil)
+
+	resp := postBatch(router, "?override_capacity=true", `{"mode": "best_effort", "operations": []}`)
+	assert.Equal(t, http.StatusMultiStatus, resp.Code)
+	var body struct {
+		Committed bool
+		Results   []struct{ Status int }
+	}
+	assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), &body))
+	assert.True(t, body.Committed)
+	if assert.Len(t, body.Results, 4) {
+		assert.Equal(t, []int{http.StatusCreated, http.StatusNoContent, http.StatusNotFound, http.StatusBadRequest},
+			[]int{body.Results[0].Status, body.Results[1].Status, body.Results[2].Status, body.Results[3].Status})
+	}
+
+	resp = postBatch(router, "", `{"operations": []}`)
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.Code)
+	assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), &body))
+	if assert.Len(t, body.Results, 2) {
+		assert.Equal(t, []int{http.StatusPreconditionFailed, http.StatusFailedDependency}, []int{body.Results[0].Status, body.Results[1].Status})
+	}
+}
+
+// TestBatchHandler_Invalid tests that malformed batches return 400 without running
+func TestBatchHandler_Invalid(t *testing.T) {
+	mockService := new(mocks.BatchService)
+	router := newBatchRouter(mockService)
+	mockService.On("Execute", mock.Anything, mock.Anything).Return(nil, fmt.Errorf("%w: no operations", service.ErrInvalidBatch))
+
+	assert.Equal(t, http.StatusBadRequest, postBatch(router, "", `{"operations": []}`).Code)
+	assert.Equal(t, http.StatusBadRequest, postBatch(router, "", `[`).Code)
+	assert.Equal(t, http.StatusBadRequest, postBatch(router, "?override_capacity=maybe", `{}`).Code)
+	mockService.AssertNumberOfCalls(t, "Execute", 1)
+}
@@ -0,0 +1,193 @@
+package handler
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"hub_management_service/internal/entity"
+	"hub_management_service/internal/middleware"
+	"hub_management_service/internal/service"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// defaultBookingWindow is how far ahead bookings are listed when no end is given
+const defaultBookingWindow = 7 * 24 * time.Hour
+
+type BookingHandler struct {
+	service service.BookingService
+	expand  service.ExpandService
+}
+
+func NewBookingHandler(service service.BookingService, expand service.ExpandService) *BookingHandler {
+	return &BookingHandler{service: service, expand: expand}
+}
+
+// CreateBooking - Handler for booking a resource for a time range
+func (h *BookingHandler) CreateBooking(c *gin.Context) {
+	resourceID, ok := parseResourceID(c)
+	if !ok {
+		return
+	}
+	var booking entity.Booking
+	if err := c.ShouldBindJSON(&booking); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.service.CreateBooking(bookingAccount(c), resourceID, &booking); err != nil {
+		writeBookingError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"message": "Booking created successfully", "booking": booking})
+}
+
+// ListBookings - Handler for listing the active bookings of a resource between ?from= and ?to=, the coming week by default
+func (h *BookingHandler) ListBookings(c *gin.Context) {
+	resourceID, ok := parseResourceID(c)
+	if !ok {
+		return
+	}
+	from := time.Now()
+	if value, ok := c.GetQuery("from"); ok {
+		var err error
+		if from, err = time.Parse(time.RFC3339, value); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "from must be an RFC 3339 timestamp, e.g. 2024-07-15T09:00:00Z"})
+			return
+		}
+	}
+	to := from.Add(defaultBookingWindow)
+	if value, ok := c.GetQuery("to"); ok {
+		var err error
+		if to, err = time.Parse(time.RFC3339, value); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "to must be an RFC 3339 timestamp, e.g. 2024-07-15T18:00:00Z"})
+			return
+		}
+	}
+	opts, ok := parseReadOptions(c, service.BookingRecord, entity.Booking{}, "")
+	if !ok {
+		return
+	}
+
+	bookings, err := h.service.ListBookings(resourceID, from, to)
+	if err != nil {
+		writeBookingError(c, err)
+		return
+	}
+	if !opts.expandBookings(c, h.expand, bookingRefs(bookings)...) {
+		return
+	}
+	records, ok := opts.sparse(c, bookings)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"bookings": records})
+}
+
+// FindBooking - Handler for retrieving a booking by its ID, with the fields and relations of ?fields= and ?expand=
+func (h *BookingHandler) FindBooking(c *gin.Context) {
+	id, ok := parseBookingID(c)
+	if !ok {
+		return
+	}
+	opts, ok := parseReadOptions(c, service.BookingRecord, entity.Booking{}, "")
+	if !ok {
+		return
+	}
+
+	booking, err := h.service.FindBooking(id)
+	if err != nil {
+		writeBookingError(c, err)
+		return
+	}
+	if !opts.expandBookings(c, h.expand, booking) {
+		return
+	}
+	record, ok := opts.sparse(c, booking)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"booking": record})
+}
+
+// CancelBooking - Handler for cancelling a booking, the booking is kept with its cancellation time
+func (h *BookingHandler) CancelBooking(c *gin.Context) {
+	id, ok := parseBookingID(c)
+	if !ok {
+		return
+	}
+
+	booking, err := h.service.CancelBooking(bookingAccount(c), id)
+	if err != nil {
+		writeBookingError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Booking cancelled successfully", "booking": booking})
+}
+
+// FindAvailability - Handler for the bookings and free ranges of the resources of a hub on ?date=, today by default
+func (h *BookingHandler) FindAvailability(c *gin.Context) {
+	hubID, ok := parseHubID(c)
+	if !ok {
+		return
+	}
+	var date entity.Date
+	if value, ok := c.GetQuery("date"); ok {
+		var err error
+		if date, err = entity.ParseDate(value); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	query, ok := parseResourceQuery(c)
+	if !ok {
+		return
+	}
+
+	availability, err := h.service.FindAvailability(hubID, date, query)
+	if err != nil {
+		writeBookingError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, availability)
+}
+
+// bookingAccount returns the account authenticated by the middleware
+func bookingAccount(c *gin.Context) service.Account {
+	return service.Account{Username: c.GetString(middleware.UsernameKey), Admin: c.GetString(middleware.RoleKey) == middleware.RoleAdmin}
+}
+
+func parseBookingID(c *gin.Context) (uint, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid Booking ID"})
+		return 0, false
+	}
+	return uint(id), true
+}
+
+func writeBookingError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrHubNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"message": "Hub not found"})
+	case errors.Is(err, service.ErrResourceNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"message": "Resource not found"})
+	case errors.Is(err, service.ErrBookingNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"message": "Booking not found"})
+	case errors.Is(err, service.ErrBookingUserNotFound), errors.Is(err, service.ErrInvalidBookingTime), errors.Is(err, service.ErrBookingInPast),
+		errors.Is(err, service.ErrOutsideOpeningHours), errors.Is(err, service.ErrInvalidTimeRange),
+		errors.Is(err, service.ErrInvalidResourceType), errors.Is(err, service.ErrInvalidAmenity):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrNotAUser), errors.Is(err, service.ErrUserDeactivated), errors.Is(err, service.ErrBookingForbidden):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrBookingConflict), errors.Is(err, service.ErrBookingCancelled):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
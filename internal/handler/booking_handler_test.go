@@ -0,0 +1,105 @@
+package handler
+
+import (
+	"bytes"
+	"hub_management_service/internal/entity"
+	"hub_management_service/internal/middleware"
+	"hub_management_service/internal/service"
+	"hub_management_service/internal/service/mocks"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newBookingRouter(mockService *mocks.BookingService) *gin.Engine {
+	handler := NewBookingHandler(mockService, new(mocks.ExpandService))
+	router := gin.Default()
+	router.Use(func(c *gin.Context) {
+		c.Set(middleware.UsernameKey, "jane@example.com")
+		c.Set(middleware.RoleKey, middleware.RoleEmployee)
+	})
+	router.POST("/resources/:id/bookings", handler.CreateBooking)
+	router.DELETE("/bookings/:id", handler.CancelBooking)
+	router.GET("/hubs/:id/availability", handler.FindAvailability)
+	return router
+}
+
+// TestCreateBookingHandler tests that a booking is created and that overlaps return 409
+func TestCreateBookingHandler(t *testing.T) {
+	mockService := new(mocks.BookingService)
+	router := newBookingRouter(mockService)
+
+	start := time.Date(2030, time.July, 15, 9, 0, 0, 0, time.UTC)
+	account := service.Account{Username: "jane@example.com"}
+	mockService.On("CreateBooking", account, uint(2), mock.MatchedBy(func(b *entity.Booking) bool {
+		return b.StartsAt.Equal(start)
+	})).Return(nil).Once()
+	mockService.On("CreateBooking", account, uint(2), mock.Anything).Return(service.ErrBookingConflict)
+
+	body := `{"starts_at": "2030-07-15T09:00:00Z", "ends_at": "2030-07-15T10:00:00Z", "title": "Planning"}`
+	req, _ := http.NewRequest("POST", "/resources/2/bookings", bytes.NewBufferString(body))
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusCreated, resp.Code)
+
+	req, _ = http.NewRequest("POST", "/resources/2/bookings", bytes.NewBufferString(body))
+	resp = httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusConflict, resp.Code)
+	mockService.AssertExpectations(t)
+}
+
+// TestCancelBookingHandler_Cancelled tests that cancelling twice returns 409
+func TestCancelBookingHandler_Cancelled(t *testing.T) {
+	mockService := new(mocks.BookingService)
+	router := newBookingRouter(mockService)
+
+	mockService.On("CancelBooking", service.Account{Username: "jane@example.com"}, uint(4)).Return(nil, service.ErrBookingCancelled)
+
+	req, _ := http.NewRequest("DELETE", "/bookings/4", nil)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusConflict, resp.Code)
+}
+
+// TestCancelBookingHandler_Forbidden tests that cancelling the booking of another user returns 403
+func TestCancelBookingHandler_Forbidden(t *testing.T) {
+	mockService := new(mocks.BookingService)
+	router := newBookingRouter(mockService)
+
+	mockService.On("CancelBooking", service.Account{Username: "jane@example.com"}, uint(6)).Return(nil, service.ErrBookingForbidden)
+
+	req, _ := http.NewRequest("DELETE", "/bookings/6", nil)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusForbidden, resp.Code)
+}
+
+// TestFindAvailabilityHandler tests that the date and resource filters are parsed
+func TestFindAvailabilityHandler(t *testing.T) {
+	mockService := new(mocks.BookingService)
+	router := newBookingRouter(mockService)
+
+	date := entity.NewDate(2030, time.July, 15)
+	mockService.On("FindAvailability", uint(1), date, service.ResourceQuery{Type: "room", Amenities: []string{"video"}}).
+		Return(&service.Availability{HubID: 1, Date: date, TimeZone: "UTC"}, nil)
+
+	req, _ := http.NewRequest("GET", "/hubs/1/availability?date=2030-07-15&type=room&amenity=video", nil)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Contains(t, resp.Body.String(), `"date":"2030-07-15"`)
+
+	req, _ = http.NewRequest("GET", "/hubs/1/availability?date=15/07/2030", nil)
+	resp = httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+	mockService.AssertExpectations(t)
+}
@@ -0,0 +1,87 @@
+package handler
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"hub_management_service/internal/service"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// etag is the entity tag of a version of a hub, team or user
+func etag(version uint) string {
+	return `"` + strconv.FormatUint(uint64(version), 10) + `"`
+}
+
+// bodyETag is the entity tag of a response that carries more than a version of a record, such as utilisation or
+// expanded relations, which change without bumping the version: the version followed by a hash of the body
+func bodyETag(version uint, body interface{}) (string, error) {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return `"` + strconv.FormatUint(uint64(version), 10) + "-" + hex.EncodeToString(sum[:8]) + `"`, nil
+}
+
+// writeTagged writes body with the ETag of the version of its record, or a bodyETag when derived parts are included,
+// and a 304 response instead when If-None-Match lists the tag
+func writeTagged(c *gin.Context, version uint, derived bool, body interface{}) {
+	tag := etag(version)
+	if derived {
+		var err error
+		if tag, err = bodyETag(version, body); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	if notModified(c, tag) {
+		return
+	}
+	c.JSON(http.StatusOK, body)
+}
+
+// notModified sets the ETag header and writes a 304 response when If-None-Match lists the tag
+func notModified(c *gin.Context, tag string) bool {
+	c.Header("ETag", tag)
+	header := c.GetHeader("If-None-Match")
+	if header == "" {
+		return false
+	}
+	for _, candidate := range strings.Split(header, ",") {
+		// If-None-Match compares weakly, W/"2" matches "2"
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == tag {
+			c.Status(http.StatusNotModified)
+			return true
+		}
+	}
+	return false
+}
+
+// ifMatch reads the version a write expects from the If-Match header, writing a 428 response when the header is
+// missing and a 412 response when it is not the ETag of a version. * matches any version, and the tag of a body with
+// derived parts matches the version it starts with.
+func ifMatch(c *gin.Context) (uint, bool) {
+	header := strings.TrimSpace(c.GetHeader("If-Match"))
+	if header == "" {
+		c.JSON(http.StatusPreconditionRequired, gin.H{"error": "If-Match header with the ETag of the current version is required"})
+		return 0, false
+	}
+	if header == "*" {
+		return service.AnyVersion, true
+	}
+	// If-Match compares strongly, so weak tags and lists never match a single version
+	if len(header) > 2 && header[0] == '"' && header[len(header)-1] == '"' {
+		tag, _, _ := strings.Cut(header[1:len(header)-1], "-")
+		version, err := strconv.ParseUint(tag, 10, 32)
+		if err == nil && version != uint64(service.AnyVersion) {
+			return uint(version), true
+		}
+	}
+	c.JSON(http.StatusPreconditionFailed, gin.H{"error": "If-Match does not match the current version"})
+	return 0, false
+}
@@ -0,0 +1,70 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"hub_management_service/internal/service"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type ExportHandler struct {
+	service service.ExportService
+}
+
+func NewExportHandler(service service.ExportService) *ExportHandler {
+	return &ExportHandler{service: service}
+}
+
+// ExportOrganisation - Handler for downloading every user with their team and hub as ?format=csv, jsonl or xlsx,
+// with ?columns= in order and filtered by ?hub_id= or ?team_id= (including sub-teams). Rows are streamed.
+func (h *ExportHandler) ExportOrganisation(c *gin.Context) {
+	query := service.ExportQuery{Format: service.ExportFormat(c.DefaultQuery("format", string(service.ExportCSV)))}
+	for _, value := range c.QueryArray("columns") {
+		for _, column := range strings.Split(value, ",") {
+			if column = strings.TrimSpace(column); column != "" {
+				query.Columns = append(query.Columns, column)
+			}
+		}
+	}
+	for param, target := range map[string]*uint{"hub_id": &query.HubID, "team_id": &query.TeamID} {
+		if value, ok := c.GetQuery(param); ok {
+			id, err := strconv.Atoi(value)
+			if err != nil || id <= 0 {
+				c.JSON(http.StatusBadRequest, gin.H{"error": param + " must be a positive integer"})
+				return
+			}
+			*target = uint(id)
+		}
+	}
+
+	export, err := h.service.PrepareExport(query)
+	switch {
+	case errors.Is(err, service.ErrHubNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"message": "Hub not found"})
+		return
+	case errors.Is(err, service.ErrTeamNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"message": "Team not found"})
+		return
+	case errors.Is(err, service.ErrInvalidExport):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	filename := fmt.Sprintf("organisation-%s.%s", time.Now().UTC().Format("2006-01-02"), export.Extension())
+	c.Header("Content-Type", export.ContentType())
+	c.Header("Content-Disposition", `attachment; filename="`+filename+`"`)
+	c.Status(http.StatusOK)
+	// The status is already sent, a failure can only cut the file short
+	if err := export.Stream(c.Writer); err != nil {
+		log.Printf("streaming %s: %v", filename, err)
+		c.Abort()
+	}
+}
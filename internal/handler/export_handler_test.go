@@ -0,0 +1,62 @@
+package handler
+
+import (
+	"hub_management_service/internal/service"
+	"hub_management_service/internal/service/mocks"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newExportRouter(mockService *mocks.ExportService) *gin.Engine {
+	handler := NewExportHandler(mockService)
+	router := gin.Default()
+	router.GET("/export", handler.ExportOrganisation)
+	return router
+}
+
+// TestExportOrganisationHandler tests that the query is parsed and the export is streamed as an attachment
+func TestExportOrganisationHandler(t *testing.T) {
+	mockService := new(mocks.ExportService)
+	mockExport := new(mocks.Export)
+	router := newExportRouter(mockService)
+	mockService.On("PrepareExport", service.ExportQuery{Format: service.ExportCSV, Columns: []string{"name", "email", "hub"}, TeamID: 2}).
+		Return(mockExport, nil)
+	mockExport.On("ContentType").Return("text/csv; charset=utf-8")
+	mockExport.On("Extension").Return("csv")
+	mockExport.On("Stream", mock.Anything).Run(func(args mock.Arguments) {
+		io.WriteString(args.Get(0).(io.Writer), "name,email,hub\n")
+	}).Return(nil)
+
+	req, _ := http.NewRequest("GET", "/export?columns=name,email&columns=hub&team_id=2", nil)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, "text/csv; charset=utf-8", resp.Header().Get("Content-Type"))
+	assert.Regexp(t, `^attachment; filename="organisation-\d{4}-\d{2}-\d{2}\.csv"$`, resp.Header().Get("Content-Disposition"))
+	assert.Equal(t, "name,email,hub\n", resp.Body.String())
+}
+
+// TestExportOrganisationHandler_Invalid tests that errors found before streaming return their status
+func TestExportOrganisationHandler_Invalid(t *testing.T) {
+	mockService := new(mocks.ExportService)
+	router := newExportRouter(mockService)
+	mockService.On("PrepareExport", service.ExportQuery{Format: "pdf"}).Return(nil, service.ErrInvalidExport)
+	mockService.On("PrepareExport", service.ExportQuery{Format: service.ExportXLSX, HubID: 9}).Return(nil, service.ErrHubNotFound)
+
+	for url, status := range map[string]int{
+		"/export?format=pdf":             http.StatusBadRequest,
+		"/export?format=xlsx&hub_id=9":   http.StatusNotFound,
+		"/export?format=xlsx&hub_id=abc": http.StatusBadRequest,
+	} {
+		req, _ := http.NewRequest("GET", url, nil)
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		assert.Equal(t, status, resp.Code, url)
+	}
+}
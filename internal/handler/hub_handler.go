@@ -1,19 +1,22 @@
 package handler
 
 import (
+	"errors"
 	"github.com/gin-gonic/gin"
 	"hub_management_service/internal/entity"
 	"hub_management_service/internal/service"
 	"net/http"
 	"strconv"
+	"strings"
 )
 
 type HubHandler struct {
 	service service.HubService
+	expand  service.ExpandService
 }
 
-func NewHubHandler(service service.HubService) *HubHandler {
-	return &HubHandler{service: service}
+func NewHubHandler(service service.HubService, expand service.ExpandService) *HubHandler {
+	return &HubHandler{service: service, expand: expand}
 }
 
 // CreateHub handles the creation of a new hub
@@ -26,20 +29,39 @@ func (h *HubHandler) CreateHub(c *gin.Context) {
 	}
 
 	if err := h.service.CreateHub(&hub); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeHubError(c, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{"message": "Hub created successfully", "hub": hub})
 }
 
-// FindHubByID retrieves a hub by its ID
+// FindHubByID retrieves a hub by its ID, ?fields= and ?expand= select the fields and relations of the hub.
+// ?include=resources is kept as an alias of ?expand=resources.
 func (h *HubHandler) FindHubByID(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
 		return
 	}
+	var includeResources bool
+	for _, include := range strings.Split(c.Query("include"), ",") {
+		switch strings.TrimSpace(include) {
+		case "":
+		case "resources":
+			includeResources = true
+		default:
+			c.JSON(http.StatusBadRequest, gin.H{"error": "include must be a comma-separated list of: resources"})
+			return
+		}
+	}
+	opts, ok := parseReadOptions(c, service.HubRecord, entity.Hub{}, "")
+	if !ok {
+		return
+	}
+	if includeResources && opts.expansion["resources"] == nil {
+		opts.expansion["resources"] = service.Expansion{}
+	}
 
 	hub, err := h.service.FindHubByID(uint(id))
 	if err != nil {
@@ -51,17 +73,65 @@ func (h *HubHandler) FindHubByID(c *gin.Context) {
 		c.JSON(http.StatusNotFound, gin.H{"message": "Hub not found"})
 		return
 	}
+	utilisation, err := h.service.FindUtilisation(hub)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if !opts.expandHubs(c, h.expand, hub) {
+		return
+	}
+	record, ok := opts.sparse(c, hub)
+	if !ok {
+		return
+	}
+
+	// The utilisation changes with the headcount, without bumping the version of the hub
+	writeTagged(c, hub.Version, true, gin.H{"hub": record, "utilisation": utilisation})
+}
+
+// UtilisationReport lists the headcount and capacity of every hub, the most utilised first. ?fields= and ?expand=
+// select the fields of each line and whether it carries its hub.
+func (h *HubHandler) UtilisationReport(c *gin.Context) {
+	opts, ok := parseReadOptions(c, service.UtilisationRecord, service.HubUtilisation{}, "")
+	if !ok {
+		return
+	}
+	report, err := h.service.UtilisationReport()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if len(opts.expansion) > 0 {
+		lines := make([]*service.HubUtilisation, len(report.Hubs))
+		for i := range report.Hubs {
+			lines[i] = &report.Hubs[i]
+		}
+		if !expanded(c, h.expand.ExpandUtilisation(lines, opts.expansion)) {
+			return
+		}
+	}
+	// The lines of the report are keyed by hub_id, their expanded hubs by id
+	records, err := opts.fields.Select(report.Hubs, "hub_id", "id")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
 
-	c.JSON(http.StatusOK, gin.H{"hub": hub})
+	c.JSON(http.StatusOK, gin.H{"threshold": report.Threshold, "hubs": records})
 }
 
-// SearchHubsByName searches for hubs by name
+// SearchHubsByName searches for hubs by name, along with their teams unless ?expand= says otherwise
 func (h *HubHandler) SearchHubsByName(c *gin.Context) {
 	name := c.DefaultQuery("name", "") // Get the 'name' query parameter
 	if name == "" {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Name parameter is required"})
 		return
 	}
+	opts, ok := parseReadOptions(c, service.HubRecord, entity.Hub{}, "teams")
+	if !ok {
+		return
+	}
 
 	hubs, err := h.service.SearchHubsByName(name)
 	if err != nil {
@@ -73,6 +143,188 @@ func (h *HubHandler) SearchHubsByName(c *gin.Context) {
 		c.JSON(http.StatusNotFound, gin.H{"message": "No hubs found with the given name"})
 		return
 	}
+	if !opts.expandHubs(c, h.expand, hubRefs(hubs)...) {
+		return
+	}
+	records, ok := opts.sparse(c, hubs)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"hubs": records})
+}
+
+// ListHubs lists hubs, optionally filtered by region_id, country_id or city
+func (h *HubHandler) ListHubs(c *gin.Context) {
+	query := service.HubQuery{City: c.Query("city")}
+	for param, target := range map[string]*uint{"region_id": &query.RegionID, "country_id": &query.CountryID} {
+		if value, ok := c.GetQuery(param); ok {
+			id, err := strconv.ParseUint(value, 10, 32)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid " + param})
+				return
+			}
+			*target = uint(id)
+		}
+	}
+	for param, target := range map[string]*int{"offset": &query.Offset, "limit": &query.Limit} {
+		if value, ok := c.GetQuery(param); ok {
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 0 {
+				c.JSON(http.StatusBadRequest, gin.H{"error": param + " must be a non-negative integer"})
+				return
+			}
+			*target = n
+		}
+	}
+	opts, ok := parseReadOptions(c, service.HubRecord, entity.Hub{}, "")
+	if !ok {
+		return
+	}
+
+	hubs, total, err := h.service.ListHubs(query)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if !opts.expandHubs(c, h.expand, hubRefs(hubs)...) {
+		return
+	}
+	records, ok := opts.sparse(c, hubs)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"hubs": records, "total": total})
+}
+
+// UpdateHub replaces the name, location and capacity of a hub at the version of If-Match
+func (h *HubHandler) UpdateHub(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+		return
+	}
+	version, ok := ifMatch(c)
+	if !ok {
+		return
+	}
+	var update entity.Hub
+	if err := c.ShouldBindJSON(&update); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	hub, err := h.service.UpdateHub(uint(id), version, &update)
+	if err != nil {
+		writeHubError(c, err)
+		return
+	}
+	c.Header("ETag", etag(hub.Version))
+
+	c.JSON(http.StatusOK, gin.H{"message": "Hub updated successfully", "hub": hub})
+}
+
+// PatchHub applies a JSON Merge Patch or JSON Patch to a hub at the version of If-Match
+func (h *HubHandler) PatchHub(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+		return
+	}
+	version, ok := ifMatch(c)
+	if !ok {
+		return
+	}
+	format, patch, ok := readPatch(c)
+	if !ok {
+		return
+	}
 
-	c.JSON(http.StatusOK, gin.H{"hubs": hubs})
+	hub, err := h.service.PatchHub(uint(id), version, format, patch)
+	if err != nil {
+		writeHubError(c, err)
+		return
+	}
+	c.Header("ETag", etag(hub.Version))
+
+	c.JSON(http.StatusOK, gin.H{"message": "Hub updated successfully", "hub": hub})
+}
+
+// FindNearby finds the hubs within radius_km of lat and lng, nearest first
+func (h *HubHandler) FindNearby(c *gin.Context) {
+	var query service.NearbyQuery
+	for _, param := range []struct {
+		name     string
+		target   *float64
+		required bool
+	}{
+		{"lat", &query.Lat, true},
+		{"lng", &query.Lng, true},
+		{"radius_km", &query.RadiusKm, false},
+	} {
+		value, ok := c.GetQuery(param.name)
+		if !ok && param.required {
+			c.JSON(http.StatusBadRequest, gin.H{"error": param.name + " is required"})
+			return
+		}
+		if !ok {
+			continue
+		}
+		n, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": param.name + " must be a number"})
+			return
+		}
+		*param.target = n
+	}
+	if value, ok := c.GetQuery("limit"); ok {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be a non-negative integer"})
+			return
+		}
+		query.Limit = n
+	}
+	opts, ok := parseReadOptions(c, service.HubRecord, service.NearbyHub{}, "")
+	if !ok {
+		return
+	}
+
+	hubs, err := h.service.FindNearby(query)
+	if err != nil {
+		writeHubError(c, err)
+		return
+	}
+	refs := make([]*entity.Hub, len(hubs))
+	for i := range hubs {
+		refs[i] = &hubs[i].Hub
+	}
+	if !opts.expandHubs(c, h.expand, refs...) {
+		return
+	}
+	records, ok := opts.sparse(c, hubs)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"hubs": records})
+}
+
+func writeHubError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrHubNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"message": "Hub not found"})
+	case errors.Is(err, service.ErrVersionMismatch):
+		c.JSON(http.StatusPreconditionFailed, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrCountryNotFound), errors.Is(err, service.ErrInvalidCoordinates), errors.Is(err, service.ErrInvalidRadius),
+		errors.Is(err, service.ErrInvalidTimeZone), errors.Is(err, service.ErrInvalidPatch):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrPatchConflict):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrInvalidPatchResult):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
 }
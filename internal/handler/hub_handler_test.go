@@ -2,7 +2,10 @@ package handler
 
 import (
 	"bytes"
+	"encoding/json"
+	"fmt"
 	"hub_management_service/internal/entity"
+	"hub_management_service/internal/service"
 	"hub_management_service/internal/service/mocks"
 	"net/http"
 	"net/http/httptest"
@@ -10,12 +13,13 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
 // TestFindHubByID tests the FindHubByID handler when the hub is found
 func TestFindHubByID(t *testing.T) {
 	mockService := new(mocks.HubService)
-	handler := NewHubHandler(mockService)
+	handler := NewHubHandler(mockService, new(mocks.ExpandService))
 
 	router := gin.Default()
 	router.GET("/hubs/:id", handler.FindHubByID)
@@ -26,6 +30,7 @@ func TestFindHubByID(t *testing.T) {
 		Name:     "Test Hub",
 		Location: "Test Location",
 	}, nil)
+	mockService.On("FindUtilisation", mock.AnythingOfType("*entity.Hub")).Return(&service.HubUtilisation{HubID: 1, Headcount: 3}, nil)
 
 	// Create request with ID parameter
 	req, _ := http.NewRequest("GET", "/hubs/1", nil)
@@ -35,13 +40,115 @@ func TestFindHubByID(t *testing.T) {
 	// Assert the response code and structure
 	assert.Equal(t, http.StatusOK, resp.Code)
 	assert.Contains(t, resp.Body.String(), "Test Hub")
+	assert.Contains(t, resp.Body.String(), `"headcount":3`)
 	mockService.AssertExpectations(t)
 }
 
+// TestFindHubByID_NotModified tests that the ETag covers the version and the utilisation, and that a matching
+// If-None-Match returns 304 only while both are unchanged
+func TestFindHubByID_NotModified(t *testing.T) {
+	mockService := new(mocks.HubService)
+	handler := NewHubHandler(mockService, new(mocks.ExpandService))
+
+	router := gin.Default()
+	router.GET("/hubs/:id", handler.FindHubByID)
+
+	mockService.On("FindHubByID", uint(1)).Return(&entity.Hub{ID: 1, Name: "Test Hub", Version: 3}, nil)
+	mockService.On("FindUtilisation", mock.Anything).Return(&service.HubUtilisation{HubID: 1, Headcount: 3}, nil).Twice()
+	mockService.On("FindUtilisation", mock.Anything).Return(&service.HubUtilisation{HubID: 1, Headcount: 4}, nil)
+
+	req, _ := http.NewRequest("GET", "/hubs/1", nil)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	tag := resp.Header().Get("ETag")
+	assert.Regexp(t, `^"3-[0-9a-f]+"$`, tag)
+
+	req, _ = http.NewRequest("GET", "/hubs/1", nil)
+	req.Header.Set("If-None-Match", `"2", W/`+tag)
+	resp = httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusNotModified, resp.Code)
+	assert.Empty(t, resp.Body.String())
+
+	// A user joined the hub, which changes the body but not the version
+	req, _ = http.NewRequest("GET", "/hubs/1", nil)
+	req.Header.Set("If-None-Match", tag)
+	resp = httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.NotEqual(t, tag, resp.Header().Get("ETag"))
+}
+
+// TestFindHubByID_IncludeResources tests that ?include=resources expands the desks and rooms of a hub
+func TestFindHubByID_IncludeResources(t *testing.T) {
+	mockService := new(mocks.HubService)
+	mockExpand := new(mocks.ExpandService)
+	handler := NewHubHandler(mockService, mockExpand)
+
+	router := gin.Default()
+	router.GET("/hubs/:id", handler.FindHubByID)
+
+	mockService.On("FindHubByID", uint(1)).Return(&entity.Hub{ID: 1, Name: "Test Hub"}, nil)
+	mockService.On("FindUtilisation", mock.AnythingOfType("*entity.Hub")).Return(&service.HubUtilisation{HubID: 1}, nil)
+	mockExpand.On("ExpandHubs", mock.Anything, service.Expansion{"resources": {}, "country": {}}).Run(func(args mock.Arguments) {
+		hub := args.Get(0).([]*entity.Hub)[0]
+		hub.Resources = &[]entity.Resource{{ID: 2, HubID: 1, Type: entity.ResourceRoom, Name: "Seine"}}
+	}).Return(nil)
+
+	req, _ := http.NewRequest("GET", "/hubs/1?include=resources&expand=country", nil)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Contains(t, resp.Body.String(), `"name":"Seine"`)
+	mockExpand.AssertExpectations(t)
+
+	req, _ = http.NewRequest("GET", "/hubs/1?include=parking", nil)
+	resp = httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+}
+
+// TestFindHubByID_FieldsAndExpand tests that ?expand= loads the teams and ?fields= keeps the selected fields and IDs
+func TestFindHubByID_FieldsAndExpand(t *testing.T) {
+	mockService := new(mocks.HubService)
+	mockExpand := new(mocks.ExpandService)
+	handler := NewHubHandler(mockService, mockExpand)
+
+	router := gin.Default()
+	router.GET("/hubs/:id", handler.FindHubByID)
+
+	mockService.On("FindHubByID", uint(1)).Return(&entity.Hub{ID: 1, Name: "Test Hub", Location: "Paris"}, nil)
+	mockService.On("FindUtilisation", mock.AnythingOfType("*entity.Hub")).Return(&service.HubUtilisation{HubID: 1}, nil)
+	mockExpand.On("ExpandHubs", mock.Anything, service.Expansion{"teams": {"users": {}}}).Run(func(args mock.Arguments) {
+		hub := args.Get(0).([]*entity.Hub)[0]
+		hub.Teams = &[]entity.Team{{ID: 2, Name: "Backend", HubID: 1, Users: &[]entity.User{{ID: 7, Name: "Jane", Email: "jane@example.com"}}}}
+	}).Return(nil)
+
+	req, _ := http.NewRequest("GET", "/hubs/1?expand=teams.users&fields=name,teams.name,teams.users.email", nil)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	var body struct {
+		Hub map[string]interface{}
+	}
+	assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), &body))
+	data, _ := json.Marshal(body.Hub)
+	assert.JSONEq(t, `{"id":1,"name":"Test Hub","teams":[{"id":2,"name":"Backend","users":[{"id":7,"email":"jane@example.com"}]}]}`, string(data))
+
+	for _, query := range []string{"fields=nickname", "fields=teams.budget", "expand=users", "expand=teams.users.team.hub"} {
+		req, _ := http.NewRequest("GET", "/hubs/1?"+query, nil)
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		assert.Equal(t, http.StatusBadRequest, resp.Code, query)
+	}
+}
+
 // TestFindHubByID_NotFound tests the FindHubByID handler when no hub is found
 func TestFindHubByID_NotFound(t *testing.T) {
 	mockService := new(mocks.HubService)
-	handler := NewHubHandler(mockService)
+	handler := NewHubHandler(mockService, new(mocks.ExpandService))
 
 	router := gin.Default()
 	router.GET("/hubs/:id", handler.FindHubByID)
@@ -62,7 +169,8 @@ func TestFindHubByID_NotFound(t *testing.T) {
 // TestSearchHubsByName tests the SearchHubsByName handler when hubs are found
 func TestSearchHubsByName(t *testing.T) {
 	mockService := new(mocks.HubService)
-	handler := NewHubHandler(mockService)
+	mockExpand := new(mocks.ExpandService)
+	handler := NewHubHandler(mockService, mockExpand)
 
 	router := gin.Default()
 	router.GET("/hubs/search", handler.SearchHubsByName)
@@ -75,6 +183,8 @@ func TestSearchHubsByName(t *testing.T) {
 			Location: "Test Location",
 		},
 	}, nil)
+	// Teams are expanded unless ?expand= says otherwise
+	mockExpand.On("ExpandHubs", mock.Anything, service.Expansion{"teams": {}}).Return(nil).Once()
 
 	// Create request with query parameter
 	req, _ := http.NewRequest("GET", "/hubs/search?name=Test Hub", nil)
@@ -85,12 +195,18 @@ func TestSearchHubsByName(t *testing.T) {
 	assert.Equal(t, http.StatusOK, resp.Code)
 	assert.Contains(t, resp.Body.String(), "Test Hub")
 	mockService.AssertExpectations(t)
+
+	req, _ = http.NewRequest("GET", "/hubs/search?name=Test Hub&expand=", nil)
+	resp = httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	mockExpand.AssertExpectations(t)
 }
 
 // TestSearchHubsByName_NoResults tests the SearchHubsByName handler when no hubs are found
 func TestSearchHubsByName_NoResults(t *testing.T) {
 	mockService := new(mocks.HubService)
-	handler := NewHubHandler(mockService)
+	handler := NewHubHandler(mockService, new(mocks.ExpandService))
 
 	router := gin.Default()
 	router.GET("/hubs/search", handler.SearchHubsByName)
@@ -111,7 +227,7 @@ func TestSearchHubsByName_NoResults(t *testing.T) {
 // TestCreateHub tests the CreateHub handler with valid input
 func TestCreateHub(t *testing.T) {
 	mockService := new(mocks.HubService)
-	handler := NewHubHandler(mockService)
+	handler := NewHubHandler(mockService, new(mocks.ExpandService))
 
 	router := gin.Default()
 	router.POST("/hubs", handler.CreateHub)
@@ -137,7 +253,7 @@ func TestCreateHub(t *testing.T) {
 // TestCreateHub_BadRequest tests the CreateHub handler with invalid input
 func TestCreateHub_BadRequest(t *testing.T) {
 	mockService := new(mocks.HubService)
-	handler := NewHubHandler(mockService)
+	handler := NewHubHandler(mockService, new(mocks.ExpandService))
 
 	router := gin.Default()
 	router.POST("/hubs", handler.CreateHub)
@@ -152,3 +268,170 @@ func TestCreateHub_BadRequest(t *testing.T) {
 	assert.Equal(t, http.StatusBadRequest, resp.Code)
 	mockService.AssertExpectations(t)
 }
+
+// TestListHubs tests that the region and country filters are passed to the service
+func TestListHubs(t *testing.T) {
+	mockService := new(mocks.HubService)
+	handler := NewHubHandler(mockService, new(mocks.ExpandService))
+
+	router := gin.Default()
+	router.GET("/hubs", handler.ListHubs)
+
+	mockService.On("ListHubs", service.HubQuery{RegionID: 1, CountryID: 2, City: "Paris"}).Return([]entity.Hub{{ID: 1, Name: "Paris Hub"}}, int64(1), nil)
+
+	req, _ := http.NewRequest("GET", "/hubs?region_id=1&country_id=2&city=Paris", nil)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Contains(t, resp.Body.String(), `"total":1`)
+	mockService.AssertExpectations(t)
+}
+
+// TestUpdateHub_CountryNotFound tests that an unknown country returns 400
+func TestUpdateHub_CountryNotFound(t *testing.T) {
+	mockService := new(mocks.HubService)
+	handler := NewHubHandler(mockService, new(mocks.ExpandService))
+
+	router := gin.Default()
+	router.PUT("/hubs/:id", handler.UpdateHub)
+
+	mockService.On("UpdateHub", uint(1), service.AnyVersion, mock.AnythingOfType("*entity.Hub")).Return(nil, service.ErrCountryNotFound)
+
+	body := `{"name": "Paris Hub", "location": "Paris", "country_id": 9, "city": "Paris"}`
+	req, _ := http.NewRequest("PUT", "/hubs/1", bytes.NewBufferString(body))
+	req.Header.Set("If-Match", "*")
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+}
+
+// TestUpdateHub_Preconditions tests that updates require an If-Match header matching the current version
+func TestUpdateHub_Preconditions(t *testing.T) {
+	mockService := new(mocks.HubService)
+	handler := NewHubHandler(mockService, new(mocks.ExpandService))
+
+	router := gin.Default()
+	router.PUT("/hubs/:id", handler.UpdateHub)
+
+	mockService.On("UpdateHub", uint(1), uint(2), mock.AnythingOfType("*entity.Hub")).Return(nil, service.ErrVersionMismatch)
+
+	body := `{"name": "Paris Hub", "location": "Paris"}`
+	for _, tc := range []struct {
+		ifMatch string
+		status  int
+	}{
+		{"", http.StatusPreconditionRequired},
+		{`W/"2"`, http.StatusPreconditionFailed},    // Weak tags never match
+		{`"2"`, http.StatusPreconditionFailed},      // The hub was updated since
+		{`"2-4f1c"`, http.StatusPreconditionFailed}, // The tag of GET /hubs/{id} names the version first
+	} {
+		req, _ := http.NewRequest("PUT", "/hubs/1", bytes.NewBufferString(body))
+		if tc.ifMatch != "" {
+			req.Header.Set("If-Match", tc.ifMatch)
+		}
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		assert.Equal(t, tc.status, resp.Code, tc.ifMatch)
+	}
+	mockService.AssertNumberOfCalls(t, "UpdateHub", 2)
+}
+
+// TestPatchHub tests that only patch documents are accepted and that patch errors map to 400, 409 and 422
+func TestPatchHub(t *testing.T) {
+	mockService := new(mocks.HubService)
+	handler := NewHubHandler(mockService, new(mocks.ExpandService))
+
+	router := gin.Default()
+	router.PATCH("/hubs/:id", handler.PatchHub)
+
+	mockService.On("PatchHub", uint(1), uint(2), service.MergePatch, []byte(`{"name": "Paris Central"}`)).
+		Return(&entity.Hub{ID: 1, Name: "Paris Central", Location: "Paris", Version: 3}, nil)
+	mockService.On("PatchHub", uint(1), uint(2), service.JSONPatch, []byte(`[]`)).Return(nil, fmt.Errorf("%w: test failed", service.ErrPatchConflict)).Once()
+	mockService.On("PatchHub", uint(1), uint(2), service.JSONPatch, []byte(`[]`)).Return(nil, fmt.Errorf("%w: name is required", service.ErrInvalidPatchResult)).Once()
+
+	for _, tc := range []struct {
+		contentType, body string
+		status            int
+	}{
+		{"application/merge-patch+json", `{"name": "Paris Central"}`, http.StatusOK},
+		{"application/json-patch+json", `[]`, http.StatusConflict},
+		{"application/json-patch+json; charset=utf-8", `[]`, http.StatusUnprocessableEntity},
+		{"application/json", `{"name": "Paris Central"}`, http.StatusUnsupportedMediaType},
+	} {
+		req, _ := http.NewRequest("PATCH", "/hubs/1", bytes.NewBufferString(tc.body))
+		req.Header.Set("Content-Type", tc.contentType)
+		req.Header.Set("If-Match", `"2"`)
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		assert.Equal(t, tc.status, resp.Code, tc.contentType)
+		if tc.status == http.StatusOK {
+			assert.Equal(t, `"3"`, resp.Header().Get("ETag"))
+		}
+		if tc.status == http.StatusUnsupportedMediaType {
+			assert.Equal(t, "application/merge-patch+json, application/json-patch+json", resp.Header().Get("Accept-Patch"))
+		}
+	}
+	mockService.AssertNumberOfCalls(t, "PatchHub", 3)
+}
+
+// TestFindNearby tests that hubs are returned with their distance
+func TestFindNearby(t *testing.T) {
+	mockService := new(mocks.HubService)
+	handler := NewHubHandler(mockService, new(mocks.ExpandService))
+
+	router := gin.Default()
+	router.GET("/hubs/nearby", handler.FindNearby)
+
+	mockService.On("FindNearby", service.NearbyQuery{Lat: 48.8584, Lng: 2.2945, RadiusKm: 10}).
+		Return([]service.NearbyHub{{Hub: entity.Hub{ID: 2, Name: "Paris Hub"}, DistanceKm: 4.213}}, nil)
+
+	req, _ := http.NewRequest("GET", "/hubs/nearby?lat=48.8584&lng=2.2945&radius_km=10", nil)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Contains(t, resp.Body.String(), `"distance_km":4.213`)
+	mockService.AssertExpectations(t)
+}
+
+// TestFindNearby_MissingLng tests that both coordinates are required
+func TestFindNearby_MissingLng(t *testing.T) {
+	mockService := new(mocks.HubService)
+	handler := NewHubHandler(mockService, new(mocks.ExpandService))
+
+	router := gin.Default()
+	router.GET("/hubs/nearby", handler.FindNearby)
+
+	req, _ := http.NewRequest("GET", "/hubs/nearby?lat=48.8584", nil)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+	mockService.AssertNotCalled(t, "FindNearby", mock.Anything)
+}
+
+// TestUtilisationReport tests that the report includes the threshold and every hub
+func TestUtilisationReport(t *testing.T) {
+	mockService := new(mocks.HubService)
+	handler := NewHubHandler(mockService, new(mocks.ExpandService))
+
+	router := gin.Default()
+	router.GET("/hubs/utilisation", handler.UtilisationReport)
+
+	capacity, ratio := 10, 0.9
+	mockService.On("UtilisationReport").Return(&service.UtilisationReport{
+		Threshold: 0.9,
+		Hubs:      []service.HubUtilisation{{HubID: 1, Name: "Main Hub", Headcount: 9, Capacity: &capacity, Utilisation: &ratio, OverThreshold: true}},
+	}, nil)
+
+	req, _ := http.NewRequest("GET", "/hubs/utilisation", nil)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Contains(t, resp.Body.String(), `"threshold":0.9`)
+	assert.Contains(t, resp.Body.String(), `"over_threshold":true`)
+	mockService.AssertExpectations(t)
+}
@@ -0,0 +1,86 @@
+package handler
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"hub_management_service/internal/service"
+	"mime"
+	"net/http"
+	"strconv"
+)
+
+// maxImportBytes bounds the size of an uploaded import file
+const maxImportBytes = 32 << 20
+
+// importFormats maps the content types of import files to their format
+var importFormats = map[string]service.ImportFormat{
+	"text/csv":             service.ImportCSV,
+	"application/jsonl":    service.ImportJSONL,
+	"application/x-ndjson": service.ImportJSONL,
+	"application/x-jsonl":  service.ImportJSONL,
+}
+
+type ImportHandler struct {
+	service service.ImportService
+}
+
+func NewImportHandler(service service.ImportService) *ImportHandler {
+	return &ImportHandler{service: service}
+}
+
+// Import - Handler for importing hubs, teams or users from a CSV or JSON Lines body, all or nothing.
+// The format comes from ?format= or the Content-Type, ?dry_run=true only reports the errors.
+func (h *ImportHandler) Import(c *gin.Context) {
+	opts, ok := parseImportOptions(c)
+	if !ok {
+		return
+	}
+
+	body := http.MaxBytesReader(c.Writer, c.Request.Body, maxImportBytes)
+	report, err := h.service.Import(service.ImportKind(c.Param("kind")), body, opts)
+	var tooLarge *http.MaxBytesError
+	switch {
+	case errors.As(err, &tooLarge):
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "the import file must not exceed 32 MiB"})
+	case errors.Is(err, service.ErrInvalidImport):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	case report.Committed:
+		c.JSON(http.StatusCreated, report)
+	case report.DryRun:
+		c.JSON(http.StatusOK, report)
+	default:
+		c.JSON(http.StatusUnprocessableEntity, report)
+	}
+}
+
+func parseImportOptions(c *gin.Context) (service.ImportOptions, bool) {
+	var opts service.ImportOptions
+	writeOpts, ok := parseWriteOptions(c)
+	if !ok {
+		return opts, false
+	}
+	opts.WriteOptions = writeOpts
+
+	if value, ok := c.GetQuery("dry_run"); ok {
+		dryRun, err := strconv.ParseBool(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "dry_run must be a boolean"})
+			return opts, false
+		}
+		opts.DryRun = dryRun
+	}
+
+	opts.Format = service.ImportFormat(c.Query("format"))
+	if opts.Format == "" {
+		mediaType, _, _ := mime.ParseMediaType(c.ContentType())
+		format, ok := importFormats[mediaType]
+		if !ok {
+			c.JSON(http.StatusUnsupportedMediaType, gin.H{"error": "send text/csv or application/jsonl, or set ?format=csv or ?format=jsonl"})
+			return opts, false
+		}
+		opts.Format = format
+	}
+	return opts, true
+}
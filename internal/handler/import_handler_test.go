@@ -0,0 +1,66 @@
+package handler
+
+import (
+	"bytes"
+	"fmt"
+	"hub_management_service/internal/service"
+	"hub_management_service/internal/service/mocks"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newImportRouter(mockService *mocks.ImportService) *gin.Engine {
+	handler := NewImportHandler(mockService)
+	router := gin.Default()
+	router.POST("/import/:kind", handler.Import)
+	return router
+}
+
+// TestImportHandler tests the status codes of committed imports, dry runs and rejected rows
+func TestImportHandler(t *testing.T) {
+	mockService := new(mocks.ImportService)
+	router := newImportRouter(mockService)
+	rejected := &service.ImportReport{Kind: service.ImportUsers, Rows: 1, Errors: []service.ImportRowError{{Line: 2, Field: "email", Error: "is required"}}}
+	mockService.On("Import", service.ImportUsers, mock.Anything, service.ImportOptions{Format: service.ImportCSV}).
+		Return(&service.ImportReport{Kind: service.ImportUsers, Rows: 1, Valid: 1, Committed: true}, nil).Once()
+	mockService.On("Import", service.ImportUsers, mock.Anything, service.ImportOptions{Format: service.ImportJSONL, DryRun: true}).
+		Return(&service.ImportReport{Kind: service.ImportUsers, DryRun: true, Rows: 1, Valid: 1}, nil)
+	mockService.On("Import", service.ImportUsers, mock.Anything, service.ImportOptions{Format: service.ImportCSV}).Return(rejected, nil)
+
+	for _, test := range []struct {
+		query, contentType string
+		status             int
+	}{
+		{"", "text/csv; charset=utf-8", http.StatusCreated},
+		{"?dry_run=true", "application/x-ndjson", http.StatusOK},
+		{"?format=csv", "application/octet-stream", http.StatusUnprocessableEntity},
+		{"", "application/octet-stream", http.StatusUnsupportedMediaType},
+		{"?dry_run=maybe", "text/csv", http.StatusBadRequest},
+	} {
+		req, _ := http.NewRequest("POST", "/import/users"+test.query, bytes.NewBufferString("name,email,team\nJane,jane@example.com,Backend\n"))
+		req.Header.Set("Content-Type", test.contentType)
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		assert.Equal(t, test.status, resp.Code, test.query+" "+test.contentType)
+	}
+	mockService.AssertNumberOfCalls(t, "Import", 3)
+}
+
+// TestImportHandler_InvalidFile tests that malformed files return 400
+func TestImportHandler_InvalidFile(t *testing.T) {
+	mockService := new(mocks.ImportService)
+	router := newImportRouter(mockService)
+	mockService.On("Import", service.ImportKind("regions"), mock.Anything, mock.Anything).
+		Return(nil, fmt.Errorf("%w: unknown kind", service.ErrInvalidImport))
+
+	req, _ := http.NewRequest("POST", "/import/regions", bytes.NewBufferString("name\nEurope\n"))
+	req.Header.Set("Content-Type", "text/csv")
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+}
@@ -0,0 +1,115 @@
+package handler
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"hub_management_service/internal/entity"
+	"hub_management_service/internal/service"
+	"net/http"
+	"strconv"
+)
+
+type MembershipHandler struct {
+	service service.MembershipService
+}
+
+func NewMembershipHandler(service service.MembershipService) *MembershipHandler {
+	return &MembershipHandler{service: service}
+}
+
+// ListMembers - Handler for listing the memberships of a team
+func (h *MembershipHandler) ListMembers(c *gin.Context) {
+	teamID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid Team ID"})
+		return
+	}
+
+	memberships, err := h.service.ListMembers(uint(teamID))
+	if err != nil {
+		writeMembershipError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"members": memberships})
+}
+
+// AddMember - Handler for adding a user to a team
+func (h *MembershipHandler) AddMember(c *gin.Context) {
+	teamID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid Team ID"})
+		return
+	}
+	var membership entity.Membership
+	if err := c.ShouldBindJSON(&membership); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	opts, ok := parseWriteOptions(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.AddMember(uint(teamID), &membership, opts); err != nil {
+		writeMembershipError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"message": "Member added successfully", "membership": membership})
+}
+
+// RemoveMember - Handler for removing a user from a team
+func (h *MembershipHandler) RemoveMember(c *gin.Context) {
+	teamID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid Team ID"})
+		return
+	}
+	userID, err := strconv.Atoi(c.Param("user_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid User ID"})
+		return
+	}
+
+	if err := h.service.RemoveMember(uint(teamID), uint(userID)); err != nil {
+		writeMembershipError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Member removed successfully"})
+}
+
+// ListUserMemberships - Handler for listing the teams a user belongs to
+func (h *MembershipHandler) ListUserMemberships(c *gin.Context) {
+	userID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid User ID"})
+		return
+	}
+
+	memberships, err := h.service.ListUserMemberships(uint(userID))
+	if err != nil {
+		writeMembershipError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"memberships": memberships})
+}
+
+// writeMembershipError maps membership service errors to HTTP responses
+func writeMembershipError(c *gin.Context, err error) {
+	var fullErr *service.HubFullError
+	switch {
+	case errors.Is(err, service.ErrTeamNotFound), errors.Is(err, service.ErrUserNotFound), errors.Is(err, service.ErrNotMember):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrInvalidRole), errors.Is(err, service.ErrInvalidMembershipDate), errors.Is(err, service.ErrPrimaryMembership):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.As(err, &fullErr):
+		writeHubFull(c, fullErr)
+	case errors.Is(err, service.ErrAlreadyMember):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,110 @@
+package handler
+
+import (
+	"bytes"
+	"hub_management_service/internal/entity"
+	"hub_management_service/internal/service"
+	"hub_management_service/internal/service/mocks"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newMembershipRouter(mockService *mocks.MembershipService) *gin.Engine {
+	handler := NewMembershipHandler(mockService)
+	router := gin.Default()
+	router.GET("/teams/:id/members", handler.ListMembers)
+	router.POST("/teams/:id/members", handler.AddMember)
+	router.DELETE("/teams/:id/members/:user_id", handler.RemoveMember)
+	return router
+}
+
+// TestListMembers tests listing the memberships of a team
+func TestListMembers(t *testing.T) {
+	mockService := new(mocks.MembershipService)
+	router := newMembershipRouter(mockService)
+
+	mockService.On("ListMembers", uint(2)).Return([]entity.Membership{{ID: 1, UserID: 7, TeamID: 2, Role: entity.RoleLead}}, nil)
+
+	req, _ := http.NewRequest("GET", "/teams/2/members", nil)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Contains(t, resp.Body.String(), `"role":"lead"`)
+	mockService.AssertExpectations(t)
+}
+
+// TestAddMember tests adding a member with a role and start date
+func TestAddMember(t *testing.T) {
+	mockService := new(mocks.MembershipService)
+	router := newMembershipRouter(mockService)
+
+	mockService.On("AddMember", uint(2), mock.MatchedBy(func(m *entity.Membership) bool {
+		return m.UserID == 7 && m.Role == entity.RoleObserver && m.StartDate.String() == "2024-03-01"
+	}), service.WriteOptions{}).Return(nil)
+
+	body := `{"user_id": 7, "role": "observer", "start_date": "2024-03-01"}`
+	req, _ := http.NewRequest("POST", "/teams/2/members", bytes.NewBufferString(body))
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusCreated, resp.Code)
+	mockService.AssertExpectations(t)
+}
+
+// TestAddMember_Conflict tests that adding an existing member returns 409
+func TestAddMember_Conflict(t *testing.T) {
+	mockService := new(mocks.MembershipService)
+	router := newMembershipRouter(mockService)
+
+	mockService.On("AddMember", uint(2), mock.Anything, service.WriteOptions{}).Return(service.ErrAlreadyMember)
+
+	req, _ := http.NewRequest("POST", "/teams/2/members", bytes.NewBufferString(`{"user_id": 7}`))
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusConflict, resp.Code)
+}
+
+// TestAddMember_HubFull tests that a primary membership into a full hub returns 409 unless overridden
+func TestAddMember_HubFull(t *testing.T) {
+	mockService := new(mocks.MembershipService)
+	router := newMembershipRouter(mockService)
+
+	mockService.On("AddMember", uint(2), mock.Anything, service.WriteOptions{}).Return(&service.HubFullError{HubID: 1, Headcount: 10, Capacity: 10})
+	mockService.On("AddMember", uint(2), mock.Anything, service.WriteOptions{OverrideCapacity: true}).Return(nil)
+
+	body := `{"user_id": 7, "primary": true}`
+	req, _ := http.NewRequest("POST", "/teams/2/members", bytes.NewBufferString(body))
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusConflict, resp.Code)
+	assert.Contains(t, resp.Body.String(), `"capacity":10`)
+
+	req, _ = http.NewRequest("POST", "/teams/2/members?override_capacity=true", bytes.NewBufferString(body))
+	resp = httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusCreated, resp.Code)
+	mockService.AssertExpectations(t)
+}
+
+// TestRemoveMember_Primary tests that removing the primary membership returns 400
+func TestRemoveMember_Primary(t *testing.T) {
+	mockService := new(mocks.MembershipService)
+	router := newMembershipRouter(mockService)
+
+	mockService.On("RemoveMember", uint(2), uint(7)).Return(service.ErrPrimaryMembership)
+
+	req, _ := http.NewRequest("DELETE", "/teams/2/members/7", nil)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+}
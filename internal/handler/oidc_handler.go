@@ -0,0 +1,85 @@
+package handler
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"hub_management_service/internal/middleware"
+	"hub_management_service/internal/service"
+	"net/http"
+)
+
+// oidcStateCookie holds the signed login state between the redirect and the callback
+const oidcStateCookie = "oidc_state"
+
+type OIDCHandler struct {
+	service service.OIDCService
+}
+
+func NewOIDCHandler(service service.OIDCService) *OIDCHandler {
+	return &OIDCHandler{service: service}
+}
+
+// Login redirects the browser to the identity provider
+func (h *OIDCHandler) Login(c *gin.Context) {
+	login, err := h.service.BeginLogin(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusBadGateway, gin.H{"error": err.Error()})
+		return
+	}
+
+	state, err := middleware.GenerateOIDCState(login.State, login.Nonce, login.CodeVerifier)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate state"})
+		return
+	}
+
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(oidcStateCookie, state, 600, "/auth/oidc", "", c.Request.TLS != nil, true)
+	c.Redirect(http.StatusFound, login.AuthURL)
+}
+
+// Callback completes the login and issues the service's own JWT token
+func (h *OIDCHandler) Callback(c *gin.Context) {
+	if errCode := c.Query("error"); errCode != "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Identity provider returned " + errCode})
+		return
+	}
+
+	code := c.Query("code")
+	if code == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Code parameter is required"})
+		return
+	}
+
+	cookie, err := c.Cookie(oidcStateCookie)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Missing login state"})
+		return
+	}
+	// The state cookie is single use
+	c.SetCookie(oidcStateCookie, "", -1, "/auth/oidc", "", c.Request.TLS != nil, true)
+
+	state, nonce, codeVerifier, err := middleware.ParseOIDCState(cookie)
+	if err != nil || state != c.Query("state") {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid login state"})
+		return
+	}
+
+	user, err := h.service.CompleteLogin(c.Request.Context(), code, codeVerifier, nonce)
+	if errors.Is(err, service.ErrUserNotProvisioned) {
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	token, err := middleware.GenerateEmployeeJWT(user.Email)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate token"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"token": token, "user": user})
+}
@@ -0,0 +1,113 @@
+package handler
+
+import (
+	"encoding/json"
+	"hub_management_service/internal/entity"
+	"hub_management_service/internal/middleware"
+	"hub_management_service/internal/service"
+	"hub_management_service/internal/service/mocks"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newOIDCRouter(mockService *mocks.OIDCService) *gin.Engine {
+	handler := NewOIDCHandler(mockService)
+	router := gin.Default()
+	router.GET("/auth/oidc/login", handler.Login)
+	router.GET("/auth/oidc/callback", handler.Callback)
+	return router
+}
+
+// TestOIDCLogin tests that the login redirects to the provider and stores the state in a cookie
+func TestOIDCLogin(t *testing.T) {
+	mockService := new(mocks.OIDCService)
+	router := newOIDCRouter(mockService)
+
+	mockService.On("BeginLogin", mock.Anything).Return(&service.OIDCLogin{
+		AuthURL: "https://idp.example.com/authorize?state=abc",
+		State:   "abc",
+		Nonce:   "nonce",
+	}, nil)
+
+	req, _ := http.NewRequest("GET", "/auth/oidc/login", nil)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusFound, resp.Code)
+	assert.Equal(t, "https://idp.example.com/authorize?state=abc", resp.Header().Get("Location"))
+	assert.Contains(t, resp.Header().Get("Set-Cookie"), oidcStateCookie+"=")
+	mockService.AssertExpectations(t)
+}
+
+// TestOIDCCallback tests that a valid callback issues an employee token, which admin routes refuse
+func TestOIDCCallback(t *testing.T) {
+	mockService := new(mocks.OIDCService)
+	router := newOIDCRouter(mockService)
+	router.POST("/batch", middleware.AuthMiddleware(), func(c *gin.Context) { c.Status(http.StatusOK) })
+	router.POST("/bookings", middleware.EmployeeAuthMiddleware(), func(c *gin.Context) {
+		c.String(http.StatusOK, c.GetString(middleware.RoleKey))
+	})
+
+	mockService.On("CompleteLogin", mock.Anything, "code-1", "verifier", "nonce").Return(&entity.User{ID: 1, Email: "user@example.com"}, nil)
+
+	state, _ := middleware.GenerateOIDCState("abc", "nonce", "verifier")
+	req, _ := http.NewRequest("GET", "/auth/oidc/callback?code=code-1&state=abc", nil)
+	req.AddCookie(&http.Cookie{Name: oidcStateCookie, Value: state})
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	var body struct{ Token string }
+	assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), &body))
+	mockService.AssertExpectations(t)
+
+	req, _ = http.NewRequest("POST", "/batch", nil)
+	req.Header.Set("Authorization", "Bearer "+body.Token)
+	resp = httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusForbidden, resp.Code)
+
+	req, _ = http.NewRequest("POST", "/bookings", nil)
+	req.Header.Set("Authorization", "Bearer "+body.Token)
+	resp = httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, middleware.RoleEmployee, resp.Body.String())
+}
+
+// TestOIDCCallback_StateMismatch tests that a callback with a foreign state is rejected
+func TestOIDCCallback_StateMismatch(t *testing.T) {
+	mockService := new(mocks.OIDCService)
+	router := newOIDCRouter(mockService)
+
+	state, _ := middleware.GenerateOIDCState("abc", "nonce", "verifier")
+	req, _ := http.NewRequest("GET", "/auth/oidc/callback?code=code-1&state=forged", nil)
+	req.AddCookie(&http.Cookie{Name: oidcStateCookie, Value: state})
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+	mockService.AssertNotCalled(t, "CompleteLogin", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+// TestOIDCCallback_NotProvisioned tests that unknown identities get 403
+func TestOIDCCallback_NotProvisioned(t *testing.T) {
+	mockService := new(mocks.OIDCService)
+	router := newOIDCRouter(mockService)
+
+	mockService.On("CompleteLogin", mock.Anything, "code-1", "verifier", "nonce").Return(nil, service.ErrUserNotProvisioned)
+
+	state, _ := middleware.GenerateOIDCState("abc", "nonce", "verifier")
+	req, _ := http.NewRequest("GET", "/auth/oidc/callback?code=code-1&state=abc", nil)
+	req.AddCookie(&http.Cookie{Name: oidcStateCookie, Value: state})
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusForbidden, resp.Code)
+	mockService.AssertExpectations(t)
+}
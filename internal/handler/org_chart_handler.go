@@ -0,0 +1,79 @@
+package handler
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"hub_management_service/internal/service"
+	"hub_management_service/pkg/orgchart"
+	"net/http"
+	"strconv"
+)
+
+type OrgChartHandler struct {
+	service service.OrgChartService
+}
+
+func NewOrgChartHandler(service service.OrgChartService) *OrgChartHandler {
+	return &OrgChartHandler{service: service}
+}
+
+// HubChart - Handler for the org chart of a hub as ?format=svg (default), dot or mermaid, without manager lines with ?managers=false
+func (h *OrgChartHandler) HubChart(c *gin.Context) {
+	hubID, ok := parseHubID(c)
+	if !ok {
+		return
+	}
+	h.render(c, func(managers bool) (*orgchart.Chart, error) {
+		return h.service.HubChart(hubID, managers)
+	})
+}
+
+// TeamChart - Handler for the org chart of a team and its sub-teams, with the same options as HubChart
+func (h *OrgChartHandler) TeamChart(c *gin.Context) {
+	teamID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid Team ID"})
+		return
+	}
+	h.render(c, func(managers bool) (*orgchart.Chart, error) {
+		return h.service.TeamChart(uint(teamID), managers)
+	})
+}
+
+func (h *OrgChartHandler) render(c *gin.Context, build func(managers bool) (*orgchart.Chart, error)) {
+	format := c.DefaultQuery("format", "svg")
+	if format != "svg" && format != "dot" && format != "mermaid" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "format must be svg, dot or mermaid"})
+		return
+	}
+	managers := true
+	if value, ok := c.GetQuery("managers"); ok {
+		var err error
+		if managers, err = strconv.ParseBool(value); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "managers must be a boolean"})
+			return
+		}
+	}
+
+	chart, err := build(managers)
+	switch {
+	case errors.Is(err, service.ErrHubNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"message": "Hub not found"})
+		return
+	case errors.Is(err, service.ErrTeamNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"message": "Team not found"})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	switch format {
+	case "dot":
+		c.Data(http.StatusOK, orgchart.DOTContentType, chart.DOT())
+	case "mermaid":
+		c.Data(http.StatusOK, orgchart.MermaidContentType, chart.Mermaid())
+	default:
+		c.Data(http.StatusOK, orgchart.SVGContentType, chart.SVG())
+	}
+}
@@ -0,0 +1,68 @@
+package handler
+
+import (
+	"hub_management_service/internal/service"
+	"hub_management_service/internal/service/mocks"
+	"hub_management_service/pkg/orgchart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func newOrgChartRouter(mockService *mocks.OrgChartService) *gin.Engine {
+	handler := NewOrgChartHandler(mockService)
+	router := gin.Default()
+	router.GET("/hubs/:id/org-chart", handler.HubChart)
+	router.GET("/teams/:id/org-chart", handler.TeamChart)
+	return router
+}
+
+func testChart() *orgchart.Chart {
+	return &orgchart.Chart{Root: &orgchart.Node{ID: "hub_1", Kind: orgchart.KindHub, Label: "Paris Hub"}}
+}
+
+// TestOrgChartHandler tests that each format is rendered with its content type
+func TestOrgChartHandler(t *testing.T) {
+	mockService := new(mocks.OrgChartService)
+	router := newOrgChartRouter(mockService)
+	mockService.On("HubChart", uint(1), true).Return(testChart(), nil)
+	mockService.On("TeamChart", uint(2), false).Return(testChart(), nil)
+
+	for url, contentType := range map[string]string{
+		"/hubs/1/org-chart":                                orgchart.SVGContentType,
+		"/hubs/1/org-chart?format=dot":                     orgchart.DOTContentType,
+		"/teams/2/org-chart?format=mermaid&managers=false": orgchart.MermaidContentType,
+	} {
+		req, _ := http.NewRequest("GET", url, nil)
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		assert.Equal(t, http.StatusOK, resp.Code, url)
+		assert.Equal(t, contentType, resp.Header().Get("Content-Type"), url)
+		assert.Contains(t, resp.Body.String(), "Paris Hub", url)
+	}
+	mockService.AssertExpectations(t)
+}
+
+// TestOrgChartHandler_Invalid tests that bad parameters and missing hubs or teams are rejected
+func TestOrgChartHandler_Invalid(t *testing.T) {
+	mockService := new(mocks.OrgChartService)
+	router := newOrgChartRouter(mockService)
+	mockService.On("HubChart", uint(9), true).Return(nil, service.ErrHubNotFound)
+	mockService.On("TeamChart", uint(9), true).Return(nil, service.ErrTeamNotFound)
+
+	for url, status := range map[string]int{
+		"/hubs/9/org-chart":                http.StatusNotFound,
+		"/teams/9/org-chart":               http.StatusNotFound,
+		"/teams/abc/org-chart":             http.StatusBadRequest,
+		"/hubs/1/org-chart?format=png":     http.StatusBadRequest,
+		"/hubs/1/org-chart?managers=maybe": http.StatusBadRequest,
+	} {
+		req, _ := http.NewRequest("GET", url, nil)
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		assert.Equal(t, status, resp.Code, url)
+	}
+}
@@ -0,0 +1,50 @@
+package handler
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"hub_management_service/internal/service"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// maxPatchBytes bounds the size of a patch document
+const maxPatchBytes = 1 << 20
+
+// patchFormats maps the content types of patch documents to their format
+var patchFormats = map[string]service.PatchFormat{
+	string(service.MergePatch): service.MergePatch,
+	string(service.JSONPatch):  service.JSONPatch,
+}
+
+// acceptPatch lists the patch formats, as sent in the Accept-Patch header
+var acceptPatch = strings.Join([]string{string(service.MergePatch), string(service.JSONPatch)}, ", ")
+
+// isPatch tells whether the body is a JSON Merge Patch or JSON Patch document
+func isPatch(c *gin.Context) bool {
+	_, ok := patchFormats[c.ContentType()]
+	return ok
+}
+
+// readPatch reads a JSON Merge Patch or JSON Patch body, writing a 415 response with Accept-Patch for other content
+// types
+func readPatch(c *gin.Context) (service.PatchFormat, []byte, bool) {
+	format, ok := patchFormats[c.ContentType()]
+	if !ok {
+		c.Header("Accept-Patch", acceptPatch)
+		c.JSON(http.StatusUnsupportedMediaType, gin.H{"error": "send " + acceptPatch})
+		return "", nil, false
+	}
+	patch, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxPatchBytes))
+	var tooLarge *http.MaxBytesError
+	switch {
+	case errors.As(err, &tooLarge):
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "the patch must not exceed 1 MiB"})
+		return "", nil, false
+	case err != nil:
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return "", nil, false
+	}
+	return format, patch, true
+}
@@ -0,0 +1,152 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"hub_management_service/internal/entity"
+	"hub_management_service/internal/service"
+	"hub_management_service/pkg/fieldset"
+	"net/http"
+	"reflect"
+)
+
+// readOptions are the fields selected with ?fields= and the relations loaded with ?expand= when reading records
+type readOptions struct {
+	fields    fieldset.Set
+	expansion service.Expansion
+}
+
+// parseReadOptions parses ?fields= against the JSON fields of record and ?expand= against the relations of kind,
+// writing a 400 response when either is invalid. defaultExpand applies when ?expand= is left out.
+func parseReadOptions(c *gin.Context, kind service.RecordKind, record interface{}, defaultExpand string) (readOptions, bool) {
+	var opts readOptions
+	var err error
+	if opts.fields, err = fieldset.Parse(c.Query("fields")); err == nil {
+		err = opts.fields.Validate(reflect.TypeOf(record))
+	}
+	if err == nil {
+		expand, ok := c.GetQuery("expand")
+		if !ok {
+			expand = defaultExpand
+		}
+		opts.expansion, err = service.ParseExpansion(kind, expand)
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return opts, false
+	}
+	return opts, true
+}
+
+// sparse keeps the selected fields of a record or a slice of records, IDs are always kept
+func (o readOptions) sparse(c *gin.Context, records interface{}) (interface{}, bool) {
+	selected, err := o.fields.Select(records, "id")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return nil, false
+	}
+	return selected, true
+}
+
+// expandHubs loads the expanded relations of hubs, writing a 500 response on failure
+func (o readOptions) expandHubs(c *gin.Context, expand service.ExpandService, hubs ...*entity.Hub) bool {
+	if len(o.expansion) == 0 {
+		return true
+	}
+	return expanded(c, expand.ExpandHubs(hubs, o.expansion))
+}
+
+// expandTeams loads the expanded relations of teams, writing a 500 response on failure
+func (o readOptions) expandTeams(c *gin.Context, expand service.ExpandService, teams ...*entity.Team) bool {
+	if len(o.expansion) == 0 {
+		return true
+	}
+	return expanded(c, expand.ExpandTeams(teams, o.expansion))
+}
+
+// expandUsers loads the expanded relations of users, writing a 500 response on failure
+func (o readOptions) expandUsers(c *gin.Context, expand service.ExpandService, users ...*entity.User) bool {
+	if len(o.expansion) == 0 {
+		return true
+	}
+	return expanded(c, expand.ExpandUsers(users, o.expansion))
+}
+
+// expandResources loads the expanded relations of desks and rooms, writing a 500 response on failure
+func (o readOptions) expandResources(c *gin.Context, expand service.ExpandService, resources ...*entity.Resource) bool {
+	if len(o.expansion) == 0 {
+		return true
+	}
+	return expanded(c, expand.ExpandResources(resources, o.expansion))
+}
+
+// expandBookings loads the expanded relations of bookings, writing a 500 response on failure
+func (o readOptions) expandBookings(c *gin.Context, expand service.ExpandService, bookings ...*entity.Booking) bool {
+	if len(o.expansion) == 0 {
+		return true
+	}
+	return expanded(c, expand.ExpandBookings(bookings, o.expansion))
+}
+
+// expandRegions loads the expanded relations of regions, writing a 500 response on failure
+func (o readOptions) expandRegions(c *gin.Context, expand service.ExpandService, regions ...*entity.Region) bool {
+	if len(o.expansion) == 0 {
+		return true
+	}
+	return expanded(c, expand.ExpandRegions(regions, o.expansion))
+}
+
+func expanded(c *gin.Context, err error) bool {
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return false
+	}
+	return true
+}
+
+func hubRefs(hubs []entity.Hub) []*entity.Hub {
+	refs := make([]*entity.Hub, len(hubs))
+	for i := range hubs {
+		refs[i] = &hubs[i]
+	}
+	return refs
+}
+
+func teamRefs(teams []entity.Team) []*entity.Team {
+	refs := make([]*entity.Team, len(teams))
+	for i := range teams {
+		refs[i] = &teams[i]
+	}
+	return refs
+}
+
+func userRefs(users []entity.User) []*entity.User {
+	refs := make([]*entity.User, len(users))
+	for i := range users {
+		refs[i] = &users[i]
+	}
+	return refs
+}
+
+func resourceRefs(resources []entity.Resource) []*entity.Resource {
+	refs := make([]*entity.Resource, len(resources))
+	for i := range resources {
+		refs[i] = &resources[i]
+	}
+	return refs
+}
+
+func bookingRefs(bookings []entity.Booking) []*entity.Booking {
+	refs := make([]*entity.Booking, len(bookings))
+	for i := range bookings {
+		refs[i] = &bookings[i]
+	}
+	return refs
+}
+
+func regionRefs(regions []entity.Region) []*entity.Region {
+	refs := make([]*entity.Region, len(regions))
+	for i := range regions {
+		refs[i] = &regions[i]
+	}
+	return refs
+}
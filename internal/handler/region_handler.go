@@ -0,0 +1,226 @@
+package handler
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"hub_management_service/internal/entity"
+	"hub_management_service/internal/service"
+	"net/http"
+	"strconv"
+)
+
+type RegionHandler struct {
+	service service.RegionService
+	expand  service.ExpandService
+}
+
+func NewRegionHandler(service service.RegionService, expand service.ExpandService) *RegionHandler {
+	return &RegionHandler{service: service, expand: expand}
+}
+
+// ListRegions - Handler for listing all regions, with the fields and relations of ?fields= and ?expand=
+func (h *RegionHandler) ListRegions(c *gin.Context) {
+	opts, ok := parseReadOptions(c, service.RegionRecord, entity.Region{}, "")
+	if !ok {
+		return
+	}
+	regions, err := h.service.ListRegions()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if !opts.expandRegions(c, h.expand, regionRefs(regions)...) {
+		return
+	}
+	records, ok := opts.sparse(c, regions)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"regions": records})
+}
+
+// FindRegion - Handler for finding a region along with its countries unless ?expand= says otherwise, with the fields
+// of ?fields=
+func (h *RegionHandler) FindRegion(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid Region ID"})
+		return
+	}
+	opts, ok := parseReadOptions(c, service.RegionRecord, entity.Region{}, "countries")
+	if !ok {
+		return
+	}
+
+	region, err := h.service.FindRegion(uint(id))
+	if err != nil {
+		writeRegionError(c, err)
+		return
+	}
+	// The region is found along with its countries, which only stay when expanded
+	if _, ok := opts.expansion["countries"]; !ok {
+		region.Countries = nil
+	}
+	record, ok := opts.sparse(c, region)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"region": record})
+}
+
+// CreateRegion - Handler for creating a region
+func (h *RegionHandler) CreateRegion(c *gin.Context) {
+	var region entity.Region
+	if err := c.ShouldBindJSON(&region); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.service.CreateRegion(&region); err != nil {
+		writeRegionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"message": "Region created successfully", "region": region})
+}
+
+// UpdateRegion - Handler for renaming a region
+func (h *RegionHandler) UpdateRegion(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid Region ID"})
+		return
+	}
+	var update entity.Region
+	if err := c.ShouldBindJSON(&update); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	region, err := h.service.UpdateRegion(uint(id), &update)
+	if err != nil {
+		writeRegionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Region updated successfully", "region": region})
+}
+
+// DeleteRegion - Handler for deleting a region without countries
+func (h *RegionHandler) DeleteRegion(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid Region ID"})
+		return
+	}
+
+	if err := h.service.DeleteRegion(uint(id)); err != nil {
+		writeRegionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Region deleted successfully"})
+}
+
+// Rollup - Handler for counting the countries, hubs, teams and users of every region
+func (h *RegionHandler) Rollup(c *gin.Context) {
+	rollups, err := h.service.Rollup()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"regions": rollups})
+}
+
+// ListCountries - Handler for listing countries, optionally of a single region with ?region_id=
+func (h *RegionHandler) ListCountries(c *gin.Context) {
+	var regionID int
+	if value, ok := c.GetQuery("region_id"); ok {
+		var err error
+		if regionID, err = strconv.Atoi(value); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid Region ID"})
+			return
+		}
+	}
+
+	countries, err := h.service.ListCountries(uint(regionID))
+	if err != nil {
+		writeRegionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"countries": countries})
+}
+
+// CreateCountry - Handler for adding a country to a region
+func (h *RegionHandler) CreateCountry(c *gin.Context) {
+	var country entity.Country
+	if err := c.ShouldBindJSON(&country); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.service.CreateCountry(&country); err != nil {
+		writeRegionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"message": "Country created successfully", "country": country})
+}
+
+// UpdateCountry - Handler for renaming a country or moving it to another region
+func (h *RegionHandler) UpdateCountry(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid Country ID"})
+		return
+	}
+	var update entity.Country
+	if err := c.ShouldBindJSON(&update); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	country, err := h.service.UpdateCountry(uint(id), &update)
+	if err != nil {
+		writeRegionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Country updated successfully", "country": country})
+}
+
+// DeleteCountry - Handler for deleting a country without hubs
+func (h *RegionHandler) DeleteCountry(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid Country ID"})
+		return
+	}
+
+	if err := h.service.DeleteCountry(uint(id)); err != nil {
+		writeRegionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Country deleted successfully"})
+}
+
+func writeRegionError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrRegionNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"message": "Region not found"})
+	case errors.Is(err, service.ErrCountryNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"message": "Country not found"})
+	case errors.Is(err, service.ErrInvalidCountryCode), errors.Is(err, service.ErrCountryRegionNotFound):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrRegionNameTaken), errors.Is(err, service.ErrCountryCodeTaken),
+		errors.Is(err, service.ErrRegionInUse), errors.Is(err, service.ErrCountryInUse):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,99 @@
+package handler
+
+import (
+	"bytes"
+	"hub_management_service/internal/entity"
+	"hub_management_service/internal/repository"
+	"hub_management_service/internal/service"
+	"hub_management_service/internal/service/mocks"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newRegionRouter(mockService *mocks.RegionService) *gin.Engine {
+	handler := NewRegionHandler(mockService, new(mocks.ExpandService))
+	router := gin.Default()
+	router.GET("/regions/rollup", handler.Rollup)
+	router.GET("/regions/:id", handler.FindRegion)
+	router.POST("/regions", handler.CreateRegion)
+	router.DELETE("/regions/:id", handler.DeleteRegion)
+	router.POST("/countries", handler.CreateCountry)
+	return router
+}
+
+// TestCreateRegionHandler tests that a created region returns 201
+func TestCreateRegionHandler(t *testing.T) {
+	mockService := new(mocks.RegionService)
+	router := newRegionRouter(mockService)
+
+	mockService.On("CreateRegion", mock.MatchedBy(func(r *entity.Region) bool { return r.Name == "EMEA" })).Return(nil)
+
+	req, _ := http.NewRequest("POST", "/regions", bytes.NewBufferString(`{"name": "EMEA"}`))
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusCreated, resp.Code)
+	mockService.AssertExpectations(t)
+}
+
+// TestFindRegionHandler_NotFound tests that a missing region returns 404
+func TestFindRegionHandler_NotFound(t *testing.T) {
+	mockService := new(mocks.RegionService)
+	router := newRegionRouter(mockService)
+
+	mockService.On("FindRegion", uint(9)).Return(nil, service.ErrRegionNotFound)
+
+	req, _ := http.NewRequest("GET", "/regions/9", nil)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+}
+
+// TestDeleteRegionHandler_InUse tests that deleting a region with countries returns 409
+func TestDeleteRegionHandler_InUse(t *testing.T) {
+	mockService := new(mocks.RegionService)
+	router := newRegionRouter(mockService)
+
+	mockService.On("DeleteRegion", uint(1)).Return(service.ErrRegionInUse)
+
+	req, _ := http.NewRequest("DELETE", "/regions/1", nil)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusConflict, resp.Code)
+}
+
+// TestCreateCountryHandler_RegionNotFound tests that an unknown region in the body returns 400
+func TestCreateCountryHandler_RegionNotFound(t *testing.T) {
+	mockService := new(mocks.RegionService)
+	router := newRegionRouter(mockService)
+
+	mockService.On("CreateCountry", mock.AnythingOfType("*entity.Country")).Return(service.ErrCountryRegionNotFound)
+
+	req, _ := http.NewRequest("POST", "/countries", bytes.NewBufferString(`{"code": "FR", "name": "France", "region_id": 9}`))
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+}
+
+// TestRollupHandler tests that the counts of every region are returned
+func TestRollupHandler(t *testing.T) {
+	mockService := new(mocks.RegionService)
+	router := newRegionRouter(mockService)
+
+	mockService.On("Rollup").Return([]repository.RegionRollup{{RegionID: 1, Name: "EMEA", Countries: 2, Hubs: 2, Teams: 3, Users: 10}}, nil)
+
+	req, _ := http.NewRequest("GET", "/regions/rollup", nil)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Contains(t, resp.Body.String(), `"users":10`)
+}
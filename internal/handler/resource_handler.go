@@ -0,0 +1,184 @@
+package handler
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"hub_management_service/internal/entity"
+	"hub_management_service/internal/service"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+type ResourceHandler struct {
+	service service.ResourceService
+	expand  service.ExpandService
+}
+
+func NewResourceHandler(service service.ResourceService, expand service.ExpandService) *ResourceHandler {
+	return &ResourceHandler{service: service, expand: expand}
+}
+
+// ListResources - Handler for listing the desks and rooms of a hub, filtered by type, floor, amenity and min_capacity,
+// with the fields and relations of ?fields= and ?expand=
+func (h *ResourceHandler) ListResources(c *gin.Context) {
+	hubID, ok := parseHubID(c)
+	if !ok {
+		return
+	}
+
+	query, ok := parseResourceQuery(c)
+	if !ok {
+		return
+	}
+	opts, ok := parseReadOptions(c, service.ResourceRecord, entity.Resource{}, "")
+	if !ok {
+		return
+	}
+
+	resources, total, err := h.service.ListResources(hubID, query)
+	if err != nil {
+		writeResourceError(c, err)
+		return
+	}
+	if !opts.expandResources(c, h.expand, resourceRefs(resources)...) {
+		return
+	}
+	records, ok := opts.sparse(c, resources)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"resources": records, "total": total})
+}
+
+// CreateResource - Handler for adding a desk or room to a hub
+func (h *ResourceHandler) CreateResource(c *gin.Context) {
+	hubID, ok := parseHubID(c)
+	if !ok {
+		return
+	}
+	var resource entity.Resource
+	if err := c.ShouldBindJSON(&resource); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.service.CreateResource(hubID, &resource); err != nil {
+		writeResourceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"message": "Resource created successfully", "resource": resource})
+}
+
+// FindResource - Handler for retrieving a resource by its ID, with the fields and relations of ?fields= and ?expand=
+func (h *ResourceHandler) FindResource(c *gin.Context) {
+	id, ok := parseResourceID(c)
+	if !ok {
+		return
+	}
+	opts, ok := parseReadOptions(c, service.ResourceRecord, entity.Resource{}, "")
+	if !ok {
+		return
+	}
+
+	resource, err := h.service.FindResource(id)
+	if err != nil {
+		writeResourceError(c, err)
+		return
+	}
+	if !opts.expandResources(c, h.expand, resource) {
+		return
+	}
+	record, ok := opts.sparse(c, resource)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"resource": record})
+}
+
+// UpdateResource - Handler for replacing the fields and amenities of a resource
+func (h *ResourceHandler) UpdateResource(c *gin.Context) {
+	id, ok := parseResourceID(c)
+	if !ok {
+		return
+	}
+	var update entity.Resource
+	if err := c.ShouldBindJSON(&update); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	resource, err := h.service.UpdateResource(id, &update)
+	if err != nil {
+		writeResourceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Resource updated successfully", "resource": resource})
+}
+
+// DeleteResource - Handler for removing a resource from its hub
+func (h *ResourceHandler) DeleteResource(c *gin.Context) {
+	id, ok := parseResourceID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.DeleteResource(id); err != nil {
+		writeResourceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Resource deleted successfully"})
+}
+
+// parseResourceQuery reads the resource filters and paging, writing a 400 response if a number is invalid
+func parseResourceQuery(c *gin.Context) (service.ResourceQuery, bool) {
+	query := service.ResourceQuery{Type: c.Query("type"), Floor: c.Query("floor")}
+	// Amenities may be repeated or comma-separated, resources must have all of them
+	for _, value := range c.QueryArray("amenity") {
+		for _, amenity := range strings.Split(value, ",") {
+			if amenity = strings.TrimSpace(amenity); amenity != "" {
+				query.Amenities = append(query.Amenities, amenity)
+			}
+		}
+	}
+	for param, target := range map[string]*int{"min_capacity": &query.MinCapacity, "offset": &query.Offset, "limit": &query.Limit} {
+		if value, ok := c.GetQuery(param); ok {
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 0 {
+				c.JSON(http.StatusBadRequest, gin.H{"error": param + " must be a non-negative integer"})
+				return query, false
+			}
+			*target = n
+		}
+	}
+	return query, true
+}
+
+func parseResourceID(c *gin.Context) (uint, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid Resource ID"})
+		return 0, false
+	}
+	return uint(id), true
+}
+
+func writeResourceError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrHubNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"message": "Hub not found"})
+	case errors.Is(err, service.ErrResourceNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"message": "Resource not found"})
+	case errors.Is(err, service.ErrInvalidResourceType), errors.Is(err, service.ErrInvalidAmenity):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrResourceNameTaken):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,85 @@
+package handler
+
+import (
+	"bytes"
+	"hub_management_service/internal/entity"
+	"hub_management_service/internal/service"
+	"hub_management_service/internal/service/mocks"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newResourceRouter(mockService *mocks.ResourceService) *gin.Engine {
+	handler := NewResourceHandler(mockService, new(mocks.ExpandService))
+	router := gin.Default()
+	router.GET("/hubs/:id/resources", handler.ListResources)
+	router.POST("/hubs/:id/resources", handler.CreateResource)
+	router.DELETE("/resources/:id", handler.DeleteResource)
+	return router
+}
+
+// TestListResourcesHandler tests that repeated and comma-separated amenities are combined
+func TestListResourcesHandler(t *testing.T) {
+	mockService := new(mocks.ResourceService)
+	router := newResourceRouter(mockService)
+	seats := 8
+
+	mockService.On("ListResources", uint(1), service.ResourceQuery{Type: "room", Amenities: []string{"monitor", "video", "whiteboard"}, MinCapacity: 4}).
+		Return([]entity.Resource{{ID: 2, HubID: 1, Type: "room", Name: "Seine", Capacity: &seats, Amenities: []entity.Amenity{{Name: "monitor"}}}}, int64(1), nil)
+
+	req, _ := http.NewRequest("GET", "/hubs/1/resources?type=room&amenity=monitor,video&amenity=whiteboard&min_capacity=4", nil)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Contains(t, resp.Body.String(), `"amenities":["monitor"]`)
+	mockService.AssertExpectations(t)
+}
+
+// TestCreateResourceHandler tests that amenities are decoded from strings and conflicts return 409
+func TestCreateResourceHandler(t *testing.T) {
+	mockService := new(mocks.ResourceService)
+	router := newResourceRouter(mockService)
+
+	mockService.On("CreateResource", uint(1), mock.MatchedBy(func(r *entity.Resource) bool {
+		return r.Name == "Seine" && len(r.Amenities) == 1 && r.Amenities[0].Name == "video"
+	})).Return(nil).Once()
+	mockService.On("CreateResource", uint(1), mock.Anything).Return(service.ErrResourceNameTaken)
+
+	body := `{"type": "room", "name": "Seine", "capacity": 8, "amenities": ["video"]}`
+	req, _ := http.NewRequest("POST", "/hubs/1/resources", bytes.NewBufferString(body))
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusCreated, resp.Code)
+
+	req, _ = http.NewRequest("POST", "/hubs/1/resources", bytes.NewBufferString(body))
+	resp = httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusConflict, resp.Code)
+
+	// Unknown types are rejected before reaching the service
+	req, _ = http.NewRequest("POST", "/hubs/1/resources", bytes.NewBufferString(`{"type": "locker", "name": "L-1"}`))
+	resp = httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+	mockService.AssertNumberOfCalls(t, "CreateResource", 2)
+}
+
+// TestDeleteResourceHandler_NotFound tests that deleting an unknown resource returns 404
+func TestDeleteResourceHandler_NotFound(t *testing.T) {
+	mockService := new(mocks.ResourceService)
+	router := newResourceRouter(mockService)
+
+	mockService.On("DeleteResource", uint(3)).Return(service.ErrResourceNotFound)
+
+	req, _ := http.NewRequest("DELETE", "/resources/3", nil)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+}
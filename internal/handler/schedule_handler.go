@@ -0,0 +1,189 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"hub_management_service/internal/entity"
+	"hub_management_service/internal/service"
+	"hub_management_service/pkg/ical"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+type ScheduleHandler struct {
+	service service.ScheduleService
+}
+
+func NewScheduleHandler(service service.ScheduleService) *ScheduleHandler {
+	return &ScheduleHandler{service: service}
+}
+
+// FindOpeningHours - Handler for listing the weekly opening hours of a hub
+func (h *ScheduleHandler) FindOpeningHours(c *gin.Context) {
+	hubID, ok := parseHubID(c)
+	if !ok {
+		return
+	}
+
+	hours, err := h.service.FindOpeningHours(hubID)
+	if err != nil {
+		writeScheduleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"hours": hours})
+}
+
+// SetOpeningHours - Handler for replacing the weekly opening hours of a hub
+func (h *ScheduleHandler) SetOpeningHours(c *gin.Context) {
+	hubID, ok := parseHubID(c)
+	if !ok {
+		return
+	}
+	var req struct {
+		Hours []entity.OpeningHours `json:"hours" binding:"dive"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	hours, err := h.service.SetOpeningHours(hubID, req.Hours)
+	if err != nil {
+		writeScheduleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Opening hours updated successfully", "hours": hours})
+}
+
+// ListHolidays - Handler for listing the holidays of a hub, optionally of a single year with ?year=
+func (h *ScheduleHandler) ListHolidays(c *gin.Context) {
+	hubID, ok := parseHubID(c)
+	if !ok {
+		return
+	}
+	var year int
+	if value, ok := c.GetQuery("year"); ok {
+		var err error
+		if year, err = strconv.Atoi(value); err != nil || year < 1 || year > 9999 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "year must be between 1 and 9999"})
+			return
+		}
+	}
+
+	holidays, err := h.service.ListHolidays(hubID, year)
+	if err != nil {
+		writeScheduleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"holidays": holidays})
+}
+
+// AddHoliday - Handler for closing a hub for a day
+func (h *ScheduleHandler) AddHoliday(c *gin.Context) {
+	hubID, ok := parseHubID(c)
+	if !ok {
+		return
+	}
+	var holiday entity.Holiday
+	if err := c.ShouldBindJSON(&holiday); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.service.AddHoliday(hubID, &holiday); err != nil {
+		writeScheduleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"message": "Holiday added successfully", "holiday": holiday})
+}
+
+// RemoveHoliday - Handler for deleting a holiday of a hub
+func (h *ScheduleHandler) RemoveHoliday(c *gin.Context) {
+	hubID, ok := parseHubID(c)
+	if !ok {
+		return
+	}
+	id, err := strconv.Atoi(c.Param("holiday_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid Holiday ID"})
+		return
+	}
+
+	if err := h.service.RemoveHoliday(hubID, uint(id)); err != nil {
+		writeScheduleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Holiday removed successfully"})
+}
+
+// ExportHolidays - Handler for downloading the holidays of a hub as an iCalendar file
+func (h *ScheduleHandler) ExportHolidays(c *gin.Context) {
+	hubID, ok := parseHubID(c)
+	if !ok {
+		return
+	}
+
+	calendar, err := h.service.ExportHolidays(hubID)
+	if err != nil {
+		writeScheduleError(c, err)
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="hub-%d-holidays.ics"`, hubID))
+	c.Data(http.StatusOK, ical.ContentType, calendar)
+}
+
+// IsOpen - Handler for telling whether a hub is open at the RFC 3339 instant ?at=, now by default
+func (h *ScheduleHandler) IsOpen(c *gin.Context) {
+	hubID, ok := parseHubID(c)
+	if !ok {
+		return
+	}
+	at := time.Now()
+	if value, ok := c.GetQuery("at"); ok {
+		var err error
+		if at, err = time.Parse(time.RFC3339, value); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "at must be an RFC 3339 timestamp, e.g. 2024-07-14T09:30:00Z"})
+			return
+		}
+	}
+
+	status, err := h.service.IsOpen(hubID, at)
+	if err != nil {
+		writeScheduleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, status)
+}
+
+func parseHubID(c *gin.Context) (uint, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+		return 0, false
+	}
+	return uint(id), true
+}
+
+func writeScheduleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrHubNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"message": "Hub not found"})
+	case errors.Is(err, service.ErrHolidayNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"message": "Holiday not found"})
+	case errors.Is(err, service.ErrInvalidOpeningHours), errors.Is(err, service.ErrInvalidHolidayDate):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrHolidayExists):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
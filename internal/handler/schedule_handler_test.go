@@ -0,0 +1,100 @@
+package handler
+
+import (
+	"bytes"
+	"hub_management_service/internal/service"
+	"hub_management_service/internal/service/mocks"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newScheduleRouter(mockService *mocks.ScheduleService) *gin.Engine {
+	handler := NewScheduleHandler(mockService)
+	router := gin.Default()
+	router.GET("/hubs/:id/open", handler.IsOpen)
+	router.PUT("/hubs/:id/hours", handler.SetOpeningHours)
+	router.GET("/hubs/:id/holidays.ics", handler.ExportHolidays)
+	router.POST("/hubs/:id/holidays", handler.AddHoliday)
+	return router
+}
+
+// TestIsOpenHandler tests that the instant is parsed from ?at=
+func TestIsOpenHandler(t *testing.T) {
+	mockService := new(mocks.ScheduleService)
+	router := newScheduleRouter(mockService)
+
+	at := time.Date(2024, time.July, 15, 9, 30, 0, 0, time.UTC)
+	mockService.On("IsOpen", uint(1), mock.MatchedBy(func(t time.Time) bool { return t.Equal(at) })).
+		Return(&service.OpenStatus{HubID: 1, Open: true, At: at, TimeZone: "UTC"}, nil)
+
+	req, _ := http.NewRequest("GET", "/hubs/1/open?at=2024-07-15T09:30:00Z", nil)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Contains(t, resp.Body.String(), `"open":true`)
+	mockService.AssertExpectations(t)
+}
+
+// TestIsOpenHandler_InvalidInstant tests that ?at= must be RFC 3339
+func TestIsOpenHandler_InvalidInstant(t *testing.T) {
+	mockService := new(mocks.ScheduleService)
+	router := newScheduleRouter(mockService)
+
+	req, _ := http.NewRequest("GET", "/hubs/1/open?at=tomorrow", nil)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+}
+
+// TestSetOpeningHoursHandler_Invalid tests that invalid hours return 400
+func TestSetOpeningHoursHandler_Invalid(t *testing.T) {
+	mockService := new(mocks.ScheduleService)
+	router := newScheduleRouter(mockService)
+
+	mockService.On("SetOpeningHours", uint(1), mock.Anything).Return(nil, service.ErrInvalidOpeningHours)
+
+	body := `{"hours": [{"weekday": 1, "opens": "18:00", "closes": "09:00"}]}`
+	req, _ := http.NewRequest("PUT", "/hubs/1/hours", bytes.NewBufferString(body))
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+}
+
+// TestAddHolidayHandler_Exists tests that a second holiday on the same date returns 409
+func TestAddHolidayHandler_Exists(t *testing.T) {
+	mockService := new(mocks.ScheduleService)
+	router := newScheduleRouter(mockService)
+
+	mockService.On("AddHoliday", uint(1), mock.AnythingOfType("*entity.Holiday")).Return(service.ErrHolidayExists)
+
+	req, _ := http.NewRequest("POST", "/hubs/1/holidays", bytes.NewBufferString(`{"date": "2024-12-25", "name": "Christmas"}`))
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusConflict, resp.Code)
+}
+
+// TestExportHolidaysHandler tests that the calendar is served as a download
+func TestExportHolidaysHandler(t *testing.T) {
+	mockService := new(mocks.ScheduleService)
+	router := newScheduleRouter(mockService)
+
+	mockService.On("ExportHolidays", uint(1)).Return([]byte("BEGIN:VCALENDAR\r\nEND:VCALENDAR\r\n"), nil)
+
+	req, _ := http.NewRequest("GET", "/hubs/1/holidays.ics", nil)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, "text/calendar; charset=utf-8", resp.Header().Get("Content-Type"))
+	assert.Contains(t, resp.Header().Get("Content-Disposition"), "hub-1-holidays.ics")
+}
@@ -0,0 +1,292 @@
+package handler
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"hub_management_service/internal/service"
+	"hub_management_service/pkg/scim"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+type SCIMHandler struct {
+	service service.SCIMService
+}
+
+func NewSCIMHandler(service service.SCIMService) *SCIMHandler {
+	return &SCIMHandler{service: service}
+}
+
+// ServiceProviderConfig describes the SCIM features supported by the service
+func (h *SCIMHandler) ServiceProviderConfig(c *gin.Context) {
+	writeSCIM(c, http.StatusOK, gin.H{
+		"schemas":        []string{"urn:ietf:params:scim:schemas:core:2.0:ServiceProviderConfig"},
+		"patch":          gin.H{"supported": true},
+		"bulk":           gin.H{"supported": false, "maxOperations": 0, "maxPayloadSize": 0},
+		"filter":         gin.H{"supported": true, "maxResults": 200},
+		"changePassword": gin.H{"supported": false},
+		"sort":           gin.H{"supported": false},
+		"etag":           gin.H{"supported": true},
+		"authenticationSchemes": []gin.H{{
+			"type":        "oauthbearertoken",
+			"name":        "Bearer Token",
+			"description": "JWT token issued by /login",
+		}},
+	})
+}
+
+// ListUsers - Handler for GET /scim/v2/Users
+func (h *SCIMHandler) ListUsers(c *gin.Context) {
+	startIndex, count, ok := scimPaging(c)
+	if !ok {
+		return
+	}
+
+	list, err := h.service.ListUsers(c.Query("filter"), startIndex, count)
+	if err != nil {
+		writeSCIMError(c, err)
+		return
+	}
+	writeSCIM(c, http.StatusOK, list)
+}
+
+// GetUser - Handler for GET /scim/v2/Users/:id
+func (h *SCIMHandler) GetUser(c *gin.Context) {
+	user, err := h.service.GetUser(c.Param("id"))
+	if err != nil {
+		writeSCIMError(c, err)
+		return
+	}
+	writeSCIMResource(c, http.StatusOK, user.Meta, user)
+}
+
+// CreateUser - Handler for POST /scim/v2/Users
+func (h *SCIMHandler) CreateUser(c *gin.Context) {
+	var resource scim.User
+	if err := c.ShouldBindJSON(&resource); err != nil {
+		writeSCIMError(c, scim.NewError(http.StatusBadRequest, "invalidSyntax", "%s", err.Error()))
+		return
+	}
+
+	user, err := h.service.CreateUser(&resource)
+	if err != nil {
+		writeSCIMError(c, err)
+		return
+	}
+	c.Header("Location", user.Meta.Location)
+	writeSCIMResource(c, http.StatusCreated, user.Meta, user)
+}
+
+// ReplaceUser - Handler for PUT /scim/v2/Users/:id
+func (h *SCIMHandler) ReplaceUser(c *gin.Context) {
+	var resource scim.User
+	if err := c.ShouldBindJSON(&resource); err != nil {
+		writeSCIMError(c, scim.NewError(http.StatusBadRequest, "invalidSyntax", "%s", err.Error()))
+		return
+	}
+	version, ok := scimIfMatch(c)
+	if !ok {
+		return
+	}
+
+	user, err := h.service.ReplaceUser(c.Param("id"), version, &resource)
+	if err != nil {
+		writeSCIMError(c, err)
+		return
+	}
+	writeSCIMResource(c, http.StatusOK, user.Meta, user)
+}
+
+// PatchUser - Handler for PATCH /scim/v2/Users/:id
+func (h *SCIMHandler) PatchUser(c *gin.Context) {
+	var req scim.PatchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeSCIMError(c, scim.NewError(http.StatusBadRequest, "invalidSyntax", "%s", err.Error()))
+		return
+	}
+	version, ok := scimIfMatch(c)
+	if !ok {
+		return
+	}
+
+	user, err := h.service.PatchUser(c.Param("id"), version, req.Operations)
+	if err != nil {
+		writeSCIMError(c, err)
+		return
+	}
+	writeSCIMResource(c, http.StatusOK, user.Meta, user)
+}
+
+// DeleteUser - Handler for DELETE /scim/v2/Users/:id
+func (h *SCIMHandler) DeleteUser(c *gin.Context) {
+	version, ok := scimIfMatch(c)
+	if !ok {
+		return
+	}
+	if err := h.service.DeleteUser(c.Param("id"), version); err != nil {
+		writeSCIMError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// ListGroups - Handler for GET /scim/v2/Groups
+func (h *SCIMHandler) ListGroups(c *gin.Context) {
+	startIndex, count, ok := scimPaging(c)
+	if !ok {
+		return
+	}
+	excludeMembers := strings.Contains(strings.ToLower(c.Query("excludedAttributes")), "members")
+
+	list, err := h.service.ListGroups(c.Query("filter"), startIndex, count, excludeMembers)
+	if err != nil {
+		writeSCIMError(c, err)
+		return
+	}
+	writeSCIM(c, http.StatusOK, list)
+}
+
+// GetGroup - Handler for GET /scim/v2/Groups/:id
+func (h *SCIMHandler) GetGroup(c *gin.Context) {
+	group, err := h.service.GetGroup(c.Param("id"))
+	if err != nil {
+		writeSCIMError(c, err)
+		return
+	}
+	writeSCIMResource(c, http.StatusOK, group.Meta, group)
+}
+
+// CreateGroup - Handler for POST /scim/v2/Groups
+func (h *SCIMHandler) CreateGroup(c *gin.Context) {
+	var resource scim.Group
+	if err := c.ShouldBindJSON(&resource); err != nil {
+		writeSCIMError(c, scim.NewError(http.StatusBadRequest, "invalidSyntax", "%s", err.Error()))
+		return
+	}
+
+	group, err := h.service.CreateGroup(&resource)
+	if err != nil {
+		writeSCIMError(c, err)
+		return
+	}
+	c.Header("Location", group.Meta.Location)
+	writeSCIMResource(c, http.StatusCreated, group.Meta, group)
+}
+
+// ReplaceGroup - Handler for PUT /scim/v2/Groups/:id
+func (h *SCIMHandler) ReplaceGroup(c *gin.Context) {
+	var resource scim.Group
+	if err := c.ShouldBindJSON(&resource); err != nil {
+		writeSCIMError(c, scim.NewError(http.StatusBadRequest, "invalidSyntax", "%s", err.Error()))
+		return
+	}
+	version, ok := scimIfMatch(c)
+	if !ok {
+		return
+	}
+
+	group, err := h.service.ReplaceGroup(c.Param("id"), version, &resource)
+	if err != nil {
+		writeSCIMError(c, err)
+		return
+	}
+	writeSCIMResource(c, http.StatusOK, group.Meta, group)
+}
+
+// PatchGroup - Handler for PATCH /scim/v2/Groups/:id
+func (h *SCIMHandler) PatchGroup(c *gin.Context) {
+	var req scim.PatchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeSCIMError(c, scim.NewError(http.StatusBadRequest, "invalidSyntax", "%s", err.Error()))
+		return
+	}
+	version, ok := scimIfMatch(c)
+	if !ok {
+		return
+	}
+
+	group, err := h.service.PatchGroup(c.Param("id"), version, req.Operations)
+	if err != nil {
+		writeSCIMError(c, err)
+		return
+	}
+	writeSCIMResource(c, http.StatusOK, group.Meta, group)
+}
+
+// DeleteGroup - Handler for DELETE /scim/v2/Groups/:id
+func (h *SCIMHandler) DeleteGroup(c *gin.Context) {
+	version, ok := scimIfMatch(c)
+	if !ok {
+		return
+	}
+	if err := h.service.DeleteGroup(c.Param("id"), version); err != nil {
+		writeSCIMError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// scimIfMatch reads the version the request expects from If-Match, AnyVersion when it is missing or "*". The service
+// checks it inside the transaction of the write.
+func scimIfMatch(c *gin.Context) (uint, bool) {
+	ifMatch := strings.TrimSpace(c.GetHeader("If-Match"))
+	if ifMatch == "" || ifMatch == "*" {
+		return service.AnyVersion, true
+	}
+	version, ok := service.ParseSCIMVersion(ifMatch)
+	if !ok {
+		writeSCIMError(c, scim.NewError(http.StatusPreconditionFailed, "", "If-Match must be a single version of the resource"))
+		return 0, false
+	}
+	return version, true
+}
+
+// etagListContains compares a comma separated list of entity tags using weak comparison
+func etagListContains(header, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		if strings.TrimPrefix(strings.TrimSpace(candidate), "W/") == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+	return false
+}
+
+// scimPaging parses startIndex and count, a missing count selects the default page size
+func scimPaging(c *gin.Context) (int, int, bool) {
+	startIndex, err := strconv.Atoi(c.DefaultQuery("startIndex", "1"))
+	if err != nil {
+		writeSCIMError(c, scim.NewError(http.StatusBadRequest, "invalidValue", "startIndex must be an integer"))
+		return 0, 0, false
+	}
+	count, err := strconv.Atoi(c.DefaultQuery("count", "-1"))
+	if err != nil {
+		writeSCIMError(c, scim.NewError(http.StatusBadRequest, "invalidValue", "count must be an integer"))
+		return 0, 0, false
+	}
+	return startIndex, count, true
+}
+
+// writeSCIMResource writes a single resource with its ETag, answering 304 when If-None-Match matches
+func writeSCIMResource(c *gin.Context, status int, meta *scim.Meta, resource interface{}) {
+	c.Header("ETag", meta.Version)
+	if status == http.StatusOK && etagListContains(c.GetHeader("If-None-Match"), meta.Version) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+	writeSCIM(c, status, resource)
+}
+
+func writeSCIM(c *gin.Context, status int, body interface{}) {
+	c.Header("Content-Type", scim.ContentType)
+	c.JSON(status, body)
+}
+
+func writeSCIMError(c *gin.Context, err error) {
+	var scimErr *scim.Error
+	if errors.As(err, &scimErr) {
+		writeSCIM(c, scimErr.StatusCode(), scimErr)
+		return
+	}
+	writeSCIM(c, http.StatusInternalServerError, scim.NewError(http.StatusInternalServerError, "", "%s", err.Error()))
+}
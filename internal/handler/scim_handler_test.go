@@ -0,0 +1,148 @@
+package handler
+
+import (
+	"bytes"
+	"hub_management_service/internal/service/mocks"
+	"hub_management_service/pkg/scim"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newSCIMRouter(mockService *mocks.SCIMService) *gin.Engine {
+	handler := NewSCIMHandler(mockService)
+	router := gin.Default()
+	router.GET("/scim/v2/Users", handler.ListUsers)
+	router.GET("/scim/v2/Users/:id", handler.GetUser)
+	router.POST("/scim/v2/Users", handler.CreateUser)
+	router.PATCH("/scim/v2/Users/:id", handler.PatchUser)
+	router.DELETE("/scim/v2/Groups/:id", handler.DeleteGroup)
+	return router
+}
+
+func testSCIMUser() *scim.User {
+	return &scim.User{
+		Schemas:  []string{scim.UserSchema},
+		ID:       "3",
+		UserName: "jane@example.com",
+		Meta:     &scim.Meta{ResourceType: "User", Location: "/scim/v2/Users/3", Version: `W/"abc"`},
+	}
+}
+
+// TestSCIMListUsersHandler tests that query parameters are passed to the service
+func TestSCIMListUsersHandler(t *testing.T) {
+	mockService := new(mocks.SCIMService)
+	router := newSCIMRouter(mockService)
+
+	mockService.On("ListUsers", `userName eq "jane@example.com"`, 1, -1).Return(&scim.ListResponse{
+		Schemas:      []string{scim.ListResponseSchema},
+		TotalResults: 1,
+		StartIndex:   1,
+		ItemsPerPage: 1,
+		Resources:    []*scim.User{testSCIMUser()},
+	}, nil)
+
+	req, _ := http.NewRequest("GET", `/scim/v2/Users?filter=userName%20eq%20%22jane%40example.com%22`, nil)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, scim.ContentType, resp.Header().Get("Content-Type"))
+	assert.Contains(t, resp.Body.String(), `"totalResults":1`)
+	mockService.AssertExpectations(t)
+}
+
+// TestSCIMGetUserHandler_NotModified tests that a matching If-None-Match returns 304
+func TestSCIMGetUserHandler_NotModified(t *testing.T) {
+	mockService := new(mocks.SCIMService)
+	router := newSCIMRouter(mockService)
+
+	mockService.On("GetUser", "3").Return(testSCIMUser(), nil)
+
+	req, _ := http.NewRequest("GET", "/scim/v2/Users/3", nil)
+	req.Header.Set("If-None-Match", `W/"abc"`)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusNotModified, resp.Code)
+	assert.Equal(t, `W/"abc"`, resp.Header().Get("ETag"))
+}
+
+// TestSCIMCreateUserHandler tests that a created user returns 201 with its location
+func TestSCIMCreateUserHandler(t *testing.T) {
+	mockService := new(mocks.SCIMService)
+	router := newSCIMRouter(mockService)
+
+	mockService.On("CreateUser", mock.MatchedBy(func(u *scim.User) bool { return u.UserName == "jane@example.com" })).Return(testSCIMUser(), nil)
+
+	body := []byte(`{"schemas":["urn:ietf:params:scim:schemas:core:2.0:User"],"userName":"jane@example.com"}`)
+	req, _ := http.NewRequest("POST", "/scim/v2/Users", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", scim.ContentType)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusCreated, resp.Code)
+	assert.Equal(t, "/scim/v2/Users/3", resp.Header().Get("Location"))
+	mockService.AssertExpectations(t)
+}
+
+// TestSCIMCreateUserHandler_Conflict tests that SCIM errors keep their status and scimType
+func TestSCIMCreateUserHandler_Conflict(t *testing.T) {
+	mockService := new(mocks.SCIMService)
+	router := newSCIMRouter(mockService)
+
+	mockService.On("CreateUser", mock.Anything).Return(nil, scim.NewError(http.StatusConflict, "uniqueness", "userName is already taken"))
+
+	req, _ := http.NewRequest("POST", "/scim/v2/Users", bytes.NewBufferString(`{"userName":"jane@example.com"}`))
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusConflict, resp.Code)
+	assert.Contains(t, resp.Body.String(), `"scimType":"uniqueness"`)
+}
+
+// TestSCIMPatchUserHandler_PreconditionFailed tests that the version in If-Match is passed to the service, which
+// checks it, and that a tag the service did not issue is rejected before patching
+func TestSCIMPatchUserHandler_PreconditionFailed(t *testing.T) {
+	mockService := new(mocks.SCIMService)
+	router := newSCIMRouter(mockService)
+
+	mockService.On("PatchUser", "3", uint(4), mock.Anything).
+		Return(nil, scim.NewError(http.StatusPreconditionFailed, "", "resource version does not match If-Match"))
+
+	body := `{"schemas":["urn:ietf:params:scim:api:messages:2.0:PatchOp"],"Operations":[{"op":"replace","path":"active","value":false}]}`
+	req, _ := http.NewRequest("PATCH", "/scim/v2/Users/3", bytes.NewBufferString(body))
+	req.Header.Set("If-Match", `W/"4-0a1b2c3d4e5f6a7b"`)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusPreconditionFailed, resp.Code)
+	mockService.AssertExpectations(t)
+
+	req, _ = http.NewRequest("PATCH", "/scim/v2/Users/3", bytes.NewBufferString(body))
+	req.Header.Set("If-Match", `W/"stale"`)
+	resp = httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusPreconditionFailed, resp.Code)
+	mockService.AssertNumberOfCalls(t, "PatchUser", 1)
+}
+
+// TestSCIMDeleteGroupHandler tests that a deleted group returns 204
+func TestSCIMDeleteGroupHandler(t *testing.T) {
+	mockService := new(mocks.SCIMService)
+	router := newSCIMRouter(mockService)
+
+	mockService.On("DeleteGroup", "1", uint(0)).Return(nil)
+
+	req, _ := http.NewRequest("DELETE", "/scim/v2/Groups/1", nil)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusNoContent, resp.Code)
+	mockService.AssertExpectations(t)
+}
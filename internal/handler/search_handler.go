@@ -0,0 +1,72 @@
+package handler
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"hub_management_service/internal/service"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+type SearchHandler struct {
+	service service.SearchService
+	expand  service.ExpandService
+}
+
+func NewSearchHandler(service service.SearchService, expand service.ExpandService) *SearchHandler {
+	return &SearchHandler{service: service, expand: expand}
+}
+
+// Search - Handler for searching hubs, teams and users with ?q=, tolerating typos, restricted with
+// ?types=hubs,teams,users and with up to ?limit= results per type. ?fields= and ?expand= apply to every hit.
+func (h *SearchHandler) Search(c *gin.Context) {
+	query := service.SearchQuery{Q: c.Query("q")}
+	for _, value := range c.QueryArray("types") {
+		for _, t := range strings.Split(value, ",") {
+			if t = strings.TrimSpace(t); t != "" {
+				query.Types = append(query.Types, service.SearchType(t))
+			}
+		}
+	}
+	if value, ok := c.GetQuery("limit"); ok {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be a positive integer"})
+			return
+		}
+		query.Limit = limit
+	}
+	opts, ok := parseReadOptions(c, service.SearchHitRecord, service.SearchHit{}, "")
+	if !ok {
+		return
+	}
+
+	results, err := h.service.Search(query)
+	switch {
+	case errors.Is(err, service.ErrInvalidSearch):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if len(opts.expansion) > 0 {
+		var hits []*service.SearchHit
+		for _, group := range [][]service.SearchHit{results.Hubs, results.Teams, results.Users} {
+			for i := range group {
+				hits = append(hits, &group[i])
+			}
+		}
+		if !expanded(c, h.expand.ExpandSearchHits(hits, opts.expansion)) {
+			return
+		}
+	}
+	body := gin.H{"query": results.Query}
+	for name, hits := range map[string][]service.SearchHit{"hubs": results.Hubs, "teams": results.Teams, "users": results.Users} {
+		if body[name], ok = opts.sparse(c, hits); !ok {
+			return
+		}
+	}
+	c.JSON(http.StatusOK, body)
+}
@@ -0,0 +1,84 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/stretchr/testify/mock"
+	"hub_management_service/internal/entity"
+	"hub_management_service/internal/service"
+	"hub_management_service/internal/service/mocks"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func newSearchRouter(mockService *mocks.SearchService) *gin.Engine {
+	handler := NewSearchHandler(mockService, new(mocks.ExpandService))
+	router := gin.Default()
+	router.GET("/search", handler.Search)
+	return router
+}
+
+// TestSearchHandler tests that the query, types and limit are passed on and the grouped results returned
+func TestSearchHandler(t *testing.T) {
+	mockService := new(mocks.SearchService)
+	router := newSearchRouter(mockService)
+	mockService.On("Search", service.SearchQuery{Q: "pairs", Types: []service.SearchType{"hubs", "users"}, Limit: 3}).
+		Return(&service.SearchResults{Query: "pairs", Hubs: []service.SearchHit{{ID: 1, Name: "Paris Hub", Score: 0.667}},
+			Teams: []service.SearchHit{}, Users: []service.SearchHit{}}, nil)
+
+	req, _ := http.NewRequest("GET", "/search?q=pairs&types=hubs,users&limit=3", nil)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	var body map[string]interface{}
+	assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), &body))
+	assert.Equal(t, "Paris Hub", body["hubs"].([]interface{})[0].(map[string]interface{})["name"])
+	assert.Equal(t, []interface{}{}, body["teams"])
+}
+
+// TestSearchHandler_Invalid tests that invalid searches are rejected with 400
+func TestSearchHandler_Invalid(t *testing.T) {
+	mockService := new(mocks.SearchService)
+	router := newSearchRouter(mockService)
+	mockService.On("Search", service.SearchQuery{}).Return(nil, fmt.Errorf("%w: q must contain letters or digits", service.ErrInvalidSearch))
+
+	for _, url := range []string{"/search", "/search?q=paris&limit=many", "/search?q=paris&limit=0"} {
+		req, _ := http.NewRequest("GET", url, nil)
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		assert.Equal(t, http.StatusBadRequest, resp.Code, url)
+	}
+}
+
+// TestSearchHandler_FieldsAndExpand tests that ?fields= and ?expand= apply to the hits of every type
+func TestSearchHandler_FieldsAndExpand(t *testing.T) {
+	mockService := new(mocks.SearchService)
+	mockExpand := new(mocks.ExpandService)
+	handler := NewSearchHandler(mockService, mockExpand)
+	router := gin.Default()
+	router.GET("/search", handler.Search)
+	mockService.On("Search", service.SearchQuery{Q: "jane"}).
+		Return(&service.SearchResults{Query: "jane", Hubs: []service.SearchHit{}, Teams: []service.SearchHit{},
+			Users: []service.SearchHit{{ID: 7, Name: "Jane", Detail: "jane@example.com", TeamID: 3, Score: 1}}}, nil)
+	mockExpand.On("ExpandSearchHits", mock.Anything, service.Expansion{"team": {}}).Run(func(args mock.Arguments) {
+		hit := args.Get(0).([]*service.SearchHit)[0]
+		hit.Team = &entity.Team{ID: 3, Name: "Backend", HubID: 1}
+	}).Return(nil)
+
+	req, _ := http.NewRequest("GET", "/search?q=jane&fields=name,team.name&expand=team", nil)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.JSONEq(t, `{"query":"jane","hubs":[],"teams":[],"users":[{"id":7,"name":"Jane","team":{"id":3,"name":"Backend"}}]}`,
+		resp.Body.String())
+
+	req, _ = http.NewRequest("GET", "/search?q=jane&expand=users", nil)
+	resp = httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+}
@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"errors"
 	"github.com/gin-gonic/gin"
 	"hub_management_service/internal/entity"
 	"hub_management_service/internal/service"
@@ -10,10 +11,11 @@ import (
 
 type TeamHandler struct {
 	service service.TeamService
+	expand  service.ExpandService
 }
 
-func NewTeamHandler(service service.TeamService) *TeamHandler {
-	return &TeamHandler{service: service}
+func NewTeamHandler(service service.TeamService, expand service.ExpandService) *TeamHandler {
+	return &TeamHandler{service: service, expand: expand}
 }
 
 func (h *TeamHandler) CreateTeam(c *gin.Context) {
@@ -24,6 +26,10 @@ func (h *TeamHandler) CreateTeam(c *gin.Context) {
 	}
 
 	if err := h.service.CreateTeam(&team); err != nil {
+		if errors.Is(err, service.ErrParentTeamNotFound) || errors.Is(err, service.ErrParentHubMismatch) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -45,6 +51,10 @@ func (h *TeamHandler) FindTeamsByHubID(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid Hub ID"})
 		return
 	}
+	opts, ok := parseReadOptions(c, service.TeamRecord, entity.Team{}, "")
+	if !ok {
+		return
+	}
 
 	teams, err := h.service.FindTeamsByHubID(uint(hubIDUint))
 	if err != nil {
@@ -56,8 +66,15 @@ func (h *TeamHandler) FindTeamsByHubID(c *gin.Context) {
 		c.JSON(http.StatusNotFound, gin.H{"message": "No teams found for this hub"})
 		return
 	}
+	if !opts.expandTeams(c, h.expand, teamRefs(teams)...) {
+		return
+	}
+	records, ok := opts.sparse(c, teams)
+	if !ok {
+		return
+	}
 
-	c.JSON(http.StatusOK, gin.H{"teams": teams})
+	c.JSON(http.StatusOK, gin.H{"teams": records})
 }
 
 // FindTeamByID - Endpoint to find a team by its ID
@@ -73,6 +90,10 @@ func (h *TeamHandler) FindTeamByID(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid Team ID"})
 		return
 	}
+	opts, ok := parseReadOptions(c, service.TeamRecord, entity.Team{}, "")
+	if !ok {
+		return
+	}
 
 	team, err := h.service.FindByID(uint(teamIDUint))
 	if err != nil {
@@ -84,6 +105,164 @@ func (h *TeamHandler) FindTeamByID(c *gin.Context) {
 		c.JSON(http.StatusNotFound, gin.H{"message": "Team not found"})
 		return
 	}
+	if !opts.expandTeams(c, h.expand, team) {
+		return
+	}
+	record, ok := opts.sparse(c, team)
+	if !ok {
+		return
+	}
+
+	writeTagged(c, team.Version, len(opts.expansion) > 0, gin.H{"team": record})
+}
+
+// SetParent - Endpoint to move a team at the version of If-Match under another team, or to the top level with a null parent_id
+func (h *TeamHandler) SetParent(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	version, ok := ifMatch(c)
+	if !ok {
+		return
+	}
+	var req struct {
+		ParentID *uint `json:"parent_id"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	team, err := h.service.SetParent(uint(id), version, req.ParentID)
+	if err != nil {
+		writeTeamError(c, err)
+		return
+	}
+	c.Header("ETag", etag(team.Version))
+
+	c.JSON(http.StatusOK, gin.H{"message": "Parent team updated successfully", "team": team})
+}
+
+// PatchTeam - Endpoint to rename a team or change its parent at the version of If-Match with a JSON Merge Patch or JSON Patch
+func (h *TeamHandler) PatchTeam(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	version, ok := ifMatch(c)
+	if !ok {
+		return
+	}
+	format, patch, ok := readPatch(c)
+	if !ok {
+		return
+	}
+
+	team, err := h.service.PatchTeam(uint(id), version, format, patch)
+	if err != nil {
+		writeTeamError(c, err)
+		return
+	}
+	c.Header("ETag", etag(team.Version))
+
+	c.JSON(http.StatusOK, gin.H{"message": "Team updated successfully", "team": team})
+}
+
+// FindSubtree - Endpoint to find a team with all of its sub-teams nested below it. ?fields= and ?expand= apply to
+// every team of the tree, which keeps its children.
+func (h *TeamHandler) FindSubtree(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	opts, ok := parseReadOptions(c, service.TeamRecord, service.TeamNode{}, "")
+	if !ok {
+		return
+	}
+	if len(opts.fields) > 0 {
+		opts.fields["children"] = opts.fields
+	}
+	tree, err := h.service.FindSubtree(uint(id))
+	if err != nil {
+		writeTeamError(c, err)
+		return
+	}
+	var teams []*entity.Team
+	for nodes := []*service.TeamNode{tree}; len(nodes) > 0; nodes = nodes[1:] {
+		teams = append(teams, &nodes[0].Team)
+		nodes = append(nodes, nodes[0].Children...)
+	}
+	if !opts.expandTeams(c, h.expand, teams...) {
+		return
+	}
+	record, ok := opts.sparse(c, tree)
+	if !ok {
+		return
+	}
 
-	c.JSON(http.StatusOK, gin.H{"team": team})
+	c.JSON(http.StatusOK, gin.H{"team": record})
+}
+
+// FindAncestors - Endpoint to find the parent teams above a team, nearest first
+func (h *TeamHandler) FindAncestors(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	opts, ok := parseReadOptions(c, service.TeamRecord, entity.Team{}, "")
+	if !ok {
+		return
+	}
+	teams, err := h.service.FindAncestors(uint(id))
+	if err != nil {
+		writeTeamError(c, err)
+		return
+	}
+	if !opts.expandTeams(c, h.expand, teamRefs(teams)...) {
+		return
+	}
+	records, ok := opts.sparse(c, teams)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"teams": records})
+}
+
+// FindHeadcount - Endpoint to count the people in a team, including its sub-teams
+func (h *TeamHandler) FindHeadcount(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	headcount, err := h.service.FindHeadcount(uint(id))
+	if err != nil {
+		writeTeamError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, headcount)
+}
+
+func writeTeamError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrTeamNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"message": "Team not found"})
+	case errors.Is(err, service.ErrParentTeamNotFound), errors.Is(err, service.ErrParentHubMismatch), errors.Is(err, service.ErrInvalidPatch):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrTeamCycle), errors.Is(err, service.ErrPatchConflict):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrInvalidPatchResult):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrVersionMismatch):
+		c.JSON(http.StatusPreconditionFailed, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
 }
@@ -2,8 +2,10 @@ package handler
 
 import (
 	"bytes"
+	"fmt"
 	"github.com/stretchr/testify/mock"
 	"hub_management_service/internal/entity"
+	"hub_management_service/internal/service"
 	"hub_management_service/internal/service/mocks"
 	"net/http"
 	"net/http/httptest"
@@ -16,7 +18,7 @@ import (
 // TestCreateTeam tests the CreateTeam handler with valid input
 func TestCreateTeam(t *testing.T) {
 	mockService := new(mocks.TeamService)
-	handler := NewTeamHandler(mockService)
+	handler := NewTeamHandler(mockService, new(mocks.ExpandService))
 
 	router := gin.Default()
 	router.POST("/teams", handler.CreateTeam)
@@ -42,7 +44,7 @@ func TestCreateTeam(t *testing.T) {
 // TestCreateTeam_BadRequest tests the CreateTeam handler with invalid input
 func TestCreateTeam_BadRequest(t *testing.T) {
 	mockService := new(mocks.TeamService)
-	handler := NewTeamHandler(mockService)
+	handler := NewTeamHandler(mockService, new(mocks.ExpandService))
 
 	router := gin.Default()
 	router.POST("/teams", handler.CreateTeam)
@@ -61,7 +63,7 @@ func TestCreateTeam_BadRequest(t *testing.T) {
 // TestFindTeamsByHubID tests the FindTeamsByHubID handler when teams are found
 func TestFindTeamsByHubID(t *testing.T) {
 	mockService := new(mocks.TeamService)
-	handler := NewTeamHandler(mockService)
+	handler := NewTeamHandler(mockService, new(mocks.ExpandService))
 
 	router := gin.Default()
 	router.GET("/teams/:hub_id", handler.FindTeamsByHubID)
@@ -89,7 +91,7 @@ func TestFindTeamsByHubID(t *testing.T) {
 // TestFindTeamsByHubID_NotFound tests the FindTeamsByHubID handler when no teams are found
 func TestFindTeamsByHubID_NotFound(t *testing.T) {
 	mockService := new(mocks.TeamService)
-	handler := NewTeamHandler(mockService)
+	handler := NewTeamHandler(mockService, new(mocks.ExpandService))
 
 	router := gin.Default()
 	router.GET("/teams/:hub_id", handler.FindTeamsByHubID)
@@ -110,7 +112,7 @@ func TestFindTeamsByHubID_NotFound(t *testing.T) {
 // TestFindTeamByID tests the FindTeamByID handler when the team is found
 func TestFindTeamByID(t *testing.T) {
 	mockService := new(mocks.TeamService)
-	handler := NewTeamHandler(mockService)
+	handler := NewTeamHandler(mockService, new(mocks.ExpandService))
 
 	router := gin.Default()
 	router.GET("/teams/:id", handler.FindTeamByID)
@@ -136,7 +138,7 @@ func TestFindTeamByID(t *testing.T) {
 // TestFindTeamByID_NotFound tests the FindTeamByID handler when the team is not found
 func TestFindTeamByID_NotFound(t *testing.T) {
 	mockService := new(mocks.TeamService)
-	handler := NewTeamHandler(mockService)
+	handler := NewTeamHandler(mockService, new(mocks.ExpandService))
 
 	router := gin.Default()
 	router.GET("/teams/:id", handler.FindTeamByID)
@@ -153,3 +155,136 @@ func TestFindTeamByID_NotFound(t *testing.T) {
 	assert.Equal(t, http.StatusNotFound, resp.Code)
 	mockService.AssertExpectations(t)
 }
+
+// TestPatchTeam tests that a patch moving a team to another hub returns 422
+func TestPatchTeam(t *testing.T) {
+	mockService := new(mocks.TeamService)
+	handler := NewTeamHandler(mockService, new(mocks.ExpandService))
+
+	router := gin.Default()
+	router.PATCH("/teams/:id", handler.PatchTeam)
+
+	patch := `[{"op": "replace", "path": "/hub_id", "value": 4}]`
+	mockService.On("PatchTeam", uint(2), service.AnyVersion, service.JSONPatch, []byte(patch)).
+		Return(nil, fmt.Errorf("%w: hub_id cannot be changed", service.ErrInvalidPatchResult))
+
+	req, _ := http.NewRequest("PATCH", "/teams/2", bytes.NewBufferString(patch))
+	req.Header.Set("Content-Type", "application/json-patch+json")
+	req.Header.Set("If-Match", "*")
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.Code)
+	assert.Contains(t, resp.Body.String(), "hub_id cannot be changed")
+}
+
+// TestSetParent tests moving a team under a parent team
+func TestSetParent(t *testing.T) {
+	mockService := new(mocks.TeamService)
+	handler := NewTeamHandler(mockService, new(mocks.ExpandService))
+
+	router := gin.Default()
+	router.PUT("/teams/:id/parent", handler.SetParent)
+
+	parentID := uint(1)
+	mockService.On("SetParent", uint(2), uint(3), &parentID).Return(&entity.Team{ID: 2, Name: "Squad", HubID: 1, ParentID: &parentID, Version: 4}, nil)
+
+	req, _ := http.NewRequest("PUT", "/teams/2/parent", bytes.NewBufferString(`{"parent_id": 1}`))
+	req.Header.Set("If-Match", `"3"`)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Contains(t, resp.Body.String(), `"parent_id":1`)
+	assert.Equal(t, `"4"`, resp.Header().Get("ETag"))
+	mockService.AssertExpectations(t)
+}
+
+// TestSetParent_Cycle tests that a cycle is reported as a conflict
+func TestSetParent_Cycle(t *testing.T) {
+	mockService := new(mocks.TeamService)
+	handler := NewTeamHandler(mockService, new(mocks.ExpandService))
+
+	router := gin.Default()
+	router.PUT("/teams/:id/parent", handler.SetParent)
+
+	mockService.On("SetParent", uint(1), service.AnyVersion, mock.Anything).Return(nil, service.ErrTeamCycle)
+
+	req, _ := http.NewRequest("PUT", "/teams/1/parent", bytes.NewBufferString(`{"parent_id": 3}`))
+	req.Header.Set("If-Match", "*")
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusConflict, resp.Code)
+}
+
+// TestFindSubtree tests that the nested tree is returned
+func TestFindSubtree(t *testing.T) {
+	mockService := new(mocks.TeamService)
+	handler := NewTeamHandler(mockService, new(mocks.ExpandService))
+
+	router := gin.Default()
+	router.GET("/teams/:id/subtree", handler.FindSubtree)
+
+	tree := &service.TeamNode{Team: entity.Team{ID: 1, Name: "Tribe", HubID: 1}, Headcount: 1, TotalHeadcount: 3}
+	tree.Children = []*service.TeamNode{{Team: entity.Team{ID: 2, Name: "Squad", HubID: 1}, Headcount: 2, TotalHeadcount: 2, Children: []*service.TeamNode{}}}
+	mockService.On("FindSubtree", uint(1)).Return(tree, nil)
+
+	req, _ := http.NewRequest("GET", "/teams/1/subtree", nil)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Contains(t, resp.Body.String(), `"total_headcount":3`)
+	assert.Contains(t, resp.Body.String(), `"name":"Squad"`)
+}
+
+// TestFindSubtree_FieldsAndExpand tests that ?fields= and ?expand= apply to every team of the tree
+func TestFindSubtree_FieldsAndExpand(t *testing.T) {
+	mockService := new(mocks.TeamService)
+	mockExpand := new(mocks.ExpandService)
+	handler := NewTeamHandler(mockService, mockExpand)
+
+	router := gin.Default()
+	router.GET("/teams/:id/subtree", handler.FindSubtree)
+
+	tree := &service.TeamNode{Team: entity.Team{ID: 1, Name: "Tribe", HubID: 1}, Headcount: 1, TotalHeadcount: 3}
+	tree.Children = []*service.TeamNode{{Team: entity.Team{ID: 2, Name: "Squad", HubID: 1}, Headcount: 2, TotalHeadcount: 2, Children: []*service.TeamNode{}}}
+	mockService.On("FindSubtree", uint(1)).Return(tree, nil)
+	mockExpand.On("ExpandTeams", mock.Anything, service.Expansion{"hub": {}}).Run(func(args mock.Arguments) {
+		for _, team := range args.Get(0).([]*entity.Team) {
+			team.Hub = &entity.Hub{ID: 1, Name: "Paris Hub"}
+		}
+	}).Return(nil)
+
+	req, _ := http.NewRequest("GET", "/teams/1/subtree?fields=name,hub.name&expand=hub", nil)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.JSONEq(t, `{"team":{"id":1,"name":"Tribe","hub":{"id":1,"name":"Paris Hub"},"children":[
+		{"id":2,"name":"Squad","hub":{"id":1,"name":"Paris Hub"},"children":[]}]}}`, resp.Body.String())
+	assert.Len(t, mockExpand.Calls[0].Arguments.Get(0), 2)
+
+	req, _ = http.NewRequest("GET", "/teams/1/subtree?fields=members", nil)
+	resp = httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+}
+
+// TestFindHeadcount_NotFound tests that a missing team returns 404
+func TestFindHeadcount_NotFound(t *testing.T) {
+	mockService := new(mocks.TeamService)
+	handler := NewTeamHandler(mockService, new(mocks.ExpandService))
+
+	router := gin.Default()
+	router.GET("/teams/:id/headcount", handler.FindHeadcount)
+
+	mockService.On("FindHeadcount", uint(9)).Return(nil, service.ErrTeamNotFound)
+
+	req, _ := http.NewRequest("GET", "/teams/9/headcount", nil)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+}
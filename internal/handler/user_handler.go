@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"errors"
 	"github.com/gin-gonic/gin"
 	"hub_management_service/internal/entity"
 	"hub_management_service/internal/service"
@@ -10,10 +11,11 @@ import (
 
 type UserHandler struct {
 	service service.UserService
+	expand  service.ExpandService
 }
 
-func NewUserHandler(service service.UserService) *UserHandler {
-	return &UserHandler{service: service}
+func NewUserHandler(service service.UserService, expand service.ExpandService) *UserHandler {
+	return &UserHandler{service: service, expand: expand}
 }
 
 func (h *UserHandler) CreateUser(c *gin.Context) {
@@ -22,15 +24,115 @@ func (h *UserHandler) CreateUser(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	opts, ok := parseWriteOptions(c)
+	if !ok {
+		return
+	}
 
-	if err := h.service.CreateUser(&user); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := h.service.CreateUser(&user, opts); err != nil {
+		writeUserError(c, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{"message": "User created successfully", "user": user})
 }
 
+// UpdateUser - Handler for partially updating a user at the version of If-Match, only the fields present in the body are changed.
+// JSON Merge Patch and JSON Patch bodies can also set or clear the manager.
+func (h *UserHandler) UpdateUser(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	version, ok := ifMatch(c)
+	if !ok {
+		return
+	}
+	opts, ok := parseWriteOptions(c)
+	if !ok {
+		return
+	}
+
+	var user *entity.User
+	if isPatch(c) {
+		format, patch, ok := readPatch(c)
+		if !ok {
+			return
+		}
+		user, err = h.service.PatchUser(uint(id), version, format, patch, opts)
+	} else {
+		var patch service.UserPatch
+		if err := c.ShouldBindJSON(&patch); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		user, err = h.service.UpdateUser(uint(id), version, &patch, opts)
+	}
+	if err != nil {
+		writeUserError(c, err)
+		return
+	}
+	c.Header("ETag", etag(user.Version))
+
+	c.JSON(http.StatusOK, gin.H{"message": "User updated successfully", "user": user})
+}
+
+// SearchUsers - Handler for GET /users, looks up a single user with ?email= or searches by profile fields
+func (h *UserHandler) SearchUsers(c *gin.Context) {
+	opts, ok := parseReadOptions(c, service.UserRecord, entity.User{}, "")
+	if !ok {
+		return
+	}
+	if email, ok := c.GetQuery("email"); ok {
+		h.findUserByEmail(c, email, opts)
+		return
+	}
+
+	query := service.UserQuery{
+		Query:          c.Query("q"),
+		JobTitle:       c.Query("job_title"),
+		EmploymentType: c.Query("employment_type"),
+		EmployeeNumber: c.Query("employee_number"),
+		Locale:         c.Query("locale"),
+		TimeZone:       c.Query("time_zone"),
+	}
+	for param, target := range map[string]*int{"offset": &query.Offset, "limit": &query.Limit} {
+		if value, ok := c.GetQuery(param); ok {
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 0 {
+				c.JSON(http.StatusBadRequest, gin.H{"error": param + " must be a non-negative integer"})
+				return
+			}
+			*target = n
+		}
+	}
+	if value, ok := c.GetQuery("team_id"); ok {
+		teamID, err := strconv.Atoi(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		query.TeamID = uint(teamID)
+	}
+
+	users, total, err := h.service.SearchUsers(query)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	h.writeUsers(c, opts, gin.H{"total": total}, "users", users)
+}
+
+func (h *UserHandler) findUserByEmail(c *gin.Context, email string, opts readOptions) {
+	user, err := h.service.FindUserByEmail(email)
+	if err != nil {
+		writeUserError(c, err)
+		return
+	}
+	h.writeUser(c, opts, user)
+}
+
 // FindUserByTeamID - Handler for finding users by TeamID
 func (h *UserHandler) FindUserByTeamID(c *gin.Context) {
 	teamID, err := strconv.Atoi(c.Param("team_id"))
@@ -38,13 +140,16 @@ func (h *UserHandler) FindUserByTeamID(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	opts, ok := parseReadOptions(c, service.UserRecord, entity.User{}, "")
+	if !ok {
+		return
+	}
 	users, err := h.service.FindUserByTeamID(uint(teamID))
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
-
-	c.JSON(http.StatusOK, gin.H{"users": users})
+	h.writeUsers(c, opts, gin.H{}, "users", users)
 }
 
 // FindUserByID - Handler for finding a user by their ID
@@ -54,11 +159,200 @@ func (h *UserHandler) FindUserByID(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	opts, ok := parseReadOptions(c, service.UserRecord, entity.User{}, "")
+	if !ok {
+		return
+	}
 	user, err := h.service.FindUserByID(uint(id))
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
+	if user == nil {
+		h.writeUser(c, opts, user)
+		return
+	}
+	if !opts.expandUsers(c, h.expand, user) {
+		return
+	}
+	record, ok := opts.sparse(c, user)
+	if !ok {
+		return
+	}
+
+	writeTagged(c, user.Version, len(opts.expansion) > 0, gin.H{"user": record})
+}
+
+// SetManager - Handler for setting the manager of a user at the version of If-Match, a null manager_id removes the manager
+func (h *UserHandler) SetManager(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	version, ok := ifMatch(c)
+	if !ok {
+		return
+	}
+	var req struct {
+		ManagerID *uint `json:"manager_id"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	user, err := h.service.SetManager(uint(id), version, req.ManagerID)
+	if err != nil {
+		writeUserError(c, err)
+		return
+	}
+	c.Header("ETag", etag(user.Version))
+
+	c.JSON(http.StatusOK, gin.H{"message": "Manager updated successfully", "user": user})
+}
+
+// FindDirectReports - Handler for finding the users reporting to a manager
+func (h *UserHandler) FindDirectReports(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	opts, ok := parseReadOptions(c, service.UserRecord, entity.User{}, "")
+	if !ok {
+		return
+	}
+	users, err := h.service.FindDirectReports(uint(id))
+	if err != nil {
+		writeUserError(c, err)
+		return
+	}
+	h.writeUsers(c, opts, gin.H{}, "users", users)
+}
+
+// FindReportingChain - Handler for finding the managers above a user, nearest first
+func (h *UserHandler) FindReportingChain(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	opts, ok := parseReadOptions(c, service.UserRecord, entity.User{}, "")
+	if !ok {
+		return
+	}
+	managers, err := h.service.FindReportingChain(uint(id))
+	if err != nil {
+		writeUserError(c, err)
+		return
+	}
+	h.writeUsers(c, opts, gin.H{}, "managers", managers)
+}
+
+// FindSkipLevel - Handler for finding the skip-level manager and reports of a user, ?fields= and ?expand= apply to each
+// of them
+func (h *UserHandler) FindSkipLevel(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	opts, ok := parseReadOptions(c, service.UserRecord, entity.User{}, "")
+	if !ok {
+		return
+	}
+	skipLevel, err := h.service.FindSkipLevel(uint(id))
+	if err != nil {
+		writeUserError(c, err)
+		return
+	}
+	users := userRefs(skipLevel.Reports)
+	if skipLevel.Manager != nil {
+		users = append(users, skipLevel.Manager)
+	}
+	if !opts.expandUsers(c, h.expand, users...) {
+		return
+	}
+	manager, ok := opts.sparse(c, skipLevel.Manager)
+	if !ok {
+		return
+	}
+	reports, ok := opts.sparse(c, skipLevel.Reports)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"manager": manager, "reports": reports})
+}
+
+// writeUser writes a user, or null when there is none, with the fields and relations of the options
+func (h *UserHandler) writeUser(c *gin.Context, opts readOptions, user *entity.User) {
+	if user != nil && !opts.expandUsers(c, h.expand, user) {
+		return
+	}
+	record, ok := opts.sparse(c, user)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"user": record})
+}
+
+// writeUsers adds users under name to body and writes it, with the fields and relations of the options
+func (h *UserHandler) writeUsers(c *gin.Context, opts readOptions, body gin.H, name string, users []entity.User) {
+	if !opts.expandUsers(c, h.expand, userRefs(users)...) {
+		return
+	}
+	records, ok := opts.sparse(c, users)
+	if !ok {
+		return
+	}
+	body[name] = records
+
+	c.JSON(http.StatusOK, body)
+}
+
+// writeUserError maps user service errors to HTTP responses
+func writeUserError(c *gin.Context, err error) {
+	var conflictErr *service.EmailConflictError
+	var fullErr *service.HubFullError
+	switch {
+	case errors.Is(err, service.ErrInvalidEmail), errors.Is(err, service.ErrInvalidProfile), errors.Is(err, service.ErrTeamNotFound),
+		errors.Is(err, service.ErrManagerNotFound), errors.Is(err, service.ErrInvalidPatch):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrInvalidPatchResult):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrUserNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"message": "User not found"})
+	case errors.Is(err, service.ErrVersionMismatch):
+		c.JSON(http.StatusPreconditionFailed, gin.H{"error": err.Error()})
+	case errors.As(err, &conflictErr):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error(), "user_id": conflictErr.UserID})
+	case errors.As(err, &fullErr):
+		writeHubFull(c, fullErr)
+	case errors.Is(err, service.ErrEmployeeNumberTaken), errors.Is(err, service.ErrManagerCycle), errors.Is(err, service.ErrPatchConflict):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+// parseWriteOptions reads ?override_capacity=true, writing a 400 response if it is not a boolean
+func parseWriteOptions(c *gin.Context) (service.WriteOptions, bool) {
+	var opts service.WriteOptions
+	if value, ok := c.GetQuery("override_capacity"); ok {
+		override, err := strconv.ParseBool(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "override_capacity must be a boolean"})
+			return opts, false
+		}
+		opts.OverrideCapacity = override
+	}
+	return opts, true
+}
 
-	c.JSON(http.StatusOK, gin.H{"user": user})
+// writeHubFull reports a move into a full hub, along with the numbers behind the decision
+func writeHubFull(c *gin.Context, err *service.HubFullError) {
+	c.JSON(http.StatusConflict, gin.H{"error": err.Error(), "hub_id": err.HubID, "headcount": err.Headcount, "capacity": err.Capacity})
 }
@@ -5,6 +5,8 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"hub_management_service/internal/entity"
+	"hub_management_service/internal/service"
 	"hub_management_service/internal/service/mocks"
 	"net/http"
 	"net/http/httptest"
@@ -14,13 +16,13 @@ import (
 // TestCreateUser tests the CreateUser handler with valid input
 func TestCreateUser(t *testing.T) {
 	mockService := new(mocks.UserService)
-	handler := NewUserHandler(mockService)
+	handler := NewUserHandler(mockService, new(mocks.ExpandService))
 
 	router := gin.Default()
 	router.POST("/users", handler.CreateUser)
 
 	// Mock the CreateUser behavior
-	mockService.On("CreateUser", mock.AnythingOfType("*entity.User")).Return(nil)
+	mockService.On("CreateUser", mock.AnythingOfType("*entity.User"), service.WriteOptions{}).Return(nil)
 
 	// Create request with valid JSON body
 	body := `{
@@ -41,7 +43,7 @@ func TestCreateUser(t *testing.T) {
 // TestCreateUser_BadRequest tests the CreateUser handler with invalid input
 func TestCreateUser_BadRequest(t *testing.T) {
 	mockService := new(mocks.UserService)
-	handler := NewUserHandler(mockService)
+	handler := NewUserHandler(mockService, new(mocks.ExpandService))
 
 	router := gin.Default()
 	router.POST("/users", handler.CreateUser)
@@ -58,3 +60,264 @@ func TestCreateUser_BadRequest(t *testing.T) {
 	assert.Equal(t, http.StatusBadRequest, resp.Code)
 	mockService.AssertExpectations(t)
 }
+
+// TestCreateUser_Conflict tests that a duplicate email returns 409 with the conflicting user ID
+func TestCreateUser_Conflict(t *testing.T) {
+	mockService := new(mocks.UserService)
+	handler := NewUserHandler(mockService, new(mocks.ExpandService))
+
+	router := gin.Default()
+	router.POST("/users", handler.CreateUser)
+
+	mockService.On("CreateUser", mock.AnythingOfType("*entity.User"), service.WriteOptions{}).Return(&service.EmailConflictError{UserID: 7})
+
+	body := `{"name": "Test User", "email": "TestUser@example.com", "team_id": 1}`
+	req, _ := http.NewRequest("POST", "/users", bytes.NewBufferString(body))
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusConflict, resp.Code)
+	assert.Contains(t, resp.Body.String(), `"user_id":7`)
+}
+
+// TestCreateUser_InvalidEmail tests that a malformed email returns 400
+func TestCreateUser_InvalidEmail(t *testing.T) {
+	mockService := new(mocks.UserService)
+	handler := NewUserHandler(mockService, new(mocks.ExpandService))
+
+	router := gin.Default()
+	router.POST("/users", handler.CreateUser)
+
+	mockService.On("CreateUser", mock.AnythingOfType("*entity.User"), service.WriteOptions{}).Return(service.ErrInvalidEmail)
+
+	body := `{"name": "Test User", "email": "not-an-email", "team_id": 1}`
+	req, _ := http.NewRequest("POST", "/users", bytes.NewBufferString(body))
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+}
+
+// TestCreateUser_HubFull tests that creating a user in a full hub returns 409 and that the override is passed on
+func TestCreateUser_HubFull(t *testing.T) {
+	mockService := new(mocks.UserService)
+	handler := NewUserHandler(mockService, new(mocks.ExpandService))
+
+	router := gin.Default()
+	router.POST("/users", handler.CreateUser)
+
+	mockService.On("CreateUser", mock.AnythingOfType("*entity.User"), service.WriteOptions{}).Return(&service.HubFullError{HubID: 3, Headcount: 20, Capacity: 20})
+	mockService.On("CreateUser", mock.AnythingOfType("*entity.User"), service.WriteOptions{OverrideCapacity: true}).Return(nil)
+
+	body := `{"name": "Test User", "email": "test@example.com", "team_id": 1}`
+	req, _ := http.NewRequest("POST", "/users", bytes.NewBufferString(body))
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusConflict, resp.Code)
+	assert.Contains(t, resp.Body.String(), `"hub_id":3`)
+
+	req, _ = http.NewRequest("POST", "/users?override_capacity=true", bytes.NewBufferString(body))
+	resp = httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	req, _ = http.NewRequest("POST", "/users?override_capacity=maybe", bytes.NewBufferString(body))
+	resp = httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+	mockService.AssertExpectations(t)
+}
+
+// TestFindUserByEmail tests the lookup of a user by email
+func TestFindUserByEmail(t *testing.T) {
+	mockService := new(mocks.UserService)
+	handler := NewUserHandler(mockService, new(mocks.ExpandService))
+
+	router := gin.Default()
+	router.GET("/users", handler.SearchUsers)
+
+	mockService.On("FindUserByEmail", "Jane@example.com").Return(&entity.User{ID: 7, Email: "jane@example.com"}, nil)
+	mockService.On("FindUserByEmail", "john@example.com").Return(nil, service.ErrUserNotFound)
+
+	req, _ := http.NewRequest("GET", "/users?email=Jane@example.com", nil)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Contains(t, resp.Body.String(), `"id":7`)
+
+	req, _ = http.NewRequest("GET", "/users?email=john@example.com", nil)
+	resp = httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+}
+
+// TestSearchUsers tests that query parameters are passed to the user search
+func TestSearchUsers(t *testing.T) {
+	mockService := new(mocks.UserService)
+	handler := NewUserHandler(mockService, new(mocks.ExpandService))
+
+	router := gin.Default()
+	router.GET("/users", handler.SearchUsers)
+
+	mockService.On("SearchUsers", service.UserQuery{
+		Query:          "jane",
+		EmploymentType: "contractor",
+		TeamID:         2,
+		Limit:          10,
+	}).Return([]entity.User{{ID: 7, Name: "Jane"}}, int64(1), nil)
+
+	req, _ := http.NewRequest("GET", "/users?q=jane&employment_type=contractor&team_id=2&limit=10", nil)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Contains(t, resp.Body.String(), `"total":1`)
+	mockService.AssertExpectations(t)
+}
+
+// TestUpdateUser tests that a partial update is passed to the service
+func TestUpdateUser(t *testing.T) {
+	mockService := new(mocks.UserService)
+	handler := NewUserHandler(mockService, new(mocks.ExpandService))
+
+	router := gin.Default()
+	router.PATCH("/users/:id", handler.UpdateUser)
+
+	mockService.On("UpdateUser", uint(7), service.AnyVersion, mock.MatchedBy(func(p *service.UserPatch) bool {
+		return p.JobTitle != nil && *p.JobTitle == "Engineer" && p.Name == nil && p.StartDate != nil && p.StartDate.String() == "2024-01-15"
+	}), service.WriteOptions{}).Return(&entity.User{ID: 7, Name: "Jane", JobTitle: "Engineer"}, nil)
+
+	body := `{"job_title": "Engineer", "start_date": "2024-01-15"}`
+	req, _ := http.NewRequest("PATCH", "/users/7", bytes.NewBufferString(body))
+	req.Header.Set("If-Match", "*")
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Contains(t, resp.Body.String(), `"job_title":"Engineer"`)
+	mockService.AssertExpectations(t)
+}
+
+// TestUpdateUser_InvalidField tests that validation errors return 400
+func TestUpdateUser_InvalidField(t *testing.T) {
+	mockService := new(mocks.UserService)
+	handler := NewUserHandler(mockService, new(mocks.ExpandService))
+
+	router := gin.Default()
+	router.PATCH("/users/:id", handler.UpdateUser)
+
+	mockService.On("UpdateUser", uint(7), service.AnyVersion, mock.Anything, service.WriteOptions{}).Return(nil, &service.ProfileFieldError{Field: "phone", Reason: "must be in international format"})
+
+	req, _ := http.NewRequest("PATCH", "/users/7", bytes.NewBufferString(`{"phone": "123"}`))
+	req.Header.Set("If-Match", "*")
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+	assert.Contains(t, resp.Body.String(), "invalid phone")
+}
+
+// TestUpdateUser_MergePatch tests that patch documents are applied by PatchUser, other bodies by UpdateUser
+func TestUpdateUser_MergePatch(t *testing.T) {
+	mockService := new(mocks.UserService)
+	handler := NewUserHandler(mockService, new(mocks.ExpandService))
+
+	router := gin.Default()
+	router.PATCH("/users/:id", handler.UpdateUser)
+
+	body := `{"manager_id": null}`
+	mockService.On("PatchUser", uint(7), service.AnyVersion, service.MergePatch, []byte(body), service.WriteOptions{OverrideCapacity: true}).
+		Return(&entity.User{ID: 7, Name: "Jane", Version: 5}, nil)
+
+	req, _ := http.NewRequest("PATCH", "/users/7?override_capacity=true", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/merge-patch+json")
+	req.Header.Set("If-Match", "*")
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, `"5"`, resp.Header().Get("ETag"))
+	mockService.AssertExpectations(t)
+	mockService.AssertNotCalled(t, "UpdateUser", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+// TestSetManager tests that clearing and cyclic managers are handled
+func TestSetManager(t *testing.T) {
+	mockService := new(mocks.UserService)
+	handler := NewUserHandler(mockService, new(mocks.ExpandService))
+
+	router := gin.Default()
+	router.PUT("/users/:id/manager", handler.SetManager)
+
+	mockService.On("SetManager", uint(3), service.AnyVersion, (*uint)(nil)).Return(&entity.User{ID: 3}, nil)
+	mockService.On("SetManager", uint(1), service.AnyVersion, mock.AnythingOfType("*uint")).Return(nil, service.ErrManagerCycle)
+
+	req, _ := http.NewRequest("PUT", "/users/3/manager", bytes.NewBufferString(`{"manager_id": null}`))
+	req.Header.Set("If-Match", "*")
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	req, _ = http.NewRequest("PUT", "/users/1/manager", bytes.NewBufferString(`{"manager_id": 3}`))
+	req.Header.Set("If-Match", "*")
+	resp = httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusConflict, resp.Code)
+}
+
+// TestFindReportingChain tests that the managers are returned nearest first
+func TestFindReportingChain(t *testing.T) {
+	mockService := new(mocks.UserService)
+	handler := NewUserHandler(mockService, new(mocks.ExpandService))
+
+	router := gin.Default()
+	router.GET("/users/:id/reporting-chain", handler.FindReportingChain)
+
+	mockService.On("FindReportingChain", uint(3)).Return([]entity.User{{ID: 2, Name: "Lead"}, {ID: 1, Name: "CEO"}}, nil)
+
+	req, _ := http.NewRequest("GET", "/users/3/reporting-chain", nil)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Contains(t, resp.Body.String(), `"managers":[{"id":2`)
+}
+
+// TestFindUserByID_Expand tests that the team and hub of a user are expanded and narrowed to the selected fields
+func TestFindUserByID_Expand(t *testing.T) {
+	mockService := new(mocks.UserService)
+	mockExpand := new(mocks.ExpandService)
+	handler := NewUserHandler(mockService, mockExpand)
+
+	router := gin.Default()
+	router.GET("/users/:id", handler.FindUserByID)
+
+	mockService.On("FindUserByID", uint(7)).Return(&entity.User{ID: 7, Name: "Jane", TeamID: 2, Version: 1}, nil)
+	mockExpand.On("ExpandUsers", mock.Anything, service.Expansion{"team": {"hub": {}}}).Run(func(args mock.Arguments) {
+		user := args.Get(0).([]*entity.User)[0]
+		user.Team = &entity.Team{ID: 2, Name: "Backend", HubID: 1, Hub: &entity.Hub{ID: 1, Name: "Paris Hub"}}
+	}).Return(nil)
+
+	req, _ := http.NewRequest("GET", "/users/7?expand=team.hub&fields=name,team.hub.name", nil)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.JSONEq(t, `{"user":{"id":7,"name":"Jane","team":{"id":2,"hub":{"id":1,"name":"Paris Hub"}}}}`, resp.Body.String())
+	// The expanded team and hub change without bumping the version of the user, so the ETag covers the body
+	tag := resp.Header().Get("ETag")
+	assert.Regexp(t, `^"1-[0-9a-f]+"$`, tag)
+
+	req, _ = http.NewRequest("GET", "/users/7?expand=team.hub&fields=name,team.hub.name", nil)
+	req.Header.Set("If-None-Match", `"1"`)
+	resp = httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	req, _ = http.NewRequest("GET", "/users/7?expand=team.hub&fields=name,team.hub.name", nil)
+	req.Header.Set("If-None-Match", tag)
+	resp = httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusNotModified, resp.Code)
+}
@@ -12,8 +12,37 @@ import (
 // Secret key to sign the JWT token, ideally should be in an environment variable
 var jwtSecret = []byte("your_secret_key")
 
-// AuthMiddleware is a middleware that checks for a valid JWT token in the request header
+// mfaChallengePurpose marks tokens that only prove the password step of a two-step login
+const mfaChallengePurpose = "mfa_challenge"
+
+// oidcStatePurpose marks tokens that carry the OIDC login state between redirect and callback
+const oidcStatePurpose = "oidc_state"
+
+// UsernameKey is the context key under which the authenticated username is stored
+const UsernameKey = "username"
+
+// RoleKey is the context key under which the role of the authenticated token is stored
+const RoleKey = "role"
+
+// Roles carried by access tokens in the role claim
+const (
+	RoleAdmin    = "admin"    // Password login, past the MFA step when enrolled
+	RoleEmployee = "employee" // OIDC sign-in of a provisioned user
+)
+
+// AuthMiddleware is a middleware that checks for a valid admin JWT token in the request header. Employee tokens
+// from OIDC sign-in are refused with 403.
 func AuthMiddleware() gin.HandlerFunc {
+	return requireRole(RoleAdmin)
+}
+
+// EmployeeAuthMiddleware accepts employee tokens as well as admin tokens, for the routes where employees act on their
+// own behalf such as bookings and check-ins
+func EmployeeAuthMiddleware() gin.HandlerFunc {
+	return requireRole(RoleAdmin, RoleEmployee)
+}
+
+func requireRole(roles ...string) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// Get the Authorization header
 		authHeader := c.GetHeader("Authorization")
@@ -32,23 +61,65 @@ func AuthMiddleware() gin.HandlerFunc {
 		}
 
 		// Parse and validate the token
-		_, err := ParseJWT(tokenParts[1])
+		token, err := ParseJWT(tokenParts[1])
 		if err != nil {
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired token"})
 			c.Abort()
 			return
 		}
 
+		// Purpose-bound tokens (MFA challenges, OIDC state) never grant access to protected routes
+		claims, _ := token.Claims.(jwt.MapClaims)
+		if purpose, ok := claims["purpose"]; ok {
+			message := "Invalid or expired token"
+			if purpose == mfaChallengePurpose {
+				message = "MFA verification required"
+			}
+			c.JSON(http.StatusUnauthorized, gin.H{"error": message})
+			c.Abort()
+			return
+		}
+		role, _ := claims["role"].(string)
+		if !containsRole(roles, role) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Administrator access required"})
+			c.Abort()
+			return
+		}
+		c.Set(UsernameKey, claims["username"])
+		c.Set(RoleKey, role)
+
 		// Continue processing the request
 		c.Next()
 	}
 }
 
-// GenerateJWT generates a JWT token with an expiration time of 15 minutes
+func containsRole(roles []string, role string) bool {
+	for _, r := range roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// GenerateJWT generates an admin JWT token with an expiration time of 15 minutes
 func GenerateJWT(username string) (string, error) {
+	return generateAccessToken(username, RoleAdmin, "pwd")
+}
+
+// GenerateEmployeeJWT generates a JWT token for an employee signed in with OIDC, valid for 15 minutes. It only opens
+// the routes behind EmployeeAuthMiddleware.
+func GenerateEmployeeJWT(username string) (string, error) {
+	return generateAccessToken(username, RoleEmployee, "oidc")
+}
+
+// generateAccessToken signs the username, role and authentication method (amr) of an access token
+func generateAccessToken(username, role, method string) (string, error) {
 	// Create a new token with username as claim
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
 		"username": username,
+		"role":     role,
+		"amr":      []string{method},
 		"exp":      time.Now().Add(15 * time.Minute).Unix(),
 	})
 
@@ -61,6 +132,67 @@ func GenerateJWT(username string) (string, error) {
 	return tokenString, nil
 }
 
+// GenerateMFAChallenge generates a token valid for 5 minutes that can only be exchanged for a full token
+func GenerateMFAChallenge(username string) (string, error) {
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"username": username,
+		"purpose":  mfaChallengePurpose,
+		"exp":      time.Now().Add(5 * time.Minute).Unix(),
+	})
+
+	return token.SignedString(jwtSecret)
+}
+
+// ParseMFAChallenge validates an MFA challenge token and returns the username it was issued for
+func ParseMFAChallenge(tokenStr string) (string, error) {
+	token, err := ParseJWT(tokenStr)
+	if err != nil {
+		return "", err
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok || claims["purpose"] != mfaChallengePurpose {
+		return "", errors.New("not an MFA challenge token")
+	}
+
+	username, ok := claims["username"].(string)
+	if !ok || username == "" {
+		return "", errors.New("MFA challenge token has no username")
+	}
+	return username, nil
+}
+
+// GenerateOIDCState signs the state, nonce and PKCE verifier of a pending OIDC login, valid for 10 minutes
+func GenerateOIDCState(state, nonce, codeVerifier string) (string, error) {
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"purpose":       oidcStatePurpose,
+		"state":         state,
+		"nonce":         nonce,
+		"code_verifier": codeVerifier,
+		"exp":           time.Now().Add(10 * time.Minute).Unix(),
+	})
+
+	return token.SignedString(jwtSecret)
+}
+
+// ParseOIDCState validates a token created by GenerateOIDCState and returns its state, nonce and PKCE verifier
+func ParseOIDCState(tokenStr string) (state, nonce, codeVerifier string, err error) {
+	token, err := ParseJWT(tokenStr)
+	if err != nil {
+		return "", "", "", err
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok || claims["purpose"] != oidcStatePurpose {
+		return "", "", "", errors.New("not an OIDC state token")
+	}
+
+	state, _ = claims["state"].(string)
+	nonce, _ = claims["nonce"].(string)
+	codeVerifier, _ = claims["code_verifier"].(string)
+	return state, nonce, codeVerifier, nil
+}
+
 // ParseJWT parses a JWT token and validates it
 func ParseJWT(tokenStr string) (*jwt.Token, error) {
 	token, err := jwt.Parse(tokenStr, func(token *jwt.Token) (interface{}, error) {
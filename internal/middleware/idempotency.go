@@ -0,0 +1,131 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"hub_management_service/internal/service"
+	"io"
+	"log"
+	"net/http"
+)
+
+// IdempotencyKeyHeader names the header carrying the idempotency key of a create request
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// MaxIdempotentBodyBytes bounds the body buffered to fingerprint a request, as large as the largest body a handler
+// behind the middleware accepts, an import file
+const MaxIdempotentBodyBytes = 32 << 20
+
+// replayedHeaders are the response headers recorded along with the body
+var replayedHeaders = []string{"Content-Type", "Location", "ETag"}
+
+// Idempotency makes create requests sent with an Idempotency-Key header safe to retry: the response to the first
+// request is recorded and replayed to retries with the same key and body. Keys are scoped to the authenticated
+// username, so the middleware must run after AuthMiddleware.
+func Idempotency(idempotency service.IdempotencyService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key, ok := c.Request.Header[IdempotencyKeyHeader]
+		if !ok {
+			c.Next()
+			return
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, MaxIdempotentBodyBytes))
+		var tooLarge *http.MaxBytesError
+		switch {
+		case errors.As(err, &tooLarge):
+			c.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, gin.H{"error": "the request body must not exceed 32 MiB"})
+			return
+		case err != nil:
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+		scope := c.GetString(UsernameKey)
+
+		response, lease, err := idempotency.Begin(scope, key[0], fingerprint(c.Request, body))
+		switch {
+		case errors.Is(err, service.ErrInvalidIdempotencyKey):
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		case errors.Is(err, service.ErrIdempotencyKeyReused):
+			c.AbortWithStatusJSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+			return
+		case errors.Is(err, service.ErrIdempotencyKeyInProgress):
+			c.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		case err != nil:
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		case response != nil:
+			for name, values := range response.Header {
+				c.Writer.Header()[name] = values
+			}
+			c.Header("Idempotent-Replayed", "true")
+			c.Writer.WriteHeader(response.StatusCode)
+			c.Writer.Write(response.Body)
+			c.Abort()
+			return
+		}
+
+		recorder := &recordingWriter{ResponseWriter: c.Writer}
+		c.Writer = recorder
+		// A panicking handler recorded nothing, the key is freed for the retry before the panic goes on
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				if err := idempotency.Abandon(scope, key[0], lease); err != nil {
+					log.Printf("abandoning idempotency key %q: %v", key[0], err)
+				}
+				panic(recovered)
+			}
+		}()
+		c.Next()
+
+		// Server errors are not recorded, the request may succeed when retried
+		if recorder.Status() >= http.StatusInternalServerError {
+			err = idempotency.Abandon(scope, key[0], lease)
+		} else {
+			header := http.Header{}
+			for _, name := range replayedHeaders {
+				if value := recorder.Header().Get(name); value != "" {
+					header.Set(name, value)
+				}
+			}
+			err = idempotency.Complete(scope, key[0], lease, &service.IdempotentResponse{
+				StatusCode: recorder.Status(),
+				Header:     header,
+				Body:       recorder.body.Bytes(),
+			})
+		}
+		if err != nil {
+			log.Printf("recording the response of idempotency key %q: %v", key[0], err)
+		}
+	}
+}
+
+// fingerprint identifies a request by its method, URL and body
+func fingerprint(r *http.Request, body []byte) string {
+	hash := sha256.New()
+	io.WriteString(hash, r.Method+" "+r.URL.RequestURI()+"\n")
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// recordingWriter keeps a copy of the response body
+type recordingWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *recordingWriter) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *recordingWriter) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
@@ -0,0 +1,145 @@
+package middleware
+
+import (
+	"bytes"
+	"hub_management_service/internal/service"
+	"hub_management_service/internal/service/mocks"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// newIdempotentRouter serves POST /users behind the middleware, creating user 7 or failing with 500 for "boom"
+func newIdempotentRouter(idempotency service.IdempotencyService, calls *int) *gin.Engine {
+	router := gin.New()
+	router.POST("/users", func(c *gin.Context) { c.Set(UsernameKey, "admin") }, Idempotency(idempotency), func(c *gin.Context) {
+		*calls++
+		var body struct{ Name string }
+		c.ShouldBindJSON(&body)
+		if body.Name == "boom" {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "database error"})
+			return
+		}
+		c.Header("Location", "/users/7")
+		c.JSON(http.StatusCreated, gin.H{"id": 7})
+	})
+	return router
+}
+
+func postUser(router *gin.Engine, key, body string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest("POST", "/users", bytes.NewBufferString(body))
+	if key != "" {
+		req.Header.Set(IdempotencyKeyHeader, key)
+	}
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	return resp
+}
+
+// TestIdempotency_RecordsResponse tests that the first request runs and its response is recorded
+func TestIdempotency_RecordsResponse(t *testing.T) {
+	idempotency := new(mocks.IdempotencyService)
+	var calls int
+	router := newIdempotentRouter(idempotency, &calls)
+
+	idempotency.On("Begin", "admin", "key-1", mock.AnythingOfType("string")).Return(nil, "lease-1", nil)
+	idempotency.On("Complete", "admin", "key-1", "lease-1", mock.MatchedBy(func(response *service.IdempotentResponse) bool {
+		return response.StatusCode == http.StatusCreated && string(response.Body) == `{"id":7}` &&
+			response.Header.Get("Location") == "/users/7"
+	})).Return(nil)
+
+	resp := postUser(router, "key-1", `{"name": "Jane"}`)
+	assert.Equal(t, http.StatusCreated, resp.Code)
+	assert.Equal(t, 1, calls)
+	idempotency.AssertExpectations(t)
+
+	// Requests without a key are not recorded
+	resp = postUser(router, "", `{"name": "Jane"}`)
+	assert.Equal(t, http.StatusCreated, resp.Code)
+	assert.Equal(t, 2, calls)
+	idempotency.AssertNumberOfCalls(t, "Begin", 1)
+}
+
+// TestIdempotency_Replay tests that a retry gets the recorded response without running again
+func TestIdempotency_Replay(t *testing.T) {
+	idempotency := new(mocks.IdempotencyService)
+	var calls int
+	router := newIdempotentRouter(idempotency, &calls)
+
+	idempotency.On("Begin", "admin", "key-1", mock.AnythingOfType("string")).Return(&service.IdempotentResponse{
+		StatusCode: http.StatusCreated,
+		Header:     http.Header{"Content-Type": {"application/json; charset=utf-8"}, "Location": {"/users/7"}},
+		Body:       []byte(`{"id":7}`),
+	}, "", nil)
+
+	resp := postUser(router, "key-1", `{"name": "Jane"}`)
+	assert.Equal(t, http.StatusCreated, resp.Code)
+	assert.Equal(t, `{"id":7}`, resp.Body.String())
+	assert.Equal(t, "/users/7", resp.Header().Get("Location"))
+	assert.Equal(t, "true", resp.Header().Get("Idempotent-Replayed"))
+	assert.Zero(t, calls)
+}
+
+// TestIdempotency_Errors tests that reused keys, retries in progress and server errors are handled
+func TestIdempotency_Errors(t *testing.T) {
+	idempotency := new(mocks.IdempotencyService)
+	var calls int
+	router := newIdempotentRouter(idempotency, &calls)
+
+	idempotency.On("Begin", "admin", "reused", mock.Anything).Return(nil, "", service.ErrIdempotencyKeyReused)
+	idempotency.On("Begin", "admin", "running", mock.Anything).Return(nil, "", service.ErrIdempotencyKeyInProgress)
+	idempotency.On("Begin", "admin", "failing", mock.Anything).Return(nil, "lease-1", nil)
+	idempotency.On("Abandon", "admin", "failing", "lease-1").Return(nil)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, postUser(router, "reused", `{"name": "Jane"}`).Code)
+	assert.Equal(t, http.StatusConflict, postUser(router, "running", `{"name": "Jane"}`).Code)
+	assert.Zero(t, calls)
+
+	assert.Equal(t, http.StatusInternalServerError, postUser(router, "failing", `{"name": "boom"}`).Code)
+	assert.Equal(t, 1, calls)
+	idempotency.AssertNotCalled(t, "Complete", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	idempotency.AssertExpectations(t)
+}
+
+// TestIdempotency_Panic tests that the key of a request whose handler panics is abandoned, and the panic goes on
+func TestIdempotency_Panic(t *testing.T) {
+	idempotency := new(mocks.IdempotencyService)
+	router := gin.New()
+	router.POST("/users", func(c *gin.Context) { c.Set(UsernameKey, "admin") }, Idempotency(idempotency), func(c *gin.Context) {
+		panic("nil map")
+	})
+
+	idempotency.On("Begin", "admin", "key-1", mock.Anything).Return(nil, "lease-1", nil)
+	idempotency.On("Abandon", "admin", "key-1", "lease-1").Return(nil)
+
+	assert.PanicsWithValue(t, "nil map", func() { postUser(router, "key-1", `{"name": "Jane"}`) })
+	idempotency.AssertNotCalled(t, "Complete", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	idempotency.AssertExpectations(t)
+}
+
+// TestIdempotency_TooLarge tests that a keyed body over the limit is rejected with 413 before it is buffered whole
+func TestIdempotency_TooLarge(t *testing.T) {
+	idempotency := new(mocks.IdempotencyService)
+	var calls int
+	router := newIdempotentRouter(idempotency, &calls)
+
+	resp := postUser(router, "key-1", `{"name": "`+strings.Repeat("a", MaxIdempotentBodyBytes)+`"}`)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, resp.Code)
+	assert.Zero(t, calls)
+	idempotency.AssertNotCalled(t, "Begin", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// TestFingerprint tests that requests differing in URL or body have different fingerprints
+func TestFingerprint(t *testing.T) {
+	users, _ := http.NewRequest("POST", "/users", nil)
+	override, _ := http.NewRequest("POST", "/users?override_capacity=true", nil)
+	jane := fingerprint(users, []byte(`{"name": "Jane"}`))
+	assert.Equal(t, jane, fingerprint(users, []byte(`{"name": "Jane"}`)))
+	assert.NotEqual(t, jane, fingerprint(users, []byte(`{"name": "John"}`)))
+	assert.NotEqual(t, jane, fingerprint(override, []byte(`{"name": "Jane"}`)))
+}
@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"hub_management_service/internal/entity"
+	"time"
+)
+
+type AttendanceRepository interface {
+	CheckIn(visit *entity.Visit) error
+	FindOpenVisit(userID, hubID uint) (*entity.Visit, error)
+	UpdateVisit(visit *entity.Visit) error
+	FindVisits(hubID uint, date entity.Date) ([]entity.Visit, error)
+	FindDaily(opts ListOptions) ([]entity.DailyAttendance, error)
+	DeleteVisitsBefore(cutoff time.Time) (int64, error)
+}
+
+type attendanceRepository struct {
+	db *gorm.DB
+}
+
+func NewAttendanceRepository(db *gorm.DB) AttendanceRepository {
+	return &attendanceRepository{db: db}
+}
+
+// CheckIn - Method to record a visit, checking the user out of any visit still open and counting
+// the user in the daily attendance of their team if it is their first visit of the day at the hub.
+// The first visit is the one that records the user as a visitor of the day, so concurrent check-ins count once.
+func (r *attendanceRepository) CheckIn(visit *entity.Visit) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&entity.Visit{}).Where("user_id = ? AND checked_out_at IS NULL", visit.UserID).
+			Update("checked_out_at", visit.CheckedInAt).Error
+		if err != nil {
+			return err
+		}
+		if err := tx.Omit("User", "Hub").Create(visit).Error; err != nil {
+			return err
+		}
+
+		first := tx.Clauses(clause.OnConflict{DoNothing: true}).
+			Create(&entity.DailyVisitor{Date: visit.Date, HubID: visit.HubID, UserID: visit.UserID})
+		if first.Error != nil || first.RowsAffected == 0 {
+			return first.Error
+		}
+
+		return tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "date"}, {Name: "hub_id"}, {Name: "team_id"}},
+			DoUpdates: clause.Assignments(map[string]interface{}{"users": gorm.Expr("attendance_daily.users + 1")}),
+		}).Create(&entity.DailyAttendance{Date: visit.Date, HubID: visit.HubID, TeamID: visit.TeamID, Users: 1}).Error
+	})
+}
+
+// FindOpenVisit - Method to find the visit of a user at a hub that has not been checked out, returns nil if there is none
+func (r *attendanceRepository) FindOpenVisit(userID, hubID uint) (*entity.Visit, error) {
+	var visits []entity.Visit
+	err := r.db.Where("user_id = ? AND hub_id = ? AND checked_out_at IS NULL", userID, hubID).
+		Order("checked_in_at DESC").Limit(1).Find(&visits).Error
+	if err != nil || len(visits) == 0 {
+		return nil, err
+	}
+	return &visits[0], nil
+}
+
+func (r *attendanceRepository) UpdateVisit(visit *entity.Visit) error {
+	return r.db.Omit("User", "Hub").Save(visit).Error
+}
+
+// FindVisits - Method to find the visits of a hub on a day along with their users, ordered by check-in
+func (r *attendanceRepository) FindVisits(hubID uint, date entity.Date) ([]entity.Visit, error) {
+	var visits []entity.Visit
+	err := r.db.Preload("User").Where("hub_id = ? AND date = ?", hubID, date).Order("checked_in_at, id").Find(&visits).Error
+	return visits, err
+}
+
+// FindDaily - Method to find the daily attendance matching the options, ordered by day, hub and team
+func (r *attendanceRepository) FindDaily(opts ListOptions) ([]entity.DailyAttendance, error) {
+	var daily []entity.DailyAttendance
+	err := opts.apply(r.db).Order("date, hub_id, team_id").Find(&daily).Error
+	return daily, err
+}
+
+// DeleteVisitsBefore - Method to purge the visits checked in before a time, along with the visitors of the days
+// before it, returns the number of purged visits
+func (r *attendanceRepository) DeleteVisitsBefore(cutoff time.Time) (int64, error) {
+	result := r.db.Where("checked_in_at < ?", cutoff).Delete(&entity.Visit{})
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	day := entity.NewDate(cutoff.Year(), cutoff.Month(), cutoff.Day())
+	if err := r.db.Where("date < ?", day).Delete(&entity.DailyVisitor{}).Error; err != nil {
+		return 0, err
+	}
+	return result.RowsAffected, nil
+}
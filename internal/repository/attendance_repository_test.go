@@ -0,0 +1,133 @@
+package repository
+
+import (
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"hub_management_service/internal/entity"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type AttendanceRepositoryTestSuite struct {
+	suite.Suite
+	DB             *gorm.DB
+	AttendanceRepo AttendanceRepository
+	Hub            *entity.Hub
+	User           *entity.User
+}
+
+func (suite *AttendanceRepositoryTestSuite) SetupTest() {
+	// Create an in-memory SQLite database
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		suite.T().Fatal("failed to connect to database")
+	}
+	// Transactions must see the same in-memory database
+	sqlDB, _ := db.DB()
+	sqlDB.SetMaxOpenConns(1)
+	suite.DB = db
+
+	suite.DB.AutoMigrate(&entity.Hub{}, &entity.Team{}, &entity.User{}, &entity.Visit{}, &entity.DailyAttendance{}, &entity.DailyVisitor{})
+	suite.Hub = &entity.Hub{Name: "Paris Hub", Location: "Paris"}
+	suite.DB.Create(suite.Hub)
+	team := &entity.Team{Name: "Backend", HubID: suite.Hub.ID}
+	suite.DB.Create(team)
+	suite.User = &entity.User{Name: "Jane", Email: "jane@example.com", TeamID: team.ID}
+	suite.DB.Create(suite.User)
+
+	suite.AttendanceRepo = NewAttendanceRepository(suite.DB)
+}
+
+func (suite *AttendanceRepositoryTestSuite) TearDownTest() {
+	// Clean up the database
+	suite.DB.Exec("DELETE FROM attendance_daily")
+	suite.DB.Exec("DELETE FROM attendance_visitors")
+	suite.DB.Exec("DELETE FROM visits")
+	suite.DB.Exec("DELETE FROM users")
+	suite.DB.Exec("DELETE FROM teams")
+	suite.DB.Exec("DELETE FROM hubs")
+}
+
+func (suite *AttendanceRepositoryTestSuite) visit(day, hour int) *entity.Visit {
+	return &entity.Visit{
+		UserID:      suite.User.ID,
+		HubID:       suite.Hub.ID,
+		TeamID:      suite.User.TeamID,
+		Date:        entity.NewDate(2030, time.July, day),
+		CheckedInAt: time.Date(2030, time.July, day, hour, 0, 0, 0, time.UTC),
+	}
+}
+
+func (suite *AttendanceRepositoryTestSuite) TestCheckInCountsUsersOncePerDay() {
+	assert.NoError(suite.T(), suite.AttendanceRepo.CheckIn(suite.visit(15, 9)))
+	assert.NoError(suite.T(), suite.AttendanceRepo.CheckIn(suite.visit(15, 14))) // Back after lunch
+	assert.NoError(suite.T(), suite.AttendanceRepo.CheckIn(suite.visit(16, 9)))
+
+	daily, err := suite.AttendanceRepo.FindDaily(ListOptions{Where: "hub_id = ?", Args: []interface{}{suite.Hub.ID}})
+	assert.NoError(suite.T(), err)
+	if assert.Len(suite.T(), daily, 2) {
+		assert.Equal(suite.T(), "2030-07-15", daily[0].Date.String())
+		assert.Equal(suite.T(), int64(1), daily[0].Users)
+		assert.Equal(suite.T(), int64(1), daily[1].Users)
+	}
+}
+
+// TestCheckInCountsConcurrentFirstVisitsOnce tests that two first check-ins of the day, each in a transaction that
+// began before the other committed, count the user once
+func (suite *AttendanceRepositoryTestSuite) TestCheckInCountsConcurrentFirstVisitsOnce() {
+	// The visit of the other check-in is not seen yet, only the visitor key tells that it came first
+	suite.DB.Create(&entity.DailyVisitor{Date: entity.NewDate(2030, time.July, 15), HubID: suite.Hub.ID, UserID: suite.User.ID})
+	suite.DB.Create(&entity.DailyAttendance{Date: entity.NewDate(2030, time.July, 15), HubID: suite.Hub.ID, TeamID: suite.User.TeamID, Users: 1})
+
+	assert.NoError(suite.T(), suite.AttendanceRepo.CheckIn(suite.visit(15, 9)))
+
+	daily, err := suite.AttendanceRepo.FindDaily(ListOptions{Where: "hub_id = ?", Args: []interface{}{suite.Hub.ID}})
+	assert.NoError(suite.T(), err)
+	if assert.Len(suite.T(), daily, 1) {
+		assert.Equal(suite.T(), int64(1), daily[0].Users)
+	}
+}
+
+func (suite *AttendanceRepositoryTestSuite) TestCheckInClosesOpenVisits() {
+	suite.AttendanceRepo.CheckIn(suite.visit(15, 9))
+	suite.AttendanceRepo.CheckIn(suite.visit(15, 14))
+
+	visits, err := suite.AttendanceRepo.FindVisits(suite.Hub.ID, entity.NewDate(2030, time.July, 15))
+	assert.NoError(suite.T(), err)
+	if assert.Len(suite.T(), visits, 2) {
+		assert.Equal(suite.T(), "Jane", visits[0].User.Name)
+		if assert.NotNil(suite.T(), visits[0].CheckedOutAt) {
+			assert.Equal(suite.T(), 14, visits[0].CheckedOutAt.UTC().Hour())
+		}
+		assert.Nil(suite.T(), visits[1].CheckedOutAt)
+	}
+
+	open, err := suite.AttendanceRepo.FindOpenVisit(suite.User.ID, suite.Hub.ID)
+	assert.NoError(suite.T(), err)
+	if assert.NotNil(suite.T(), open) {
+		assert.Equal(suite.T(), visits[1].ID, open.ID)
+	}
+}
+
+func (suite *AttendanceRepositoryTestSuite) TestDeleteVisitsBefore() {
+	suite.AttendanceRepo.CheckIn(suite.visit(15, 9))
+	suite.AttendanceRepo.CheckIn(suite.visit(16, 9))
+
+	deleted, err := suite.AttendanceRepo.DeleteVisitsBefore(time.Date(2030, time.July, 16, 0, 0, 0, 0, time.UTC))
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), int64(1), deleted)
+
+	// Daily attendance outlives the visits, the visitors go with them
+	daily, _ := suite.AttendanceRepo.FindDaily(ListOptions{})
+	assert.Len(suite.T(), daily, 2)
+	var visitors int64
+	suite.DB.Model(&entity.DailyVisitor{}).Count(&visitors)
+	assert.Equal(suite.T(), int64(1), visitors)
+}
+
+func TestAttendanceRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(AttendanceRepositoryTestSuite))
+}
@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"errors"
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+	"hub_management_service/internal/entity"
+	"time"
+)
+
+// ErrBookingOverlap is returned when a booking overlaps an active booking of the same resource
+var ErrBookingOverlap = errors.New("booking overlaps another booking of the resource")
+
+// exclusionViolation is the Postgres error code of a violated exclusion constraint
+const exclusionViolation = "23P01"
+
+type BookingRepository interface {
+	Create(booking *entity.Booking) error
+	FindByID(id uint) (*entity.Booking, error)
+	FindActive(resourceIDs []uint, from, to time.Time) ([]entity.Booking, error)
+	Update(booking *entity.Booking) error
+}
+
+type bookingRepository struct {
+	db *gorm.DB
+}
+
+func NewBookingRepository(db *gorm.DB) BookingRepository {
+	return &bookingRepository{db: db}
+}
+
+// Create - Method to create a booking, returns ErrBookingOverlap if the resource is already booked at that time
+func (r *bookingRepository) Create(booking *entity.Booking) error {
+	if r.db.Dialector.Name() == "postgres" {
+		return r.createExclusive(booking)
+	}
+	return r.createLocked(booking)
+}
+
+// createExclusive relies on the bookings_no_overlap exclusion constraint to reject overlaps
+func (r *bookingRepository) createExclusive(booking *entity.Booking) error {
+	err := r.db.Omit("Resource", "User").Create(booking).Error
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == exclusionViolation {
+		return ErrBookingOverlap
+	}
+	return err
+}
+
+// createLocked checks for overlaps and inserts under the database write lock, for databases without exclusion constraints
+func (r *bookingRepository) createLocked(booking *entity.Booking) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		// Writing first takes SQLite's write lock, so concurrent bookings wait for this check to finish
+		if err := tx.Exec("UPDATE hub_resources SET id = id WHERE id = ?", booking.ResourceID).Error; err != nil {
+			return err
+		}
+		var overlapping int64
+		err := tx.Model(&entity.Booking{}).
+			Where("resource_id = ? AND cancelled_at IS NULL AND starts_at < ? AND ends_at > ?", booking.ResourceID, booking.EndsAt, booking.StartsAt).
+			Count(&overlapping).Error
+		if err != nil {
+			return err
+		}
+		if overlapping > 0 {
+			return ErrBookingOverlap
+		}
+		return tx.Omit("Resource", "User").Create(booking).Error
+	})
+}
+
+func (r *bookingRepository) FindByID(id uint) (*entity.Booking, error) {
+	var booking entity.Booking
+	err := r.db.First(&booking, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &booking, nil
+}
+
+// FindActive - Method to find the bookings of resources that are not cancelled and overlap a time range, ordered by start
+func (r *bookingRepository) FindActive(resourceIDs []uint, from, to time.Time) ([]entity.Booking, error) {
+	var bookings []entity.Booking
+	err := r.db.Where("resource_id IN ? AND cancelled_at IS NULL AND starts_at < ? AND ends_at > ?", resourceIDs, to, from).
+		Order("starts_at, resource_id").Find(&bookings).Error
+	return bookings, err
+}
+
+func (r *bookingRepository) Update(booking *entity.Booking) error {
+	return r.db.Omit("Resource", "User").Save(booking).Error
+}
@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"hub_management_service/internal/entity"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type BookingRepositoryTestSuite struct {
+	suite.Suite
+	DB          *gorm.DB
+	BookingRepo BookingRepository
+	Resource    *entity.Resource
+	User        *entity.User
+}
+
+func (suite *BookingRepositoryTestSuite) SetupTest() {
+	// Create an in-memory SQLite database
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		suite.T().Fatal("failed to connect to database")
+	}
+	// Transactions must see the same in-memory database
+	sqlDB, _ := db.DB()
+	sqlDB.SetMaxOpenConns(1)
+	suite.DB = db
+
+	suite.DB.AutoMigrate(&entity.Hub{}, &entity.Team{}, &entity.User{}, &entity.Resource{}, &entity.Amenity{}, &entity.Booking{})
+	hub := &entity.Hub{Name: "Paris Hub", Location: "Paris"}
+	suite.DB.Create(hub)
+	team := &entity.Team{Name: "Backend", HubID: hub.ID}
+	suite.DB.Create(team)
+	suite.User = &entity.User{Name: "Jane", Email: "jane@example.com", TeamID: team.ID}
+	suite.DB.Create(suite.User)
+	suite.Resource = &entity.Resource{HubID: hub.ID, Type: entity.ResourceRoom, Name: "Seine", Capacity: seats(8)}
+	suite.DB.Create(suite.Resource)
+
+	suite.BookingRepo = NewBookingRepository(suite.DB)
+}
+
+func (suite *BookingRepositoryTestSuite) TearDownTest() {
+	// Clean up the database
+	suite.DB.Exec("DELETE FROM bookings")
+	suite.DB.Exec("DELETE FROM hub_resources")
+	suite.DB.Exec("DELETE FROM users")
+	suite.DB.Exec("DELETE FROM teams")
+	suite.DB.Exec("DELETE FROM hubs")
+}
+
+func (suite *BookingRepositoryTestSuite) booking(startHour, endHour int) *entity.Booking {
+	day := time.Date(2030, time.July, 15, 0, 0, 0, 0, time.UTC)
+	return &entity.Booking{
+		ResourceID: suite.Resource.ID,
+		UserID:     suite.User.ID,
+		StartsAt:   day.Add(time.Duration(startHour) * time.Hour),
+		EndsAt:     day.Add(time.Duration(endHour) * time.Hour),
+	}
+}
+
+func (suite *BookingRepositoryTestSuite) TestCreateRejectsOverlap() {
+	first := suite.booking(9, 11)
+	assert.NoError(suite.T(), suite.BookingRepo.Create(first))
+
+	assert.ErrorIs(suite.T(), suite.BookingRepo.Create(suite.booking(10, 12)), ErrBookingOverlap)
+	assert.ErrorIs(suite.T(), suite.BookingRepo.Create(suite.booking(8, 12)), ErrBookingOverlap)
+	// Ranges are half-open, so back-to-back bookings do not overlap
+	assert.NoError(suite.T(), suite.BookingRepo.Create(suite.booking(11, 12)))
+
+	// A cancelled booking frees its range
+	cancelledAt := time.Now()
+	first.CancelledAt = &cancelledAt
+	assert.NoError(suite.T(), suite.BookingRepo.Update(first))
+	assert.NoError(suite.T(), suite.BookingRepo.Create(suite.booking(9, 10)))
+}
+
+func (suite *BookingRepositoryTestSuite) TestFindActive() {
+	suite.BookingRepo.Create(suite.booking(13, 14))
+	suite.BookingRepo.Create(suite.booking(9, 10))
+	suite.BookingRepo.Create(suite.booking(30, 31)) // The next day
+
+	day := time.Date(2030, time.July, 15, 0, 0, 0, 0, time.UTC)
+	bookings, err := suite.BookingRepo.FindActive([]uint{suite.Resource.ID}, day, day.AddDate(0, 0, 1))
+	assert.NoError(suite.T(), err)
+	if assert.Len(suite.T(), bookings, 2) {
+		assert.Equal(suite.T(), 9, bookings[0].StartsAt.UTC().Hour())
+		assert.Equal(suite.T(), 13, bookings[1].StartsAt.UTC().Hour())
+	}
+}
+
+func TestBookingRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(BookingRepositoryTestSuite))
+}
@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"errors"
+	"gorm.io/gorm"
+	"hub_management_service/internal/entity"
+)
+
+type CountryRepository interface {
+	Create(country *entity.Country) error
+	FindAll() ([]entity.Country, error)
+	FindByRegionID(regionID uint) ([]entity.Country, error)
+	FindByID(id uint) (*entity.Country, error)
+	FindByCode(code string) (*entity.Country, error)
+	List(opts ListOptions) ([]entity.Country, int64, error)
+	Update(country *entity.Country) error
+	Delete(id uint) error
+}
+
+type countryRepository struct {
+	db *gorm.DB
+}
+
+func NewCountryRepository(db *gorm.DB) CountryRepository {
+	return &countryRepository{db: db}
+}
+
+func (r *countryRepository) Create(country *entity.Country) error {
+	return r.db.Omit("Region").Create(country).Error
+}
+
+// FindAll - Method to find all countries, ordered by name
+func (r *countryRepository) FindAll() ([]entity.Country, error) {
+	var countries []entity.Country
+	err := r.db.Order("name").Find(&countries).Error
+	return countries, err
+}
+
+// FindByRegionID - Method to find the countries of a region, ordered by name
+func (r *countryRepository) FindByRegionID(regionID uint) ([]entity.Country, error) {
+	var countries []entity.Country
+	err := r.db.Where("region_id = ?", regionID).Order("name").Find(&countries).Error
+	return countries, err
+}
+
+// FindByID - Method to find a country along with its region
+func (r *countryRepository) FindByID(id uint) (*entity.Country, error) {
+	var country entity.Country
+	err := r.db.Preload("Region").First(&country, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &country, nil
+}
+
+// FindByCode - Method to find a country by its ISO code, returns nil if no country matches
+func (r *countryRepository) FindByCode(code string) (*entity.Country, error) {
+	var country entity.Country
+	err := r.db.Where("code = ?", code).First(&country).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &country, nil
+}
+
+// List - Method to find a page of countries matching the options ordered by name, and the total number of matches
+func (r *countryRepository) List(opts ListOptions) ([]entity.Country, int64, error) {
+	var total int64
+	if err := (ListOptions{Where: opts.Where, Args: opts.Args}).apply(r.db.Model(&entity.Country{})).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var countries []entity.Country
+	err := opts.apply(r.db).Order("name").Find(&countries).Error
+	return countries, total, err
+}
+
+func (r *countryRepository) Update(country *entity.Country) error {
+	return r.db.Omit("Region").Save(country).Error
+}
+
+func (r *countryRepository) Delete(id uint) error {
+	return r.db.Delete(&entity.Country{}, id).Error
+}
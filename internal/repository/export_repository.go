@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"gorm.io/gorm"
+	"hub_management_service/internal/entity"
+	"time"
+)
+
+// OrgRow is a user along with their team and hub, a row of an organisation export
+type OrgRow struct {
+	UserID         uint
+	UserName       string
+	Email          string
+	JobTitle       string
+	Phone          string
+	EmployeeNumber *string
+	StartDate      *entity.Date
+	EmploymentType string
+	Locale         string
+	TimeZone       string
+	ManagerID      *uint
+	DeactivatedAt  *time.Time
+	TeamID         uint
+	TeamName       string
+	ParentTeamID   *uint
+	HubID          uint
+	HubName        string
+	HubLocation    string
+	HubCity        string
+	CountryCode    *string
+}
+
+type ExportRepository interface {
+	StreamOrg(opts ListOptions, fn func(row *OrgRow) error) error
+}
+
+type exportRepository struct {
+	db *gorm.DB
+}
+
+func NewExportRepository(db *gorm.DB) ExportRepository {
+	return &exportRepository{db: db}
+}
+
+// orgColumns selects the columns of OrgRow, conditions may refer to the users, teams and hubs tables
+const orgColumns = `users.id AS user_id, users.name AS user_name, users.email, users.job_title, users.phone,
+	users.employee_number, users.start_date, users.employment_type, users.locale, users.time_zone, users.manager_id,
+	users.deactivated_at, teams.id AS team_id, teams.name AS team_name, teams.parent_id AS parent_team_id,
+	hubs.id AS hub_id, hubs.name AS hub_name, hubs.location AS hub_location, hubs.city AS hub_city,
+	countries.code AS country_code`
+
+// StreamOrg - Method to pass every user matching the options to fn one row at a time, ordered by hub, team and name,
+// so that exports never hold all rows in memory. Iteration stops at the first error returned by fn.
+func (r *exportRepository) StreamOrg(opts ListOptions, fn func(row *OrgRow) error) error {
+	query := opts.apply(r.db.Table("users").Select(orgColumns).
+		Joins("JOIN teams ON teams.id = users.team_id").
+		Joins("JOIN hubs ON hubs.id = teams.hub_id").
+		Joins("LEFT JOIN countries ON countries.id = hubs.country_id")).
+		Order("hubs.name, hubs.id, teams.name, teams.id, users.name, users.id")
+	rows, err := query.Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var row OrgRow
+		if err := r.db.ScanRows(rows, &row); err != nil {
+			return err
+		}
+		if err := fn(&row); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
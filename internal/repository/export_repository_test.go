@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"hub_management_service/internal/entity"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type ExportRepositoryTestSuite struct {
+	suite.Suite
+	DB         *gorm.DB
+	ExportRepo ExportRepository
+	Berlin     *entity.Team
+}
+
+func (suite *ExportRepositoryTestSuite) SetupTest() {
+	// Create an in-memory SQLite database
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		suite.T().Fatal("failed to connect to database")
+	}
+	suite.DB = db
+
+	suite.DB.AutoMigrate(&entity.Region{}, &entity.Country{}, &entity.Hub{}, &entity.Team{}, &entity.User{})
+	region := &entity.Region{Name: "Europe"}
+	suite.DB.Create(region)
+	country := &entity.Country{Name: "Germany", Code: "DE", RegionID: region.ID}
+	suite.DB.Create(country)
+	paris := &entity.Hub{Name: "Paris Hub", Location: "Paris"}
+	berlin := &entity.Hub{Name: "Berlin Hub", Location: "Berlin", CountryID: &country.ID}
+	suite.DB.Create(paris)
+	suite.DB.Create(berlin)
+	backend := &entity.Team{Name: "Backend", HubID: paris.ID}
+	suite.Berlin = &entity.Team{Name: "Platform", HubID: berlin.ID}
+	suite.DB.Create(backend)
+	suite.DB.Create(suite.Berlin)
+	suite.DB.Create(&entity.User{Name: "Zoe", Email: "zoe@example.com", TeamID: backend.ID})
+	suite.DB.Create(&entity.User{Name: "Adam", Email: "adam@example.com", TeamID: backend.ID})
+	suite.DB.Create(&entity.User{Name: "Jane", Email: "jane@example.com", TeamID: suite.Berlin.ID})
+
+	suite.ExportRepo = NewExportRepository(suite.DB)
+}
+
+func (suite *ExportRepositoryTestSuite) TearDownTest() {
+	// Clean up the database
+	suite.DB.Exec("DELETE FROM users")
+	suite.DB.Exec("DELETE FROM teams")
+	suite.DB.Exec("DELETE FROM hubs")
+	suite.DB.Exec("DELETE FROM countries")
+	suite.DB.Exec("DELETE FROM regions")
+}
+
+func (suite *ExportRepositoryTestSuite) TestStreamOrgOrdersByHubTeamAndName() {
+	var rows []OrgRow
+	err := suite.ExportRepo.StreamOrg(ListOptions{}, func(row *OrgRow) error {
+		rows = append(rows, *row)
+		return nil
+	})
+	assert.NoError(suite.T(), err)
+	if assert.Len(suite.T(), rows, 3) {
+		assert.Equal(suite.T(), "Jane", rows[0].UserName)
+		assert.Equal(suite.T(), "Berlin Hub", rows[0].HubName)
+		assert.Equal(suite.T(), "Platform", rows[0].TeamName)
+		if assert.NotNil(suite.T(), rows[0].CountryCode) {
+			assert.Equal(suite.T(), "DE", *rows[0].CountryCode)
+		}
+		assert.Equal(suite.T(), "Adam", rows[1].UserName)
+		assert.Nil(suite.T(), rows[1].CountryCode)
+		assert.Equal(suite.T(), "zoe@example.com", rows[2].Email)
+	}
+}
+
+func (suite *ExportRepositoryTestSuite) TestStreamOrgFilters() {
+	var names []string
+	err := suite.ExportRepo.StreamOrg(ListOptions{Where: "teams.id IN ?", Args: []interface{}{[]uint{suite.Berlin.ID}}}, func(row *OrgRow) error {
+		names = append(names, row.UserName)
+		return nil
+	})
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), []string{"Jane"}, names)
+}
+
+func TestExportRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(ExportRepositoryTestSuite))
+}
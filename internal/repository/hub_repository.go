@@ -10,6 +10,11 @@ type HubRepository interface {
 	FindAll() ([]entity.Hub, error)
 	FindByID(id uint) (*entity.Hub, error)
 	SearchByName(name string) ([]entity.Hub, error)
+	Update(hub *entity.Hub) error
+	Delete(id, version uint) error
+	CountTeams(hubID uint) (int64, error)
+	List(opts ListOptions) ([]entity.Hub, int64, error)
+	CountUsers(hubIDs []uint) (map[uint]int64, error)
 }
 
 type hubRepository struct {
@@ -21,7 +26,7 @@ func NewHubRepository(db *gorm.DB) HubRepository {
 }
 
 func (r *hubRepository) Create(hub *entity.Hub) error {
-	return r.db.Create(hub).Error
+	return r.db.Omit("Country").Create(hub).Error
 }
 
 func (r *hubRepository) FindAll() ([]entity.Hub, error) {
@@ -30,10 +35,11 @@ func (r *hubRepository) FindAll() ([]entity.Hub, error) {
 	return hubs, err
 }
 
+// FindByID finds a hub, locking it until the end of the transaction when called within one
 func (r *hubRepository) FindByID(id uint) (*entity.Hub, error) {
 	var hub entity.Hub
 	// Use First to find a record by ID
-	err := r.db.First(&hub, id).Error
+	err := forUpdate(r.db).First(&hub, id).Error
 	if err != nil {
 		return nil, err
 	}
@@ -42,7 +48,56 @@ func (r *hubRepository) FindByID(id uint) (*entity.Hub, error) {
 
 func (r *hubRepository) SearchByName(name string) ([]entity.Hub, error) {
 	var hubs []entity.Hub
-	// Preload related teams and search for hubs by name
-	err := r.db.Preload("Teams").Where("name LIKE ?", "%"+name+"%").Find(&hubs).Error
+	// Search for hubs by name, ignoring case; their teams are expanded on request
+	err := r.db.Where("LOWER(name) LIKE LOWER(?)", "%"+name+"%").Find(&hubs).Error
 	return hubs, err
 }
+
+// Update saves the hub, returns ErrStaleVersion if it was changed since it was read
+func (r *hubRepository) Update(hub *entity.Hub) error {
+	return saveVersioned(r.db, hub, &hub.Version, "Country", "Teams", "Resources")
+}
+
+// List finds a page of hubs matching the options along with their country, and the total number of matches
+func (r *hubRepository) List(opts ListOptions) ([]entity.Hub, int64, error) {
+	var total int64
+	if err := (ListOptions{Where: opts.Where, Args: opts.Args}).apply(r.db.Model(&entity.Hub{})).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var hubs []entity.Hub
+	err := opts.apply(r.db.Preload("Country")).Order("id").Find(&hubs).Error
+	return hubs, total, err
+}
+
+// Delete deletes a hub by its ID while it is still at version
+func (r *hubRepository) Delete(id, version uint) error {
+	return deleteVersioned(r.db, &entity.Hub{}, id, version)
+}
+
+// CountTeams counts the teams of a hub
+func (r *hubRepository) CountTeams(hubID uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&entity.Team{}).Where("hub_id = ?", hubID).Count(&count).Error
+	return count, err
+}
+
+// CountUsers counts the users whose primary team is in one of the given hubs, by hub
+func (r *hubRepository) CountUsers(hubIDs []uint) (map[uint]int64, error) {
+	var rows []struct {
+		HubID uint
+		Count int64
+	}
+	err := r.db.Model(&entity.User{}).Select("teams.hub_id AS hub_id, COUNT(*) AS count").
+		Joins("JOIN teams ON teams.id = users.team_id").
+		Where("teams.hub_id IN ?", hubIDs).Group("teams.hub_id").Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[uint]int64, len(rows))
+	for _, row := range rows {
+		counts[row.HubID] = row.Count
+	}
+	return counts, nil
+}
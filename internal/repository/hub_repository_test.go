@@ -24,8 +24,8 @@ func (suite *HubRepositoryTestSuite) SetupTest() {
 	}
 	suite.DB = db
 
-	// Auto-migrate the Hub entity
-	suite.DB.AutoMigrate(&entity.Hub{}, &entity.Team{}) // Add all related entities here
+	// Auto-migrate the Hub entity, and Team and User for headcounts
+	suite.DB.AutoMigrate(&entity.Hub{}, &entity.Team{}, &entity.User{}) // Add all related entities here
 
 	// Initialize the HubRepository
 	suite.HubRepo = NewHubRepository(suite.DB)
@@ -33,6 +33,8 @@ func (suite *HubRepositoryTestSuite) SetupTest() {
 
 func (suite *HubRepositoryTestSuite) TearDownTest() {
 	// Clean up the database
+	suite.DB.Exec("DELETE FROM users")
+	suite.DB.Exec("DELETE FROM teams")
 	suite.DB.Exec("DELETE FROM hubs")
 }
 
@@ -76,6 +78,87 @@ func (suite *HubRepositoryTestSuite) TestSearchHubByName() {
 	assert.NoError(suite.T(), err)
 	assert.Len(suite.T(), hubs, 1)
 	assert.Equal(suite.T(), "Test Hub", hubs[0].Name)
+
+	// Case is ignored
+	hubs, err = suite.HubRepo.SearchByName("test h")
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), hubs, 1)
+}
+
+func (suite *HubRepositoryTestSuite) TestUpdateHubAddressAndCoordinates() {
+	hub := &entity.Hub{Name: "Paris Hub", Location: "Paris"}
+	suite.HubRepo.Create(hub)
+
+	latitude, longitude := 48.8566, 2.3522
+	hub.Address = entity.Address{Street: "1 Rue de Rivoli", PostalCode: "75001"}
+	hub.Latitude, hub.Longitude = &latitude, &longitude
+	assert.NoError(suite.T(), suite.HubRepo.Update(hub))
+
+	// A wrapped longitude range as used around the antimeridian
+	hubs, _, err := suite.HubRepo.List(ListOptions{Where: "(longitude >= ? OR longitude <= ?)", Args: []interface{}{179.5, 2.5}})
+	assert.NoError(suite.T(), err)
+	if assert.Len(suite.T(), hubs, 1) {
+		assert.Equal(suite.T(), "75001", hubs[0].Address.PostalCode)
+		assert.Equal(suite.T(), latitude, *hubs[0].Latitude)
+	}
+}
+
+// TestUpdateHubVersion tests that updates increment the version and that a hub read before another update is not saved
+func (suite *HubRepositoryTestSuite) TestUpdateHubVersion() {
+	hub := &entity.Hub{Name: "Paris Hub", Location: "Paris"}
+	suite.HubRepo.Create(hub)
+	assert.Equal(suite.T(), uint(1), hub.Version)
+
+	stale, err := suite.HubRepo.FindByID(hub.ID)
+	assert.NoError(suite.T(), err)
+	hub.Name = "Paris Central"
+	assert.NoError(suite.T(), suite.HubRepo.Update(hub))
+	assert.Equal(suite.T(), uint(2), hub.Version)
+
+	stale.Name = "Paris Nord"
+	assert.ErrorIs(suite.T(), suite.HubRepo.Update(stale), ErrStaleVersion)
+	assert.Equal(suite.T(), uint(1), stale.Version)
+
+	saved, err := suite.HubRepo.FindByID(hub.ID)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "Paris Central", saved.Name)
+	assert.Equal(suite.T(), uint(2), saved.Version)
+}
+
+func (suite *HubRepositoryTestSuite) TestCountUsers() {
+	main, empty := &entity.Hub{Name: "Main Hub"}, &entity.Hub{Name: "Empty Hub"}
+	suite.HubRepo.Create(main)
+	suite.HubRepo.Create(empty)
+	backend, frontend := &entity.Team{Name: "Backend", HubID: main.ID}, &entity.Team{Name: "Frontend", HubID: main.ID}
+	suite.DB.Create(backend)
+	suite.DB.Create(frontend)
+	suite.DB.Create(&[]entity.User{
+		{Name: "Jane", Email: "jane@example.com", TeamID: backend.ID},
+		{Name: "John", Email: "john@example.com", TeamID: frontend.ID},
+	})
+
+	counts, err := suite.HubRepo.CountUsers([]uint{main.ID, empty.ID})
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), int64(2), counts[main.ID])
+	assert.Equal(suite.T(), int64(0), counts[empty.ID])
+}
+
+// TestDeleteHub tests that teams are counted per hub, that a hub is only deleted at its current version and that a
+// deleted hub is gone
+func (suite *HubRepositoryTestSuite) TestDeleteHub() {
+	hub := &entity.Hub{Name: "Paris Hub", Location: "Paris"}
+	suite.HubRepo.Create(hub)
+	suite.DB.Create(&entity.Team{Name: "Backend", HubID: hub.ID})
+
+	count, err := suite.HubRepo.CountTeams(hub.ID)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), int64(1), count)
+
+	suite.DB.Exec("DELETE FROM teams")
+	assert.ErrorIs(suite.T(), suite.HubRepo.Delete(hub.ID, hub.Version+1), ErrStaleVersion)
+	assert.NoError(suite.T(), suite.HubRepo.Delete(hub.ID, hub.Version))
+	_, err = suite.HubRepo.FindByID(hub.ID)
+	assert.True(suite.T(), IsNotFound(err))
 }
 
 func TestHubRepositoryTestSuite(t *testing.T) {
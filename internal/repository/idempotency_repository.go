@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"hub_management_service/internal/entity"
+	"time"
+)
+
+var (
+	// ErrIdempotencyKeyTaken is returned when a key of the scope is already recorded
+	ErrIdempotencyKeyTaken = errors.New("idempotency key is already recorded")
+	// ErrIdempotencyLeaseLost is returned when the lease of a key is no longer held by the given token, as when a
+	// retry took the key over from a request that outlived its lease
+	ErrIdempotencyLeaseLost = errors.New("idempotency key is leased by another request")
+)
+
+type IdempotencyRepository interface {
+	Create(record *entity.IdempotencyKey) error
+	Find(scope, key string) (*entity.IdempotencyKey, error)
+	TakeOver(record *entity.IdempotencyKey, now time.Time) error
+	SaveResponse(record *entity.IdempotencyKey) error
+	Release(record *entity.IdempotencyKey) error
+	Delete(scope, key string) error
+	DeleteExpired(now time.Time) (int64, error)
+}
+
+type idempotencyRepository struct {
+	db *gorm.DB
+}
+
+func NewIdempotencyRepository(db *gorm.DB) IdempotencyRepository {
+	return &idempotencyRepository{db: db}
+}
+
+// Create records a key, returns ErrIdempotencyKeyTaken if the scope already has it, as when two requests race
+func (r *idempotencyRepository) Create(record *entity.IdempotencyKey) error {
+	result := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(record)
+	if result.Error == nil && result.RowsAffected == 0 {
+		return ErrIdempotencyKeyTaken
+	}
+	return result.Error
+}
+
+// Find returns the record of a key, or gorm.ErrRecordNotFound
+func (r *idempotencyRepository) Find(scope, key string) (*entity.IdempotencyKey, error) {
+	var record entity.IdempotencyKey
+	if err := r.db.Where("scope = ? AND key = ?", scope, key).First(&record).Error; err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
+
+// TakeOver leases a key without a response whose lease is over at now to record.LeaseToken until record.LockedUntil,
+// returns ErrIdempotencyKeyTaken if the key has a response or is still leased, as when another retry took it over first
+func (r *idempotencyRepository) TakeOver(record *entity.IdempotencyKey, now time.Time) error {
+	result := r.db.Model(&entity.IdempotencyKey{}).
+		Where("scope = ? AND key = ? AND status_code = 0 AND (locked_until IS NULL OR locked_until <= ?)", record.Scope, record.Key, now).
+		Updates(map[string]interface{}{"locked_until": record.LockedUntil, "lease_token": record.LeaseToken})
+	if result.Error == nil && result.RowsAffected == 0 {
+		return ErrIdempotencyKeyTaken
+	}
+	return result.Error
+}
+
+// SaveResponse stores the status, headers and body of the response to the request of a key and ends its lease,
+// returns ErrIdempotencyLeaseLost unless the key is still leased to record.LeaseToken
+func (r *idempotencyRepository) SaveResponse(record *entity.IdempotencyKey) error {
+	result := r.db.Model(&entity.IdempotencyKey{}).
+		Where("scope = ? AND key = ? AND status_code = 0 AND lease_token = ?", record.Scope, record.Key, record.LeaseToken).
+		Updates(map[string]interface{}{"status_code": record.StatusCode, "header": record.Header, "body": record.Body,
+			"locked_until": nil})
+	if result.Error == nil && result.RowsAffected == 0 {
+		return ErrIdempotencyLeaseLost
+	}
+	return result.Error
+}
+
+// Release deletes a key without a response so that a retry runs its request again, returns ErrIdempotencyLeaseLost
+// unless the key is still leased to record.LeaseToken
+func (r *idempotencyRepository) Release(record *entity.IdempotencyKey) error {
+	result := r.db.Where("scope = ? AND key = ? AND status_code = 0 AND lease_token = ?", record.Scope, record.Key, record.LeaseToken).
+		Delete(&entity.IdempotencyKey{})
+	if result.Error == nil && result.RowsAffected == 0 {
+		return ErrIdempotencyLeaseLost
+	}
+	return result.Error
+}
+
+func (r *idempotencyRepository) Delete(scope, key string) error {
+	return r.db.Where("scope = ? AND key = ?", scope, key).Delete(&entity.IdempotencyKey{}).Error
+}
+
+// DeleteExpired purges the keys expired at now, returns the number of purged keys
+func (r *idempotencyRepository) DeleteExpired(now time.Time) (int64, error) {
+	result := r.db.Where("expires_at <= ?", now).Delete(&entity.IdempotencyKey{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,132 @@
+package repository
+
+import (
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"hub_management_service/internal/entity"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type IdempotencyRepositoryTestSuite struct {
+	suite.Suite
+	DB              *gorm.DB
+	IdempotencyRepo IdempotencyRepository
+}
+
+func (suite *IdempotencyRepositoryTestSuite) SetupTest() {
+	// Create an in-memory SQLite database
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		suite.T().Fatal("failed to connect to database")
+	}
+	suite.DB = db
+	suite.DB.AutoMigrate(&entity.IdempotencyKey{})
+	suite.IdempotencyRepo = NewIdempotencyRepository(suite.DB)
+}
+
+func (suite *IdempotencyRepositoryTestSuite) TearDownTest() {
+	// Clean up the database
+	suite.DB.Exec("DELETE FROM idempotency_keys")
+}
+
+func (suite *IdempotencyRepositoryTestSuite) key(scope, key string, expiresAt time.Time) *entity.IdempotencyKey {
+	return &entity.IdempotencyKey{Scope: scope, Key: key, Fingerprint: "abc", CreatedAt: expiresAt.Add(-time.Hour), ExpiresAt: expiresAt}
+}
+
+// TestCreateAndSaveResponse tests that a key is recorded once per scope and its response stored
+func (suite *IdempotencyRepositoryTestSuite) TestCreateAndSaveResponse() {
+	expiresAt := time.Date(2030, time.July, 15, 13, 0, 0, 0, time.UTC)
+	assert.NoError(suite.T(), suite.IdempotencyRepo.Create(suite.key("admin", "key-1", expiresAt)))
+	assert.ErrorIs(suite.T(), suite.IdempotencyRepo.Create(suite.key("admin", "key-1", expiresAt)), ErrIdempotencyKeyTaken)
+	assert.NoError(suite.T(), suite.IdempotencyRepo.Create(suite.key("robot", "key-1", expiresAt)))
+
+	err := suite.IdempotencyRepo.SaveResponse(&entity.IdempotencyKey{Scope: "admin", Key: "key-1", StatusCode: 200,
+		Header: `{"Content-Type":["application/json"]}`, Body: []byte(`{"id":1}`)})
+	assert.NoError(suite.T(), err)
+
+	record, err := suite.IdempotencyRepo.Find("admin", "key-1")
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 200, record.StatusCode)
+	assert.Equal(suite.T(), "abc", record.Fingerprint)
+	assert.Equal(suite.T(), `{"id":1}`, string(record.Body))
+
+	record, err = suite.IdempotencyRepo.Find("robot", "key-1")
+	assert.NoError(suite.T(), err)
+	assert.Zero(suite.T(), record.StatusCode)
+
+	_, err = suite.IdempotencyRepo.Find("admin", "key-2")
+	assert.True(suite.T(), IsNotFound(err))
+}
+
+// TestTakeOver tests that only a key without a response whose lease is over is taken over, and only once
+func (suite *IdempotencyRepositoryTestSuite) TestTakeOver() {
+	now := time.Date(2030, time.July, 15, 12, 0, 0, 0, time.UTC)
+	leased, over, renewed := now.Add(time.Minute), now.Add(-time.Minute), now.Add(2*time.Minute)
+	running := suite.key("admin", "running", now.Add(time.Hour))
+	running.LockedUntil = &leased
+	crashed := suite.key("admin", "crashed", now.Add(time.Hour))
+	crashed.LockedUntil = &over
+	suite.IdempotencyRepo.Create(running)
+	suite.IdempotencyRepo.Create(crashed)
+	suite.IdempotencyRepo.Create(suite.key("admin", "done", now.Add(time.Hour)))
+	suite.IdempotencyRepo.SaveResponse(&entity.IdempotencyKey{Scope: "admin", Key: "done", StatusCode: 201})
+
+	take := func(key string) error {
+		return suite.IdempotencyRepo.TakeOver(&entity.IdempotencyKey{Scope: "admin", Key: key, LockedUntil: &renewed}, now)
+	}
+	assert.ErrorIs(suite.T(), take("running"), ErrIdempotencyKeyTaken)
+	assert.ErrorIs(suite.T(), take("done"), ErrIdempotencyKeyTaken)
+	assert.NoError(suite.T(), take("crashed"))
+	assert.ErrorIs(suite.T(), take("crashed"), ErrIdempotencyKeyTaken)
+
+	record, err := suite.IdempotencyRepo.Find("admin", "crashed")
+	assert.NoError(suite.T(), err)
+	assert.True(suite.T(), record.LockedUntil.Equal(renewed))
+}
+
+// TestLeaseToken tests that once a retry took a key over, the request that lost the lease can neither record its
+// response nor free the key, while the retry can
+func (suite *IdempotencyRepositoryTestSuite) TestLeaseToken() {
+	now := time.Date(2030, time.July, 15, 12, 0, 0, 0, time.UTC)
+	over, renewed := now.Add(-time.Minute), now.Add(2*time.Minute)
+	slow := suite.key("admin", "key-1", now.Add(time.Hour))
+	slow.LockedUntil, slow.LeaseToken = &over, "first"
+	suite.IdempotencyRepo.Create(slow)
+	assert.NoError(suite.T(), suite.IdempotencyRepo.TakeOver(&entity.IdempotencyKey{Scope: "admin", Key: "key-1",
+		LockedUntil: &renewed, LeaseToken: "retry"}, now))
+
+	assert.ErrorIs(suite.T(), suite.IdempotencyRepo.Release(&entity.IdempotencyKey{Scope: "admin", Key: "key-1", LeaseToken: "first"}),
+		ErrIdempotencyLeaseLost)
+	assert.ErrorIs(suite.T(), suite.IdempotencyRepo.SaveResponse(&entity.IdempotencyKey{Scope: "admin", Key: "key-1",
+		LeaseToken: "first", StatusCode: 201, Body: []byte(`{"id":1}`)}), ErrIdempotencyLeaseLost)
+	assert.NoError(suite.T(), suite.IdempotencyRepo.SaveResponse(&entity.IdempotencyKey{Scope: "admin", Key: "key-1",
+		LeaseToken: "retry", StatusCode: 201, Body: []byte(`{"id":2}`)}))
+
+	record, err := suite.IdempotencyRepo.Find("admin", "key-1")
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), `{"id":2}`, string(record.Body))
+	assert.Nil(suite.T(), record.LockedUntil)
+	assert.ErrorIs(suite.T(), suite.IdempotencyRepo.Release(&entity.IdempotencyKey{Scope: "admin", Key: "key-1", LeaseToken: "retry"}),
+		ErrIdempotencyLeaseLost)
+}
+
+// TestDeleteExpired tests that only the keys whose window is over are purged
+func (suite *IdempotencyRepositoryTestSuite) TestDeleteExpired() {
+	now := time.Date(2030, time.July, 15, 12, 0, 0, 0, time.UTC)
+	suite.IdempotencyRepo.Create(suite.key("admin", "old", now.Add(-time.Minute)))
+	suite.IdempotencyRepo.Create(suite.key("admin", "new", now.Add(time.Minute)))
+
+	deleted, err := suite.IdempotencyRepo.DeleteExpired(now)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), int64(1), deleted)
+	_, err = suite.IdempotencyRepo.Find("admin", "new")
+	assert.NoError(suite.T(), err)
+}
+
+func TestIdempotencyRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(IdempotencyRepositoryTestSuite))
+}
@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"errors"
+	"gorm.io/gorm"
+	"hub_management_service/internal/entity"
+)
+
+type MembershipRepository interface {
+	Create(membership *entity.Membership) error
+	Find(teamID, userID uint) (*entity.Membership, error)
+	FindByTeamID(teamID uint) ([]entity.Membership, error)
+	FindByUserID(userID uint) ([]entity.Membership, error)
+	Update(membership *entity.Membership) error
+	Delete(id uint) error
+}
+
+type membershipRepository struct {
+	db *gorm.DB
+}
+
+func NewMembershipRepository(db *gorm.DB) MembershipRepository {
+	return &membershipRepository{db: db}
+}
+
+func (r *membershipRepository) Create(membership *entity.Membership) error {
+	return r.db.Omit("User", "Team").Create(membership).Error
+}
+
+// Find - Method to find the current membership of a user in a team, open ones first, returns nil if the user is not a
+// member. Memberships that ended before today are kept as history and ignored.
+func (r *membershipRepository) Find(teamID, userID uint) (*entity.Membership, error) {
+	var membership entity.Membership
+	err := r.db.Where("team_id = ? AND user_id = ? AND (end_date IS NULL OR end_date >= ?)", teamID, userID, entity.Today()).
+		Order("end_date IS NULL DESC, id DESC").First(&membership).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &membership, nil
+}
+
+// FindByTeamID - Method to find the memberships of a team along with their users
+func (r *membershipRepository) FindByTeamID(teamID uint) ([]entity.Membership, error) {
+	var memberships []entity.Membership
+	err := r.db.Preload("User").Where("team_id = ?", teamID).Order("id").Find(&memberships).Error
+	return memberships, err
+}
+
+// FindByUserID - Method to find the memberships of a user along with their teams
+func (r *membershipRepository) FindByUserID(userID uint) ([]entity.Membership, error) {
+	var memberships []entity.Membership
+	err := r.db.Preload("Team").Where("user_id = ?", userID).Order("is_primary DESC, id").Find(&memberships).Error
+	return memberships, err
+}
+
+func (r *membershipRepository) Update(membership *entity.Membership) error {
+	return r.db.Omit("User", "Team").Save(membership).Error
+}
+
+func (r *membershipRepository) Delete(id uint) error {
+	return r.db.Delete(&entity.Membership{}, id).Error
+}
+
+// syncPrimaryMembership makes the open membership of the user's TeamID their only primary membership,
+// creating it if the user has no open membership of the team. Ended memberships are kept as history, so a user who
+// rejoins a team gets a new membership. The previous primary membership ends today, when the user is transferred,
+// unless it ended before.
+func syncPrimaryMembership(tx *gorm.DB, user *entity.User) error {
+	today := entity.Today()
+	err := tx.Model(&entity.Membership{}).
+		Where("user_id = ? AND team_id <> ? AND is_primary", user.ID, user.TeamID).
+		Updates(map[string]interface{}{
+			"is_primary": false,
+			"end_date":   gorm.Expr("CASE WHEN end_date IS NULL OR end_date > ? THEN ? ELSE end_date END", today, today),
+		}).Error
+	if err != nil {
+		return err
+	}
+
+	result := tx.Model(&entity.Membership{}).
+		Where("user_id = ? AND team_id = ? AND end_date IS NULL", user.ID, user.TeamID).
+		Update("is_primary", true)
+	if result.Error != nil || result.RowsAffected > 0 {
+		return result.Error
+	}
+	return tx.Create(&entity.Membership{
+		UserID:    user.ID,
+		TeamID:    user.TeamID,
+		Role:      entity.RoleMember,
+		StartDate: &today,
+		Primary:   true,
+	}).Error
+}
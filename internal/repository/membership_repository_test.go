@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"hub_management_service/internal/entity"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type MembershipRepositoryTestSuite struct {
+	suite.Suite
+	DB             *gorm.DB
+	MembershipRepo MembershipRepository
+}
+
+func (suite *MembershipRepositoryTestSuite) SetupTest() {
+	// Create an in-memory SQLite database
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		suite.T().Fatal("failed to connect to database")
+	}
+	suite.DB = db
+	suite.DB.AutoMigrate(&entity.Hub{}, &entity.Team{}, &entity.User{}, &entity.Membership{})
+
+	suite.MembershipRepo = NewMembershipRepository(suite.DB)
+}
+
+func (suite *MembershipRepositoryTestSuite) TestCreateAndFind() {
+	suite.DB.Create(&entity.Team{ID: 1, Name: "Team A", HubID: 1})
+	suite.DB.Create(&entity.User{ID: 7, Name: "User 7", TeamID: 1})
+	membership := &entity.Membership{UserID: 7, TeamID: 1, Role: entity.RoleLead}
+	assert.NoError(suite.T(), suite.MembershipRepo.Create(membership))
+
+	found, err := suite.MembershipRepo.Find(1, 7)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), entity.RoleLead, found.Role)
+
+	found, err = suite.MembershipRepo.Find(2, 7)
+	assert.NoError(suite.T(), err)
+	assert.Nil(suite.T(), found)
+
+	// Team memberships come with their users and user memberships with their teams
+	memberships, err := suite.MembershipRepo.FindByTeamID(1)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "User 7", memberships[0].User.Name)
+
+	memberships, err = suite.MembershipRepo.FindByUserID(7)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "Team A", memberships[0].Team.Name)
+
+	assert.NoError(suite.T(), suite.MembershipRepo.Delete(membership.ID))
+	found, _ = suite.MembershipRepo.Find(1, 7)
+	assert.Nil(suite.T(), found)
+}
+
+func (suite *MembershipRepositoryTestSuite) TestFindOpen() {
+	suite.DB.Create(&entity.Team{ID: 1, Name: "Team A", HubID: 1})
+	suite.DB.Create(&entity.User{ID: 7, Name: "User 7", TeamID: 1})
+	ended := entity.NewDate(2020, time.January, 31)
+	assert.NoError(suite.T(), suite.MembershipRepo.Create(&entity.Membership{UserID: 7, TeamID: 1, Role: entity.RoleLead, EndDate: &ended}))
+
+	// Ended memberships are ignored and do not block a new one
+	found, err := suite.MembershipRepo.Find(1, 7)
+	assert.NoError(suite.T(), err)
+	assert.Nil(suite.T(), found)
+
+	membership := &entity.Membership{UserID: 7, TeamID: 1, Role: entity.RoleMember}
+	assert.NoError(suite.T(), suite.MembershipRepo.Create(membership))
+	found, _ = suite.MembershipRepo.Find(1, 7)
+	if assert.NotNil(suite.T(), found) {
+		assert.Equal(suite.T(), membership.ID, found.ID)
+	}
+
+	// A user has at most one open membership per team
+	assert.Error(suite.T(), suite.MembershipRepo.Create(&entity.Membership{UserID: 7, TeamID: 1, Role: entity.RoleObserver}))
+}
+
+func TestMembershipRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(MembershipRepositoryTestSuite))
+}
@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"errors"
+	"gorm.io/gorm"
+	"hub_management_service/internal/entity"
+	"time"
+)
+
+type MFARepository interface {
+	Save(enrollment *entity.MFAEnrollment) error
+	FindByUsername(username string) (*entity.MFAEnrollment, error)
+	Confirm(id uint) error
+	Delete(username string) error
+	UseRecoveryCode(enrollmentID uint, codeHash string) (bool, error)
+	UseStep(enrollmentID uint, step int64) (bool, error)
+	RecordFailure(enrollmentID uint, maxFailures int, lockedUntil time.Time) error
+	ClearFailures(enrollmentID uint) error
+}
+
+type mfaRepository struct {
+	db *gorm.DB
+}
+
+func NewMFARepository(db *gorm.DB) MFARepository {
+	return &mfaRepository{db: db}
+}
+
+// Save replaces any existing enrollment of the user with the given one and its recovery codes
+func (r *mfaRepository) Save(enrollment *entity.MFAEnrollment) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := deleteEnrollment(tx, enrollment.Username); err != nil {
+			return err
+		}
+		return tx.Create(enrollment).Error
+	})
+}
+
+// FindByUsername returns the enrollment of a user, or nil if the user has not enrolled
+func (r *mfaRepository) FindByUsername(username string) (*entity.MFAEnrollment, error) {
+	var enrollment entity.MFAEnrollment
+	err := r.db.Where("username = ?", username).First(&enrollment).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &enrollment, nil
+}
+
+func (r *mfaRepository) Confirm(id uint) error {
+	return r.db.Model(&entity.MFAEnrollment{}).Where("id = ?", id).Update("confirmed", true).Error
+}
+
+func (r *mfaRepository) Delete(username string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return deleteEnrollment(tx, username)
+	})
+}
+
+// UseRecoveryCode marks an unused recovery code as used, reporting whether one matched
+func (r *mfaRepository) UseRecoveryCode(enrollmentID uint, codeHash string) (bool, error) {
+	result := r.db.Model(&entity.RecoveryCode{}).
+		Where("enrollment_id = ? AND code_hash = ? AND used_at IS NULL", enrollmentID, codeHash).
+		Update("used_at", time.Now())
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// UseStep records the TOTP step of an accepted code and clears the failures, reporting false when the step or a later
+// one was already used, e.g. by a concurrent login with the same code
+func (r *mfaRepository) UseStep(enrollmentID uint, step int64) (bool, error) {
+	result := r.db.Model(&entity.MFAEnrollment{}).
+		Where("id = ? AND last_used_step < ?", enrollmentID, step).
+		Updates(map[string]interface{}{"last_used_step": step, "failed_codes": 0})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// RecordFailure counts an invalid code, and locks the enrollment until lockedUntil once maxFailures are reached
+func (r *mfaRepository) RecordFailure(enrollmentID uint, maxFailures int, lockedUntil time.Time) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&entity.MFAEnrollment{}).Where("id = ?", enrollmentID).
+			Update("failed_codes", gorm.Expr("failed_codes + 1")).Error
+		if err != nil {
+			return err
+		}
+		return tx.Model(&entity.MFAEnrollment{}).Where("id = ? AND failed_codes >= ?", enrollmentID, maxFailures).
+			Updates(map[string]interface{}{"failed_codes": 0, "locked_until": lockedUntil}).Error
+	})
+}
+
+// ClearFailures resets the count of invalid codes after a valid one
+func (r *mfaRepository) ClearFailures(enrollmentID uint) error {
+	return r.db.Model(&entity.MFAEnrollment{}).Where("id = ?", enrollmentID).Update("failed_codes", 0).Error
+}
+
+// deleteEnrollment removes the user's enrollment together with its recovery codes
+func deleteEnrollment(tx *gorm.DB, username string) error {
+	var ids []uint
+	if err := tx.Model(&entity.MFAEnrollment{}).Where("username = ?", username).Pluck("id", &ids).Error; err != nil {
+		return err
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	if err := tx.Where("enrollment_id IN ?", ids).Delete(&entity.RecoveryCode{}).Error; err != nil {
+		return err
+	}
+	return tx.Where("id IN ?", ids).Delete(&entity.MFAEnrollment{}).Error
+}
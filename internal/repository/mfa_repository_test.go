@@ -0,0 +1,149 @@
+package repository
+
+import (
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"hub_management_service/internal/entity"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type MFARepositoryTestSuite struct {
+	suite.Suite
+	DB      *gorm.DB
+	MFARepo MFARepository
+}
+
+func (suite *MFARepositoryTestSuite) SetupTest() {
+	// Create an in-memory SQLite database
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		suite.T().Fatal("failed to connect to database")
+	}
+	// Transactions must see the same in-memory database
+	sqlDB, _ := db.DB()
+	sqlDB.SetMaxOpenConns(1)
+	suite.DB = db
+
+	suite.DB.AutoMigrate(&entity.MFAEnrollment{}, &entity.RecoveryCode{})
+
+	suite.MFARepo = NewMFARepository(suite.DB)
+}
+
+func (suite *MFARepositoryTestSuite) TearDownTest() {
+	suite.DB.Exec("DELETE FROM recovery_codes")
+	suite.DB.Exec("DELETE FROM mfa_enrollments")
+}
+
+func (suite *MFARepositoryTestSuite) TestSaveAndFind() {
+	enrollment := &entity.MFAEnrollment{
+		Username:      "admin",
+		Secret:        "SECRET",
+		RecoveryCodes: []entity.RecoveryCode{{CodeHash: "hash-1"}, {CodeHash: "hash-2"}},
+	}
+	assert.NoError(suite.T(), suite.MFARepo.Save(enrollment))
+
+	found, err := suite.MFARepo.FindByUsername("admin")
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "SECRET", found.Secret)
+	assert.False(suite.T(), found.Confirmed)
+}
+
+func (suite *MFARepositoryTestSuite) TestFindByUsername_NotEnrolled() {
+	found, err := suite.MFARepo.FindByUsername("nobody")
+	assert.NoError(suite.T(), err)
+	assert.Nil(suite.T(), found)
+}
+
+func (suite *MFARepositoryTestSuite) TestSaveReplacesPreviousEnrollment() {
+	suite.MFARepo.Save(&entity.MFAEnrollment{Username: "admin", Secret: "OLD", RecoveryCodes: []entity.RecoveryCode{{CodeHash: "old"}}})
+	suite.MFARepo.Save(&entity.MFAEnrollment{Username: "admin", Secret: "NEW"})
+
+	found, _ := suite.MFARepo.FindByUsername("admin")
+	assert.Equal(suite.T(), "NEW", found.Secret)
+
+	var count int64
+	suite.DB.Model(&entity.RecoveryCode{}).Count(&count)
+	assert.Equal(suite.T(), int64(0), count)
+}
+
+func (suite *MFARepositoryTestSuite) TestConfirm() {
+	enrollment := &entity.MFAEnrollment{Username: "admin", Secret: "SECRET"}
+	suite.MFARepo.Save(enrollment)
+
+	assert.NoError(suite.T(), suite.MFARepo.Confirm(enrollment.ID))
+
+	found, _ := suite.MFARepo.FindByUsername("admin")
+	assert.True(suite.T(), found.Confirmed)
+}
+
+func (suite *MFARepositoryTestSuite) TestUseRecoveryCode() {
+	enrollment := &entity.MFAEnrollment{Username: "admin", Secret: "SECRET", RecoveryCodes: []entity.RecoveryCode{{CodeHash: "hash-1"}}}
+	suite.MFARepo.Save(enrollment)
+
+	// A code can only be used once
+	used, err := suite.MFARepo.UseRecoveryCode(enrollment.ID, "hash-1")
+	assert.NoError(suite.T(), err)
+	assert.True(suite.T(), used)
+
+	used, err = suite.MFARepo.UseRecoveryCode(enrollment.ID, "hash-1")
+	assert.NoError(suite.T(), err)
+	assert.False(suite.T(), used)
+}
+
+func (suite *MFARepositoryTestSuite) TestUseStep() {
+	enrollment := &entity.MFAEnrollment{Username: "admin", Secret: "SECRET", FailedCodes: 2}
+	suite.MFARepo.Save(enrollment)
+
+	// A step is only accepted after the last used one
+	used, err := suite.MFARepo.UseStep(enrollment.ID, 100)
+	assert.NoError(suite.T(), err)
+	assert.True(suite.T(), used)
+
+	used, err = suite.MFARepo.UseStep(enrollment.ID, 100)
+	assert.NoError(suite.T(), err)
+	assert.False(suite.T(), used)
+
+	found, _ := suite.MFARepo.FindByUsername("admin")
+	assert.Equal(suite.T(), int64(100), found.LastUsedStep)
+	assert.Zero(suite.T(), found.FailedCodes)
+}
+
+func (suite *MFARepositoryTestSuite) TestRecordFailure() {
+	enrollment := &entity.MFAEnrollment{Username: "admin", Secret: "SECRET"}
+	suite.MFARepo.Save(enrollment)
+	lockedUntil := time.Now().Add(time.Hour)
+
+	for i := 0; i < 2; i++ {
+		assert.NoError(suite.T(), suite.MFARepo.RecordFailure(enrollment.ID, 3, lockedUntil))
+	}
+	found, _ := suite.MFARepo.FindByUsername("admin")
+	assert.Equal(suite.T(), 2, found.FailedCodes)
+	assert.Nil(suite.T(), found.LockedUntil)
+
+	// The third failure locks the enrollment and starts a new count
+	assert.NoError(suite.T(), suite.MFARepo.RecordFailure(enrollment.ID, 3, lockedUntil))
+	found, _ = suite.MFARepo.FindByUsername("admin")
+	assert.Zero(suite.T(), found.FailedCodes)
+	if assert.NotNil(suite.T(), found.LockedUntil) {
+		assert.WithinDuration(suite.T(), lockedUntil, *found.LockedUntil, time.Second)
+	}
+
+	assert.NoError(suite.T(), suite.MFARepo.ClearFailures(enrollment.ID))
+}
+
+func (suite *MFARepositoryTestSuite) TestDelete() {
+	suite.MFARepo.Save(&entity.MFAEnrollment{Username: "admin", Secret: "SECRET"})
+
+	assert.NoError(suite.T(), suite.MFARepo.Delete("admin"))
+
+	found, _ := suite.MFARepo.FindByUsername("admin")
+	assert.Nil(suite.T(), found)
+}
+
+func TestMFARepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(MFARepositoryTestSuite))
+}
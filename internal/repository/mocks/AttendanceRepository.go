@@ -0,0 +1,162 @@
+// Code generated by mockery v2.33.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	entity "hub_management_service/internal/entity"
+
+	mock "github.com/stretchr/testify/mock"
+
+	repository "hub_management_service/internal/repository"
+
+	time "time"
+)
+
+// AttendanceRepository is an autogenerated mock type for the AttendanceRepository type
+type AttendanceRepository struct {
+	mock.Mock
+}
+
+// CheckIn provides a mock function with given fields: visit
+func (_m *AttendanceRepository) CheckIn(visit *entity.Visit) error {
+	ret := _m.Called(visit)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*entity.Visit) error); ok {
+		r0 = rf(visit)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteVisitsBefore provides a mock function with given fields: cutoff
+func (_m *AttendanceRepository) DeleteVisitsBefore(cutoff time.Time) (int64, error) {
+	ret := _m.Called(cutoff)
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(time.Time) (int64, error)); ok {
+		return rf(cutoff)
+	}
+	if rf, ok := ret.Get(0).(func(time.Time) int64); ok {
+		r0 = rf(cutoff)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(time.Time) error); ok {
+		r1 = rf(cutoff)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindDaily provides a mock function with given fields: opts
+func (_m *AttendanceRepository) FindDaily(opts repository.ListOptions) ([]entity.DailyAttendance, error) {
+	ret := _m.Called(opts)
+
+	var r0 []entity.DailyAttendance
+	var r1 error
+	if rf, ok := ret.Get(0).(func(repository.ListOptions) ([]entity.DailyAttendance, error)); ok {
+		return rf(opts)
+	}
+	if rf, ok := ret.Get(0).(func(repository.ListOptions) []entity.DailyAttendance); ok {
+		r0 = rf(opts)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.DailyAttendance)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(repository.ListOptions) error); ok {
+		r1 = rf(opts)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindOpenVisit provides a mock function with given fields: userID, hubID
+func (_m *AttendanceRepository) FindOpenVisit(userID uint, hubID uint) (*entity.Visit, error) {
+	ret := _m.Called(userID, hubID)
+
+	var r0 *entity.Visit
+	var r1 error
+	if rf, ok := ret.Get(0).(func(uint, uint) (*entity.Visit, error)); ok {
+		return rf(userID, hubID)
+	}
+	if rf, ok := ret.Get(0).(func(uint, uint) *entity.Visit); ok {
+		r0 = rf(userID, hubID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.Visit)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(uint, uint) error); ok {
+		r1 = rf(userID, hubID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindVisits provides a mock function with given fields: hubID, date
+func (_m *AttendanceRepository) FindVisits(hubID uint, date entity.Date) ([]entity.Visit, error) {
+	ret := _m.Called(hubID, date)
+
+	var r0 []entity.Visit
+	var r1 error
+	if rf, ok := ret.Get(0).(func(uint, entity.Date) ([]entity.Visit, error)); ok {
+		return rf(hubID, date)
+	}
+	if rf, ok := ret.Get(0).(func(uint, entity.Date) []entity.Visit); ok {
+		r0 = rf(hubID, date)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.Visit)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(uint, entity.Date) error); ok {
+		r1 = rf(hubID, date)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateVisit provides a mock function with given fields: visit
+func (_m *AttendanceRepository) UpdateVisit(visit *entity.Visit) error {
+	ret := _m.Called(visit)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*entity.Visit) error); ok {
+		r0 = rf(visit)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewAttendanceRepository creates a new instance of AttendanceRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewAttendanceRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *AttendanceRepository {
+	mock := &AttendanceRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
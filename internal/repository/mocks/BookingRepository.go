@@ -0,0 +1,110 @@
+// Code generated by mockery v2.33.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	entity "hub_management_service/internal/entity"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// BookingRepository is an autogenerated mock type for the BookingRepository type
+type BookingRepository struct {
+	mock.Mock
+}
+
+// Create provides a mock function with given fields: booking
+func (_m *BookingRepository) Create(booking *entity.Booking) error {
+	ret := _m.Called(booking)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*entity.Booking) error); ok {
+		r0 = rf(booking)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// FindActive provides a mock function with given fields: resourceIDs, from, to
+func (_m *BookingRepository) FindActive(resourceIDs []uint, from time.Time, to time.Time) ([]entity.Booking, error) {
+	ret := _m.Called(resourceIDs, from, to)
+
+	var r0 []entity.Booking
+	var r1 error
+	if rf, ok := ret.Get(0).(func([]uint, time.Time, time.Time) ([]entity.Booking, error)); ok {
+		return rf(resourceIDs, from, to)
+	}
+	if rf, ok := ret.Get(0).(func([]uint, time.Time, time.Time) []entity.Booking); ok {
+		r0 = rf(resourceIDs, from, to)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.Booking)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func([]uint, time.Time, time.Time) error); ok {
+		r1 = rf(resourceIDs, from, to)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindByID provides a mock function with given fields: id
+func (_m *BookingRepository) FindByID(id uint) (*entity.Booking, error) {
+	ret := _m.Called(id)
+
+	var r0 *entity.Booking
+	var r1 error
+	if rf, ok := ret.Get(0).(func(uint) (*entity.Booking, error)); ok {
+		return rf(id)
+	}
+	if rf, ok := ret.Get(0).(func(uint) *entity.Booking); ok {
+		r0 = rf(id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.Booking)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(uint) error); ok {
+		r1 = rf(id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Update provides a mock function with given fields: booking
+func (_m *BookingRepository) Update(booking *entity.Booking) error {
+	ret := _m.Called(booking)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*entity.Booking) error); ok {
+		r0 = rf(booking)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewBookingRepository creates a new instance of BookingRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewBookingRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *BookingRepository {
+	mock := &BookingRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,209 @@
+// Code generated by mockery v2.33.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	entity "hub_management_service/internal/entity"
+
+	mock "github.com/stretchr/testify/mock"
+
+	repository "hub_management_service/internal/repository"
+)
+
+// CountryRepository is an autogenerated mock type for the CountryRepository type
+type CountryRepository struct {
+	mock.Mock
+}
+
+// Create provides a mock function with given fields: country
+func (_m *CountryRepository) Create(country *entity.Country) error {
+	ret := _m.Called(country)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*entity.Country) error); ok {
+		r0 = rf(country)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Delete provides a mock function with given fields: id
+func (_m *CountryRepository) Delete(id uint) error {
+	ret := _m.Called(id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(uint) error); ok {
+		r0 = rf(id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// FindAll provides a mock function with given fields:
+func (_m *CountryRepository) FindAll() ([]entity.Country, error) {
+	ret := _m.Called()
+
+	var r0 []entity.Country
+	var r1 error
+	if rf, ok := ret.Get(0).(func() ([]entity.Country, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() []entity.Country); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.Country)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindByCode provides a mock function with given fields: code
+func (_m *CountryRepository) FindByCode(code string) (*entity.Country, error) {
+	ret := _m.Called(code)
+
+	var r0 *entity.Country
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (*entity.Country, error)); ok {
+		return rf(code)
+	}
+	if rf, ok := ret.Get(0).(func(string) *entity.Country); ok {
+		r0 = rf(code)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.Country)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(code)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindByID provides a mock function with given fields: id
+func (_m *CountryRepository) FindByID(id uint) (*entity.Country, error) {
+	ret := _m.Called(id)
+
+	var r0 *entity.Country
+	var r1 error
+	if rf, ok := ret.Get(0).(func(uint) (*entity.Country, error)); ok {
+		return rf(id)
+	}
+	if rf, ok := ret.Get(0).(func(uint) *entity.Country); ok {
+		r0 = rf(id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.Country)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(uint) error); ok {
+		r1 = rf(id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindByRegionID provides a mock function with given fields: regionID
+func (_m *CountryRepository) FindByRegionID(regionID uint) ([]entity.Country, error) {
+	ret := _m.Called(regionID)
+
+	var r0 []entity.Country
+	var r1 error
+	if rf, ok := ret.Get(0).(func(uint) ([]entity.Country, error)); ok {
+		return rf(regionID)
+	}
+	if rf, ok := ret.Get(0).(func(uint) []entity.Country); ok {
+		r0 = rf(regionID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.Country)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(uint) error); ok {
+		r1 = rf(regionID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// List provides a mock function with given fields: opts
+func (_m *CountryRepository) List(opts repository.ListOptions) ([]entity.Country, int64, error) {
+	ret := _m.Called(opts)
+
+	var r0 []entity.Country
+	var r1 int64
+	var r2 error
+	if rf, ok := ret.Get(0).(func(repository.ListOptions) ([]entity.Country, int64, error)); ok {
+		return rf(opts)
+	}
+	if rf, ok := ret.Get(0).(func(repository.ListOptions) []entity.Country); ok {
+		r0 = rf(opts)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.Country)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(repository.ListOptions) int64); ok {
+		r1 = rf(opts)
+	} else {
+		r1 = ret.Get(1).(int64)
+	}
+
+	if rf, ok := ret.Get(2).(func(repository.ListOptions) error); ok {
+		r2 = rf(opts)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// Update provides a mock function with given fields: country
+func (_m *CountryRepository) Update(country *entity.Country) error {
+	ret := _m.Called(country)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*entity.Country) error); ok {
+		r0 = rf(country)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewCountryRepository creates a new instance of CountryRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewCountryRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *CountryRepository {
+	mock := &CountryRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
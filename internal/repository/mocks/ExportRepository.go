@@ -0,0 +1,42 @@
+// Code generated by mockery v2.33.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	repository "hub_management_service/internal/repository"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// ExportRepository is an autogenerated mock type for the ExportRepository type
+type ExportRepository struct {
+	mock.Mock
+}
+
+// StreamOrg provides a mock function with given fields: opts, fn
+func (_m *ExportRepository) StreamOrg(opts repository.ListOptions, fn func(*repository.OrgRow) error) error {
+	ret := _m.Called(opts, fn)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(repository.ListOptions, func(*repository.OrgRow) error) error); ok {
+		r0 = rf(opts, fn)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewExportRepository creates a new instance of ExportRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewExportRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *ExportRepository {
+	mock := &ExportRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -6,6 +6,8 @@ import (
 	entity "hub_management_service/internal/entity"
 
 	mock "github.com/stretchr/testify/mock"
+
+	repository "hub_management_service/internal/repository"
 )
 
 // HubRepository is an autogenerated mock type for the HubRepository type
@@ -13,6 +15,56 @@ type HubRepository struct {
 	mock.Mock
 }
 
+// CountTeams provides a mock function with given fields: hubID
+func (_m *HubRepository) CountTeams(hubID uint) (int64, error) {
+	ret := _m.Called(hubID)
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(uint) (int64, error)); ok {
+		return rf(hubID)
+	}
+	if rf, ok := ret.Get(0).(func(uint) int64); ok {
+		r0 = rf(hubID)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(uint) error); ok {
+		r1 = rf(hubID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CountUsers provides a mock function with given fields: hubIDs
+func (_m *HubRepository) CountUsers(hubIDs []uint) (map[uint]int64, error) {
+	ret := _m.Called(hubIDs)
+
+	var r0 map[uint]int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func([]uint) (map[uint]int64, error)); ok {
+		return rf(hubIDs)
+	}
+	if rf, ok := ret.Get(0).(func([]uint) map[uint]int64); ok {
+		r0 = rf(hubIDs)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[uint]int64)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func([]uint) error); ok {
+		r1 = rf(hubIDs)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Create provides a mock function with given fields: hub
 func (_m *HubRepository) Create(hub *entity.Hub) error {
 	ret := _m.Called(hub)
@@ -27,6 +79,20 @@ func (_m *HubRepository) Create(hub *entity.Hub) error {
 	return r0
 }
 
+// Delete provides a mock function with given fields: id, version
+func (_m *HubRepository) Delete(id uint, version uint) error {
+	ret := _m.Called(id, version)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(uint, uint) error); ok {
+		r0 = rf(id, version)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // FindAll provides a mock function with given fields:
 func (_m *HubRepository) FindAll() ([]entity.Hub, error) {
 	ret := _m.Called()
@@ -79,6 +145,39 @@ func (_m *HubRepository) FindByID(id uint) (*entity.Hub, error) {
 	return r0, r1
 }
 
+// List provides a mock function with given fields: opts
+func (_m *HubRepository) List(opts repository.ListOptions) ([]entity.Hub, int64, error) {
+	ret := _m.Called(opts)
+
+	var r0 []entity.Hub
+	var r1 int64
+	var r2 error
+	if rf, ok := ret.Get(0).(func(repository.ListOptions) ([]entity.Hub, int64, error)); ok {
+		return rf(opts)
+	}
+	if rf, ok := ret.Get(0).(func(repository.ListOptions) []entity.Hub); ok {
+		r0 = rf(opts)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.Hub)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(repository.ListOptions) int64); ok {
+		r1 = rf(opts)
+	} else {
+		r1 = ret.Get(1).(int64)
+	}
+
+	if rf, ok := ret.Get(2).(func(repository.ListOptions) error); ok {
+		r2 = rf(opts)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
 // SearchByName provides a mock function with given fields: name
 func (_m *HubRepository) SearchByName(name string) ([]entity.Hub, error) {
 	ret := _m.Called(name)
@@ -105,6 +204,20 @@ func (_m *HubRepository) SearchByName(name string) ([]entity.Hub, error) {
 	return r0, r1
 }
 
+// Update provides a mock function with given fields: hub
+func (_m *HubRepository) Update(hub *entity.Hub) error {
+	ret := _m.Called(hub)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*entity.Hub) error); ok {
+		r0 = rf(hub)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // NewHubRepository creates a new instance of HubRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewHubRepository(t interface {
@@ -0,0 +1,150 @@
+// Code generated by mockery v2.33.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	entity "hub_management_service/internal/entity"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// IdempotencyRepository is an autogenerated mock type for the IdempotencyRepository type
+type IdempotencyRepository struct {
+	mock.Mock
+}
+
+// Create provides a mock function with given fields: record
+func (_m *IdempotencyRepository) Create(record *entity.IdempotencyKey) error {
+	ret := _m.Called(record)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*entity.IdempotencyKey) error); ok {
+		r0 = rf(record)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Delete provides a mock function with given fields: scope, key
+func (_m *IdempotencyRepository) Delete(scope string, key string) error {
+	ret := _m.Called(scope, key)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string) error); ok {
+		r0 = rf(scope, key)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteExpired provides a mock function with given fields: now
+func (_m *IdempotencyRepository) DeleteExpired(now time.Time) (int64, error) {
+	ret := _m.Called(now)
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(time.Time) (int64, error)); ok {
+		return rf(now)
+	}
+	if rf, ok := ret.Get(0).(func(time.Time) int64); ok {
+		r0 = rf(now)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(time.Time) error); ok {
+		r1 = rf(now)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Find provides a mock function with given fields: scope, key
+func (_m *IdempotencyRepository) Find(scope string, key string) (*entity.IdempotencyKey, error) {
+	ret := _m.Called(scope, key)
+
+	var r0 *entity.IdempotencyKey
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, string) (*entity.IdempotencyKey, error)); ok {
+		return rf(scope, key)
+	}
+	if rf, ok := ret.Get(0).(func(string, string) *entity.IdempotencyKey); ok {
+		r0 = rf(scope, key)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.IdempotencyKey)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(scope, key)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Release provides a mock function with given fields: record
+func (_m *IdempotencyRepository) Release(record *entity.IdempotencyKey) error {
+	ret := _m.Called(record)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*entity.IdempotencyKey) error); ok {
+		r0 = rf(record)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SaveResponse provides a mock function with given fields: record
+func (_m *IdempotencyRepository) SaveResponse(record *entity.IdempotencyKey) error {
+	ret := _m.Called(record)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*entity.IdempotencyKey) error); ok {
+		r0 = rf(record)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// TakeOver provides a mock function with given fields: record, now
+func (_m *IdempotencyRepository) TakeOver(record *entity.IdempotencyKey, now time.Time) error {
+	ret := _m.Called(record, now)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*entity.IdempotencyKey, time.Time) error); ok {
+		r0 = rf(record, now)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewIdempotencyRepository creates a new instance of IdempotencyRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewIdempotencyRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *IdempotencyRepository {
+	mock := &IdempotencyRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
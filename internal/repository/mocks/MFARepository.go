@@ -0,0 +1,174 @@
+// Code generated by mockery v2.33.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	entity "hub_management_service/internal/entity"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// MFARepository is an autogenerated mock type for the MFARepository type
+type MFARepository struct {
+	mock.Mock
+}
+
+// ClearFailures provides a mock function with given fields: enrollmentID
+func (_m *MFARepository) ClearFailures(enrollmentID uint) error {
+	ret := _m.Called(enrollmentID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(uint) error); ok {
+		r0 = rf(enrollmentID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Confirm provides a mock function with given fields: id
+func (_m *MFARepository) Confirm(id uint) error {
+	ret := _m.Called(id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(uint) error); ok {
+		r0 = rf(id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Delete provides a mock function with given fields: username
+func (_m *MFARepository) Delete(username string) error {
+	ret := _m.Called(username)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(username)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// FindByUsername provides a mock function with given fields: username
+func (_m *MFARepository) FindByUsername(username string) (*entity.MFAEnrollment, error) {
+	ret := _m.Called(username)
+
+	var r0 *entity.MFAEnrollment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (*entity.MFAEnrollment, error)); ok {
+		return rf(username)
+	}
+	if rf, ok := ret.Get(0).(func(string) *entity.MFAEnrollment); ok {
+		r0 = rf(username)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.MFAEnrollment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(username)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RecordFailure provides a mock function with given fields: enrollmentID, maxFailures, lockedUntil
+func (_m *MFARepository) RecordFailure(enrollmentID uint, maxFailures int, lockedUntil time.Time) error {
+	ret := _m.Called(enrollmentID, maxFailures, lockedUntil)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(uint, int, time.Time) error); ok {
+		r0 = rf(enrollmentID, maxFailures, lockedUntil)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Save provides a mock function with given fields: enrollment
+func (_m *MFARepository) Save(enrollment *entity.MFAEnrollment) error {
+	ret := _m.Called(enrollment)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*entity.MFAEnrollment) error); ok {
+		r0 = rf(enrollment)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UseRecoveryCode provides a mock function with given fields: enrollmentID, codeHash
+func (_m *MFARepository) UseRecoveryCode(enrollmentID uint, codeHash string) (bool, error) {
+	ret := _m.Called(enrollmentID, codeHash)
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(uint, string) (bool, error)); ok {
+		return rf(enrollmentID, codeHash)
+	}
+	if rf, ok := ret.Get(0).(func(uint, string) bool); ok {
+		r0 = rf(enrollmentID, codeHash)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(uint, string) error); ok {
+		r1 = rf(enrollmentID, codeHash)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UseStep provides a mock function with given fields: enrollmentID, step
+func (_m *MFARepository) UseStep(enrollmentID uint, step int64) (bool, error) {
+	ret := _m.Called(enrollmentID, step)
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(uint, int64) (bool, error)); ok {
+		return rf(enrollmentID, step)
+	}
+	if rf, ok := ret.Get(0).(func(uint, int64) bool); ok {
+		r0 = rf(enrollmentID, step)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(uint, int64) error); ok {
+		r1 = rf(enrollmentID, step)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewMFARepository creates a new instance of MFARepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMFARepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MFARepository {
+	mock := &MFARepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
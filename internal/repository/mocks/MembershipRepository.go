@@ -0,0 +1,148 @@
+// Code generated by mockery v2.33.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	entity "hub_management_service/internal/entity"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MembershipRepository is an autogenerated mock type for the MembershipRepository type
+type MembershipRepository struct {
+	mock.Mock
+}
+
+// Create provides a mock function with given fields: membership
+func (_m *MembershipRepository) Create(membership *entity.Membership) error {
+	ret := _m.Called(membership)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*entity.Membership) error); ok {
+		r0 = rf(membership)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Delete provides a mock function with given fields: id
+func (_m *MembershipRepository) Delete(id uint) error {
+	ret := _m.Called(id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(uint) error); ok {
+		r0 = rf(id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Find provides a mock function with given fields: teamID, userID
+func (_m *MembershipRepository) Find(teamID uint, userID uint) (*entity.Membership, error) {
+	ret := _m.Called(teamID, userID)
+
+	var r0 *entity.Membership
+	var r1 error
+	if rf, ok := ret.Get(0).(func(uint, uint) (*entity.Membership, error)); ok {
+		return rf(teamID, userID)
+	}
+	if rf, ok := ret.Get(0).(func(uint, uint) *entity.Membership); ok {
+		r0 = rf(teamID, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.Membership)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(uint, uint) error); ok {
+		r1 = rf(teamID, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindByTeamID provides a mock function with given fields: teamID
+func (_m *MembershipRepository) FindByTeamID(teamID uint) ([]entity.Membership, error) {
+	ret := _m.Called(teamID)
+
+	var r0 []entity.Membership
+	var r1 error
+	if rf, ok := ret.Get(0).(func(uint) ([]entity.Membership, error)); ok {
+		return rf(teamID)
+	}
+	if rf, ok := ret.Get(0).(func(uint) []entity.Membership); ok {
+		r0 = rf(teamID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.Membership)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(uint) error); ok {
+		r1 = rf(teamID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindByUserID provides a mock function with given fields: userID
+func (_m *MembershipRepository) FindByUserID(userID uint) ([]entity.Membership, error) {
+	ret := _m.Called(userID)
+
+	var r0 []entity.Membership
+	var r1 error
+	if rf, ok := ret.Get(0).(func(uint) ([]entity.Membership, error)); ok {
+		return rf(userID)
+	}
+	if rf, ok := ret.Get(0).(func(uint) []entity.Membership); ok {
+		r0 = rf(userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.Membership)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(uint) error); ok {
+		r1 = rf(userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Update provides a mock function with given fields: membership
+func (_m *MembershipRepository) Update(membership *entity.Membership) error {
+	ret := _m.Called(membership)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*entity.Membership) error); ok {
+		r0 = rf(membership)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewMembershipRepository creates a new instance of MembershipRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMembershipRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MembershipRepository {
+	mock := &MembershipRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,176 @@
+// Code generated by mockery v2.33.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	entity "hub_management_service/internal/entity"
+
+	mock "github.com/stretchr/testify/mock"
+
+	repository "hub_management_service/internal/repository"
+)
+
+// RegionRepository is an autogenerated mock type for the RegionRepository type
+type RegionRepository struct {
+	mock.Mock
+}
+
+// Create provides a mock function with given fields: region
+func (_m *RegionRepository) Create(region *entity.Region) error {
+	ret := _m.Called(region)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*entity.Region) error); ok {
+		r0 = rf(region)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Delete provides a mock function with given fields: id
+func (_m *RegionRepository) Delete(id uint) error {
+	ret := _m.Called(id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(uint) error); ok {
+		r0 = rf(id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// FindAll provides a mock function with given fields:
+func (_m *RegionRepository) FindAll() ([]entity.Region, error) {
+	ret := _m.Called()
+
+	var r0 []entity.Region
+	var r1 error
+	if rf, ok := ret.Get(0).(func() ([]entity.Region, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() []entity.Region); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.Region)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindByID provides a mock function with given fields: id
+func (_m *RegionRepository) FindByID(id uint) (*entity.Region, error) {
+	ret := _m.Called(id)
+
+	var r0 *entity.Region
+	var r1 error
+	if rf, ok := ret.Get(0).(func(uint) (*entity.Region, error)); ok {
+		return rf(id)
+	}
+	if rf, ok := ret.Get(0).(func(uint) *entity.Region); ok {
+		r0 = rf(id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.Region)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(uint) error); ok {
+		r1 = rf(id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindByName provides a mock function with given fields: name
+func (_m *RegionRepository) FindByName(name string) (*entity.Region, error) {
+	ret := _m.Called(name)
+
+	var r0 *entity.Region
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (*entity.Region, error)); ok {
+		return rf(name)
+	}
+	if rf, ok := ret.Get(0).(func(string) *entity.Region); ok {
+		r0 = rf(name)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.Region)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(name)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Rollup provides a mock function with given fields:
+func (_m *RegionRepository) Rollup() ([]repository.RegionRollup, error) {
+	ret := _m.Called()
+
+	var r0 []repository.RegionRollup
+	var r1 error
+	if rf, ok := ret.Get(0).(func() ([]repository.RegionRollup, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() []repository.RegionRollup); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]repository.RegionRollup)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Update provides a mock function with given fields: region
+func (_m *RegionRepository) Update(region *entity.Region) error {
+	ret := _m.Called(region)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*entity.Region) error); ok {
+		r0 = rf(region)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewRegionRepository creates a new instance of RegionRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewRegionRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *RegionRepository {
+	mock := &RegionRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
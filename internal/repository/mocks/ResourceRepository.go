@@ -0,0 +1,157 @@
+// Code generated by mockery v2.33.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	entity "hub_management_service/internal/entity"
+
+	mock "github.com/stretchr/testify/mock"
+
+	repository "hub_management_service/internal/repository"
+)
+
+// ResourceRepository is an autogenerated mock type for the ResourceRepository type
+type ResourceRepository struct {
+	mock.Mock
+}
+
+// Create provides a mock function with given fields: resource
+func (_m *ResourceRepository) Create(resource *entity.Resource) error {
+	ret := _m.Called(resource)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*entity.Resource) error); ok {
+		r0 = rf(resource)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Delete provides a mock function with given fields: id
+func (_m *ResourceRepository) Delete(id uint) error {
+	ret := _m.Called(id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(uint) error); ok {
+		r0 = rf(id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// FindByID provides a mock function with given fields: id
+func (_m *ResourceRepository) FindByID(id uint) (*entity.Resource, error) {
+	ret := _m.Called(id)
+
+	var r0 *entity.Resource
+	var r1 error
+	if rf, ok := ret.Get(0).(func(uint) (*entity.Resource, error)); ok {
+		return rf(id)
+	}
+	if rf, ok := ret.Get(0).(func(uint) *entity.Resource); ok {
+		r0 = rf(id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.Resource)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(uint) error); ok {
+		r1 = rf(id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindByName provides a mock function with given fields: hubID, name
+func (_m *ResourceRepository) FindByName(hubID uint, name string) (*entity.Resource, error) {
+	ret := _m.Called(hubID, name)
+
+	var r0 *entity.Resource
+	var r1 error
+	if rf, ok := ret.Get(0).(func(uint, string) (*entity.Resource, error)); ok {
+		return rf(hubID, name)
+	}
+	if rf, ok := ret.Get(0).(func(uint, string) *entity.Resource); ok {
+		r0 = rf(hubID, name)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.Resource)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(uint, string) error); ok {
+		r1 = rf(hubID, name)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// List provides a mock function with given fields: opts
+func (_m *ResourceRepository) List(opts repository.ListOptions) ([]entity.Resource, int64, error) {
+	ret := _m.Called(opts)
+
+	var r0 []entity.Resource
+	var r1 int64
+	var r2 error
+	if rf, ok := ret.Get(0).(func(repository.ListOptions) ([]entity.Resource, int64, error)); ok {
+		return rf(opts)
+	}
+	if rf, ok := ret.Get(0).(func(repository.ListOptions) []entity.Resource); ok {
+		r0 = rf(opts)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.Resource)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(repository.ListOptions) int64); ok {
+		r1 = rf(opts)
+	} else {
+		r1 = ret.Get(1).(int64)
+	}
+
+	if rf, ok := ret.Get(2).(func(repository.ListOptions) error); ok {
+		r2 = rf(opts)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// Update provides a mock function with given fields: resource
+func (_m *ResourceRepository) Update(resource *entity.Resource) error {
+	ret := _m.Called(resource)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*entity.Resource) error); ok {
+		r0 = rf(resource)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewResourceRepository creates a new instance of ResourceRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewResourceRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *ResourceRepository {
+	mock := &ResourceRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
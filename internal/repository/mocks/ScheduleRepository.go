@@ -0,0 +1,174 @@
+// Code generated by mockery v2.33.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	entity "hub_management_service/internal/entity"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// ScheduleRepository is an autogenerated mock type for the ScheduleRepository type
+type ScheduleRepository struct {
+	mock.Mock
+}
+
+// CreateHoliday provides a mock function with given fields: holiday
+func (_m *ScheduleRepository) CreateHoliday(holiday *entity.Holiday) error {
+	ret := _m.Called(holiday)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*entity.Holiday) error); ok {
+		r0 = rf(holiday)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteHoliday provides a mock function with given fields: id
+func (_m *ScheduleRepository) DeleteHoliday(id uint) error {
+	ret := _m.Called(id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(uint) error); ok {
+		r0 = rf(id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// FindHolidayByDate provides a mock function with given fields: hubID, date
+func (_m *ScheduleRepository) FindHolidayByDate(hubID uint, date entity.Date) (*entity.Holiday, error) {
+	ret := _m.Called(hubID, date)
+
+	var r0 *entity.Holiday
+	var r1 error
+	if rf, ok := ret.Get(0).(func(uint, entity.Date) (*entity.Holiday, error)); ok {
+		return rf(hubID, date)
+	}
+	if rf, ok := ret.Get(0).(func(uint, entity.Date) *entity.Holiday); ok {
+		r0 = rf(hubID, date)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.Holiday)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(uint, entity.Date) error); ok {
+		r1 = rf(hubID, date)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindHolidayByID provides a mock function with given fields: id
+func (_m *ScheduleRepository) FindHolidayByID(id uint) (*entity.Holiday, error) {
+	ret := _m.Called(id)
+
+	var r0 *entity.Holiday
+	var r1 error
+	if rf, ok := ret.Get(0).(func(uint) (*entity.Holiday, error)); ok {
+		return rf(id)
+	}
+	if rf, ok := ret.Get(0).(func(uint) *entity.Holiday); ok {
+		r0 = rf(id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.Holiday)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(uint) error); ok {
+		r1 = rf(id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindHolidays provides a mock function with given fields: hubID, from, to
+func (_m *ScheduleRepository) FindHolidays(hubID uint, from *entity.Date, to *entity.Date) ([]entity.Holiday, error) {
+	ret := _m.Called(hubID, from, to)
+
+	var r0 []entity.Holiday
+	var r1 error
+	if rf, ok := ret.Get(0).(func(uint, *entity.Date, *entity.Date) ([]entity.Holiday, error)); ok {
+		return rf(hubID, from, to)
+	}
+	if rf, ok := ret.Get(0).(func(uint, *entity.Date, *entity.Date) []entity.Holiday); ok {
+		r0 = rf(hubID, from, to)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.Holiday)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(uint, *entity.Date, *entity.Date) error); ok {
+		r1 = rf(hubID, from, to)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindOpeningHours provides a mock function with given fields: hubID
+func (_m *ScheduleRepository) FindOpeningHours(hubID uint) ([]entity.OpeningHours, error) {
+	ret := _m.Called(hubID)
+
+	var r0 []entity.OpeningHours
+	var r1 error
+	if rf, ok := ret.Get(0).(func(uint) ([]entity.OpeningHours, error)); ok {
+		return rf(hubID)
+	}
+	if rf, ok := ret.Get(0).(func(uint) []entity.OpeningHours); ok {
+		r0 = rf(hubID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.OpeningHours)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(uint) error); ok {
+		r1 = rf(hubID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ReplaceOpeningHours provides a mock function with given fields: hubID, hours
+func (_m *ScheduleRepository) ReplaceOpeningHours(hubID uint, hours []entity.OpeningHours) error {
+	ret := _m.Called(hubID, hours)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(uint, []entity.OpeningHours) error); ok {
+		r0 = rf(hubID, hours)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewScheduleRepository creates a new instance of ScheduleRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewScheduleRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *ScheduleRepository {
+	mock := &ScheduleRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
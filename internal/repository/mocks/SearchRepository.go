@@ -0,0 +1,106 @@
+// Code generated by mockery v2.33.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	entity "hub_management_service/internal/entity"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// SearchRepository is an autogenerated mock type for the SearchRepository type
+type SearchRepository struct {
+	mock.Mock
+}
+
+// SearchHubs provides a mock function with given fields: query, limit
+func (_m *SearchRepository) SearchHubs(query string, limit int) ([]entity.Hub, error) {
+	ret := _m.Called(query, limit)
+
+	var r0 []entity.Hub
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, int) ([]entity.Hub, error)); ok {
+		return rf(query, limit)
+	}
+	if rf, ok := ret.Get(0).(func(string, int) []entity.Hub); ok {
+		r0 = rf(query, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.Hub)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, int) error); ok {
+		r1 = rf(query, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SearchTeams provides a mock function with given fields: query, limit
+func (_m *SearchRepository) SearchTeams(query string, limit int) ([]entity.Team, error) {
+	ret := _m.Called(query, limit)
+
+	var r0 []entity.Team
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, int) ([]entity.Team, error)); ok {
+		return rf(query, limit)
+	}
+	if rf, ok := ret.Get(0).(func(string, int) []entity.Team); ok {
+		r0 = rf(query, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.Team)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, int) error); ok {
+		r1 = rf(query, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SearchUsers provides a mock function with given fields: query, limit
+func (_m *SearchRepository) SearchUsers(query string, limit int) ([]entity.User, error) {
+	ret := _m.Called(query, limit)
+
+	var r0 []entity.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, int) ([]entity.User, error)); ok {
+		return rf(query, limit)
+	}
+	if rf, ok := ret.Get(0).(func(string, int) []entity.User); ok {
+		r0 = rf(query, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, int) error); ok {
+		r1 = rf(query, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewSearchRepository creates a new instance of SearchRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewSearchRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *SearchRepository {
+	mock := &SearchRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -6,6 +6,8 @@ import (
 	entity "hub_management_service/internal/entity"
 
 	mock "github.com/stretchr/testify/mock"
+
+	repository "hub_management_service/internal/repository"
 )
 
 // TeamRepository is an autogenerated mock type for the TeamRepository type
@@ -13,6 +15,32 @@ type TeamRepository struct {
 	mock.Mock
 }
 
+// CountUsers provides a mock function with given fields: teamIDs
+func (_m *TeamRepository) CountUsers(teamIDs []uint) (map[uint]int64, error) {
+	ret := _m.Called(teamIDs)
+
+	var r0 map[uint]int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func([]uint) (map[uint]int64, error)); ok {
+		return rf(teamIDs)
+	}
+	if rf, ok := ret.Get(0).(func([]uint) map[uint]int64); ok {
+		r0 = rf(teamIDs)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[uint]int64)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func([]uint) error); ok {
+		r1 = rf(teamIDs)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Create provides a mock function with given fields: team
 func (_m *TeamRepository) Create(team *entity.Team) error {
 	ret := _m.Called(team)
@@ -27,6 +55,20 @@ func (_m *TeamRepository) Create(team *entity.Team) error {
 	return r0
 }
 
+// Delete provides a mock function with given fields: id, version
+func (_m *TeamRepository) Delete(id uint, version uint) error {
+	ret := _m.Called(id, version)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(uint, uint) error); ok {
+		r0 = rf(id, version)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // FindAll provides a mock function with given fields:
 func (_m *TeamRepository) FindAll() ([]entity.Team, error) {
 	ret := _m.Called()
@@ -53,6 +95,32 @@ func (_m *TeamRepository) FindAll() ([]entity.Team, error) {
 	return r0, r1
 }
 
+// FindAncestors provides a mock function with given fields: teamID
+func (_m *TeamRepository) FindAncestors(teamID uint) ([]entity.Team, error) {
+	ret := _m.Called(teamID)
+
+	var r0 []entity.Team
+	var r1 error
+	if rf, ok := ret.Get(0).(func(uint) ([]entity.Team, error)); ok {
+		return rf(teamID)
+	}
+	if rf, ok := ret.Get(0).(func(uint) []entity.Team); ok {
+		r0 = rf(teamID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.Team)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(uint) error); ok {
+		r1 = rf(teamID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // FindByHubID provides a mock function with given fields: hubID
 func (_m *TeamRepository) FindByHubID(hubID uint) ([]entity.Team, error) {
 	ret := _m.Called(hubID)
@@ -105,6 +173,105 @@ func (_m *TeamRepository) FindByID(id uint) (*entity.Team, error) {
 	return r0, r1
 }
 
+// FindChildren provides a mock function with given fields: parentID
+func (_m *TeamRepository) FindChildren(parentID uint) ([]entity.Team, error) {
+	ret := _m.Called(parentID)
+
+	var r0 []entity.Team
+	var r1 error
+	if rf, ok := ret.Get(0).(func(uint) ([]entity.Team, error)); ok {
+		return rf(parentID)
+	}
+	if rf, ok := ret.Get(0).(func(uint) []entity.Team); ok {
+		r0 = rf(parentID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.Team)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(uint) error); ok {
+		r1 = rf(parentID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindDescendants provides a mock function with given fields: teamID
+func (_m *TeamRepository) FindDescendants(teamID uint) ([]entity.Team, error) {
+	ret := _m.Called(teamID)
+
+	var r0 []entity.Team
+	var r1 error
+	if rf, ok := ret.Get(0).(func(uint) ([]entity.Team, error)); ok {
+		return rf(teamID)
+	}
+	if rf, ok := ret.Get(0).(func(uint) []entity.Team); ok {
+		r0 = rf(teamID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.Team)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(uint) error); ok {
+		r1 = rf(teamID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// List provides a mock function with given fields: opts
+func (_m *TeamRepository) List(opts repository.ListOptions) ([]entity.Team, int64, error) {
+	ret := _m.Called(opts)
+
+	var r0 []entity.Team
+	var r1 int64
+	var r2 error
+	if rf, ok := ret.Get(0).(func(repository.ListOptions) ([]entity.Team, int64, error)); ok {
+		return rf(opts)
+	}
+	if rf, ok := ret.Get(0).(func(repository.ListOptions) []entity.Team); ok {
+		r0 = rf(opts)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.Team)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(repository.ListOptions) int64); ok {
+		r1 = rf(opts)
+	} else {
+		r1 = ret.Get(1).(int64)
+	}
+
+	if rf, ok := ret.Get(2).(func(repository.ListOptions) error); ok {
+		r2 = rf(opts)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// Update provides a mock function with given fields: team
+func (_m *TeamRepository) Update(team *entity.Team) error {
+	ret := _m.Called(team)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*entity.Team) error); ok {
+		r0 = rf(team)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // NewTeamRepository creates a new instance of TeamRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewTeamRepository(t interface {
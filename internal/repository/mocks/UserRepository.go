@@ -6,6 +6,8 @@ import (
 	entity "hub_management_service/internal/entity"
 
 	mock "github.com/stretchr/testify/mock"
+
+	repository "hub_management_service/internal/repository"
 )
 
 // UserRepository is an autogenerated mock type for the UserRepository type
@@ -27,6 +29,72 @@ func (_m *UserRepository) Create(user *entity.User) error {
 	return r0
 }
 
+// Delete provides a mock function with given fields: id, version
+func (_m *UserRepository) Delete(id uint, version uint) error {
+	ret := _m.Called(id, version)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(uint, uint) error); ok {
+		r0 = rf(id, version)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// FindByEmail provides a mock function with given fields: email
+func (_m *UserRepository) FindByEmail(email string) (*entity.User, error) {
+	ret := _m.Called(email)
+
+	var r0 *entity.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (*entity.User, error)); ok {
+		return rf(email)
+	}
+	if rf, ok := ret.Get(0).(func(string) *entity.User); ok {
+		r0 = rf(email)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(email)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindByEmployeeNumber provides a mock function with given fields: employeeNumber
+func (_m *UserRepository) FindByEmployeeNumber(employeeNumber string) (*entity.User, error) {
+	ret := _m.Called(employeeNumber)
+
+	var r0 *entity.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (*entity.User, error)); ok {
+		return rf(employeeNumber)
+	}
+	if rf, ok := ret.Get(0).(func(string) *entity.User); ok {
+		r0 = rf(employeeNumber)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(employeeNumber)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // FindByID provides a mock function with given fields: id
 func (_m *UserRepository) FindByID(id uint) (*entity.User, error) {
 	ret := _m.Called(id)
@@ -53,6 +121,136 @@ func (_m *UserRepository) FindByID(id uint) (*entity.User, error) {
 	return r0, r1
 }
 
+// FindByOIDCSubject provides a mock function with given fields: subject
+func (_m *UserRepository) FindByOIDCSubject(subject string) (*entity.User, error) {
+	ret := _m.Called(subject)
+
+	var r0 *entity.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (*entity.User, error)); ok {
+		return rf(subject)
+	}
+	if rf, ok := ret.Get(0).(func(string) *entity.User); ok {
+		r0 = rf(subject)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(subject)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindDirectReports provides a mock function with given fields: managerID
+func (_m *UserRepository) FindDirectReports(managerID uint) ([]entity.User, error) {
+	ret := _m.Called(managerID)
+
+	var r0 []entity.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(uint) ([]entity.User, error)); ok {
+		return rf(managerID)
+	}
+	if rf, ok := ret.Get(0).(func(uint) []entity.User); ok {
+		r0 = rf(managerID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(uint) error); ok {
+		r1 = rf(managerID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindMembersByTeamID provides a mock function with given fields: teamID
+func (_m *UserRepository) FindMembersByTeamID(teamID uint) ([]entity.User, error) {
+	ret := _m.Called(teamID)
+
+	var r0 []entity.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(uint) ([]entity.User, error)); ok {
+		return rf(teamID)
+	}
+	if rf, ok := ret.Get(0).(func(uint) []entity.User); ok {
+		r0 = rf(teamID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(uint) error); ok {
+		r1 = rf(teamID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindReportingChain provides a mock function with given fields: userID
+func (_m *UserRepository) FindReportingChain(userID uint) ([]entity.User, error) {
+	ret := _m.Called(userID)
+
+	var r0 []entity.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(uint) ([]entity.User, error)); ok {
+		return rf(userID)
+	}
+	if rf, ok := ret.Get(0).(func(uint) []entity.User); ok {
+		r0 = rf(userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(uint) error); ok {
+		r1 = rf(userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindSkipLevelReports provides a mock function with given fields: managerID
+func (_m *UserRepository) FindSkipLevelReports(managerID uint) ([]entity.User, error) {
+	ret := _m.Called(managerID)
+
+	var r0 []entity.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(uint) ([]entity.User, error)); ok {
+		return rf(managerID)
+	}
+	if rf, ok := ret.Get(0).(func(uint) []entity.User); ok {
+		r0 = rf(managerID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(uint) error); ok {
+		r1 = rf(managerID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // FindUserByTeamID provides a mock function with given fields: teamID
 func (_m *UserRepository) FindUserByTeamID(teamID uint) ([]entity.User, error) {
 	ret := _m.Called(teamID)
@@ -79,6 +277,67 @@ func (_m *UserRepository) FindUserByTeamID(teamID uint) ([]entity.User, error) {
 	return r0, r1
 }
 
+// LinkOIDCSubject provides a mock function with given fields: id, subject
+func (_m *UserRepository) LinkOIDCSubject(id uint, subject string) error {
+	ret := _m.Called(id, subject)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(uint, string) error); ok {
+		r0 = rf(id, subject)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// List provides a mock function with given fields: opts
+func (_m *UserRepository) List(opts repository.ListOptions) ([]entity.User, int64, error) {
+	ret := _m.Called(opts)
+
+	var r0 []entity.User
+	var r1 int64
+	var r2 error
+	if rf, ok := ret.Get(0).(func(repository.ListOptions) ([]entity.User, int64, error)); ok {
+		return rf(opts)
+	}
+	if rf, ok := ret.Get(0).(func(repository.ListOptions) []entity.User); ok {
+		r0 = rf(opts)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(repository.ListOptions) int64); ok {
+		r1 = rf(opts)
+	} else {
+		r1 = ret.Get(1).(int64)
+	}
+
+	if rf, ok := ret.Get(2).(func(repository.ListOptions) error); ok {
+		r2 = rf(opts)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// Update provides a mock function with given fields: user
+func (_m *UserRepository) Update(user *entity.User) error {
+	ret := _m.Called(user)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*entity.User) error); ok {
+		r0 = rf(user)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // NewUserRepository creates a new instance of UserRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewUserRepository(t interface {
@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"errors"
+	"gorm.io/gorm"
+	"hub_management_service/internal/entity"
+)
+
+type RegionRepository interface {
+	Create(region *entity.Region) error
+	FindAll() ([]entity.Region, error)
+	FindByID(id uint) (*entity.Region, error)
+	FindByName(name string) (*entity.Region, error)
+	Update(region *entity.Region) error
+	Delete(id uint) error
+	Rollup() ([]RegionRollup, error)
+}
+
+// RegionRollup counts what is located in a region, users are counted by their primary team
+type RegionRollup struct {
+	RegionID  uint   `json:"region_id"`
+	Name      string `json:"name"`
+	Countries int64  `json:"countries"`
+	Hubs      int64  `json:"hubs"`
+	Teams     int64  `json:"teams"`
+	Users     int64  `json:"users"`
+}
+
+type regionRepository struct {
+	db *gorm.DB
+}
+
+func NewRegionRepository(db *gorm.DB) RegionRepository {
+	return &regionRepository{db: db}
+}
+
+func (r *regionRepository) Create(region *entity.Region) error {
+	return r.db.Omit("Countries").Create(region).Error
+}
+
+// FindAll - Method to find all regions, ordered by name
+func (r *regionRepository) FindAll() ([]entity.Region, error) {
+	var regions []entity.Region
+	err := r.db.Order("name").Find(&regions).Error
+	return regions, err
+}
+
+// FindByID - Method to find a region along with its countries
+func (r *regionRepository) FindByID(id uint) (*entity.Region, error) {
+	var region entity.Region
+	err := r.db.Preload("Countries", func(db *gorm.DB) *gorm.DB {
+		return db.Order("name")
+	}).First(&region, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &region, nil
+}
+
+// FindByName - Method to find a region by name regardless of case, returns nil if no region matches
+func (r *regionRepository) FindByName(name string) (*entity.Region, error) {
+	var region entity.Region
+	err := r.db.Where("LOWER(name) = LOWER(?)", name).First(&region).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &region, nil
+}
+
+func (r *regionRepository) Update(region *entity.Region) error {
+	return r.db.Omit("Countries").Save(region).Error
+}
+
+func (r *regionRepository) Delete(id uint) error {
+	return r.db.Delete(&entity.Region{}, id).Error
+}
+
+// Rollup - Method to count the countries, hubs, teams and users of every region
+func (r *regionRepository) Rollup() ([]RegionRollup, error) {
+	var rollups []RegionRollup
+	err := r.db.Table("regions").
+		Select("regions.id AS region_id, regions.name, COUNT(DISTINCT countries.id) AS countries, COUNT(DISTINCT hubs.id) AS hubs, " +
+			"COUNT(DISTINCT teams.id) AS teams, COUNT(DISTINCT users.id) AS users").
+		Joins("LEFT JOIN countries ON countries.region_id = regions.id").
+		Joins("LEFT JOIN hubs ON hubs.country_id = countries.id").
+		Joins("LEFT JOIN teams ON teams.hub_id = hubs.id").
+		Joins("LEFT JOIN users ON users.team_id = teams.id").
+		Group("regions.id, regions.name").
+		Order("regions.name").
+		Scan(&rollups).Error
+	return rollups, err
+}
@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"hub_management_service/internal/entity"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type RegionRepositoryTestSuite struct {
+	suite.Suite
+	DB          *gorm.DB
+	RegionRepo  RegionRepository
+	CountryRepo CountryRepository
+	HubRepo     HubRepository
+}
+
+func (suite *RegionRepositoryTestSuite) SetupTest() {
+	// Create an in-memory SQLite database
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		suite.T().Fatal("failed to connect to database")
+	}
+	suite.DB = db
+
+	// Auto-migrate the hierarchy down to users for rollups
+	suite.DB.AutoMigrate(&entity.Region{}, &entity.Country{}, &entity.Hub{}, &entity.Team{}, &entity.User{})
+
+	suite.RegionRepo = NewRegionRepository(suite.DB)
+	suite.CountryRepo = NewCountryRepository(suite.DB)
+	suite.HubRepo = NewHubRepository(suite.DB)
+}
+
+func (suite *RegionRepositoryTestSuite) TearDownTest() {
+	// Clean up the database
+	for _, table := range []string{"users", "teams", "hubs", "countries", "regions"} {
+		suite.DB.Exec("DELETE FROM " + table)
+	}
+}
+
+func (suite *RegionRepositoryTestSuite) TestCreateAndFindRegion() {
+	region := &entity.Region{Name: "EMEA"}
+	assert.NoError(suite.T(), suite.RegionRepo.Create(region))
+	assert.NoError(suite.T(), suite.CountryRepo.Create(&entity.Country{Code: "FR", Name: "France", RegionID: region.ID}))
+	assert.NoError(suite.T(), suite.CountryRepo.Create(&entity.Country{Code: "DE", Name: "Germany", RegionID: region.ID}))
+
+	fetched, err := suite.RegionRepo.FindByID(region.ID)
+	assert.NoError(suite.T(), err)
+	if assert.Len(suite.T(), fetched.Countries, 2) {
+		assert.Equal(suite.T(), "FR", fetched.Countries[0].Code)
+	}
+
+	byName, err := suite.RegionRepo.FindByName("emea")
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), region.ID, byName.ID)
+
+	missing, err := suite.RegionRepo.FindByName("APAC")
+	assert.NoError(suite.T(), err)
+	assert.Nil(suite.T(), missing)
+}
+
+func (suite *RegionRepositoryTestSuite) TestFindCountries() {
+	emea := &entity.Region{Name: "EMEA"}
+	apac := &entity.Region{Name: "APAC"}
+	suite.RegionRepo.Create(emea)
+	suite.RegionRepo.Create(apac)
+	suite.CountryRepo.Create(&entity.Country{Code: "FR", Name: "France", RegionID: emea.ID})
+	suite.CountryRepo.Create(&entity.Country{Code: "VN", Name: "Viet Nam", RegionID: apac.ID})
+
+	countries, err := suite.CountryRepo.FindByRegionID(apac.ID)
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), countries, 1)
+
+	country, err := suite.CountryRepo.FindByCode("VN")
+	assert.NoError(suite.T(), err)
+	if assert.NotNil(suite.T(), country) {
+		fetched, err := suite.CountryRepo.FindByID(country.ID)
+		assert.NoError(suite.T(), err)
+		assert.Equal(suite.T(), "APAC", fetched.Region.Name)
+	}
+}
+
+func (suite *RegionRepositoryTestSuite) TestRollup() {
+	emea := &entity.Region{Name: "EMEA"}
+	apac := &entity.Region{Name: "APAC"}
+	suite.RegionRepo.Create(emea)
+	suite.RegionRepo.Create(apac)
+	france := &entity.Country{Code: "FR", Name: "France", RegionID: emea.ID}
+	germany := &entity.Country{Code: "DE", Name: "Germany", RegionID: emea.ID}
+	suite.CountryRepo.Create(france)
+	suite.CountryRepo.Create(germany)
+
+	paris := &entity.Hub{Name: "Paris Hub", Location: "Paris", CountryID: &france.ID, City: "Paris"}
+	berlin := &entity.Hub{Name: "Berlin Hub", Location: "Berlin", CountryID: &germany.ID, City: "Berlin"}
+	suite.HubRepo.Create(paris)
+	suite.HubRepo.Create(berlin)
+	teamA := &entity.Team{Name: "Team A", HubID: paris.ID}
+	teamB := &entity.Team{Name: "Team B", HubID: berlin.ID}
+	suite.DB.Create(teamA)
+	suite.DB.Create(teamB)
+	suite.DB.Create(&[]entity.User{
+		{Name: "Jane", Email: "jane@example.com", TeamID: teamA.ID},
+		{Name: "John", Email: "john@example.com", TeamID: teamA.ID},
+		{Name: "Joan", Email: "joan@example.com", TeamID: teamB.ID},
+	})
+
+	rollups, err := suite.RegionRepo.Rollup()
+	assert.NoError(suite.T(), err)
+	if assert.Len(suite.T(), rollups, 2) {
+		// Ordered by name, regions without hubs are included
+		assert.Equal(suite.T(), RegionRollup{RegionID: apac.ID, Name: "APAC"}, rollups[0])
+		assert.Equal(suite.T(), RegionRollup{RegionID: emea.ID, Name: "EMEA", Countries: 2, Hubs: 2, Teams: 2, Users: 3}, rollups[1])
+	}
+
+	hubs, total, err := suite.HubRepo.List(ListOptions{Where: "country_id = ?", Args: []interface{}{france.ID}})
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), int64(1), total)
+	if assert.Len(suite.T(), hubs, 1) {
+		assert.Equal(suite.T(), "FR", hubs[0].Country.Code)
+	}
+}
+
+func TestRegionRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(RegionRepositoryTestSuite))
+}
@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrStaleVersion is returned when a versioned row was changed by another write after it was read
+var ErrStaleVersion = errors.New("row was changed by another write")
+
+// ListOptions restricts and pages list queries
+type ListOptions struct {
+	Where  string
+	Args   []interface{}
+	Offset int
+	Limit  int
+}
+
+// apply adds the filter and paging of the options to a query
+func (o ListOptions) apply(db *gorm.DB) *gorm.DB {
+	if o.Where != "" {
+		db = db.Where(o.Where, o.Args...)
+	}
+	if o.Offset > 0 {
+		db = db.Offset(o.Offset)
+	}
+	if o.Limit > 0 {
+		db = db.Limit(o.Limit)
+	}
+	return db
+}
+
+// IsNotFound reports whether an error returned by a repository means the record does not exist
+func IsNotFound(err error) bool {
+	return errors.Is(err, gorm.ErrRecordNotFound)
+}
+
+// forUpdate locks the rows read by a query until the end of the transaction db is bound to, so that the checks a unit
+// of work makes on a parent row, such as a hub being empty or having room, still hold when it writes. Concurrent units
+// of work reading the same row wait for the first to finish. Outside of transactions the query is left as is; SQLite
+// has no row locks and serialises writers with its database lock instead.
+func forUpdate(db *gorm.DB) *gorm.DB {
+	if _, ok := db.Statement.ConnPool.(gorm.TxCommitter); ok {
+		return db.Clauses(clause.Locking{Strength: "UPDATE"})
+	}
+	return db
+}
+
+// deleteVersioned deletes the row of value with the ID only while it is still at version, so that a delete cannot
+// remove changes it has not seen
+func deleteVersioned(db *gorm.DB, value interface{}, id, version uint) error {
+	result := db.Where("id = ? AND version = ?", id, version).Delete(value)
+	if result.Error == nil && result.RowsAffected == 0 {
+		return ErrStaleVersion
+	}
+	return result.Error
+}
+
+// saveVersioned saves every column of a row, except the associations in omit, only while the row is still at the
+// version it was read with, and increments the version
+func saveVersioned(db *gorm.DB, value interface{}, version *uint, omit ...string) error {
+	read := *version
+	*version = read + 1
+	result := db.Model(value).Select("*").Omit(omit...).Where("version = ?", read).Updates(value)
+	if result.Error == nil && result.RowsAffected == 0 {
+		result.Error = ErrStaleVersion
+	}
+	if result.Error != nil {
+		*version = read
+	}
+	return result.Error
+}
@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"errors"
+	"gorm.io/gorm"
+	"hub_management_service/internal/entity"
+)
+
+type ResourceRepository interface {
+	Create(resource *entity.Resource) error
+	FindByID(id uint) (*entity.Resource, error)
+	FindByName(hubID uint, name string) (*entity.Resource, error)
+	List(opts ListOptions) ([]entity.Resource, int64, error)
+	Update(resource *entity.Resource) error
+	Delete(id uint) error
+}
+
+type resourceRepository struct {
+	db *gorm.DB
+}
+
+func NewResourceRepository(db *gorm.DB) ResourceRepository {
+	return &resourceRepository{db: db}
+}
+
+// Create - Method to create a resource along with its amenities
+func (r *resourceRepository) Create(resource *entity.Resource) error {
+	return r.db.Omit("Hub").Create(resource).Error
+}
+
+// FindByID - Method to find a resource along with its amenities
+func (r *resourceRepository) FindByID(id uint) (*entity.Resource, error) {
+	var resource entity.Resource
+	err := r.preloadAmenities(r.db).First(&resource, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &resource, nil
+}
+
+// FindByName - Method to find a resource of a hub by name regardless of case, returns nil if no resource matches
+func (r *resourceRepository) FindByName(hubID uint, name string) (*entity.Resource, error) {
+	var resource entity.Resource
+	err := r.db.Where("hub_id = ? AND LOWER(name) = LOWER(?)", hubID, name).First(&resource).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &resource, nil
+}
+
+// List - Method to find a page of resources matching the options along with their amenities, and the total number of matches
+func (r *resourceRepository) List(opts ListOptions) ([]entity.Resource, int64, error) {
+	var total int64
+	if err := (ListOptions{Where: opts.Where, Args: opts.Args}).apply(r.db.Model(&entity.Resource{})).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var resources []entity.Resource
+	err := opts.apply(r.preloadAmenities(r.db)).Order("floor, name, id").Find(&resources).Error
+	return resources, total, err
+}
+
+// Update - Method to save all fields of a resource, replacing its amenities
+func (r *resourceRepository) Update(resource *entity.Resource) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Hub", "Amenities").Save(resource).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("resource_id = ?", resource.ID).Delete(&entity.Amenity{}).Error; err != nil {
+			return err
+		}
+		if len(resource.Amenities) == 0 {
+			return nil
+		}
+		for i := range resource.Amenities {
+			resource.Amenities[i].ResourceID = resource.ID
+		}
+		return tx.Create(&resource.Amenities).Error
+	})
+}
+
+// Delete - Method to delete a resource, its amenities are removed with it
+func (r *resourceRepository) Delete(id uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		// SQLite does not enforce the cascade unless foreign keys are enabled
+		if err := tx.Where("resource_id = ?", id).Delete(&entity.Amenity{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&entity.Resource{}, id).Error
+	})
+}
+
+func (r *resourceRepository) preloadAmenities(db *gorm.DB) *gorm.DB {
+	return db.Preload("Amenities", func(db *gorm.DB) *gorm.DB {
+		return db.Order("name")
+	})
+}
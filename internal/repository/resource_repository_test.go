@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"hub_management_service/internal/entity"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type ResourceRepositoryTestSuite struct {
+	suite.Suite
+	DB           *gorm.DB
+	ResourceRepo ResourceRepository
+	Hub          *entity.Hub
+}
+
+func (suite *ResourceRepositoryTestSuite) SetupTest() {
+	// Create an in-memory SQLite database
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		suite.T().Fatal("failed to connect to database")
+	}
+	// Transactions must see the same in-memory database
+	sqlDB, _ := db.DB()
+	sqlDB.SetMaxOpenConns(1)
+	suite.DB = db
+
+	suite.DB.AutoMigrate(&entity.Hub{}, &entity.Resource{}, &entity.Amenity{})
+	suite.Hub = &entity.Hub{Name: "Paris Hub", Location: "Paris"}
+	suite.DB.Create(suite.Hub)
+
+	suite.ResourceRepo = NewResourceRepository(suite.DB)
+}
+
+func (suite *ResourceRepositoryTestSuite) TearDownTest() {
+	// Clean up the database
+	suite.DB.Exec("DELETE FROM hub_resource_amenities")
+	suite.DB.Exec("DELETE FROM hub_resources")
+	suite.DB.Exec("DELETE FROM hubs")
+}
+
+func seats(n int) *int {
+	return &n
+}
+
+func (suite *ResourceRepositoryTestSuite) TestCreateAndList() {
+	suite.ResourceRepo.Create(&entity.Resource{HubID: suite.Hub.ID, Type: entity.ResourceDesk, Name: "D-101", Floor: "1", Capacity: seats(1),
+		Amenities: []entity.Amenity{{Name: "monitor"}, {Name: "standing"}}})
+	suite.ResourceRepo.Create(&entity.Resource{HubID: suite.Hub.ID, Type: entity.ResourceRoom, Name: "Seine", Floor: "1", Capacity: seats(8),
+		Amenities: []entity.Amenity{{Name: "monitor"}, {Name: "video"}}})
+
+	// Every amenity must match
+	resources, total, err := suite.ResourceRepo.List(ListOptions{
+		Where: "hub_id = ? AND id IN (SELECT resource_id FROM hub_resource_amenities WHERE name = ?) AND id IN (SELECT resource_id FROM hub_resource_amenities WHERE name = ?)",
+		Args:  []interface{}{suite.Hub.ID, "monitor", "video"},
+	})
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), int64(1), total)
+	if assert.Len(suite.T(), resources, 1) {
+		assert.Equal(suite.T(), "Seine", resources[0].Name)
+		assert.Len(suite.T(), resources[0].Amenities, 2)
+	}
+}
+
+func (suite *ResourceRepositoryTestSuite) TestUpdateReplacesAmenities() {
+	resource := &entity.Resource{HubID: suite.Hub.ID, Type: entity.ResourceDesk, Name: "D-101", Capacity: seats(1),
+		Amenities: []entity.Amenity{{Name: "monitor"}}}
+	suite.ResourceRepo.Create(resource)
+
+	resource.Floor = "2"
+	resource.Amenities = []entity.Amenity{{Name: "dock"}, {Name: "standing"}}
+	assert.NoError(suite.T(), suite.ResourceRepo.Update(resource))
+
+	found, err := suite.ResourceRepo.FindByID(resource.ID)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "2", found.Floor)
+	if assert.Len(suite.T(), found.Amenities, 2) {
+		assert.Equal(suite.T(), "dock", found.Amenities[0].Name)
+	}
+
+	assert.NoError(suite.T(), suite.ResourceRepo.Delete(resource.ID))
+	var count int64
+	suite.DB.Model(&entity.Amenity{}).Count(&count)
+	assert.Equal(suite.T(), int64(0), count)
+}
+
+func (suite *ResourceRepositoryTestSuite) TestFindByName() {
+	suite.ResourceRepo.Create(&entity.Resource{HubID: suite.Hub.ID, Type: entity.ResourceRoom, Name: "Seine", Capacity: seats(8)})
+
+	found, err := suite.ResourceRepo.FindByName(suite.Hub.ID, "SEINE")
+	assert.NoError(suite.T(), err)
+	assert.NotNil(suite.T(), found)
+
+	found, err = suite.ResourceRepo.FindByName(suite.Hub.ID, "Loire")
+	assert.NoError(suite.T(), err)
+	assert.Nil(suite.T(), found)
+}
+
+func TestResourceRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(ResourceRepositoryTestSuite))
+}
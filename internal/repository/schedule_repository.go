@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"errors"
+	"gorm.io/gorm"
+	"hub_management_service/internal/entity"
+)
+
+type ScheduleRepository interface {
+	FindOpeningHours(hubID uint) ([]entity.OpeningHours, error)
+	ReplaceOpeningHours(hubID uint, hours []entity.OpeningHours) error
+	FindHolidays(hubID uint, from, to *entity.Date) ([]entity.Holiday, error)
+	FindHolidayByID(id uint) (*entity.Holiday, error)
+	FindHolidayByDate(hubID uint, date entity.Date) (*entity.Holiday, error)
+	CreateHoliday(holiday *entity.Holiday) error
+	DeleteHoliday(id uint) error
+}
+
+type scheduleRepository struct {
+	db *gorm.DB
+}
+
+func NewScheduleRepository(db *gorm.DB) ScheduleRepository {
+	return &scheduleRepository{db: db}
+}
+
+// FindOpeningHours - Method to find the weekly opening hours of a hub, ordered by day and time
+func (r *scheduleRepository) FindOpeningHours(hubID uint) ([]entity.OpeningHours, error) {
+	var hours []entity.OpeningHours
+	err := r.db.Where("hub_id = ?", hubID).Order("weekday, opens").Find(&hours).Error
+	return hours, err
+}
+
+// ReplaceOpeningHours - Method to replace the whole weekly schedule of a hub
+func (r *scheduleRepository) ReplaceOpeningHours(hubID uint, hours []entity.OpeningHours) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("hub_id = ?", hubID).Delete(&entity.OpeningHours{}).Error; err != nil {
+			return err
+		}
+		if len(hours) == 0 {
+			return nil
+		}
+		for i := range hours {
+			hours[i].HubID = hubID
+		}
+		return tx.Omit("Hub").Create(&hours).Error
+	})
+}
+
+// FindHolidays - Method to find the holidays of a hub, optionally between two dates inclusive, ordered by date
+func (r *scheduleRepository) FindHolidays(hubID uint, from, to *entity.Date) ([]entity.Holiday, error) {
+	query := r.db.Where("hub_id = ?", hubID)
+	if from != nil {
+		query = query.Where("date >= ?", *from)
+	}
+	if to != nil {
+		query = query.Where("date <= ?", *to)
+	}
+
+	var holidays []entity.Holiday
+	err := query.Order("date").Find(&holidays).Error
+	return holidays, err
+}
+
+func (r *scheduleRepository) FindHolidayByID(id uint) (*entity.Holiday, error) {
+	var holiday entity.Holiday
+	err := r.db.First(&holiday, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &holiday, nil
+}
+
+// FindHolidayByDate - Method to find the holiday of a hub on a date, returns nil if the date is not a holiday
+func (r *scheduleRepository) FindHolidayByDate(hubID uint, date entity.Date) (*entity.Holiday, error) {
+	var holiday entity.Holiday
+	err := r.db.Where("hub_id = ? AND date = ?", hubID, date).First(&holiday).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &holiday, nil
+}
+
+func (r *scheduleRepository) CreateHoliday(holiday *entity.Holiday) error {
+	return r.db.Omit("Hub").Create(holiday).Error
+}
+
+func (r *scheduleRepository) DeleteHoliday(id uint) error {
+	return r.db.Delete(&entity.Holiday{}, id).Error
+}
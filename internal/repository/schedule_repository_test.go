@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"hub_management_service/internal/entity"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type ScheduleRepositoryTestSuite struct {
+	suite.Suite
+	DB           *gorm.DB
+	ScheduleRepo ScheduleRepository
+	Hub          *entity.Hub
+}
+
+func (suite *ScheduleRepositoryTestSuite) SetupTest() {
+	// Create an in-memory SQLite database
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		suite.T().Fatal("failed to connect to database")
+	}
+	// Transactions must see the same in-memory database
+	sqlDB, _ := db.DB()
+	sqlDB.SetMaxOpenConns(1)
+	suite.DB = db
+
+	suite.DB.AutoMigrate(&entity.Hub{}, &entity.OpeningHours{}, &entity.Holiday{})
+	suite.Hub = &entity.Hub{Name: "Paris Hub", Location: "Paris"}
+	suite.DB.Create(suite.Hub)
+
+	suite.ScheduleRepo = NewScheduleRepository(suite.DB)
+}
+
+func (suite *ScheduleRepositoryTestSuite) TearDownTest() {
+	// Clean up the database
+	suite.DB.Exec("DELETE FROM hub_opening_hours")
+	suite.DB.Exec("DELETE FROM hub_holidays")
+	suite.DB.Exec("DELETE FROM hubs")
+}
+
+func (suite *ScheduleRepositoryTestSuite) TestReplaceOpeningHours() {
+	err := suite.ScheduleRepo.ReplaceOpeningHours(suite.Hub.ID, []entity.OpeningHours{
+		{Weekday: time.Monday, Opens: "13:00", Closes: "18:00"},
+		{Weekday: time.Monday, Opens: "09:00", Closes: "12:00"},
+	})
+	assert.NoError(suite.T(), err)
+
+	// Replacing drops the previous schedule
+	err = suite.ScheduleRepo.ReplaceOpeningHours(suite.Hub.ID, []entity.OpeningHours{
+		{Weekday: time.Tuesday, Opens: "13:00", Closes: "18:00"},
+		{Weekday: time.Monday, Opens: "09:00", Closes: "17:00"},
+	})
+	assert.NoError(suite.T(), err)
+
+	hours, err := suite.ScheduleRepo.FindOpeningHours(suite.Hub.ID)
+	assert.NoError(suite.T(), err)
+	if assert.Len(suite.T(), hours, 2) {
+		assert.Equal(suite.T(), time.Monday, hours[0].Weekday)
+		assert.Equal(suite.T(), "17:00", hours[0].Closes)
+	}
+
+	assert.NoError(suite.T(), suite.ScheduleRepo.ReplaceOpeningHours(suite.Hub.ID, nil))
+	hours, _ = suite.ScheduleRepo.FindOpeningHours(suite.Hub.ID)
+	assert.Empty(suite.T(), hours)
+}
+
+func (suite *ScheduleRepositoryTestSuite) TestHolidays() {
+	for _, holiday := range []*entity.Holiday{
+		{HubID: suite.Hub.ID, Date: entity.NewDate(2024, time.December, 25), Name: "Christmas"},
+		{HubID: suite.Hub.ID, Date: entity.NewDate(2024, time.July, 14), Name: "Bastille Day"},
+		{HubID: suite.Hub.ID, Date: entity.NewDate(2025, time.January, 1), Name: "New Year"},
+	} {
+		assert.NoError(suite.T(), suite.ScheduleRepo.CreateHoliday(holiday))
+	}
+
+	from, to := entity.NewDate(2024, time.January, 1), entity.NewDate(2024, time.December, 31)
+	holidays, err := suite.ScheduleRepo.FindHolidays(suite.Hub.ID, &from, &to)
+	assert.NoError(suite.T(), err)
+	if assert.Len(suite.T(), holidays, 2) {
+		assert.Equal(suite.T(), "Bastille Day", holidays[0].Name)
+	}
+
+	holiday, err := suite.ScheduleRepo.FindHolidayByDate(suite.Hub.ID, entity.NewDate(2025, time.January, 1))
+	assert.NoError(suite.T(), err)
+	if assert.NotNil(suite.T(), holiday) {
+		assert.NoError(suite.T(), suite.ScheduleRepo.DeleteHoliday(holiday.ID))
+	}
+
+	holiday, err = suite.ScheduleRepo.FindHolidayByDate(suite.Hub.ID, entity.NewDate(2025, time.January, 1))
+	assert.NoError(suite.T(), err)
+	assert.Nil(suite.T(), holiday)
+}
+
+func TestScheduleRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(ScheduleRepositoryTestSuite))
+}
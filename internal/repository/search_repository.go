@@ -0,0 +1,224 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"hub_management_service/internal/entity"
+	"hub_management_service/pkg/fuzzy"
+	"strings"
+	"sync"
+)
+
+// SearchSimilarityThreshold is the trigram word similarity above which Postgres keeps a candidate,
+// low enough for a swap of two letters in a short word
+const SearchSimilarityThreshold = 0.3
+
+var errFullTextUnavailable = errors.New("SQLite was built without FTS5")
+
+// SearchRepository finds the candidates of a search by name, location or email. Candidates come roughly
+// ordered by relevance and the caller ranks them.
+type SearchRepository interface {
+	SearchHubs(query string, limit int) ([]entity.Hub, error)
+	SearchTeams(query string, limit int) ([]entity.Team, error)
+	SearchUsers(query string, limit int) ([]entity.User, error)
+}
+
+// searchTarget describes the searched columns of a table and the rows kept
+type searchTarget struct {
+	kind    string
+	table   string
+	columns []string
+	where   string
+}
+
+var (
+	hubSearch  = searchTarget{kind: "hub", table: "hubs", columns: []string{"name", "location"}}
+	teamSearch = searchTarget{kind: "team", table: "teams", columns: []string{"name"}}
+	userSearch = searchTarget{kind: "user", table: "users", columns: []string{"name", "email"}, where: "users.deactivated_at IS NULL"}
+)
+
+// document is the expression of the text indexed for full-text search, the columns of the row qualified by
+// qualifier joined by spaces. It must match the expression of the full-text indexes.
+func (t searchTarget) document(qualifier string) string {
+	columns := make([]string, len(t.columns))
+	for i, column := range t.columns {
+		columns[i] = "COALESCE(" + qualifier + column + ", '')"
+	}
+	return strings.Join(columns, " || ' ' || ")
+}
+
+type searchRepository struct {
+	db *gorm.DB
+
+	ftsOnce sync.Once
+	fts     bool
+}
+
+func NewSearchRepository(db *gorm.DB) SearchRepository {
+	return &searchRepository{db: db}
+}
+
+func (r *searchRepository) SearchHubs(query string, limit int) ([]entity.Hub, error) {
+	var hubs []entity.Hub
+	err := r.search(hubSearch, query, limit, &hubs)
+	return hubs, err
+}
+
+func (r *searchRepository) SearchTeams(query string, limit int) ([]entity.Team, error) {
+	var teams []entity.Team
+	err := r.search(teamSearch, query, limit, &teams)
+	return teams, err
+}
+
+// SearchUsers searches active users only
+func (r *searchRepository) SearchUsers(query string, limit int) ([]entity.User, error) {
+	var users []entity.User
+	err := r.search(userSearch, query, limit, &users)
+	return users, err
+}
+
+func (r *searchRepository) search(target searchTarget, query string, limit int, dest interface{}) error {
+	words := fuzzy.Tokens(query)
+	if len(words) == 0 {
+		return nil
+	}
+	if r.db.Dialector.Name() == "postgres" {
+		return r.searchTrigram(target, query, words, limit, dest)
+	}
+	r.ftsOnce.Do(func() { r.fts = r.ensureFullTextIndex() == nil })
+	if r.fts {
+		return r.searchFullText(target, words, limit, dest)
+	}
+	return r.searchLike(target, words, limit, dest)
+}
+
+// searchTrigram keeps the rows with a word similar to the query, through the pg_trgm indexes, or whose text search
+// document contains words starting with the query words, and orders them by similarity and text search rank
+func (r *searchRepository) searchTrigram(target searchTarget, query string, words []string, limit int, dest interface{}) error {
+	prefixes := make([]string, len(words))
+	for i, word := range words {
+		prefixes[i] = word + ":*"
+	}
+	tsQuery := strings.Join(prefixes, " & ")
+	document := fmt.Sprintf("to_tsvector('simple', %s)", target.document(""))
+
+	conditions := make([]string, 0, len(target.columns)+1)
+	similarities := make([]string, 0, len(target.columns))
+	args := make([]interface{}, 0, len(target.columns)+1)
+	orderArgs := make([]interface{}, 0, len(target.columns)+1)
+	for _, column := range target.columns {
+		conditions = append(conditions, "? <% "+column)
+		similarities = append(similarities, "word_similarity(?, "+column+")")
+		args = append(args, query)
+		orderArgs = append(orderArgs, query)
+	}
+	conditions = append(conditions, document+" @@ to_tsquery('simple', ?)")
+	args = append(args, tsQuery)
+	orderArgs = append(orderArgs, tsQuery)
+	order := fmt.Sprintf("GREATEST(%s) + ts_rank(%s, to_tsquery('simple', ?)) DESC, id",
+		strings.Join(similarities, ", "), document)
+
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		// The <% operator uses the session threshold, which only lasts for the transaction
+		err := tx.Exec("SELECT set_config('pg_trgm.word_similarity_threshold', ?, true)",
+			fmt.Sprint(SearchSimilarityThreshold)).Error
+		if err != nil {
+			return err
+		}
+		db := tx.Table(target.table).Where(strings.Join(conditions, " OR "), args...)
+		if target.where != "" {
+			db = db.Where(target.where)
+		}
+		return ListOptions{Limit: limit}.apply(db).Order(clause.Expr{SQL: order, Vars: orderArgs, WithoutParentheses: true}).Find(dest).Error
+	})
+}
+
+// ensureFullTextIndex creates the FTS5 index of SQLite along with the triggers keeping it up to date,
+// and fails when SQLite was built without FTS5
+func (r *searchRepository) ensureFullTextIndex() error {
+	var available bool
+	if err := r.db.Raw("SELECT sqlite_compileoption_used('ENABLE_FTS5')").Scan(&available).Error; err != nil {
+		return err
+	}
+	if !available {
+		return errFullTextUnavailable
+	}
+	var exists int64
+	if err := r.db.Raw("SELECT COUNT(*) FROM sqlite_master WHERE name = 'search_index'").Scan(&exists).Error; err != nil {
+		return err
+	}
+	if exists > 0 {
+		return nil
+	}
+
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		// Index the start of every word on its first two letters, the shortest prefix a query looks up
+		err := tx.Exec("CREATE VIRTUAL TABLE search_index USING fts5(kind UNINDEXED, ref UNINDEXED, document, prefix = '2')").Error
+		if err != nil {
+			return err
+		}
+		for _, target := range []searchTarget{hubSearch, teamSearch, userSearch} {
+			insert := fmt.Sprintf("INSERT INTO search_index (kind, ref, document) VALUES ('%s', new.id, %s);",
+				target.kind, target.document("new."))
+			remove := fmt.Sprintf("DELETE FROM search_index WHERE kind = '%s' AND ref = old.id;", target.kind)
+			statements := []string{
+				fmt.Sprintf("INSERT INTO search_index (kind, ref, document) SELECT '%s', id, %s FROM %s",
+					target.kind, target.document(""), target.table),
+				fmt.Sprintf("CREATE TRIGGER %s_search_insert AFTER INSERT ON %s BEGIN %s END", target.table, target.table, insert),
+				fmt.Sprintf("CREATE TRIGGER %s_search_update AFTER UPDATE ON %s BEGIN %s %s END", target.table, target.table, remove, insert),
+				fmt.Sprintf("CREATE TRIGGER %s_search_delete AFTER DELETE ON %s BEGIN %s END", target.table, target.table, remove),
+			}
+			for _, statement := range statements {
+				if err := tx.Exec(statement).Error; err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
+
+// searchFullText keeps the rows with a word starting with each query word or with its first two letters,
+// which tolerates typos after the second letter, best matches first
+func (r *searchRepository) searchFullText(target searchTarget, words []string, limit int, dest interface{}) error {
+	terms := make([]string, len(words))
+	for i, word := range words {
+		terms[i] = fmt.Sprintf(`"%s"*`, word)
+		if prefix := []rune(word); len(prefix) > 2 {
+			terms[i] = fmt.Sprintf(`(%s OR "%s"*)`, terms[i], string(prefix[:2]))
+		}
+	}
+
+	db := r.db.Table(target.table).Select(target.table+".*").
+		Joins(fmt.Sprintf("JOIN search_index ON search_index.ref = %s.id AND search_index.kind = ?", target.table), target.kind).
+		Where("search_index MATCH ?", strings.Join(terms, " OR "))
+	if target.where != "" {
+		db = db.Where(target.where)
+	}
+	return ListOptions{Limit: limit}.apply(db).Order("search_index.rank").Find(dest).Error
+}
+
+// searchLike keeps the rows with a column containing the first two letters of a query word,
+// for SQLite builds without FTS5
+func (r *searchRepository) searchLike(target searchTarget, words []string, limit int, dest interface{}) error {
+	var conditions []string
+	var args []interface{}
+	for _, word := range words {
+		prefix := []rune(word)
+		if len(prefix) > 2 {
+			prefix = prefix[:2]
+		}
+		for _, column := range target.columns {
+			conditions = append(conditions, "LOWER("+column+") LIKE ?")
+			args = append(args, "%"+string(prefix)+"%")
+		}
+	}
+
+	db := r.db.Table(target.table).Where(strings.Join(conditions, " OR "), args...)
+	if target.where != "" {
+		db = db.Where(target.where)
+	}
+	return ListOptions{Limit: limit}.apply(db).Order("id").Find(dest).Error
+}
@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"hub_management_service/internal/entity"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type SearchRepositoryTestSuite struct {
+	suite.Suite
+	DB         *gorm.DB
+	SearchRepo SearchRepository
+	Hub        entity.Hub
+	Team       entity.Team
+}
+
+func (suite *SearchRepositoryTestSuite) SetupTest() {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		suite.T().Fatal("failed to connect to database")
+	}
+	sqlDB, _ := db.DB()
+	sqlDB.SetMaxOpenConns(1) // The full-text index must live in the same in-memory database
+	suite.DB = db
+	suite.DB.AutoMigrate(&entity.Hub{}, &entity.Team{}, &entity.User{})
+
+	suite.Hub = entity.Hub{Name: "Paris Hub", Location: "Paris"}
+	suite.DB.Create(&suite.Hub)
+	suite.DB.Create(&entity.Hub{Name: "London Hub", Location: "London"})
+	suite.Team = entity.Team{Name: "Payments", HubID: suite.Hub.ID}
+	suite.DB.Create(&suite.Team)
+	suite.DB.Create(&entity.Team{Name: "Design", HubID: suite.Hub.ID})
+	suite.SearchRepo = NewSearchRepository(suite.DB)
+}
+
+func (suite *SearchRepositoryTestSuite) TestSearchHubs() {
+	// Location matches as well as name, case is ignored
+	hubs, err := suite.SearchRepo.SearchHubs("PARIS", 10)
+	assert.NoError(suite.T(), err)
+	if assert.NotEmpty(suite.T(), hubs) {
+		assert.Equal(suite.T(), "Paris Hub", hubs[0].Name)
+	}
+
+	// Swapped letters after the start of the word still find the hub
+	hubs, err = suite.SearchRepo.SearchHubs("pairs", 10)
+	assert.NoError(suite.T(), err)
+	assert.Contains(suite.T(), hubNames(hubs), "Paris Hub")
+
+	hubs, err = suite.SearchRepo.SearchHubs("zzz", 10)
+	assert.NoError(suite.T(), err)
+	assert.Empty(suite.T(), hubs)
+
+	// Punctuation alone searches nothing
+	hubs, err = suite.SearchRepo.SearchHubs("%_", 10)
+	assert.NoError(suite.T(), err)
+	assert.Empty(suite.T(), hubs)
+}
+
+func (suite *SearchRepositoryTestSuite) TestSearchTeams() {
+	teams, err := suite.SearchRepo.SearchTeams("paym", 10)
+	assert.NoError(suite.T(), err)
+	if assert.Len(suite.T(), teams, 1) {
+		assert.Equal(suite.T(), suite.Team.ID, teams[0].ID)
+	}
+}
+
+func (suite *SearchRepositoryTestSuite) TestSearchUsers() {
+	now := time.Now()
+	suite.DB.Create(&entity.User{Name: "Jane Doe", Email: "jane.doe@example.com", TeamID: suite.Team.ID})
+	suite.DB.Create(&entity.User{Name: "Janet Former", Email: "janet@example.com", TeamID: suite.Team.ID, DeactivatedAt: &now})
+
+	// Users created after the first search are found as well
+	users, err := suite.SearchRepo.SearchUsers("doe", 10)
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), users, 1)
+
+	// Email matches, deactivated users are left out
+	users, err = suite.SearchRepo.SearchUsers("jane@", 10)
+	assert.NoError(suite.T(), err)
+	if assert.Len(suite.T(), users, 1) {
+		assert.Equal(suite.T(), "Jane Doe", users[0].Name)
+	}
+
+	// Renamed users are found under their new name
+	suite.DB.Model(&entity.User{}).Where("email = ?", "jane.doe@example.com").Update("name", "Jane Smith")
+	users, err = suite.SearchRepo.SearchUsers("smith", 10)
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), users, 1)
+
+	users, err = suite.SearchRepo.SearchUsers("jane", 0)
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), users, 1)
+}
+
+func hubNames(hubs []entity.Hub) []string {
+	names := make([]string, len(hubs))
+	for i, hub := range hubs {
+		names[i] = hub.Name
+	}
+	return names
+}
+
+func TestSearchRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(SearchRepositoryTestSuite))
+}
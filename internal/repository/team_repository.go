@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"errors"
 	"gorm.io/gorm"
 	"hub_management_service/internal/entity"
 )
@@ -10,8 +11,18 @@ type TeamRepository interface {
 	FindAll() ([]entity.Team, error)
 	FindByHubID(hubID uint) ([]entity.Team, error)
 	FindByID(id uint) (*entity.Team, error)
+	Update(team *entity.Team) error
+	Delete(id, version uint) error
+	List(opts ListOptions) ([]entity.Team, int64, error)
+	FindChildren(parentID uint) ([]entity.Team, error)
+	FindDescendants(teamID uint) ([]entity.Team, error)
+	FindAncestors(teamID uint) ([]entity.Team, error)
+	CountUsers(teamIDs []uint) (map[uint]int64, error)
 }
 
+// MaxTeamDepth bounds team tree lookups, which also guards against cycles in existing data
+const MaxTeamDepth = 100
+
 type teamRepository struct {
 	db *gorm.DB
 }
@@ -21,7 +32,7 @@ func NewTeamRepository(db *gorm.DB) TeamRepository {
 }
 
 func (r *teamRepository) Create(team *entity.Team) error {
-	return r.db.Create(team).Error
+	return r.db.Omit("Users").Create(team).Error
 }
 
 func (r *teamRepository) FindAll() ([]entity.Team, error) {
@@ -36,11 +47,159 @@ func (r *teamRepository) FindByHubID(hubID uint) ([]entity.Team, error) {
 	return teams, err
 }
 
+// FindByID finds a team, locking it until the end of the transaction when called within one
 func (r *teamRepository) FindByID(id uint) (*entity.Team, error) {
 	var team entity.Team
-	err := r.db.First(&team, id).Error
+	err := forUpdate(r.db).First(&team, id).Error
 	if err != nil {
 		return nil, err
 	}
 	return &team, nil
 }
+
+// Update saves the team, returns ErrStaleVersion if it was changed since it was read
+func (r *teamRepository) Update(team *entity.Team) error {
+	return saveVersioned(r.db, team, &team.Version, "Hub", "Users")
+}
+
+// Delete deletes a team by its ID while it is still at version
+func (r *teamRepository) Delete(id, version uint) error {
+	return deleteVersioned(r.db, &entity.Team{}, id, version)
+}
+
+// List finds a page of teams matching the options, along with the total number of matches
+func (r *teamRepository) List(opts ListOptions) ([]entity.Team, int64, error) {
+	var total int64
+	if err := (ListOptions{Where: opts.Where, Args: opts.Args}).apply(r.db.Model(&entity.Team{})).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var teams []entity.Team
+	err := opts.apply(r.db).Order("id").Find(&teams).Error
+	return teams, total, err
+}
+
+// FindChildren finds the direct sub-teams of a team
+func (r *teamRepository) FindChildren(parentID uint) ([]entity.Team, error) {
+	var teams []entity.Team
+	err := r.db.Where("parent_id = ?", parentID).Order("id").Find(&teams).Error
+	return teams, err
+}
+
+// FindDescendants finds all sub-teams below a team, ordered by depth
+func (r *teamRepository) FindDescendants(teamID uint) ([]entity.Team, error) {
+	if r.db.Dialector.Name() == "postgres" {
+		return r.findDescendantsRecursive(teamID)
+	}
+	return r.findDescendantsIterative(teamID)
+}
+
+// findDescendantsRecursive walks the tree in a single recursive query, the path stops the walk at cycles
+func (r *teamRepository) findDescendantsRecursive(teamID uint) ([]entity.Team, error) {
+	var teams []entity.Team
+	err := r.db.Raw(`
+		WITH RECURSIVE tree (id, depth, path) AS (
+			SELECT id, 0, ARRAY[id] FROM teams WHERE id = ?
+			UNION ALL
+			SELECT t.id, tree.depth + 1, tree.path || t.id
+			FROM teams t JOIN tree ON t.parent_id = tree.id
+			WHERE NOT t.id = ANY(tree.path) AND tree.depth < ?
+		)
+		SELECT teams.* FROM teams JOIN tree ON teams.id = tree.id
+		WHERE tree.depth > 0
+		ORDER BY tree.depth, teams.id`, teamID, MaxTeamDepth).Scan(&teams).Error
+	return teams, err
+}
+
+// findDescendantsIterative fetches the tree one level at a time, for databases without recursive CTE support
+func (r *teamRepository) findDescendantsIterative(teamID uint) ([]entity.Team, error) {
+	var descendants []entity.Team
+	seen := map[uint]bool{teamID: true}
+	level := []uint{teamID}
+	for depth := 0; len(level) > 0 && depth < MaxTeamDepth; depth++ {
+		var children []entity.Team
+		if err := r.db.Where("parent_id IN ?", level).Order("id").Find(&children).Error; err != nil {
+			return nil, err
+		}
+		level = nil
+		for _, child := range children {
+			if !seen[child.ID] {
+				seen[child.ID] = true
+				descendants = append(descendants, child)
+				level = append(level, child.ID)
+			}
+		}
+	}
+	return descendants, nil
+}
+
+// FindAncestors finds the parent teams above a team, nearest first
+func (r *teamRepository) FindAncestors(teamID uint) ([]entity.Team, error) {
+	if r.db.Dialector.Name() == "postgres" {
+		return r.findAncestorsRecursive(teamID)
+	}
+	return r.findAncestorsIterative(teamID)
+}
+
+// findAncestorsRecursive walks up the tree in a single recursive query, the path stops the walk at cycles
+func (r *teamRepository) findAncestorsRecursive(teamID uint) ([]entity.Team, error) {
+	var teams []entity.Team
+	err := r.db.Raw(`
+		WITH RECURSIVE chain (id, parent_id, depth, path) AS (
+			SELECT id, parent_id, 0, ARRAY[id] FROM teams WHERE id = ?
+			UNION ALL
+			SELECT t.id, t.parent_id, chain.depth + 1, chain.path || t.id
+			FROM teams t JOIN chain ON t.id = chain.parent_id
+			WHERE NOT t.id = ANY(chain.path) AND chain.depth < ?
+		)
+		SELECT teams.* FROM teams JOIN chain ON teams.id = chain.id
+		WHERE chain.depth > 0
+		ORDER BY chain.depth`, teamID, MaxTeamDepth).Scan(&teams).Error
+	return teams, err
+}
+
+// findAncestorsIterative follows parent_id one query at a time, for databases without recursive CTE support
+func (r *teamRepository) findAncestorsIterative(teamID uint) ([]entity.Team, error) {
+	var team entity.Team
+	if err := r.db.Select("id", "parent_id").First(&team, teamID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var ancestors []entity.Team
+	seen := map[uint]bool{team.ID: true}
+	for parentID := team.ParentID; parentID != nil && !seen[*parentID] && len(ancestors) < MaxTeamDepth; {
+		var parent entity.Team
+		if err := r.db.First(&parent, *parentID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				break
+			}
+			return nil, err
+		}
+		seen[parent.ID] = true
+		ancestors = append(ancestors, parent)
+		parentID = parent.ParentID
+	}
+	return ancestors, nil
+}
+
+// CountUsers counts the users whose primary team is one of the given teams, by team
+func (r *teamRepository) CountUsers(teamIDs []uint) (map[uint]int64, error) {
+	var rows []struct {
+		TeamID uint
+		Count  int64
+	}
+	err := r.db.Model(&entity.User{}).Select("team_id, COUNT(*) AS count").
+		Where("team_id IN ?", teamIDs).Group("team_id").Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[uint]int64, len(rows))
+	for _, row := range rows {
+		counts[row.TeamID] = row.Count
+	}
+	return counts, nil
+}
@@ -24,8 +24,8 @@ func (suite *TeamRepositoryTestSuite) SetupTest() {
 	}
 	suite.DB = db
 
-	// Auto-migrate the Team entity
-	suite.DB.AutoMigrate(&entity.Team{})
+	// Auto-migrate the Team entity, and User for headcounts
+	suite.DB.AutoMigrate(&entity.Team{}, &entity.User{})
 
 	// Initialize the TeamRepository
 	suite.TeamRepo = NewTeamRepository(suite.DB)
@@ -33,6 +33,7 @@ func (suite *TeamRepositoryTestSuite) SetupTest() {
 
 func (suite *TeamRepositoryTestSuite) TearDownTest() {
 	// Clean up the database
+	suite.DB.Exec("DELETE FROM users")
 	suite.DB.Exec("DELETE FROM teams")
 }
 
@@ -65,6 +66,110 @@ func (suite *TeamRepositoryTestSuite) TestFindTeamsByHubID() {
 	assert.Len(suite.T(), teams, 2)
 }
 
+func (suite *TeamRepositoryTestSuite) TestListTeams() {
+	suite.TeamRepo.Create(&entity.Team{Name: "Team A", HubID: 1})
+	suite.TeamRepo.Create(&entity.Team{Name: "Team B", HubID: 2})
+
+	teams, total, err := suite.TeamRepo.List(ListOptions{Where: "hub_id = ?", Args: []interface{}{2}})
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), int64(1), total)
+	assert.Equal(suite.T(), "Team B", teams[0].Name)
+}
+
+func (suite *TeamRepositoryTestSuite) TestUpdateAndDeleteTeam() {
+	team := &entity.Team{Name: "Team A", HubID: 1}
+	suite.TeamRepo.Create(team)
+
+	team.Name = "Team Renamed"
+	assert.NoError(suite.T(), suite.TeamRepo.Update(team))
+	fetchedTeam, _ := suite.TeamRepo.FindByID(team.ID)
+	assert.Equal(suite.T(), "Team Renamed", fetchedTeam.Name)
+
+	assert.ErrorIs(suite.T(), suite.TeamRepo.Delete(team.ID, team.Version-1), ErrStaleVersion)
+	assert.NoError(suite.T(), suite.TeamRepo.Delete(team.ID, team.Version))
+	_, err := suite.TeamRepo.FindByID(team.ID)
+	assert.True(suite.T(), IsNotFound(err))
+}
+
+// createTree creates a tribe with two squads, one of which has a chapter below it
+func (suite *TeamRepositoryTestSuite) createTree() (tribe, squadA, squadB, chapter *entity.Team) {
+	tribe = &entity.Team{Name: "Tribe", HubID: 1}
+	suite.Require().NoError(suite.TeamRepo.Create(tribe))
+	squadA = &entity.Team{Name: "Squad A", HubID: 1, ParentID: &tribe.ID}
+	suite.Require().NoError(suite.TeamRepo.Create(squadA))
+	squadB = &entity.Team{Name: "Squad B", HubID: 1, ParentID: &tribe.ID}
+	suite.Require().NoError(suite.TeamRepo.Create(squadB))
+	chapter = &entity.Team{Name: "Chapter", HubID: 1, ParentID: &squadA.ID}
+	suite.Require().NoError(suite.TeamRepo.Create(chapter))
+	return tribe, squadA, squadB, chapter
+}
+
+func (suite *TeamRepositoryTestSuite) TestFindChildrenAndDescendants() {
+	tribe, squadA, squadB, chapter := suite.createTree()
+
+	children, err := suite.TeamRepo.FindChildren(tribe.ID)
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), children, 2)
+
+	descendants, err := suite.TeamRepo.FindDescendants(tribe.ID)
+	assert.NoError(suite.T(), err)
+	if assert.Len(suite.T(), descendants, 3) {
+		// Ordered by depth
+		assert.Equal(suite.T(), []uint{squadA.ID, squadB.ID, chapter.ID}, []uint{descendants[0].ID, descendants[1].ID, descendants[2].ID})
+	}
+
+	descendants, err = suite.TeamRepo.FindDescendants(chapter.ID)
+	assert.NoError(suite.T(), err)
+	assert.Empty(suite.T(), descendants)
+}
+
+func (suite *TeamRepositoryTestSuite) TestFindAncestors() {
+	tribe, squadA, _, chapter := suite.createTree()
+
+	ancestors, err := suite.TeamRepo.FindAncestors(chapter.ID)
+	assert.NoError(suite.T(), err)
+	if assert.Len(suite.T(), ancestors, 2) {
+		// Nearest first
+		assert.Equal(suite.T(), squadA.ID, ancestors[0].ID)
+		assert.Equal(suite.T(), tribe.ID, ancestors[1].ID)
+	}
+
+	ancestors, err = suite.TeamRepo.FindAncestors(tribe.ID)
+	assert.NoError(suite.T(), err)
+	assert.Empty(suite.T(), ancestors)
+}
+
+func (suite *TeamRepositoryTestSuite) TestFindTreeStopsAtCycles() {
+	teamA := &entity.Team{Name: "Team A", HubID: 1}
+	suite.Require().NoError(suite.TeamRepo.Create(teamA))
+	teamB := &entity.Team{Name: "Team B", HubID: 1, ParentID: &teamA.ID}
+	suite.Require().NoError(suite.TeamRepo.Create(teamB))
+	suite.DB.Model(teamA).Update("parent_id", teamB.ID)
+
+	ancestors, err := suite.TeamRepo.FindAncestors(teamA.ID)
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), ancestors, 1)
+
+	descendants, err := suite.TeamRepo.FindDescendants(teamA.ID)
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), descendants, 1)
+}
+
+func (suite *TeamRepositoryTestSuite) TestCountUsers() {
+	tribe, squadA, squadB, _ := suite.createTree()
+	suite.DB.Create(&[]entity.User{
+		{Name: "Jane", Email: "jane@example.com", TeamID: squadA.ID},
+		{Name: "John", Email: "john@example.com", TeamID: squadA.ID},
+		{Name: "Joan", Email: "joan@example.com", TeamID: tribe.ID},
+	})
+
+	counts, err := suite.TeamRepo.CountUsers([]uint{tribe.ID, squadA.ID, squadB.ID})
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), int64(1), counts[tribe.ID])
+	assert.Equal(suite.T(), int64(2), counts[squadA.ID])
+	assert.Equal(suite.T(), int64(0), counts[squadB.ID])
+}
+
 func TestTeamRepositoryTestSuite(t *testing.T) {
 	suite.Run(t, new(TeamRepositoryTestSuite))
 }
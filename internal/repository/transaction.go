@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"gorm.io/gorm"
+)
+
+// ErrSavepoint reports that a savepoint could not be created or rolled back to, the transaction is then unusable
+var ErrSavepoint = errors.New("savepoint failed")
+
+// Repositories groups the repositories bound to one database or transaction, it is the unit of work of the services
+type Repositories struct {
+	Hubs        HubRepository
+	Teams       TeamRepository
+	Users       UserRepository
+	Countries   CountryRepository
+	Regions     RegionRepository
+	Memberships MembershipRepository
+	MFA         MFARepository
+	Schedules   ScheduleRepository
+	Resources   ResourceRepository
+	Bookings    BookingRepository
+	Attendance  AttendanceRepository
+	db          *gorm.DB
+}
+
+// Transactor runs work against repositories that share a single transaction
+type Transactor interface {
+	// Transaction commits when fn returns nil and rolls everything back otherwise
+	Transaction(fn func(repos Repositories) error) error
+}
+
+// NewRepositories binds every repository to db
+func NewRepositories(db *gorm.DB) Repositories {
+	return Repositories{
+		Hubs:        NewHubRepository(db),
+		Teams:       NewTeamRepository(db),
+		Users:       NewUserRepository(db),
+		Countries:   NewCountryRepository(db),
+		Regions:     NewRegionRepository(db),
+		Memberships: NewMembershipRepository(db),
+		MFA:         NewMFARepository(db),
+		Schedules:   NewScheduleRepository(db),
+		Resources:   NewResourceRepository(db),
+		Bookings:    NewBookingRepository(db),
+		Attendance:  NewAttendanceRepository(db),
+		db:          db,
+	}
+}
+
+func NewTransactor(db *gorm.DB) Transactor {
+	return NewRepositories(db)
+}
+
+// Transaction runs fn with the repositories bound to a new transaction. When the repositories are already bound
+// to a transaction, the new one is nested within it as a savepoint. Repositories built outside of NewRepositories,
+// such as mocks, have no database and simply run fn.
+func (r Repositories) Transaction(fn func(repos Repositories) error) error {
+	if r.db == nil {
+		return fn(r)
+	}
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return fn(NewRepositories(tx))
+	})
+}
+
+// Savepoint runs fn within a savepoint: when fn fails its writes are undone and the transaction can go on.
+// Repositories without a database have no savepoints and simply run fn.
+func (r Repositories) Savepoint(name string, fn func() error) error {
+	if r.db == nil {
+		return fn()
+	}
+	if err := r.db.SavePoint(name).Error; err != nil {
+		return fmt.Errorf("%w: creating %s: %v", ErrSavepoint, name, err)
+	}
+	if err := fn(); err != nil {
+		if rollbackErr := r.db.RollbackTo(name).Error; rollbackErr != nil {
+			return fmt.Errorf("%w: rolling back to %s: %v", ErrSavepoint, name, rollbackErr)
+		}
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"hub_management_service/internal/entity"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// postgresTestDB connects to the database in TEST_POSTGRES_DSN, row locks cannot be tested on SQLite
+func postgresTestDB(t *testing.T) *gorm.DB {
+	dsn := os.Getenv("TEST_POSTGRES_DSN")
+	if dsn == "" {
+		t.Skip("TEST_POSTGRES_DSN is not set")
+	}
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("failed to connect to database: %v", err)
+	}
+	if err := db.AutoMigrate(&entity.Hub{}, &entity.Team{}); err != nil {
+		t.Fatalf("failed to migrate: %v", err)
+	}
+	return db
+}
+
+// TestFindByIDLocksWithinTransaction tests that a unit of work checking that a hub is empty waits for a concurrent
+// one that read the hub to create a team in it, and then sees the team
+func TestFindByIDLocksWithinTransaction(t *testing.T) {
+	db := postgresTestDB(t)
+	transactor := NewTransactor(db)
+	hub := &entity.Hub{Name: "Lock Hub", Location: "Paris"}
+	if err := db.Create(hub).Error; err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		db.Where("hub_id = ?", hub.ID).Delete(&entity.Team{})
+		db.Delete(hub)
+	})
+
+	locked := make(chan struct{})
+	created := make(chan error, 1)
+	go func() {
+		created <- transactor.Transaction(func(repos Repositories) error {
+			if _, err := repos.Hubs.FindByID(hub.ID); err != nil {
+				return err
+			}
+			close(locked)
+			// Without the lock, the concurrent check would find the hub empty meanwhile
+			time.Sleep(200 * time.Millisecond)
+			return repos.Teams.Create(&entity.Team{Name: "Backend", HubID: hub.ID})
+		})
+	}()
+	<-locked
+
+	var teams int64
+	err := transactor.Transaction(func(repos Repositories) (err error) {
+		if _, err = repos.Hubs.FindByID(hub.ID); err != nil {
+			return err
+		}
+		_, teams, err = repos.Teams.List(ListOptions{Where: "hub_id = ?", Args: []interface{}{hub.ID}})
+		return err
+	})
+
+	assert.NoError(t, <-created)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), teams)
+}
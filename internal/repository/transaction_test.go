@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"errors"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"hub_management_service/internal/entity"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type TransactorTestSuite struct {
+	suite.Suite
+	DB         *gorm.DB
+	Transactor Transactor
+}
+
+func (suite *TransactorTestSuite) SetupTest() {
+	// Create an in-memory SQLite database
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		suite.T().Fatal("failed to connect to database")
+	}
+	// Transactions must see the same in-memory database
+	sqlDB, _ := db.DB()
+	sqlDB.SetMaxOpenConns(1)
+	suite.DB = db
+
+	suite.DB.AutoMigrate(&entity.Hub{}, &entity.Team{})
+	suite.Transactor = NewTransactor(suite.DB)
+}
+
+func (suite *TransactorTestSuite) TearDownTest() {
+	// Clean up the database
+	suite.DB.Exec("DELETE FROM teams")
+	suite.DB.Exec("DELETE FROM hubs")
+}
+
+func (suite *TransactorTestSuite) countHubs() int64 {
+	var count int64
+	suite.DB.Model(&entity.Hub{}).Count(&count)
+	return count
+}
+
+func (suite *TransactorTestSuite) TestTransactionCommits() {
+	err := suite.Transactor.Transaction(func(repos Repositories) error {
+		hub := &entity.Hub{Name: "Paris Hub", Location: "Paris"}
+		if err := repos.Hubs.Create(hub); err != nil {
+			return err
+		}
+		return repos.Teams.Create(&entity.Team{Name: "Backend", HubID: hub.ID})
+	})
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), int64(1), suite.countHubs())
+}
+
+func (suite *TransactorTestSuite) TestTransactionRollsBack() {
+	failure := errors.New("failure")
+	err := suite.Transactor.Transaction(func(repos Repositories) error {
+		repos.Hubs.Create(&entity.Hub{Name: "Paris Hub", Location: "Paris"})
+		return failure
+	})
+	assert.ErrorIs(suite.T(), err, failure)
+	assert.Equal(suite.T(), int64(0), suite.countHubs())
+}
+
+func (suite *TransactorTestSuite) TestSavepointUndoesFailedWork() {
+	err := suite.Transactor.Transaction(func(repos Repositories) error {
+		err := repos.Savepoint("first", func() error {
+			return repos.Hubs.Create(&entity.Hub{Name: "Paris Hub", Location: "Paris"})
+		})
+		if err != nil {
+			return err
+		}
+		err = repos.Savepoint("second", func() error {
+			repos.Hubs.Create(&entity.Hub{Name: "Berlin Hub", Location: "Berlin"})
+			return errors.New("row failed")
+		})
+		assert.EqualError(suite.T(), err, "row failed")
+		return nil
+	})
+	assert.NoError(suite.T(), err)
+
+	var hubs []entity.Hub
+	suite.DB.Find(&hubs)
+	if assert.Len(suite.T(), hubs, 1) {
+		assert.Equal(suite.T(), "Paris Hub", hubs[0].Name)
+	}
+}
+
+func (suite *TransactorTestSuite) TestNestedTransactionIsASavepoint() {
+	err := suite.Transactor.Transaction(func(repos Repositories) error {
+		if err := repos.Hubs.Create(&entity.Hub{Name: "Paris Hub", Location: "Paris"}); err != nil {
+			return err
+		}
+		err := repos.Transaction(func(nested Repositories) error {
+			nested.Hubs.Create(&entity.Hub{Name: "Berlin Hub", Location: "Berlin"})
+			return errors.New("nested failed")
+		})
+		assert.EqualError(suite.T(), err, "nested failed")
+		return nil
+	})
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), int64(1), suite.countHubs())
+}
+
+func (suite *TransactorTestSuite) TestRepositoriesWithoutDatabase() {
+	hubs := NewHubRepository(suite.DB)
+	err := Repositories{Hubs: hubs}.Transaction(func(repos Repositories) error {
+		assert.Same(suite.T(), hubs, repos.Hubs)
+		repos.Hubs.Create(&entity.Hub{Name: "Paris Hub", Location: "Paris"})
+		return errors.New("failure")
+	})
+	assert.EqualError(suite.T(), err, "failure")
+	assert.Equal(suite.T(), int64(1), suite.countHubs()) // Nothing to roll back
+}
+
+func TestTransactorTestSuite(t *testing.T) {
+	suite.Run(t, new(TransactorTestSuite))
+}
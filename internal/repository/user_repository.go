@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"errors"
 	"gorm.io/gorm"
 	"hub_management_service/internal/entity"
 )
@@ -8,9 +9,23 @@ import (
 type UserRepository interface {
 	Create(user *entity.User) error
 	FindUserByTeamID(teamID uint) ([]entity.User, error)
+	FindMembersByTeamID(teamID uint) ([]entity.User, error)
 	FindByID(id uint) (*entity.User, error)
+	FindByEmail(email string) (*entity.User, error)
+	FindByEmployeeNumber(employeeNumber string) (*entity.User, error)
+	FindByOIDCSubject(subject string) (*entity.User, error)
+	LinkOIDCSubject(id uint, subject string) error
+	Update(user *entity.User) error
+	Delete(id, version uint) error
+	List(opts ListOptions) ([]entity.User, int64, error)
+	FindDirectReports(managerID uint) ([]entity.User, error)
+	FindSkipLevelReports(managerID uint) ([]entity.User, error)
+	FindReportingChain(userID uint) ([]entity.User, error)
 }
 
+// MaxReportingDepth bounds reporting chain lookups, which also guards against cycles in existing data
+const MaxReportingDepth = 100
+
 type userRepository struct {
 	db *gorm.DB
 }
@@ -19,23 +34,168 @@ func NewUserRepository(db *gorm.DB) UserRepository {
 	return &userRepository{db: db}
 }
 
+// Create - Method to create a user along with the primary membership of their team
 func (r *userRepository) Create(user *entity.User) error {
-	return r.db.Create(user).Error
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(user).Error; err != nil {
+			return err
+		}
+		return syncPrimaryMembership(tx, user)
+	})
 }
 
 // Removed FindAll method, as per the request
 
-// FindUserByTeamID - Method to find users by TeamID
+// FindUserByTeamID - Method to find users by TeamID, i.e. the users whose primary team it is
 func (r *userRepository) FindUserByTeamID(teamID uint) ([]entity.User, error) {
 	var users []entity.User
 	err := r.db.Where("team_id = ?", teamID).Find(&users).Error
 	return users, err
 }
 
-// FindByID - Method to find a user by their ID
+// FindMembersByTeamID - Method to find the users with a current membership in a team, primary or not
+func (r *userRepository) FindMembersByTeamID(teamID uint) ([]entity.User, error) {
+	today := entity.Today()
+	members := r.db.Model(&entity.Membership{}).Select("user_id").
+		Where("team_id = ? AND (start_date IS NULL OR start_date <= ?) AND (end_date IS NULL OR end_date >= ?)", teamID, today, today)
+
+	var users []entity.User
+	err := r.db.Where("team_id = ? OR id IN (?)", teamID, members).Order("id").Find(&users).Error
+	return users, err
+}
+
+// FindByID - Method to find a user by their ID, locking it until the end of the transaction when called within one
 func (r *userRepository) FindByID(id uint) (*entity.User, error) {
 	var user entity.User
-	err := r.db.First(&user, id).Error
+	err := forUpdate(r.db).First(&user, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// FindByEmail - Method to find a user by email, returns nil if no user matches
+func (r *userRepository) FindByEmail(email string) (*entity.User, error) {
+	return r.findOne("LOWER(email) = LOWER(?)", email)
+}
+
+// FindByEmployeeNumber - Method to find a user by employee number, returns nil if no user matches
+func (r *userRepository) FindByEmployeeNumber(employeeNumber string) (*entity.User, error) {
+	return r.findOne("employee_number = ?", employeeNumber)
+}
+
+// FindByOIDCSubject - Method to find the user linked to an identity provider subject, returns nil if none is linked
+func (r *userRepository) FindByOIDCSubject(subject string) (*entity.User, error) {
+	return r.findOne("oidc_subject = ?", subject)
+}
+
+// LinkOIDCSubject - Method to link a user to an identity provider subject
+func (r *userRepository) LinkOIDCSubject(id uint, subject string) error {
+	return r.db.Model(&entity.User{}).Where("id = ?", id).Update("oidc_subject", subject).Error
+}
+
+// Update - Method to save all fields of an existing user, keeping the primary membership in line with TeamID.
+// Returns ErrStaleVersion if the user was changed since it was read.
+func (r *userRepository) Update(user *entity.User) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := saveVersioned(tx, user, &user.Version, "Team"); err != nil {
+			return err
+		}
+		return syncPrimaryMembership(tx, user)
+	})
+}
+
+// Delete - Method to delete a user by their ID while they are still at version
+func (r *userRepository) Delete(id, version uint) error {
+	return deleteVersioned(r.db, &entity.User{}, id, version)
+}
+
+// List - Method to find a page of users matching the options, along with the total number of matches
+func (r *userRepository) List(opts ListOptions) ([]entity.User, int64, error) {
+	var total int64
+	if err := (ListOptions{Where: opts.Where, Args: opts.Args}).apply(r.db.Model(&entity.User{})).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var users []entity.User
+	err := opts.apply(r.db).Order("id").Find(&users).Error
+	return users, total, err
+}
+
+// FindDirectReports - Method to find the users reporting to a manager
+func (r *userRepository) FindDirectReports(managerID uint) ([]entity.User, error) {
+	var users []entity.User
+	err := r.db.Where("manager_id = ?", managerID).Order("id").Find(&users).Error
+	return users, err
+}
+
+// FindSkipLevelReports - Method to find the users reporting to the direct reports of a manager
+func (r *userRepository) FindSkipLevelReports(managerID uint) ([]entity.User, error) {
+	reports := r.db.Model(&entity.User{}).Select("id").Where("manager_id = ?", managerID)
+
+	var users []entity.User
+	err := r.db.Where("manager_id IN (?)", reports).Order("id").Find(&users).Error
+	return users, err
+}
+
+// FindReportingChain - Method to find the managers above a user, nearest first
+func (r *userRepository) FindReportingChain(userID uint) ([]entity.User, error) {
+	if r.db.Dialector.Name() == "postgres" {
+		return r.findReportingChainRecursive(userID)
+	}
+	return r.findReportingChainIterative(userID)
+}
+
+// findReportingChainRecursive walks the chain in a single recursive query, the path stops the walk at cycles
+func (r *userRepository) findReportingChainRecursive(userID uint) ([]entity.User, error) {
+	var users []entity.User
+	err := r.db.Raw(`
+		WITH RECURSIVE chain (id, manager_id, depth, path) AS (
+			SELECT id, manager_id, 0, ARRAY[id] FROM users WHERE id = ?
+			UNION ALL
+			SELECT u.id, u.manager_id, chain.depth + 1, chain.path || u.id
+			FROM users u JOIN chain ON u.id = chain.manager_id
+			WHERE NOT u.id = ANY(chain.path) AND chain.depth < ?
+		)
+		SELECT users.* FROM users JOIN chain ON users.id = chain.id
+		WHERE chain.depth > 0
+		ORDER BY chain.depth`, userID, MaxReportingDepth).Scan(&users).Error
+	return users, err
+}
+
+// findReportingChainIterative follows manager_id one query at a time, for databases without recursive CTE support
+func (r *userRepository) findReportingChainIterative(userID uint) ([]entity.User, error) {
+	var user entity.User
+	if err := r.db.Select("id", "manager_id").First(&user, userID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var chain []entity.User
+	seen := map[uint]bool{user.ID: true}
+	for managerID := user.ManagerID; managerID != nil && !seen[*managerID] && len(chain) < MaxReportingDepth; {
+		var manager entity.User
+		if err := r.db.First(&manager, *managerID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				break
+			}
+			return nil, err
+		}
+		seen[manager.ID] = true
+		chain = append(chain, manager)
+		managerID = manager.ManagerID
+	}
+	return chain, nil
+}
+
+func (r *userRepository) findOne(query string, args ...interface{}) (*entity.User, error) {
+	var user entity.User
+	err := r.db.Where(query, args...).First(&user).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -5,6 +5,7 @@ import (
 	"gorm.io/gorm"
 	"hub_management_service/internal/entity"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
@@ -24,8 +25,8 @@ func (suite *UserRepositoryTestSuite) SetupTest() {
 	}
 	suite.DB = db
 
-	// Auto-migrate the User entity
-	suite.DB.AutoMigrate(&entity.User{})
+	// Auto-migrate the User entity and the memberships maintained along with it
+	suite.DB.AutoMigrate(&entity.User{}, &entity.Membership{})
 
 	// Initialize the UserRepository
 	suite.UserRepo = NewUserRepository(suite.DB)
@@ -33,6 +34,7 @@ func (suite *UserRepositoryTestSuite) SetupTest() {
 
 func (suite *UserRepositoryTestSuite) TearDownTest() {
 	// Clean up the database
+	suite.DB.Exec("DELETE FROM team_memberships")
 	suite.DB.Exec("DELETE FROM users")
 }
 
@@ -65,6 +67,188 @@ func (suite *UserRepositoryTestSuite) TestFindUserByTeamID() {
 	assert.Len(suite.T(), users, 2)
 }
 
+func (suite *UserRepositoryTestSuite) TestFindByEmail() {
+	suite.UserRepo.Create(&entity.User{Name: "User 1", TeamID: 1, Email: "User1@Example.com"})
+
+	// Lookup is case-insensitive and returns nil when no user matches
+	user, err := suite.UserRepo.FindByEmail("user1@example.com")
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "User 1", user.Name)
+
+	user, err = suite.UserRepo.FindByEmail("nobody@example.com")
+	assert.NoError(suite.T(), err)
+	assert.Nil(suite.T(), user)
+}
+
+func (suite *UserRepositoryTestSuite) TestUpdateAndDeleteUser() {
+	user := &entity.User{Name: "User 1", TeamID: 1}
+	suite.UserRepo.Create(user)
+
+	user.Name = "Renamed"
+	assert.NoError(suite.T(), suite.UserRepo.Update(user))
+	fetchedUser, _ := suite.UserRepo.FindByID(user.ID)
+	assert.Equal(suite.T(), "Renamed", fetchedUser.Name)
+
+	assert.ErrorIs(suite.T(), suite.UserRepo.Delete(user.ID, user.Version-1), ErrStaleVersion)
+	assert.NoError(suite.T(), suite.UserRepo.Delete(user.ID, user.Version))
+	_, err := suite.UserRepo.FindByID(user.ID)
+	assert.True(suite.T(), IsNotFound(err))
+}
+
+func (suite *UserRepositoryTestSuite) TestListUsers() {
+	for _, name := range []string{"Alice", "Bob", "Alicia"} {
+		suite.UserRepo.Create(&entity.User{Name: name, TeamID: 1})
+	}
+
+	// The total counts all matches while the page is limited
+	users, total, err := suite.UserRepo.List(ListOptions{Where: "name LIKE ?", Args: []interface{}{"Ali%"}, Limit: 1})
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), int64(2), total)
+	assert.Len(suite.T(), users, 1)
+	assert.Equal(suite.T(), "Alice", users[0].Name)
+
+	users, _, _ = suite.UserRepo.List(ListOptions{Where: "name LIKE ?", Args: []interface{}{"Ali%"}, Offset: 1, Limit: 1})
+	assert.Equal(suite.T(), "Alicia", users[0].Name)
+}
+
+func (suite *UserRepositoryTestSuite) TestProfileFields() {
+	employeeNumber := "E-100"
+	startDate := entity.NewDate(2024, time.January, 15)
+	suite.UserRepo.Create(&entity.User{Name: "User 1", TeamID: 1, EmployeeNumber: &employeeNumber, StartDate: &startDate, TimeZone: "Europe/Berlin"})
+
+	// Profile fields round-trip and employee numbers can be looked up
+	user, err := suite.UserRepo.FindByEmployeeNumber("E-100")
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "2024-01-15", user.StartDate.String())
+	assert.Equal(suite.T(), "Europe/Berlin", user.TimeZone)
+
+	user, err = suite.UserRepo.FindByEmployeeNumber("E-200")
+	assert.NoError(suite.T(), err)
+	assert.Nil(suite.T(), user)
+}
+
+func (suite *UserRepositoryTestSuite) TestPrimaryMembershipFollowsTeam() {
+	user := &entity.User{Name: "User 1", TeamID: 1}
+	suite.UserRepo.Create(user)
+
+	var memberships []entity.Membership
+	suite.DB.Where("user_id = ?", user.ID).Find(&memberships)
+	assert.Len(suite.T(), memberships, 1)
+	assert.True(suite.T(), memberships[0].Primary)
+	assert.Equal(suite.T(), uint(1), memberships[0].TeamID)
+
+	// Moving the user makes the new team primary and keeps the old one as a secondary membership ending today
+	user.TeamID = 2
+	assert.NoError(suite.T(), suite.UserRepo.Update(user))
+	suite.DB.Where("user_id = ?", user.ID).Order("team_id").Find(&memberships)
+	assert.Len(suite.T(), memberships, 2)
+	assert.False(suite.T(), memberships[0].Primary)
+	assert.True(suite.T(), memberships[1].Primary)
+	assert.Nil(suite.T(), memberships[1].EndDate)
+	today := entity.Today()
+	if assert.NotNil(suite.T(), memberships[0].EndDate) {
+		assert.Equal(suite.T(), today.String(), memberships[0].EndDate.String())
+	}
+}
+
+func (suite *UserRepositoryTestSuite) TestTransferKeepsEarlierEndDate() {
+	user := &entity.User{Name: "User 1", TeamID: 1}
+	suite.UserRepo.Create(user)
+	ended := entity.NewDate(2020, time.January, 31)
+	suite.DB.Model(&entity.Membership{}).Where("user_id = ? AND team_id = ?", user.ID, 1).Update("end_date", ended)
+
+	user.TeamID = 2
+	assert.NoError(suite.T(), suite.UserRepo.Update(user))
+
+	var membership entity.Membership
+	suite.DB.Where("user_id = ? AND team_id = ?", user.ID, 1).First(&membership)
+	if assert.NotNil(suite.T(), membership.EndDate) {
+		assert.Equal(suite.T(), ended.String(), membership.EndDate.String())
+	}
+}
+
+func (suite *UserRepositoryTestSuite) TestRejoinCreatesMembership() {
+	user := &entity.User{Name: "User 1", TeamID: 1}
+	suite.UserRepo.Create(user)
+	ended := entity.NewDate(2020, time.January, 31)
+	suite.DB.Model(&entity.Membership{}).Where("user_id = ? AND team_id = ?", user.ID, 1).Update("end_date", ended)
+	user.TeamID = 2
+	assert.NoError(suite.T(), suite.UserRepo.Update(user))
+
+	// Moving back opens a new membership and keeps the ended one as history
+	user.TeamID = 1
+	assert.NoError(suite.T(), suite.UserRepo.Update(user))
+	var memberships []entity.Membership
+	suite.DB.Where("user_id = ? AND team_id = ?", user.ID, 1).Order("id").Find(&memberships)
+	if assert.Len(suite.T(), memberships, 2) {
+		assert.False(suite.T(), memberships[0].Primary)
+		assert.Equal(suite.T(), ended.String(), memberships[0].EndDate.String())
+		assert.True(suite.T(), memberships[1].Primary)
+		assert.Nil(suite.T(), memberships[1].EndDate)
+	}
+}
+
+func (suite *UserRepositoryTestSuite) TestFindMembersByTeamID() {
+	lead := &entity.User{Name: "Lead", TeamID: 1}
+	guildMember := &entity.User{Name: "Guild member", TeamID: 2}
+	former := &entity.User{Name: "Former", TeamID: 2}
+	suite.UserRepo.Create(lead)
+	suite.UserRepo.Create(guildMember)
+	suite.UserRepo.Create(former)
+
+	ended := entity.NewDate(2020, time.January, 1)
+	suite.DB.Create(&entity.Membership{UserID: guildMember.ID, TeamID: 1, Role: entity.RoleMember})
+	suite.DB.Create(&entity.Membership{UserID: former.ID, TeamID: 1, Role: entity.RoleMember, EndDate: &ended})
+
+	// Primary and current secondary members are returned, ended memberships are not
+	users, err := suite.UserRepo.FindMembersByTeamID(1)
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), users, 2)
+	assert.Equal(suite.T(), "Lead", users[0].Name)
+	assert.Equal(suite.T(), "Guild member", users[1].Name)
+}
+
+func (suite *UserRepositoryTestSuite) TestReportingLines() {
+	ceo := &entity.User{Name: "CEO", TeamID: 1}
+	suite.UserRepo.Create(ceo)
+	vp := &entity.User{Name: "VP", TeamID: 1, ManagerID: &ceo.ID}
+	suite.UserRepo.Create(vp)
+	lead := &entity.User{Name: "Lead", TeamID: 1, ManagerID: &vp.ID}
+	suite.UserRepo.Create(lead)
+	engineer := &entity.User{Name: "Engineer", TeamID: 1, ManagerID: &lead.ID}
+	suite.UserRepo.Create(engineer)
+
+	reports, err := suite.UserRepo.FindDirectReports(vp.ID)
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), reports, 1)
+	assert.Equal(suite.T(), "Lead", reports[0].Name)
+
+	skipLevel, err := suite.UserRepo.FindSkipLevelReports(vp.ID)
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), skipLevel, 1)
+	assert.Equal(suite.T(), "Engineer", skipLevel[0].Name)
+
+	chain, err := suite.UserRepo.FindReportingChain(engineer.ID)
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), chain, 3)
+	assert.Equal(suite.T(), "Lead", chain[0].Name)
+	assert.Equal(suite.T(), "CEO", chain[2].Name)
+}
+
+func (suite *UserRepositoryTestSuite) TestReportingChainStopsAtCycle() {
+	a := &entity.User{Name: "A", TeamID: 1}
+	b := &entity.User{Name: "B", TeamID: 1}
+	suite.UserRepo.Create(a)
+	suite.UserRepo.Create(b)
+	suite.DB.Model(a).Update("manager_id", b.ID)
+	suite.DB.Model(b).Update("manager_id", a.ID)
+
+	chain, err := suite.UserRepo.FindReportingChain(a.ID)
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), chain, 1)
+	assert.Equal(suite.T(), "B", chain[0].Name)
+}
+
 func TestUserRepositoryTestSuite(t *testing.T) {
 	suite.Run(t, new(UserRepositoryTestSuite))
 }
@@ -5,35 +5,147 @@ import (
 	"github.com/gin-gonic/gin"
 	"hub_management_service/internal/handler"
 	"hub_management_service/internal/middleware"
+	"hub_management_service/internal/service"
 )
 
 // NewRouter initializes and returns the Gin router with all routes and middleware applied
-func NewRouter(hubHandler *handler.HubHandler, teamHandler *handler.TeamHandler, userHandler *handler.UserHandler) *gin.Engine {
+func NewRouter(authHandler *handler.AuthHandler, oidcHandler *handler.OIDCHandler, hubHandler *handler.HubHandler, teamHandler *handler.TeamHandler, userHandler *handler.UserHandler, membershipHandler *handler.MembershipHandler, regionHandler *handler.RegionHandler, scheduleHandler *handler.ScheduleHandler, resourceHandler *handler.ResourceHandler, bookingHandler *handler.BookingHandler, attendanceHandler *handler.AttendanceHandler, importHandler *handler.ImportHandler, batchHandler *handler.BatchHandler, exportHandler *handler.ExportHandler, orgChartHandler *handler.OrgChartHandler, searchHandler *handler.SearchHandler, scimHandler *handler.SCIMHandler, idempotencyService service.IdempotencyService) *gin.Engine {
 	r := gin.Default()
 	// Custom CORS configuration using gin-contrib/cors
 	corsConfig := cors.DefaultConfig()
-	corsConfig.AllowOrigins = []string{"http://localhost:8081"}                   // Allow swagger UI
-	corsConfig.AllowMethods = []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"} // Allow necessary methods
-	corsConfig.AllowHeaders = []string{"Content-Type", "Authorization"}           // Allow the Authorization header
-	corsConfig.ExposeHeaders = []string{"Authorization"}                          // Expose the Authorization header if needed
+	corsConfig.AllowOrigins = []string{"http://localhost:8081"}                                                                       // Allow swagger UI
+	corsConfig.AllowMethods = []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"}                                            // Allow necessary methods
+	corsConfig.AllowHeaders = []string{"Content-Type", "Authorization", "If-Match", "If-None-Match", middleware.IdempotencyKeyHeader} // Allow the Authorization, precondition and idempotency headers
+	corsConfig.ExposeHeaders = []string{"Authorization", "ETag", "Location", "Idempotent-Replayed"}                                   // Expose the Authorization header if needed
 
 	// Apply CORS middleware to the Gin router
 	r.Use(cors.New(corsConfig))
 	// Login route (no auth required)
-	r.POST("/login", handler.LoginHandler)
+	r.POST("/login", authHandler.Login)
+	r.POST("/login/mfa", authHandler.VerifyMFA) // Exchange an MFA challenge token for a JWT
+
+	// OpenID Connect login, only available when an identity provider is configured
+	if oidcHandler != nil {
+		r.GET("/auth/oidc/login", oidcHandler.Login)
+		r.GET("/auth/oidc/callback", oidcHandler.Callback)
+	}
+
+	// MFA enrollment for the authenticated account
+	r.POST("/mfa/enroll", middleware.AuthMiddleware(), authHandler.EnrollMFA)
+	r.POST("/mfa/confirm", middleware.AuthMiddleware(), authHandler.ConfirmMFA)
+	r.POST("/mfa/disable", middleware.AuthMiddleware(), authHandler.DisableMFA)
+
+	// Create endpoints replay their response to retries sent with the same Idempotency-Key header
+	idempotent := middleware.Idempotency(idempotencyService)
 
 	// Protected routes with authentication middleware
-	r.POST("/hubs", middleware.AuthMiddleware(), hubHandler.CreateHub)
-	r.GET("/hubs/:id", hubHandler.FindHubByID)         // Get hub by ID
-	r.GET("/hubs/search", hubHandler.SearchHubsByName) // Search hubs by name
+	r.POST("/hubs", middleware.AuthMiddleware(), idempotent, hubHandler.CreateHub)
+	r.GET("/hubs", hubHandler.ListHubs) // List hubs by region, country or city
+	r.PUT("/hubs/:id", middleware.AuthMiddleware(), hubHandler.UpdateHub)
+	r.PATCH("/hubs/:id", middleware.AuthMiddleware(), hubHandler.PatchHub) // JSON Merge Patch or JSON Patch
+
+	// Opening hours and holidays, in the time zone of the hub
+	r.GET("/hubs/:id/open", scheduleHandler.IsOpen) // Tell whether a hub is open at ?at=
+	r.GET("/hubs/:id/hours", scheduleHandler.FindOpeningHours)
+	r.PUT("/hubs/:id/hours", middleware.AuthMiddleware(), scheduleHandler.SetOpeningHours)
+	r.GET("/hubs/:id/holidays", scheduleHandler.ListHolidays)
+	r.GET("/hubs/:id/holidays.ics", scheduleHandler.ExportHolidays) // Download holidays as iCalendar
+	r.POST("/hubs/:id/holidays", middleware.AuthMiddleware(), idempotent, scheduleHandler.AddHoliday)
+	r.DELETE("/hubs/:id/holidays/:holiday_id", middleware.AuthMiddleware(), scheduleHandler.RemoveHoliday)
+	r.GET("/hubs/:id/resources", resourceHandler.ListResources) // List desks and rooms by type, floor, amenity or capacity
+	r.POST("/hubs/:id/resources", middleware.AuthMiddleware(), idempotent, resourceHandler.CreateResource)
+	r.GET("/hubs/:id/availability", bookingHandler.FindAvailability) // Bookings and free ranges of the resources on ?date=
+	r.GET("/hubs/:id", hubHandler.FindHubByID)                       // Get hub by ID
+	r.GET("/hubs/search", hubHandler.SearchHubsByName)               // Search hubs by name
+	r.GET("/hubs/nearby", hubHandler.FindNearby)                     // Find hubs near a point, nearest first
+	r.GET("/hubs/utilisation", hubHandler.UtilisationReport)         // Headcount against capacity for every hub
+
+	// Check-in, presence and daily attendance
+	r.POST("/hubs/:id/check-in", middleware.EmployeeAuthMiddleware(), idempotent, attendanceHandler.CheckIn)
+	r.POST("/hubs/:id/check-out", middleware.EmployeeAuthMiddleware(), attendanceHandler.CheckOut)
+	r.GET("/hubs/:id/presence", middleware.EmployeeAuthMiddleware(), attendanceHandler.FindPresence) // Who checked in on ?date=, today by default
+	r.GET("/hubs/:id/attendance", attendanceHandler.FindHubAttendance)                               // Distinct users per day and team between ?from= and ?to=
+	r.GET("/teams/:id/attendance", attendanceHandler.FindTeamAttendance)                             // Distinct users per day and hub between ?from= and ?to=
+
+	// Org charts as ?format=svg, dot or mermaid
+	r.GET("/hubs/:id/org-chart", orgChartHandler.HubChart)
+	r.GET("/teams/:id/org-chart", orgChartHandler.TeamChart)
+
+	// Desks and meeting rooms within hubs
+	r.GET("/resources/:id", resourceHandler.FindResource)
+	r.PUT("/resources/:id", middleware.AuthMiddleware(), resourceHandler.UpdateResource)
+	r.DELETE("/resources/:id", middleware.AuthMiddleware(), resourceHandler.DeleteResource)
+	r.GET("/resources/:id/bookings", bookingHandler.ListBookings) // List active bookings between ?from= and ?to=
+	r.POST("/resources/:id/bookings", middleware.EmployeeAuthMiddleware(), idempotent, bookingHandler.CreateBooking)
+	r.GET("/bookings/:id", bookingHandler.FindBooking)
+	r.DELETE("/bookings/:id", middleware.EmployeeAuthMiddleware(), bookingHandler.CancelBooking)
+
+	// Region and country hierarchy above hubs
+	r.GET("/regions", regionHandler.ListRegions)
+	r.GET("/regions/rollup", regionHandler.Rollup) // Count countries, hubs, teams and users per region
+	r.GET("/regions/:id", regionHandler.FindRegion)
+	r.POST("/regions", middleware.AuthMiddleware(), idempotent, regionHandler.CreateRegion)
+	r.PUT("/regions/:id", middleware.AuthMiddleware(), regionHandler.UpdateRegion)
+	r.DELETE("/regions/:id", middleware.AuthMiddleware(), regionHandler.DeleteRegion)
+	r.GET("/countries", regionHandler.ListCountries) // List countries, of one region with ?region_id=
+	r.POST("/countries", middleware.AuthMiddleware(), idempotent, regionHandler.CreateCountry)
+	r.PUT("/countries/:id", middleware.AuthMiddleware(), regionHandler.UpdateCountry)
+	r.DELETE("/countries/:id", middleware.AuthMiddleware(), regionHandler.DeleteCountry)
 
-	r.POST("/teams", middleware.AuthMiddleware(), teamHandler.CreateTeam)
+	r.POST("/teams", middleware.AuthMiddleware(), idempotent, teamHandler.CreateTeam)
 	r.GET("/teams/hub/:hub_id", teamHandler.FindTeamsByHubID) // Find teams by hub ID
 	r.GET("/teams/:id", teamHandler.FindTeamByID)             // Find team by ID
+	r.PUT("/teams/:id/parent", middleware.AuthMiddleware(), teamHandler.SetParent)
+	r.PATCH("/teams/:id", middleware.AuthMiddleware(), teamHandler.PatchTeam)
+	r.GET("/teams/:id/subtree", teamHandler.FindSubtree)     // Find a team with its nested sub-teams
+	r.GET("/teams/:id/ancestors", teamHandler.FindAncestors) // Find the parent teams above a team
+	r.GET("/teams/:id/headcount", teamHandler.FindHeadcount) // Count the people in a team and its sub-teams
 
-	r.POST("/users", middleware.AuthMiddleware(), userHandler.CreateUser)
+	r.GET("/teams/:id/members", membershipHandler.ListMembers) // List the memberships of a team
+	r.POST("/teams/:id/members", middleware.AuthMiddleware(), idempotent, membershipHandler.AddMember)
+	r.DELETE("/teams/:id/members/:user_id", middleware.AuthMiddleware(), membershipHandler.RemoveMember)
+
+	r.POST("/users", middleware.AuthMiddleware(), idempotent, userHandler.CreateUser)
+	r.GET("/users", userHandler.SearchUsers)                    // Search users, or find one with ?email=
 	r.GET("/users/team/:team_id", userHandler.FindUserByTeamID) // Find users by team ID
 	r.GET("/users/:id", userHandler.FindUserByID)               // Get user by ID
+	r.PATCH("/users/:id", middleware.AuthMiddleware(), userHandler.UpdateUser)
+	r.GET("/users/:id/memberships", membershipHandler.ListUserMemberships) // List the teams of a user
+
+	// Reporting lines
+	r.PUT("/users/:id/manager", middleware.AuthMiddleware(), userHandler.SetManager)
+	r.GET("/users/:id/reports", userHandler.FindDirectReports)
+	r.GET("/users/:id/reporting-chain", userHandler.FindReportingChain)
+	r.GET("/users/:id/skip-level", userHandler.FindSkipLevel)
+
+	// Hubs, teams and users matching ?q= with typo tolerance, best first and grouped by type
+	r.GET("/search", searchHandler.Search)
+
+	// Bulk import of hubs, teams or users from CSV or JSON Lines, ?dry_run=true only reports the errors
+	r.POST("/import/:kind", middleware.AuthMiddleware(), idempotent, importHandler.Import)
+
+	// Ordered creates, updates and deletes of hubs, teams and users in one transaction, later operations may refer
+	// to records created earlier with "$ref"
+	r.POST("/batch", middleware.AuthMiddleware(), idempotent, batchHandler.Batch)
+
+	// Users joined with their team and hub as CSV, JSON Lines or XLSX, filtered by ?hub_id= or ?team_id=
+	r.GET("/export", middleware.AuthMiddleware(), exportHandler.ExportOrganisation)
+
+	// SCIM 2.0 provisioning for identity providers
+	scimGroup := r.Group("/scim/v2", middleware.AuthMiddleware())
+	scimGroup.GET("/ServiceProviderConfig", scimHandler.ServiceProviderConfig)
+	scimGroup.GET("/Users", scimHandler.ListUsers)
+	scimGroup.POST("/Users", idempotent, scimHandler.CreateUser)
+	scimGroup.GET("/Users/:id", scimHandler.GetUser)
+	scimGroup.PUT("/Users/:id", scimHandler.ReplaceUser)
+	scimGroup.PATCH("/Users/:id", scimHandler.PatchUser)
+	scimGroup.DELETE("/Users/:id", scimHandler.DeleteUser)
+	scimGroup.GET("/Groups", scimHandler.ListGroups)
+	scimGroup.POST("/Groups", idempotent, scimHandler.CreateGroup)
+	scimGroup.GET("/Groups/:id", scimHandler.GetGroup)
+	scimGroup.PUT("/Groups/:id", scimHandler.ReplaceGroup)
+	scimGroup.PATCH("/Groups/:id", scimHandler.PatchGroup)
+	scimGroup.DELETE("/Groups/:id", scimHandler.DeleteGroup)
 
 	return r
 }
@@ -0,0 +1,285 @@
+package service
+
+import (
+	"errors"
+	"hub_management_service/internal/entity"
+	"hub_management_service/internal/repository"
+	"strings"
+	"time"
+)
+
+var (
+	ErrNotAUser         = errors.New("the authenticated account is not linked to a user")
+	ErrUserDeactivated  = errors.New("the user is deactivated")
+	ErrAlreadyCheckedIn = errors.New("user is already checked in at the hub")
+	ErrNotCheckedIn     = errors.New("user is not checked in at the hub")
+	ErrInvalidDateRange = errors.New("from must not be after to, and the range must span at most 366 days")
+)
+
+// DefaultAttendanceRetentionDays is how long visits are kept when no retention is configured
+const DefaultAttendanceRetentionDays = 90
+
+// Date ranges of FindDailyAttendance
+const (
+	defaultAttendanceRangeDays = 30
+	maxAttendanceRangeDays     = 366
+)
+
+// Presence is the attendance of a user at a hub on a day
+type Presence struct {
+	User         entity.User `json:"user"`
+	FirstCheckIn time.Time   `json:"first_check_in"`
+	LastCheckOut *time.Time  `json:"last_check_out,omitempty"`
+	Present      bool        `json:"present"` // Checked in and not checked out since
+}
+
+// HubPresence lists the users who checked in at a hub on a day
+type HubPresence struct {
+	HubID    uint        `json:"hub_id"`
+	Date     entity.Date `json:"date"`
+	TimeZone string      `json:"time_zone"`
+	Present  int         `json:"present"` // Number of users still in
+	Users    []Presence  `json:"users"`
+}
+
+// AttendanceQuery filters daily attendance, zero IDs are ignored and zero dates select the last 30 days
+type AttendanceQuery struct {
+	HubID  uint
+	TeamID uint
+	From   entity.Date
+	To     entity.Date
+}
+
+type AttendanceService interface {
+	CheckIn(username string, hubID uint) (*entity.Visit, error)
+	CheckOut(username string, hubID uint) (*entity.Visit, error)
+	FindPresence(hubID uint, date entity.Date) (*HubPresence, error)
+	FindDailyAttendance(query AttendanceQuery) ([]entity.DailyAttendance, error)
+	PurgeExpired() (int64, error)
+}
+
+type attendanceService struct {
+	tx            repository.Transactor
+	repo          repository.AttendanceRepository
+	userRepo      repository.UserRepository
+	hubRepo       repository.HubRepository
+	teamRepo      repository.TeamRepository
+	retentionDays int
+	now           func() time.Time
+}
+
+// NewAttendanceService creates the attendance service, visits are purged after retentionDays or the default when it is not positive
+func NewAttendanceService(tx repository.Transactor, repo repository.AttendanceRepository, userRepo repository.UserRepository, hubRepo repository.HubRepository,
+	teamRepo repository.TeamRepository, retentionDays int) AttendanceService {
+	if retentionDays <= 0 {
+		retentionDays = DefaultAttendanceRetentionDays
+	}
+	return &attendanceService{tx: tx, repo: repo, userRepo: userRepo, hubRepo: hubRepo, teamRepo: teamRepo, retentionDays: retentionDays, now: time.Now}
+}
+
+// bind returns the service working with the repositories of a transaction
+func (s *attendanceService) bind(repos repository.Repositories) *attendanceService {
+	return &attendanceService{tx: repos, repo: repos.Attendance, userRepo: repos.Users, hubRepo: repos.Hubs, teamRepo: repos.Teams,
+		retentionDays: s.retentionDays, now: s.now}
+}
+
+// CheckIn records the authenticated user arriving at a hub. A visit still open elsewhere is checked out.
+func (s *attendanceService) CheckIn(username string, hubID uint) (*entity.Visit, error) {
+	var visit *entity.Visit
+	err := inTransaction(s.tx, s, s.bind, func(s *attendanceService) (err error) {
+		visit, err = s.checkIn(username, hubID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return visit, nil
+}
+
+func (s *attendanceService) checkIn(username string, hubID uint) (*entity.Visit, error) {
+	user, err := s.findAccountUser(username)
+	if err != nil {
+		return nil, err
+	}
+	hub, err := s.findHub(hubID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.now().UTC()
+	local := now.In(hubLocation(hub))
+	date := entity.NewDate(local.Year(), local.Month(), local.Day())
+	open, err := s.repo.FindOpenVisit(user.ID, hub.ID)
+	if err != nil {
+		return nil, err
+	}
+	if open != nil && open.Date.String() == date.String() {
+		return nil, ErrAlreadyCheckedIn
+	}
+
+	visit := &entity.Visit{UserID: user.ID, HubID: hub.ID, TeamID: user.TeamID, Date: date, CheckedInAt: now}
+	if err := s.repo.CheckIn(visit); err != nil {
+		return nil, err
+	}
+	return visit, nil
+}
+
+// CheckOut records the authenticated user leaving a hub
+func (s *attendanceService) CheckOut(username string, hubID uint) (*entity.Visit, error) {
+	var visit *entity.Visit
+	err := inTransaction(s.tx, s, s.bind, func(s *attendanceService) (err error) {
+		visit, err = s.checkOut(username, hubID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return visit, nil
+}
+
+func (s *attendanceService) checkOut(username string, hubID uint) (*entity.Visit, error) {
+	user, err := s.findAccountUser(username)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.findHub(hubID); err != nil {
+		return nil, err
+	}
+
+	visit, err := s.repo.FindOpenVisit(user.ID, hubID)
+	if err != nil {
+		return nil, err
+	}
+	if visit == nil {
+		return nil, ErrNotCheckedIn
+	}
+	checkedOutAt := s.now().UTC()
+	visit.CheckedOutAt = &checkedOutAt
+	if err := s.repo.UpdateVisit(visit); err != nil {
+		return nil, err
+	}
+	return visit, nil
+}
+
+// FindPresence lists the users who checked in at a hub on a day, today in its time zone for a zero date, those still in first
+func (s *attendanceService) FindPresence(hubID uint, date entity.Date) (*HubPresence, error) {
+	hub, err := s.findHub(hubID)
+	if err != nil {
+		return nil, err
+	}
+	location := hubLocation(hub)
+	if date.IsZero() {
+		now := s.now().In(location)
+		date = entity.NewDate(now.Year(), now.Month(), now.Day())
+	}
+	visits, err := s.repo.FindVisits(hub.ID, date)
+	if err != nil {
+		return nil, err
+	}
+
+	presence := &HubPresence{HubID: hub.ID, Date: date, TimeZone: location.String(), Users: []Presence{}}
+	index := make(map[uint]int)
+	for _, visit := range visits {
+		i, ok := index[visit.UserID]
+		if !ok {
+			i = len(presence.Users)
+			index[visit.UserID] = i
+			user := entity.User{ID: visit.UserID}
+			if visit.User != nil {
+				user = *visit.User
+			}
+			presence.Users = append(presence.Users, Presence{User: user, FirstCheckIn: visit.CheckedInAt})
+		}
+		// Visits are ordered by check-in, so the last visit tells whether the user is still in
+		p := &presence.Users[i]
+		p.Present = visit.CheckedOutAt == nil
+		if visit.CheckedOutAt != nil {
+			p.LastCheckOut = visit.CheckedOutAt
+		}
+	}
+	for _, p := range presence.Users {
+		if p.Present {
+			presence.Present++
+		}
+	}
+	return presence, nil
+}
+
+// FindDailyAttendance returns the number of distinct users per day, hub and team over a date range
+func (s *attendanceService) FindDailyAttendance(query AttendanceQuery) ([]entity.DailyAttendance, error) {
+	if query.HubID != 0 {
+		if _, err := s.findHub(query.HubID); err != nil {
+			return nil, err
+		}
+	}
+	if query.TeamID != 0 {
+		team, err := s.teamRepo.FindByID(query.TeamID)
+		if repository.IsNotFound(err) || (err == nil && team == nil) {
+			return nil, ErrTeamNotFound
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	to, from := query.To, query.From
+	if to.IsZero() {
+		now := s.now().UTC()
+		to = entity.NewDate(now.Year(), now.Month(), now.Day())
+	}
+	if from.IsZero() {
+		from = entity.Date{Time: to.AddDate(0, 0, 1-defaultAttendanceRangeDays)}
+	}
+	if from.After(to.Time) || to.Sub(from.Time) >= maxAttendanceRangeDays*24*time.Hour {
+		return nil, ErrInvalidDateRange
+	}
+
+	conditions := []string{"date >= ?", "date <= ?"}
+	args := []interface{}{from, to}
+	if query.HubID != 0 {
+		conditions = append(conditions, "hub_id = ?")
+		args = append(args, query.HubID)
+	}
+	if query.TeamID != 0 {
+		conditions = append(conditions, "team_id = ?")
+		args = append(args, query.TeamID)
+	}
+	return s.repo.FindDaily(repository.ListOptions{Where: strings.Join(conditions, " AND "), Args: args})
+}
+
+// PurgeExpired deletes the visits older than the retention period, daily attendance is kept
+func (s *attendanceService) PurgeExpired() (int64, error) {
+	return s.repo.DeleteVisitsBefore(s.now().AddDate(0, 0, -s.retentionDays).UTC())
+}
+
+// findAccountUser finds the user behind an authenticated account, whose username is the user's email, deactivated
+// users cannot act through their account
+func (s *attendanceService) findAccountUser(username string) (*entity.User, error) {
+	return findAccountUser(s.userRepo, username)
+}
+
+func findAccountUser(userRepo repository.UserRepository, username string) (*entity.User, error) {
+	email, err := NormalizeEmail(username)
+	if err != nil {
+		return nil, ErrNotAUser
+	}
+	user, err := userRepo.FindByEmail(email)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, ErrNotAUser
+	}
+	if user.DeactivatedAt != nil {
+		return nil, ErrUserDeactivated
+	}
+	return user, nil
+}
+
+func (s *attendanceService) findHub(id uint) (*entity.Hub, error) {
+	hub, err := s.hubRepo.FindByID(id)
+	if repository.IsNotFound(err) || (err == nil && hub == nil) {
+		return nil, ErrHubNotFound
+	}
+	return hub, err
+}
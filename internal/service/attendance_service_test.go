@@ -0,0 +1,152 @@
+package service
+
+import (
+	"gorm.io/gorm"
+	"hub_management_service/internal/entity"
+	"hub_management_service/internal/repository"
+	"hub_management_service/internal/repository/mocks"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// newTestAttendanceService sets up a user of team 2 and a Tokyo hub, with the clock on 15 July 2030 at 23:00 UTC
+func newTestAttendanceService() (*attendanceService, *mocks.AttendanceRepository, *mocks.TeamRepository) {
+	attendanceRepo := new(mocks.AttendanceRepository)
+	userRepo := new(mocks.UserRepository)
+	hubRepo := new(mocks.HubRepository)
+	teamRepo := new(mocks.TeamRepository)
+
+	userRepo.On("FindByEmail", "jane@example.com").Return(&entity.User{ID: 7, Name: "Jane", Email: "jane@example.com", TeamID: 2}, nil)
+	userRepo.On("FindByEmail", mock.Anything).Return(nil, nil)
+	hubRepo.On("FindByID", uint(1)).Return(&entity.Hub{ID: 1, Name: "Tokyo Hub", TimeZone: "Asia/Tokyo"}, nil)
+	hubRepo.On("FindByID", mock.Anything).Return(nil, gorm.ErrRecordNotFound)
+
+	service := NewAttendanceService(nil, attendanceRepo, userRepo, hubRepo, teamRepo, 0).(*attendanceService)
+	service.now = func() time.Time { return time.Date(2030, time.July, 15, 23, 0, 0, 0, time.UTC) }
+	return service, attendanceRepo, teamRepo
+}
+
+// TestCheckIn tests that visits are dated in the time zone of the hub
+func TestCheckIn(t *testing.T) {
+	service, attendanceRepo, _ := newTestAttendanceService()
+	attendanceRepo.On("FindOpenVisit", uint(7), uint(1)).Return(nil, nil)
+	attendanceRepo.On("CheckIn", mock.AnythingOfType("*entity.Visit")).Return(nil)
+
+	visit, err := service.CheckIn("Jane@Example.com", 1)
+	assert.NoError(t, err)
+	assert.Equal(t, "2030-07-16", visit.Date.String()) // 08:00 the next morning in Tokyo
+	assert.Equal(t, uint(2), visit.TeamID)
+
+	_, err = service.CheckIn("admin", 1)
+	assert.ErrorIs(t, err, ErrNotAUser)
+	_, err = service.CheckIn("jane@example.com", 9)
+	assert.ErrorIs(t, err, ErrHubNotFound)
+}
+
+// TestCheckIn_AlreadyIn tests that a visit open since the same day blocks a new check-in but one from a previous day does not
+func TestCheckIn_AlreadyIn(t *testing.T) {
+	service, attendanceRepo, _ := newTestAttendanceService()
+	attendanceRepo.On("FindOpenVisit", uint(7), uint(1)).Return(&entity.Visit{ID: 3, Date: entity.NewDate(2030, time.July, 16)}, nil).Once()
+	attendanceRepo.On("FindOpenVisit", uint(7), uint(1)).Return(&entity.Visit{ID: 2, Date: entity.NewDate(2030, time.July, 15)}, nil)
+	attendanceRepo.On("CheckIn", mock.AnythingOfType("*entity.Visit")).Return(nil)
+
+	_, err := service.CheckIn("jane@example.com", 1)
+	assert.ErrorIs(t, err, ErrAlreadyCheckedIn)
+	_, err = service.CheckIn("jane@example.com", 1)
+	assert.NoError(t, err)
+	attendanceRepo.AssertNumberOfCalls(t, "CheckIn", 1)
+}
+
+// TestCheckOut tests that the open visit is closed, and that checking out twice fails
+func TestCheckOut(t *testing.T) {
+	service, attendanceRepo, _ := newTestAttendanceService()
+	attendanceRepo.On("FindOpenVisit", uint(7), uint(1)).Return(&entity.Visit{ID: 3}, nil).Once()
+	attendanceRepo.On("FindOpenVisit", uint(7), uint(1)).Return(nil, nil)
+	attendanceRepo.On("UpdateVisit", mock.AnythingOfType("*entity.Visit")).Return(nil)
+
+	visit, err := service.CheckOut("jane@example.com", 1)
+	assert.NoError(t, err)
+	if assert.NotNil(t, visit.CheckedOutAt) {
+		assert.Equal(t, service.now(), *visit.CheckedOutAt)
+	}
+	_, err = service.CheckOut("jane@example.com", 1)
+	assert.ErrorIs(t, err, ErrNotCheckedIn)
+}
+
+// TestCheckIn_DeactivatedUser tests that deactivated users neither check in nor out
+func TestCheckIn_DeactivatedUser(t *testing.T) {
+	attendanceRepo := new(mocks.AttendanceRepository)
+	userRepo := new(mocks.UserRepository)
+	hubRepo := new(mocks.HubRepository)
+	deactivatedAt := time.Date(2030, time.July, 1, 0, 0, 0, 0, time.UTC)
+	userRepo.On("FindByEmail", "john@example.com").Return(&entity.User{ID: 8, Name: "John", TeamID: 2, DeactivatedAt: &deactivatedAt}, nil)
+	hubRepo.On("FindByID", uint(1)).Return(&entity.Hub{ID: 1, Name: "Tokyo Hub", TimeZone: "Asia/Tokyo"}, nil)
+	service := NewAttendanceService(nil, attendanceRepo, userRepo, hubRepo, new(mocks.TeamRepository), 0)
+
+	_, err := service.CheckIn("john@example.com", 1)
+	assert.ErrorIs(t, err, ErrUserDeactivated)
+	_, err = service.CheckOut("john@example.com", 1)
+	assert.ErrorIs(t, err, ErrUserDeactivated)
+	attendanceRepo.AssertNotCalled(t, "CheckIn", mock.Anything)
+	attendanceRepo.AssertNotCalled(t, "UpdateVisit", mock.Anything)
+}
+
+// TestFindPresence tests that visits are grouped per user and that only users without a check-out are present
+func TestFindPresence(t *testing.T) {
+	service, attendanceRepo, _ := newTestAttendanceService()
+	at := func(hour int) *time.Time {
+		t := time.Date(2030, time.July, 16, hour, 0, 0, 0, time.UTC)
+		return &t
+	}
+	attendanceRepo.On("FindVisits", uint(1), entity.NewDate(2030, time.July, 16)).Return([]entity.Visit{
+		{UserID: 7, User: &entity.User{ID: 7, Name: "Jane"}, CheckedInAt: *at(0), CheckedOutAt: at(3)},
+		{UserID: 8, User: &entity.User{ID: 8, Name: "John"}, CheckedInAt: *at(1), CheckedOutAt: at(2)},
+		{UserID: 7, User: &entity.User{ID: 7, Name: "Jane"}, CheckedInAt: *at(4)},
+	}, nil)
+
+	presence, err := service.FindPresence(1, entity.Date{})
+	assert.NoError(t, err)
+	assert.Equal(t, "Asia/Tokyo", presence.TimeZone)
+	assert.Equal(t, 1, presence.Present)
+	if assert.Len(t, presence.Users, 2) {
+		assert.True(t, presence.Users[0].Present)
+		assert.Equal(t, *at(0), presence.Users[0].FirstCheckIn)
+		assert.Equal(t, at(3), presence.Users[0].LastCheckOut)
+		assert.False(t, presence.Users[1].Present)
+	}
+}
+
+// TestFindDailyAttendance tests the default range, the filters and range validation
+func TestFindDailyAttendance(t *testing.T) {
+	service, attendanceRepo, teamRepo := newTestAttendanceService()
+	teamRepo.On("FindByID", uint(2)).Return(&entity.Team{ID: 2}, nil)
+	teamRepo.On("FindByID", uint(9)).Return(nil, gorm.ErrRecordNotFound)
+	attendanceRepo.On("FindDaily", repository.ListOptions{
+		Where: "date >= ? AND date <= ? AND team_id = ?",
+		Args:  []interface{}{entity.NewDate(2030, time.June, 16), entity.NewDate(2030, time.July, 15), uint(2)},
+	}).Return([]entity.DailyAttendance{{HubID: 1, TeamID: 2, Users: 4}}, nil)
+
+	daily, err := service.FindDailyAttendance(AttendanceQuery{TeamID: 2})
+	assert.NoError(t, err)
+	assert.Len(t, daily, 1)
+
+	_, err = service.FindDailyAttendance(AttendanceQuery{TeamID: 9})
+	assert.ErrorIs(t, err, ErrTeamNotFound)
+	_, err = service.FindDailyAttendance(AttendanceQuery{From: entity.NewDate(2030, time.July, 2), To: entity.NewDate(2030, time.July, 1)})
+	assert.ErrorIs(t, err, ErrInvalidDateRange)
+	_, err = service.FindDailyAttendance(AttendanceQuery{From: entity.NewDate(2029, time.January, 1), To: entity.NewDate(2030, time.January, 2)})
+	assert.ErrorIs(t, err, ErrInvalidDateRange)
+}
+
+// TestPurgeExpired tests that visits are deleted after the default retention
+func TestPurgeExpired(t *testing.T) {
+	service, attendanceRepo, _ := newTestAttendanceService()
+	attendanceRepo.On("DeleteVisitsBefore", time.Date(2030, time.April, 16, 23, 0, 0, 0, time.UTC)).Return(int64(12), nil)
+
+	deleted, err := service.PurgeExpired()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(12), deleted)
+}
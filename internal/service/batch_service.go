@@ -0,0 +1,509 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hub_management_service/internal/entity"
+	"hub_management_service/internal/repository"
+	"regexp"
+	"strings"
+)
+
+var (
+	ErrInvalidBatch          = errors.New("invalid batch")
+	ErrInvalidBatchOperation = errors.New("invalid operation")
+	ErrBatchDependency       = errors.New("referenced operation failed")
+)
+
+// MaxBatchOperations bounds the operations of a single batch
+const MaxBatchOperations = 100
+
+// BatchMode tells whether a batch is kept only when every operation succeeds
+type BatchMode string
+
+const (
+	BatchAtomic     BatchMode = "atomic"      // Stop at the first failure and roll everything back
+	BatchBestEffort BatchMode = "best_effort" // Run every operation and keep the ones that succeed
+)
+
+// BatchAction is what an operation of a batch does
+type BatchAction string
+
+const (
+	BatchCreate BatchAction = "create"
+	BatchUpdate BatchAction = "update"
+	BatchDelete BatchAction = "delete"
+)
+
+// BatchType is the type of record an operation of a batch works on
+type BatchType string
+
+const (
+	BatchHubs  BatchType = "hubs"
+	BatchTeams BatchType = "teams"
+	BatchUsers BatchType = "users"
+)
+
+// BatchOutcome is how an operation of a batch ended
+type BatchOutcome string
+
+const (
+	BatchSucceeded BatchOutcome = "succeeded"
+	BatchFailed    BatchOutcome = "failed"
+	BatchSkipped   BatchOutcome = "skipped" // Not run because an earlier operation of an atomic batch failed
+)
+
+// batchRefPattern restricts the names operations are referred to by, "$name" refers to the ID created by the
+// operation named name
+var batchRefPattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_-]{0,63}$`)
+
+// BatchRequest is an ordered list of operations run in a single transaction
+type BatchRequest struct {
+	Mode       BatchMode        `json:"mode"`
+	Operations []BatchOperation `json:"operations"`
+}
+
+// BatchOperation creates, updates or deletes one hub, team or user. The ID, and body fields ending in _id, take a
+// number or "$ref" for the record created by an earlier operation. Updates and deletes need the version they apply
+// to, or "*" for any version, as If-Match does.
+//
+// Updates have the body of the matching endpoint: a full hub as for PUT /hubs/{id}, the parent_id of a team as for
+// PUT /teams/{id}/parent, and the changed fields of a user as for PATCH /users/{id} along with an optional manager_id.
+type BatchOperation struct {
+	Ref     string          `json:"ref,omitempty"`
+	Op      BatchAction     `json:"op"`
+	Type    BatchType       `json:"type"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Version json.RawMessage `json:"version,omitempty"`
+	Body    json.RawMessage `json:"body,omitempty"`
+}
+
+// expectedVersion is the version an update or delete applies to, AnyVersion for "*"
+func (op BatchOperation) expectedVersion() (uint, error) {
+	var tag string
+	if json.Unmarshal(op.Version, &tag) == nil && tag == "*" {
+		return AnyVersion, nil
+	}
+	var version uint
+	if err := json.Unmarshal(op.Version, &version); err != nil || version == AnyVersion {
+		return 0, errors.New(`version must be a positive integer or "*"`)
+	}
+	return version, nil
+}
+
+// BatchResult is the outcome of an operation, with the record as it was left by a create or update
+type BatchResult struct {
+	Index   int          `json:"index"`
+	Ref     string       `json:"ref,omitempty"`
+	Op      BatchAction  `json:"op"`
+	Type    BatchType    `json:"type"`
+	Outcome BatchOutcome `json:"outcome"`
+	ID      uint         `json:"id,omitempty"`
+	Version uint         `json:"version,omitempty"`
+	Record  interface{}  `json:"record,omitempty"`
+	Error   string       `json:"error,omitempty"`
+	Err     error        `json:"-"`
+}
+
+// BatchReport is the outcome of a batch. Committed is false when an atomic batch was rolled back, the results of
+// its operations then tell what happened before the failure but nothing was kept.
+type BatchReport struct {
+	Mode      BatchMode     `json:"mode"`
+	Committed bool          `json:"committed"`
+	Succeeded int           `json:"succeeded"`
+	Failed    int           `json:"failed"`
+	Results   []BatchResult `json:"results"`
+}
+
+type BatchService interface {
+	Execute(req *BatchRequest, opts WriteOptions) (*BatchReport, error)
+}
+
+// errBatchRollback aborts the transaction of an atomic batch after a failure
+var errBatchRollback = errors.New("batch rolled back")
+
+type batchService struct {
+	transactor repository.Transactor
+	capacity   *CapacityPolicy // Threshold applied to created and moved users, nil for no limits
+}
+
+func NewBatchService(transactor repository.Transactor, capacity *CapacityPolicy) BatchService {
+	return &batchService{transactor: transactor, capacity: capacity}
+}
+
+// Execute runs the operations of a batch in order in a single transaction, through the same services and checks
+// as the individual endpoints. Atomic batches stop at the first failure and keep nothing, best-effort batches
+// undo each failed operation on its own and keep the rest.
+func (s *batchService) Execute(req *BatchRequest, opts WriteOptions) (*BatchReport, error) {
+	if req.Mode == "" {
+		req.Mode = BatchAtomic
+	}
+	if err := checkBatch(req); err != nil {
+		return nil, err
+	}
+
+	report := &BatchReport{Mode: req.Mode, Results: make([]BatchResult, len(req.Operations))}
+	err := s.transactor.Transaction(func(repos repository.Repositories) error {
+		b := s.newBatcher(repos, opts)
+		for i, op := range req.Operations {
+			result := &report.Results[i]
+			*result = BatchResult{Index: i, Ref: op.Ref, Op: op.Op, Type: op.Type}
+			if req.Mode == BatchAtomic && report.Failed > 0 {
+				result.Outcome = BatchSkipped
+				continue
+			}
+
+			var err error
+			if req.Mode == BatchBestEffort {
+				// A savepoint per operation keeps the transaction usable after an operation fails
+				err = repos.Savepoint(fmt.Sprintf("batch_op_%d", i+1), func() error {
+					return b.run(op, result)
+				})
+				if errors.Is(err, repository.ErrSavepoint) {
+					return err
+				}
+			} else {
+				err = b.run(op, result)
+			}
+			if err != nil {
+				*result = BatchResult{Index: i, Ref: op.Ref, Op: op.Op, Type: op.Type, Outcome: BatchFailed, Error: err.Error(), Err: err}
+				report.Failed++
+				continue
+			}
+			result.Outcome = BatchSucceeded
+			report.Succeeded++
+			if op.Ref != "" {
+				b.refs[op.Ref] = result.ID
+			}
+		}
+		if req.Mode == BatchAtomic && report.Failed > 0 {
+			return errBatchRollback
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errBatchRollback) {
+		return nil, err
+	}
+	report.Committed = err == nil
+	return report, nil
+}
+
+// checkBatch rejects malformed batches before anything runs, including references to operations that do not come
+// earlier in the batch
+func checkBatch(req *BatchRequest) error {
+	if req.Mode != BatchAtomic && req.Mode != BatchBestEffort {
+		return fmt.Errorf("%w: mode must be atomic or best_effort", ErrInvalidBatch)
+	}
+	if len(req.Operations) == 0 {
+		return fmt.Errorf("%w: no operations", ErrInvalidBatch)
+	}
+	if len(req.Operations) > MaxBatchOperations {
+		return fmt.Errorf("%w: more than %d operations", ErrInvalidBatch, MaxBatchOperations)
+	}
+
+	declared := map[string]bool{}
+	for i, op := range req.Operations {
+		if err := checkBatchOperation(op, declared); err != nil {
+			return fmt.Errorf("%w: operation %d: %v", ErrInvalidBatch, i, err)
+		}
+		if op.Ref != "" {
+			declared[op.Ref] = true
+		}
+	}
+	return nil
+}
+
+func checkBatchOperation(op BatchOperation, declared map[string]bool) error {
+	switch op.Type {
+	case BatchHubs, BatchTeams, BatchUsers:
+	default:
+		return errors.New("type must be hubs, teams or users")
+	}
+	switch op.Op {
+	case BatchCreate:
+		if op.ID != nil {
+			return errors.New("creates take no id")
+		}
+		if op.Version != nil {
+			return errors.New("creates take no version")
+		}
+	case BatchUpdate, BatchDelete:
+		if op.ID == nil {
+			return fmt.Errorf("%ss need an id", op.Op)
+		}
+		if op.Version == nil {
+			return fmt.Errorf(`%ss need a version, or "*" for any version`, op.Op)
+		}
+		if _, err := op.expectedVersion(); err != nil {
+			return err
+		}
+	default:
+		return errors.New("op must be create, update or delete")
+	}
+	if op.Op != BatchDelete && len(op.Body) == 0 {
+		return fmt.Errorf("%ss need a body", op.Op)
+	}
+
+	if op.Ref != "" {
+		if op.Op != BatchCreate {
+			return errors.New("only creates can have a ref")
+		}
+		if !batchRefPattern.MatchString(op.Ref) {
+			return fmt.Errorf("ref %q must start with a letter and contain only letters, digits, - and _", op.Ref)
+		}
+		if declared[op.Ref] {
+			return fmt.Errorf("ref %q is used twice", op.Ref)
+		}
+	}
+
+	refs, err := batchReferences(op)
+	if err != nil {
+		return err
+	}
+	for _, ref := range refs {
+		if !declared[ref] {
+			return fmt.Errorf("$%s does not refer to an earlier create", ref)
+		}
+	}
+	return nil
+}
+
+// batchReferences lists the refs an operation uses in its ID and in the _id fields of its body
+func batchReferences(op BatchOperation) ([]string, error) {
+	var refs []string
+	if op.ID != nil {
+		if ref, ok := batchRef(op.ID); ok {
+			refs = append(refs, ref)
+		} else if _, err := parseBatchID(op.ID); err != nil {
+			return nil, err
+		}
+	}
+	if len(op.Body) == 0 {
+		return refs, nil
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(op.Body, &fields); err != nil {
+		return nil, errors.New("body must be a JSON object")
+	}
+	for name, value := range fields {
+		if ref, ok := batchRef(value); ok && strings.HasSuffix(name, "_id") {
+			refs = append(refs, ref)
+		}
+	}
+	return refs, nil
+}
+
+// batchRef returns the name of a "$ref" string
+func batchRef(value json.RawMessage) (string, bool) {
+	var text string
+	if json.Unmarshal(value, &text) != nil || !strings.HasPrefix(text, "$") {
+		return "", false
+	}
+	return text[1:], true
+}
+
+func parseBatchID(value json.RawMessage) (uint, error) {
+	var id uint
+	if err := json.Unmarshal(value, &id); err != nil || id == 0 {
+		return 0, errors.New(`id must be a positive integer or "$ref"`)
+	}
+	return id, nil
+}
+
+// batcher runs operations through the services, bound to the repositories of the batch transaction
+type batcher struct {
+	hubs  HubService
+	teams TeamService
+	users UserService
+	opts  WriteOptions
+	refs  map[string]uint // IDs created by the operations that succeeded so far, by ref
+}
+
+func (s *batchService) newBatcher(repos repository.Repositories, opts WriteOptions) *batcher {
+	capacity := s.capacity.bind(repos)
+	return &batcher{
+		hubs:  NewHubService(repos, repos.Hubs, repos.Countries, capacity),
+		teams: NewTeamService(repos, repos.Teams, repos.Hubs),
+		users: NewUserService(repos, repos.Users, repos.Teams, capacity),
+		opts:  opts,
+		refs:  map[string]uint{},
+	}
+}
+
+// run resolves the references of an operation and runs it, filling in the ID, version and record of its result
+func (b *batcher) run(op BatchOperation, result *BatchResult) error {
+	var id uint
+	if op.ID != nil {
+		var err error
+		if id, err = b.resolveID(op.ID); err != nil {
+			return err
+		}
+	}
+	var version uint
+	if op.Op != BatchCreate {
+		var err error
+		if version, err = op.expectedVersion(); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidBatchOperation, err)
+		}
+	}
+	body, err := b.resolveBody(op.Body)
+	if err != nil {
+		return err
+	}
+	result.ID = id
+
+	switch op.Type {
+	case BatchHubs:
+		return b.runHub(op, id, version, body, result)
+	case BatchTeams:
+		return b.runTeam(op, id, version, body, result)
+	default:
+		return b.runUser(op, id, version, body, result)
+	}
+}
+
+func (b *batcher) runHub(op BatchOperation, id, version uint, body json.RawMessage, result *BatchResult) error {
+	if op.Op == BatchDelete {
+		return b.hubs.DeleteHub(id, version)
+	}
+	hub := &entity.Hub{}
+	if err := decodeBatchBody(body, hub); err != nil {
+		return err
+	}
+	if op.Op == BatchCreate {
+		if err := b.hubs.CreateHub(hub); err != nil {
+			return err
+		}
+	} else {
+		var err error
+		if hub, err = b.hubs.UpdateHub(id, version, hub); err != nil {
+			return err
+		}
+	}
+	result.ID, result.Version, result.Record = hub.ID, hub.Version, hub
+	return nil
+}
+
+func (b *batcher) runTeam(op BatchOperation, id, version uint, body json.RawMessage, result *BatchResult) error {
+	switch op.Op {
+	case BatchDelete:
+		return b.teams.DeleteTeam(id, version)
+	case BatchCreate:
+		team := &entity.Team{}
+		if err := decodeBatchBody(body, team); err != nil {
+			return err
+		}
+		if err := b.teams.CreateTeam(team); err != nil {
+			return err
+		}
+		result.ID, result.Version, result.Record = team.ID, team.Version, team
+	default:
+		var update struct {
+			ParentID *uint `json:"parent_id"`
+		}
+		if err := decodeBatchBody(body, &update); err != nil {
+			return err
+		}
+		team, err := b.teams.SetParent(id, version, update.ParentID)
+		if err != nil {
+			return err
+		}
+		result.Version, result.Record = team.Version, team
+	}
+	return nil
+}
+
+func (b *batcher) runUser(op BatchOperation, id, version uint, body json.RawMessage, result *BatchResult) error {
+	switch op.Op {
+	case BatchDelete:
+		return b.users.DeleteUser(id, version)
+	case BatchCreate:
+		user := &entity.User{}
+		if err := decodeBatchBody(body, user); err != nil {
+			return err
+		}
+		if err := b.users.CreateUser(user, b.opts); err != nil {
+			return err
+		}
+		result.ID, result.Version, result.Record = user.ID, user.Version, user
+	default:
+		var patch UserPatch
+		if err := decodeBatchBody(body, &patch); err != nil {
+			return err
+		}
+		user, err := b.users.UpdateUser(id, version, &patch, b.opts)
+		if err != nil {
+			return err
+		}
+		// The manager is set as for PUT /users/{id}/manager, and only when the body has a manager_id
+		var fields map[string]json.RawMessage
+		if err := json.Unmarshal(body, &fields); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidBatchOperation, err)
+		}
+		if value, ok := fields["manager_id"]; ok {
+			var managerID *uint
+			if err := json.Unmarshal(value, &managerID); err != nil {
+				return fmt.Errorf("%w: manager_id must be a user ID or null", ErrInvalidBatchOperation)
+			}
+			if user, err = b.users.SetManager(id, user.Version, managerID); err != nil {
+				return err
+			}
+		}
+		result.Version, result.Record = user.Version, user
+	}
+	return nil
+}
+
+func (b *batcher) resolveID(value json.RawMessage) (uint, error) {
+	ref, ok := batchRef(value)
+	if !ok {
+		return parseBatchID(value)
+	}
+	id, ok := b.refs[ref]
+	if !ok {
+		return 0, fmt.Errorf("%w: $%s", ErrBatchDependency, ref)
+	}
+	return id, nil
+}
+
+// resolveBody replaces the "$ref" values of the _id fields of a body with the IDs they refer to
+func (b *batcher) resolveBody(body json.RawMessage) (json.RawMessage, error) {
+	if len(body) == 0 {
+		return body, nil
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(body, &fields); err != nil {
+		return nil, err
+	}
+	resolved := false
+	for name, value := range fields {
+		if !strings.HasSuffix(name, "_id") {
+			continue
+		}
+		if _, ok := batchRef(value); !ok {
+			continue
+		}
+		id, err := b.resolveID(value)
+		if err != nil {
+			return nil, err
+		}
+		fields[name] = json.RawMessage(fmt.Sprint(id))
+		resolved = true
+	}
+	if !resolved {
+		return body, nil
+	}
+	return json.Marshal(fields)
+}
+
+// decodeBatchBody decodes the body of an operation and applies the binding rules of the matching endpoint
+func decodeBatchBody(body json.RawMessage, record interface{}) error {
+	if err := json.Unmarshal(body, record); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidBatchOperation, err)
+	}
+	if err := (&importFields{}).validate(record); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidBatchOperation, err)
+	}
+	return nil
+}
@@ -0,0 +1,140 @@
+package service
+
+import (
+	"encoding/json"
+	"hub_management_service/internal/entity"
+	"hub_management_service/internal/repository"
+	"hub_management_service/internal/repository/mocks"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func newTestBatchService() (BatchService, *testTransactor) {
+	transactor := &testTransactor{repos: repository.Repositories{
+		Hubs:      new(mocks.HubRepository),
+		Teams:     new(mocks.TeamRepository),
+		Users:     new(mocks.UserRepository),
+		Countries: new(mocks.CountryRepository),
+	}}
+	return NewBatchService(transactor, nil), transactor
+}
+
+func decodeBatch(t *testing.T, body string) *BatchRequest {
+	var req BatchRequest
+	if err := json.Unmarshal([]byte(body), &req); err != nil {
+		t.Fatal(err)
+	}
+	return &req
+}
+
+// TestBatch_References tests that later operations use the IDs created earlier in the batch
+func TestBatch_References(t *testing.T) {
+	service, transactor := newTestBatchService()
+	hubRepo := transactor.repos.Hubs.(*mocks.HubRepository)
+	teamRepo := transactor.repos.Teams.(*mocks.TeamRepository)
+	hubRepo.On("Create", mock.AnythingOfType("*entity.Hub")).Run(func(args mock.Arguments) {
+		args.Get(0).(*entity.Hub).ID = 10
+	}).Return(nil)
+	hubRepo.On("FindByID", uint(10)).Return(&entity.Hub{ID: 10}, nil)
+	teamRepo.On("Create", mock.MatchedBy(func(team *entity.Team) bool { return team.ParentID == nil })).Run(func(args mock.Arguments) {
+		args.Get(0).(*entity.Team).ID = 20
+	}).Return(nil).Once()
+	teamRepo.On("FindByID", uint(20)).Return(&entity.Team{ID: 20, HubID: 10}, nil)
+	teamRepo.On("Create", mock.MatchedBy(func(team *entity.Team) bool { return *team.ParentID == 20 && team.HubID == 10 })).Run(func(args mock.Arguments) {
+		args.Get(0).(*entity.Team).ID = 21
+	}).Return(nil).Once()
+
+	report, err := service.Execute(decodeBatch(t, `{"operations": [
+		{"ref": "paris", "op": "create", "type": "hubs", "body": {"name": "Paris Hub", "location": "Paris"}},
+		{"ref": "tribe", "op": "create", "type": "teams", "body": {"name": "Tribe", "hub_id": "$paris"}},
+		{"op": "create", "type": "teams", "body": {"name": "Squad", "hub_id": "$paris", "parent_id": "$tribe"}}
+	]}`), WriteOptions{})
+
+	assert.NoError(t, err)
+	assert.True(t, report.Committed)
+	assert.Equal(t, BatchAtomic, report.Mode)
+	assert.Equal(t, 3, report.Succeeded)
+	assert.Equal(t, []uint{10, 20, 21}, []uint{report.Results[0].ID, report.Results[1].ID, report.Results[2].ID})
+	teamRepo.AssertExpectations(t)
+}
+
+// TestBatch_Atomic tests that an atomic batch stops at the first failure and rolls back
+func TestBatch_Atomic(t *testing.T) {
+	service, transactor := newTestBatchService()
+	hubRepo := transactor.repos.Hubs.(*mocks.HubRepository)
+	userRepo := transactor.repos.Users.(*mocks.UserRepository)
+	hubRepo.On("Create", mock.AnythingOfType("*entity.Hub")).Return(nil)
+	userRepo.On("FindByID", uint(7)).Return(nil, gorm.ErrRecordNotFound)
+
+	report, err := service.Execute(decodeBatch(t, `{"mode": "atomic", "operations": [
+		{"op": "create", "type": "hubs", "body": {"name": "Paris Hub", "location": "Paris"}},
+		{"op": "delete", "type": "users", "id": 7, "version": "*"},
+		{"op": "create", "type": "hubs", "body": {"name": "Lyon Hub", "location": "Lyon"}}
+	]}`), WriteOptions{})
+
+	assert.NoError(t, err)
+	assert.False(t, report.Committed)
+	assert.ErrorIs(t, transactor.err, errBatchRollback)
+	assert.Equal(t, []BatchOutcome{BatchSucceeded, BatchFailed, BatchSkipped},
+		[]BatchOutcome{report.Results[0].Outcome, report.Results[1].Outcome, report.Results[2].Outcome})
+	assert.ErrorIs(t, report.Results[1].Err, ErrUserNotFound)
+	hubRepo.AssertNumberOfCalls(t, "Create", 1)
+}
+
+// TestBatch_BestEffort tests that a best-effort batch keeps going after failures and fails operations that refer
+// to a failed one
+func TestBatch_BestEffort(t *testing.T) {
+	service, transactor := newTestBatchService()
+	hubRepo := transactor.repos.Hubs.(*mocks.HubRepository)
+	userRepo := transactor.repos.Users.(*mocks.UserRepository)
+	hubRepo.On("Create", mock.AnythingOfType("*entity.Hub")).Return(nil)
+	userRepo.On("FindByID", uint(7)).Return(&entity.User{ID: 7, Name: "Jane", Email: "jane@example.com", TeamID: 1, Version: 2}, nil)
+	userRepo.On("FindByEmail", "jane@example.com").Return(&entity.User{ID: 7}, nil)
+	userRepo.On("Update", mock.AnythingOfType("*entity.User")).Run(func(args mock.Arguments) {
+		args.Get(0).(*entity.User).Version++
+	}).Return(nil)
+
+	report, err := service.Execute(decodeBatch(t, `{"mode": "best_effort", "operations": [
+		{"ref": "paris", "op": "create", "type": "hubs", "body": {"name": "Paris Hub"}},
+		{"op": "create", "type": "teams", "body": {"name": "Backend", "hub_id": "$paris"}},
+		{"op": "update", "type": "users", "id": 7, "version": 2, "body": {"job_title": "Engineer"}}
+	]}`), WriteOptions{})
+
+	assert.NoError(t, err)
+	assert.True(t, report.Committed)
+	assert.Equal(t, 1, report.Succeeded)
+	assert.Equal(t, 2, report.Failed)
+	assert.ErrorIs(t, report.Results[0].Err, ErrInvalidBatchOperation) // No location
+	assert.ErrorIs(t, report.Results[1].Err, ErrBatchDependency)
+	assert.Equal(t, BatchSucceeded, report.Results[2].Outcome)
+	assert.Equal(t, uint(3), report.Results[2].Version)
+	hubRepo.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+// TestBatch_Invalid tests that malformed batches are rejected before anything runs
+func TestBatch_Invalid(t *testing.T) {
+	service, _ := newTestBatchService()
+	for name, body := range map[string]string{
+		"unknown mode":           `{"mode": "eventually", "operations": [{"op": "delete", "type": "hubs", "id": 1, "version": 1}]}`,
+		"no operations":          `{"operations": []}`,
+		"unknown type":           `{"operations": [{"op": "delete", "type": "regions", "id": 1, "version": 1}]}`,
+		"unknown op":             `{"operations": [{"op": "upsert", "type": "hubs", "id": 1, "version": 1, "body": {}}]}`,
+		"create with id":         `{"operations": [{"op": "create", "type": "hubs", "id": 1, "body": {}}]}`,
+		"create with version":    `{"operations": [{"op": "create", "type": "hubs", "version": 1, "body": {}}]}`,
+		"update without id":      `{"operations": [{"op": "update", "type": "hubs", "version": 1, "body": {}}]}`,
+		"update without version": `{"operations": [{"op": "update", "type": "hubs", "id": 1, "body": {}}]}`,
+		"delete without version": `{"operations": [{"op": "delete", "type": "hubs", "id": 1}]}`,
+		"version zero":           `{"operations": [{"op": "delete", "type": "hubs", "id": 1, "version": 0}]}`,
+		"version not a tag":      `{"operations": [{"op": "delete", "type": "hubs", "id": 1, "version": "any"}]}`,
+		"invalid id":             `{"operations": [{"op": "delete", "type": "hubs", "id": -1, "version": 1}]}`,
+		"forward ref":            `{"operations": [{"op": "delete", "type": "hubs", "id": "$later", "version": 1}, {"ref": "later", "op": "create", "type": "hubs", "body": {}}]}`,
+		"duplicate ref":          `{"operations": [{"ref": "a", "op": "create", "type": "hubs", "body": {}}, {"ref": "a", "op": "create", "type": "hubs", "body": {}}]}`,
+		"body not object":        `{"operations": [{"op": "create", "type": "hubs", "body": [1]}]}`,
+	} {
+		_, err := service.Execute(decodeBatch(t, body), WriteOptions{})
+		assert.ErrorIs(t, err, ErrInvalidBatch, name)
+	}
+}
@@ -0,0 +1,348 @@
+package service
+
+import (
+	"errors"
+	"hub_management_service/internal/entity"
+	"hub_management_service/internal/repository"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrBookingNotFound     = errors.New("booking does not exist")
+	ErrBookingUserNotFound = errors.New("user does not exist")
+	ErrInvalidBookingTime  = errors.New("booking must end after it starts")
+	ErrBookingInPast       = errors.New("booking must not start in the past")
+	ErrOutsideOpeningHours = errors.New("booking must fall within one opening interval of the hub, on a day that is not a holiday")
+	ErrBookingConflict     = errors.New("resource is already booked at that time")
+	ErrBookingCancelled    = errors.New("booking is already cancelled")
+	ErrInvalidTimeRange    = errors.New("to must be after from")
+	ErrBookingForbidden    = errors.New("only administrators can book for or cancel the bookings of other users")
+)
+
+// Account is the authenticated account a booking request acts as
+type Account struct {
+	Username string
+	Admin    bool
+}
+
+// TimeRange is a span of time from StartsAt up to, but not including, EndsAt
+type TimeRange struct {
+	StartsAt time.Time `json:"starts_at"`
+	EndsAt   time.Time `json:"ends_at"`
+}
+
+// ResourceAvailability lists the bookings of a resource over a day and the free ranges between them
+type ResourceAvailability struct {
+	Resource entity.Resource  `json:"resource"`
+	Bookings []entity.Booking `json:"bookings"`
+	Free     []TimeRange      `json:"free"`
+}
+
+// Availability is the state of the resources of a hub over one day in the hub's time zone
+type Availability struct {
+	HubID     uint                   `json:"hub_id"`
+	Date      entity.Date            `json:"date"`
+	TimeZone  string                 `json:"time_zone"`
+	Open      []TimeRange            `json:"open"` // Empty when the hub is closed all day
+	Holiday   *entity.Holiday        `json:"holiday,omitempty"`
+	Resources []ResourceAvailability `json:"resources"`
+}
+
+type BookingService interface {
+	CreateBooking(account Account, resourceID uint, booking *entity.Booking) error
+	FindBooking(id uint) (*entity.Booking, error)
+	ListBookings(resourceID uint, from, to time.Time) ([]entity.Booking, error)
+	CancelBooking(account Account, id uint) (*entity.Booking, error)
+	FindAvailability(hubID uint, date entity.Date, query ResourceQuery) (*Availability, error)
+}
+
+type bookingService struct {
+	tx           repository.Transactor
+	repo         repository.BookingRepository
+	resourceRepo repository.ResourceRepository
+	scheduleRepo repository.ScheduleRepository
+	hubRepo      repository.HubRepository
+	userRepo     repository.UserRepository
+	now          func() time.Time
+}
+
+func NewBookingService(tx repository.Transactor, repo repository.BookingRepository, resourceRepo repository.ResourceRepository, scheduleRepo repository.ScheduleRepository,
+	hubRepo repository.HubRepository, userRepo repository.UserRepository) BookingService {
+	return &bookingService{tx: tx, repo: repo, resourceRepo: resourceRepo, scheduleRepo: scheduleRepo, hubRepo: hubRepo, userRepo: userRepo, now: time.Now}
+}
+
+// bind returns the service working with the repositories of a transaction
+func (s *bookingService) bind(repos repository.Repositories) *bookingService {
+	return &bookingService{tx: repos, repo: repos.Bookings, resourceRepo: repos.Resources, scheduleRepo: repos.Schedules,
+		hubRepo: repos.Hubs, userRepo: repos.Users, now: s.now}
+}
+
+// CreateBooking books a resource for the user of the account, or for the user of the booking when an administrator
+// books. The booking must lie within one opening interval of the hub, in the hub's time zone, and must not overlap
+// another active booking of the resource.
+func (s *bookingService) CreateBooking(account Account, resourceID uint, booking *entity.Booking) error {
+	return inTransaction(s.tx, s, s.bind, func(s *bookingService) error {
+		return s.createBooking(account, resourceID, booking)
+	})
+}
+
+func (s *bookingService) createBooking(account Account, resourceID uint, booking *entity.Booking) error {
+	resource, err := s.findResource(resourceID)
+	if err != nil {
+		return err
+	}
+	user, err := s.findBookingUser(account, booking.UserID)
+	if err != nil {
+		return err
+	}
+
+	booking.ID = 0
+	booking.UserID = user.ID
+	booking.ResourceID = resource.ID
+	booking.StartsAt = booking.StartsAt.UTC()
+	booking.EndsAt = booking.EndsAt.UTC()
+	booking.Title = strings.TrimSpace(booking.Title)
+	booking.CancelledAt = nil
+	if !booking.StartsAt.Before(booking.EndsAt) {
+		return ErrInvalidBookingTime
+	}
+	if booking.StartsAt.Before(s.now()) {
+		return ErrBookingInPast
+	}
+	if err := s.checkOpen(resource.HubID, booking); err != nil {
+		return err
+	}
+
+	if err := s.repo.Create(booking); err != nil {
+		if errors.Is(err, repository.ErrBookingOverlap) {
+			return ErrBookingConflict
+		}
+		return err
+	}
+	return nil
+}
+
+// FindBooking returns a booking, cancelled or not
+func (s *bookingService) FindBooking(id uint) (*entity.Booking, error) {
+	booking, err := s.repo.FindByID(id)
+	if repository.IsNotFound(err) || (err == nil && booking == nil) {
+		return nil, ErrBookingNotFound
+	}
+	return booking, err
+}
+
+// ListBookings returns the active bookings of a resource overlapping a time range
+func (s *bookingService) ListBookings(resourceID uint, from, to time.Time) ([]entity.Booking, error) {
+	if _, err := s.findResource(resourceID); err != nil {
+		return nil, err
+	}
+	if !from.Before(to) {
+		return nil, ErrInvalidTimeRange
+	}
+	return s.repo.FindActive([]uint{resourceID}, from.UTC(), to.UTC())
+}
+
+// CancelBooking cancels a booking of the user of the account, or any booking for an administrator, which frees its
+// time range for other bookings
+func (s *bookingService) CancelBooking(account Account, id uint) (*entity.Booking, error) {
+	var booking *entity.Booking
+	err := inTransaction(s.tx, s, s.bind, func(s *bookingService) (err error) {
+		if booking, err = s.FindBooking(id); err != nil {
+			return err
+		}
+		if !account.Admin {
+			user, err := findAccountUser(s.userRepo, account.Username)
+			if err != nil {
+				return err
+			}
+			if booking.UserID != user.ID {
+				return ErrBookingForbidden
+			}
+		}
+		if booking.CancelledAt != nil {
+			return ErrBookingCancelled
+		}
+
+		cancelledAt := s.now().UTC()
+		booking.CancelledAt = &cancelledAt
+		return s.repo.Update(booking)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return booking, nil
+}
+
+// FindAvailability returns the opening hours of a hub on a day, today in its time zone for a zero date,
+// and for each resource matching the query its bookings and free ranges within the opening hours
+func (s *bookingService) FindAvailability(hubID uint, date entity.Date, query ResourceQuery) (*Availability, error) {
+	hub, err := s.hubRepo.FindByID(hubID)
+	if repository.IsNotFound(err) || (err == nil && hub == nil) {
+		return nil, ErrHubNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	location := hubLocation(hub)
+	if date.IsZero() {
+		now := s.now().In(location)
+		date = entity.NewDate(now.Year(), now.Month(), now.Day())
+	}
+
+	if query.Limit == 0 {
+		query.Limit = MaxResourceListLimit
+	}
+	opts, err := resourceListOptions(hub.ID, query)
+	if err != nil {
+		return nil, err
+	}
+	resources, _, err := s.resourceRepo.List(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	availability := &Availability{HubID: hub.ID, Date: date, TimeZone: location.String(), Open: []TimeRange{}, Resources: []ResourceAvailability{}}
+	availability.Holiday, err = s.scheduleRepo.FindHolidayByDate(hub.ID, date)
+	if err != nil {
+		return nil, err
+	}
+	if availability.Holiday == nil {
+		hours, err := s.scheduleRepo.FindOpeningHours(hub.ID)
+		if err != nil {
+			return nil, err
+		}
+		availability.Open = openingRanges(date, location, hours)
+	}
+
+	bookingsByResource := make(map[uint][]entity.Booking)
+	if len(resources) > 0 {
+		ids := make([]uint, 0, len(resources))
+		for _, resource := range resources {
+			ids = append(ids, resource.ID)
+		}
+		dayStart := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, location)
+		bookings, err := s.repo.FindActive(ids, dayStart.UTC(), dayStart.AddDate(0, 0, 1).UTC())
+		if err != nil {
+			return nil, err
+		}
+		for _, booking := range bookings {
+			bookingsByResource[booking.ResourceID] = append(bookingsByResource[booking.ResourceID], booking)
+		}
+	}
+	for _, resource := range resources {
+		bookings := bookingsByResource[resource.ID]
+		if bookings == nil {
+			bookings = []entity.Booking{}
+		}
+		availability.Resources = append(availability.Resources, ResourceAvailability{
+			Resource: resource,
+			Bookings: bookings,
+			Free:     freeRanges(availability.Open, bookings),
+		})
+	}
+	return availability, nil
+}
+
+// checkOpen returns ErrOutsideOpeningHours unless the booking lies within one opening interval of the hub
+func (s *bookingService) checkOpen(hubID uint, booking *entity.Booking) error {
+	hub, err := s.hubRepo.FindByID(hubID)
+	if err != nil {
+		return err
+	}
+	location := hubLocation(hub)
+	local := booking.StartsAt.In(location)
+	date := entity.NewDate(local.Year(), local.Month(), local.Day())
+
+	holiday, err := s.scheduleRepo.FindHolidayByDate(hub.ID, date)
+	if err != nil {
+		return err
+	}
+	if holiday != nil {
+		return ErrOutsideOpeningHours
+	}
+	hours, err := s.scheduleRepo.FindOpeningHours(hub.ID)
+	if err != nil {
+		return err
+	}
+	for _, open := range openingRanges(date, location, hours) {
+		if !booking.StartsAt.Before(open.StartsAt) && !booking.EndsAt.After(open.EndsAt) {
+			return nil
+		}
+	}
+	return ErrOutsideOpeningHours
+}
+
+// findBookingUser returns the user a booking is for: the user of the account, or any user an administrator names.
+// Deactivated users cannot be booked for.
+func (s *bookingService) findBookingUser(account Account, userID uint) (*entity.User, error) {
+	if account.Admin && userID != 0 {
+		user, err := s.userRepo.FindByID(userID)
+		if repository.IsNotFound(err) || (err == nil && user == nil) {
+			return nil, ErrBookingUserNotFound
+		}
+		if err == nil && user.DeactivatedAt != nil {
+			return nil, ErrUserDeactivated
+		}
+		return user, err
+	}
+
+	user, err := findAccountUser(s.userRepo, account.Username)
+	if err != nil {
+		return nil, err
+	}
+	if userID != 0 && userID != user.ID {
+		return nil, ErrBookingForbidden
+	}
+	return user, nil
+}
+
+func (s *bookingService) findResource(id uint) (*entity.Resource, error) {
+	resource, err := s.resourceRepo.FindByID(id)
+	if repository.IsNotFound(err) || (err == nil && resource == nil) {
+		return nil, ErrResourceNotFound
+	}
+	return resource, err
+}
+
+// openingRanges returns the opening intervals of a hub on a day as times in its time zone, hubs without opening hours are closed
+func openingRanges(date entity.Date, location *time.Location, hours []entity.OpeningHours) []TimeRange {
+	day := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, location)
+	ranges := []TimeRange{}
+	for _, h := range hours {
+		if h.Weekday == day.Weekday() {
+			ranges = append(ranges, TimeRange{StartsAt: clockOn(day, h.Opens), EndsAt: clockOn(day, h.Closes)})
+		}
+	}
+	return ranges
+}
+
+// clockOn returns the time of an HH:MM clock on a day, 24:00 being midnight at the end of the day
+func clockOn(day time.Time, clock string) time.Time {
+	hour, _ := strconv.Atoi(clock[:2])
+	minute, _ := strconv.Atoi(clock[3:])
+	return time.Date(day.Year(), day.Month(), day.Day(), hour, minute, 0, 0, day.Location())
+}
+
+// freeRanges subtracts bookings, ordered by start, from opening ranges
+func freeRanges(open []TimeRange, bookings []entity.Booking) []TimeRange {
+	free := []TimeRange{}
+	for _, r := range open {
+		location := r.StartsAt.Location()
+		cursor := r.StartsAt
+		for _, booking := range bookings {
+			if !booking.StartsAt.Before(r.EndsAt) || !booking.EndsAt.After(cursor) {
+				continue
+			}
+			if booking.StartsAt.After(cursor) {
+				free = append(free, TimeRange{StartsAt: cursor, EndsAt: booking.StartsAt.In(location)})
+			}
+			cursor = booking.EndsAt.In(location)
+		}
+		if cursor.Before(r.EndsAt) {
+			free = append(free, TimeRange{StartsAt: cursor, EndsAt: r.EndsAt})
+		}
+	}
+	return free
+}
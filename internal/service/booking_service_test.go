@@ -0,0 +1,158 @@
+package service
+
+import (
+	"hub_management_service/internal/entity"
+	"hub_management_service/internal/repository"
+	"hub_management_service/internal/repository/mocks"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var (
+	janeAccount  = Account{Username: "jane@example.com"}
+	adminAccount = Account{Username: "admin", Admin: true}
+)
+
+type bookingMocks struct {
+	bookingRepo  *mocks.BookingRepository
+	resourceRepo *mocks.ResourceRepository
+	scheduleRepo *mocks.ScheduleRepository
+}
+
+// newTestBookingService sets up a room in a Paris hub open 09:00-18:00 on Mondays, with the clock on Sunday 14 July 2030
+func newTestBookingService() (*bookingService, bookingMocks) {
+	m := bookingMocks{new(mocks.BookingRepository), new(mocks.ResourceRepository), new(mocks.ScheduleRepository)}
+	hubRepo := new(mocks.HubRepository)
+	userRepo := new(mocks.UserRepository)
+
+	hubRepo.On("FindByID", uint(1)).Return(&entity.Hub{ID: 1, Name: "Paris Hub", TimeZone: "Europe/Paris"}, nil)
+	userRepo.On("FindByID", uint(7)).Return(&entity.User{ID: 7, Name: "Jane"}, nil)
+	userRepo.On("FindByEmail", "jane@example.com").Return(&entity.User{ID: 7, Name: "Jane"}, nil)
+	m.resourceRepo.On("FindByID", uint(2)).Return(&entity.Resource{ID: 2, HubID: 1, Type: entity.ResourceRoom, Name: "Seine"}, nil)
+	m.scheduleRepo.On("FindHolidayByDate", uint(1), mock.Anything).Return(nil, nil)
+	m.scheduleRepo.On("FindOpeningHours", uint(1)).Return([]entity.OpeningHours{
+		{Weekday: time.Monday, Opens: "09:00", Closes: "18:00"},
+	}, nil)
+
+	service := NewBookingService(nil, m.bookingRepo, m.resourceRepo, m.scheduleRepo, hubRepo, userRepo).(*bookingService)
+	service.now = func() time.Time { return time.Date(2030, time.July, 14, 12, 0, 0, 0, time.UTC) }
+	return service, m
+}
+
+// TestCreateBooking tests that opening hours are checked in the time zone of the hub
+func TestCreateBooking(t *testing.T) {
+	service, m := newTestBookingService()
+	m.bookingRepo.On("Create", mock.AnythingOfType("*entity.Booking")).Return(nil)
+
+	// 07:00-09:00 UTC is 09:00-11:00 in Paris
+	paris, _ := time.LoadLocation("Europe/Paris")
+	booking := &entity.Booking{StartsAt: time.Date(2030, time.July, 15, 9, 0, 0, 0, paris), EndsAt: time.Date(2030, time.July, 15, 11, 0, 0, 0, paris)}
+	assert.NoError(t, service.CreateBooking(janeAccount, 2, booking))
+	assert.Equal(t, uint(7), booking.UserID)
+	assert.Equal(t, uint(2), booking.ResourceID)
+	assert.Equal(t, time.UTC, booking.StartsAt.Location())
+
+	// 17:00-19:00 UTC ends after closing time in Paris
+	late := &entity.Booking{UserID: 7, StartsAt: time.Date(2030, time.July, 15, 15, 0, 0, 0, time.UTC), EndsAt: time.Date(2030, time.July, 15, 17, 0, 0, 0, time.UTC)}
+	assert.ErrorIs(t, service.CreateBooking(adminAccount, 2, late), ErrOutsideOpeningHours)
+	m.bookingRepo.AssertNumberOfCalls(t, "Create", 1)
+}
+
+// TestCreateBooking_Invalid tests the validation of the user, the time range and conflicts
+func TestCreateBooking_Invalid(t *testing.T) {
+	service, m := newTestBookingService()
+	m.bookingRepo.On("Create", mock.AnythingOfType("*entity.Booking")).Return(repository.ErrBookingOverlap)
+	service.userRepo.(*mocks.UserRepository).On("FindByID", uint(8)).Return(nil, nil)
+
+	start := time.Date(2030, time.July, 15, 8, 0, 0, 0, time.UTC)
+	assert.ErrorIs(t, service.CreateBooking(adminAccount, 2, &entity.Booking{UserID: 8, StartsAt: start, EndsAt: start.Add(time.Hour)}), ErrBookingUserNotFound)
+	assert.ErrorIs(t, service.CreateBooking(janeAccount, 2, &entity.Booking{StartsAt: start, EndsAt: start}), ErrInvalidBookingTime)
+	assert.ErrorIs(t, service.CreateBooking(janeAccount, 2, &entity.Booking{StartsAt: start.AddDate(0, 0, -7), EndsAt: start.AddDate(0, 0, -7).Add(time.Hour)}), ErrBookingInPast)
+	assert.ErrorIs(t, service.CreateBooking(janeAccount, 2, &entity.Booking{StartsAt: start, EndsAt: start.Add(time.Hour)}), ErrBookingConflict)
+}
+
+// TestCreateBooking_OtherUser tests that only administrators book for another user than their own
+func TestCreateBooking_OtherUser(t *testing.T) {
+	service, m := newTestBookingService()
+	service.userRepo.(*mocks.UserRepository).On("FindByEmail", "admin").Return(nil, nil)
+
+	start := time.Date(2030, time.July, 15, 8, 0, 0, 0, time.UTC)
+	assert.ErrorIs(t, service.CreateBooking(janeAccount, 2, &entity.Booking{UserID: 8, StartsAt: start, EndsAt: start.Add(time.Hour)}), ErrBookingForbidden)
+	assert.ErrorIs(t, service.CreateBooking(Account{Username: "admin"}, 2, &entity.Booking{UserID: 7, StartsAt: start, EndsAt: start.Add(time.Hour)}), ErrNotAUser)
+	m.bookingRepo.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+// TestCreateBooking_DeactivatedUser tests that deactivated users neither book for themselves nor are booked for
+func TestCreateBooking_DeactivatedUser(t *testing.T) {
+	service, m := newTestBookingService()
+	deactivatedAt := time.Date(2030, time.July, 1, 0, 0, 0, 0, time.UTC)
+	userRepo := service.userRepo.(*mocks.UserRepository)
+	userRepo.On("FindByID", uint(8)).Return(&entity.User{ID: 8, Name: "John", DeactivatedAt: &deactivatedAt}, nil)
+	userRepo.On("FindByEmail", "john@example.com").Return(&entity.User{ID: 8, Name: "John", DeactivatedAt: &deactivatedAt}, nil)
+
+	start := time.Date(2030, time.July, 15, 8, 0, 0, 0, time.UTC)
+	assert.ErrorIs(t, service.CreateBooking(Account{Username: "john@example.com"}, 2, &entity.Booking{StartsAt: start, EndsAt: start.Add(time.Hour)}), ErrUserDeactivated)
+	assert.ErrorIs(t, service.CreateBooking(adminAccount, 2, &entity.Booking{UserID: 8, StartsAt: start, EndsAt: start.Add(time.Hour)}), ErrUserDeactivated)
+	m.bookingRepo.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+// TestCancelBooking tests that a booking can only be cancelled once
+func TestCancelBooking(t *testing.T) {
+	service, m := newTestBookingService()
+	cancelledAt := time.Date(2030, time.July, 1, 0, 0, 0, 0, time.UTC)
+	m.bookingRepo.On("FindByID", uint(4)).Return(&entity.Booking{ID: 4, UserID: 7}, nil)
+	m.bookingRepo.On("FindByID", uint(5)).Return(&entity.Booking{ID: 5, UserID: 7, CancelledAt: &cancelledAt}, nil)
+	m.bookingRepo.On("Update", mock.AnythingOfType("*entity.Booking")).Return(nil)
+
+	booking, err := service.CancelBooking(janeAccount, 4)
+	assert.NoError(t, err)
+	assert.Equal(t, service.now().UTC(), *booking.CancelledAt)
+
+	_, err = service.CancelBooking(janeAccount, 5)
+	assert.ErrorIs(t, err, ErrBookingCancelled)
+}
+
+// TestCancelBooking_OtherUser tests that the bookings of other users are only cancelled by administrators
+func TestCancelBooking_OtherUser(t *testing.T) {
+	service, m := newTestBookingService()
+	m.bookingRepo.On("FindByID", uint(6)).Return(&entity.Booking{ID: 6, UserID: 8}, nil)
+	m.bookingRepo.On("Update", mock.AnythingOfType("*entity.Booking")).Return(nil)
+
+	_, err := service.CancelBooking(janeAccount, 6)
+	assert.ErrorIs(t, err, ErrBookingForbidden)
+	m.bookingRepo.AssertNotCalled(t, "Update", mock.Anything)
+
+	_, err = service.CancelBooking(adminAccount, 6)
+	assert.NoError(t, err)
+}
+
+// TestFindAvailability tests that free ranges are the opening hours minus the bookings
+func TestFindAvailability(t *testing.T) {
+	service, m := newTestBookingService()
+	m.resourceRepo.On("List", mock.AnythingOfType("repository.ListOptions")).
+		Return([]entity.Resource{{ID: 2, HubID: 1, Name: "Seine"}, {ID: 3, HubID: 1, Name: "Loire"}}, int64(2), nil)
+	m.bookingRepo.On("FindActive", []uint{2, 3},
+		time.Date(2030, time.July, 14, 22, 0, 0, 0, time.UTC), time.Date(2030, time.July, 15, 22, 0, 0, 0, time.UTC)).
+		Return([]entity.Booking{
+			{ID: 1, ResourceID: 2, StartsAt: time.Date(2030, time.July, 15, 7, 0, 0, 0, time.UTC), EndsAt: time.Date(2030, time.July, 15, 8, 0, 0, 0, time.UTC)},
+			{ID: 2, ResourceID: 2, StartsAt: time.Date(2030, time.July, 15, 12, 0, 0, 0, time.UTC), EndsAt: time.Date(2030, time.July, 15, 13, 0, 0, 0, time.UTC)},
+		}, nil)
+
+	availability, err := service.FindAvailability(1, entity.NewDate(2030, time.July, 15), ResourceQuery{})
+	assert.NoError(t, err)
+	assert.Equal(t, "Europe/Paris", availability.TimeZone)
+	assert.Len(t, availability.Open, 1)
+	if assert.Len(t, availability.Resources, 2) {
+		// Booked 09:00-10:00 and 14:00-15:00 Paris time
+		free := availability.Resources[0].Free
+		if assert.Len(t, free, 2) {
+			assert.Equal(t, "10:00-14:00", free[0].StartsAt.Format("15:04")+"-"+free[0].EndsAt.Format("15:04"))
+			assert.Equal(t, "15:00-18:00", free[1].StartsAt.Format("15:04")+"-"+free[1].EndsAt.Format("15:04"))
+		}
+		assert.Len(t, availability.Resources[1].Free, 1)
+		assert.Empty(t, availability.Resources[1].Bookings)
+	}
+}
@@ -0,0 +1,132 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"hub_management_service/internal/entity"
+	"hub_management_service/internal/repository"
+)
+
+// DefaultCapacityThreshold rejects moves into a hub once its headcount reaches its capacity
+const DefaultCapacityThreshold = 1.0
+
+var ErrHubFull = errors.New("hub is at capacity")
+
+// HubFullError reports the hub that has no room left, it matches ErrHubFull
+type HubFullError struct {
+	HubID     uint
+	Headcount int64
+	Capacity  int
+}
+
+func (e *HubFullError) Error() string {
+	return fmt.Sprintf("hub %d is at capacity with %d of %d seats allocated", e.HubID, e.Headcount, e.Capacity)
+}
+
+func (e *HubFullError) Is(target error) bool {
+	return target == ErrHubFull
+}
+
+// WriteOptions adjusts the checks applied when users are created or moved between teams
+type WriteOptions struct {
+	OverrideCapacity bool // Allow moving users into a hub at or above the capacity threshold
+}
+
+// HubUtilisation is the allocated headcount of a hub against its seat capacity
+type HubUtilisation struct {
+	HubID         uint        `json:"hub_id"`
+	Name          string      `json:"name"`
+	Headcount     int64       `json:"headcount"`   // Users whose primary team is in the hub
+	Capacity      *int        `json:"capacity"`    // Null when the hub has no capacity set
+	Utilisation   *float64    `json:"utilisation"` // Headcount divided by capacity, null without capacity
+	OverThreshold bool        `json:"over_threshold"`
+	Hub           *entity.Hub `json:"hub,omitempty"` // Only loaded with ?expand=hub
+}
+
+// UtilisationReport lists the utilisation of every hub against the configured threshold
+type UtilisationReport struct {
+	Threshold float64          `json:"threshold"`
+	Hubs      []HubUtilisation `json:"hubs"`
+}
+
+// CapacityPolicy decides whether a hub has room for more users. A hub is full once its
+// headcount reaches Threshold times its capacity; hubs without a capacity are never full.
+type CapacityPolicy struct {
+	hubRepo   repository.HubRepository
+	teamRepo  repository.TeamRepository
+	Threshold float64
+}
+
+func NewCapacityPolicy(hubRepo repository.HubRepository, teamRepo repository.TeamRepository, threshold float64) *CapacityPolicy {
+	if threshold <= 0 {
+		threshold = DefaultCapacityThreshold
+	}
+	return &CapacityPolicy{hubRepo: hubRepo, teamRepo: teamRepo, Threshold: threshold}
+}
+
+// bind returns the policy reading through the repositories of a transaction
+func (p *CapacityPolicy) bind(repos repository.Repositories) *CapacityPolicy {
+	if p == nil {
+		return nil
+	}
+	return &CapacityPolicy{hubRepo: repos.Hubs, teamRepo: repos.Teams, Threshold: p.Threshold}
+}
+
+// Utilisation computes the utilisation of a hub with the given headcount
+func (p *CapacityPolicy) Utilisation(hub *entity.Hub, headcount int64) HubUtilisation {
+	utilisation := HubUtilisation{HubID: hub.ID, Name: hub.Name, Headcount: headcount, Capacity: hub.Capacity}
+	if hub.Capacity == nil {
+		return utilisation
+	}
+	if *hub.Capacity > 0 {
+		ratio := float64(headcount) / float64(*hub.Capacity)
+		utilisation.Utilisation = &ratio
+	}
+	utilisation.OverThreshold = float64(headcount) >= p.threshold()*float64(*hub.Capacity)
+	return utilisation
+}
+
+// threshold returns the configured threshold, or the default for a nil policy
+func (p *CapacityPolicy) threshold() float64 {
+	if p == nil {
+		return DefaultCapacityThreshold
+	}
+	return p.Threshold
+}
+
+// CheckRoom returns a HubFullError when the hub of a team cannot take another user, unless overridden.
+// fromTeamID is the current team of a user being moved, zero for new users; moves within a hub are always allowed.
+func (p *CapacityPolicy) CheckRoom(teamID, fromTeamID uint, opts WriteOptions) error {
+	if p == nil || opts.OverrideCapacity {
+		return nil
+	}
+	team, err := p.teamRepo.FindByID(teamID)
+	if repository.IsNotFound(err) || (err == nil && team == nil) {
+		return ErrTeamNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if fromTeamID != 0 {
+		from, err := p.teamRepo.FindByID(fromTeamID)
+		if err == nil && from != nil && from.HubID == team.HubID {
+			return nil
+		}
+	}
+
+	hub, err := p.hubRepo.FindByID(team.HubID)
+	if err != nil {
+		return err
+	}
+	if hub == nil || hub.Capacity == nil {
+		return nil
+	}
+	counts, err := p.hubRepo.CountUsers([]uint{hub.ID})
+	if err != nil {
+		return err
+	}
+	if p.Utilisation(hub, counts[hub.ID]).OverThreshold {
+		return &HubFullError{HubID: hub.ID, Headcount: counts[hub.ID], Capacity: *hub.Capacity}
+	}
+	return nil
+}
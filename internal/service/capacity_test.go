@@ -0,0 +1,101 @@
+package service
+
+import (
+	"hub_management_service/internal/entity"
+	"hub_management_service/internal/repository/mocks"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func intPtr(n int) *int {
+	return &n
+}
+
+// TestCheckRoom tests that a hub at its threshold rejects new users unless overridden
+func TestCheckRoom(t *testing.T) {
+	mockHubRepo := new(mocks.HubRepository)
+	mockTeamRepo := new(mocks.TeamRepository)
+	policy := NewCapacityPolicy(mockHubRepo, mockTeamRepo, 0.8)
+
+	mockTeamRepo.On("FindByID", uint(2)).Return(&entity.Team{ID: 2, HubID: 1}, nil)
+	mockHubRepo.On("FindByID", uint(1)).Return(&entity.Hub{ID: 1, Capacity: intPtr(10)}, nil)
+	mockHubRepo.On("CountUsers", []uint{1}).Return(map[uint]int64{1: 8}, nil)
+
+	err := policy.CheckRoom(2, 0, WriteOptions{})
+	assert.ErrorIs(t, err, ErrHubFull)
+	if fullErr, ok := err.(*HubFullError); assert.True(t, ok) {
+		assert.Equal(t, int64(8), fullErr.Headcount)
+		assert.Equal(t, 10, fullErr.Capacity)
+	}
+	assert.NoError(t, policy.CheckRoom(2, 0, WriteOptions{OverrideCapacity: true}))
+}
+
+// TestCheckRoom_SameHub tests that moves between teams of one hub are always allowed
+func TestCheckRoom_SameHub(t *testing.T) {
+	mockHubRepo := new(mocks.HubRepository)
+	mockTeamRepo := new(mocks.TeamRepository)
+	policy := NewCapacityPolicy(mockHubRepo, mockTeamRepo, 0)
+
+	mockTeamRepo.On("FindByID", uint(2)).Return(&entity.Team{ID: 2, HubID: 1}, nil)
+	mockTeamRepo.On("FindByID", uint(3)).Return(&entity.Team{ID: 3, HubID: 1}, nil)
+
+	assert.NoError(t, policy.CheckRoom(2, 3, WriteOptions{}))
+	mockHubRepo.AssertNotCalled(t, "CountUsers", mock.Anything)
+}
+
+// TestCheckRoom_NoCapacity tests that hubs without a capacity never fill up, and that a nil policy allows everything
+func TestCheckRoom_NoCapacity(t *testing.T) {
+	mockHubRepo := new(mocks.HubRepository)
+	mockTeamRepo := new(mocks.TeamRepository)
+	policy := NewCapacityPolicy(mockHubRepo, mockTeamRepo, 0)
+
+	mockTeamRepo.On("FindByID", uint(2)).Return(&entity.Team{ID: 2, HubID: 1}, nil)
+	mockHubRepo.On("FindByID", uint(1)).Return(&entity.Hub{ID: 1}, nil)
+
+	assert.NoError(t, policy.CheckRoom(2, 0, WriteOptions{}))
+	assert.NoError(t, (*CapacityPolicy)(nil).CheckRoom(2, 0, WriteOptions{}))
+}
+
+// TestUtilisationReport tests that the report is ordered by utilisation with hubs without capacity last
+func TestUtilisationReport(t *testing.T) {
+	mockHubRepo := new(mocks.HubRepository)
+	service := NewHubService(nil, mockHubRepo, new(mocks.CountryRepository), NewCapacityPolicy(mockHubRepo, new(mocks.TeamRepository), 0.9))
+
+	mockHubRepo.On("FindAll").Return([]entity.Hub{
+		{ID: 1, Name: "Unlimited"},
+		{ID: 2, Name: "Half", Capacity: intPtr(10)},
+		{ID: 3, Name: "Busy", Capacity: intPtr(10)},
+	}, nil)
+	mockHubRepo.On("CountUsers", []uint{1, 2, 3}).Return(map[uint]int64{1: 50, 2: 5, 3: 9}, nil)
+
+	report, err := service.UtilisationReport()
+	assert.NoError(t, err)
+	assert.Equal(t, 0.9, report.Threshold)
+	if assert.Len(t, report.Hubs, 3) {
+		assert.Equal(t, []uint{3, 2, 1}, []uint{report.Hubs[0].HubID, report.Hubs[1].HubID, report.Hubs[2].HubID})
+		assert.True(t, report.Hubs[0].OverThreshold)
+		assert.False(t, report.Hubs[1].OverThreshold)
+		assert.Nil(t, report.Hubs[2].Utilisation)
+	}
+}
+
+// TestUpdateUser_HubFull tests that moving a user into a full hub is rejected
+func TestUpdateUser_HubFull(t *testing.T) {
+	mockUserRepo := new(mocks.UserRepository)
+	mockTeamRepo := new(mocks.TeamRepository)
+	mockHubRepo := new(mocks.HubRepository)
+	service := NewUserService(nil, mockUserRepo, mockTeamRepo, NewCapacityPolicy(mockHubRepo, mockTeamRepo, 0))
+
+	mockUserRepo.On("FindByID", uint(7)).Return(&entity.User{ID: 7, Name: "Jane", Email: "jane@example.com", TeamID: 2}, nil)
+	mockTeamRepo.On("FindByID", uint(2)).Return(&entity.Team{ID: 2, HubID: 1}, nil)
+	mockTeamRepo.On("FindByID", uint(5)).Return(&entity.Team{ID: 5, HubID: 4}, nil)
+	mockHubRepo.On("FindByID", uint(4)).Return(&entity.Hub{ID: 4, Capacity: intPtr(3)}, nil)
+	mockHubRepo.On("CountUsers", []uint{4}).Return(map[uint]int64{4: 3}, nil)
+
+	teamID := uint(5)
+	_, err := service.UpdateUser(7, AnyVersion, &UserPatch{TeamID: &teamID}, WriteOptions{})
+	assert.ErrorIs(t, err, ErrHubFull)
+	mockUserRepo.AssertNotCalled(t, "Update", mock.Anything)
+}
@@ -0,0 +1,459 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"hub_management_service/internal/entity"
+	"hub_management_service/internal/repository"
+	"sort"
+	"strings"
+)
+
+var ErrInvalidExpansion = errors.New("invalid expand")
+
+// MaxExpandDepth bounds the relations followed by one expand path, e.g. teams.users follows two
+const MaxExpandDepth = 3
+
+// RecordKind names the kind of records an expansion starts from
+type RecordKind string
+
+const (
+	HubRecord         RecordKind = "hub"
+	TeamRecord        RecordKind = "team"
+	UserRecord        RecordKind = "user"
+	ResourceRecord    RecordKind = "resource"
+	BookingRecord     RecordKind = "booking"
+	RegionRecord      RecordKind = "region"
+	UtilisationRecord RecordKind = "utilisation"
+	SearchHitRecord   RecordKind = "search hit"
+	countryRecord     RecordKind = "country"
+)
+
+// relations lists the relations that can be expanded from each kind of record, and the kind they lead to
+var relations = map[RecordKind]map[string]RecordKind{
+	HubRecord:         {"teams": TeamRecord, "resources": ResourceRecord, "country": countryRecord},
+	TeamRecord:        {"hub": HubRecord, "users": UserRecord},
+	UserRecord:        {"team": TeamRecord},
+	ResourceRecord:    {"hub": HubRecord},
+	BookingRecord:     {"resource": ResourceRecord, "user": UserRecord},
+	RegionRecord:      {"countries": countryRecord},
+	UtilisationRecord: {"hub": HubRecord},
+	SearchHitRecord:   {"hub": HubRecord, "team": TeamRecord},
+}
+
+// Expansion is a tree of relations to load along with records, e.g. teams and teams.users for hubs
+type Expansion map[string]Expansion
+
+// ParseExpansion reads a comma-separated list of relation paths of kind, such as teams,teams.users for hubs
+func ParseExpansion(kind RecordKind, list string) (Expansion, error) {
+	expansion := Expansion{}
+	for _, path := range strings.Split(list, ",") {
+		path = strings.TrimSpace(path)
+		if path == "" {
+			continue
+		}
+		names := strings.Split(path, ".")
+		if len(names) > MaxExpandDepth {
+			return nil, fmt.Errorf("%w: %s follows more than %d relations", ErrInvalidExpansion, path, MaxExpandDepth)
+		}
+		node, from := expansion, kind
+		for _, name := range names {
+			to, ok := relations[from][name]
+			if !ok {
+				return nil, fmt.Errorf("%w: %s has no relation %q, expected one of: %s", ErrInvalidExpansion, from, name, relationNames(from))
+			}
+			if node[name] == nil {
+				node[name] = Expansion{}
+			}
+			node, from = node[name], to
+		}
+	}
+	return expansion, nil
+}
+
+func relationNames(kind RecordKind) string {
+	names := make([]string, 0, len(relations[kind]))
+	for name := range relations[kind] {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	if len(names) == 0 {
+		return "none"
+	}
+	return strings.Join(names, ", ")
+}
+
+// ExpandService loads the relations of an expansion into records that were already found, with one query per
+// relation and level rather than per record
+type ExpandService interface {
+	ExpandHubs(hubs []*entity.Hub, expansion Expansion) error
+	ExpandTeams(teams []*entity.Team, expansion Expansion) error
+	ExpandUsers(users []*entity.User, expansion Expansion) error
+	ExpandResources(resources []*entity.Resource, expansion Expansion) error
+	ExpandBookings(bookings []*entity.Booking, expansion Expansion) error
+	ExpandRegions(regions []*entity.Region, expansion Expansion) error
+	ExpandUtilisation(hubs []*HubUtilisation, expansion Expansion) error
+	ExpandSearchHits(hits []*SearchHit, expansion Expansion) error
+}
+
+type expandService struct {
+	hubRepo      repository.HubRepository
+	teamRepo     repository.TeamRepository
+	userRepo     repository.UserRepository
+	countryRepo  repository.CountryRepository
+	resourceRepo repository.ResourceRepository
+}
+
+func NewExpandService(hubRepo repository.HubRepository, teamRepo repository.TeamRepository, userRepo repository.UserRepository, countryRepo repository.CountryRepository, resourceRepo repository.ResourceRepository) ExpandService {
+	return &expandService{hubRepo: hubRepo, teamRepo: teamRepo, userRepo: userRepo, countryRepo: countryRepo, resourceRepo: resourceRepo}
+}
+
+// ExpandHubs sets the teams, desks and rooms, and country of hubs
+func (s *expandService) ExpandHubs(hubs []*entity.Hub, expansion Expansion) error {
+	if len(hubs) == 0 {
+		return nil
+	}
+	ids := make([]uint, len(hubs))
+	for i, hub := range hubs {
+		ids[i] = hub.ID
+	}
+	if next, ok := expansion["teams"]; ok {
+		teams, _, err := s.teamRepo.List(repository.ListOptions{Where: "hub_id IN ?", Args: []interface{}{ids}})
+		if err != nil {
+			return err
+		}
+		byHub := make(map[uint][]entity.Team, len(hubs))
+		for _, team := range teams {
+			byHub[team.HubID] = append(byHub[team.HubID], team)
+		}
+		var loaded []*entity.Team
+		for _, hub := range hubs {
+			hubTeams := append([]entity.Team{}, byHub[hub.ID]...)
+			hub.Teams = &hubTeams
+			for i := range hubTeams {
+				loaded = append(loaded, &hubTeams[i])
+			}
+		}
+		if err := s.ExpandTeams(loaded, next); err != nil {
+			return err
+		}
+	}
+	if next, ok := expansion["resources"]; ok {
+		resources, _, err := s.resourceRepo.List(repository.ListOptions{Where: "hub_id IN ?", Args: []interface{}{ids}})
+		if err != nil {
+			return err
+		}
+		byHub := make(map[uint][]entity.Resource, len(hubs))
+		for _, resource := range resources {
+			byHub[resource.HubID] = append(byHub[resource.HubID], resource)
+		}
+		var loaded []*entity.Resource
+		for _, hub := range hubs {
+			hubResources := append([]entity.Resource{}, byHub[hub.ID]...)
+			hub.Resources = &hubResources
+			for i := range hubResources {
+				loaded = append(loaded, &hubResources[i])
+			}
+		}
+		if err := s.ExpandResources(loaded, next); err != nil {
+			return err
+		}
+	}
+	if _, ok := expansion["country"]; ok {
+		var countryIDs []uint
+		for _, hub := range hubs {
+			if hub.CountryID != nil {
+				countryIDs = append(countryIDs, *hub.CountryID)
+			}
+		}
+		byID := map[uint]*entity.Country{}
+		if countryIDs = distinct(countryIDs); len(countryIDs) > 0 {
+			countries, _, err := s.countryRepo.List(repository.ListOptions{Where: "id IN ?", Args: []interface{}{countryIDs}})
+			if err != nil {
+				return err
+			}
+			for i := range countries {
+				byID[countries[i].ID] = &countries[i]
+			}
+		}
+		for _, hub := range hubs {
+			if hub.CountryID != nil {
+				hub.Country = byID[*hub.CountryID]
+			}
+		}
+	}
+	return nil
+}
+
+// ExpandTeams sets the hub of teams and the users whose primary team they are
+func (s *expandService) ExpandTeams(teams []*entity.Team, expansion Expansion) error {
+	if len(teams) == 0 {
+		return nil
+	}
+	if next, ok := expansion["hub"]; ok {
+		hubIDs := make([]uint, len(teams))
+		for i, team := range teams {
+			hubIDs[i] = team.HubID
+		}
+		hubs, err := s.findHubs(hubIDs, next)
+		if err != nil {
+			return err
+		}
+		for _, team := range teams {
+			team.Hub = hubs[team.HubID]
+		}
+	}
+	if next, ok := expansion["users"]; ok {
+		ids := make([]uint, len(teams))
+		for i, team := range teams {
+			ids[i] = team.ID
+		}
+		users, _, err := s.userRepo.List(repository.ListOptions{Where: "team_id IN ?", Args: []interface{}{ids}})
+		if err != nil {
+			return err
+		}
+		byTeam := make(map[uint][]entity.User, len(teams))
+		for _, user := range users {
+			byTeam[user.TeamID] = append(byTeam[user.TeamID], user)
+		}
+		var loaded []*entity.User
+		for _, team := range teams {
+			teamUsers := append([]entity.User{}, byTeam[team.ID]...)
+			team.Users = &teamUsers
+			for i := range teamUsers {
+				loaded = append(loaded, &teamUsers[i])
+			}
+		}
+		if err := s.ExpandUsers(loaded, next); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ExpandUsers sets the primary team of users
+func (s *expandService) ExpandUsers(users []*entity.User, expansion Expansion) error {
+	if len(users) == 0 {
+		return nil
+	}
+	if next, ok := expansion["team"]; ok {
+		teamIDs := make([]uint, len(users))
+		for i, user := range users {
+			teamIDs[i] = user.TeamID
+		}
+		teams, err := s.findTeams(teamIDs, next)
+		if err != nil {
+			return err
+		}
+		for _, user := range users {
+			user.Team = teams[user.TeamID]
+		}
+	}
+	return nil
+}
+
+// ExpandResources sets the hub of desks and rooms
+func (s *expandService) ExpandResources(resources []*entity.Resource, expansion Expansion) error {
+	if len(resources) == 0 {
+		return nil
+	}
+	if next, ok := expansion["hub"]; ok {
+		hubIDs := make([]uint, len(resources))
+		for i, resource := range resources {
+			hubIDs[i] = resource.HubID
+		}
+		hubs, err := s.findHubs(hubIDs, next)
+		if err != nil {
+			return err
+		}
+		for _, resource := range resources {
+			resource.Hub = hubs[resource.HubID]
+		}
+	}
+	return nil
+}
+
+// ExpandBookings sets the booked resource and the user a booking is for
+func (s *expandService) ExpandBookings(bookings []*entity.Booking, expansion Expansion) error {
+	if len(bookings) == 0 {
+		return nil
+	}
+	if next, ok := expansion["resource"]; ok {
+		resourceIDs := make([]uint, len(bookings))
+		for i, booking := range bookings {
+			resourceIDs[i] = booking.ResourceID
+		}
+		resources, _, err := s.resourceRepo.List(repository.ListOptions{Where: "id IN ?", Args: []interface{}{distinct(resourceIDs)}})
+		if err != nil {
+			return err
+		}
+		byID := make(map[uint]*entity.Resource, len(resources))
+		loaded := make([]*entity.Resource, len(resources))
+		for i := range resources {
+			byID[resources[i].ID] = &resources[i]
+			loaded[i] = &resources[i]
+		}
+		for _, booking := range bookings {
+			booking.Resource = byID[booking.ResourceID]
+		}
+		if err := s.ExpandResources(loaded, next); err != nil {
+			return err
+		}
+	}
+	if next, ok := expansion["user"]; ok {
+		userIDs := make([]uint, len(bookings))
+		for i, booking := range bookings {
+			userIDs[i] = booking.UserID
+		}
+		users, _, err := s.userRepo.List(repository.ListOptions{Where: "id IN ?", Args: []interface{}{distinct(userIDs)}})
+		if err != nil {
+			return err
+		}
+		byID := make(map[uint]*entity.User, len(users))
+		loaded := make([]*entity.User, len(users))
+		for i := range users {
+			byID[users[i].ID] = &users[i]
+			loaded[i] = &users[i]
+		}
+		for _, booking := range bookings {
+			booking.User = byID[booking.UserID]
+		}
+		if err := s.ExpandUsers(loaded, next); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ExpandRegions sets the countries of regions, ordered by name
+func (s *expandService) ExpandRegions(regions []*entity.Region, expansion Expansion) error {
+	if len(regions) == 0 {
+		return nil
+	}
+	if _, ok := expansion["countries"]; ok {
+		ids := make([]uint, len(regions))
+		for i, region := range regions {
+			ids[i] = region.ID
+		}
+		countries, _, err := s.countryRepo.List(repository.ListOptions{Where: "region_id IN ?", Args: []interface{}{ids}})
+		if err != nil {
+			return err
+		}
+		byRegion := make(map[uint][]entity.Country, len(regions))
+		for _, country := range countries {
+			byRegion[country.RegionID] = append(byRegion[country.RegionID], country)
+		}
+		for _, region := range regions {
+			region.Countries = append([]entity.Country{}, byRegion[region.ID]...)
+		}
+	}
+	return nil
+}
+
+// ExpandUtilisation sets the hub of the lines of the utilisation report
+func (s *expandService) ExpandUtilisation(hubs []*HubUtilisation, expansion Expansion) error {
+	if len(hubs) == 0 {
+		return nil
+	}
+	if next, ok := expansion["hub"]; ok {
+		hubIDs := make([]uint, len(hubs))
+		for i, utilisation := range hubs {
+			hubIDs[i] = utilisation.HubID
+		}
+		loaded, err := s.findHubs(hubIDs, next)
+		if err != nil {
+			return err
+		}
+		for _, utilisation := range hubs {
+			utilisation.Hub = loaded[utilisation.HubID]
+		}
+	}
+	return nil
+}
+
+// ExpandSearchHits sets the hub of team hits and the primary team of user hits
+func (s *expandService) ExpandSearchHits(hits []*SearchHit, expansion Expansion) error {
+	if len(hits) == 0 {
+		return nil
+	}
+	if next, ok := expansion["hub"]; ok {
+		var hubIDs []uint
+		for _, hit := range hits {
+			if hit.HubID != 0 {
+				hubIDs = append(hubIDs, hit.HubID)
+			}
+		}
+		hubs, err := s.findHubs(hubIDs, next)
+		if err != nil {
+			return err
+		}
+		for _, hit := range hits {
+			hit.Hub = hubs[hit.HubID]
+		}
+	}
+	if next, ok := expansion["team"]; ok {
+		var teamIDs []uint
+		for _, hit := range hits {
+			if hit.TeamID != 0 {
+				teamIDs = append(teamIDs, hit.TeamID)
+			}
+		}
+		teams, err := s.findTeams(teamIDs, next)
+		if err != nil {
+			return err
+		}
+		for _, hit := range hits {
+			hit.Team = teams[hit.TeamID]
+		}
+	}
+	return nil
+}
+
+// findHubs loads the hubs with the given IDs along with the relations of expansion, by ID
+func (s *expandService) findHubs(ids []uint, expansion Expansion) (map[uint]*entity.Hub, error) {
+	if ids = distinct(ids); len(ids) == 0 {
+		return nil, nil
+	}
+	hubs, _, err := s.hubRepo.List(repository.ListOptions{Where: "id IN ?", Args: []interface{}{ids}})
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[uint]*entity.Hub, len(hubs))
+	loaded := make([]*entity.Hub, len(hubs))
+	for i := range hubs {
+		byID[hubs[i].ID] = &hubs[i]
+		loaded[i] = &hubs[i]
+	}
+	return byID, s.ExpandHubs(loaded, expansion)
+}
+
+// findTeams loads the teams with the given IDs along with the relations of expansion, by ID
+func (s *expandService) findTeams(ids []uint, expansion Expansion) (map[uint]*entity.Team, error) {
+	if ids = distinct(ids); len(ids) == 0 {
+		return nil, nil
+	}
+	teams, _, err := s.teamRepo.List(repository.ListOptions{Where: "id IN ?", Args: []interface{}{ids}})
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[uint]*entity.Team, len(teams))
+	loaded := make([]*entity.Team, len(teams))
+	for i := range teams {
+		byID[teams[i].ID] = &teams[i]
+		loaded[i] = &teams[i]
+	}
+	return byID, s.ExpandTeams(loaded, expansion)
+}
+
+// distinct drops the repeated IDs, keeping the first occurrence of each
+func distinct(ids []uint) []uint {
+	var unique []uint
+	seen := make(map[uint]bool, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	return unique
+}
@@ -0,0 +1,124 @@
+package service
+
+import (
+	"encoding/json"
+	"hub_management_service/internal/entity"
+	"hub_management_service/internal/repository"
+	"hub_management_service/internal/repository/mocks"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestParseExpansion tests that paths follow the relations of each kind of record up to MaxExpandDepth
+func TestParseExpansion(t *testing.T) {
+	expansion, err := ParseExpansion(HubRecord, "teams, teams.users,country,")
+	assert.NoError(t, err)
+	assert.Equal(t, Expansion{"teams": {"users": {}}, "country": {}}, expansion)
+
+	expansion, err = ParseExpansion(UserRecord, "team.hub.teams")
+	assert.NoError(t, err)
+	assert.Equal(t, Expansion{"team": {"hub": {"teams": {}}}}, expansion)
+
+	for kind, list := range map[RecordKind]string{
+		HubRecord:       "users",
+		TeamRecord:      "hub.country.region",
+		UserRecord:      "team.hub.teams.users",
+		BookingRecord:   "resource.hub.resources.hub",
+		SearchHitRecord: "users",
+	} {
+		_, err := ParseExpansion(kind, list)
+		assert.ErrorIs(t, err, ErrInvalidExpansion, list)
+	}
+}
+
+// TestExpandHubs tests that teams and their users are loaded with one query per level and grouped by parent
+func TestExpandHubs(t *testing.T) {
+	teamRepo := new(mocks.TeamRepository)
+	userRepo := new(mocks.UserRepository)
+	service := NewExpandService(new(mocks.HubRepository), teamRepo, userRepo, new(mocks.CountryRepository), new(mocks.ResourceRepository))
+	teamRepo.On("List", repository.ListOptions{Where: "hub_id IN ?", Args: []interface{}{[]uint{1, 2}}}).
+		Return([]entity.Team{{ID: 3, Name: "Backend", HubID: 1}, {ID: 4, Name: "Design", HubID: 1}}, int64(2), nil)
+	userRepo.On("List", repository.ListOptions{Where: "team_id IN ?", Args: []interface{}{[]uint{3, 4}}}).
+		Return([]entity.User{{ID: 7, Name: "Jane", TeamID: 4}}, int64(1), nil)
+
+	hubs := []*entity.Hub{{ID: 1, Name: "Paris Hub"}, {ID: 2, Name: "Lyon Hub"}}
+	assert.NoError(t, service.ExpandHubs(hubs, Expansion{"teams": {"users": {}}}))
+	if assert.NotNil(t, hubs[0].Teams) && assert.Len(t, *hubs[0].Teams, 2) {
+		backend, design := (*hubs[0].Teams)[0], (*hubs[0].Teams)[1]
+		assert.Empty(t, *backend.Users)
+		if assert.Len(t, *design.Users, 1) {
+			assert.Equal(t, "Jane", (*design.Users)[0].Name)
+		}
+	}
+	if assert.NotNil(t, hubs[1].Teams) {
+		assert.Empty(t, *hubs[1].Teams)
+	}
+	userRepo.AssertNumberOfCalls(t, "List", 1)
+}
+
+// TestExpandHubs_ResourcesAndCountry tests that the desks and rooms of hubs and their countries take one query each
+func TestExpandHubs_ResourcesAndCountry(t *testing.T) {
+	countryRepo := new(mocks.CountryRepository)
+	resourceRepo := new(mocks.ResourceRepository)
+	service := NewExpandService(new(mocks.HubRepository), new(mocks.TeamRepository), new(mocks.UserRepository), countryRepo, resourceRepo)
+	resourceRepo.On("List", repository.ListOptions{Where: "hub_id IN ?", Args: []interface{}{[]uint{1, 2, 3}}}).
+		Return([]entity.Resource{{ID: 4, HubID: 2, Name: "Seine"}}, int64(1), nil)
+	countryRepo.On("List", repository.ListOptions{Where: "id IN ?", Args: []interface{}{[]uint{5}}}).
+		Return([]entity.Country{{ID: 5, Code: "FR", Name: "France"}}, int64(1), nil)
+
+	france := uint(5)
+	hubs := []*entity.Hub{{ID: 1, CountryID: &france}, {ID: 2, CountryID: &france}, {ID: 3}}
+	assert.NoError(t, service.ExpandHubs(hubs, Expansion{"resources": {}, "country": {}}))
+	assert.Empty(t, *hubs[0].Resources)
+	if assert.Len(t, *hubs[1].Resources, 1) {
+		assert.Equal(t, "Seine", (*hubs[1].Resources)[0].Name)
+	}
+	assert.Same(t, hubs[0].Country, hubs[1].Country)
+	assert.Nil(t, hubs[2].Country)
+	countryRepo.AssertNumberOfCalls(t, "List", 1)
+}
+
+// TestExpandBookings tests that bookings share the resources and users they refer to, along with nested relations
+func TestExpandBookings(t *testing.T) {
+	hubRepo := new(mocks.HubRepository)
+	userRepo := new(mocks.UserRepository)
+	resourceRepo := new(mocks.ResourceRepository)
+	service := NewExpandService(hubRepo, new(mocks.TeamRepository), userRepo, new(mocks.CountryRepository), resourceRepo)
+	resourceRepo.On("List", repository.ListOptions{Where: "id IN ?", Args: []interface{}{[]uint{4}}}).
+		Return([]entity.Resource{{ID: 4, HubID: 1, Name: "Seine"}}, int64(1), nil)
+	hubRepo.On("List", repository.ListOptions{Where: "id IN ?", Args: []interface{}{[]uint{1}}}).
+		Return([]entity.Hub{{ID: 1, Name: "Paris Hub"}}, int64(1), nil)
+	userRepo.On("List", repository.ListOptions{Where: "id IN ?", Args: []interface{}{[]uint{7, 8}}}).
+		Return([]entity.User{{ID: 7, Name: "Jane"}, {ID: 8, Name: "John"}}, int64(2), nil)
+
+	bookings := []*entity.Booking{{ID: 1, ResourceID: 4, UserID: 7}, {ID: 2, ResourceID: 4, UserID: 8}}
+	assert.NoError(t, service.ExpandBookings(bookings, Expansion{"resource": {"hub": {}}, "user": {}}))
+	assert.Same(t, bookings[0].Resource, bookings[1].Resource)
+	if assert.NotNil(t, bookings[0].Resource.Hub) {
+		assert.Equal(t, "Paris Hub", bookings[0].Resource.Hub.Name)
+	}
+	assert.Equal(t, "John", bookings[1].User.Name)
+}
+
+// TestExpandUsers tests that users of the same team share it, and that the result encodes without cycles
+func TestExpandUsers(t *testing.T) {
+	hubRepo := new(mocks.HubRepository)
+	teamRepo := new(mocks.TeamRepository)
+	service := NewExpandService(hubRepo, teamRepo, new(mocks.UserRepository), new(mocks.CountryRepository), new(mocks.ResourceRepository))
+	teamRepo.On("List", repository.ListOptions{Where: "id IN ?", Args: []interface{}{[]uint{3}}}).
+		Return([]entity.Team{{ID: 3, Name: "Backend", HubID: 1}}, int64(1), nil)
+	hubRepo.On("List", repository.ListOptions{Where: "id IN ?", Args: []interface{}{[]uint{1}}}).
+		Return([]entity.Hub{{ID: 1, Name: "Paris Hub"}}, int64(1), nil)
+	teamRepo.On("List", repository.ListOptions{Where: "hub_id IN ?", Args: []interface{}{[]uint{1}}}).
+		Return([]entity.Team{{ID: 3, Name: "Backend", HubID: 1}, {ID: 5, Name: "Sales", HubID: 1}}, int64(2), nil)
+
+	users := []*entity.User{{ID: 7, TeamID: 3}, {ID: 8, TeamID: 3}}
+	assert.NoError(t, service.ExpandUsers(users, Expansion{"team": {"hub": {"teams": {}}}}))
+	assert.Same(t, users[0].Team, users[1].Team)
+	if assert.NotNil(t, users[0].Team) && assert.NotNil(t, users[0].Team.Hub) {
+		assert.Len(t, *users[0].Team.Hub.Teams, 2)
+	}
+	_, err := json.Marshal(users)
+	assert.NoError(t, err)
+}
@@ -0,0 +1,320 @@
+package service
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hub_management_service/internal/repository"
+	"hub_management_service/pkg/xlsx"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+)
+
+var ErrInvalidExport = errors.New("invalid export")
+
+// plainNumberPattern matches signed numbers and phone numbers, which spreadsheets read as values rather than formulas
+var plainNumberPattern = regexp.MustCompile(`^[+-]?[0-9 ().-]+$`)
+
+// ExportFormat is the file format of an export
+type ExportFormat string
+
+const (
+	ExportCSV   ExportFormat = "csv"
+	ExportJSONL ExportFormat = "jsonl"
+	ExportXLSX  ExportFormat = "xlsx"
+)
+
+// exportContentTypes maps the formats to their media type
+var exportContentTypes = map[ExportFormat]string{
+	ExportCSV:   "text/csv; charset=utf-8",
+	ExportJSONL: "application/jsonl",
+	ExportXLSX:  xlsx.ContentType,
+}
+
+// exportColumn is a column of the organisation export
+type exportColumn struct {
+	Name  string
+	Value func(row *repository.OrgRow) interface{} // A uint, a string or nil
+}
+
+// exportColumns lists every column in its default order
+var exportColumns = []exportColumn{
+	{"user_id", func(r *repository.OrgRow) interface{} { return r.UserID }},
+	{"name", func(r *repository.OrgRow) interface{} { return r.UserName }},
+	{"email", func(r *repository.OrgRow) interface{} { return r.Email }},
+	{"job_title", func(r *repository.OrgRow) interface{} { return r.JobTitle }},
+	{"phone", func(r *repository.OrgRow) interface{} { return r.Phone }},
+	{"employee_number", func(r *repository.OrgRow) interface{} { return stringOrNil(r.EmployeeNumber) }},
+	{"start_date", func(r *repository.OrgRow) interface{} {
+		if r.StartDate == nil {
+			return nil
+		}
+		return r.StartDate.String()
+	}},
+	{"employment_type", func(r *repository.OrgRow) interface{} { return r.EmploymentType }},
+	{"locale", func(r *repository.OrgRow) interface{} { return r.Locale }},
+	{"time_zone", func(r *repository.OrgRow) interface{} { return r.TimeZone }},
+	{"manager_id", func(r *repository.OrgRow) interface{} { return uintOrNil(r.ManagerID) }},
+	{"deactivated_at", func(r *repository.OrgRow) interface{} {
+		if r.DeactivatedAt == nil {
+			return nil
+		}
+		return r.DeactivatedAt.UTC().Format(time.RFC3339)
+	}},
+	{"team_id", func(r *repository.OrgRow) interface{} { return r.TeamID }},
+	{"team", func(r *repository.OrgRow) interface{} { return r.TeamName }},
+	{"parent_team_id", func(r *repository.OrgRow) interface{} { return uintOrNil(r.ParentTeamID) }},
+	{"hub_id", func(r *repository.OrgRow) interface{} { return r.HubID }},
+	{"hub", func(r *repository.OrgRow) interface{} { return r.HubName }},
+	{"hub_location", func(r *repository.OrgRow) interface{} { return r.HubLocation }},
+	{"hub_city", func(r *repository.OrgRow) interface{} { return r.HubCity }},
+	{"country", func(r *repository.OrgRow) interface{} { return stringOrNil(r.CountryCode) }},
+}
+
+// ExportQuery selects the users and columns of an export
+type ExportQuery struct {
+	Format  ExportFormat
+	Columns []string // Column names in output order, every column when empty
+	HubID   uint
+	TeamID  uint // The team and all of its sub-teams
+}
+
+// Export is a checked export ready to be streamed
+type Export interface {
+	ContentType() string
+	Extension() string
+	// Stream writes the export to w one row at a time
+	Stream(w io.Writer) error
+}
+
+type ExportService interface {
+	PrepareExport(query ExportQuery) (Export, error)
+}
+
+type exportService struct {
+	repo     repository.ExportRepository
+	hubRepo  repository.HubRepository
+	teamRepo repository.TeamRepository
+}
+
+func NewExportService(repo repository.ExportRepository, hubRepo repository.HubRepository, teamRepo repository.TeamRepository) ExportService {
+	return &exportService{repo: repo, hubRepo: hubRepo, teamRepo: teamRepo}
+}
+
+// PrepareExport checks the format, columns and filters of an export of users joined with their team and hub,
+// so that errors are reported before anything is streamed
+func (s *exportService) PrepareExport(query ExportQuery) (Export, error) {
+	if _, ok := exportContentTypes[query.Format]; !ok {
+		return nil, fmt.Errorf("%w: unknown format %q, expected csv, jsonl or xlsx", ErrInvalidExport, query.Format)
+	}
+	columns, err := selectExportColumns(query.Columns)
+	if err != nil {
+		return nil, err
+	}
+
+	var conditions []string
+	var args []interface{}
+	if query.HubID != 0 {
+		hub, err := s.hubRepo.FindByID(query.HubID)
+		if repository.IsNotFound(err) || (err == nil && hub == nil) {
+			return nil, ErrHubNotFound
+		}
+		if err != nil {
+			return nil, err
+		}
+		conditions = append(conditions, "hubs.id = ?")
+		args = append(args, query.HubID)
+	}
+	if query.TeamID != 0 {
+		team, err := s.teamRepo.FindByID(query.TeamID)
+		if repository.IsNotFound(err) || (err == nil && team == nil) {
+			return nil, ErrTeamNotFound
+		}
+		if err != nil {
+			return nil, err
+		}
+		descendants, err := s.teamRepo.FindDescendants(team.ID)
+		if err != nil {
+			return nil, err
+		}
+		teamIDs := []uint{team.ID}
+		for _, descendant := range descendants {
+			teamIDs = append(teamIDs, descendant.ID)
+		}
+		conditions = append(conditions, "teams.id IN ?")
+		args = append(args, teamIDs)
+	}
+
+	return &orgExport{
+		repo:    s.repo,
+		format:  query.Format,
+		columns: columns,
+		opts:    repository.ListOptions{Where: strings.Join(conditions, " AND "), Args: args},
+	}, nil
+}
+
+// selectExportColumns looks up the requested columns, every column when none are requested
+func selectExportColumns(names []string) ([]exportColumn, error) {
+	if len(names) == 0 {
+		return exportColumns, nil
+	}
+	columns := make([]exportColumn, 0, len(names))
+	seen := make(map[string]bool, len(names))
+	for _, name := range names {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		column, ok := findExportColumn(name)
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown column %q", ErrInvalidExport, name)
+		}
+		columns = append(columns, column)
+	}
+	return columns, nil
+}
+
+func findExportColumn(name string) (exportColumn, bool) {
+	for _, column := range exportColumns {
+		if column.Name == name {
+			return column, true
+		}
+	}
+	return exportColumn{}, false
+}
+
+// orgExport streams users joined with their team and hub
+type orgExport struct {
+	repo    repository.ExportRepository
+	format  ExportFormat
+	columns []exportColumn
+	opts    repository.ListOptions
+}
+
+func (e *orgExport) ContentType() string {
+	return exportContentTypes[e.format]
+}
+
+func (e *orgExport) Extension() string {
+	return string(e.format)
+}
+
+func (e *orgExport) Stream(w io.Writer) error {
+	switch e.format {
+	case ExportXLSX:
+		return e.streamXLSX(w)
+	case ExportJSONL:
+		return e.streamJSONL(w)
+	default:
+		return e.streamCSV(w)
+	}
+}
+
+func (e *orgExport) streamCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	record := make([]string, len(e.columns))
+	for i, column := range e.columns {
+		record[i] = column.Name
+	}
+	if err := writer.Write(record); err != nil {
+		return err
+	}
+	err := e.repo.StreamOrg(e.opts, func(row *repository.OrgRow) error {
+		for i, column := range e.columns {
+			if value := csvSafe(column.Value(row)); value != nil {
+				record[i] = fmt.Sprint(value)
+			} else {
+				record[i] = ""
+			}
+		}
+		return writer.Write(record)
+	})
+	if err != nil {
+		return err
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+func (e *orgExport) streamJSONL(w io.Writer) error {
+	writer := bufio.NewWriter(w)
+	keys := make([][]byte, len(e.columns))
+	for i, column := range e.columns {
+		keys[i], _ = json.Marshal(column.Name)
+	}
+	err := e.repo.StreamOrg(e.opts, func(row *repository.OrgRow) error {
+		// Objects are written by hand to keep the columns in the requested order
+		writer.WriteByte('{')
+		for i, column := range e.columns {
+			if i > 0 {
+				writer.WriteByte(',')
+			}
+			value, err := json.Marshal(column.Value(row))
+			if err != nil {
+				return err
+			}
+			writer.Write(keys[i])
+			writer.WriteByte(':')
+			writer.Write(value)
+		}
+		_, err := writer.WriteString("}\n")
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	return writer.Flush()
+}
+
+func (e *orgExport) streamXLSX(w io.Writer) error {
+	writer, err := xlsx.NewWriter(w, "Organisation")
+	if err != nil {
+		return err
+	}
+	cells := make([]interface{}, len(e.columns))
+	for i, column := range e.columns {
+		cells[i] = column.Name
+	}
+	if err := writer.WriteRow(cells); err != nil {
+		return err
+	}
+	err = e.repo.StreamOrg(e.opts, func(row *repository.OrgRow) error {
+		for i, column := range e.columns {
+			// Strings are written as inline text, which spreadsheets never evaluate as formulas
+			cells[i] = column.Value(row)
+		}
+		return writer.WriteRow(cells)
+	})
+	if err != nil {
+		return err
+	}
+	return writer.Close()
+}
+
+// csvSafe keeps spreadsheets opening a CSV export from running text as a formula: strings starting with =, +, -, @, a
+// tab or a carriage return get a leading ' that makes them plain text, unless they are plain numbers such as phones
+func csvSafe(value interface{}) interface{} {
+	if s, ok := value.(string); ok && s != "" && strings.ContainsRune("=+-@\t\r", rune(s[0])) && !plainNumberPattern.MatchString(s) {
+		return "'" + s
+	}
+	return value
+}
+
+func stringOrNil(s *string) interface{} {
+	if s == nil {
+		return nil
+	}
+	return *s
+}
+
+func uintOrNil(n *uint) interface{} {
+	if n == nil {
+		return nil
+	}
+	return *n
+}
@@ -0,0 +1,173 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"gorm.io/gorm"
+	"hub_management_service/internal/entity"
+	"hub_management_service/internal/repository"
+	"hub_management_service/internal/repository/mocks"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// newTestExportService streams two users, one of them without a country or employee number
+func newTestExportService(opts repository.ListOptions) (ExportService, *mocks.TeamRepository) {
+	exportRepo := new(mocks.ExportRepository)
+	hubRepo := new(mocks.HubRepository)
+	teamRepo := new(mocks.TeamRepository)
+	hubRepo.On("FindByID", uint(1)).Return(&entity.Hub{ID: 1}, nil)
+	hubRepo.On("FindByID", mock.Anything).Return(nil, gorm.ErrRecordNotFound)
+
+	employeeNumber, country := "E-7", "DE"
+	exportRepo.On("StreamOrg", opts, mock.Anything).Run(func(args mock.Arguments) {
+		fn := args.Get(1).(func(row *repository.OrgRow) error)
+		fn(&repository.OrgRow{UserID: 7, UserName: "Jane, \"JD\" Doe", Email: "jane@example.com", EmployeeNumber: &employeeNumber,
+			TeamID: 2, TeamName: "Platform", HubID: 1, HubName: "Berlin Hub", CountryCode: &country})
+		fn(&repository.OrgRow{UserID: 8, UserName: "Adam", Email: "adam@example.com", TeamID: 3, TeamName: "Backend", HubID: 4, HubName: "Paris Hub"})
+	}).Return(nil)
+	return NewExportService(exportRepo, hubRepo, teamRepo), teamRepo
+}
+
+// TestExportCSV tests that the requested columns are written in order and quoted as needed
+func TestExportCSV(t *testing.T) {
+	service, _ := newTestExportService(repository.ListOptions{})
+	export, err := service.PrepareExport(ExportQuery{Format: ExportCSV, Columns: []string{"name", "Team", "hub", "employee_number", "user_id"}})
+	assert.NoError(t, err)
+	assert.Equal(t, "text/csv; charset=utf-8", export.ContentType())
+
+	var buf bytes.Buffer
+	assert.NoError(t, export.Stream(&buf))
+	assert.Equal(t, "name,team,hub,employee_number,user_id\n"+
+		"\"Jane, \"\"JD\"\" Doe\",Platform,Berlin Hub,E-7,7\n"+
+		"Adam,Backend,Paris Hub,,8\n", buf.String())
+}
+
+// TestExportJSONL tests that values keep their type and nulls are written for missing values
+func TestExportJSONL(t *testing.T) {
+	service, _ := newTestExportService(repository.ListOptions{Where: "hubs.id = ?", Args: []interface{}{uint(1)}})
+	export, err := service.PrepareExport(ExportQuery{Format: ExportJSONL, Columns: []string{"user_id", "email", "country"}, HubID: 1})
+	assert.NoError(t, err)
+
+	var buf bytes.Buffer
+	assert.NoError(t, export.Stream(&buf))
+	assert.Equal(t, `{"user_id":7,"email":"jane@example.com","country":"DE"}`+"\n"+
+		`{"user_id":8,"email":"adam@example.com","country":null}`+"\n", buf.String())
+}
+
+// TestExportXLSX tests that every column is exported to a workbook by default
+func TestExportXLSX(t *testing.T) {
+	service, _ := newTestExportService(repository.ListOptions{})
+	export, err := service.PrepareExport(ExportQuery{Format: ExportXLSX})
+	assert.NoError(t, err)
+	assert.Equal(t, "xlsx", export.Extension())
+
+	var buf bytes.Buffer
+	assert.NoError(t, export.Stream(&buf))
+	archive, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if assert.NoError(t, err) {
+		for _, file := range archive.File {
+			if file.Name == "xl/worksheets/sheet1.xml" {
+				r, _ := file.Open()
+				sheet, _ := io.ReadAll(r)
+				assert.Contains(t, string(sheet), `<c r="T1" t="inlineStr"><is><t xml:space="preserve">country</t></is></c>`)
+				assert.Contains(t, string(sheet), `<c r="A3"><v>8</v></c>`)
+			}
+		}
+	}
+}
+
+// streamTestExport exports one row with the given columns in a format
+func streamTestExport(t *testing.T, row *repository.OrgRow, format ExportFormat, columns ...string) string {
+	exportRepo := new(mocks.ExportRepository)
+	exportRepo.On("StreamOrg", repository.ListOptions{}, mock.Anything).Run(func(args mock.Arguments) {
+		fn := args.Get(1).(func(row *repository.OrgRow) error)
+		fn(row)
+	}).Return(nil)
+	service := NewExportService(exportRepo, new(mocks.HubRepository), new(mocks.TeamRepository))
+	export, err := service.PrepareExport(ExportQuery{Format: format, Columns: columns})
+	assert.NoError(t, err)
+	var buf bytes.Buffer
+	assert.NoError(t, export.Stream(&buf))
+	return buf.String()
+}
+
+// testWorksheet reads the first worksheet of an XLSX export
+func testWorksheet(t *testing.T, workbook string) string {
+	archive, err := zip.NewReader(strings.NewReader(workbook), int64(len(workbook)))
+	if !assert.NoError(t, err) {
+		return ""
+	}
+	for _, file := range archive.File {
+		if file.Name == "xl/worksheets/sheet1.xml" {
+			r, _ := file.Open()
+			sheet, _ := io.ReadAll(r)
+			return string(sheet)
+		}
+	}
+	t.Fatal("the workbook has no worksheet")
+	return ""
+}
+
+// TestExport_FormulaInjection tests that text a spreadsheet would run as a formula is exported as plain text to CSV,
+// and as is to XLSX inline strings, which are never evaluated, and to JSON Lines
+func TestExport_FormulaInjection(t *testing.T) {
+	row := &repository.OrgRow{UserID: 7, UserName: `=HYPERLINK("http://evil.example","Jane")`, JobTitle: "@SUM(A1)"}
+	columns := []string{"user_id", "name", "job_title"}
+
+	assert.Equal(t, "user_id,name,job_title\n"+
+		`7,"'=HYPERLINK(""http://evil.example"",""Jane"")",'@SUM(A1)`+"\n", streamTestExport(t, row, ExportCSV, columns...))
+	assert.Contains(t, streamTestExport(t, row, ExportJSONL, columns...), `"name":"=HYPERLINK(\"http://evil.example\",\"Jane\")"`)
+
+	sheet := testWorksheet(t, streamTestExport(t, row, ExportXLSX, columns...))
+	assert.Contains(t, sheet, `<t xml:space="preserve">=HYPERLINK(&#34;http://evil.example&#34;,&#34;Jane&#34;)</t>`)
+	assert.Contains(t, sheet, `<c r="A2"><v>7</v></c>`)
+}
+
+// TestExport_PhoneRoundTrip tests that phones and signed numbers are exported unchanged to CSV and XLSX, so that an
+// export can be imported again
+func TestExport_PhoneRoundTrip(t *testing.T) {
+	row := &repository.OrgRow{UserID: 7, Phone: "+4930123456", JobTitle: "-12.5"}
+	columns := []string{"user_id", "phone", "job_title"}
+
+	records, err := csv.NewReader(strings.NewReader(streamTestExport(t, row, ExportCSV, columns...))).ReadAll()
+	if assert.NoError(t, err) && assert.Len(t, records, 2) {
+		assert.Equal(t, []string{"7", "+4930123456", "-12.5"}, records[1])
+		assert.Regexp(t, phonePattern, records[1][1])
+	}
+
+	sheet := testWorksheet(t, streamTestExport(t, row, ExportXLSX, columns...))
+	assert.Contains(t, sheet, `<t xml:space="preserve">+4930123456</t>`)
+	assert.Contains(t, sheet, `<t xml:space="preserve">-12.5</t>`)
+}
+
+// TestPrepareExport_TeamFilter tests that a team filter includes the sub-teams
+func TestPrepareExport_TeamFilter(t *testing.T) {
+	service, teamRepo := newTestExportService(repository.ListOptions{Where: "teams.id IN ?", Args: []interface{}{[]uint{2, 5, 6}}})
+	teamRepo.On("FindByID", uint(2)).Return(&entity.Team{ID: 2}, nil)
+	teamRepo.On("FindByID", uint(9)).Return(nil, gorm.ErrRecordNotFound)
+	teamRepo.On("FindDescendants", uint(2)).Return([]entity.Team{{ID: 5}, {ID: 6}}, nil)
+
+	export, err := service.PrepareExport(ExportQuery{Format: ExportCSV, TeamID: 2})
+	assert.NoError(t, err)
+	assert.NoError(t, export.Stream(io.Discard))
+
+	_, err = service.PrepareExport(ExportQuery{Format: ExportCSV, TeamID: 9})
+	assert.ErrorIs(t, err, ErrTeamNotFound)
+}
+
+// TestPrepareExport_Invalid tests that bad formats, columns and hubs are rejected before streaming
+func TestPrepareExport_Invalid(t *testing.T) {
+	service, _ := newTestExportService(repository.ListOptions{})
+	_, err := service.PrepareExport(ExportQuery{Format: "pdf"})
+	assert.ErrorIs(t, err, ErrInvalidExport)
+	_, err = service.PrepareExport(ExportQuery{Format: ExportCSV, Columns: []string{"name", "salary"}})
+	assert.ErrorIs(t, err, ErrInvalidExport)
+	_, err = service.PrepareExport(ExportQuery{Format: ExportCSV, HubID: 9})
+	assert.ErrorIs(t, err, ErrHubNotFound)
+}
@@ -1,25 +1,106 @@
 package service
 
 import (
+	"errors"
+	"fmt"
 	"hub_management_service/internal/entity"
 	"hub_management_service/internal/repository"
+	"hub_management_service/pkg/geo"
+	"math"
+	"sort"
+	"strings"
 )
 
+var (
+	ErrHubNotFound        = errors.New("hub does not exist")
+	ErrHubNotEmpty        = errors.New("hub still has teams")
+	ErrInvalidCoordinates = errors.New("latitude must be between -90 and 90 and longitude between -180 and 180, and both must be set together")
+	ErrInvalidRadius      = errors.New("radius must be greater than 0")
+	ErrInvalidTimeZone    = errors.New("time zone must be an IANA time zone, e.g. Europe/Berlin")
+)
+
+// Page size limits of ListHubs
+const (
+	defaultHubListLimit = 50
+	maxHubListLimit     = 200
+)
+
+// Defaults of FindNearby
+const (
+	defaultNearbyRadiusKm = 50
+	defaultNearbyLimit    = 20
+	maxNearbyLimit        = 200
+)
+
+// HubQuery filters a hub listing, zero fields are ignored
+type HubQuery struct {
+	RegionID  uint
+	CountryID uint
+	City      string // Case-insensitive exact match
+	Offset    int
+	Limit     int
+}
+
+// NearbyQuery searches hubs around a point, zero radius and limit select the defaults
+type NearbyQuery struct {
+	Lat      float64
+	Lng      float64
+	RadiusKm float64
+	Limit    int
+}
+
+// NearbyHub is a hub along with its distance from the searched point
+type NearbyHub struct {
+	entity.Hub
+	DistanceKm float64 `json:"distance_km"`
+}
+
 type HubService interface {
 	CreateHub(hub *entity.Hub) error
 	FindHubByID(id uint) (*entity.Hub, error)
 	SearchHubsByName(name string) ([]entity.Hub, error)
+	UpdateHub(id, version uint, hub *entity.Hub) (*entity.Hub, error)
+	PatchHub(id, version uint, format PatchFormat, patch []byte) (*entity.Hub, error)
+	DeleteHub(id, version uint) error
+	ListHubs(query HubQuery) ([]entity.Hub, int64, error)
+	FindNearby(query NearbyQuery) ([]NearbyHub, error)
+	FindUtilisation(hub *entity.Hub) (*HubUtilisation, error)
+	UtilisationReport() (*UtilisationReport, error)
 }
 
 type hubService struct {
-	repo repository.HubRepository
+	tx          repository.Transactor
+	repo        repository.HubRepository
+	countryRepo repository.CountryRepository
+	capacity    *CapacityPolicy
 }
 
-func NewHubService(repo repository.HubRepository) HubService {
-	return &hubService{repo: repo}
+func NewHubService(tx repository.Transactor, repo repository.HubRepository, countryRepo repository.CountryRepository, capacity *CapacityPolicy) HubService {
+	return &hubService{tx: tx, repo: repo, countryRepo: countryRepo, capacity: capacity}
 }
 
+// bind returns the service working with the repositories of a transaction
+func (s *hubService) bind(repos repository.Repositories) *hubService {
+	return &hubService{tx: repos, repo: repos.Hubs, countryRepo: repos.Countries, capacity: s.capacity.bind(repos)}
+}
+
+// CreateHub checks the country and creates the hub in one transaction
 func (s *hubService) CreateHub(hub *entity.Hub) error {
+	return inTransaction(s.tx, s, s.bind, func(s *hubService) error {
+		return s.createHub(hub)
+	})
+}
+
+func (s *hubService) createHub(hub *entity.Hub) error {
+	if err := checkCoordinates(hub); err != nil {
+		return err
+	}
+	if err := checkTimeZone(hub); err != nil {
+		return err
+	}
+	if err := s.checkCountry(hub); err != nil {
+		return err
+	}
 	return s.repo.Create(hub)
 }
 
@@ -32,3 +113,267 @@ func (s *hubService) FindHubByID(id uint) (*entity.Hub, error) {
 func (s *hubService) SearchHubsByName(name string) ([]entity.Hub, error) {
 	return s.repo.SearchByName(name)
 }
+
+// UpdateHub replaces the name and location of a hub at version, or any version with AnyVersion
+func (s *hubService) UpdateHub(id, version uint, update *entity.Hub) (*entity.Hub, error) {
+	var hub *entity.Hub
+	err := inTransaction(s.tx, s, s.bind, func(s *hubService) (err error) {
+		hub, err = s.updateHub(id, version, update)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return hub, nil
+}
+
+func (s *hubService) updateHub(id, version uint, update *entity.Hub) (*entity.Hub, error) {
+	hub, err := s.findHub(id, version)
+	if err != nil {
+		return nil, err
+	}
+	return hub, s.replaceHub(hub, update)
+}
+
+// PatchHub applies a JSON Merge Patch or JSON Patch document to a hub at version, the result is checked and saved
+// as by UpdateHub
+func (s *hubService) PatchHub(id, version uint, format PatchFormat, patch []byte) (*entity.Hub, error) {
+	var hub *entity.Hub
+	err := inTransaction(s.tx, s, s.bind, func(s *hubService) (err error) {
+		if hub, err = s.findHub(id, version); err != nil {
+			return err
+		}
+		current := *hub
+		current.Country, current.Teams, current.Resources = nil, nil, nil
+		var update entity.Hub
+		if err := applyPatch(format, &current, patch, &update, "id", "country", "teams", "resources"); err != nil {
+			return err
+		}
+		return s.replaceHub(hub, &update)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return hub, nil
+}
+
+// findHub finds a hub to change at version
+func (s *hubService) findHub(id, version uint) (*entity.Hub, error) {
+	hub, err := s.repo.FindByID(id)
+	if repository.IsNotFound(err) || (err == nil && hub == nil) {
+		return nil, ErrHubNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := checkVersion("hub", hub.Version, version); err != nil {
+		return nil, err
+	}
+	return hub, nil
+}
+
+// replaceHub copies the fields of update onto hub, checks and saves it
+func (s *hubService) replaceHub(hub, update *entity.Hub) error {
+	hub.Name = update.Name
+	hub.Location = update.Location
+	hub.CountryID = update.CountryID
+	hub.City = strings.TrimSpace(update.City)
+	hub.Address = update.Address
+	hub.Latitude = update.Latitude
+	hub.Longitude = update.Longitude
+	hub.TimeZone = update.TimeZone
+	hub.Capacity = update.Capacity
+	hub.Country = nil
+	if err := checkCoordinates(hub); err != nil {
+		return err
+	}
+	if err := checkTimeZone(hub); err != nil {
+		return err
+	}
+	if err := s.checkCountry(hub); err != nil {
+		return err
+	}
+	return versionError(s.repo.Update(hub))
+}
+
+// DeleteHub deletes a hub at version along with its resources and schedule. Its teams, and their users, would be
+// deleted with it, so they must be moved or deleted first.
+func (s *hubService) DeleteHub(id, version uint) error {
+	return inTransaction(s.tx, s, s.bind, func(s *hubService) error {
+		hub, err := s.findHub(id, version)
+		if err != nil {
+			return err
+		}
+		teams, err := s.repo.CountTeams(id)
+		if err != nil {
+			return err
+		}
+		if teams > 0 {
+			return fmt.Errorf("%w: hub %d has %d teams", ErrHubNotEmpty, id, teams)
+		}
+		return versionError(s.repo.Delete(id, hub.Version))
+	})
+}
+
+// ListHubs returns a page of hubs filtered by region, country or city, along with the total number of matches
+func (s *hubService) ListHubs(query HubQuery) ([]entity.Hub, int64, error) {
+	var conditions []string
+	var args []interface{}
+	if query.RegionID != 0 {
+		conditions = append(conditions, "country_id IN (SELECT id FROM countries WHERE region_id = ?)")
+		args = append(args, query.RegionID)
+	}
+	if query.CountryID != 0 {
+		conditions = append(conditions, "country_id = ?")
+		args = append(args, query.CountryID)
+	}
+	if city := strings.TrimSpace(query.City); city != "" {
+		conditions = append(conditions, "LOWER(city) = LOWER(?)")
+		args = append(args, city)
+	}
+
+	limit := query.Limit
+	if limit <= 0 {
+		limit = defaultHubListLimit
+	}
+	if limit > maxHubListLimit {
+		limit = maxHubListLimit
+	}
+	return s.repo.List(repository.ListOptions{
+		Where:  strings.Join(conditions, " AND "),
+		Args:   args,
+		Offset: query.Offset,
+		Limit:  limit,
+	})
+}
+
+// FindNearby returns the hubs within a radius of a point, nearest first. Candidates are narrowed down
+// with a bounding box in the database and exact haversine distances are computed here, which works
+// on any database without a geo extension.
+func (s *hubService) FindNearby(query NearbyQuery) ([]NearbyHub, error) {
+	center := geo.Point{Lat: query.Lat, Lng: query.Lng}
+	if !center.Valid() {
+		return nil, ErrInvalidCoordinates
+	}
+	radius := query.RadiusKm
+	if radius == 0 {
+		radius = defaultNearbyRadiusKm
+	}
+	if radius < 0 || math.IsNaN(radius) {
+		return nil, ErrInvalidRadius
+	}
+	radius = math.Min(radius, geo.MaxDistanceKm)
+	limit := query.Limit
+	if limit <= 0 {
+		limit = defaultNearbyLimit
+	}
+	if limit > maxNearbyLimit {
+		limit = maxNearbyLimit
+	}
+
+	box := geo.BoundingBox(center, radius)
+	conditions := []string{"latitude BETWEEN ? AND ?"}
+	args := []interface{}{box.MinLat, box.MaxLat}
+	if box.WrapsLng() {
+		conditions = append(conditions, "(longitude >= ? OR longitude <= ?)")
+	} else {
+		conditions = append(conditions, "longitude BETWEEN ? AND ?")
+	}
+	args = append(args, box.MinLng, box.MaxLng)
+
+	candidates, _, err := s.repo.List(repository.ListOptions{Where: strings.Join(conditions, " AND "), Args: args})
+	if err != nil {
+		return nil, err
+	}
+
+	var nearby []NearbyHub
+	for _, hub := range candidates {
+		if hub.Latitude == nil || hub.Longitude == nil {
+			continue
+		}
+		distance := geo.Distance(center, geo.Point{Lat: *hub.Latitude, Lng: *hub.Longitude})
+		if distance <= radius {
+			nearby = append(nearby, NearbyHub{Hub: hub, DistanceKm: math.Round(distance*1000) / 1000})
+		}
+	}
+	sort.SliceStable(nearby, func(i, j int) bool { return nearby[i].DistanceKm < nearby[j].DistanceKm })
+	if len(nearby) > limit {
+		nearby = nearby[:limit]
+	}
+	return nearby, nil
+}
+
+// FindUtilisation computes the allocated headcount of a hub against its capacity
+func (s *hubService) FindUtilisation(hub *entity.Hub) (*HubUtilisation, error) {
+	counts, err := s.repo.CountUsers([]uint{hub.ID})
+	if err != nil {
+		return nil, err
+	}
+	utilisation := s.capacity.Utilisation(hub, counts[hub.ID])
+	return &utilisation, nil
+}
+
+// UtilisationReport computes the utilisation of every hub, the most utilised first and hubs without capacity last
+func (s *hubService) UtilisationReport() (*UtilisationReport, error) {
+	hubs, err := s.repo.FindAll()
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]uint, 0, len(hubs))
+	for _, hub := range hubs {
+		ids = append(ids, hub.ID)
+	}
+	counts, err := s.repo.CountUsers(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	report := make([]HubUtilisation, 0, len(hubs))
+	for i := range hubs {
+		report = append(report, s.capacity.Utilisation(&hubs[i], counts[hubs[i].ID]))
+	}
+	sort.SliceStable(report, func(i, j int) bool {
+		a, b := report[i], report[j]
+		if a.Capacity == nil || b.Capacity == nil {
+			return a.Capacity != nil && b.Capacity == nil
+		}
+		if a.Utilisation == nil || b.Utilisation == nil {
+			// Zero capacity is the fullest a hub can be
+			return a.Utilisation == nil && b.Utilisation != nil
+		}
+		return *a.Utilisation > *b.Utilisation
+	})
+	return &UtilisationReport{Threshold: s.capacity.threshold(), Hubs: report}, nil
+}
+
+// checkCoordinates checks that a hub has both or neither of latitude and longitude, within range
+func checkCoordinates(hub *entity.Hub) error {
+	if hub.Latitude == nil && hub.Longitude == nil {
+		return nil
+	}
+	if hub.Latitude == nil || hub.Longitude == nil || !(geo.Point{Lat: *hub.Latitude, Lng: *hub.Longitude}).Valid() {
+		return ErrInvalidCoordinates
+	}
+	return nil
+}
+
+// checkTimeZone trims the time zone of a hub and checks it is an IANA time zone
+func checkTimeZone(hub *entity.Hub) error {
+	hub.TimeZone = strings.TrimSpace(hub.TimeZone)
+	if hub.TimeZone != "" && !validTimeZone(hub.TimeZone) {
+		return ErrInvalidTimeZone
+	}
+	return nil
+}
+
+// checkCountry checks that the country of a hub, if any, exists
+func (s *hubService) checkCountry(hub *entity.Hub) error {
+	if hub.CountryID == nil {
+		return nil
+	}
+	country, err := s.countryRepo.FindByID(*hub.CountryID)
+	if repository.IsNotFound(err) || (err == nil && country == nil) {
+		return ErrCountryNotFound
+	}
+	return err
+}
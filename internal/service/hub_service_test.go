@@ -3,17 +3,19 @@ package service
 import (
 	"errors"
 	"hub_management_service/internal/entity"
+	"hub_management_service/internal/repository"
 	"hub_management_service/internal/repository/mocks"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
 )
 
 // TestCreateHub tests the CreateHub service method
 func TestCreateHub(t *testing.T) {
 	mockRepo := new(mocks.HubRepository)
-	service := NewHubService(mockRepo)
+	service := NewHubService(nil, mockRepo, new(mocks.CountryRepository), nil)
 
 	// Mock the Create method of HubRepository
 	mockRepo.On("Create", mock.AnythingOfType("*entity.Hub")).Return(nil)
@@ -34,7 +36,7 @@ func TestCreateHub(t *testing.T) {
 // TestCreateHub_Error tests the CreateHub service method when the repository returns an error
 func TestCreateHub_Error(t *testing.T) {
 	mockRepo := new(mocks.HubRepository)
-	service := NewHubService(mockRepo)
+	service := NewHubService(nil, mockRepo, new(mocks.CountryRepository), nil)
 
 	// Mock the Create method of HubRepository to return an error
 	mockRepo.On("Create", mock.AnythingOfType("*entity.Hub")).Return(errors.New("unable to create hub"))
@@ -56,7 +58,7 @@ func TestCreateHub_Error(t *testing.T) {
 // TestFindHubByID tests the FindHubByID service method when the hub is found
 func TestFindHubByID(t *testing.T) {
 	mockRepo := new(mocks.HubRepository)
-	service := NewHubService(mockRepo)
+	service := NewHubService(nil, mockRepo, new(mocks.CountryRepository), nil)
 
 	// Mock the FindByID method of HubRepository to return a hub
 	mockRepo.On("FindByID", uint(1)).Return(&entity.Hub{
@@ -78,7 +80,7 @@ func TestFindHubByID(t *testing.T) {
 // TestFindHubByID_NotFound tests the FindHubByID service method when the hub is not found
 func TestFindHubByID_NotFound(t *testing.T) {
 	mockRepo := new(mocks.HubRepository)
-	service := NewHubService(mockRepo)
+	service := NewHubService(nil, mockRepo, new(mocks.CountryRepository), nil)
 
 	// Mock the FindByID method of HubRepository to return nil (hub not found)
 	mockRepo.On("FindByID", uint(1)).Return(nil, nil)
@@ -95,7 +97,7 @@ func TestFindHubByID_NotFound(t *testing.T) {
 // TestFindHubByID_Error tests the FindHubByID service method when an error occurs
 func TestFindHubByID_Error(t *testing.T) {
 	mockRepo := new(mocks.HubRepository)
-	service := NewHubService(mockRepo)
+	service := NewHubService(nil, mockRepo, new(mocks.CountryRepository), nil)
 
 	// Mock the FindByID method of HubRepository to return an error
 	mockRepo.On("FindByID", uint(1)).Return(nil, errors.New("unable to find hub"))
@@ -113,7 +115,7 @@ func TestFindHubByID_Error(t *testing.T) {
 // TestSearchHubsByName tests the SearchHubsByName service method when hubs are found
 func TestSearchHubsByName(t *testing.T) {
 	mockRepo := new(mocks.HubRepository)
-	service := NewHubService(mockRepo)
+	service := NewHubService(nil, mockRepo, new(mocks.CountryRepository), nil)
 
 	// Mock the SearchByName method of HubRepository to return a list of hubs
 	mockRepo.On("SearchByName", "Test").Return([]entity.Hub{
@@ -135,7 +137,7 @@ func TestSearchHubsByName(t *testing.T) {
 // TestSearchHubsByName_NoResults tests the SearchHubsByName service method when no hubs are found
 func TestSearchHubsByName_NoResults(t *testing.T) {
 	mockRepo := new(mocks.HubRepository)
-	service := NewHubService(mockRepo)
+	service := NewHubService(nil, mockRepo, new(mocks.CountryRepository), nil)
 
 	// Mock the SearchByName method of HubRepository to return an empty list
 	mockRepo.On("SearchByName", "NonExistent").Return([]entity.Hub{}, nil)
@@ -152,7 +154,7 @@ func TestSearchHubsByName_NoResults(t *testing.T) {
 // TestSearchHubsByName_Error tests the SearchHubsByName service method when an error occurs
 func TestSearchHubsByName_Error(t *testing.T) {
 	mockRepo := new(mocks.HubRepository)
-	service := NewHubService(mockRepo)
+	service := NewHubService(nil, mockRepo, new(mocks.CountryRepository), nil)
 
 	// Mock the SearchByName method of HubRepository to return an error
 	mockRepo.On("SearchByName", "Test").Return(nil, errors.New("unable to search hubs"))
@@ -166,3 +168,162 @@ func TestSearchHubsByName_Error(t *testing.T) {
 	assert.Equal(t, "unable to search hubs", err.Error())
 	mockRepo.AssertExpectations(t)
 }
+
+// TestCreateHub_CountryNotFound tests that the country of a hub must exist
+func TestCreateHub_CountryNotFound(t *testing.T) {
+	mockRepo := new(mocks.HubRepository)
+	mockCountryRepo := new(mocks.CountryRepository)
+	service := NewHubService(nil, mockRepo, mockCountryRepo, nil)
+
+	mockCountryRepo.On("FindByID", uint(9)).Return(nil, gorm.ErrRecordNotFound)
+
+	countryID := uint(9)
+	err := service.CreateHub(&entity.Hub{Name: "Test Hub", Location: "Paris", CountryID: &countryID})
+
+	assert.ErrorIs(t, err, ErrCountryNotFound)
+	mockRepo.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+// TestListHubs tests that region and country filters are combined
+func TestListHubs(t *testing.T) {
+	mockRepo := new(mocks.HubRepository)
+	service := NewHubService(nil, mockRepo, new(mocks.CountryRepository), nil)
+
+	mockRepo.On("List", repository.ListOptions{
+		Where:  "country_id IN (SELECT id FROM countries WHERE region_id = ?) AND country_id = ?",
+		Args:   []interface{}{uint(1), uint(2)},
+		Limit:  defaultHubListLimit,
+		Offset: 0,
+	}).Return([]entity.Hub{{ID: 1, Name: "Paris Hub"}}, int64(1), nil)
+
+	hubs, total, err := service.ListHubs(HubQuery{RegionID: 1, CountryID: 2})
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	assert.Len(t, hubs, 1)
+	mockRepo.AssertExpectations(t)
+}
+
+// TestFindNearby tests that candidates from the bounding box are filtered by exact distance and sorted
+func TestFindNearby(t *testing.T) {
+	mockRepo := new(mocks.HubRepository)
+	service := NewHubService(nil, mockRepo, new(mocks.CountryRepository), nil)
+
+	coordinates := func(lat, lng float64) (*float64, *float64) { return &lat, &lng }
+	versailles := entity.Hub{ID: 1, Name: "Versailles Hub"}
+	versailles.Latitude, versailles.Longitude = coordinates(48.8049, 2.1204)
+	paris := entity.Hub{ID: 2, Name: "Paris Hub"}
+	paris.Latitude, paris.Longitude = coordinates(48.8566, 2.3522)
+	corner := entity.Hub{ID: 3, Name: "Corner Hub"} // Inside the box but outside the circle
+	corner.Latitude, corner.Longitude = coordinates(49.2, 2.85)
+
+	mockRepo.On("List", mock.MatchedBy(func(opts repository.ListOptions) bool {
+		return opts.Where == "latitude BETWEEN ? AND ? AND longitude BETWEEN ? AND ?" && len(opts.Args) == 4
+	})).Return([]entity.Hub{versailles, paris, corner}, int64(3), nil)
+
+	hubs, err := service.FindNearby(NearbyQuery{Lat: 48.8584, Lng: 2.2945, RadiusKm: 50})
+
+	assert.NoError(t, err)
+	if assert.Len(t, hubs, 2) {
+		assert.Equal(t, "Paris Hub", hubs[0].Name)
+		assert.InDelta(t, 4.2, hubs[0].DistanceKm, 0.1)
+		assert.Equal(t, "Versailles Hub", hubs[1].Name)
+	}
+}
+
+// TestFindNearby_InvalidInput tests the validation of the point and radius
+func TestFindNearby_InvalidInput(t *testing.T) {
+	mockRepo := new(mocks.HubRepository)
+	service := NewHubService(nil, mockRepo, new(mocks.CountryRepository), nil)
+
+	_, err := service.FindNearby(NearbyQuery{Lat: 91, Lng: 0})
+	assert.ErrorIs(t, err, ErrInvalidCoordinates)
+	_, err = service.FindNearby(NearbyQuery{Lat: 0, Lng: 0, RadiusKm: -1})
+	assert.ErrorIs(t, err, ErrInvalidRadius)
+	mockRepo.AssertNotCalled(t, "List", mock.Anything)
+}
+
+// TestCreateHub_InvalidCoordinates tests that latitude and longitude must be set together
+func TestCreateHub_InvalidCoordinates(t *testing.T) {
+	mockRepo := new(mocks.HubRepository)
+	service := NewHubService(nil, mockRepo, new(mocks.CountryRepository), nil)
+
+	latitude := 48.8566
+	err := service.CreateHub(&entity.Hub{Name: "Paris Hub", Location: "Paris", Latitude: &latitude})
+
+	assert.ErrorIs(t, err, ErrInvalidCoordinates)
+	mockRepo.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+// TestUpdateHub_VersionMismatch tests that an update expecting another version, or losing a race with another
+// update, is rejected
+func TestUpdateHub_VersionMismatch(t *testing.T) {
+	mockRepo := new(mocks.HubRepository)
+	service := NewHubService(nil, mockRepo, new(mocks.CountryRepository), nil)
+
+	mockRepo.On("FindByID", uint(1)).Return(&entity.Hub{ID: 1, Name: "Paris Hub", Location: "Paris", Version: 3}, nil)
+	_, err := service.UpdateHub(1, 2, &entity.Hub{Name: "Paris Central", Location: "Paris"})
+	assert.ErrorIs(t, err, ErrVersionMismatch)
+	mockRepo.AssertNotCalled(t, "Update", mock.Anything)
+
+	mockRepo.On("Update", mock.AnythingOfType("*entity.Hub")).Return(repository.ErrStaleVersion)
+	_, err = service.UpdateHub(1, 3, &entity.Hub{Name: "Paris Central", Location: "Paris"})
+	assert.ErrorIs(t, err, ErrVersionMismatch)
+}
+
+// TestPatchHub tests that merge patches and JSON patches change only what they name and that the result is checked
+// like a full update
+func TestPatchHub(t *testing.T) {
+	mockRepo := new(mocks.HubRepository)
+	service := NewHubService(nil, mockRepo, new(mocks.CountryRepository), nil)
+
+	capacity := 40
+	mockRepo.On("FindByID", uint(1)).Return(func(uint) *entity.Hub {
+		return &entity.Hub{ID: 1, Name: "Paris Hub", Location: "Paris", City: "Paris", Capacity: &capacity, Version: 2}
+	}, nil)
+	mockRepo.On("Update", mock.AnythingOfType("*entity.Hub")).Return(nil)
+
+	hub, err := service.PatchHub(1, 2, MergePatch, []byte(`{"name": "Paris Central", "capacity": null}`))
+	assert.NoError(t, err)
+	assert.Equal(t, "Paris Central", hub.Name)
+	assert.Equal(t, "Paris", hub.City)
+	assert.Nil(t, hub.Capacity)
+
+	hub, err = service.PatchHub(1, AnyVersion, JSONPatch, []byte(`[{"op": "test", "path": "/capacity", "value": 40}, {"op": "add", "path": "/address/street", "value": "1 Rue de Rivoli"}]`))
+	assert.NoError(t, err)
+	assert.Equal(t, "1 Rue de Rivoli", hub.Address.Street)
+	assert.Equal(t, 40, *hub.Capacity)
+
+	for patch, expected := range map[string]error{
+		`[{"op": "test", "path": "/capacity", "value": 50}]`: ErrPatchConflict,
+		`[{"op": "remove", "path": "/latitude"}]`:            ErrPatchConflict,
+		`[{"op": "replace", "path": "/name", "value": "P"}]`: ErrInvalidPatchResult,
+		`[{"op": "remove", "path": "/location"}]`:            ErrInvalidPatchResult,
+		`[{"op": "replace", "path": "/id", "value": 2}]`:     ErrInvalidPatchResult,
+		`[{"op": "add", "path": "/teams", "value": []}]`:     ErrInvalidPatchResult,
+		`{"op": "remove", "path": "/name"}`:                  ErrInvalidPatch,
+	} {
+		_, err := service.PatchHub(1, AnyVersion, JSONPatch, []byte(patch))
+		assert.ErrorIs(t, err, expected, patch)
+	}
+	_, err = service.PatchHub(1, 1, MergePatch, []byte(`{"name": "Paris Central"}`))
+	assert.ErrorIs(t, err, ErrVersionMismatch)
+	mockRepo.AssertNumberOfCalls(t, "Update", 2)
+}
+
+// TestDeleteHub tests that only hubs without teams are deleted
+func TestDeleteHub(t *testing.T) {
+	mockRepo := new(mocks.HubRepository)
+	service := NewHubService(nil, mockRepo, new(mocks.CountryRepository), nil)
+
+	mockRepo.On("FindByID", uint(1)).Return(&entity.Hub{ID: 1, Version: 2}, nil)
+	mockRepo.On("FindByID", uint(2)).Return(&entity.Hub{ID: 2, Version: 1}, nil)
+	mockRepo.On("CountTeams", uint(1)).Return(int64(3), nil)
+	mockRepo.On("CountTeams", uint(2)).Return(int64(0), nil)
+	mockRepo.On("Delete", uint(2), uint(1)).Return(nil)
+
+	assert.ErrorIs(t, service.DeleteHub(1, AnyVersion), ErrHubNotEmpty)
+	assert.ErrorIs(t, service.DeleteHub(2, 2), ErrVersionMismatch)
+	assert.NoError(t, service.DeleteHub(2, 1))
+	mockRepo.AssertNumberOfCalls(t, "Delete", 1)
+}
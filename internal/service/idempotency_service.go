@@ -0,0 +1,159 @@
+package service
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hub_management_service/internal/entity"
+	"hub_management_service/internal/repository"
+	"net/http"
+	"time"
+)
+
+var (
+	ErrInvalidIdempotencyKey    = errors.New("invalid idempotency key")
+	ErrIdempotencyKeyReused     = errors.New("idempotency key was used for another request")
+	ErrIdempotencyKeyInProgress = errors.New("a request with the idempotency key is still in progress")
+)
+
+const (
+	// DefaultIdempotencyKeyTTL is how long responses are replayed when no window is configured
+	DefaultIdempotencyKeyTTL = 24 * time.Hour
+	MaxIdempotencyKeyLength  = 255
+	// IdempotencyLease is how long a key is held for its request before a retry can take it over. A request that runs
+	// longer, such as a large import, may be run again by a retry, and then no longer records its response.
+	IdempotencyLease = 2 * time.Minute
+)
+
+// IdempotentResponse is the response recorded for an idempotency key
+type IdempotentResponse struct {
+	StatusCode int
+	Header     http.Header // Only the headers worth replaying, such as Content-Type and Location
+	Body       []byte
+}
+
+type IdempotencyService interface {
+	Begin(scope, key, fingerprint string) (*IdempotentResponse, string, error)
+	Complete(scope, key, lease string, response *IdempotentResponse) error
+	Abandon(scope, key, lease string) error
+	PurgeExpired() (int64, error)
+}
+
+type idempotencyService struct {
+	repo repository.IdempotencyRepository
+	ttl  time.Duration
+	now  func() time.Time
+}
+
+// NewIdempotencyService creates the idempotency service, responses are replayed for ttl or the default when it is not positive
+func NewIdempotencyService(repo repository.IdempotencyRepository, ttl time.Duration) IdempotencyService {
+	if ttl <= 0 {
+		ttl = DefaultIdempotencyKeyTTL
+	}
+	return &idempotencyService{repo: repo, ttl: ttl, now: time.Now}
+}
+
+// Begin records a request under a key of the scope. When the key was already used for the same request, it returns
+// the recorded response, or ErrIdempotencyKeyInProgress while that request is still being handled. A request that
+// left no response once its lease is over, as when the server crashed, is handled again. A key used for another
+// request fails with ErrIdempotencyKeyReused. Otherwise it returns no response and the token of the lease on the key,
+// and the caller handles the request, then calls Complete or Abandon with the token.
+func (s *idempotencyService) Begin(scope, key, fingerprint string) (*IdempotentResponse, string, error) {
+	if key == "" || len(key) > MaxIdempotencyKeyLength {
+		return nil, "", fmt.Errorf("%w: must be 1 to %d characters", ErrInvalidIdempotencyKey, MaxIdempotencyKeyLength)
+	}
+	lease, err := generateLeaseToken()
+	if err != nil {
+		return nil, "", err
+	}
+
+	now := s.now().UTC()
+	record, err := s.repo.Find(scope, key)
+	if err != nil && !repository.IsNotFound(err) {
+		return nil, "", err
+	}
+	if record != nil && record.ExpiresAt.After(now) {
+		return s.replay(record, fingerprint, lease, now)
+	}
+	if record != nil {
+		if err := s.repo.Delete(scope, key); err != nil {
+			return nil, "", err
+		}
+	}
+
+	lockedUntil := now.Add(IdempotencyLease)
+	err = s.repo.Create(&entity.IdempotencyKey{Scope: scope, Key: key, Fingerprint: fingerprint, CreatedAt: now,
+		ExpiresAt: now.Add(s.ttl), LockedUntil: &lockedUntil, LeaseToken: lease})
+	if errors.Is(err, repository.ErrIdempotencyKeyTaken) {
+		// Another request with the key was recorded in between
+		return nil, "", ErrIdempotencyKeyInProgress
+	}
+	if err != nil {
+		return nil, "", err
+	}
+	return nil, lease, nil
+}
+
+// replay returns the response recorded for the same request, or takes over the key of a request that left none
+// when its lease is over
+func (s *idempotencyService) replay(record *entity.IdempotencyKey, fingerprint, lease string, now time.Time) (*IdempotentResponse, string, error) {
+	if record.Fingerprint != fingerprint {
+		return nil, "", ErrIdempotencyKeyReused
+	}
+	if record.StatusCode == 0 {
+		if record.LockedUntil != nil && record.LockedUntil.After(now) {
+			return nil, "", ErrIdempotencyKeyInProgress
+		}
+		lockedUntil := now.Add(IdempotencyLease)
+		err := s.repo.TakeOver(&entity.IdempotencyKey{Scope: record.Scope, Key: record.Key, LockedUntil: &lockedUntil,
+			LeaseToken: lease}, now)
+		if errors.Is(err, repository.ErrIdempotencyKeyTaken) {
+			// Another retry took the key over in between
+			return nil, "", ErrIdempotencyKeyInProgress
+		}
+		if err != nil {
+			return nil, "", err
+		}
+		return nil, lease, nil
+	}
+	response := &IdempotentResponse{StatusCode: record.StatusCode, Header: http.Header{}, Body: record.Body}
+	if record.Header != "" {
+		if err := json.Unmarshal([]byte(record.Header), &response.Header); err != nil {
+			return nil, "", err
+		}
+	}
+	return response, "", nil
+}
+
+// Complete records the response to the request of a key, to be replayed on retries. It fails with
+// repository.ErrIdempotencyLeaseLost when a retry took the key over meanwhile.
+func (s *idempotencyService) Complete(scope, key, lease string, response *IdempotentResponse) error {
+	header, err := json.Marshal(response.Header)
+	if err != nil {
+		return err
+	}
+	return s.repo.SaveResponse(&entity.IdempotencyKey{Scope: scope, Key: key, LeaseToken: lease,
+		StatusCode: response.StatusCode, Header: string(header), Body: response.Body})
+}
+
+// Abandon forgets a key whose request failed on the server side, so that a retry runs it again. It fails with
+// repository.ErrIdempotencyLeaseLost when a retry took the key over meanwhile, leaving the key to the retry.
+func (s *idempotencyService) Abandon(scope, key, lease string) error {
+	return s.repo.Release(&entity.IdempotencyKey{Scope: scope, Key: key, LeaseToken: lease})
+}
+
+// PurgeExpired deletes the keys whose window is over
+func (s *idempotencyService) PurgeExpired() (int64, error) {
+	return s.repo.DeleteExpired(s.now().UTC())
+}
+
+// generateLeaseToken returns a random token identifying the request holding the lease of a key
+func generateLeaseToken() (string, error) {
+	raw := make([]byte, 16)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(raw), nil
+}
@@ -0,0 +1,139 @@
+package service
+
+import (
+	"gorm.io/gorm"
+	"hub_management_service/internal/entity"
+	"hub_management_service/internal/repository"
+	"hub_management_service/internal/repository/mocks"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// newTestIdempotencyService keeps keys for an hour, with the clock on 15 July 2030 at noon UTC
+func newTestIdempotencyService() (*idempotencyService, *mocks.IdempotencyRepository) {
+	repo := new(mocks.IdempotencyRepository)
+	service := NewIdempotencyService(repo, time.Hour).(*idempotencyService)
+	service.now = func() time.Time { return time.Date(2030, time.July, 15, 12, 0, 0, 0, time.UTC) }
+	return service, repo
+}
+
+// TestBeginIdempotentRequest tests that a new key is recorded for the window
+func TestBeginIdempotentRequest(t *testing.T) {
+	service, repo := newTestIdempotencyService()
+	repo.On("Find", "admin", "key-1").Return(nil, gorm.ErrRecordNotFound)
+	repo.On("Create", mock.MatchedBy(func(record *entity.IdempotencyKey) bool {
+		return record.Fingerprint == "abc" && record.StatusCode == 0 && len(record.LeaseToken) == 32 &&
+			record.ExpiresAt.Equal(time.Date(2030, time.July, 15, 13, 0, 0, 0, time.UTC))
+	})).Return(nil)
+
+	response, lease, err := service.Begin("admin", "key-1", "abc")
+	assert.NoError(t, err)
+	assert.Nil(t, response)
+	assert.Equal(t, repo.Calls[1].Arguments.Get(0).(*entity.IdempotencyKey).LeaseToken, lease)
+	repo.AssertExpectations(t)
+
+	_, _, err = service.Begin("admin", strings.Repeat("k", MaxIdempotencyKeyLength+1), "abc")
+	assert.ErrorIs(t, err, ErrInvalidIdempotencyKey)
+}
+
+// TestBeginIdempotentRequest_Replay tests that a completed request is replayed to the same request only
+func TestBeginIdempotentRequest_Replay(t *testing.T) {
+	service, repo := newTestIdempotencyService()
+	repo.On("Find", "admin", "key-1").Return(&entity.IdempotencyKey{
+		Scope: "admin", Key: "key-1", Fingerprint: "abc", StatusCode: http.StatusCreated,
+		Header:    `{"Location":["/scim/v2/Users/7"]}`,
+		Body:      []byte(`{"id":"7"}`),
+		ExpiresAt: time.Date(2030, time.July, 15, 12, 30, 0, 0, time.UTC),
+	}, nil)
+
+	response, lease, err := service.Begin("admin", "key-1", "abc")
+	assert.NoError(t, err)
+	assert.Empty(t, lease)
+	if assert.NotNil(t, response) {
+		assert.Equal(t, http.StatusCreated, response.StatusCode)
+		assert.Equal(t, "/scim/v2/Users/7", response.Header.Get("Location"))
+		assert.Equal(t, `{"id":"7"}`, string(response.Body))
+	}
+
+	_, _, err = service.Begin("admin", "key-1", "def")
+	assert.ErrorIs(t, err, ErrIdempotencyKeyReused)
+	repo.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+// TestBeginIdempotentRequest_InProgress tests that a retry racing the first request is rejected
+func TestBeginIdempotentRequest_InProgress(t *testing.T) {
+	service, repo := newTestIdempotencyService()
+	lockedUntil := time.Date(2030, time.July, 15, 12, 1, 0, 0, time.UTC)
+	repo.On("Find", "admin", "key-1").Return(&entity.IdempotencyKey{
+		Scope: "admin", Key: "key-1", Fingerprint: "abc", ExpiresAt: time.Date(2030, time.July, 15, 12, 30, 0, 0, time.UTC),
+		LockedUntil: &lockedUntil,
+	}, nil)
+	repo.On("Find", "admin", "key-2").Return(nil, gorm.ErrRecordNotFound)
+	repo.On("Create", mock.Anything).Return(repository.ErrIdempotencyKeyTaken)
+
+	_, _, err := service.Begin("admin", "key-1", "abc")
+	assert.ErrorIs(t, err, ErrIdempotencyKeyInProgress)
+	_, _, err = service.Begin("admin", "key-2", "abc")
+	assert.ErrorIs(t, err, ErrIdempotencyKeyInProgress)
+	repo.AssertNotCalled(t, "TakeOver", mock.Anything, mock.Anything)
+}
+
+// TestBeginIdempotentRequest_LeaseOver tests that a retry takes over the key of a request that left no response once
+// its lease is over, and that only one retry does
+func TestBeginIdempotentRequest_LeaseOver(t *testing.T) {
+	service, repo := newTestIdempotencyService()
+	lockedUntil := time.Date(2030, time.July, 15, 11, 59, 0, 0, time.UTC)
+	repo.On("Find", "admin", "key-1").Return(&entity.IdempotencyKey{
+		Scope: "admin", Key: "key-1", Fingerprint: "abc", ExpiresAt: time.Date(2030, time.July, 15, 12, 30, 0, 0, time.UTC),
+		LockedUntil: &lockedUntil,
+	}, nil)
+	repo.On("TakeOver", mock.MatchedBy(func(record *entity.IdempotencyKey) bool {
+		return record.Key == "key-1" && record.LockedUntil.Equal(time.Date(2030, time.July, 15, 12, 2, 0, 0, time.UTC)) &&
+			len(record.LeaseToken) == 32
+	}), time.Date(2030, time.July, 15, 12, 0, 0, 0, time.UTC)).Return(nil).Once()
+	repo.On("TakeOver", mock.Anything, mock.Anything).Return(repository.ErrIdempotencyKeyTaken).Once()
+
+	response, lease, err := service.Begin("admin", "key-1", "abc")
+	assert.NoError(t, err)
+	assert.Nil(t, response)
+	assert.Equal(t, repo.Calls[1].Arguments.Get(0).(*entity.IdempotencyKey).LeaseToken, lease)
+	_, _, err = service.Begin("admin", "key-1", "abc")
+	assert.ErrorIs(t, err, ErrIdempotencyKeyInProgress)
+	repo.AssertExpectations(t)
+}
+
+// TestBeginIdempotentRequest_Expired tests that an expired key is reused for a new request
+func TestBeginIdempotentRequest_Expired(t *testing.T) {
+	service, repo := newTestIdempotencyService()
+	repo.On("Find", "admin", "key-1").Return(&entity.IdempotencyKey{
+		Scope: "admin", Key: "key-1", Fingerprint: "abc", StatusCode: http.StatusOK,
+		ExpiresAt: time.Date(2030, time.July, 15, 11, 0, 0, 0, time.UTC),
+	}, nil)
+	repo.On("Delete", "admin", "key-1").Return(nil)
+	repo.On("Create", mock.MatchedBy(func(record *entity.IdempotencyKey) bool { return record.Fingerprint == "def" })).Return(nil)
+
+	response, lease, err := service.Begin("admin", "key-1", "def")
+	assert.NoError(t, err)
+	assert.Nil(t, response)
+	assert.NotEmpty(t, lease)
+	repo.AssertExpectations(t)
+}
+
+// TestCompleteIdempotentRequest tests that the response and its release are recorded under the lease of the request
+func TestCompleteIdempotentRequest(t *testing.T) {
+	service, repo := newTestIdempotencyService()
+	repo.On("SaveResponse", mock.MatchedBy(func(record *entity.IdempotencyKey) bool {
+		return record.Key == "key-1" && record.LeaseToken == "lease-1" && record.StatusCode == http.StatusCreated
+	})).Return(repository.ErrIdempotencyLeaseLost)
+	repo.On("Release", &entity.IdempotencyKey{Scope: "admin", Key: "key-2", LeaseToken: "lease-2"}).Return(nil)
+
+	err := service.Complete("admin", "key-1", "lease-1", &IdempotentResponse{StatusCode: http.StatusCreated})
+	assert.ErrorIs(t, err, repository.ErrIdempotencyLeaseLost)
+	assert.NoError(t, service.Abandon("admin", "key-2", "lease-2"))
+	repo.AssertExpectations(t)
+}
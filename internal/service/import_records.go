@@ -0,0 +1,189 @@
+package service
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// importRecord is a row of an import file, its values are keyed by column
+type importRecord struct {
+	Line   int // Line of the row in the file, for error reports
+	Fields map[string]string
+}
+
+// importAliases lets files use the JSON names of reference fields, which only take IDs there
+var importAliases = map[string]string{
+	"country_id": "country",
+	"hub_id":     "hub",
+	"parent_id":  "parent",
+	"team_id":    "team",
+	"manager_id": "manager",
+}
+
+// maxImportLine bounds the length of a JSON Lines row
+const maxImportLine = 1 << 20
+
+// readImportRecords reads the rows of a CSV or JSON Lines file and checks their columns against the known ones
+func readImportRecords(r io.Reader, format ImportFormat, columns []string) ([]importRecord, error) {
+	var records []importRecord
+	var err error
+	switch format {
+	case ImportCSV:
+		records, err = readCSVRecords(r)
+	case ImportJSONL:
+		records, err = readJSONLRecords(r)
+	default:
+		return nil, fmt.Errorf("%w: unknown format %q, expected csv or jsonl", ErrInvalidImport, format)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("%w: the file has no rows", ErrInvalidImport)
+	}
+	if len(records) > MaxImportRows {
+		return nil, fmt.Errorf("%w: the file has %d rows, at most %d are allowed", ErrInvalidImport, len(records), MaxImportRows)
+	}
+
+	known := make(map[string]bool, len(columns))
+	for _, column := range columns {
+		known[column] = true
+	}
+	for _, record := range records {
+		for column := range record.Fields {
+			if !known[column] {
+				return nil, fmt.Errorf("%w: line %d: unknown column %q, expected %s", ErrInvalidImport, record.Line, column, strings.Join(columns, ", "))
+			}
+		}
+	}
+	return records, nil
+}
+
+// readCSVRecords reads a CSV file whose first row names the columns
+func readCSVRecords(r io.Reader) ([]importRecord, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidImport, err)
+	}
+	seen := make(map[string]bool, len(header))
+	for i, name := range header {
+		if i == 0 {
+			name = strings.TrimPrefix(name, "\ufeff") // Spreadsheets save a byte order mark
+		}
+		header[i] = importColumn(name)
+		if seen[header[i]] {
+			return nil, fmt.Errorf("%w: line 1: duplicate column %q", ErrInvalidImport, header[i])
+		}
+		seen[header[i]] = true
+	}
+
+	var records []importRecord
+	for {
+		row, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return records, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidImport, err)
+		}
+		line, _ := reader.FieldPos(0)
+		record := importRecord{Line: line, Fields: make(map[string]string, len(row))}
+		for i, value := range row {
+			if value = strings.TrimSpace(value); value != "" {
+				record.Fields[header[i]] = value
+			}
+		}
+		records = append(records, record)
+	}
+}
+
+// readJSONLRecords reads a JSON Lines file of flat objects, blank lines are skipped
+func readJSONLRecords(r io.Reader) ([]importRecord, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), maxImportLine)
+	var records []importRecord
+	for line := 1; scanner.Scan(); line++ {
+		text := bytes.TrimSpace(scanner.Bytes())
+		if len(text) == 0 {
+			continue
+		}
+		decoder := json.NewDecoder(bytes.NewReader(text))
+		decoder.UseNumber()
+		var object map[string]interface{}
+		if err := decoder.Decode(&object); err != nil || object == nil {
+			return nil, fmt.Errorf("%w: line %d: expected a JSON object", ErrInvalidImport, line)
+		}
+
+		record := importRecord{Line: line, Fields: make(map[string]string, len(object))}
+		for key, value := range object {
+			var text string
+			switch value := value.(type) {
+			case nil:
+				continue
+			case string:
+				text = strings.TrimSpace(value)
+			case json.Number:
+				text = value.String()
+			case bool:
+				text = fmt.Sprint(value)
+			default:
+				return nil, fmt.Errorf("%w: line %d: %q must be a string, number or boolean", ErrInvalidImport, line, key)
+			}
+			if text != "" {
+				record.Fields[importColumn(key)] = text
+			}
+		}
+		records = append(records, record)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidImport, err)
+	}
+	return records, nil
+}
+
+// importColumn normalises a column name and resolves aliases
+func importColumn(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if alias, ok := importAliases[name]; ok {
+		return alias
+	}
+	return name
+}
+
+// importFieldError is an invalid value in a column of a row
+type importFieldError struct {
+	Field   string
+	Message string
+}
+
+// importFieldErrors collects the invalid values of a row
+type importFieldErrors []importFieldError
+
+func (e importFieldErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, fieldErr := range e {
+		messages[i] = fieldErr.Field + " " + fieldErr.Message
+	}
+	return strings.Join(messages, "; ")
+}
+
+// has reports whether a column already has an error
+func (e importFieldErrors) has(field string) bool {
+	for _, fieldErr := range e {
+		if fieldErr.Field == field {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,473 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"hub_management_service/internal/entity"
+	"hub_management_service/internal/repository"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+var ErrInvalidImport = errors.New("invalid import file")
+
+// MaxImportRows bounds the rows of a single import
+const MaxImportRows = 10000
+
+// ImportKind is the type of record created by an import
+type ImportKind string
+
+const (
+	ImportHubs  ImportKind = "hubs"
+	ImportTeams ImportKind = "teams"
+	ImportUsers ImportKind = "users"
+)
+
+// ImportFormat is the file format of an import
+type ImportFormat string
+
+const (
+	ImportCSV   ImportFormat = "csv"
+	ImportJSONL ImportFormat = "jsonl"
+)
+
+// importColumns lists the columns accepted for each kind. References to hubs, teams and managers take an ID
+// or a name (an email for managers), countries take an ID or an ISO code.
+var importColumns = map[ImportKind][]string{
+	ImportHubs: {"name", "location", "city", "country", "time_zone", "capacity", "latitude", "longitude",
+		"address_street", "address_street2", "address_postal_code", "address_state"},
+	ImportTeams: {"name", "hub", "parent"},
+	ImportUsers: {"name", "email", "team", "job_title", "phone", "employee_number", "start_date", "avatar_url",
+		"locale", "time_zone", "employment_type", "manager"},
+}
+
+// ImportOptions controls how an import is checked and committed
+type ImportOptions struct {
+	Format ImportFormat
+	DryRun bool // Check every row and report the errors without keeping anything
+	WriteOptions
+}
+
+// ImportRowError is the reason a row of an import was rejected
+type ImportRowError struct {
+	Line  int    `json:"line"`
+	Field string `json:"field,omitempty"`
+	Error string `json:"error"`
+}
+
+// ImportReport is the outcome of an import. Rows are only kept when every row is valid and it is not a dry run.
+type ImportReport struct {
+	Kind      ImportKind       `json:"kind"`
+	DryRun    bool             `json:"dry_run"`
+	Rows      int              `json:"rows"`
+	Valid     int              `json:"valid"` // Rows that were created, or would be on their own
+	Committed bool             `json:"committed"`
+	Errors    []ImportRowError `json:"errors"`
+}
+
+type ImportService interface {
+	Import(kind ImportKind, r io.Reader, opts ImportOptions) (*ImportReport, error)
+}
+
+// errImportRollback aborts the import transaction after a dry run or when rows failed
+var errImportRollback = errors.New("import rolled back")
+
+// importValidator applies the binding rules of the request bodies and names fields after their JSON names
+var importValidator = newImportValidator()
+
+type importService struct {
+	transactor repository.Transactor
+	capacity   *CapacityPolicy // Threshold applied to imported users, nil for no limits
+}
+
+func NewImportService(transactor repository.Transactor, capacity *CapacityPolicy) ImportService {
+	return &importService{transactor: transactor, capacity: capacity}
+}
+
+// Import creates the hubs, teams or users of a CSV or JSON Lines file in a single transaction. Every row is checked
+// with the same rules as the API, rows may refer to rows above them, and nothing is kept unless all rows are valid.
+func (s *importService) Import(kind ImportKind, r io.Reader, opts ImportOptions) (*ImportReport, error) {
+	columns, ok := importColumns[kind]
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown kind %q, expected hubs, teams or users", ErrInvalidImport, kind)
+	}
+	records, err := readImportRecords(r, opts.Format, columns)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &ImportReport{Kind: kind, DryRun: opts.DryRun, Rows: len(records), Errors: []ImportRowError{}}
+	err = s.transactor.Transaction(func(repos repository.Repositories) error {
+		im := s.newImporter(repos, opts.WriteOptions)
+		for i, record := range records {
+			// A savepoint per row keeps the transaction usable after a row fails
+			err := repos.Savepoint(fmt.Sprintf("import_row_%d", i+1), func() error {
+				return im.importRecord(kind, record)
+			})
+			if errors.Is(err, repository.ErrSavepoint) {
+				return err
+			}
+			if err != nil {
+				report.Errors = append(report.Errors, importRowErrors(record.Line, err)...)
+				continue
+			}
+			report.Valid++
+		}
+		if len(report.Errors) > 0 || opts.DryRun {
+			return errImportRollback
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errImportRollback) {
+		return nil, err
+	}
+	report.Committed = err == nil
+	return report, nil
+}
+
+// importer creates records through the services, bound to the repositories of the import transaction
+type importer struct {
+	repos repository.Repositories
+	hubs  HubService
+	teams TeamService
+	users UserService
+	opts  WriteOptions
+}
+
+func (s *importService) newImporter(repos repository.Repositories, opts WriteOptions) *importer {
+	capacity := s.capacity.bind(repos)
+	return &importer{
+		repos: repos,
+		hubs:  NewHubService(repos, repos.Hubs, repos.Countries, capacity),
+		teams: NewTeamService(repos, repos.Teams, repos.Hubs),
+		users: NewUserService(repos, repos.Users, repos.Teams, capacity),
+		opts:  opts,
+	}
+}
+
+func (im *importer) importRecord(kind ImportKind, record importRecord) error {
+	fields := &importFields{values: record.Fields}
+	switch kind {
+	case ImportHubs:
+		return im.importHub(fields)
+	case ImportTeams:
+		return im.importTeam(fields)
+	default:
+		return im.importUser(fields)
+	}
+}
+
+func (im *importer) importHub(f *importFields) error {
+	hub := &entity.Hub{
+		Name:      f.text("name"),
+		Location:  f.text("location"),
+		City:      f.text("city"),
+		TimeZone:  f.text("time_zone"),
+		Capacity:  f.integer("capacity"),
+		Latitude:  f.float("latitude"),
+		Longitude: f.float("longitude"),
+		Address: entity.Address{
+			Street:     f.text("address_street"),
+			Street2:    f.text("address_street2"),
+			PostalCode: f.text("address_postal_code"),
+			State:      f.text("address_state"),
+		},
+	}
+	if ref := f.text("country"); ref != "" {
+		countryID, err := im.findCountry(ref)
+		f.check("country", err)
+		hub.CountryID = countryID
+	}
+	if err := f.validate(hub); err != nil {
+		return err
+	}
+	return im.hubs.CreateHub(hub)
+}
+
+func (im *importer) importTeam(f *importFields) error {
+	team := &entity.Team{Name: f.text("name")}
+	if ref := f.text("hub"); ref != "" {
+		hubID, err := im.findHub(ref)
+		f.check("hub", err)
+		team.HubID = hubID
+	}
+	if ref := f.text("parent"); ref != "" && team.HubID != 0 {
+		parentID, err := im.findParent(ref, team.HubID)
+		f.check("parent", err)
+		team.ParentID = parentID
+	}
+	if err := f.validate(team); err != nil {
+		return err
+	}
+	return im.teams.CreateTeam(team)
+}
+
+func (im *importer) importUser(f *importFields) error {
+	user := &entity.User{
+		Name:           f.text("name"),
+		Email:          f.text("email"),
+		JobTitle:       f.text("job_title"),
+		Phone:          f.text("phone"),
+		EmployeeNumber: f.optional("employee_number"),
+		StartDate:      f.date("start_date"),
+		AvatarURL:      f.text("avatar_url"),
+		Locale:         f.text("locale"),
+		TimeZone:       f.text("time_zone"),
+		EmploymentType: f.text("employment_type"),
+	}
+	if ref := f.text("team"); ref != "" {
+		teamID, err := im.findTeam(ref)
+		f.check("team", err)
+		user.TeamID = teamID
+	}
+	if ref := f.text("manager"); ref != "" {
+		managerID, err := im.findManager(ref)
+		f.check("manager", err)
+		user.ManagerID = managerID
+	}
+	if err := f.validate(user); err != nil {
+		return err
+	}
+	return im.users.CreateUser(user, im.opts)
+}
+
+// findCountry resolves a country ID or ISO code, IDs are checked when the hub is created
+func (im *importer) findCountry(ref string) (*uint, error) {
+	if id, ok := parseImportID(ref); ok {
+		return &id, nil
+	}
+	country, err := im.repos.Countries.FindByCode(strings.ToUpper(ref))
+	if err != nil {
+		return nil, err
+	}
+	if country == nil {
+		return nil, ErrCountryNotFound
+	}
+	return &country.ID, nil
+}
+
+// findHub resolves a hub ID or name
+func (im *importer) findHub(ref string) (uint, error) {
+	if id, ok := parseImportID(ref); ok {
+		hub, err := im.repos.Hubs.FindByID(id)
+		if repository.IsNotFound(err) || (err == nil && hub == nil) {
+			return 0, ErrHubNotFound
+		}
+		return id, err
+	}
+	hubs, _, err := im.repos.Hubs.List(repository.ListOptions{Where: "LOWER(name) = LOWER(?)", Args: []interface{}{ref}})
+	if err != nil {
+		return 0, err
+	}
+	switch len(hubs) {
+	case 0:
+		return 0, ErrHubNotFound
+	case 1:
+		return hubs[0].ID, nil
+	default:
+		return 0, fmt.Errorf("%d hubs are named %q, use an ID", len(hubs), ref)
+	}
+}
+
+// findParent resolves a parent team ID or the name of a team in the same hub, IDs are checked when the team is created
+func (im *importer) findParent(ref string, hubID uint) (*uint, error) {
+	if id, ok := parseImportID(ref); ok {
+		return &id, nil
+	}
+	teams, _, err := im.repos.Teams.List(repository.ListOptions{Where: "LOWER(name) = LOWER(?) AND hub_id = ?", Args: []interface{}{ref, hubID}})
+	if err != nil {
+		return nil, err
+	}
+	switch len(teams) {
+	case 0:
+		return nil, ErrParentTeamNotFound
+	case 1:
+		return &teams[0].ID, nil
+	default:
+		return nil, fmt.Errorf("%d teams of the hub are named %q, use an ID", len(teams), ref)
+	}
+}
+
+// findTeam resolves a team ID or name
+func (im *importer) findTeam(ref string) (uint, error) {
+	if id, ok := parseImportID(ref); ok {
+		team, err := im.repos.Teams.FindByID(id)
+		if repository.IsNotFound(err) || (err == nil && team == nil) {
+			return 0, ErrTeamNotFound
+		}
+		return id, err
+	}
+	teams, _, err := im.repos.Teams.List(repository.ListOptions{Where: "LOWER(name) = LOWER(?)", Args: []interface{}{ref}})
+	if err != nil {
+		return 0, err
+	}
+	switch len(teams) {
+	case 0:
+		return 0, ErrTeamNotFound
+	case 1:
+		return teams[0].ID, nil
+	default:
+		return 0, fmt.Errorf("%d teams are named %q, use an ID", len(teams), ref)
+	}
+}
+
+// findManager resolves a manager ID or email, IDs are checked when the user is created
+func (im *importer) findManager(ref string) (*uint, error) {
+	if id, ok := parseImportID(ref); ok {
+		return &id, nil
+	}
+	email, err := NormalizeEmail(ref)
+	if err != nil {
+		return nil, err
+	}
+	manager, err := im.repos.Users.FindByEmail(email)
+	if err != nil {
+		return nil, err
+	}
+	if manager == nil {
+		return nil, ErrManagerNotFound
+	}
+	return &manager.ID, nil
+}
+
+func parseImportID(ref string) (uint, bool) {
+	id, err := strconv.ParseUint(ref, 10, 64)
+	if err != nil || id == 0 {
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// importFields reads typed values from the columns of a row and collects the invalid ones
+type importFields struct {
+	values map[string]string
+	errs   importFieldErrors
+}
+
+func (f *importFields) text(name string) string {
+	return f.values[name]
+}
+
+// optional returns nil for an empty column
+func (f *importFields) optional(name string) *string {
+	if value, ok := f.values[name]; ok {
+		return &value
+	}
+	return nil
+}
+
+func (f *importFields) integer(name string) *int {
+	value, ok := f.values[name]
+	if !ok {
+		return nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		f.fail(name, "must be an integer")
+		return nil
+	}
+	return &n
+}
+
+func (f *importFields) float(name string) *float64 {
+	value, ok := f.values[name]
+	if !ok {
+		return nil
+	}
+	n, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		f.fail(name, "must be a number")
+		return nil
+	}
+	return &n
+}
+
+func (f *importFields) date(name string) *entity.Date {
+	value, ok := f.values[name]
+	if !ok {
+		return nil
+	}
+	date, err := entity.ParseDate(value)
+	if err != nil {
+		f.fail(name, "must be a date in YYYY-MM-DD format")
+		return nil
+	}
+	return &date
+}
+
+func (f *importFields) check(name string, err error) {
+	if err != nil {
+		f.fail(name, err.Error())
+	}
+}
+
+func (f *importFields) fail(name, message string) {
+	f.errs = append(f.errs, importFieldError{Field: name, Message: message})
+}
+
+// validate applies the binding rules to a record, reporting columns that already failed only once
+func (f *importFields) validate(record interface{}) error {
+	var validationErrs validator.ValidationErrors
+	if err := importValidator.Struct(record); errors.As(err, &validationErrs) {
+		for _, fieldErr := range validationErrs {
+			name := importFieldName(fieldErr.Namespace())
+			if f.errs.has(name) {
+				continue
+			}
+			f.fail(name, importRuleMessage(fieldErr))
+		}
+	} else if err != nil {
+		return err
+	}
+	if len(f.errs) > 0 {
+		return f.errs
+	}
+	return nil
+}
+
+func newImportValidator() *validator.Validate {
+	v := validator.New()
+	v.SetTagName("binding")
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+		if name == "-" {
+			return ""
+		}
+		return name
+	})
+	return v
+}
+
+// importFieldName turns a namespace such as Hub.address.street into the column address_street
+func importFieldName(namespace string) string {
+	parts := strings.Split(namespace, ".")
+	return importColumn(strings.Join(parts[1:], "_"))
+}
+
+func importRuleMessage(fieldErr validator.FieldError) string {
+	switch {
+	case fieldErr.Tag() == "required":
+		return "is required"
+	case fieldErr.Param() != "":
+		return fmt.Sprintf("must satisfy %s=%s", fieldErr.Tag(), fieldErr.Param())
+	default:
+		return "must satisfy " + fieldErr.Tag()
+	}
+}
+
+// importRowErrors reports the errors of a row, one per invalid column when known
+func importRowErrors(line int, err error) []ImportRowError {
+	var fieldErrs importFieldErrors
+	if !errors.As(err, &fieldErrs) {
+		return []ImportRowError{{Line: line, Error: err.Error()}}
+	}
+	rowErrs := make([]ImportRowError, len(fieldErrs))
+	for i, fieldErr := range fieldErrs {
+		rowErrs[i] = ImportRowError{Line: line, Field: fieldErr.Field, Error: fieldErr.Message}
+	}
+	return rowErrs
+}
@@ -0,0 +1,125 @@
+package service
+
+import (
+	"gorm.io/gorm"
+	"hub_management_service/internal/entity"
+	"hub_management_service/internal/repository"
+	"hub_management_service/internal/repository/mocks"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// testTransactor runs services against mocked repositories and records how the transaction ended
+type testTransactor struct {
+	repos repository.Repositories
+	err   error
+}
+
+func (t *testTransactor) Transaction(fn func(repos repository.Repositories) error) error {
+	t.err = fn(t.repos)
+	return t.err
+}
+
+func newTestImportService() (ImportService, *testTransactor) {
+	transactor := &testTransactor{repos: repository.Repositories{
+		Hubs:      new(mocks.HubRepository),
+		Teams:     new(mocks.TeamRepository),
+		Users:     new(mocks.UserRepository),
+		Countries: new(mocks.CountryRepository),
+	}}
+	return NewImportService(transactor, nil), transactor
+}
+
+// TestImportUsers tests that teams are resolved by name or ID and that every invalid row is reported
+func TestImportUsers(t *testing.T) {
+	service, transactor := newTestImportService()
+	teamRepo := transactor.repos.Teams.(*mocks.TeamRepository)
+	userRepo := transactor.repos.Users.(*mocks.UserRepository)
+	teamRepo.On("List", repository.ListOptions{Where: "LOWER(name) = LOWER(?)", Args: []interface{}{"Backend"}}).
+		Return([]entity.Team{{ID: 2, Name: "Backend", HubID: 1}}, int64(1), nil)
+	teamRepo.On("FindByID", uint(2)).Return(&entity.Team{ID: 2, Name: "Backend", HubID: 1}, nil)
+	teamRepo.On("FindByID", uint(42)).Return(nil, gorm.ErrRecordNotFound)
+	userRepo.On("FindByEmail", mock.Anything).Return(nil, nil)
+	userRepo.On("Create", mock.AnythingOfType("*entity.User")).Return(nil)
+
+	file := "name,email,team,start_date\n" +
+		"Jane,Jane@Example.com,Backend,2030-07-01\n" +
+		",john@example.com,Backend,01/07/2030\n" +
+		"Ann,ann@example.com,42,\n"
+	report, err := service.Import(ImportUsers, strings.NewReader(file), ImportOptions{Format: ImportCSV})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, report.Rows)
+	assert.Equal(t, 1, report.Valid)
+	assert.False(t, report.Committed)
+	assert.Equal(t, []ImportRowError{
+		{Line: 3, Field: "start_date", Error: "must be a date in YYYY-MM-DD format"},
+		{Line: 3, Field: "name", Error: "is required"},
+		{Line: 4, Field: "team", Error: ErrTeamNotFound.Error()},
+	}, report.Errors)
+	assert.ErrorIs(t, transactor.err, errImportRollback)
+}
+
+// TestImportHubs tests that a valid JSON Lines file is committed, with countries resolved by code
+func TestImportHubs(t *testing.T) {
+	service, transactor := newTestImportService()
+	countryRepo := transactor.repos.Countries.(*mocks.CountryRepository)
+	hubRepo := transactor.repos.Hubs.(*mocks.HubRepository)
+	countryRepo.On("FindByCode", "FR").Return(&entity.Country{ID: 5, Code: "FR"}, nil)
+	countryRepo.On("FindByID", uint(5)).Return(&entity.Country{ID: 5, Code: "FR"}, nil)
+	hubRepo.On("Create", mock.MatchedBy(func(hub *entity.Hub) bool {
+		return *hub.CountryID == 5 && hub.Address.Street != ""
+	})).Return(nil)
+
+	file := `{"name": "Paris Hub", "location": "Paris", "country": "fr", "capacity": 120, "address_street": "1 Rue de Rivoli"}` + "\n\n" +
+		`{"name": "Lyon Hub", "location": "Lyon", "country_id": 5, "latitude": 45.76, "longitude": 4.83, "address_street": "2 Quai Saint-Antoine"}` + "\n"
+	report, err := service.Import(ImportHubs, strings.NewReader(file), ImportOptions{Format: ImportJSONL})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, report.Valid)
+	assert.Empty(t, report.Errors)
+	assert.True(t, report.Committed)
+	hubRepo.AssertNumberOfCalls(t, "Create", 2)
+}
+
+// TestImportTeams_DryRun tests that a dry run checks every row and keeps nothing
+func TestImportTeams_DryRun(t *testing.T) {
+	service, transactor := newTestImportService()
+	hubRepo := transactor.repos.Hubs.(*mocks.HubRepository)
+	teamRepo := transactor.repos.Teams.(*mocks.TeamRepository)
+	hubRepo.On("List", repository.ListOptions{Where: "LOWER(name) = LOWER(?)", Args: []interface{}{"Paris Hub"}}).
+		Return([]entity.Hub{{ID: 1}}, int64(1), nil)
+	hubRepo.On("FindByID", uint(1)).Return(&entity.Hub{ID: 1}, nil)
+	teamRepo.On("Create", mock.AnythingOfType("*entity.Team")).Return(nil)
+
+	report, err := service.Import(ImportTeams, strings.NewReader("name,hub\nBackend,Paris Hub\nFrontend,1\nQA,1\n"), ImportOptions{Format: ImportCSV, DryRun: true})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, report.Valid)
+	assert.True(t, report.DryRun)
+	assert.False(t, report.Committed)
+	assert.ErrorIs(t, transactor.err, errImportRollback)
+	assert.Len(t, report.Errors, 1) // QA is too short
+	assert.Equal(t, "name", report.Errors[0].Field)
+}
+
+// TestImport_InvalidFile tests that malformed files are rejected before any row is imported
+func TestImport_InvalidFile(t *testing.T) {
+	service, _ := newTestImportService()
+	for name, test := range map[string]struct {
+		kind   ImportKind
+		format ImportFormat
+		file   string
+	}{
+		"unknown kind":   {"regions", ImportCSV, "name\nEurope\n"},
+		"unknown format": {ImportHubs, "xlsx", "name\nParis\n"},
+		"unknown column": {ImportTeams, ImportCSV, "name,hub,colour\nBackend,1,blue\n"},
+		"no rows":        {ImportTeams, ImportCSV, "name,hub\n"},
+		"ragged csv":     {ImportTeams, ImportCSV, "name,hub\nBackend\n"},
+		"not an object":  {ImportTeams, ImportJSONL, "[\"Backend\", 1]\n"},
+		"nested value":   {ImportTeams, ImportJSONL, `{"name": "Backend", "hub": {"id": 1}}`},
+	} {
+		_, err := service.Import(test.kind, strings.NewReader(test.file), ImportOptions{Format: test.format})
+		assert.ErrorIs(t, err, ErrInvalidImport, name)
+	}
+}
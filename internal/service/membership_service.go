@@ -0,0 +1,158 @@
+package service
+
+import (
+	"errors"
+	"hub_management_service/internal/entity"
+	"hub_management_service/internal/repository"
+)
+
+var (
+	ErrInvalidRole           = errors.New("role must be one of lead, member or observer")
+	ErrInvalidMembershipDate = errors.New("membership end date must not be before its start date")
+	ErrAlreadyMember         = errors.New("user is already a member of the team")
+	ErrNotMember             = errors.New("user is not a member of the team")
+	ErrPrimaryMembership     = errors.New("the primary membership cannot be removed or ended, make another team primary first")
+)
+
+var membershipRoles = map[string]bool{
+	entity.RoleLead:     true,
+	entity.RoleMember:   true,
+	entity.RoleObserver: true,
+}
+
+type MembershipService interface {
+	ListMembers(teamID uint) ([]entity.Membership, error)
+	ListUserMemberships(userID uint) ([]entity.Membership, error)
+	AddMember(teamID uint, membership *entity.Membership, opts WriteOptions) error
+	RemoveMember(teamID, userID uint) error
+}
+
+type membershipService struct {
+	tx       repository.Transactor
+	repo     repository.MembershipRepository
+	userRepo repository.UserRepository
+	teamRepo repository.TeamRepository
+	capacity *CapacityPolicy // Limits moves into full hubs, nil for no limits
+}
+
+func NewMembershipService(tx repository.Transactor, repo repository.MembershipRepository, userRepo repository.UserRepository, teamRepo repository.TeamRepository, capacity *CapacityPolicy) MembershipService {
+	return &membershipService{tx: tx, repo: repo, userRepo: userRepo, teamRepo: teamRepo, capacity: capacity}
+}
+
+// bind returns the service working with the repositories of a transaction
+func (s *membershipService) bind(repos repository.Repositories) *membershipService {
+	return &membershipService{tx: repos, repo: repos.Memberships, userRepo: repos.Users, teamRepo: repos.Teams, capacity: s.capacity.bind(repos)}
+}
+
+// ListMembers returns all memberships of a team, including ended ones
+func (s *membershipService) ListMembers(teamID uint) ([]entity.Membership, error) {
+	if _, err := s.findTeam(teamID); err != nil {
+		return nil, err
+	}
+	return s.repo.FindByTeamID(teamID)
+}
+
+// ListUserMemberships returns the memberships of a user, primary first
+func (s *membershipService) ListUserMemberships(userID uint) ([]entity.Membership, error) {
+	if _, err := s.findUser(userID); err != nil {
+		return nil, err
+	}
+	return s.repo.FindByUserID(userID)
+}
+
+// AddMember adds a user to a team. A primary membership also moves the user's TeamID to the team,
+// which counts as a transfer into the team's hub. Both writes happen in one transaction.
+func (s *membershipService) AddMember(teamID uint, membership *entity.Membership, opts WriteOptions) error {
+	return inTransaction(s.tx, s, s.bind, func(s *membershipService) error {
+		return s.addMember(teamID, membership, opts)
+	})
+}
+
+func (s *membershipService) addMember(teamID uint, membership *entity.Membership, opts WriteOptions) error {
+	if membership.Role == "" {
+		membership.Role = entity.RoleMember
+	}
+	if !membershipRoles[membership.Role] {
+		return ErrInvalidRole
+	}
+	if membership.StartDate != nil && membership.EndDate != nil && membership.EndDate.Before(membership.StartDate.Time) {
+		return ErrInvalidMembershipDate
+	}
+	if membership.Primary && membership.EndDate != nil {
+		return ErrPrimaryMembership
+	}
+
+	if _, err := s.findTeam(teamID); err != nil {
+		return err
+	}
+	user, err := s.findUser(membership.UserID)
+	if err != nil {
+		return err
+	}
+	// Only a current membership blocks joining, ended ones are history and the user may rejoin
+	existing, err := s.repo.Find(teamID, user.ID)
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		return ErrAlreadyMember
+	}
+	if membership.Primary {
+		if err := s.capacity.CheckRoom(teamID, user.TeamID, opts); err != nil {
+			return err
+		}
+	}
+
+	// The primary flag is owned by the user repository, which keeps it in line with User.TeamID
+	primary := membership.Primary
+	membership.TeamID = teamID
+	membership.Primary = false
+	if err := s.repo.Create(membership); err != nil {
+		return err
+	}
+	if primary {
+		user.TeamID = teamID
+		if err := s.userRepo.Update(user); err != nil {
+			return err
+		}
+		membership.Primary = true
+	}
+	return nil
+}
+
+// RemoveMember removes a user from a team, the primary membership can only change by making another team primary
+func (s *membershipService) RemoveMember(teamID, userID uint) error {
+	return inTransaction(s.tx, s, s.bind, func(s *membershipService) error {
+		return s.removeMember(teamID, userID)
+	})
+}
+
+func (s *membershipService) removeMember(teamID, userID uint) error {
+	membership, err := s.repo.Find(teamID, userID)
+	if err != nil {
+		return err
+	}
+	if membership == nil {
+		return ErrNotMember
+	}
+	if membership.Primary {
+		return ErrPrimaryMembership
+	}
+	return s.repo.Delete(membership.ID)
+}
+
+func (s *membershipService) findTeam(id uint) (*entity.Team, error) {
+	team, err := s.teamRepo.FindByID(id)
+	if repository.IsNotFound(err) || (err == nil && team == nil) {
+		return nil, ErrTeamNotFound
+	}
+	return team, err
+}
+
+func (s *membershipService) findUser(id uint) (*entity.User, error) {
+	user, err := s.userRepo.FindByID(id)
+	if repository.IsNotFound(err) || (err == nil && user == nil) {
+		return nil, ErrUserNotFound
+	}
+	return user, err
+}
@@ -0,0 +1,88 @@
+package service
+
+import (
+	"hub_management_service/internal/entity"
+	"hub_management_service/internal/repository/mocks"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func newTestMembershipService() (MembershipService, *mocks.MembershipRepository, *mocks.UserRepository, *mocks.TeamRepository) {
+	mockRepo := new(mocks.MembershipRepository)
+	mockUserRepo := new(mocks.UserRepository)
+	mockTeamRepo := new(mocks.TeamRepository)
+	return NewMembershipService(nil, mockRepo, mockUserRepo, mockTeamRepo, nil), mockRepo, mockUserRepo, mockTeamRepo
+}
+
+// TestAddMember tests that a secondary membership is created with the default role
+func TestAddMember(t *testing.T) {
+	service, mockRepo, mockUserRepo, mockTeamRepo := newTestMembershipService()
+
+	mockTeamRepo.On("FindByID", uint(2)).Return(&entity.Team{ID: 2}, nil)
+	mockUserRepo.On("FindByID", uint(7)).Return(&entity.User{ID: 7, TeamID: 1}, nil)
+	mockRepo.On("Find", uint(2), uint(7)).Return(nil, nil)
+	mockRepo.On("Create", mock.MatchedBy(func(m *entity.Membership) bool {
+		return m.TeamID == 2 && m.UserID == 7 && m.Role == entity.RoleMember && !m.Primary
+	})).Return(nil)
+
+	err := service.AddMember(2, &entity.Membership{UserID: 7}, WriteOptions{})
+
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+	mockUserRepo.AssertNotCalled(t, "Update", mock.Anything)
+}
+
+// TestAddMember_Primary tests that a primary membership moves the user's team
+func TestAddMember_Primary(t *testing.T) {
+	service, mockRepo, mockUserRepo, mockTeamRepo := newTestMembershipService()
+
+	mockTeamRepo.On("FindByID", uint(2)).Return(&entity.Team{ID: 2}, nil)
+	mockUserRepo.On("FindByID", uint(7)).Return(&entity.User{ID: 7, TeamID: 1}, nil)
+	mockRepo.On("Find", uint(2), uint(7)).Return(nil, nil)
+	mockRepo.On("Create", mock.AnythingOfType("*entity.Membership")).Return(nil)
+	mockUserRepo.On("Update", mock.MatchedBy(func(u *entity.User) bool { return u.TeamID == 2 })).Return(nil)
+
+	membership := &entity.Membership{UserID: 7, Role: entity.RoleLead, Primary: true}
+	err := service.AddMember(2, membership, WriteOptions{})
+
+	assert.NoError(t, err)
+	assert.True(t, membership.Primary)
+	mockUserRepo.AssertExpectations(t)
+}
+
+// TestAddMember_Invalid tests the validation of roles, dates and duplicates
+func TestAddMember_Invalid(t *testing.T) {
+	service, mockRepo, mockUserRepo, mockTeamRepo := newTestMembershipService()
+
+	start, end := entity.NewDate(2024, time.June, 1), entity.NewDate(2024, time.January, 1)
+	assert.ErrorIs(t, service.AddMember(2, &entity.Membership{UserID: 7, Role: "owner"}, WriteOptions{}), ErrInvalidRole)
+	assert.ErrorIs(t, service.AddMember(2, &entity.Membership{UserID: 7, StartDate: &start, EndDate: &end}, WriteOptions{}), ErrInvalidMembershipDate)
+
+	mockTeamRepo.On("FindByID", uint(2)).Return(&entity.Team{ID: 2}, nil)
+	mockTeamRepo.On("FindByID", uint(3)).Return(nil, gorm.ErrRecordNotFound)
+	mockUserRepo.On("FindByID", uint(7)).Return(&entity.User{ID: 7, TeamID: 1}, nil)
+	mockRepo.On("Find", uint(2), uint(7)).Return(&entity.Membership{ID: 1}, nil)
+
+	assert.ErrorIs(t, service.AddMember(3, &entity.Membership{UserID: 7}, WriteOptions{}), ErrTeamNotFound)
+	assert.ErrorIs(t, service.AddMember(2, &entity.Membership{UserID: 7}, WriteOptions{}), ErrAlreadyMember)
+	mockRepo.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+// TestRemoveMember tests that only secondary memberships can be removed
+func TestRemoveMember(t *testing.T) {
+	service, mockRepo, _, _ := newTestMembershipService()
+
+	mockRepo.On("Find", uint(1), uint(7)).Return(&entity.Membership{ID: 10, Primary: true}, nil)
+	mockRepo.On("Find", uint(2), uint(7)).Return(&entity.Membership{ID: 11}, nil)
+	mockRepo.On("Find", uint(3), uint(7)).Return(nil, nil)
+	mockRepo.On("Delete", uint(11)).Return(nil)
+
+	assert.ErrorIs(t, service.RemoveMember(1, 7), ErrPrimaryMembership)
+	assert.NoError(t, service.RemoveMember(2, 7))
+	assert.ErrorIs(t, service.RemoveMember(3, 7), ErrNotMember)
+	mockRepo.AssertExpectations(t)
+}
@@ -0,0 +1,233 @@
+package service
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"hub_management_service/internal/entity"
+	"hub_management_service/internal/repository"
+	"hub_management_service/pkg/totp"
+	"strings"
+	"time"
+)
+
+// mfaIssuer is the issuer shown by authenticator apps
+const mfaIssuer = "Hub Management"
+
+// recoveryCodeCount is the number of recovery codes issued on enrollment
+const recoveryCodeCount = 10
+
+// maxMFAFailures invalid codes in a row lock the enrollment for mfaLockout, which bounds guessing of 6 digit codes
+const (
+	maxMFAFailures = 5
+	mfaLockout     = 15 * time.Minute
+)
+
+var (
+	ErrMFANotEnrolled = errors.New("mfa is not enrolled")
+	ErrInvalidMFACode = errors.New("invalid mfa code")
+	ErrMFAEnabled     = errors.New("mfa is already enabled, disable it with a code before enrolling again")
+	ErrMFALocked      = errors.New("too many invalid mfa codes, try again later")
+)
+
+// MFAEnrollmentResult is returned once on enrollment, it is the only time the secret and recovery codes are shown
+type MFAEnrollmentResult struct {
+	Secret          string   `json:"secret"`
+	ProvisioningURI string   `json:"provisioning_uri"`
+	RecoveryCodes   []string `json:"recovery_codes"`
+}
+
+type MFAService interface {
+	Enroll(username string) (*MFAEnrollmentResult, error)
+	Confirm(username, code string) error
+	IsEnabled(username string) (bool, error)
+	Verify(username, code string) error
+	Disable(username, code string) error
+}
+
+type mfaService struct {
+	tx   repository.Transactor
+	repo repository.MFARepository
+	now  func() time.Time
+}
+
+func NewMFAService(tx repository.Transactor, repo repository.MFARepository) MFAService {
+	return &mfaService{tx: tx, repo: repo, now: time.Now}
+}
+
+// bind returns the service working with the repositories of a transaction
+func (s *mfaService) bind(repos repository.Repositories) *mfaService {
+	return &mfaService{tx: repos, repo: repos.MFA, now: s.now}
+}
+
+// Enroll creates a new unconfirmed enrollment, replacing a pending one. A confirmed enrollment is only replaced after
+// Disable, which needs a code, so that a stolen bearer token cannot reset MFA.
+func (s *mfaService) Enroll(username string) (*MFAEnrollmentResult, error) {
+	var result *MFAEnrollmentResult
+	err := inTransaction(s.tx, s, s.bind, func(s *mfaService) (err error) {
+		result, err = s.enroll(username)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (s *mfaService) enroll(username string) (*MFAEnrollmentResult, error) {
+	existing, err := s.repo.FindByUsername(username)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil && existing.Confirmed {
+		return nil, ErrMFAEnabled
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	codes := make([]string, recoveryCodeCount)
+	hashes := make([]entity.RecoveryCode, recoveryCodeCount)
+	for i := range codes {
+		code, err := generateRecoveryCode()
+		if err != nil {
+			return nil, err
+		}
+		codes[i] = code
+		hashes[i] = entity.RecoveryCode{CodeHash: hashRecoveryCode(code)}
+	}
+
+	enrollment := &entity.MFAEnrollment{Username: username, Secret: secret, RecoveryCodes: hashes}
+	if err := s.repo.Save(enrollment); err != nil {
+		return nil, err
+	}
+
+	return &MFAEnrollmentResult{
+		Secret:          secret,
+		ProvisioningURI: totp.ProvisioningURI(mfaIssuer, username, secret),
+		RecoveryCodes:   codes,
+	}, nil
+}
+
+// Confirm activates an enrollment once the user proves their authenticator produces valid codes
+func (s *mfaService) Confirm(username, code string) error {
+	var enrollment *entity.MFAEnrollment
+	err := inTransaction(s.tx, s, s.bind, func(s *mfaService) (err error) {
+		enrollment, err = s.repo.FindByUsername(username)
+		if err != nil {
+			return err
+		}
+		if enrollment == nil {
+			return ErrMFANotEnrolled
+		}
+		if err := s.checkCode(enrollment, code, false); err != nil {
+			return err
+		}
+		return s.repo.Confirm(enrollment.ID)
+	})
+	return s.recordFailure(enrollment, err)
+}
+
+// IsEnabled reports whether the user must complete the MFA step on login
+func (s *mfaService) IsEnabled(username string) (bool, error) {
+	enrollment, err := s.repo.FindByUsername(username)
+	if err != nil {
+		return false, err
+	}
+	return enrollment != nil && enrollment.Confirmed, nil
+}
+
+// Verify accepts either a TOTP code newer than the last accepted one or an unused recovery code
+func (s *mfaService) Verify(username, code string) error {
+	var enrollment *entity.MFAEnrollment
+	err := inTransaction(s.tx, s, s.bind, func(s *mfaService) (err error) {
+		enrollment, err = s.verify(username, code)
+		return err
+	})
+	return s.recordFailure(enrollment, err)
+}
+
+// verify returns the enrollment it checked the code against, so that callers can count an invalid code
+func (s *mfaService) verify(username, code string) (*entity.MFAEnrollment, error) {
+	enrollment, err := s.repo.FindByUsername(username)
+	if err != nil {
+		return nil, err
+	}
+	if enrollment == nil || !enrollment.Confirmed {
+		return nil, ErrMFANotEnrolled
+	}
+	return enrollment, s.checkCode(enrollment, code, true)
+}
+
+// checkCode accepts a TOTP code of a step after the last accepted one, recording its step so that it cannot be
+// replayed, or with allowRecovery an unused recovery code. Locked enrollments accept no code.
+func (s *mfaService) checkCode(enrollment *entity.MFAEnrollment, code string, allowRecovery bool) error {
+	if enrollment.LockedUntil != nil && s.now().Before(*enrollment.LockedUntil) {
+		return ErrMFALocked
+	}
+
+	if step, ok := totp.ValidateStep(enrollment.Secret, code, s.now(), enrollment.LastUsedStep); ok {
+		used, err := s.repo.UseStep(enrollment.ID, step)
+		if err != nil {
+			return err
+		}
+		if !used {
+			// A concurrent request accepted the same code first
+			return ErrInvalidMFACode
+		}
+		return nil
+	}
+	if !allowRecovery {
+		return ErrInvalidMFACode
+	}
+
+	used, err := s.repo.UseRecoveryCode(enrollment.ID, hashRecoveryCode(code))
+	if err != nil {
+		return err
+	}
+	if !used {
+		return ErrInvalidMFACode
+	}
+	return s.repo.ClearFailures(enrollment.ID)
+}
+
+// recordFailure counts an invalid code against the enrollment and locks it after maxMFAFailures. It runs after the
+// transaction, which an invalid code rolls back.
+func (s *mfaService) recordFailure(enrollment *entity.MFAEnrollment, err error) error {
+	if enrollment != nil && errors.Is(err, ErrInvalidMFACode) {
+		if recordErr := s.repo.RecordFailure(enrollment.ID, maxMFAFailures, s.now().Add(mfaLockout)); recordErr != nil {
+			return recordErr
+		}
+	}
+	return err
+}
+
+// Disable removes the enrollment after verifying a code
+func (s *mfaService) Disable(username, code string) error {
+	var enrollment *entity.MFAEnrollment
+	err := inTransaction(s.tx, s, s.bind, func(s *mfaService) (err error) {
+		if enrollment, err = s.verify(username, code); err != nil {
+			return err
+		}
+		return s.repo.Delete(username)
+	})
+	return s.recordFailure(enrollment, err)
+}
+
+// generateRecoveryCode returns a random code formatted as xxxxx-xxxxx
+func generateRecoveryCode() (string, error) {
+	raw := make([]byte, 5)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+	code := hex.EncodeToString(raw)
+	return code[:5] + "-" + code[5:], nil
+}
+
+func hashRecoveryCode(code string) string {
+	sum := sha256.Sum256([]byte(strings.ToLower(strings.TrimSpace(code))))
+	return hex.EncodeToString(sum[:])
+}
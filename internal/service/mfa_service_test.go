@@ -0,0 +1,163 @@
+package service
+
+import (
+	"hub_management_service/internal/entity"
+	"hub_management_service/internal/repository/mocks"
+	"hub_management_service/pkg/totp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const testSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+// TestEnroll tests that enrollment stores hashed recovery codes and returns the plaintext ones
+func TestEnroll(t *testing.T) {
+	mockRepo := new(mocks.MFARepository)
+	service := NewMFAService(nil, mockRepo)
+
+	var saved *entity.MFAEnrollment
+	mockRepo.On("FindByUsername", "admin").Return(&entity.MFAEnrollment{ID: 1, Secret: testSecret}, nil)
+	mockRepo.On("Save", mock.AnythingOfType("*entity.MFAEnrollment")).Run(func(args mock.Arguments) {
+		saved = args.Get(0).(*entity.MFAEnrollment)
+	}).Return(nil)
+
+	result, err := service.Enroll("admin")
+
+	assert.NoError(t, err)
+	assert.Len(t, result.RecoveryCodes, recoveryCodeCount)
+	assert.Contains(t, result.ProvisioningURI, "secret="+result.Secret)
+	assert.Equal(t, result.Secret, saved.Secret)
+	assert.False(t, saved.Confirmed)
+	assert.Equal(t, hashRecoveryCode(result.RecoveryCodes[0]), saved.RecoveryCodes[0].CodeHash)
+	mockRepo.AssertExpectations(t)
+}
+
+// TestEnroll_AlreadyEnabled tests that a confirmed enrollment cannot be replaced without disabling it first
+func TestEnroll_AlreadyEnabled(t *testing.T) {
+	mockRepo := new(mocks.MFARepository)
+	service := NewMFAService(nil, mockRepo)
+
+	mockRepo.On("FindByUsername", "admin").Return(&entity.MFAEnrollment{ID: 1, Secret: testSecret, Confirmed: true}, nil)
+
+	_, err := service.Enroll("admin")
+
+	assert.ErrorIs(t, err, ErrMFAEnabled)
+	mockRepo.AssertNotCalled(t, "Save", mock.Anything)
+}
+
+// TestConfirm_InvalidCode tests that an enrollment is not confirmed with a wrong code
+func TestConfirm_InvalidCode(t *testing.T) {
+	mockRepo := new(mocks.MFARepository)
+	service := NewMFAService(nil, mockRepo)
+
+	mockRepo.On("FindByUsername", "admin").Return(&entity.MFAEnrollment{ID: 1, Secret: testSecret}, nil)
+	mockRepo.On("RecordFailure", uint(1), maxMFAFailures, mock.AnythingOfType("time.Time")).Return(nil)
+
+	err := service.Confirm("admin", "000000")
+
+	assert.ErrorIs(t, err, ErrInvalidMFACode)
+	mockRepo.AssertNotCalled(t, "Confirm", mock.Anything)
+}
+
+// TestConfirm_Success tests that a valid code confirms the enrollment
+func TestConfirm_Success(t *testing.T) {
+	mockRepo := new(mocks.MFARepository)
+	service := NewMFAService(nil, mockRepo)
+
+	mockRepo.On("FindByUsername", "admin").Return(&entity.MFAEnrollment{ID: 1, Secret: testSecret}, nil)
+	mockRepo.On("Confirm", uint(1)).Return(nil)
+
+	now := time.Now()
+	mockRepo.On("UseStep", uint(1), now.Unix()/totp.Period).Return(true, nil)
+	code, _ := totp.GenerateCode(testSecret, now)
+	err := service.Confirm("admin", code)
+
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+// TestIsEnabled tests that only confirmed enrollments require MFA
+func TestIsEnabled(t *testing.T) {
+	mockRepo := new(mocks.MFARepository)
+	service := NewMFAService(nil, mockRepo)
+
+	mockRepo.On("FindByUsername", "pending").Return(&entity.MFAEnrollment{Confirmed: false}, nil)
+	mockRepo.On("FindByUsername", "admin").Return(&entity.MFAEnrollment{Confirmed: true}, nil)
+	mockRepo.On("FindByUsername", "none").Return(nil, nil)
+
+	enabled, _ := service.IsEnabled("pending")
+	assert.False(t, enabled)
+	enabled, _ = service.IsEnabled("admin")
+	assert.True(t, enabled)
+	enabled, _ = service.IsEnabled("none")
+	assert.False(t, enabled)
+}
+
+// TestVerify_RecoveryCode tests that a recovery code is accepted when the TOTP code does not match
+func TestVerify_RecoveryCode(t *testing.T) {
+	mockRepo := new(mocks.MFARepository)
+	service := NewMFAService(nil, mockRepo)
+
+	mockRepo.On("FindByUsername", "admin").Return(&entity.MFAEnrollment{ID: 1, Secret: testSecret, Confirmed: true}, nil)
+	mockRepo.On("UseRecoveryCode", uint(1), hashRecoveryCode("abcde-12345")).Return(true, nil)
+	mockRepo.On("ClearFailures", uint(1)).Return(nil)
+
+	err := service.Verify("admin", "ABCDE-12345")
+
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+// TestVerify_InvalidCode tests that an unknown code is rejected
+func TestVerify_InvalidCode(t *testing.T) {
+	mockRepo := new(mocks.MFARepository)
+	service := NewMFAService(nil, mockRepo)
+
+	mockRepo.On("FindByUsername", "admin").Return(&entity.MFAEnrollment{ID: 1, Secret: testSecret, Confirmed: true}, nil)
+	mockRepo.On("UseRecoveryCode", uint(1), mock.Anything).Return(false, nil)
+	mockRepo.On("RecordFailure", uint(1), maxMFAFailures, mock.AnythingOfType("time.Time")).Return(nil)
+
+	err := service.Verify("admin", "nope")
+
+	assert.ErrorIs(t, err, ErrInvalidMFACode)
+	mockRepo.AssertExpectations(t)
+}
+
+// TestVerify_ReplayedCode tests that a TOTP code of the last accepted step or an earlier one is rejected
+func TestVerify_ReplayedCode(t *testing.T) {
+	mockRepo := new(mocks.MFARepository)
+	service := NewMFAService(nil, mockRepo)
+
+	now := time.Now()
+	step := now.Unix() / totp.Period
+	mockRepo.On("FindByUsername", "admin").
+		Return(&entity.MFAEnrollment{ID: 1, Secret: testSecret, Confirmed: true, LastUsedStep: step}, nil)
+	mockRepo.On("UseRecoveryCode", uint(1), mock.Anything).Return(false, nil)
+	mockRepo.On("RecordFailure", uint(1), maxMFAFailures, mock.AnythingOfType("time.Time")).Return(nil)
+
+	code, _ := totp.GenerateCode(testSecret, now)
+	err := service.Verify("admin", code)
+
+	assert.ErrorIs(t, err, ErrInvalidMFACode)
+	mockRepo.AssertNotCalled(t, "UseStep", mock.Anything, mock.Anything)
+}
+
+// TestVerify_Locked tests that a locked enrollment refuses even a valid code until the lock expires
+func TestVerify_Locked(t *testing.T) {
+	mockRepo := new(mocks.MFARepository)
+	service := NewMFAService(nil, mockRepo)
+
+	lockedUntil := time.Now().Add(time.Minute)
+	mockRepo.On("FindByUsername", "admin").
+		Return(&entity.MFAEnrollment{ID: 1, Secret: testSecret, Confirmed: true, LockedUntil: &lockedUntil}, nil)
+
+	code, _ := totp.GenerateCode(testSecret, time.Now())
+	err := service.Verify("admin", code)
+
+	assert.ErrorIs(t, err, ErrMFALocked)
+	mockRepo.AssertNotCalled(t, "UseStep", mock.Anything, mock.Anything)
+	mockRepo.AssertNotCalled(t, "RecordFailure", mock.Anything, mock.Anything, mock.Anything)
+}
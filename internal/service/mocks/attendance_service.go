@@ -0,0 +1,158 @@
+// Code generated by mockery v2.33.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	entity "hub_management_service/internal/entity"
+
+	mock "github.com/stretchr/testify/mock"
+
+	service "hub_management_service/internal/service"
+)
+
+// AttendanceService is an autogenerated mock type for the AttendanceService type
+type AttendanceService struct {
+	mock.Mock
+}
+
+// CheckIn provides a mock function with given fields: username, hubID
+func (_m *AttendanceService) CheckIn(username string, hubID uint) (*entity.Visit, error) {
+	ret := _m.Called(username, hubID)
+
+	var r0 *entity.Visit
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, uint) (*entity.Visit, error)); ok {
+		return rf(username, hubID)
+	}
+	if rf, ok := ret.Get(0).(func(string, uint) *entity.Visit); ok {
+		r0 = rf(username, hubID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.Visit)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, uint) error); ok {
+		r1 = rf(username, hubID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CheckOut provides a mock function with given fields: username, hubID
+func (_m *AttendanceService) CheckOut(username string, hubID uint) (*entity.Visit, error) {
+	ret := _m.Called(username, hubID)
+
+	var r0 *entity.Visit
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, uint) (*entity.Visit, error)); ok {
+		return rf(username, hubID)
+	}
+	if rf, ok := ret.Get(0).(func(string, uint) *entity.Visit); ok {
+		r0 = rf(username, hubID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.Visit)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, uint) error); ok {
+		r1 = rf(username, hubID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindDailyAttendance provides a mock function with given fields: query
+func (_m *AttendanceService) FindDailyAttendance(query service.AttendanceQuery) ([]entity.DailyAttendance, error) {
+	ret := _m.Called(query)
+
+	var r0 []entity.DailyAttendance
+	var r1 error
+	if rf, ok := ret.Get(0).(func(service.AttendanceQuery) ([]entity.DailyAttendance, error)); ok {
+		return rf(query)
+	}
+	if rf, ok := ret.Get(0).(func(service.AttendanceQuery) []entity.DailyAttendance); ok {
+		r0 = rf(query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.DailyAttendance)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(service.AttendanceQuery) error); ok {
+		r1 = rf(query)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindPresence provides a mock function with given fields: hubID, date
+func (_m *AttendanceService) FindPresence(hubID uint, date entity.Date) (*service.HubPresence, error) {
+	ret := _m.Called(hubID, date)
+
+	var r0 *service.HubPresence
+	var r1 error
+	if rf, ok := ret.Get(0).(func(uint, entity.Date) (*service.HubPresence, error)); ok {
+		return rf(hubID, date)
+	}
+	if rf, ok := ret.Get(0).(func(uint, entity.Date) *service.HubPresence); ok {
+		r0 = rf(hubID, date)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*service.HubPresence)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(uint, entity.Date) error); ok {
+		r1 = rf(hubID, date)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PurgeExpired provides a mock function with given fields:
+func (_m *AttendanceService) PurgeExpired() (int64, error) {
+	ret := _m.Called()
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func() (int64, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() int64); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewAttendanceService creates a new instance of AttendanceService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewAttendanceService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *AttendanceService {
+	mock := &AttendanceService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,54 @@
+// Code generated by mockery v2.33.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	service "hub_management_service/internal/service"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// BatchService is an autogenerated mock type for the BatchService type
+type BatchService struct {
+	mock.Mock
+}
+
+// Execute provides a mock function with given fields: req, opts
+func (_m *BatchService) Execute(req *service.BatchRequest, opts service.WriteOptions) (*service.BatchReport, error) {
+	ret := _m.Called(req, opts)
+
+	var r0 *service.BatchReport
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*service.BatchRequest, service.WriteOptions) (*service.BatchReport, error)); ok {
+		return rf(req, opts)
+	}
+	if rf, ok := ret.Get(0).(func(*service.BatchRequest, service.WriteOptions) *service.BatchReport); ok {
+		r0 = rf(req, opts)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*service.BatchReport)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*service.BatchRequest, service.WriteOptions) error); ok {
+		r1 = rf(req, opts)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewBatchService creates a new instance of BatchService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewBatchService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *BatchService {
+	mock := &BatchService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,150 @@
+// Code generated by mockery v2.33.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	entity "hub_management_service/internal/entity"
+
+	mock "github.com/stretchr/testify/mock"
+
+	service "hub_management_service/internal/service"
+
+	time "time"
+)
+
+// BookingService is an autogenerated mock type for the BookingService type
+type BookingService struct {
+	mock.Mock
+}
+
+// CancelBooking provides a mock function with given fields: account, id
+func (_m *BookingService) CancelBooking(account service.Account, id uint) (*entity.Booking, error) {
+	ret := _m.Called(account, id)
+
+	var r0 *entity.Booking
+	var r1 error
+	if rf, ok := ret.Get(0).(func(service.Account, uint) (*entity.Booking, error)); ok {
+		return rf(account, id)
+	}
+	if rf, ok := ret.Get(0).(func(service.Account, uint) *entity.Booking); ok {
+		r0 = rf(account, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.Booking)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(service.Account, uint) error); ok {
+		r1 = rf(account, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateBooking provides a mock function with given fields: account, resourceID, booking
+func (_m *BookingService) CreateBooking(account service.Account, resourceID uint, booking *entity.Booking) error {
+	ret := _m.Called(account, resourceID, booking)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(service.Account, uint, *entity.Booking) error); ok {
+		r0 = rf(account, resourceID, booking)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// FindAvailability provides a mock function with given fields: hubID, date, query
+func (_m *BookingService) FindAvailability(hubID uint, date entity.Date, query service.ResourceQuery) (*service.Availability, error) {
+	ret := _m.Called(hubID, date, query)
+
+	var r0 *service.Availability
+	var r1 error
+	if rf, ok := ret.Get(0).(func(uint, entity.Date, service.ResourceQuery) (*service.Availability, error)); ok {
+		return rf(hubID, date, query)
+	}
+	if rf, ok := ret.Get(0).(func(uint, entity.Date, service.ResourceQuery) *service.Availability); ok {
+		r0 = rf(hubID, date, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*service.Availability)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(uint, entity.Date, service.ResourceQuery) error); ok {
+		r1 = rf(hubID, date, query)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindBooking provides a mock function with given fields: id
+func (_m *BookingService) FindBooking(id uint) (*entity.Booking, error) {
+	ret := _m.Called(id)
+
+	var r0 *entity.Booking
+	var r1 error
+	if rf, ok := ret.Get(0).(func(uint) (*entity.Booking, error)); ok {
+		return rf(id)
+	}
+	if rf, ok := ret.Get(0).(func(uint) *entity.Booking); ok {
+		r0 = rf(id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.Booking)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(uint) error); ok {
+		r1 = rf(id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListBookings provides a mock function with given fields: resourceID, from, to
+func (_m *BookingService) ListBookings(resourceID uint, from time.Time, to time.Time) ([]entity.Booking, error) {
+	ret := _m.Called(resourceID, from, to)
+
+	var r0 []entity.Booking
+	var r1 error
+	if rf, ok := ret.Get(0).(func(uint, time.Time, time.Time) ([]entity.Booking, error)); ok {
+		return rf(resourceID, from, to)
+	}
+	if rf, ok := ret.Get(0).(func(uint, time.Time, time.Time) []entity.Booking); ok {
+		r0 = rf(resourceID, from, to)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.Booking)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(uint, time.Time, time.Time) error); ok {
+		r1 = rf(resourceID, from, to)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewBookingService creates a new instance of BookingService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewBookingService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *BookingService {
+	mock := &BookingService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
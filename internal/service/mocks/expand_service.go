@@ -0,0 +1,142 @@
+// Code generated by mockery v2.33.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	entity "hub_management_service/internal/entity"
+
+	mock "github.com/stretchr/testify/mock"
+
+	service "hub_management_service/internal/service"
+)
+
+// ExpandService is an autogenerated mock type for the ExpandService type
+type ExpandService struct {
+	mock.Mock
+}
+
+// ExpandBookings provides a mock function with given fields: bookings, expansion
+func (_m *ExpandService) ExpandBookings(bookings []*entity.Booking, expansion service.Expansion) error {
+	ret := _m.Called(bookings, expansion)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func([]*entity.Booking, service.Expansion) error); ok {
+		r0 = rf(bookings, expansion)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ExpandHubs provides a mock function with given fields: hubs, expansion
+func (_m *ExpandService) ExpandHubs(hubs []*entity.Hub, expansion service.Expansion) error {
+	ret := _m.Called(hubs, expansion)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func([]*entity.Hub, service.Expansion) error); ok {
+		r0 = rf(hubs, expansion)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ExpandRegions provides a mock function with given fields: regions, expansion
+func (_m *ExpandService) ExpandRegions(regions []*entity.Region, expansion service.Expansion) error {
+	ret := _m.Called(regions, expansion)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func([]*entity.Region, service.Expansion) error); ok {
+		r0 = rf(regions, expansion)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ExpandResources provides a mock function with given fields: resources, expansion
+func (_m *ExpandService) ExpandResources(resources []*entity.Resource, expansion service.Expansion) error {
+	ret := _m.Called(resources, expansion)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func([]*entity.Resource, service.Expansion) error); ok {
+		r0 = rf(resources, expansion)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ExpandSearchHits provides a mock function with given fields: hits, expansion
+func (_m *ExpandService) ExpandSearchHits(hits []*service.SearchHit, expansion service.Expansion) error {
+	ret := _m.Called(hits, expansion)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func([]*service.SearchHit, service.Expansion) error); ok {
+		r0 = rf(hits, expansion)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ExpandTeams provides a mock function with given fields: teams, expansion
+func (_m *ExpandService) ExpandTeams(teams []*entity.Team, expansion service.Expansion) error {
+	ret := _m.Called(teams, expansion)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func([]*entity.Team, service.Expansion) error); ok {
+		r0 = rf(teams, expansion)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ExpandUsers provides a mock function with given fields: users, expansion
+func (_m *ExpandService) ExpandUsers(users []*entity.User, expansion service.Expansion) error {
+	ret := _m.Called(users, expansion)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func([]*entity.User, service.Expansion) error); ok {
+		r0 = rf(users, expansion)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ExpandUtilisation provides a mock function with given fields: hubs, expansion
+func (_m *ExpandService) ExpandUtilisation(hubs []*service.HubUtilisation, expansion service.Expansion) error {
+	ret := _m.Called(hubs, expansion)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func([]*service.HubUtilisation, service.Expansion) error); ok {
+		r0 = rf(hubs, expansion)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewExpandService creates a new instance of ExpandService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewExpandService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *ExpandService {
+	mock := &ExpandService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
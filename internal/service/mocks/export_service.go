@@ -0,0 +1,54 @@
+// Code generated by mockery v2.33.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	service "hub_management_service/internal/service"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// ExportService is an autogenerated mock type for the ExportService type
+type ExportService struct {
+	mock.Mock
+}
+
+// PrepareExport provides a mock function with given fields: query
+func (_m *ExportService) PrepareExport(query service.ExportQuery) (service.Export, error) {
+	ret := _m.Called(query)
+
+	var r0 service.Export
+	var r1 error
+	if rf, ok := ret.Get(0).(func(service.ExportQuery) (service.Export, error)); ok {
+		return rf(query)
+	}
+	if rf, ok := ret.Get(0).(func(service.ExportQuery) service.Export); ok {
+		r0 = rf(query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(service.Export)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(service.ExportQuery) error); ok {
+		r1 = rf(query)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewExportService creates a new instance of ExportService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewExportService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *ExportService {
+	mock := &ExportService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
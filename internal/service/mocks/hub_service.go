@@ -6,6 +6,8 @@ import (
 	entity "hub_management_service/internal/entity"
 
 	mock "github.com/stretchr/testify/mock"
+
+	service "hub_management_service/internal/service"
 )
 
 // HubService is an autogenerated mock type for the HubService type
@@ -27,6 +29,20 @@ func (_m *HubService) CreateHub(hub *entity.Hub) error {
 	return r0
 }
 
+// DeleteHub provides a mock function with given fields: id, version
+func (_m *HubService) DeleteHub(id uint, version uint) error {
+	ret := _m.Called(id, version)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(uint, uint) error); ok {
+		r0 = rf(id, version)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // FindHubByID provides a mock function with given fields: id
 func (_m *HubService) FindHubByID(id uint) (*entity.Hub, error) {
 	ret := _m.Called(id)
@@ -53,25 +69,110 @@ func (_m *HubService) FindHubByID(id uint) (*entity.Hub, error) {
 	return r0, r1
 }
 
-// GetAllHubs provides a mock function with given fields:
-func (_m *HubService) GetAllHubs() ([]entity.Hub, error) {
-	ret := _m.Called()
+// FindNearby provides a mock function with given fields: query
+func (_m *HubService) FindNearby(query service.NearbyQuery) ([]service.NearbyHub, error) {
+	ret := _m.Called(query)
 
-	var r0 []entity.Hub
+	var r0 []service.NearbyHub
 	var r1 error
-	if rf, ok := ret.Get(0).(func() ([]entity.Hub, error)); ok {
-		return rf()
+	if rf, ok := ret.Get(0).(func(service.NearbyQuery) ([]service.NearbyHub, error)); ok {
+		return rf(query)
 	}
-	if rf, ok := ret.Get(0).(func() []entity.Hub); ok {
-		r0 = rf()
+	if rf, ok := ret.Get(0).(func(service.NearbyQuery) []service.NearbyHub); ok {
+		r0 = rf(query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]service.NearbyHub)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(service.NearbyQuery) error); ok {
+		r1 = rf(query)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindUtilisation provides a mock function with given fields: hub
+func (_m *HubService) FindUtilisation(hub *entity.Hub) (*service.HubUtilisation, error) {
+	ret := _m.Called(hub)
+
+	var r0 *service.HubUtilisation
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*entity.Hub) (*service.HubUtilisation, error)); ok {
+		return rf(hub)
+	}
+	if rf, ok := ret.Get(0).(func(*entity.Hub) *service.HubUtilisation); ok {
+		r0 = rf(hub)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*service.HubUtilisation)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*entity.Hub) error); ok {
+		r1 = rf(hub)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListHubs provides a mock function with given fields: query
+func (_m *HubService) ListHubs(query service.HubQuery) ([]entity.Hub, int64, error) {
+	ret := _m.Called(query)
+
+	var r0 []entity.Hub
+	var r1 int64
+	var r2 error
+	if rf, ok := ret.Get(0).(func(service.HubQuery) ([]entity.Hub, int64, error)); ok {
+		return rf(query)
+	}
+	if rf, ok := ret.Get(0).(func(service.HubQuery) []entity.Hub); ok {
+		r0 = rf(query)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]entity.Hub)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func() error); ok {
-		r1 = rf()
+	if rf, ok := ret.Get(1).(func(service.HubQuery) int64); ok {
+		r1 = rf(query)
+	} else {
+		r1 = ret.Get(1).(int64)
+	}
+
+	if rf, ok := ret.Get(2).(func(service.HubQuery) error); ok {
+		r2 = rf(query)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// PatchHub provides a mock function with given fields: id, version, format, patch
+func (_m *HubService) PatchHub(id uint, version uint, format service.PatchFormat, patch []byte) (*entity.Hub, error) {
+	ret := _m.Called(id, version, format, patch)
+
+	var r0 *entity.Hub
+	var r1 error
+	if rf, ok := ret.Get(0).(func(uint, uint, service.PatchFormat, []byte) (*entity.Hub, error)); ok {
+		return rf(id, version, format, patch)
+	}
+	if rf, ok := ret.Get(0).(func(uint, uint, service.PatchFormat, []byte) *entity.Hub); ok {
+		r0 = rf(id, version, format, patch)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.Hub)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(uint, uint, service.PatchFormat, []byte) error); ok {
+		r1 = rf(id, version, format, patch)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -105,6 +206,58 @@ func (_m *HubService) SearchHubsByName(name string) ([]entity.Hub, error) {
 	return r0, r1
 }
 
+// UpdateHub provides a mock function with given fields: id, version, hub
+func (_m *HubService) UpdateHub(id uint, version uint, hub *entity.Hub) (*entity.Hub, error) {
+	ret := _m.Called(id, version, hub)
+
+	var r0 *entity.Hub
+	var r1 error
+	if rf, ok := ret.Get(0).(func(uint, uint, *entity.Hub) (*entity.Hub, error)); ok {
+		return rf(id, version, hub)
+	}
+	if rf, ok := ret.Get(0).(func(uint, uint, *entity.Hub) *entity.Hub); ok {
+		r0 = rf(id, version, hub)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.Hub)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(uint, uint, *entity.Hub) error); ok {
+		r1 = rf(id, version, hub)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UtilisationReport provides a mock function with given fields:
+func (_m *HubService) UtilisationReport() (*service.UtilisationReport, error) {
+	ret := _m.Called()
+
+	var r0 *service.UtilisationReport
+	var r1 error
+	if rf, ok := ret.Get(0).(func() (*service.UtilisationReport, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() *service.UtilisationReport); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*service.UtilisationReport)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // NewHubService creates a new instance of HubService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewHubService(t interface {
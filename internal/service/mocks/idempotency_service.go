@@ -0,0 +1,113 @@
+// Code generated by mockery v2.33.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	service "hub_management_service/internal/service"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// IdempotencyService is an autogenerated mock type for the IdempotencyService type
+type IdempotencyService struct {
+	mock.Mock
+}
+
+// Abandon provides a mock function with given fields: scope, key, lease
+func (_m *IdempotencyService) Abandon(scope string, key string, lease string) error {
+	ret := _m.Called(scope, key, lease)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, string) error); ok {
+		r0 = rf(scope, key, lease)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Begin provides a mock function with given fields: scope, key, fingerprint
+func (_m *IdempotencyService) Begin(scope string, key string, fingerprint string) (*service.IdempotentResponse, string, error) {
+	ret := _m.Called(scope, key, fingerprint)
+
+	var r0 *service.IdempotentResponse
+	var r1 string
+	var r2 error
+	if rf, ok := ret.Get(0).(func(string, string, string) (*service.IdempotentResponse, string, error)); ok {
+		return rf(scope, key, fingerprint)
+	}
+	if rf, ok := ret.Get(0).(func(string, string, string) *service.IdempotentResponse); ok {
+		r0 = rf(scope, key, fingerprint)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*service.IdempotentResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, string, string) string); ok {
+		r1 = rf(scope, key, fingerprint)
+	} else {
+		r1 = ret.Get(1).(string)
+	}
+
+	if rf, ok := ret.Get(2).(func(string, string, string) error); ok {
+		r2 = rf(scope, key, fingerprint)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// Complete provides a mock function with given fields: scope, key, lease, response
+func (_m *IdempotencyService) Complete(scope string, key string, lease string, response *service.IdempotentResponse) error {
+	ret := _m.Called(scope, key, lease, response)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, string, *service.IdempotentResponse) error); ok {
+		r0 = rf(scope, key, lease, response)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// PurgeExpired provides a mock function with given fields:
+func (_m *IdempotencyService) PurgeExpired() (int64, error) {
+	ret := _m.Called()
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func() (int64, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() int64); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewIdempotencyService creates a new instance of IdempotencyService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewIdempotencyService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *IdempotencyService {
+	mock := &IdempotencyService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
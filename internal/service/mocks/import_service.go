@@ -0,0 +1,55 @@
+// Code generated by mockery v2.33.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	service "hub_management_service/internal/service"
+	io "io"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// ImportService is an autogenerated mock type for the ImportService type
+type ImportService struct {
+	mock.Mock
+}
+
+// Import provides a mock function with given fields: kind, r, opts
+func (_m *ImportService) Import(kind service.ImportKind, r io.Reader, opts service.ImportOptions) (*service.ImportReport, error) {
+	ret := _m.Called(kind, r, opts)
+
+	var r0 *service.ImportReport
+	var r1 error
+	if rf, ok := ret.Get(0).(func(service.ImportKind, io.Reader, service.ImportOptions) (*service.ImportReport, error)); ok {
+		return rf(kind, r, opts)
+	}
+	if rf, ok := ret.Get(0).(func(service.ImportKind, io.Reader, service.ImportOptions) *service.ImportReport); ok {
+		r0 = rf(kind, r, opts)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*service.ImportReport)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(service.ImportKind, io.Reader, service.ImportOptions) error); ok {
+		r1 = rf(kind, r, opts)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewImportService creates a new instance of ImportService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewImportService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *ImportService {
+	mock := &ImportService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,110 @@
+// Code generated by mockery v2.33.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	entity "hub_management_service/internal/entity"
+
+	mock "github.com/stretchr/testify/mock"
+
+	service "hub_management_service/internal/service"
+)
+
+// MembershipService is an autogenerated mock type for the MembershipService type
+type MembershipService struct {
+	mock.Mock
+}
+
+// AddMember provides a mock function with given fields: teamID, membership, opts
+func (_m *MembershipService) AddMember(teamID uint, membership *entity.Membership, opts service.WriteOptions) error {
+	ret := _m.Called(teamID, membership, opts)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(uint, *entity.Membership, service.WriteOptions) error); ok {
+		r0 = rf(teamID, membership, opts)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ListMembers provides a mock function with given fields: teamID
+func (_m *MembershipService) ListMembers(teamID uint) ([]entity.Membership, error) {
+	ret := _m.Called(teamID)
+
+	var r0 []entity.Membership
+	var r1 error
+	if rf, ok := ret.Get(0).(func(uint) ([]entity.Membership, error)); ok {
+		return rf(teamID)
+	}
+	if rf, ok := ret.Get(0).(func(uint) []entity.Membership); ok {
+		r0 = rf(teamID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.Membership)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(uint) error); ok {
+		r1 = rf(teamID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListUserMemberships provides a mock function with given fields: userID
+func (_m *MembershipService) ListUserMemberships(userID uint) ([]entity.Membership, error) {
+	ret := _m.Called(userID)
+
+	var r0 []entity.Membership
+	var r1 error
+	if rf, ok := ret.Get(0).(func(uint) ([]entity.Membership, error)); ok {
+		return rf(userID)
+	}
+	if rf, ok := ret.Get(0).(func(uint) []entity.Membership); ok {
+		r0 = rf(userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.Membership)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(uint) error); ok {
+		r1 = rf(userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RemoveMember provides a mock function with given fields: teamID, userID
+func (_m *MembershipService) RemoveMember(teamID uint, userID uint) error {
+	ret := _m.Called(teamID, userID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(uint, uint) error); ok {
+		r0 = rf(teamID, userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewMembershipService creates a new instance of MembershipService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMembershipService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MembershipService {
+	mock := &MembershipService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
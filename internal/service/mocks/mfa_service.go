@@ -0,0 +1,120 @@
+// Code generated by mockery v2.33.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	service "hub_management_service/internal/service"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MFAService is an autogenerated mock type for the MFAService type
+type MFAService struct {
+	mock.Mock
+}
+
+// Confirm provides a mock function with given fields: username, code
+func (_m *MFAService) Confirm(username string, code string) error {
+	ret := _m.Called(username, code)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string) error); ok {
+		r0 = rf(username, code)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Disable provides a mock function with given fields: username, code
+func (_m *MFAService) Disable(username string, code string) error {
+	ret := _m.Called(username, code)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string) error); ok {
+		r0 = rf(username, code)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Enroll provides a mock function with given fields: username
+func (_m *MFAService) Enroll(username string) (*service.MFAEnrollmentResult, error) {
+	ret := _m.Called(username)
+
+	var r0 *service.MFAEnrollmentResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (*service.MFAEnrollmentResult, error)); ok {
+		return rf(username)
+	}
+	if rf, ok := ret.Get(0).(func(string) *service.MFAEnrollmentResult); ok {
+		r0 = rf(username)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*service.MFAEnrollmentResult)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(username)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// IsEnabled provides a mock function with given fields: username
+func (_m *MFAService) IsEnabled(username string) (bool, error) {
+	ret := _m.Called(username)
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (bool, error)); ok {
+		return rf(username)
+	}
+	if rf, ok := ret.Get(0).(func(string) bool); ok {
+		r0 = rf(username)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(username)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Verify provides a mock function with given fields: username, code
+func (_m *MFAService) Verify(username string, code string) error {
+	ret := _m.Called(username, code)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string) error); ok {
+		r0 = rf(username, code)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewMFAService creates a new instance of MFAService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMFAService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MFAService {
+	mock := &MFAService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,80 @@
+// Code generated by mockery v2.33.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	orgchart "hub_management_service/pkg/orgchart"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// OrgChartService is an autogenerated mock type for the OrgChartService type
+type OrgChartService struct {
+	mock.Mock
+}
+
+// HubChart provides a mock function with given fields: hubID, managers
+func (_m *OrgChartService) HubChart(hubID uint, managers bool) (*orgchart.Chart, error) {
+	ret := _m.Called(hubID, managers)
+
+	var r0 *orgchart.Chart
+	var r1 error
+	if rf, ok := ret.Get(0).(func(uint, bool) (*orgchart.Chart, error)); ok {
+		return rf(hubID, managers)
+	}
+	if rf, ok := ret.Get(0).(func(uint, bool) *orgchart.Chart); ok {
+		r0 = rf(hubID, managers)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*orgchart.Chart)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(uint, bool) error); ok {
+		r1 = rf(hubID, managers)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// TeamChart provides a mock function with given fields: teamID, managers
+func (_m *OrgChartService) TeamChart(teamID uint, managers bool) (*orgchart.Chart, error) {
+	ret := _m.Called(teamID, managers)
+
+	var r0 *orgchart.Chart
+	var r1 error
+	if rf, ok := ret.Get(0).(func(uint, bool) (*orgchart.Chart, error)); ok {
+		return rf(teamID, managers)
+	}
+	if rf, ok := ret.Get(0).(func(uint, bool) *orgchart.Chart); ok {
+		r0 = rf(teamID, managers)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*orgchart.Chart)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(uint, bool) error); ok {
+		r1 = rf(teamID, managers)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewOrgChartService creates a new instance of OrgChartService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewOrgChartService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *OrgChartService {
+	mock := &OrgChartService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
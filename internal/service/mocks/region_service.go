@@ -0,0 +1,242 @@
+// Code generated by mockery v2.33.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	entity "hub_management_service/internal/entity"
+
+	mock "github.com/stretchr/testify/mock"
+
+	repository "hub_management_service/internal/repository"
+)
+
+// RegionService is an autogenerated mock type for the RegionService type
+type RegionService struct {
+	mock.Mock
+}
+
+// CreateCountry provides a mock function with given fields: country
+func (_m *RegionService) CreateCountry(country *entity.Country) error {
+	ret := _m.Called(country)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*entity.Country) error); ok {
+		r0 = rf(country)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CreateRegion provides a mock function with given fields: region
+func (_m *RegionService) CreateRegion(region *entity.Region) error {
+	ret := _m.Called(region)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*entity.Region) error); ok {
+		r0 = rf(region)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteCountry provides a mock function with given fields: id
+func (_m *RegionService) DeleteCountry(id uint) error {
+	ret := _m.Called(id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(uint) error); ok {
+		r0 = rf(id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteRegion provides a mock function with given fields: id
+func (_m *RegionService) DeleteRegion(id uint) error {
+	ret := _m.Called(id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(uint) error); ok {
+		r0 = rf(id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// FindRegion provides a mock function with given fields: id
+func (_m *RegionService) FindRegion(id uint) (*entity.Region, error) {
+	ret := _m.Called(id)
+
+	var r0 *entity.Region
+	var r1 error
+	if rf, ok := ret.Get(0).(func(uint) (*entity.Region, error)); ok {
+		return rf(id)
+	}
+	if rf, ok := ret.Get(0).(func(uint) *entity.Region); ok {
+		r0 = rf(id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.Region)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(uint) error); ok {
+		r1 = rf(id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListCountries provides a mock function with given fields: regionID
+func (_m *RegionService) ListCountries(regionID uint) ([]entity.Country, error) {
+	ret := _m.Called(regionID)
+
+	var r0 []entity.Country
+	var r1 error
+	if rf, ok := ret.Get(0).(func(uint) ([]entity.Country, error)); ok {
+		return rf(regionID)
+	}
+	if rf, ok := ret.Get(0).(func(uint) []entity.Country); ok {
+		r0 = rf(regionID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.Country)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(uint) error); ok {
+		r1 = rf(regionID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListRegions provides a mock function with given fields:
+func (_m *RegionService) ListRegions() ([]entity.Region, error) {
+	ret := _m.Called()
+
+	var r0 []entity.Region
+	var r1 error
+	if rf, ok := ret.Get(0).(func() ([]entity.Region, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() []entity.Region); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.Region)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Rollup provides a mock function with given fields:
+func (_m *RegionService) Rollup() ([]repository.RegionRollup, error) {
+	ret := _m.Called()
+
+	var r0 []repository.RegionRollup
+	var r1 error
+	if rf, ok := ret.Get(0).(func() ([]repository.RegionRollup, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() []repository.RegionRollup); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]repository.RegionRollup)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateCountry provides a mock function with given fields: id, country
+func (_m *RegionService) UpdateCountry(id uint, country *entity.Country) (*entity.Country, error) {
+	ret := _m.Called(id, country)
+
+	var r0 *entity.Country
+	var r1 error
+	if rf, ok := ret.Get(0).(func(uint, *entity.Country) (*entity.Country, error)); ok {
+		return rf(id, country)
+	}
+	if rf, ok := ret.Get(0).(func(uint, *entity.Country) *entity.Country); ok {
+		r0 = rf(id, country)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.Country)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(uint, *entity.Country) error); ok {
+		r1 = rf(id, country)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateRegion provides a mock function with given fields: id, region
+func (_m *RegionService) UpdateRegion(id uint, region *entity.Region) (*entity.Region, error) {
+	ret := _m.Called(id, region)
+
+	var r0 *entity.Region
+	var r1 error
+	if rf, ok := ret.Get(0).(func(uint, *entity.Region) (*entity.Region, error)); ok {
+		return rf(id, region)
+	}
+	if rf, ok := ret.Get(0).(func(uint, *entity.Region) *entity.Region); ok {
+		r0 = rf(id, region)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.Region)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(uint, *entity.Region) error); ok {
+		r1 = rf(id, region)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewRegionService creates a new instance of RegionService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewRegionService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *RegionService {
+	mock := &RegionService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
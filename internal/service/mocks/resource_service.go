@@ -0,0 +1,143 @@
+// Code generated by mockery v2.33.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	entity "hub_management_service/internal/entity"
+
+	mock "github.com/stretchr/testify/mock"
+
+	service "hub_management_service/internal/service"
+)
+
+// ResourceService is an autogenerated mock type for the ResourceService type
+type ResourceService struct {
+	mock.Mock
+}
+
+// CreateResource provides a mock function with given fields: hubID, resource
+func (_m *ResourceService) CreateResource(hubID uint, resource *entity.Resource) error {
+	ret := _m.Called(hubID, resource)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(uint, *entity.Resource) error); ok {
+		r0 = rf(hubID, resource)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteResource provides a mock function with given fields: id
+func (_m *ResourceService) DeleteResource(id uint) error {
+	ret := _m.Called(id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(uint) error); ok {
+		r0 = rf(id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// FindResource provides a mock function with given fields: id
+func (_m *ResourceService) FindResource(id uint) (*entity.Resource, error) {
+	ret := _m.Called(id)
+
+	var r0 *entity.Resource
+	var r1 error
+	if rf, ok := ret.Get(0).(func(uint) (*entity.Resource, error)); ok {
+		return rf(id)
+	}
+	if rf, ok := ret.Get(0).(func(uint) *entity.Resource); ok {
+		r0 = rf(id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.Resource)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(uint) error); ok {
+		r1 = rf(id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListResources provides a mock function with given fields: hubID, query
+func (_m *ResourceService) ListResources(hubID uint, query service.ResourceQuery) ([]entity.Resource, int64, error) {
+	ret := _m.Called(hubID, query)
+
+	var r0 []entity.Resource
+	var r1 int64
+	var r2 error
+	if rf, ok := ret.Get(0).(func(uint, service.ResourceQuery) ([]entity.Resource, int64, error)); ok {
+		return rf(hubID, query)
+	}
+	if rf, ok := ret.Get(0).(func(uint, service.ResourceQuery) []entity.Resource); ok {
+		r0 = rf(hubID, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.Resource)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(uint, service.ResourceQuery) int64); ok {
+		r1 = rf(hubID, query)
+	} else {
+		r1 = ret.Get(1).(int64)
+	}
+
+	if rf, ok := ret.Get(2).(func(uint, service.ResourceQuery) error); ok {
+		r2 = rf(hubID, query)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// UpdateResource provides a mock function with given fields: id, update
+func (_m *ResourceService) UpdateResource(id uint, update *entity.Resource) (*entity.Resource, error) {
+	ret := _m.Called(id, update)
+
+	var r0 *entity.Resource
+	var r1 error
+	if rf, ok := ret.Get(0).(func(uint, *entity.Resource) (*entity.Resource, error)); ok {
+		return rf(id, update)
+	}
+	if rf, ok := ret.Get(0).(func(uint, *entity.Resource) *entity.Resource); ok {
+		r0 = rf(id, update)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.Resource)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(uint, *entity.Resource) error); ok {
+		r1 = rf(id, update)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewResourceService creates a new instance of ResourceService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewResourceService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *ResourceService {
+	mock := &ResourceService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
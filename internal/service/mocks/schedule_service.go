@@ -0,0 +1,190 @@
+// Code generated by mockery v2.33.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	entity "hub_management_service/internal/entity"
+
+	mock "github.com/stretchr/testify/mock"
+
+	service "hub_management_service/internal/service"
+
+	time "time"
+)
+
+// ScheduleService is an autogenerated mock type for the ScheduleService type
+type ScheduleService struct {
+	mock.Mock
+}
+
+// AddHoliday provides a mock function with given fields: hubID, holiday
+func (_m *ScheduleService) AddHoliday(hubID uint, holiday *entity.Holiday) error {
+	ret := _m.Called(hubID, holiday)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(uint, *entity.Holiday) error); ok {
+		r0 = rf(hubID, holiday)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ExportHolidays provides a mock function with given fields: hubID
+func (_m *ScheduleService) ExportHolidays(hubID uint) ([]byte, error) {
+	ret := _m.Called(hubID)
+
+	var r0 []byte
+	var r1 error
+	if rf, ok := ret.Get(0).(func(uint) ([]byte, error)); ok {
+		return rf(hubID)
+	}
+	if rf, ok := ret.Get(0).(func(uint) []byte); ok {
+		r0 = rf(hubID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]byte)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(uint) error); ok {
+		r1 = rf(hubID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindOpeningHours provides a mock function with given fields: hubID
+func (_m *ScheduleService) FindOpeningHours(hubID uint) ([]entity.OpeningHours, error) {
+	ret := _m.Called(hubID)
+
+	var r0 []entity.OpeningHours
+	var r1 error
+	if rf, ok := ret.Get(0).(func(uint) ([]entity.OpeningHours, error)); ok {
+		return rf(hubID)
+	}
+	if rf, ok := ret.Get(0).(func(uint) []entity.OpeningHours); ok {
+		r0 = rf(hubID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.OpeningHours)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(uint) error); ok {
+		r1 = rf(hubID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// IsOpen provides a mock function with given fields: hubID, at
+func (_m *ScheduleService) IsOpen(hubID uint, at time.Time) (*service.OpenStatus, error) {
+	ret := _m.Called(hubID, at)
+
+	var r0 *service.OpenStatus
+	var r1 error
+	if rf, ok := ret.Get(0).(func(uint, time.Time) (*service.OpenStatus, error)); ok {
+		return rf(hubID, at)
+	}
+	if rf, ok := ret.Get(0).(func(uint, time.Time) *service.OpenStatus); ok {
+		r0 = rf(hubID, at)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*service.OpenStatus)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(uint, time.Time) error); ok {
+		r1 = rf(hubID, at)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListHolidays provides a mock function with given fields: hubID, year
+func (_m *ScheduleService) ListHolidays(hubID uint, year int) ([]entity.Holiday, error) {
+	ret := _m.Called(hubID, year)
+
+	var r0 []entity.Holiday
+	var r1 error
+	if rf, ok := ret.Get(0).(func(uint, int) ([]entity.Holiday, error)); ok {
+		return rf(hubID, year)
+	}
+	if rf, ok := ret.Get(0).(func(uint, int) []entity.Holiday); ok {
+		r0 = rf(hubID, year)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.Holiday)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(uint, int) error); ok {
+		r1 = rf(hubID, year)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RemoveHoliday provides a mock function with given fields: hubID, id
+func (_m *ScheduleService) RemoveHoliday(hubID uint, id uint) error {
+	ret := _m.Called(hubID, id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(uint, uint) error); ok {
+		r0 = rf(hubID, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetOpeningHours provides a mock function with given fields: hubID, hours
+func (_m *ScheduleService) SetOpeningHours(hubID uint, hours []entity.OpeningHours) ([]entity.OpeningHours, error) {
+	ret := _m.Called(hubID, hours)
+
+	var r0 []entity.OpeningHours
+	var r1 error
+	if rf, ok := ret.Get(0).(func(uint, []entity.OpeningHours) ([]entity.OpeningHours, error)); ok {
+		return rf(hubID, hours)
+	}
+	if rf, ok := ret.Get(0).(func(uint, []entity.OpeningHours) []entity.OpeningHours); ok {
+		r0 = rf(hubID, hours)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.OpeningHours)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(uint, []entity.OpeningHours) error); ok {
+		r1 = rf(hubID, hours)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewScheduleService creates a new instance of ScheduleService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewScheduleService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *ScheduleService {
+	mock := &ScheduleService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,316 @@
+// Code generated by mockery v2.33.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	scim "hub_management_service/pkg/scim"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// SCIMService is an autogenerated mock type for the SCIMService type
+type SCIMService struct {
+	mock.Mock
+}
+
+// CreateGroup provides a mock function with given fields: group
+func (_m *SCIMService) CreateGroup(group *scim.Group) (*scim.Group, error) {
+	ret := _m.Called(group)
+
+	var r0 *scim.Group
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*scim.Group) (*scim.Group, error)); ok {
+		return rf(group)
+	}
+	if rf, ok := ret.Get(0).(func(*scim.Group) *scim.Group); ok {
+		r0 = rf(group)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*scim.Group)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*scim.Group) error); ok {
+		r1 = rf(group)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateUser provides a mock function with given fields: user
+func (_m *SCIMService) CreateUser(user *scim.User) (*scim.User, error) {
+	ret := _m.Called(user)
+
+	var r0 *scim.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*scim.User) (*scim.User, error)); ok {
+		return rf(user)
+	}
+	if rf, ok := ret.Get(0).(func(*scim.User) *scim.User); ok {
+		r0 = rf(user)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*scim.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*scim.User) error); ok {
+		r1 = rf(user)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteGroup provides a mock function with given fields: id, version
+func (_m *SCIMService) DeleteGroup(id string, version uint) error {
+	ret := _m.Called(id, version)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, uint) error); ok {
+		r0 = rf(id, version)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteUser provides a mock function with given fields: id, version
+func (_m *SCIMService) DeleteUser(id string, version uint) error {
+	ret := _m.Called(id, version)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, uint) error); ok {
+		r0 = rf(id, version)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetGroup provides a mock function with given fields: id
+func (_m *SCIMService) GetGroup(id string) (*scim.Group, error) {
+	ret := _m.Called(id)
+
+	var r0 *scim.Group
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (*scim.Group, error)); ok {
+		return rf(id)
+	}
+	if rf, ok := ret.Get(0).(func(string) *scim.Group); ok {
+		r0 = rf(id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*scim.Group)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetUser provides a mock function with given fields: id
+func (_m *SCIMService) GetUser(id string) (*scim.User, error) {
+	ret := _m.Called(id)
+
+	var r0 *scim.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (*scim.User, error)); ok {
+		return rf(id)
+	}
+	if rf, ok := ret.Get(0).(func(string) *scim.User); ok {
+		r0 = rf(id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*scim.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListGroups provides a mock function with given fields: filter, startIndex, count, excludeMembers
+func (_m *SCIMService) ListGroups(filter string, startIndex int, count int, excludeMembers bool) (*scim.ListResponse, error) {
+	ret := _m.Called(filter, startIndex, count, excludeMembers)
+
+	var r0 *scim.ListResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, int, int, bool) (*scim.ListResponse, error)); ok {
+		return rf(filter, startIndex, count, excludeMembers)
+	}
+	if rf, ok := ret.Get(0).(func(string, int, int, bool) *scim.ListResponse); ok {
+		r0 = rf(filter, startIndex, count, excludeMembers)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*scim.ListResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, int, int, bool) error); ok {
+		r1 = rf(filter, startIndex, count, excludeMembers)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListUsers provides a mock function with given fields: filter, startIndex, count
+func (_m *SCIMService) ListUsers(filter string, startIndex int, count int) (*scim.ListResponse, error) {
+	ret := _m.Called(filter, startIndex, count)
+
+	var r0 *scim.ListResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, int, int) (*scim.ListResponse, error)); ok {
+		return rf(filter, startIndex, count)
+	}
+	if rf, ok := ret.Get(0).(func(string, int, int) *scim.ListResponse); ok {
+		r0 = rf(filter, startIndex, count)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*scim.ListResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, int, int) error); ok {
+		r1 = rf(filter, startIndex, count)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PatchGroup provides a mock function with given fields: id, version, operations
+func (_m *SCIMService) PatchGroup(id string, version uint, operations []scim.PatchOperation) (*scim.Group, error) {
+	ret := _m.Called(id, version, operations)
+
+	var r0 *scim.Group
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, uint, []scim.PatchOperation) (*scim.Group, error)); ok {
+		return rf(id, version, operations)
+	}
+	if rf, ok := ret.Get(0).(func(string, uint, []scim.PatchOperation) *scim.Group); ok {
+		r0 = rf(id, version, operations)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*scim.Group)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, uint, []scim.PatchOperation) error); ok {
+		r1 = rf(id, version, operations)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PatchUser provides a mock function with given fields: id, version, operations
+func (_m *SCIMService) PatchUser(id string, version uint, operations []scim.PatchOperation) (*scim.User, error) {
+	ret := _m.Called(id, version, operations)
+
+	var r0 *scim.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, uint, []scim.PatchOperation) (*scim.User, error)); ok {
+		return rf(id, version, operations)
+	}
+	if rf, ok := ret.Get(0).(func(string, uint, []scim.PatchOperation) *scim.User); ok {
+		r0 = rf(id, version, operations)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*scim.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, uint, []scim.PatchOperation) error); ok {
+		r1 = rf(id, version, operations)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ReplaceGroup provides a mock function with given fields: id, version, group
+func (_m *SCIMService) ReplaceGroup(id string, version uint, group *scim.Group) (*scim.Group, error) {
+	ret := _m.Called(id, version, group)
+
+	var r0 *scim.Group
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, uint, *scim.Group) (*scim.Group, error)); ok {
+		return rf(id, version, group)
+	}
+	if rf, ok := ret.Get(0).(func(string, uint, *scim.Group) *scim.Group); ok {
+		r0 = rf(id, version, group)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*scim.Group)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, uint, *scim.Group) error); ok {
+		r1 = rf(id, version, group)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ReplaceUser provides a mock function with given fields: id, version, user
+func (_m *SCIMService) ReplaceUser(id string, version uint, user *scim.User) (*scim.User, error) {
+	ret := _m.Called(id, version, user)
+
+	var r0 *scim.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, uint, *scim.User) (*scim.User, error)); ok {
+		return rf(id, version, user)
+	}
+	if rf, ok := ret.Get(0).(func(string, uint, *scim.User) *scim.User); ok {
+		r0 = rf(id, version, user)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*scim.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, uint, *scim.User) error); ok {
+		r1 = rf(id, version, user)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewSCIMService creates a new instance of SCIMService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewSCIMService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *SCIMService {
+	mock := &SCIMService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,54 @@
+// Code generated by mockery v2.33.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	service "hub_management_service/internal/service"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// SearchService is an autogenerated mock type for the SearchService type
+type SearchService struct {
+	mock.Mock
+}
+
+// Search provides a mock function with given fields: query
+func (_m *SearchService) Search(query service.SearchQuery) (*service.SearchResults, error) {
+	ret := _m.Called(query)
+
+	var r0 *service.SearchResults
+	var r1 error
+	if rf, ok := ret.Get(0).(func(service.SearchQuery) (*service.SearchResults, error)); ok {
+		return rf(query)
+	}
+	if rf, ok := ret.Get(0).(func(service.SearchQuery) *service.SearchResults); ok {
+		r0 = rf(query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*service.SearchResults)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(service.SearchQuery) error); ok {
+		r1 = rf(query)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewSearchService creates a new instance of SearchService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewSearchService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *SearchService {
+	mock := &SearchService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
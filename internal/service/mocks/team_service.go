@@ -6,6 +6,8 @@ import (
 	entity "hub_management_service/internal/entity"
 
 	mock "github.com/stretchr/testify/mock"
+
+	service "hub_management_service/internal/service"
 )
 
 // TeamService is an autogenerated mock type for the TeamService type
@@ -27,6 +29,46 @@ func (_m *TeamService) CreateTeam(team *entity.Team) error {
 	return r0
 }
 
+// DeleteTeam provides a mock function with given fields: id, version
+func (_m *TeamService) DeleteTeam(id uint, version uint) error {
+	ret := _m.Called(id, version)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(uint, uint) error); ok {
+		r0 = rf(id, version)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// FindAncestors provides a mock function with given fields: id
+func (_m *TeamService) FindAncestors(id uint) ([]entity.Team, error) {
+	ret := _m.Called(id)
+
+	var r0 []entity.Team
+	var r1 error
+	if rf, ok := ret.Get(0).(func(uint) ([]entity.Team, error)); ok {
+		return rf(id)
+	}
+	if rf, ok := ret.Get(0).(func(uint) []entity.Team); ok {
+		r0 = rf(id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.Team)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(uint) error); ok {
+		r1 = rf(id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // FindByID provides a mock function with given fields: id
 func (_m *TeamService) FindByID(id uint) (*entity.Team, error) {
 	ret := _m.Called(id)
@@ -53,6 +95,58 @@ func (_m *TeamService) FindByID(id uint) (*entity.Team, error) {
 	return r0, r1
 }
 
+// FindHeadcount provides a mock function with given fields: id
+func (_m *TeamService) FindHeadcount(id uint) (*service.Headcount, error) {
+	ret := _m.Called(id)
+
+	var r0 *service.Headcount
+	var r1 error
+	if rf, ok := ret.Get(0).(func(uint) (*service.Headcount, error)); ok {
+		return rf(id)
+	}
+	if rf, ok := ret.Get(0).(func(uint) *service.Headcount); ok {
+		r0 = rf(id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*service.Headcount)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(uint) error); ok {
+		r1 = rf(id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindSubtree provides a mock function with given fields: id
+func (_m *TeamService) FindSubtree(id uint) (*service.TeamNode, error) {
+	ret := _m.Called(id)
+
+	var r0 *service.TeamNode
+	var r1 error
+	if rf, ok := ret.Get(0).(func(uint) (*service.TeamNode, error)); ok {
+		return rf(id)
+	}
+	if rf, ok := ret.Get(0).(func(uint) *service.TeamNode); ok {
+		r0 = rf(id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*service.TeamNode)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(uint) error); ok {
+		r1 = rf(id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // FindTeamsByHubID provides a mock function with given fields: hubID
 func (_m *TeamService) FindTeamsByHubID(hubID uint) ([]entity.Team, error) {
 	ret := _m.Called(hubID)
@@ -79,6 +173,58 @@ func (_m *TeamService) FindTeamsByHubID(hubID uint) ([]entity.Team, error) {
 	return r0, r1
 }
 
+// PatchTeam provides a mock function with given fields: id, version, format, patch
+func (_m *TeamService) PatchTeam(id uint, version uint, format service.PatchFormat, patch []byte) (*entity.Team, error) {
+	ret := _m.Called(id, version, format, patch)
+
+	var r0 *entity.Team
+	var r1 error
+	if rf, ok := ret.Get(0).(func(uint, uint, service.PatchFormat, []byte) (*entity.Team, error)); ok {
+		return rf(id, version, format, patch)
+	}
+	if rf, ok := ret.Get(0).(func(uint, uint, service.PatchFormat, []byte) *entity.Team); ok {
+		r0 = rf(id, version, format, patch)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.Team)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(uint, uint, service.PatchFormat, []byte) error); ok {
+		r1 = rf(id, version, format, patch)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SetParent provides a mock function with given fields: id, version, parentID
+func (_m *TeamService) SetParent(id uint, version uint, parentID *uint) (*entity.Team, error) {
+	ret := _m.Called(id, version, parentID)
+
+	var r0 *entity.Team
+	var r1 error
+	if rf, ok := ret.Get(0).(func(uint, uint, *uint) (*entity.Team, error)); ok {
+		return rf(id, version, parentID)
+	}
+	if rf, ok := ret.Get(0).(func(uint, uint, *uint) *entity.Team); ok {
+		r0 = rf(id, version, parentID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.Team)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(uint, uint, *uint) error); ok {
+		r1 = rf(id, version, parentID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // NewTeamService creates a new instance of TeamService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewTeamService(t interface {
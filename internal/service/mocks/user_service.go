@@ -6,6 +6,8 @@ import (
 	entity "hub_management_service/internal/entity"
 
 	mock "github.com/stretchr/testify/mock"
+
+	service "hub_management_service/internal/service"
 )
 
 // UserService is an autogenerated mock type for the UserService type
@@ -13,13 +15,13 @@ type UserService struct {
 	mock.Mock
 }
 
-// CreateUser provides a mock function with given fields: user
-func (_m *UserService) CreateUser(user *entity.User) error {
-	ret := _m.Called(user)
+// CreateUser provides a mock function with given fields: user, opts
+func (_m *UserService) CreateUser(user *entity.User, opts service.WriteOptions) error {
+	ret := _m.Called(user, opts)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(*entity.User) error); ok {
-		r0 = rf(user)
+	if rf, ok := ret.Get(0).(func(*entity.User, service.WriteOptions) error); ok {
+		r0 = rf(user, opts)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -27,6 +29,124 @@ func (_m *UserService) CreateUser(user *entity.User) error {
 	return r0
 }
 
+// DeleteUser provides a mock function with given fields: id, version
+func (_m *UserService) DeleteUser(id uint, version uint) error {
+	ret := _m.Called(id, version)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(uint, uint) error); ok {
+		r0 = rf(id, version)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// FindDirectReports provides a mock function with given fields: id
+func (_m *UserService) FindDirectReports(id uint) ([]entity.User, error) {
+	ret := _m.Called(id)
+
+	var r0 []entity.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(uint) ([]entity.User, error)); ok {
+		return rf(id)
+	}
+	if rf, ok := ret.Get(0).(func(uint) []entity.User); ok {
+		r0 = rf(id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(uint) error); ok {
+		r1 = rf(id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindReportingChain provides a mock function with given fields: id
+func (_m *UserService) FindReportingChain(id uint) ([]entity.User, error) {
+	ret := _m.Called(id)
+
+	var r0 []entity.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(uint) ([]entity.User, error)); ok {
+		return rf(id)
+	}
+	if rf, ok := ret.Get(0).(func(uint) []entity.User); ok {
+		r0 = rf(id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(uint) error); ok {
+		r1 = rf(id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindSkipLevel provides a mock function with given fields: id
+func (_m *UserService) FindSkipLevel(id uint) (*service.SkipLevel, error) {
+	ret := _m.Called(id)
+
+	var r0 *service.SkipLevel
+	var r1 error
+	if rf, ok := ret.Get(0).(func(uint) (*service.SkipLevel, error)); ok {
+		return rf(id)
+	}
+	if rf, ok := ret.Get(0).(func(uint) *service.SkipLevel); ok {
+		r0 = rf(id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*service.SkipLevel)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(uint) error); ok {
+		r1 = rf(id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindUserByEmail provides a mock function with given fields: email
+func (_m *UserService) FindUserByEmail(email string) (*entity.User, error) {
+	ret := _m.Called(email)
+
+	var r0 *entity.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (*entity.User, error)); ok {
+		return rf(email)
+	}
+	if rf, ok := ret.Get(0).(func(string) *entity.User); ok {
+		r0 = rf(email)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(email)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // FindUserByID provides a mock function with given fields: id
 func (_m *UserService) FindUserByID(id uint) (*entity.User, error) {
 	ret := _m.Called(id)
@@ -79,6 +199,117 @@ func (_m *UserService) FindUserByTeamID(teamID uint) ([]entity.User, error) {
 	return r0, r1
 }
 
+// PatchUser provides a mock function with given fields: id, version, format, patch, opts
+func (_m *UserService) PatchUser(id uint, version uint, format service.PatchFormat, patch []byte, opts service.WriteOptions) (*entity.User, error) {
+	ret := _m.Called(id, version, format, patch, opts)
+
+	var r0 *entity.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(uint, uint, service.PatchFormat, []byte, service.WriteOptions) (*entity.User, error)); ok {
+		return rf(id, version, format, patch, opts)
+	}
+	if rf, ok := ret.Get(0).(func(uint, uint, service.PatchFormat, []byte, service.WriteOptions) *entity.User); ok {
+		r0 = rf(id, version, format, patch, opts)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(uint, uint, service.PatchFormat, []byte, service.WriteOptions) error); ok {
+		r1 = rf(id, version, format, patch, opts)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SearchUsers provides a mock function with given fields: query
+func (_m *UserService) SearchUsers(query service.UserQuery) ([]entity.User, int64, error) {
+	ret := _m.Called(query)
+
+	var r0 []entity.User
+	var r1 int64
+	var r2 error
+	if rf, ok := ret.Get(0).(func(service.UserQuery) ([]entity.User, int64, error)); ok {
+		return rf(query)
+	}
+	if rf, ok := ret.Get(0).(func(service.UserQuery) []entity.User); ok {
+		r0 = rf(query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(service.UserQuery) int64); ok {
+		r1 = rf(query)
+	} else {
+		r1 = ret.Get(1).(int64)
+	}
+
+	if rf, ok := ret.Get(2).(func(service.UserQuery) error); ok {
+		r2 = rf(query)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// SetManager provides a mock function with given fields: id, version, managerID
+func (_m *UserService) SetManager(id uint, version uint, managerID *uint) (*entity.User, error) {
+	ret := _m.Called(id, version, managerID)
+
+	var r0 *entity.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(uint, uint, *uint) (*entity.User, error)); ok {
+		return rf(id, version, managerID)
+	}
+	if rf, ok := ret.Get(0).(func(uint, uint, *uint) *entity.User); ok {
+		r0 = rf(id, version, managerID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(uint, uint, *uint) error); ok {
+		r1 = rf(id, version, managerID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateUser provides a mock function with given fields: id, version, patch, opts
+func (_m *UserService) UpdateUser(id uint, version uint, patch *service.UserPatch, opts service.WriteOptions) (*entity.User, error) {
+	ret := _m.Called(id, version, patch, opts)
+
+	var r0 *entity.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(uint, uint, *service.UserPatch, service.WriteOptions) (*entity.User, error)); ok {
+		return rf(id, version, patch, opts)
+	}
+	if rf, ok := ret.Get(0).(func(uint, uint, *service.UserPatch, service.WriteOptions) *entity.User); ok {
+		r0 = rf(id, version, patch, opts)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(uint, uint, *service.UserPatch, service.WriteOptions) error); ok {
+		r1 = rf(id, version, patch, opts)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // NewUserService creates a new instance of UserService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewUserService(t interface {
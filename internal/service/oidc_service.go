@@ -0,0 +1,116 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"hub_management_service/internal/entity"
+	"hub_management_service/internal/repository"
+	"hub_management_service/pkg/oidc"
+)
+
+var ErrUserNotProvisioned = errors.New("no user is provisioned for this identity")
+
+// OIDCProvider is the part of the OpenID Connect client used by the service
+type OIDCProvider interface {
+	AuthCodeURL(ctx context.Context, state, nonce, codeChallenge string) (string, error)
+	Exchange(ctx context.Context, code, codeVerifier, nonce string) (*oidc.Claims, error)
+}
+
+// OIDCLogin holds the values that must survive the round trip to the identity provider
+type OIDCLogin struct {
+	AuthURL      string
+	State        string
+	Nonce        string
+	CodeVerifier string
+}
+
+type OIDCService interface {
+	BeginLogin(ctx context.Context) (*OIDCLogin, error)
+	CompleteLogin(ctx context.Context, code, codeVerifier, nonce string) (*entity.User, error)
+}
+
+type oidcService struct {
+	tx       repository.Transactor
+	provider OIDCProvider
+	userRepo repository.UserRepository
+}
+
+func NewOIDCService(tx repository.Transactor, provider OIDCProvider, userRepo repository.UserRepository) OIDCService {
+	return &oidcService{tx: tx, provider: provider, userRepo: userRepo}
+}
+
+// bind returns the service working with the repositories of a transaction
+func (s *oidcService) bind(repos repository.Repositories) *oidcService {
+	return &oidcService{tx: repos, provider: s.provider, userRepo: repos.Users}
+}
+
+// BeginLogin generates state, nonce and a PKCE verifier and builds the authorization URL
+func (s *oidcService) BeginLogin(ctx context.Context) (*OIDCLogin, error) {
+	login := &OIDCLogin{}
+	for _, value := range []*string{&login.State, &login.Nonce, &login.CodeVerifier} {
+		random, err := oidc.RandomString()
+		if err != nil {
+			return nil, err
+		}
+		*value = random
+	}
+
+	authURL, err := s.provider.AuthCodeURL(ctx, login.State, login.Nonce, oidc.CodeChallenge(login.CodeVerifier))
+	if err != nil {
+		return nil, err
+	}
+	login.AuthURL = authURL
+	return login, nil
+}
+
+// CompleteLogin redeems the authorization code and maps the identity to an existing user.
+// Users are matched by subject first, then by verified email, in which case the subject is linked.
+func (s *oidcService) CompleteLogin(ctx context.Context, code, codeVerifier, nonce string) (*entity.User, error) {
+	claims, err := s.provider.Exchange(ctx, code, codeVerifier, nonce)
+	if err != nil {
+		return nil, err
+	}
+
+	var user *entity.User
+	err = inTransaction(s.tx, s, s.bind, func(s *oidcService) (err error) {
+		user, err = s.findOrLinkUser(claims)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// findOrLinkUser finds the user of an identity, linking the subject to the user with the verified email on first login.
+// Users deactivated through SCIM are not provisioned any more.
+func (s *oidcService) findOrLinkUser(claims *oidc.Claims) (*entity.User, error) {
+	user, err := s.userRepo.FindByOIDCSubject(claims.Subject)
+	if err != nil {
+		return nil, err
+	}
+	if user != nil && user.DeactivatedAt != nil {
+		return nil, ErrUserNotProvisioned
+	}
+	if user != nil {
+		return user, nil
+	}
+
+	if claims.Email == "" || !claims.EmailVerified {
+		return nil, ErrUserNotProvisioned
+	}
+	user, err = s.userRepo.FindByEmail(claims.Email)
+	if err != nil {
+		return nil, err
+	}
+	// A user already linked to another subject must not be taken over by email
+	if user == nil || user.OIDCSubject != nil || user.DeactivatedAt != nil {
+		return nil, ErrUserNotProvisioned
+	}
+
+	if err := s.userRepo.LinkOIDCSubject(user.ID, claims.Subject); err != nil {
+		return nil, err
+	}
+	user.OIDCSubject = &claims.Subject
+	return user, nil
+}
@@ -0,0 +1,113 @@
+package service
+
+import (
+	"context"
+	"hub_management_service/internal/entity"
+	"hub_management_service/internal/repository/mocks"
+	"hub_management_service/pkg/oidc"
+	"hub_management_service/pkg/oidc/oidctest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// loginWithMockIdP runs the authorization code flow against a mock identity provider
+func loginWithMockIdP(t *testing.T, idp *oidctest.Server, userRepo *mocks.UserRepository) (*entity.User, error) {
+	client := oidc.NewClient(oidc.Config{Issuer: idp.URL, ClientID: idp.ClientID, RedirectURL: "http://localhost/callback"}, nil)
+	service := NewOIDCService(nil, client, userRepo)
+	ctx := context.Background()
+
+	login, err := service.BeginLogin(ctx)
+	assert.NoError(t, err)
+
+	redirect, err := idp.Authorize(login.AuthURL)
+	assert.NoError(t, err)
+	assert.Equal(t, login.State, redirect.Query().Get("state"))
+
+	return service.CompleteLogin(ctx, redirect.Query().Get("code"), login.CodeVerifier, login.Nonce)
+}
+
+// TestCompleteLogin_LinkedSubject tests that a user already linked to the subject is returned
+func TestCompleteLogin_LinkedSubject(t *testing.T) {
+	idp := oidctest.NewServer("hub-service")
+	defer idp.Close()
+	mockUserRepo := new(mocks.UserRepository)
+
+	subject := "user-1"
+	mockUserRepo.On("FindByOIDCSubject", "user-1").Return(&entity.User{ID: 7, OIDCSubject: &subject}, nil)
+
+	user, err := loginWithMockIdP(t, idp, mockUserRepo)
+
+	assert.NoError(t, err)
+	assert.Equal(t, uint(7), user.ID)
+	mockUserRepo.AssertExpectations(t)
+}
+
+// TestCompleteLogin_LinksByEmail tests that the first login links the subject to the user with the same email
+func TestCompleteLogin_LinksByEmail(t *testing.T) {
+	idp := oidctest.NewServer("hub-service")
+	defer idp.Close()
+	mockUserRepo := new(mocks.UserRepository)
+
+	mockUserRepo.On("FindByOIDCSubject", "user-1").Return(nil, nil)
+	mockUserRepo.On("FindByEmail", "user@example.com").Return(&entity.User{ID: 7, Email: "user@example.com"}, nil)
+	mockUserRepo.On("LinkOIDCSubject", uint(7), "user-1").Return(nil)
+
+	user, err := loginWithMockIdP(t, idp, mockUserRepo)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "user-1", *user.OIDCSubject)
+	mockUserRepo.AssertExpectations(t)
+}
+
+// TestCompleteLogin_NotProvisioned tests that unknown identities are rejected
+func TestCompleteLogin_NotProvisioned(t *testing.T) {
+	idp := oidctest.NewServer("hub-service")
+	defer idp.Close()
+	mockUserRepo := new(mocks.UserRepository)
+
+	mockUserRepo.On("FindByOIDCSubject", "user-1").Return(nil, nil)
+	mockUserRepo.On("FindByEmail", "user@example.com").Return(nil, nil)
+
+	_, err := loginWithMockIdP(t, idp, mockUserRepo)
+
+	assert.ErrorIs(t, err, ErrUserNotProvisioned)
+	mockUserRepo.AssertNotCalled(t, "LinkOIDCSubject", mock.Anything, mock.Anything)
+}
+
+// TestCompleteLogin_EmailLinkedToOtherSubject tests that an email match cannot take over a linked user
+func TestCompleteLogin_EmailLinkedToOtherSubject(t *testing.T) {
+	idp := oidctest.NewServer("hub-service")
+	defer idp.Close()
+	mockUserRepo := new(mocks.UserRepository)
+
+	other := "someone-else"
+	mockUserRepo.On("FindByOIDCSubject", "user-1").Return(nil, nil)
+	mockUserRepo.On("FindByEmail", "user@example.com").Return(&entity.User{ID: 7, OIDCSubject: &other}, nil)
+
+	_, err := loginWithMockIdP(t, idp, mockUserRepo)
+
+	assert.ErrorIs(t, err, ErrUserNotProvisioned)
+}
+
+// TestCompleteLogin_Deactivated tests that users deactivated through SCIM cannot sign in, linked or not
+func TestCompleteLogin_Deactivated(t *testing.T) {
+	idp := oidctest.NewServer("hub-service")
+	defer idp.Close()
+	deactivatedAt := time.Now()
+
+	subject := "user-1"
+	linked := new(mocks.UserRepository)
+	linked.On("FindByOIDCSubject", "user-1").Return(&entity.User{ID: 7, OIDCSubject: &subject, DeactivatedAt: &deactivatedAt}, nil)
+	_, err := loginWithMockIdP(t, idp, linked)
+	assert.ErrorIs(t, err, ErrUserNotProvisioned)
+
+	unlinked := new(mocks.UserRepository)
+	unlinked.On("FindByOIDCSubject", "user-1").Return(nil, nil)
+	unlinked.On("FindByEmail", "user@example.com").Return(&entity.User{ID: 7, Email: "user@example.com", DeactivatedAt: &deactivatedAt}, nil)
+	_, err = loginWithMockIdP(t, idp, unlinked)
+	assert.ErrorIs(t, err, ErrUserNotProvisioned)
+	unlinked.AssertNotCalled(t, "LinkOIDCSubject", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,153 @@
+package service
+
+import (
+	"fmt"
+	"hub_management_service/internal/entity"
+	"hub_management_service/internal/repository"
+	"hub_management_service/pkg/orgchart"
+	"sort"
+)
+
+type OrgChartService interface {
+	HubChart(hubID uint, managers bool) (*orgchart.Chart, error)
+	TeamChart(teamID uint, managers bool) (*orgchart.Chart, error)
+}
+
+type orgChartService struct {
+	hubRepo  repository.HubRepository
+	teamRepo repository.TeamRepository
+	userRepo repository.UserRepository
+}
+
+func NewOrgChartService(hubRepo repository.HubRepository, teamRepo repository.TeamRepository, userRepo repository.UserRepository) OrgChartService {
+	return &orgChartService{hubRepo: hubRepo, teamRepo: teamRepo, userRepo: userRepo}
+}
+
+// HubChart charts a hub with its teams, their sub-teams and their active users, and optionally manager lines
+func (s *orgChartService) HubChart(hubID uint, managers bool) (*orgchart.Chart, error) {
+	hub, err := s.hubRepo.FindByID(hubID)
+	if repository.IsNotFound(err) || (err == nil && hub == nil) {
+		return nil, ErrHubNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	teams, err := s.teamRepo.FindByHubID(hub.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	root := &orgchart.Node{ID: fmt.Sprintf("hub_%d", hub.ID), Kind: orgchart.KindHub, Label: hub.Name, Detail: hub.Location}
+	return s.build(root, 0, teams, managers)
+}
+
+// TeamChart charts a team with its sub-teams and their active users, and optionally manager lines
+func (s *orgChartService) TeamChart(teamID uint, managers bool) (*orgchart.Chart, error) {
+	team, err := s.teamRepo.FindByID(teamID)
+	if repository.IsNotFound(err) || (err == nil && team == nil) {
+		return nil, ErrTeamNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	descendants, err := s.teamRepo.FindDescendants(team.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.build(nil, team.ID, append([]entity.Team{*team}, descendants...), managers)
+}
+
+// build places the teams under the root, or under the root team when root is nil, with their users as members.
+// Teams whose parent is outside of the chart, or part of a cycle, hang from the root.
+func (s *orgChartService) build(root *orgchart.Node, rootTeamID uint, teams []entity.Team, managers bool) (*orgchart.Chart, error) {
+	sort.Slice(teams, func(i, j int) bool {
+		if teams[i].Name != teams[j].Name {
+			return teams[i].Name < teams[j].Name
+		}
+		return teams[i].ID < teams[j].ID
+	})
+	teamIDs := make([]uint, len(teams))
+	byID := make(map[uint]*entity.Team, len(teams))
+	nodes := make(map[uint]*orgchart.Node, len(teams))
+	for i := range teams {
+		teamIDs[i] = teams[i].ID
+		byID[teams[i].ID] = &teams[i]
+		nodes[teams[i].ID] = &orgchart.Node{ID: fmt.Sprintf("team_%d", teams[i].ID), Kind: orgchart.KindTeam, Label: teams[i].Name}
+	}
+	if root == nil {
+		root = nodes[rootTeamID]
+	}
+
+	var users []entity.User
+	if len(teamIDs) > 0 {
+		var err error
+		users, _, err = s.userRepo.List(repository.ListOptions{Where: "team_id IN ? AND deactivated_at IS NULL", Args: []interface{}{teamIDs}})
+		if err != nil {
+			return nil, err
+		}
+	}
+	sort.SliceStable(users, func(i, j int) bool { return users[i].Name < users[j].Name })
+	inChart := make(map[uint]bool, len(users))
+	for _, user := range users {
+		inChart[user.ID] = true
+		team := nodes[user.TeamID]
+		team.Members = append(team.Members, &orgchart.Node{ID: fmt.Sprintf("user_%d", user.ID), Kind: orgchart.KindUser, Label: user.Name, Detail: user.JobTitle})
+	}
+
+	for _, team := range teams {
+		node := nodes[team.ID]
+		node.Detail = headcountLabel(len(node.Members))
+		if team.ID == rootTeamID {
+			continue
+		}
+		if parent := chartParent(&team, byID, rootTeamID); parent != 0 {
+			nodes[parent].Children = append(nodes[parent].Children, node)
+		} else {
+			root.Children = append(root.Children, node)
+		}
+	}
+
+	chart := &orgchart.Chart{Root: root}
+	if managers {
+		for _, user := range users {
+			if user.ManagerID != nil && inChart[*user.ManagerID] {
+				chart.Lines = append(chart.Lines, orgchart.Line{From: fmt.Sprintf("user_%d", *user.ManagerID), To: fmt.Sprintf("user_%d", user.ID)})
+			}
+		}
+	}
+	return chart, nil
+}
+
+// chartParent returns the parent of a team within the chart, or 0 when it hangs from the root
+func chartParent(team *entity.Team, teams map[uint]*entity.Team, rootTeamID uint) uint {
+	if team.ParentID == nil || teams[*team.ParentID] == nil {
+		return 0
+	}
+	// Follow the parents up to the top to make sure the team is not part of a cycle. A cycle further up
+	// does not matter, the team stays under its parent which is itself hung from the root.
+	visited := map[uint]bool{team.ID: true}
+	current := team
+	for current.ParentID != nil && current.ID != rootTeamID {
+		parent := teams[*current.ParentID]
+		if parent == nil {
+			break
+		}
+		if parent.ID == team.ID {
+			return 0
+		}
+		if visited[parent.ID] {
+			break
+		}
+		visited[parent.ID] = true
+		current = parent
+	}
+	return *team.ParentID
+}
+
+func headcountLabel(n int) string {
+	if n == 1 {
+		return "1 person"
+	}
+	return fmt.Sprintf("%d people", n)
+}
@@ -0,0 +1,98 @@
+package service
+
+import (
+	"gorm.io/gorm"
+	"hub_management_service/internal/entity"
+	"hub_management_service/internal/repository"
+	"hub_management_service/internal/repository/mocks"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func uintPtr(n uint) *uint {
+	return &n
+}
+
+// newTestOrgChartService sets up a hub with Backend, its sub-team Payments and a top-level Design team
+func newTestOrgChartService() (OrgChartService, *mocks.UserRepository) {
+	hubRepo := new(mocks.HubRepository)
+	teamRepo := new(mocks.TeamRepository)
+	userRepo := new(mocks.UserRepository)
+	hubRepo.On("FindByID", uint(1)).Return(&entity.Hub{ID: 1, Name: "Paris Hub", Location: "Paris"}, nil)
+	hubRepo.On("FindByID", uint(9)).Return(nil, gorm.ErrRecordNotFound)
+	teamRepo.On("FindByHubID", uint(1)).Return([]entity.Team{
+		{ID: 3, Name: "Payments", HubID: 1, ParentID: uintPtr(2)},
+		{ID: 4, Name: "Design", HubID: 1},
+		{ID: 2, Name: "Backend", HubID: 1},
+	}, nil)
+	teamRepo.On("FindByID", uint(2)).Return(&entity.Team{ID: 2, Name: "Backend", HubID: 1}, nil)
+	teamRepo.On("FindDescendants", uint(2)).Return([]entity.Team{{ID: 3, Name: "Payments", HubID: 1, ParentID: uintPtr(2)}}, nil)
+	return NewOrgChartService(hubRepo, teamRepo, userRepo), userRepo
+}
+
+// TestHubChart tests that teams nest under their parents with their users as members, and that managers are linked
+func TestHubChart(t *testing.T) {
+	service, userRepo := newTestOrgChartService()
+	userRepo.On("List", repository.ListOptions{Where: "team_id IN ? AND deactivated_at IS NULL", Args: []interface{}{[]uint{2, 4, 3}}}).
+		Return([]entity.User{
+			{ID: 7, Name: "Zoe", TeamID: 2, JobTitle: "Engineering Manager"},
+			{ID: 8, Name: "Adam", TeamID: 2, ManagerID: uintPtr(7)},
+			{ID: 9, Name: "Jane", TeamID: 3, ManagerID: uintPtr(7)},
+			{ID: 10, Name: "Ann", TeamID: 4, ManagerID: uintPtr(99)}, // Manager outside of the chart
+		}, int64(4), nil)
+
+	chart, err := service.HubChart(1, true)
+	assert.NoError(t, err)
+	assert.Equal(t, "hub_1", chart.Root.ID)
+	if assert.Len(t, chart.Root.Children, 2) {
+		backend := chart.Root.Children[0]
+		assert.Equal(t, "Backend", backend.Label)
+		assert.Equal(t, "2 people", backend.Detail)
+		assert.Equal(t, "user_8", backend.Members[0].ID) // Ordered by name
+		assert.Equal(t, "Engineering Manager", backend.Members[1].Detail)
+		if assert.Len(t, backend.Children, 1) {
+			assert.Equal(t, "1 person", backend.Children[0].Detail)
+		}
+		assert.Equal(t, "Design", chart.Root.Children[1].Label)
+	}
+	assert.Len(t, chart.Lines, 2)
+
+	chart, err = service.HubChart(1, false)
+	assert.NoError(t, err)
+	assert.Empty(t, chart.Lines)
+
+	_, err = service.HubChart(9, true)
+	assert.ErrorIs(t, err, ErrHubNotFound)
+}
+
+// TestTeamChart tests that a team chart is rooted at the team
+func TestTeamChart(t *testing.T) {
+	service, userRepo := newTestOrgChartService()
+	userRepo.On("List", repository.ListOptions{Where: "team_id IN ? AND deactivated_at IS NULL", Args: []interface{}{[]uint{2, 3}}}).
+		Return([]entity.User{{ID: 9, Name: "Jane", TeamID: 3}}, int64(1), nil)
+
+	chart, err := service.TeamChart(2, true)
+	assert.NoError(t, err)
+	assert.Equal(t, "team_2", chart.Root.ID)
+	assert.Equal(t, "0 people", chart.Root.Detail)
+	if assert.Len(t, chart.Root.Children, 1) {
+		assert.Equal(t, "Jane", chart.Root.Children[0].Members[0].Label)
+	}
+}
+
+// TestChartParent tests that teams in a parent cycle hang from the root instead of disappearing
+func TestChartParent(t *testing.T) {
+	teams := map[uint]*entity.Team{
+		1: {ID: 1, ParentID: uintPtr(2)},
+		2: {ID: 2, ParentID: uintPtr(1)},
+		3: {ID: 3, ParentID: uintPtr(1)},
+		4: {ID: 4, ParentID: uintPtr(40)},
+		5: {ID: 5, ParentID: uintPtr(3)},
+	}
+	assert.Equal(t, uint(0), chartParent(teams[1], teams, 0))
+	assert.Equal(t, uint(0), chartParent(teams[2], teams, 0))
+	assert.Equal(t, uint(1), chartParent(teams[3], teams, 0))
+	assert.Equal(t, uint(0), chartParent(teams[4], teams, 0))
+	assert.Equal(t, uint(3), chartParent(teams[5], teams, 0))
+}
@@ -0,0 +1,77 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hub_management_service/pkg/jsonpatch"
+	"reflect"
+)
+
+// PatchFormat is the media type of a patch document
+type PatchFormat string
+
+const (
+	MergePatch PatchFormat = "application/merge-patch+json" // RFC 7396
+	JSONPatch  PatchFormat = "application/json-patch+json"  // RFC 6902
+)
+
+var (
+	ErrInvalidPatch       = errors.New("invalid patch document")
+	ErrPatchConflict      = errors.New("patch does not apply")
+	ErrInvalidPatchResult = errors.New("patched record is invalid")
+)
+
+// applyPatch applies a patch document to the JSON of record, as returned by the API, and decodes the result into
+// patched. The result must keep the readOnly fields as they were and satisfy the binding rules of the record, so
+// that a patch is held to the same rules as a full body.
+func applyPatch(format PatchFormat, record interface{}, patch []byte, patched interface{}, readOnly ...string) error {
+	doc, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	var result []byte
+	switch format {
+	case MergePatch:
+		result, err = jsonpatch.MergePatch(doc, patch)
+	case JSONPatch:
+		result, err = jsonpatch.Apply(doc, patch)
+	default:
+		return fmt.Errorf("%w: unsupported format %q", ErrInvalidPatch, format)
+	}
+	if errors.Is(err, jsonpatch.ErrInvalidPatch) {
+		return fmt.Errorf("%w: %v", ErrInvalidPatch, err)
+	}
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrPatchConflict, err)
+	}
+
+	var before, after map[string]interface{}
+	if err := json.Unmarshal(doc, &before); err != nil {
+		return err
+	}
+	if err := json.Unmarshal(result, &after); err != nil {
+		return fmt.Errorf("%w: the result must be an object", ErrInvalidPatchResult)
+	}
+	for _, name := range readOnly {
+		if !reflect.DeepEqual(before[name], after[name]) {
+			return fmt.Errorf("%w: %s cannot be changed", ErrInvalidPatchResult, name)
+		}
+	}
+
+	if err := json.Unmarshal(result, patched); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidPatchResult, err)
+	}
+	if err := (&importFields{}).validate(patched); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidPatchResult, err)
+	}
+	return nil
+}
+
+// sameID tells whether two optional IDs are both unset or equal
+func sameID(a, b *uint) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
@@ -0,0 +1,241 @@
+package service
+
+import (
+	"errors"
+	"hub_management_service/internal/entity"
+	"hub_management_service/internal/repository"
+	"regexp"
+	"strings"
+)
+
+var (
+	ErrRegionNotFound        = errors.New("region does not exist")
+	ErrRegionNameTaken       = errors.New("region name is already in use")
+	ErrRegionInUse           = errors.New("region still has countries")
+	ErrCountryNotFound       = errors.New("country does not exist")
+	ErrCountryRegionNotFound = errors.New("region of the country does not exist")
+	ErrInvalidCountryCode    = errors.New("country code must be an ISO 3166-1 alpha-2 code")
+	ErrCountryCodeTaken      = errors.New("country code is already in use")
+	ErrCountryInUse          = errors.New("country still has hubs")
+)
+
+var countryCodePattern = regexp.MustCompile(`^[A-Z]{2}$`)
+
+type RegionService interface {
+	ListRegions() ([]entity.Region, error)
+	FindRegion(id uint) (*entity.Region, error)
+	CreateRegion(region *entity.Region) error
+	UpdateRegion(id uint, region *entity.Region) (*entity.Region, error)
+	DeleteRegion(id uint) error
+	Rollup() ([]repository.RegionRollup, error)
+	ListCountries(regionID uint) ([]entity.Country, error)
+	CreateCountry(country *entity.Country) error
+	UpdateCountry(id uint, country *entity.Country) (*entity.Country, error)
+	DeleteCountry(id uint) error
+}
+
+type regionService struct {
+	tx          repository.Transactor
+	repo        repository.RegionRepository
+	countryRepo repository.CountryRepository
+	hubRepo     repository.HubRepository
+}
+
+func NewRegionService(tx repository.Transactor, repo repository.RegionRepository, countryRepo repository.CountryRepository, hubRepo repository.HubRepository) RegionService {
+	return &regionService{tx: tx, repo: repo, countryRepo: countryRepo, hubRepo: hubRepo}
+}
+
+// bind returns the service working with the repositories of a transaction
+func (s *regionService) bind(repos repository.Repositories) *regionService {
+	return &regionService{tx: repos, repo: repos.Regions, countryRepo: repos.Countries, hubRepo: repos.Hubs}
+}
+
+// ListRegions returns all regions, ordered by name
+func (s *regionService) ListRegions() ([]entity.Region, error) {
+	return s.repo.FindAll()
+}
+
+// FindRegion returns a region along with its countries
+func (s *regionService) FindRegion(id uint) (*entity.Region, error) {
+	return s.findRegion(id)
+}
+
+// CreateRegion creates a region, its name must be unique regardless of case
+func (s *regionService) CreateRegion(region *entity.Region) error {
+	region.Name = strings.TrimSpace(region.Name)
+	var writeErr error
+	err := inTransaction(s.tx, s, s.bind, func(s *regionService) error {
+		if err := s.checkRegionName(region); err != nil {
+			return err
+		}
+		writeErr = s.repo.Create(region)
+		return writeErr
+	})
+	if writeErr != nil {
+		return s.nameConflict(region, err)
+	}
+	return err
+}
+
+// UpdateRegion renames a region
+func (s *regionService) UpdateRegion(id uint, update *entity.Region) (*entity.Region, error) {
+	var region *entity.Region
+	var writeErr error
+	err := inTransaction(s.tx, s, s.bind, func(s *regionService) (err error) {
+		if region, err = s.findRegion(id); err != nil {
+			return err
+		}
+		region.Name = strings.TrimSpace(update.Name)
+		if err := s.checkRegionName(region); err != nil {
+			return err
+		}
+		writeErr = s.repo.Update(region)
+		return writeErr
+	})
+	if writeErr != nil {
+		return nil, s.nameConflict(region, err)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return region, nil
+}
+
+// DeleteRegion deletes a region that no longer has countries
+func (s *regionService) DeleteRegion(id uint) error {
+	return inTransaction(s.tx, s, s.bind, func(s *regionService) error {
+		region, err := s.findRegion(id)
+		if err != nil {
+			return err
+		}
+		if len(region.Countries) > 0 {
+			return ErrRegionInUse
+		}
+		return s.repo.Delete(id)
+	})
+}
+
+// Rollup counts the countries, hubs, teams and users of every region
+func (s *regionService) Rollup() ([]repository.RegionRollup, error) {
+	return s.repo.Rollup()
+}
+
+// ListCountries returns the countries of a region or, with a zero regionID, all countries
+func (s *regionService) ListCountries(regionID uint) ([]entity.Country, error) {
+	if regionID == 0 {
+		return s.countryRepo.FindAll()
+	}
+	if _, err := s.findRegion(regionID); err != nil {
+		return nil, err
+	}
+	return s.countryRepo.FindByRegionID(regionID)
+}
+
+// CreateCountry adds a country to a region
+func (s *regionService) CreateCountry(country *entity.Country) error {
+	return inTransaction(s.tx, s, s.bind, func(s *regionService) error {
+		if err := s.normalizeCountry(country); err != nil {
+			return err
+		}
+		return s.countryRepo.Create(country)
+	})
+}
+
+// UpdateCountry renames a country or moves it to another region
+func (s *regionService) UpdateCountry(id uint, update *entity.Country) (*entity.Country, error) {
+	var country *entity.Country
+	err := inTransaction(s.tx, s, s.bind, func(s *regionService) (err error) {
+		if country, err = s.findCountry(id); err != nil {
+			return err
+		}
+		country.Code = update.Code
+		country.Name = update.Name
+		country.RegionID = update.RegionID
+		country.Region = nil
+		if err := s.normalizeCountry(country); err != nil {
+			return err
+		}
+		return s.countryRepo.Update(country)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return country, nil
+}
+
+// DeleteCountry deletes a country that no longer has hubs
+func (s *regionService) DeleteCountry(id uint) error {
+	return inTransaction(s.tx, s, s.bind, func(s *regionService) error {
+		if _, err := s.findCountry(id); err != nil {
+			return err
+		}
+		_, hubs, err := s.hubRepo.List(repository.ListOptions{Where: "country_id = ?", Args: []interface{}{id}, Limit: 1})
+		if err != nil {
+			return err
+		}
+		if hubs > 0 {
+			return ErrCountryInUse
+		}
+		return s.countryRepo.Delete(id)
+	})
+}
+
+// normalizeCountry upper-cases the country code and checks it is unique and the region exists
+func (s *regionService) normalizeCountry(country *entity.Country) error {
+	country.Code = strings.ToUpper(strings.TrimSpace(country.Code))
+	country.Name = strings.TrimSpace(country.Name)
+	if !countryCodePattern.MatchString(country.Code) {
+		return ErrInvalidCountryCode
+	}
+	_, err := s.findRegion(country.RegionID)
+	if errors.Is(err, ErrRegionNotFound) {
+		return ErrCountryRegionNotFound
+	}
+	if err != nil {
+		return err
+	}
+	existing, err := s.countryRepo.FindByCode(country.Code)
+	if err != nil {
+		return err
+	}
+	if existing != nil && existing.ID != country.ID {
+		return ErrCountryCodeTaken
+	}
+	return nil
+}
+
+func (s *regionService) checkRegionName(region *entity.Region) error {
+	existing, err := s.repo.FindByName(region.Name)
+	if err != nil {
+		return err
+	}
+	if existing != nil && existing.ID != region.ID {
+		return ErrRegionNameTaken
+	}
+	return nil
+}
+
+// nameConflict reports a write that failed after the name check as a taken name when a concurrent request took the
+// name meanwhile, caught by the unique index on LOWER(name), or else returns err
+func (s *regionService) nameConflict(region *entity.Region, err error) error {
+	if conflictErr := s.checkRegionName(region); conflictErr != nil {
+		return conflictErr
+	}
+	return err
+}
+
+func (s *regionService) findRegion(id uint) (*entity.Region, error) {
+	region, err := s.repo.FindByID(id)
+	if repository.IsNotFound(err) || (err == nil && region == nil) {
+		return nil, ErrRegionNotFound
+	}
+	return region, err
+}
+
+func (s *regionService) findCountry(id uint) (*entity.Country, error) {
+	country, err := s.countryRepo.FindByID(id)
+	if repository.IsNotFound(err) || (err == nil && country == nil) {
+		return nil, ErrCountryNotFound
+	}
+	return country, err
+}
@@ -0,0 +1,99 @@
+package service
+
+import (
+	"errors"
+	"hub_management_service/internal/entity"
+	"hub_management_service/internal/repository"
+	"hub_management_service/internal/repository/mocks"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func newTestRegionService() (RegionService, *mocks.RegionRepository, *mocks.CountryRepository, *mocks.HubRepository) {
+	regionRepo := new(mocks.RegionRepository)
+	countryRepo := new(mocks.CountryRepository)
+	hubRepo := new(mocks.HubRepository)
+	return NewRegionService(nil, regionRepo, countryRepo, hubRepo), regionRepo, countryRepo, hubRepo
+}
+
+// TestCreateRegion_NameTaken tests that region names are unique regardless of case
+func TestCreateRegion_NameTaken(t *testing.T) {
+	service, regionRepo, _, _ := newTestRegionService()
+
+	regionRepo.On("FindByName", "emea").Return(&entity.Region{ID: 1, Name: "EMEA"}, nil)
+
+	err := service.CreateRegion(&entity.Region{Name: " emea "})
+
+	assert.ErrorIs(t, err, ErrRegionNameTaken)
+	regionRepo.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+// TestCreateRegion_ConcurrentName tests that a name taken in another case meanwhile, caught by the unique index, is
+// reported as taken
+func TestCreateRegion_ConcurrentName(t *testing.T) {
+	service, regionRepo, _, _ := newTestRegionService()
+
+	regionRepo.On("FindByName", "emea").Return(nil, nil).Once()
+	regionRepo.On("FindByName", "emea").Return(&entity.Region{ID: 1, Name: "EMEA"}, nil).Once()
+	regionRepo.On("Create", mock.AnythingOfType("*entity.Region")).Return(errors.New("duplicate key value violates unique constraint"))
+
+	err := service.CreateRegion(&entity.Region{Name: "emea"})
+
+	assert.ErrorIs(t, err, ErrRegionNameTaken)
+	regionRepo.AssertExpectations(t)
+}
+
+// TestDeleteRegion_InUse tests that a region with countries cannot be deleted
+func TestDeleteRegion_InUse(t *testing.T) {
+	service, regionRepo, _, _ := newTestRegionService()
+
+	regionRepo.On("FindByID", uint(1)).Return(&entity.Region{ID: 1, Name: "EMEA", Countries: []entity.Country{{ID: 1, Code: "FR"}}}, nil)
+
+	assert.ErrorIs(t, service.DeleteRegion(1), ErrRegionInUse)
+	regionRepo.AssertNotCalled(t, "Delete", mock.Anything)
+}
+
+// TestCreateCountry_Success tests that country codes are upper-cased
+func TestCreateCountry_Success(t *testing.T) {
+	service, regionRepo, countryRepo, _ := newTestRegionService()
+
+	regionRepo.On("FindByID", uint(1)).Return(&entity.Region{ID: 1, Name: "EMEA"}, nil)
+	countryRepo.On("FindByCode", "FR").Return(nil, nil)
+	countryRepo.On("Create", mock.AnythingOfType("*entity.Country")).Return(nil)
+
+	country := &entity.Country{Code: "fr", Name: "France", RegionID: 1}
+	err := service.CreateCountry(country)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "FR", country.Code)
+	countryRepo.AssertExpectations(t)
+}
+
+// TestCreateCountry_Invalid tests the validation of codes and regions
+func TestCreateCountry_Invalid(t *testing.T) {
+	service, regionRepo, countryRepo, _ := newTestRegionService()
+
+	regionRepo.On("FindByID", uint(9)).Return(nil, gorm.ErrRecordNotFound)
+	regionRepo.On("FindByID", uint(1)).Return(&entity.Region{ID: 1, Name: "EMEA"}, nil)
+	countryRepo.On("FindByCode", "DE").Return(&entity.Country{ID: 2, Code: "DE"}, nil)
+
+	assert.ErrorIs(t, service.CreateCountry(&entity.Country{Code: "FRA", Name: "France", RegionID: 1}), ErrInvalidCountryCode)
+	assert.ErrorIs(t, service.CreateCountry(&entity.Country{Code: "FR", Name: "France", RegionID: 9}), ErrCountryRegionNotFound)
+	assert.ErrorIs(t, service.CreateCountry(&entity.Country{Code: "DE", Name: "Germany", RegionID: 1}), ErrCountryCodeTaken)
+	countryRepo.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+// TestDeleteCountry_InUse tests that a country with hubs cannot be deleted
+func TestDeleteCountry_InUse(t *testing.T) {
+	service, _, countryRepo, hubRepo := newTestRegionService()
+
+	countryRepo.On("FindByID", uint(1)).Return(&entity.Country{ID: 1, Code: "FR"}, nil)
+	hubRepo.On("List", repository.ListOptions{Where: "country_id = ?", Args: []interface{}{uint(1)}, Limit: 1}).
+		Return([]entity.Hub{{ID: 3}}, int64(1), nil)
+
+	assert.ErrorIs(t, service.DeleteCountry(1), ErrCountryInUse)
+	countryRepo.AssertNotCalled(t, "Delete", mock.Anything)
+}
@@ -0,0 +1,282 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"hub_management_service/internal/entity"
+	"hub_management_service/internal/repository"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+var (
+	ErrResourceNotFound    = errors.New("resource does not exist")
+	ErrInvalidResourceType = errors.New("resource type must be desk or room")
+	ErrInvalidAmenity      = errors.New("amenities must be lowercase letters, digits, dashes or underscores, at most 64 characters")
+	ErrResourceNameTaken   = errors.New("hub already has a resource with that name")
+)
+
+// Page size limits of ListResources, a hub detail view lists up to MaxResourceListLimit resources
+const (
+	defaultResourceListLimit = 100
+	MaxResourceListLimit     = 500
+)
+
+// amenityPattern matches a normalized amenity name such as video-conferencing or standing_desk
+var amenityPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,63}$`)
+
+// ResourceQuery filters the resources of a hub, empty fields are ignored
+type ResourceQuery struct {
+	Type        string
+	Floor       string
+	Amenities   []string // Resources must have all of them
+	MinCapacity int
+	Offset      int
+	Limit       int
+}
+
+type ResourceService interface {
+	CreateResource(hubID uint, resource *entity.Resource) error
+	FindResource(id uint) (*entity.Resource, error)
+	ListResources(hubID uint, query ResourceQuery) ([]entity.Resource, int64, error)
+	UpdateResource(id uint, update *entity.Resource) (*entity.Resource, error)
+	DeleteResource(id uint) error
+}
+
+type resourceService struct {
+	tx      repository.Transactor
+	repo    repository.ResourceRepository
+	hubRepo repository.HubRepository
+}
+
+func NewResourceService(tx repository.Transactor, repo repository.ResourceRepository, hubRepo repository.HubRepository) ResourceService {
+	return &resourceService{tx: tx, repo: repo, hubRepo: hubRepo}
+}
+
+// bind returns the service working with the repositories of a transaction
+func (s *resourceService) bind(repos repository.Repositories) *resourceService {
+	return &resourceService{tx: repos, repo: repos.Resources, hubRepo: repos.Hubs}
+}
+
+// CreateResource adds a desk or room to a hub
+func (s *resourceService) CreateResource(hubID uint, resource *entity.Resource) error {
+	var writeErr error
+	err := inTransaction(s.tx, s, s.bind, func(s *resourceService) error {
+		if err := s.checkNewResource(hubID, resource); err != nil {
+			return err
+		}
+		writeErr = s.repo.Create(resource)
+		return writeErr
+	})
+	if writeErr != nil {
+		return s.nameConflict(resource, err)
+	}
+	return err
+}
+
+// checkNewResource normalizes a new resource of a hub and checks that it can be created
+func (s *resourceService) checkNewResource(hubID uint, resource *entity.Resource) error {
+	hub, err := s.hubRepo.FindByID(hubID)
+	if repository.IsNotFound(err) || (err == nil && hub == nil) {
+		return ErrHubNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	resource.ID = 0
+	resource.HubID = hubID
+	if err := normalizeResource(resource); err != nil {
+		return err
+	}
+	return s.checkName(resource)
+}
+
+// nameConflict reports a failed write as ErrResourceNameTaken when a concurrent write took the name of the resource,
+// which the unique index rejected. The check runs after the rollback, a failed statement aborts the transaction on
+// Postgres.
+func (s *resourceService) nameConflict(resource *entity.Resource, err error) error {
+	if conflictErr := s.checkName(resource); conflictErr != nil {
+		return conflictErr
+	}
+	return err
+}
+
+// FindResource returns a resource with its amenities
+func (s *resourceService) FindResource(id uint) (*entity.Resource, error) {
+	resource, err := s.repo.FindByID(id)
+	if repository.IsNotFound(err) || (err == nil && resource == nil) {
+		return nil, ErrResourceNotFound
+	}
+	return resource, err
+}
+
+// ListResources returns a page of the resources of a hub matching the query, along with the total number of matches
+func (s *resourceService) ListResources(hubID uint, query ResourceQuery) ([]entity.Resource, int64, error) {
+	hub, err := s.hubRepo.FindByID(hubID)
+	if repository.IsNotFound(err) || (err == nil && hub == nil) {
+		return nil, 0, ErrHubNotFound
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+
+	opts, err := resourceListOptions(hubID, query)
+	if err != nil {
+		return nil, 0, err
+	}
+	return s.repo.List(opts)
+}
+
+// UpdateResource replaces the fields and amenities of a resource, it stays in its hub
+func (s *resourceService) UpdateResource(id uint, update *entity.Resource) (*entity.Resource, error) {
+	var resource *entity.Resource
+	var writeErr error
+	err := inTransaction(s.tx, s, s.bind, func(s *resourceService) (err error) {
+		if resource, err = s.checkUpdate(id, update); err != nil {
+			return err
+		}
+		writeErr = s.repo.Update(resource)
+		return writeErr
+	})
+	if writeErr != nil {
+		return nil, s.nameConflict(resource, err)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return resource, nil
+}
+
+// checkUpdate copies the fields of update onto the resource, normalizes and checks them
+func (s *resourceService) checkUpdate(id uint, update *entity.Resource) (*entity.Resource, error) {
+	resource, err := s.FindResource(id)
+	if err != nil {
+		return nil, err
+	}
+
+	resource.Type = update.Type
+	resource.Name = update.Name
+	resource.Floor = update.Floor
+	resource.Capacity = update.Capacity
+	resource.Amenities = update.Amenities
+	if err := normalizeResource(resource); err != nil {
+		return nil, err
+	}
+	if err := s.checkName(resource); err != nil {
+		return nil, err
+	}
+	return resource, nil
+}
+
+// DeleteResource removes a resource from its hub
+func (s *resourceService) DeleteResource(id uint) error {
+	return inTransaction(s.tx, s, s.bind, func(s *resourceService) error {
+		if _, err := s.FindResource(id); err != nil {
+			return err
+		}
+		return s.repo.Delete(id)
+	})
+}
+
+// checkName returns ErrResourceNameTaken if another resource of the hub has the same name
+func (s *resourceService) checkName(resource *entity.Resource) error {
+	existing, err := s.repo.FindByName(resource.HubID, resource.Name)
+	if err != nil {
+		return err
+	}
+	if existing != nil && existing.ID != resource.ID {
+		return ErrResourceNameTaken
+	}
+	return nil
+}
+
+// resourceListOptions turns a query into the conditions and paging of a resource listing
+func resourceListOptions(hubID uint, query ResourceQuery) (repository.ListOptions, error) {
+	conditions := []string{"hub_id = ?"}
+	args := []interface{}{hubID}
+	if query.Type != "" {
+		if query.Type != entity.ResourceDesk && query.Type != entity.ResourceRoom {
+			return repository.ListOptions{}, ErrInvalidResourceType
+		}
+		conditions = append(conditions, "type = ?")
+		args = append(args, query.Type)
+	}
+	if floor := strings.TrimSpace(query.Floor); floor != "" {
+		conditions = append(conditions, "floor = ?")
+		args = append(args, floor)
+	}
+	amenities, err := normalizeAmenities(query.Amenities)
+	if err != nil {
+		return repository.ListOptions{}, err
+	}
+	for _, amenity := range amenities {
+		conditions = append(conditions, "id IN (SELECT resource_id FROM hub_resource_amenities WHERE name = ?)")
+		args = append(args, amenity)
+	}
+	if query.MinCapacity > 0 {
+		conditions = append(conditions, "capacity >= ?")
+		args = append(args, query.MinCapacity)
+	}
+
+	limit := query.Limit
+	if limit <= 0 {
+		limit = defaultResourceListLimit
+	}
+	if limit > MaxResourceListLimit {
+		limit = MaxResourceListLimit
+	}
+	return repository.ListOptions{
+		Where:  strings.Join(conditions, " AND "),
+		Args:   args,
+		Offset: query.Offset,
+		Limit:  limit,
+	}, nil
+}
+
+// normalizeResource validates a resource, trims its fields, defaults a missing capacity to one seat and normalizes
+// its amenities
+func normalizeResource(resource *entity.Resource) error {
+	if resource.Type != entity.ResourceDesk && resource.Type != entity.ResourceRoom {
+		return ErrInvalidResourceType
+	}
+	resource.Name = strings.TrimSpace(resource.Name)
+	resource.Floor = strings.TrimSpace(resource.Floor)
+	if resource.Capacity == nil {
+		seats := 1
+		resource.Capacity = &seats
+	}
+
+	names := make([]string, 0, len(resource.Amenities))
+	for _, amenity := range resource.Amenities {
+		names = append(names, amenity.Name)
+	}
+	names, err := normalizeAmenities(names)
+	if err != nil {
+		return err
+	}
+	resource.Amenities = make([]entity.Amenity, 0, len(names))
+	for _, name := range names {
+		resource.Amenities = append(resource.Amenities, entity.Amenity{ResourceID: resource.ID, Name: name})
+	}
+	return nil
+}
+
+// normalizeAmenities lowercases, deduplicates and sorts amenity names
+func normalizeAmenities(names []string) ([]string, error) {
+	seen := make(map[string]bool, len(names))
+	normalized := make([]string, 0, len(names))
+	for _, name := range names {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if !amenityPattern.MatchString(name) {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidAmenity, name)
+		}
+		if !seen[name] {
+			seen[name] = true
+			normalized = append(normalized, name)
+		}
+	}
+	sort.Strings(normalized)
+	return normalized, nil
+}
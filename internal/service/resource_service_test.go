@@ -0,0 +1,99 @@
+package service
+
+import (
+	"errors"
+	"hub_management_service/internal/entity"
+	"hub_management_service/internal/repository"
+	"hub_management_service/internal/repository/mocks"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func newTestResourceService() (ResourceService, *mocks.ResourceRepository, *mocks.HubRepository) {
+	resourceRepo := new(mocks.ResourceRepository)
+	hubRepo := new(mocks.HubRepository)
+	hubRepo.On("FindByID", uint(1)).Return(&entity.Hub{ID: 1, Name: "Paris Hub"}, nil)
+	hubRepo.On("FindByID", uint(9)).Return(nil, gorm.ErrRecordNotFound)
+	return NewResourceService(nil, resourceRepo, hubRepo), resourceRepo, hubRepo
+}
+
+// TestCreateResource tests that amenities are normalized and a missing capacity defaults to one seat, while an
+// explicit 0 is kept
+func TestCreateResource(t *testing.T) {
+	service, resourceRepo, _ := newTestResourceService()
+
+	resourceRepo.On("FindByName", uint(1), "D-101").Return(nil, nil)
+	resourceRepo.On("FindByName", uint(1), "Lobby").Return(nil, nil)
+	resourceRepo.On("Create", mock.AnythingOfType("*entity.Resource")).Return(nil)
+
+	resource := &entity.Resource{Type: entity.ResourceDesk, Name: " D-101 ", Amenities: []entity.Amenity{{Name: "Monitor"}, {Name: "dock"}, {Name: "monitor "}}}
+	assert.NoError(t, service.CreateResource(1, resource))
+	assert.Equal(t, uint(1), resource.HubID)
+	assert.Equal(t, intPtr(1), resource.Capacity)
+	assert.Equal(t, []entity.Amenity{{Name: "dock"}, {Name: "monitor"}}, resource.Amenities)
+
+	lobby := &entity.Resource{Type: entity.ResourceRoom, Name: "Lobby", Capacity: intPtr(0)}
+	assert.NoError(t, service.CreateResource(1, lobby))
+	assert.Equal(t, intPtr(0), lobby.Capacity)
+	resourceRepo.AssertExpectations(t)
+}
+
+// TestCreateResource_ConcurrentName tests that a create rejected by the unique index because a concurrent one took
+// the name, in another case, is reported as a taken name
+func TestCreateResource_ConcurrentName(t *testing.T) {
+	service, resourceRepo, _ := newTestResourceService()
+
+	resourceRepo.On("FindByName", uint(1), "Seine").Return(nil, nil).Once()
+	resourceRepo.On("FindByName", uint(1), "Seine").Return(&entity.Resource{ID: 4, HubID: 1, Name: "SEINE"}, nil).Once()
+	resourceRepo.On("Create", mock.AnythingOfType("*entity.Resource")).Return(errors.New("duplicate key value violates unique constraint"))
+
+	err := service.CreateResource(1, &entity.Resource{Type: entity.ResourceRoom, Name: "Seine"})
+	assert.ErrorIs(t, err, ErrResourceNameTaken)
+	resourceRepo.AssertExpectations(t)
+}
+
+// TestCreateResource_Invalid tests the validation of the hub, type, amenities and name
+func TestCreateResource_Invalid(t *testing.T) {
+	service, resourceRepo, _ := newTestResourceService()
+
+	resourceRepo.On("FindByName", uint(1), "Seine").Return(&entity.Resource{ID: 4, HubID: 1, Name: "Seine"}, nil)
+
+	assert.ErrorIs(t, service.CreateResource(9, &entity.Resource{Type: entity.ResourceRoom, Name: "Seine"}), ErrHubNotFound)
+	assert.ErrorIs(t, service.CreateResource(1, &entity.Resource{Type: "locker", Name: "L-1"}), ErrInvalidResourceType)
+	assert.ErrorIs(t, service.CreateResource(1, &entity.Resource{Type: entity.ResourceRoom, Name: "Loire", Amenities: []entity.Amenity{{Name: "big screen"}}}), ErrInvalidAmenity)
+	assert.ErrorIs(t, service.CreateResource(1, &entity.Resource{Type: entity.ResourceRoom, Name: "Seine"}), ErrResourceNameTaken)
+	resourceRepo.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+// TestListResources tests that every filter becomes a condition, one per amenity
+func TestListResources(t *testing.T) {
+	service, resourceRepo, _ := newTestResourceService()
+
+	resourceRepo.On("List", repository.ListOptions{
+		Where: "hub_id = ? AND type = ? AND id IN (SELECT resource_id FROM hub_resource_amenities WHERE name = ?) AND " +
+			"id IN (SELECT resource_id FROM hub_resource_amenities WHERE name = ?) AND capacity >= ?",
+		Args:  []interface{}{uint(1), "room", "monitor", "video", 6},
+		Limit: defaultResourceListLimit,
+	}).Return([]entity.Resource{{ID: 2, Name: "Seine"}}, int64(1), nil)
+
+	resources, total, err := service.ListResources(1, ResourceQuery{Type: "room", Amenities: []string{"Video", "monitor"}, MinCapacity: 6})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	assert.Len(t, resources, 1)
+
+	_, _, err = service.ListResources(9, ResourceQuery{})
+	assert.ErrorIs(t, err, ErrHubNotFound)
+}
+
+// TestDeleteResource_NotFound tests that deleting an unknown resource is reported
+func TestDeleteResource_NotFound(t *testing.T) {
+	service, resourceRepo, _ := newTestResourceService()
+
+	resourceRepo.On("FindByID", uint(3)).Return(nil, gorm.ErrRecordNotFound)
+
+	assert.ErrorIs(t, service.DeleteResource(3), ErrResourceNotFound)
+	resourceRepo.AssertNotCalled(t, "Delete", mock.Anything)
+}
@@ -0,0 +1,265 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"hub_management_service/internal/entity"
+	"hub_management_service/internal/repository"
+	"hub_management_service/pkg/ical"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalidOpeningHours = errors.New("invalid opening hours")
+	ErrInvalidHolidayDate  = errors.New("holiday date is required")
+	ErrHolidayExists       = errors.New("hub already has a holiday on that date")
+	ErrHolidayNotFound     = errors.New("holiday does not exist")
+)
+
+// clockPattern matches HH:MM from 00:00 to 24:00
+var clockPattern = regexp.MustCompile(`^(([01][0-9]|2[0-3]):[0-5][0-9]|24:00)$`)
+
+// OpenStatus tells whether a hub is open at an instant, and why
+type OpenStatus struct {
+	HubID    uint                 `json:"hub_id"`
+	Open     bool                 `json:"open"`
+	At       time.Time            `json:"at"` // The instant in the hub's time zone
+	TimeZone string               `json:"time_zone"`
+	Hours    *entity.OpeningHours `json:"hours,omitempty"`   // The opening interval containing the instant
+	Holiday  *entity.Holiday      `json:"holiday,omitempty"` // Set when the hub is closed for a holiday
+}
+
+type ScheduleService interface {
+	FindOpeningHours(hubID uint) ([]entity.OpeningHours, error)
+	SetOpeningHours(hubID uint, hours []entity.OpeningHours) ([]entity.OpeningHours, error)
+	ListHolidays(hubID uint, year int) ([]entity.Holiday, error)
+	AddHoliday(hubID uint, holiday *entity.Holiday) error
+	RemoveHoliday(hubID, id uint) error
+	IsOpen(hubID uint, at time.Time) (*OpenStatus, error)
+	ExportHolidays(hubID uint) ([]byte, error)
+}
+
+type scheduleService struct {
+	tx      repository.Transactor
+	repo    repository.ScheduleRepository
+	hubRepo repository.HubRepository
+}
+
+func NewScheduleService(tx repository.Transactor, repo repository.ScheduleRepository, hubRepo repository.HubRepository) ScheduleService {
+	return &scheduleService{tx: tx, repo: repo, hubRepo: hubRepo}
+}
+
+// bind returns the service working with the repositories of a transaction
+func (s *scheduleService) bind(repos repository.Repositories) *scheduleService {
+	return &scheduleService{tx: repos, repo: repos.Schedules, hubRepo: repos.Hubs}
+}
+
+// FindOpeningHours returns the weekly opening hours of a hub
+func (s *scheduleService) FindOpeningHours(hubID uint) ([]entity.OpeningHours, error) {
+	if _, err := s.findHub(hubID); err != nil {
+		return nil, err
+	}
+	return s.repo.FindOpeningHours(hubID)
+}
+
+// SetOpeningHours replaces the weekly opening hours of a hub. Intervals of a day must not overlap.
+func (s *scheduleService) SetOpeningHours(hubID uint, hours []entity.OpeningHours) ([]entity.OpeningHours, error) {
+	var saved []entity.OpeningHours
+	err := inTransaction(s.tx, s, s.bind, func(s *scheduleService) (err error) {
+		saved, err = s.setOpeningHours(hubID, hours)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return saved, nil
+}
+
+func (s *scheduleService) setOpeningHours(hubID uint, hours []entity.OpeningHours) ([]entity.OpeningHours, error) {
+	if _, err := s.findHub(hubID); err != nil {
+		return nil, err
+	}
+
+	for _, h := range hours {
+		if h.Weekday < time.Sunday || h.Weekday > time.Saturday {
+			return nil, fmt.Errorf("%w: weekday must be between 0 (Sunday) and 6 (Saturday)", ErrInvalidOpeningHours)
+		}
+		if !clockPattern.MatchString(h.Opens) || !clockPattern.MatchString(h.Closes) {
+			return nil, fmt.Errorf("%w: times must be formatted as HH:MM", ErrInvalidOpeningHours)
+		}
+		if h.Opens >= h.Closes {
+			return nil, fmt.Errorf("%w: %s opens at %s but closes at %s", ErrInvalidOpeningHours, h.Weekday, h.Opens, h.Closes)
+		}
+	}
+	sort.SliceStable(hours, func(i, j int) bool {
+		if hours[i].Weekday != hours[j].Weekday {
+			return hours[i].Weekday < hours[j].Weekday
+		}
+		return hours[i].Opens < hours[j].Opens
+	})
+	for i := 1; i < len(hours); i++ {
+		if hours[i].Weekday == hours[i-1].Weekday && hours[i].Opens < hours[i-1].Closes {
+			return nil, fmt.Errorf("%w: intervals on %s overlap", ErrInvalidOpeningHours, hours[i].Weekday)
+		}
+	}
+
+	if err := s.repo.ReplaceOpeningHours(hubID, hours); err != nil {
+		return nil, err
+	}
+	return hours, nil
+}
+
+// ListHolidays returns the holidays of a hub in a year or, with a zero year, all of them
+func (s *scheduleService) ListHolidays(hubID uint, year int) ([]entity.Holiday, error) {
+	if _, err := s.findHub(hubID); err != nil {
+		return nil, err
+	}
+	if year == 0 {
+		return s.repo.FindHolidays(hubID, nil, nil)
+	}
+	from, to := entity.NewDate(year, time.January, 1), entity.NewDate(year, time.December, 31)
+	return s.repo.FindHolidays(hubID, &from, &to)
+}
+
+// AddHoliday closes a hub for a day
+func (s *scheduleService) AddHoliday(hubID uint, holiday *entity.Holiday) error {
+	var writeErr error
+	err := inTransaction(s.tx, s, s.bind, func(s *scheduleService) error {
+		if err := s.checkNewHoliday(hubID, holiday); err != nil {
+			return err
+		}
+		writeErr = s.repo.CreateHoliday(holiday)
+		return writeErr
+	})
+	if writeErr != nil {
+		// A concurrent request may have added a holiday on the same date, caught by the unique index
+		if conflictErr := s.checkHolidayDate(holiday); conflictErr != nil {
+			return conflictErr
+		}
+	}
+	return err
+}
+
+// checkNewHoliday normalizes a new holiday of a hub and checks that it can be added
+func (s *scheduleService) checkNewHoliday(hubID uint, holiday *entity.Holiday) error {
+	if _, err := s.findHub(hubID); err != nil {
+		return err
+	}
+	if holiday.Date.IsZero() {
+		return ErrInvalidHolidayDate
+	}
+	holiday.ID = 0
+	holiday.HubID = hubID
+	holiday.Name = strings.TrimSpace(holiday.Name)
+	return s.checkHolidayDate(holiday)
+}
+
+// checkHolidayDate checks that the hub of a holiday has no other holiday on its date
+func (s *scheduleService) checkHolidayDate(holiday *entity.Holiday) error {
+	existing, err := s.repo.FindHolidayByDate(holiday.HubID, holiday.Date)
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		return ErrHolidayExists
+	}
+	return nil
+}
+
+// RemoveHoliday deletes a holiday of a hub
+func (s *scheduleService) RemoveHoliday(hubID, id uint) error {
+	return inTransaction(s.tx, s, s.bind, func(s *scheduleService) error {
+		holiday, err := s.repo.FindHolidayByID(id)
+		if repository.IsNotFound(err) || (err == nil && (holiday == nil || holiday.HubID != hubID)) {
+			return ErrHolidayNotFound
+		}
+		if err != nil {
+			return err
+		}
+		return s.repo.DeleteHoliday(id)
+	})
+}
+
+// IsOpen tells whether a hub is open at an instant, using its opening hours and holidays in its own time zone
+func (s *scheduleService) IsOpen(hubID uint, at time.Time) (*OpenStatus, error) {
+	hub, err := s.findHub(hubID)
+	if err != nil {
+		return nil, err
+	}
+	location := hubLocation(hub)
+	local := at.In(location)
+	status := &OpenStatus{HubID: hub.ID, At: local, TimeZone: location.String()}
+
+	holiday, err := s.repo.FindHolidayByDate(hub.ID, entity.NewDate(local.Year(), local.Month(), local.Day()))
+	if err != nil {
+		return nil, err
+	}
+	if holiday != nil {
+		status.Holiday = holiday
+		return status, nil
+	}
+
+	hours, err := s.repo.FindOpeningHours(hub.ID)
+	if err != nil {
+		return nil, err
+	}
+	clock := local.Format("15:04")
+	for i := range hours {
+		if hours[i].Weekday == local.Weekday() && hours[i].Opens <= clock && clock < hours[i].Closes {
+			status.Open = true
+			status.Hours = &hours[i]
+			break
+		}
+	}
+	return status, nil
+}
+
+// ExportHolidays encodes all holidays of a hub as an iCalendar file
+func (s *scheduleService) ExportHolidays(hubID uint) ([]byte, error) {
+	hub, err := s.findHub(hubID)
+	if err != nil {
+		return nil, err
+	}
+	holidays, err := s.repo.FindHolidays(hub.ID, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	calendar := &ical.Calendar{
+		ProdID:   "-//Hub Management Service//Hub Holidays//EN",
+		Name:     hub.Name + " holidays",
+		TimeZone: hub.TimeZone,
+	}
+	for _, holiday := range holidays {
+		calendar.Events = append(calendar.Events, ical.Event{
+			UID:     fmt.Sprintf("hub-%d-holiday-%d@hub-management-service", hub.ID, holiday.ID),
+			Summary: holiday.Name,
+			Start:   holiday.Date.Time,
+			End:     holiday.Date.AddDate(0, 0, 1),
+		})
+	}
+	return calendar.Marshal(time.Now()), nil
+}
+
+func (s *scheduleService) findHub(id uint) (*entity.Hub, error) {
+	hub, err := s.hubRepo.FindByID(id)
+	if repository.IsNotFound(err) || (err == nil && hub == nil) {
+		return nil, ErrHubNotFound
+	}
+	return hub, err
+}
+
+// hubLocation returns the time zone of a hub, UTC when it has none
+func hubLocation(hub *entity.Hub) *time.Location {
+	if hub.TimeZone == "" {
+		return time.UTC
+	}
+	location, err := time.LoadLocation(hub.TimeZone)
+	if err != nil {
+		return time.UTC
+	}
+	return location
+}
@@ -0,0 +1,136 @@
+package service
+
+import (
+	"errors"
+	"hub_management_service/internal/entity"
+	"hub_management_service/internal/repository/mocks"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newTestScheduleService(hub *entity.Hub) (ScheduleService, *mocks.ScheduleRepository) {
+	scheduleRepo := new(mocks.ScheduleRepository)
+	hubRepo := new(mocks.HubRepository)
+	hubRepo.On("FindByID", hub.ID).Return(hub, nil)
+	return NewScheduleService(nil, scheduleRepo, hubRepo), scheduleRepo
+}
+
+// TestIsOpen tests that opening hours are evaluated in the time zone of the hub
+func TestIsOpen(t *testing.T) {
+	service, scheduleRepo := newTestScheduleService(&entity.Hub{ID: 1, Name: "Tokyo Hub", TimeZone: "Asia/Tokyo"})
+
+	scheduleRepo.On("FindHolidayByDate", uint(1), mock.Anything).Return(nil, nil)
+	scheduleRepo.On("FindOpeningHours", uint(1)).Return([]entity.OpeningHours{
+		{Weekday: time.Monday, Opens: "09:00", Closes: "18:00"},
+	}, nil)
+
+	// Sunday 23:30 UTC is Monday 08:30 in Tokyo, and 00:30 UTC is 09:30
+	status, err := service.IsOpen(1, time.Date(2024, time.July, 14, 23, 30, 0, 0, time.UTC))
+	assert.NoError(t, err)
+	assert.False(t, status.Open)
+
+	status, err = service.IsOpen(1, time.Date(2024, time.July, 15, 0, 30, 0, 0, time.UTC))
+	assert.NoError(t, err)
+	assert.True(t, status.Open)
+	assert.Equal(t, "Asia/Tokyo", status.TimeZone)
+	assert.Equal(t, 9, status.At.Hour())
+}
+
+// TestIsOpen_Holiday tests that a holiday closes the hub regardless of its opening hours
+func TestIsOpen_Holiday(t *testing.T) {
+	service, scheduleRepo := newTestScheduleService(&entity.Hub{ID: 1, Name: "Paris Hub", TimeZone: "Europe/Paris"})
+
+	scheduleRepo.On("FindHolidayByDate", uint(1), entity.NewDate(2024, time.July, 15)).
+		Return(&entity.Holiday{ID: 3, HubID: 1, Date: entity.NewDate(2024, time.July, 15), Name: "Bridge day"}, nil)
+
+	// 22:30 UTC on the 14th is already the 15th in Paris
+	status, err := service.IsOpen(1, time.Date(2024, time.July, 14, 22, 30, 0, 0, time.UTC))
+
+	assert.NoError(t, err)
+	assert.False(t, status.Open)
+	assert.Equal(t, "Bridge day", status.Holiday.Name)
+	scheduleRepo.AssertNotCalled(t, "FindOpeningHours", mock.Anything)
+}
+
+// TestSetOpeningHours_Invalid tests the validation of opening hours
+func TestSetOpeningHours_Invalid(t *testing.T) {
+	service, scheduleRepo := newTestScheduleService(&entity.Hub{ID: 1, Name: "Paris Hub"})
+
+	for _, hours := range [][]entity.OpeningHours{
+		{{Weekday: 7, Opens: "09:00", Closes: "18:00"}},
+		{{Weekday: time.Monday, Opens: "9:00", Closes: "18:00"}},
+		{{Weekday: time.Monday, Opens: "18:00", Closes: "09:00"}},
+		{{Weekday: time.Monday, Opens: "09:00", Closes: "13:00"}, {Weekday: time.Monday, Opens: "12:00", Closes: "18:00"}},
+	} {
+		_, err := service.SetOpeningHours(1, hours)
+		assert.ErrorIs(t, err, ErrInvalidOpeningHours)
+	}
+	scheduleRepo.AssertNotCalled(t, "ReplaceOpeningHours", mock.Anything, mock.Anything)
+}
+
+// TestSetOpeningHours_Success tests that intervals are sorted before they are saved
+func TestSetOpeningHours_Success(t *testing.T) {
+	service, scheduleRepo := newTestScheduleService(&entity.Hub{ID: 1, Name: "Paris Hub"})
+
+	scheduleRepo.On("ReplaceOpeningHours", uint(1), mock.Anything).Return(nil)
+
+	hours, err := service.SetOpeningHours(1, []entity.OpeningHours{
+		{Weekday: time.Monday, Opens: "14:00", Closes: "24:00"},
+		{Weekday: time.Monday, Opens: "09:00", Closes: "12:00"},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "09:00", hours[0].Opens)
+	scheduleRepo.AssertExpectations(t)
+}
+
+// TestAddHoliday_Exists tests that a hub has at most one holiday per date
+func TestAddHoliday_Exists(t *testing.T) {
+	service, scheduleRepo := newTestScheduleService(&entity.Hub{ID: 1, Name: "Paris Hub"})
+
+	date := entity.NewDate(2024, time.December, 25)
+	scheduleRepo.On("FindHolidayByDate", uint(1), date).Return(&entity.Holiday{ID: 2, HubID: 1, Date: date}, nil)
+
+	err := service.AddHoliday(1, &entity.Holiday{Date: date, Name: "Christmas"})
+
+	assert.ErrorIs(t, err, ErrHolidayExists)
+	scheduleRepo.AssertNotCalled(t, "CreateHoliday", mock.Anything)
+}
+
+// TestAddHoliday_Concurrent tests that a holiday added on the same date meanwhile is reported as a conflict, and
+// that an ID in the request is not used
+func TestAddHoliday_Concurrent(t *testing.T) {
+	service, scheduleRepo := newTestScheduleService(&entity.Hub{ID: 1, Name: "Paris Hub"})
+
+	date := entity.NewDate(2024, time.December, 25)
+	scheduleRepo.On("FindHolidayByDate", uint(1), date).Return(nil, nil).Once()
+	scheduleRepo.On("FindHolidayByDate", uint(1), date).Return(&entity.Holiday{ID: 2, HubID: 1, Date: date}, nil).Once()
+	scheduleRepo.On("CreateHoliday", mock.MatchedBy(func(holiday *entity.Holiday) bool { return holiday.ID == 0 })).
+		Return(errors.New("duplicate key value violates unique constraint"))
+
+	err := service.AddHoliday(1, &entity.Holiday{ID: 2, Date: date, Name: "Christmas"})
+
+	assert.ErrorIs(t, err, ErrHolidayExists)
+	scheduleRepo.AssertExpectations(t)
+}
+
+// TestExportHolidays tests that holidays become all-day events
+func TestExportHolidays(t *testing.T) {
+	service, scheduleRepo := newTestScheduleService(&entity.Hub{ID: 1, Name: "Paris Hub", TimeZone: "Europe/Paris"})
+
+	scheduleRepo.On("FindHolidays", uint(1), (*entity.Date)(nil), (*entity.Date)(nil)).Return([]entity.Holiday{
+		{ID: 4, HubID: 1, Date: entity.NewDate(2024, time.December, 31), Name: "New Year's Eve"},
+	}, nil)
+
+	calendar, err := service.ExportHolidays(1)
+
+	assert.NoError(t, err)
+	out := string(calendar)
+	assert.Contains(t, out, "UID:hub-1-holiday-4@hub-management-service\r\n")
+	assert.Contains(t, out, "DTSTART;VALUE=DATE:20241231\r\nDTEND;VALUE=DATE:20250101\r\n")
+	assert.Equal(t, 1, strings.Count(out, "BEGIN:VEVENT"))
+}
@@ -0,0 +1,343 @@
+package service
+
+import (
+	"fmt"
+	"hub_management_service/pkg/scim"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Lower-cased extension attribute paths as they appear in PATCH operations
+var (
+	scimUserExtensionPath  = strings.ToLower(scim.UserExtensionSchema)
+	scimGroupExtensionPath = strings.ToLower(scim.GroupExtensionSchema)
+)
+
+// patchSCIMUser applies a single PATCH operation to a user resource.
+// Attributes the service does not store are accepted and ignored.
+func patchSCIMUser(resource *scim.User, op scim.PatchOperation) error {
+	kind, path, err := parsePatchOperation(op)
+	if err != nil {
+		return err
+	}
+
+	if kind == "remove" {
+		switch {
+		case path == "username" || path == "emails" || strings.HasPrefix(path, "emails["):
+			return scim.NewError(http.StatusBadRequest, "mutability", "%s is required and cannot be removed", op.Path)
+		case path == scimUserExtensionPath+":teamid":
+			return scim.NewError(http.StatusBadRequest, "mutability", "teamId is required and cannot be removed")
+		}
+		return nil
+	}
+
+	// Without a path the value is an object of attributes to set
+	if path == "" {
+		values, ok := op.Value.(map[string]interface{})
+		if !ok {
+			return scim.NewError(http.StatusBadRequest, "invalidValue", "value must be an object when no path is given")
+		}
+		for key, value := range values {
+			if err := setSCIMUserAttribute(resource, scim.NormalizePath(key), value); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return setSCIMUserAttribute(resource, path, op.Value)
+}
+
+func setSCIMUserAttribute(resource *scim.User, path string, value interface{}) error {
+	switch {
+	case path == "active":
+		active, err := patchBool(value)
+		if err != nil {
+			return err
+		}
+		resource.Active = &active
+	case path == "username":
+		userName, err := patchString(value)
+		if err != nil {
+			return err
+		}
+		resource.UserName = userName
+		resource.Emails = nil
+	case path == "displayname" || path == "name.formatted":
+		name, err := patchString(value)
+		if err != nil {
+			return err
+		}
+		resource.DisplayName = name
+		resource.Name = &scim.Name{Formatted: name}
+	case path == "name.givenname" || path == "name.familyname":
+		part, err := patchString(value)
+		if err != nil {
+			return err
+		}
+		given, family := splitName(resource)
+		if path == "name.givenname" {
+			given = part
+		} else {
+			family = part
+		}
+		resource.DisplayName = ""
+		resource.Name = &scim.Name{GivenName: given, FamilyName: family}
+	case path == "name":
+		values, ok := value.(map[string]interface{})
+		if !ok {
+			return scim.NewError(http.StatusBadRequest, "invalidValue", "name must be an object")
+		}
+		for key, v := range values {
+			if err := setSCIMUserAttribute(resource, "name."+strings.ToLower(key), v); err != nil {
+				return err
+			}
+		}
+	case path == "emails.value" || (strings.HasPrefix(path, "emails[") && strings.HasSuffix(path, "].value")):
+		email, err := patchString(value)
+		if err != nil {
+			return err
+		}
+		resource.Emails = []scim.Email{{Value: email, Type: "work", Primary: true}}
+	case path == "emails":
+		emails, ok := value.([]interface{})
+		if !ok || len(emails) == 0 {
+			return scim.NewError(http.StatusBadRequest, "invalidValue", "emails must be a non-empty array")
+		}
+		resource.Emails = nil
+		for _, e := range emails {
+			email, _ := e.(map[string]interface{})
+			primary, _ := email["primary"].(bool)
+			resource.Emails = append(resource.Emails, scim.Email{Value: fmt.Sprint(email["value"]), Primary: primary})
+		}
+	case path == scimUserExtensionPath+":teamid":
+		teamID, err := patchID(value)
+		if err != nil {
+			return err
+		}
+		resource.Extension = &scim.UserExtension{TeamID: teamID}
+	case path == scimUserExtensionPath:
+		values, ok := value.(map[string]interface{})
+		if !ok {
+			return scim.NewError(http.StatusBadRequest, "invalidValue", "%s must be an object", scim.UserExtensionSchema)
+		}
+		if teamID, ok := values["teamId"]; ok {
+			return setSCIMUserAttribute(resource, scimUserExtensionPath+":teamid", teamID)
+		}
+	}
+	return nil
+}
+
+// patchSCIMGroup applies a single PATCH operation to a group resource
+func patchSCIMGroup(resource *scim.Group, op scim.PatchOperation) error {
+	kind, path, err := parsePatchOperation(op)
+	if err != nil {
+		return err
+	}
+
+	if path == "" {
+		if kind == "remove" {
+			return scim.NewError(http.StatusBadRequest, "noTarget", "remove requires a path")
+		}
+		values, ok := op.Value.(map[string]interface{})
+		if !ok {
+			return scim.NewError(http.StatusBadRequest, "invalidValue", "value must be an object when no path is given")
+		}
+		for key, value := range values {
+			if err := patchSCIMGroup(resource, scim.PatchOperation{Op: kind, Path: key, Value: value}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	switch {
+	case path == "displayname":
+		if kind == "remove" {
+			return scim.NewError(http.StatusBadRequest, "mutability", "displayName is required and cannot be removed")
+		}
+		name, err := patchString(op.Value)
+		if err != nil {
+			return err
+		}
+		resource.DisplayName = name
+	case path == "members":
+		members, err := patchMembers(op.Value)
+		if err != nil {
+			return err
+		}
+		switch kind {
+		case "add":
+			resource.Members = mergeMembers(resource.Members, members)
+		case "replace":
+			resource.Members = members
+		case "remove":
+			// Without a value every member is removed
+			if op.Value == nil {
+				resource.Members = nil
+			} else {
+				resource.Members = removeMembers(resource.Members, members)
+			}
+		}
+	case strings.HasPrefix(path, "members["):
+		if kind != "remove" {
+			return scim.NewError(http.StatusBadRequest, "invalidPath", "filtered member paths are only supported by remove")
+		}
+		expr, err := scim.ParseFilter(op.Path)
+		if err != nil {
+			return scim.NewError(http.StatusBadRequest, "invalidPath", "%s", err.Error())
+		}
+		var members []scim.Reference
+		if err := collectMemberValues(expr, &members); err != nil {
+			return err
+		}
+		resource.Members = removeMembers(resource.Members, members)
+	case path == scimGroupExtensionPath+":hubid":
+		if kind == "remove" {
+			return scim.NewError(http.StatusBadRequest, "mutability", "hubId is required and cannot be removed")
+		}
+		hubID, err := patchID(op.Value)
+		if err != nil {
+			return err
+		}
+		resource.Extension = &scim.GroupExtension{HubID: hubID}
+	case path == scimGroupExtensionPath:
+		values, ok := op.Value.(map[string]interface{})
+		if !ok {
+			return scim.NewError(http.StatusBadRequest, "invalidValue", "%s must be an object", scim.GroupExtensionSchema)
+		}
+		if hubID, ok := values["hubId"]; ok {
+			return patchSCIMGroup(resource, scim.PatchOperation{Op: kind, Path: scim.GroupExtensionSchema + ":hubId", Value: hubID})
+		}
+	}
+	return nil
+}
+
+// parsePatchOperation returns the lower-cased operation and normalized path
+func parsePatchOperation(op scim.PatchOperation) (string, string, error) {
+	kind := strings.ToLower(op.Op)
+	if kind != "add" && kind != "replace" && kind != "remove" {
+		return "", "", scim.NewError(http.StatusBadRequest, "invalidSyntax", "unknown operation %q", op.Op)
+	}
+	return kind, scim.NormalizePath(op.Path), nil
+}
+
+// collectMemberValues extracts the member IDs of a filter such as members[value eq "1" or value eq "2"]
+func collectMemberValues(expr scim.Expression, members *[]scim.Reference) error {
+	switch e := expr.(type) {
+	case scim.AttributeExpression:
+		if e.Path != "members.value" || e.Operator != "eq" {
+			return scim.NewError(http.StatusBadRequest, "invalidPath", "only value eq filters are supported on members")
+		}
+		*members = append(*members, scim.Reference{Value: fmt.Sprint(e.Value)})
+		return nil
+	case scim.LogicalExpression:
+		if e.Operator != "or" {
+			return scim.NewError(http.StatusBadRequest, "invalidPath", "only or is supported in member filters")
+		}
+		if err := collectMemberValues(e.Left, members); err != nil {
+			return err
+		}
+		return collectMemberValues(e.Right, members)
+	}
+	return scim.NewError(http.StatusBadRequest, "invalidPath", "unsupported member filter")
+}
+
+func patchMembers(value interface{}) ([]scim.Reference, error) {
+	if value == nil {
+		return nil, nil
+	}
+	items, ok := value.([]interface{})
+	if !ok {
+		return nil, scim.NewError(http.StatusBadRequest, "invalidValue", "members must be an array")
+	}
+
+	members := make([]scim.Reference, 0, len(items))
+	for _, item := range items {
+		member, ok := item.(map[string]interface{})
+		if !ok || member["value"] == nil {
+			return nil, scim.NewError(http.StatusBadRequest, "invalidValue", "members must have a value")
+		}
+		id, err := patchID(member["value"])
+		if err != nil {
+			return nil, err
+		}
+		members = append(members, scim.Reference{Value: id})
+	}
+	return members, nil
+}
+
+func mergeMembers(current, added []scim.Reference) []scim.Reference {
+	seen := make(map[string]bool)
+	for _, member := range current {
+		seen[member.Value] = true
+	}
+	for _, member := range added {
+		if !seen[member.Value] {
+			current = append(current, member)
+			seen[member.Value] = true
+		}
+	}
+	return current
+}
+
+func removeMembers(current, removed []scim.Reference) []scim.Reference {
+	drop := make(map[string]bool)
+	for _, member := range removed {
+		drop[member.Value] = true
+	}
+	kept := current[:0]
+	for _, member := range current {
+		if !drop[member.Value] {
+			kept = append(kept, member)
+		}
+	}
+	return kept
+}
+
+func splitName(resource *scim.User) (string, string) {
+	if resource.Name == nil {
+		return "", ""
+	}
+	if resource.Name.GivenName != "" || resource.Name.FamilyName != "" {
+		return resource.Name.GivenName, resource.Name.FamilyName
+	}
+	// Only the formatted name is stored, treat the last word as the family name
+	formatted := strings.TrimSpace(resource.Name.Formatted)
+	if i := strings.LastIndex(formatted, " "); i > 0 {
+		return formatted[:i], formatted[i+1:]
+	}
+	return formatted, ""
+}
+
+func patchString(value interface{}) (string, error) {
+	s, ok := value.(string)
+	if !ok {
+		return "", scim.NewError(http.StatusBadRequest, "invalidValue", "expected a string value")
+	}
+	return s, nil
+}
+
+// patchBool accepts booleans and their string form, which some identity providers send
+func patchBool(value interface{}) (bool, error) {
+	switch v := value.(type) {
+	case bool:
+		return v, nil
+	case string:
+		if b, err := strconv.ParseBool(v); err == nil {
+			return b, nil
+		}
+	}
+	return false, scim.NewError(http.StatusBadRequest, "invalidValue", "expected a boolean value")
+}
+
+// patchID accepts resource IDs given as strings or numbers
+func patchID(value interface{}) (string, error) {
+	switch v := value.(type) {
+	case string:
+		return v, nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	}
+	return "", scim.NewError(http.StatusBadRequest, "invalidValue", "expected an ID")
+}
@@ -0,0 +1,698 @@
+package service
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hub_management_service/internal/entity"
+	"hub_management_service/internal/repository"
+	"hub_management_service/pkg/scim"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	scimBasePath     = "/scim/v2"
+	scimDefaultCount = 100
+	scimMaxCount     = 200
+)
+
+// Filterable attributes of SCIM users and groups and the columns they map to
+var (
+	scimUserColumns = map[string]scim.Column{
+		"id":             {Name: "id", CaseExact: true},
+		"username":       {Name: "email"},
+		"emails":         {Name: "email"},
+		"emails.value":   {Name: "email"},
+		"displayname":    {Name: "name"},
+		"name.formatted": {Name: "name"},
+	}
+	scimGroupColumns = map[string]scim.Column{
+		"id":          {Name: "id", CaseExact: true},
+		"displayname": {Name: "name"},
+	}
+)
+
+type SCIMService interface {
+	ListUsers(filter string, startIndex, count int) (*scim.ListResponse, error)
+	GetUser(id string) (*scim.User, error)
+	CreateUser(user *scim.User) (*scim.User, error)
+	ReplaceUser(id string, version uint, user *scim.User) (*scim.User, error)
+	PatchUser(id string, version uint, operations []scim.PatchOperation) (*scim.User, error)
+	DeleteUser(id string, version uint) error
+
+	ListGroups(filter string, startIndex, count int, excludeMembers bool) (*scim.ListResponse, error)
+	GetGroup(id string) (*scim.Group, error)
+	CreateGroup(group *scim.Group) (*scim.Group, error)
+	ReplaceGroup(id string, version uint, group *scim.Group) (*scim.Group, error)
+	PatchGroup(id string, version uint, operations []scim.PatchOperation) (*scim.Group, error)
+	DeleteGroup(id string, version uint) error
+}
+
+type scimService struct {
+	tx       repository.Transactor
+	userRepo repository.UserRepository
+	teamRepo repository.TeamRepository
+	hubRepo  repository.HubRepository
+	capacity *CapacityPolicy // Limits moves into full hubs, nil for no limits
+}
+
+func NewSCIMService(tx repository.Transactor, userRepo repository.UserRepository, teamRepo repository.TeamRepository, hubRepo repository.HubRepository,
+	capacity *CapacityPolicy) SCIMService {
+	return &scimService{tx: tx, userRepo: userRepo, teamRepo: teamRepo, hubRepo: hubRepo, capacity: capacity}
+}
+
+// bind returns the service working with the repositories of a transaction
+func (s *scimService) bind(repos repository.Repositories) *scimService {
+	return &scimService{tx: repos, userRepo: repos.Users, teamRepo: repos.Teams, hubRepo: repos.Hubs, capacity: s.capacity.bind(repos)}
+}
+
+// ListUsers returns a page of users matching the filter, startIndex is 1-based as in RFC 7644
+func (s *scimService) ListUsers(filter string, startIndex, count int) (*scim.ListResponse, error) {
+	opts, startIndex, err := scimListOptions(filter, startIndex, count, scimUserColumns)
+	if err != nil {
+		return nil, err
+	}
+
+	users, total, err := s.userRepo.List(opts)
+	if err != nil {
+		return nil, err
+	}
+	if count == 0 {
+		users = nil
+	}
+
+	resources := make([]*scim.User, 0, len(users))
+	for _, user := range users {
+		resources = append(resources, toSCIMUser(&user))
+	}
+	return newListResponse(total, startIndex, len(resources), resources), nil
+}
+
+func (s *scimService) GetUser(id string) (*scim.User, error) {
+	user, err := s.findUser(id)
+	if err != nil {
+		return nil, err
+	}
+	return toSCIMUser(user), nil
+}
+
+func (s *scimService) CreateUser(resource *scim.User) (*scim.User, error) {
+	var result *scim.User
+	err := inTransaction(s.tx, s, s.bind, func(s *scimService) (err error) {
+		result, err = s.createUser(resource)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (s *scimService) createUser(resource *scim.User) (*scim.User, error) {
+	user := &entity.User{}
+	if err := s.applySCIMUser(user, resource); err != nil {
+		return nil, err
+	}
+	if err := s.userRepo.Create(user); err != nil {
+		return nil, err
+	}
+	return toSCIMUser(user), nil
+}
+
+// ReplaceUser replaces the user at version, AnyVersion when the request has no If-Match
+func (s *scimService) ReplaceUser(id string, version uint, resource *scim.User) (*scim.User, error) {
+	var result *scim.User
+	err := inTransaction(s.tx, s, s.bind, func(s *scimService) (err error) {
+		result, err = s.replaceUser(id, version, resource)
+		return err
+	})
+	if err != nil {
+		return nil, scimPreconditionError(err)
+	}
+	return result, nil
+}
+
+func (s *scimService) replaceUser(id string, version uint, resource *scim.User) (*scim.User, error) {
+	user, err := s.findUser(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkVersion("user", user.Version, version); err != nil {
+		return nil, err
+	}
+	if err := s.applySCIMUser(user, resource); err != nil {
+		return nil, err
+	}
+	if err := s.userRepo.Update(user); err != nil {
+		return nil, err
+	}
+	return toSCIMUser(user), nil
+}
+
+// PatchUser applies the operations to the user at version and saves the result
+func (s *scimService) PatchUser(id string, version uint, operations []scim.PatchOperation) (*scim.User, error) {
+	var result *scim.User
+	err := inTransaction(s.tx, s, s.bind, func(s *scimService) (err error) {
+		result, err = s.patchUser(id, version, operations)
+		return err
+	})
+	if err != nil {
+		return nil, scimPreconditionError(err)
+	}
+	return result, nil
+}
+
+func (s *scimService) patchUser(id string, version uint, operations []scim.PatchOperation) (*scim.User, error) {
+	user, err := s.findUser(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkVersion("user", user.Version, version); err != nil {
+		return nil, err
+	}
+
+	resource := toSCIMUser(user)
+	for _, op := range operations {
+		if err := patchSCIMUser(resource, op); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.applySCIMUser(user, resource); err != nil {
+		return nil, err
+	}
+	if err := s.userRepo.Update(user); err != nil {
+		return nil, err
+	}
+	return toSCIMUser(user), nil
+}
+
+// DeleteUser deletes the user at version
+func (s *scimService) DeleteUser(id string, version uint) error {
+	return scimPreconditionError(inTransaction(s.tx, s, s.bind, func(s *scimService) error {
+		return s.deleteUser(id, version)
+	}))
+}
+
+func (s *scimService) deleteUser(id string, version uint) error {
+	user, err := s.findUser(id)
+	if err != nil {
+		return err
+	}
+	if err := checkVersion("user", user.Version, version); err != nil {
+		return err
+	}
+	return s.userRepo.Delete(user.ID, user.Version)
+}
+
+func (s *scimService) ListGroups(filter string, startIndex, count int, excludeMembers bool) (*scim.ListResponse, error) {
+	opts, startIndex, err := scimListOptions(filter, startIndex, count, scimGroupColumns)
+	if err != nil {
+		return nil, err
+	}
+
+	teams, total, err := s.teamRepo.List(opts)
+	if err != nil {
+		return nil, err
+	}
+	if count == 0 {
+		teams = nil
+	}
+
+	resources := make([]*scim.Group, 0, len(teams))
+	for _, team := range teams {
+		var members []entity.User
+		if !excludeMembers {
+			if members, err = s.userRepo.FindUserByTeamID(team.ID); err != nil {
+				return nil, err
+			}
+		}
+		resources = append(resources, toSCIMGroup(&team, members))
+	}
+	return newListResponse(total, startIndex, len(resources), resources), nil
+}
+
+func (s *scimService) GetGroup(id string) (*scim.Group, error) {
+	team, err := s.findTeam(id)
+	if err != nil {
+		return nil, err
+	}
+	return s.groupWithMembers(team)
+}
+
+func (s *scimService) CreateGroup(resource *scim.Group) (*scim.Group, error) {
+	var result *scim.Group
+	err := inTransaction(s.tx, s, s.bind, func(s *scimService) (err error) {
+		result, err = s.createGroup(resource)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (s *scimService) createGroup(resource *scim.Group) (*scim.Group, error) {
+	team := &entity.Team{}
+	if err := s.applySCIMGroup(team, resource); err != nil {
+		return nil, err
+	}
+	// Validate members before anything is written
+	members, err := s.findMembers(resource.Members)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.teamRepo.Create(team); err != nil {
+		return nil, err
+	}
+	if err := s.moveMembers(team, members); err != nil {
+		return nil, err
+	}
+	return s.groupWithMembers(team)
+}
+
+// ReplaceGroup replaces the group at version, AnyVersion when the request has no If-Match
+func (s *scimService) ReplaceGroup(id string, version uint, resource *scim.Group) (*scim.Group, error) {
+	var result *scim.Group
+	err := inTransaction(s.tx, s, s.bind, func(s *scimService) (err error) {
+		result, err = s.replaceGroup(id, version, resource)
+		return err
+	})
+	if err != nil {
+		return nil, scimPreconditionError(err)
+	}
+	return result, nil
+}
+
+func (s *scimService) replaceGroup(id string, version uint, resource *scim.Group) (*scim.Group, error) {
+	team, err := s.findTeam(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkVersion("group", team.Version, version); err != nil {
+		return nil, err
+	}
+	if err := s.saveGroup(team, resource); err != nil {
+		return nil, err
+	}
+	return s.groupWithMembers(team)
+}
+
+// PatchGroup applies the operations to the group at version and saves the result
+func (s *scimService) PatchGroup(id string, version uint, operations []scim.PatchOperation) (*scim.Group, error) {
+	var result *scim.Group
+	err := inTransaction(s.tx, s, s.bind, func(s *scimService) (err error) {
+		result, err = s.patchGroup(id, version, operations)
+		return err
+	})
+	if err != nil {
+		return nil, scimPreconditionError(err)
+	}
+	return result, nil
+}
+
+func (s *scimService) patchGroup(id string, version uint, operations []scim.PatchOperation) (*scim.Group, error) {
+	team, err := s.findTeam(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkVersion("group", team.Version, version); err != nil {
+		return nil, err
+	}
+	resource, err := s.groupWithMembers(team)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, op := range operations {
+		if err := patchSCIMGroup(resource, op); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.saveGroup(team, resource); err != nil {
+		return nil, err
+	}
+	return s.groupWithMembers(team)
+}
+
+// DeleteGroup deletes an empty group at version. Members would be deleted with the team, so they must be moved first.
+func (s *scimService) DeleteGroup(id string, version uint) error {
+	return scimPreconditionError(inTransaction(s.tx, s, s.bind, func(s *scimService) error {
+		return s.deleteGroup(id, version)
+	}))
+}
+
+func (s *scimService) deleteGroup(id string, version uint) error {
+	team, err := s.findTeam(id)
+	if err != nil {
+		return err
+	}
+	if err := checkVersion("group", team.Version, version); err != nil {
+		return err
+	}
+
+	members, err := s.userRepo.FindUserByTeamID(team.ID)
+	if err != nil {
+		return err
+	}
+	if len(members) > 0 {
+		return scim.NewError(http.StatusBadRequest, "mutability", "group %d still has %d members, move them to another group first", team.ID, len(members))
+	}
+	return s.teamRepo.Delete(team.ID, team.Version)
+}
+
+func (s *scimService) findUser(id string) (*entity.User, error) {
+	userID, err := strconv.ParseUint(id, 10, 32)
+	if err != nil {
+		return nil, scim.ErrNotFound("User", id)
+	}
+	user, err := s.userRepo.FindByID(uint(userID))
+	if repository.IsNotFound(err) || (err == nil && user == nil) {
+		return nil, scim.ErrNotFound("User", id)
+	}
+	return user, err
+}
+
+func (s *scimService) findTeam(id string) (*entity.Team, error) {
+	teamID, err := strconv.ParseUint(id, 10, 32)
+	if err != nil {
+		return nil, scim.ErrNotFound("Group", id)
+	}
+	team, err := s.teamRepo.FindByID(uint(teamID))
+	if repository.IsNotFound(err) || (err == nil && team == nil) {
+		return nil, scim.ErrNotFound("Group", id)
+	}
+	return team, err
+}
+
+// applySCIMUser validates a SCIM user and copies its attributes onto the entity
+func (s *scimService) applySCIMUser(user *entity.User, resource *scim.User) error {
+	email := resource.UserName
+	for _, e := range resource.Emails {
+		if e.Primary || email == "" {
+			email = e.Value
+		}
+	}
+	if strings.TrimSpace(email) == "" {
+		return scim.NewError(http.StatusBadRequest, "invalidValue", "userName or a primary email is required")
+	}
+	normalized, err := NormalizeEmail(email)
+	if err != nil {
+		return scim.NewError(http.StatusBadRequest, "invalidValue", "%q is not a valid email address", email)
+	}
+	email = normalized
+
+	name := resource.DisplayName
+	if resource.Name != nil {
+		if resource.Name.Formatted != "" {
+			name = resource.Name.Formatted
+		} else if full := strings.TrimSpace(resource.Name.GivenName + " " + resource.Name.FamilyName); full != "" {
+			name = full
+		}
+	}
+	if name == "" {
+		name = email
+	}
+
+	teamID := user.TeamID
+	if resource.Extension != nil && resource.Extension.TeamID != "" && resource.Extension.TeamID != strconv.FormatUint(uint64(teamID), 10) {
+		team, err := s.findTeam(resource.Extension.TeamID)
+		if err != nil {
+			return scim.NewError(http.StatusBadRequest, "invalidValue", "team %s does not exist", resource.Extension.TeamID)
+		}
+		teamID = team.ID
+	}
+	if teamID == 0 {
+		return scim.NewError(http.StatusBadRequest, "invalidValue", "%s:teamId is required", scim.UserExtensionSchema)
+	}
+	if user.ID == 0 || teamID != user.TeamID {
+		if err := s.checkRoom(teamID, user.TeamID); err != nil {
+			return err
+		}
+	}
+
+	existing, err := s.userRepo.FindByEmail(email)
+	if err != nil {
+		return err
+	}
+	if existing != nil && existing.ID != user.ID {
+		return scim.NewError(http.StatusConflict, "uniqueness", "userName %s is already used by user %d", email, existing.ID)
+	}
+
+	user.Email = email
+	user.Name = name
+	user.TeamID = teamID
+	if resource.Active != nil {
+		if *resource.Active {
+			user.DeactivatedAt = nil
+		} else if user.DeactivatedAt == nil {
+			now := time.Now()
+			user.DeactivatedAt = &now
+		}
+	}
+	return nil
+}
+
+// applySCIMGroup validates a SCIM group and copies its attributes onto the team
+func (s *scimService) applySCIMGroup(team *entity.Team, resource *scim.Group) error {
+	name := strings.TrimSpace(resource.DisplayName)
+	if len(name) < 3 || len(name) > 255 {
+		return scim.NewError(http.StatusBadRequest, "invalidValue", "displayName must be between 3 and 255 characters")
+	}
+
+	hubID := team.HubID
+	if resource.Extension != nil && resource.Extension.HubID != "" && resource.Extension.HubID != strconv.FormatUint(uint64(hubID), 10) {
+		id, err := strconv.ParseUint(resource.Extension.HubID, 10, 32)
+		if err != nil {
+			return scim.NewError(http.StatusBadRequest, "invalidValue", "hub %s does not exist", resource.Extension.HubID)
+		}
+		hub, err := s.hubRepo.FindByID(uint(id))
+		if err != nil || hub == nil {
+			return scim.NewError(http.StatusBadRequest, "invalidValue", "hub %s does not exist", resource.Extension.HubID)
+		}
+		hubID = hub.ID
+		if err := s.checkGroupMovable(team); err != nil {
+			return err
+		}
+	}
+	if hubID == 0 {
+		return scim.NewError(http.StatusBadRequest, "invalidValue", "%s:hubId is required", scim.GroupExtensionSchema)
+	}
+
+	team.Name = name
+	team.HubID = hubID
+	return nil
+}
+
+// checkGroupMovable rejects moving a team to another hub while it is part of a team tree, which must stay within one hub
+func (s *scimService) checkGroupMovable(team *entity.Team) error {
+	if team.ID == 0 {
+		return nil
+	}
+	if team.ParentID != nil {
+		return scim.NewError(http.StatusBadRequest, "mutability", "hub of a sub-team cannot be changed")
+	}
+	children, err := s.teamRepo.FindChildren(team.ID)
+	if err != nil {
+		return err
+	}
+	if len(children) > 0 {
+		return scim.NewError(http.StatusBadRequest, "mutability", "hub of a team with sub-teams cannot be changed")
+	}
+	return nil
+}
+
+// saveGroup saves the group attributes and reconciles its members with the resource
+func (s *scimService) saveGroup(team *entity.Team, resource *scim.Group) error {
+	if err := s.applySCIMGroup(team, resource); err != nil {
+		return err
+	}
+	members, err := s.findMembers(resource.Members)
+	if err != nil {
+		return err
+	}
+
+	// Users always belong to exactly one team, so a member can only leave by joining another group
+	current, err := s.userRepo.FindUserByTeamID(team.ID)
+	if err != nil {
+		return err
+	}
+	wanted := make(map[uint]bool)
+	for _, member := range members {
+		wanted[member.ID] = true
+	}
+	for _, user := range current {
+		if !wanted[user.ID] {
+			return scim.NewError(http.StatusBadRequest, "mutability", "user %d cannot be removed from group %d, add them to another group instead", user.ID, team.ID)
+		}
+	}
+
+	if err := s.teamRepo.Update(team); err != nil {
+		return err
+	}
+	return s.moveMembers(team, members)
+}
+
+func (s *scimService) findMembers(references []scim.Reference) ([]*entity.User, error) {
+	members := make([]*entity.User, 0, len(references))
+	for _, reference := range references {
+		user, err := s.findUser(reference.Value)
+		if err != nil {
+			return nil, scim.NewError(http.StatusBadRequest, "invalidValue", "member %s does not exist", reference.Value)
+		}
+		members = append(members, user)
+	}
+	return members, nil
+}
+
+func (s *scimService) moveMembers(team *entity.Team, members []*entity.User) error {
+	for _, member := range members {
+		if member.TeamID == team.ID {
+			continue
+		}
+		if err := s.checkRoom(team.ID, member.TeamID); err != nil {
+			return err
+		}
+		member.TeamID = team.ID
+		if err := s.userRepo.Update(member); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkRoom checks that the hub of a team can take a user from fromTeamID, zero for new users, reporting a full hub as
+// a SCIM conflict. Identity providers cannot override the capacity.
+func (s *scimService) checkRoom(teamID, fromTeamID uint) error {
+	err := s.capacity.CheckRoom(teamID, fromTeamID, WriteOptions{})
+	var fullErr *HubFullError
+	if errors.As(err, &fullErr) {
+		return scim.NewError(http.StatusConflict, "", "%s", fullErr.Error())
+	}
+	return err
+}
+
+func (s *scimService) groupWithMembers(team *entity.Team) (*scim.Group, error) {
+	members, err := s.userRepo.FindUserByTeamID(team.ID)
+	if err != nil {
+		return nil, err
+	}
+	return toSCIMGroup(team, members), nil
+}
+
+func toSCIMUser(user *entity.User) *scim.User {
+	active := user.DeactivatedAt == nil
+	id := strconv.FormatUint(uint64(user.ID), 10)
+	teamID := strconv.FormatUint(uint64(user.TeamID), 10)
+
+	resource := &scim.User{
+		Schemas:     []string{scim.UserSchema, scim.UserExtensionSchema},
+		ID:          id,
+		UserName:    user.Email,
+		Name:        &scim.Name{Formatted: user.Name},
+		DisplayName: user.Name,
+		Emails:      []scim.Email{{Value: user.Email, Type: "work", Primary: true}},
+		Active:      &active,
+		Groups:      []scim.Reference{{Value: teamID, Ref: scimBasePath + "/Groups/" + teamID}},
+		Extension:   &scim.UserExtension{TeamID: teamID},
+	}
+	resource.Meta = &scim.Meta{ResourceType: "User", Location: scimBasePath + "/Users/" + id, Version: scimVersion(user.Version, resource)}
+	return resource
+}
+
+func toSCIMGroup(team *entity.Team, members []entity.User) *scim.Group {
+	id := strconv.FormatUint(uint64(team.ID), 10)
+
+	resource := &scim.Group{
+		Schemas:     []string{scim.GroupSchema, scim.GroupExtensionSchema},
+		ID:          id,
+		DisplayName: team.Name,
+		Extension:   &scim.GroupExtension{HubID: strconv.FormatUint(uint64(team.HubID), 10)},
+	}
+	for _, member := range members {
+		memberID := strconv.FormatUint(uint64(member.ID), 10)
+		resource.Members = append(resource.Members, scim.Reference{Value: memberID, Display: member.Name, Ref: scimBasePath + "/Users/" + memberID})
+	}
+	resource.Meta = &scim.Meta{ResourceType: "Group", Location: scimBasePath + "/Groups/" + id, Version: scimVersion(team.Version, resource)}
+	return resource
+}
+
+// scimVersion is the weak ETag of a resource: the version of its record, which If-Match is checked against, followed
+// by a hash of the representation, which also changes when the members of a group do
+func scimVersion(version uint, resource interface{}) string {
+	body, _ := json.Marshal(resource)
+	sum := sha256.Sum256(body)
+	return fmt.Sprintf(`W/"%d-%x"`, version, sum[:8])
+}
+
+// ParseSCIMVersion reads the record version from the ETag of a resource, see scimVersion. It reports false for tags
+// this service did not issue.
+func ParseSCIMVersion(tag string) (uint, bool) {
+	tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+	if len(tag) < 2 || tag[0] != '"' || tag[len(tag)-1] != '"' {
+		return 0, false
+	}
+	prefix, _, _ := strings.Cut(tag[1:len(tag)-1], "-")
+	version, err := strconv.ParseUint(prefix, 10, 32)
+	if err != nil || uint(version) == AnyVersion {
+		return 0, false
+	}
+	return uint(version), true
+}
+
+// scimPreconditionError reports a write at another version than the current one, whether the If-Match was stale or
+// a concurrent write got in first, as a SCIM 412 error
+func scimPreconditionError(err error) error {
+	if err = versionError(err); errors.Is(err, ErrVersionMismatch) {
+		return scim.NewError(http.StatusPreconditionFailed, "", "resource version does not match If-Match")
+	}
+	return err
+}
+
+// scimListOptions builds the repository query for a list request. A negative count selects the default page size,
+// a count of 0 only asks for totalResults.
+func scimListOptions(filter string, startIndex, count int, columns map[string]scim.Column) (repository.ListOptions, int, error) {
+	if startIndex < 1 {
+		startIndex = 1
+	}
+	if count < 0 {
+		count = scimDefaultCount
+	}
+	if count > scimMaxCount {
+		count = scimMaxCount
+	}
+
+	opts := repository.ListOptions{Offset: startIndex - 1, Limit: count}
+	if count == 0 {
+		// The repository treats a zero limit as unlimited, fetch a single row and discard it instead
+		opts.Limit = 1
+	}
+	if filter != "" {
+		expr, err := scim.ParseFilter(filter)
+		if err != nil {
+			return opts, startIndex, err
+		}
+		where, args, err := scim.ToSQL(expr, columns)
+		if err != nil {
+			return opts, startIndex, err
+		}
+		opts.Where, opts.Args = where, args
+	}
+	return opts, startIndex, nil
+}
+
+func newListResponse(total int64, startIndex, itemsPerPage int, resources interface{}) *scim.ListResponse {
+	return &scim.ListResponse{
+		Schemas:      []string{scim.ListResponseSchema},
+		TotalResults: total,
+		StartIndex:   startIndex,
+		ItemsPerPage: itemsPerPage,
+		Resources:    resources,
+	}
+}
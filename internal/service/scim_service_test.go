@@ -0,0 +1,260 @@
+package service
+
+import (
+	"hub_management_service/internal/entity"
+	"hub_management_service/internal/repository"
+	"hub_management_service/internal/repository/mocks"
+	"hub_management_service/pkg/scim"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func newTestSCIMService() (SCIMService, *mocks.UserRepository, *mocks.TeamRepository, *mocks.HubRepository) {
+	mockUserRepo := new(mocks.UserRepository)
+	mockTeamRepo := new(mocks.TeamRepository)
+	mockHubRepo := new(mocks.HubRepository)
+	return NewSCIMService(nil, mockUserRepo, mockTeamRepo, mockHubRepo, nil), mockUserRepo, mockTeamRepo, mockHubRepo
+}
+
+func assertSCIMError(t *testing.T, err error, status int, scimType string) {
+	scimErr, ok := err.(*scim.Error)
+	if assert.True(t, ok, "expected a SCIM error, got %v", err) {
+		assert.Equal(t, status, scimErr.StatusCode())
+		assert.Equal(t, scimType, scimErr.ScimType)
+	}
+}
+
+// TestSCIMListUsers tests that the filter and paging are translated into repository options
+func TestSCIMListUsers(t *testing.T) {
+	service, mockUserRepo, _, _ := newTestSCIMService()
+
+	mockUserRepo.On("List", repository.ListOptions{
+		Where:  "LOWER(email) = LOWER(?)",
+		Args:   []interface{}{"jane@example.com"},
+		Offset: 10,
+		Limit:  5,
+	}).Return([]entity.User{{ID: 3, Name: "Jane", Email: "jane@example.com", TeamID: 1}}, int64(11), nil)
+
+	list, err := service.ListUsers(`userName eq "jane@example.com"`, 11, 5)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(11), list.TotalResults)
+	assert.Equal(t, 11, list.StartIndex)
+	assert.Equal(t, 1, list.ItemsPerPage)
+	assert.Equal(t, "jane@example.com", list.Resources.([]*scim.User)[0].UserName)
+	mockUserRepo.AssertExpectations(t)
+}
+
+// TestSCIMListUsers_InvalidFilter tests that unsupported filters are rejected before querying
+func TestSCIMListUsers_InvalidFilter(t *testing.T) {
+	service, mockUserRepo, _, _ := newTestSCIMService()
+
+	_, err := service.ListUsers(`title eq "x"`, 1, -1)
+
+	assertSCIMError(t, err, http.StatusBadRequest, "invalidFilter")
+	mockUserRepo.AssertNotCalled(t, "List", mock.Anything)
+}
+
+// TestSCIMCreateUser tests that a SCIM user is mapped onto the entity
+func TestSCIMCreateUser(t *testing.T) {
+	service, mockUserRepo, mockTeamRepo, _ := newTestSCIMService()
+
+	mockTeamRepo.On("FindByID", uint(2)).Return(&entity.Team{ID: 2, Name: "Team"}, nil)
+	mockUserRepo.On("FindByEmail", "jane@example.com").Return(nil, nil)
+	mockUserRepo.On("Create", mock.MatchedBy(func(u *entity.User) bool {
+		return u.Email == "jane@example.com" && u.Name == "Jane Doe" && u.TeamID == 2
+	})).Return(nil)
+
+	user, err := service.CreateUser(&scim.User{
+		UserName:  "jane@example.com",
+		Name:      &scim.Name{GivenName: "Jane", FamilyName: "Doe"},
+		Extension: &scim.UserExtension{TeamID: "2"},
+	})
+
+	assert.NoError(t, err)
+	assert.True(t, *user.Active)
+	assert.NotEmpty(t, user.Meta.Version)
+	mockUserRepo.AssertExpectations(t)
+}
+
+// TestSCIMCreateUser_MissingTeam tests that the team extension is required
+func TestSCIMCreateUser_MissingTeam(t *testing.T) {
+	service, _, _, _ := newTestSCIMService()
+
+	_, err := service.CreateUser(&scim.User{UserName: "jane@example.com"})
+
+	assertSCIMError(t, err, http.StatusBadRequest, "invalidValue")
+}
+
+// TestSCIMCreateUser_Duplicate tests that an existing userName is reported as a uniqueness conflict
+func TestSCIMCreateUser_Duplicate(t *testing.T) {
+	service, mockUserRepo, mockTeamRepo, _ := newTestSCIMService()
+
+	mockTeamRepo.On("FindByID", uint(2)).Return(&entity.Team{ID: 2}, nil)
+	mockUserRepo.On("FindByEmail", "jane@example.com").Return(&entity.User{ID: 9}, nil)
+
+	_, err := service.CreateUser(&scim.User{UserName: "jane@example.com", Extension: &scim.UserExtension{TeamID: "2"}})
+
+	assertSCIMError(t, err, http.StatusConflict, "uniqueness")
+}
+
+// TestSCIMPatchUser_Deactivate tests that active=false deactivates the user
+func TestSCIMPatchUser_Deactivate(t *testing.T) {
+	service, mockUserRepo, _, _ := newTestSCIMService()
+
+	mockUserRepo.On("FindByID", uint(3)).Return(&entity.User{ID: 3, Name: "Jane", Email: "jane@example.com", TeamID: 1}, nil)
+	mockUserRepo.On("FindByEmail", "jane@example.com").Return(&entity.User{ID: 3}, nil)
+	mockUserRepo.On("Update", mock.MatchedBy(func(u *entity.User) bool {
+		return u.DeactivatedAt != nil && u.Name == "Jane"
+	})).Return(nil)
+
+	// Azure AD sends the operation capitalised and the boolean as a string
+	user, err := service.PatchUser("3", AnyVersion, []scim.PatchOperation{{Op: "Replace", Path: "active", Value: "False"}})
+
+	assert.NoError(t, err)
+	assert.False(t, *user.Active)
+	mockUserRepo.AssertExpectations(t)
+}
+
+// TestSCIMPatchUser_StaleVersion tests that a patch at another version than the current one is refused with 412,
+// whether the If-Match is stale or a concurrent write saved first
+func TestSCIMPatchUser_StaleVersion(t *testing.T) {
+	service, mockUserRepo, _, _ := newTestSCIMService()
+
+	mockUserRepo.On("FindByID", uint(3)).Return(&entity.User{ID: 3, Name: "Jane", Email: "jane@example.com", TeamID: 1, Version: 4}, nil)
+	mockUserRepo.On("FindByEmail", "jane@example.com").Return(&entity.User{ID: 3}, nil)
+	mockUserRepo.On("Update", mock.Anything).Return(repository.ErrStaleVersion)
+
+	operations := []scim.PatchOperation{{Op: "replace", Path: "active", Value: false}}
+	_, err := service.PatchUser("3", 3, operations)
+	assertSCIMError(t, err, http.StatusPreconditionFailed, "")
+	mockUserRepo.AssertNotCalled(t, "Update", mock.Anything)
+
+	_, err = service.PatchUser("3", 4, operations)
+	assertSCIMError(t, err, http.StatusPreconditionFailed, "")
+	mockUserRepo.AssertNumberOfCalls(t, "Update", 1)
+}
+
+// TestSCIMUserVersion tests that the ETag of a user carries the version of its record
+func TestSCIMUserVersion(t *testing.T) {
+	service, mockUserRepo, _, _ := newTestSCIMService()
+
+	mockUserRepo.On("FindByID", uint(3)).Return(&entity.User{ID: 3, Name: "Jane", Email: "jane@example.com", TeamID: 1, Version: 4}, nil)
+
+	user, err := service.GetUser("3")
+	assert.NoError(t, err)
+	version, ok := ParseSCIMVersion(user.Meta.Version)
+	assert.True(t, ok)
+	assert.Equal(t, uint(4), version)
+
+	_, ok = ParseSCIMVersion(`W/"stale"`)
+	assert.False(t, ok)
+}
+
+// TestSCIMGetUser_NotFound tests that missing users map to 404
+func TestSCIMGetUser_NotFound(t *testing.T) {
+	service, mockUserRepo, _, _ := newTestSCIMService()
+
+	mockUserRepo.On("FindByID", uint(3)).Return(nil, gorm.ErrRecordNotFound)
+
+	_, err := service.GetUser("3")
+	assertSCIMError(t, err, http.StatusNotFound, "")
+
+	_, err = service.GetUser("not-a-number")
+	assertSCIMError(t, err, http.StatusNotFound, "")
+}
+
+// TestSCIMPatchGroup_AddMember tests that adding a member moves the user into the team
+func TestSCIMPatchGroup_AddMember(t *testing.T) {
+	service, mockUserRepo, mockTeamRepo, _ := newTestSCIMService()
+
+	mockTeamRepo.On("FindByID", uint(1)).Return(&entity.Team{ID: 1, Name: "Team A", HubID: 1}, nil)
+	mockUserRepo.On("FindUserByTeamID", uint(1)).Return([]entity.User{}, nil).Once()
+	mockUserRepo.On("FindUserByTeamID", uint(1)).Return([]entity.User{}, nil).Once()
+	mockUserRepo.On("FindByID", uint(5)).Return(&entity.User{ID: 5, TeamID: 2}, nil)
+	mockTeamRepo.On("Update", mock.AnythingOfType("*entity.Team")).Return(nil)
+	mockUserRepo.On("Update", mock.MatchedBy(func(u *entity.User) bool { return u.ID == 5 && u.TeamID == 1 })).Return(nil)
+	mockUserRepo.On("FindUserByTeamID", uint(1)).Return([]entity.User{{ID: 5, TeamID: 1}}, nil)
+
+	group, err := service.PatchGroup("1", AnyVersion, []scim.PatchOperation{{
+		Op:    "add",
+		Path:  "members",
+		Value: []interface{}{map[string]interface{}{"value": "5"}},
+	}})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "5", group.Members[0].Value)
+	mockUserRepo.AssertExpectations(t)
+}
+
+// TestSCIMPatchGroup_HubFull tests that members are not moved into a hub at capacity, which is a conflict
+func TestSCIMPatchGroup_HubFull(t *testing.T) {
+	mockUserRepo := new(mocks.UserRepository)
+	mockTeamRepo := new(mocks.TeamRepository)
+	mockHubRepo := new(mocks.HubRepository)
+	service := NewSCIMService(nil, mockUserRepo, mockTeamRepo, mockHubRepo, NewCapacityPolicy(mockHubRepo, mockTeamRepo, 1))
+
+	mockTeamRepo.On("FindByID", uint(1)).Return(&entity.Team{ID: 1, Name: "Team A", HubID: 1}, nil)
+	mockTeamRepo.On("FindByID", uint(2)).Return(&entity.Team{ID: 2, Name: "Team B", HubID: 2}, nil)
+	mockHubRepo.On("FindByID", uint(1)).Return(&entity.Hub{ID: 1, Capacity: intPtr(3)}, nil)
+	mockHubRepo.On("CountUsers", []uint{1}).Return(map[uint]int64{1: 3}, nil)
+	mockUserRepo.On("FindUserByTeamID", uint(1)).Return([]entity.User{}, nil)
+	mockUserRepo.On("FindByID", uint(5)).Return(&entity.User{ID: 5, TeamID: 2}, nil)
+	mockTeamRepo.On("Update", mock.AnythingOfType("*entity.Team")).Return(nil)
+
+	_, err := service.PatchGroup("1", AnyVersion, []scim.PatchOperation{{
+		Op:    "add",
+		Path:  "members",
+		Value: []interface{}{map[string]interface{}{"value": "5"}},
+	}})
+
+	assertSCIMError(t, err, http.StatusConflict, "")
+	mockUserRepo.AssertNotCalled(t, "Update", mock.Anything)
+}
+
+// TestSCIMCreateUser_HubFull tests that users are not provisioned into a hub at capacity
+func TestSCIMCreateUser_HubFull(t *testing.T) {
+	mockUserRepo := new(mocks.UserRepository)
+	mockTeamRepo := new(mocks.TeamRepository)
+	mockHubRepo := new(mocks.HubRepository)
+	service := NewSCIMService(nil, mockUserRepo, mockTeamRepo, mockHubRepo, NewCapacityPolicy(mockHubRepo, mockTeamRepo, 1))
+
+	mockTeamRepo.On("FindByID", uint(2)).Return(&entity.Team{ID: 2, Name: "Team", HubID: 1}, nil)
+	mockHubRepo.On("FindByID", uint(1)).Return(&entity.Hub{ID: 1, Capacity: intPtr(3)}, nil)
+	mockHubRepo.On("CountUsers", []uint{1}).Return(map[uint]int64{1: 3}, nil)
+
+	_, err := service.CreateUser(&scim.User{UserName: "jane@example.com", Extension: &scim.UserExtension{TeamID: "2"}})
+
+	assertSCIMError(t, err, http.StatusConflict, "")
+	mockUserRepo.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+// TestSCIMPatchGroup_RemoveCurrentMember tests that members cannot be left without a team
+func TestSCIMPatchGroup_RemoveCurrentMember(t *testing.T) {
+	service, mockUserRepo, mockTeamRepo, _ := newTestSCIMService()
+
+	mockTeamRepo.On("FindByID", uint(1)).Return(&entity.Team{ID: 1, Name: "Team A", HubID: 1}, nil)
+	mockUserRepo.On("FindUserByTeamID", uint(1)).Return([]entity.User{{ID: 5, TeamID: 1}}, nil)
+
+	_, err := service.PatchGroup("1", AnyVersion, []scim.PatchOperation{{Op: "remove", Path: `members[value eq "5"]`}})
+
+	assertSCIMError(t, err, http.StatusBadRequest, "mutability")
+	mockTeamRepo.AssertNotCalled(t, "Update", mock.Anything)
+}
+
+// TestSCIMDeleteGroup_WithMembers tests that non-empty groups cannot be deleted
+func TestSCIMDeleteGroup_WithMembers(t *testing.T) {
+	service, mockUserRepo, mockTeamRepo, _ := newTestSCIMService()
+
+	mockTeamRepo.On("FindByID", uint(1)).Return(&entity.Team{ID: 1}, nil)
+	mockUserRepo.On("FindUserByTeamID", uint(1)).Return([]entity.User{{ID: 5}}, nil)
+
+	err := service.DeleteGroup("1", AnyVersion)
+
+	assertSCIMError(t, err, http.StatusBadRequest, "mutability")
+	mockTeamRepo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,170 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"hub_management_service/internal/entity"
+	"hub_management_service/internal/repository"
+	"hub_management_service/pkg/fuzzy"
+	"math"
+	"sort"
+	"unicode/utf8"
+)
+
+var ErrInvalidSearch = errors.New("invalid search")
+
+// SearchType is a kind of result of the unified search
+type SearchType string
+
+const (
+	SearchHubs  SearchType = "hubs"
+	SearchTeams SearchType = "teams"
+	SearchUsers SearchType = "users"
+)
+
+const (
+	DefaultSearchLimit   = 10
+	MaxSearchLimit       = 50
+	MaxSearchQueryLength = 100
+	// MinSearchScore drops the candidates that only share a prefix with the query
+	MinSearchScore = 0.5
+	// searchCandidates is how many candidates per result are asked from the database before ranking
+	searchCandidates = 5
+)
+
+// SearchQuery is a search of hubs, teams and users
+type SearchQuery struct {
+	Q     string
+	Types []SearchType // Every type when empty
+	Limit int          // Results per type, DefaultSearchLimit when 0
+}
+
+// SearchHit is a result of the search, Detail is the location of a hub or the email of a user
+type SearchHit struct {
+	ID     uint         `json:"id"`
+	Name   string       `json:"name"`
+	Detail string       `json:"detail,omitempty"`
+	HubID  uint         `json:"hub_id,omitempty"`
+	TeamID uint         `json:"team_id,omitempty"`
+	Score  float64      `json:"score"`
+	Hub    *entity.Hub  `json:"hub,omitempty"`  // Hub of a team, only loaded with ?expand=hub
+	Team   *entity.Team `json:"team,omitempty"` // Primary team of a user, only loaded with ?expand=team
+}
+
+// SearchResults groups the hits by type, best first
+type SearchResults struct {
+	Query string      `json:"query"`
+	Hubs  []SearchHit `json:"hubs"`
+	Teams []SearchHit `json:"teams"`
+	Users []SearchHit `json:"users"`
+}
+
+type SearchService interface {
+	Search(query SearchQuery) (*SearchResults, error)
+}
+
+type searchService struct {
+	repo repository.SearchRepository
+}
+
+func NewSearchService(repo repository.SearchRepository) SearchService {
+	return &searchService{repo: repo}
+}
+
+// Search finds the hubs by name or location, teams by name and active users by name or email matching the query.
+// The database finds the candidates and they are ranked with fuzzy.Score, so that the order is the same on
+// every database.
+func (s *searchService) Search(query SearchQuery) (*SearchResults, error) {
+	if len(fuzzy.Tokens(query.Q)) == 0 {
+		return nil, fmt.Errorf("%w: q must contain letters or digits", ErrInvalidSearch)
+	}
+	if utf8.RuneCountInString(query.Q) > MaxSearchQueryLength {
+		return nil, fmt.Errorf("%w: q is longer than %d characters", ErrInvalidSearch, MaxSearchQueryLength)
+	}
+	if query.Limit == 0 {
+		query.Limit = DefaultSearchLimit
+	}
+	if query.Limit < 0 || query.Limit > MaxSearchLimit {
+		return nil, fmt.Errorf("%w: limit must be between 1 and %d", ErrInvalidSearch, MaxSearchLimit)
+	}
+	types := map[SearchType]bool{}
+	for _, t := range query.Types {
+		if t != SearchHubs && t != SearchTeams && t != SearchUsers {
+			return nil, fmt.Errorf("%w: unknown type %q", ErrInvalidSearch, t)
+		}
+		types[t] = true
+	}
+	wanted := func(t SearchType) bool { return len(types) == 0 || types[t] }
+
+	results := &SearchResults{Query: query.Q, Hubs: []SearchHit{}, Teams: []SearchHit{}, Users: []SearchHit{}}
+	candidates := query.Limit * searchCandidates
+	if wanted(SearchHubs) {
+		hubs, err := s.repo.SearchHubs(query.Q, candidates)
+		if err != nil {
+			return nil, err
+		}
+		for _, hub := range hubs {
+			hit := SearchHit{ID: hub.ID, Name: hub.Name, Detail: hub.Location}
+			results.Hubs = appendHit(results.Hubs, hit, query.Q, hub.Name, hub.Location)
+		}
+	}
+	if wanted(SearchTeams) {
+		teams, err := s.repo.SearchTeams(query.Q, candidates)
+		if err != nil {
+			return nil, err
+		}
+		for _, team := range teams {
+			hit := SearchHit{ID: team.ID, Name: team.Name, HubID: team.HubID}
+			results.Teams = appendHit(results.Teams, hit, query.Q, team.Name)
+		}
+	}
+	if wanted(SearchUsers) {
+		users, err := s.repo.SearchUsers(query.Q, candidates)
+		if err != nil {
+			return nil, err
+		}
+		for _, user := range users {
+			hit := SearchHit{ID: user.ID, Name: user.Name, Detail: user.Email, TeamID: user.TeamID}
+			results.Users = appendHit(results.Users, hit, query.Q, user.Name, user.Email)
+		}
+	}
+
+	results.Hubs = rankHits(results.Hubs, query.Limit)
+	results.Teams = rankHits(results.Teams, query.Limit)
+	results.Users = rankHits(results.Users, query.Limit)
+	return results, nil
+}
+
+// appendHit scores a candidate on its best field, the fields after the first weigh 0.9,
+// and keeps it when it reaches MinSearchScore
+func appendHit(hits []SearchHit, hit SearchHit, query string, fields ...string) []SearchHit {
+	for i, field := range fields {
+		score := fuzzy.Score(query, field)
+		if i > 0 {
+			score *= 0.9
+		}
+		hit.Score = math.Max(hit.Score, score)
+	}
+	if hit.Score < MinSearchScore {
+		return hits
+	}
+	hit.Score = math.Round(hit.Score*1000) / 1000
+	return append(hits, hit)
+}
+
+// rankHits orders the hits by score, then name and ID, and keeps the first limit
+func rankHits(hits []SearchHit, limit int) []SearchHit {
+	sort.SliceStable(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		if hits[i].Name != hits[j].Name {
+			return hits[i].Name < hits[j].Name
+		}
+		return hits[i].ID < hits[j].ID
+	})
+	if len(hits) > limit {
+		hits = hits[:limit]
+	}
+	return hits
+}
@@ -0,0 +1,82 @@
+package service
+
+import (
+	"errors"
+	"hub_management_service/internal/entity"
+	"hub_management_service/internal/repository/mocks"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestSearch tests that candidates are ranked, filtered and grouped by type
+func TestSearch(t *testing.T) {
+	repo := new(mocks.SearchRepository)
+	service := NewSearchService(repo)
+	repo.On("SearchHubs", "pari", 50).Return([]entity.Hub{
+		{ID: 1, Name: "Paris Hub", Location: "Paris"},
+		{ID: 2, Name: "Hub 2", Location: "Paris"},
+		{ID: 3, Name: "Pasadena", Location: "Pasadena"}, // Only shares the first letters
+	}, nil)
+	repo.On("SearchTeams", "pari", 50).Return([]entity.Team{{ID: 4, Name: "Parisian Ops", HubID: 1}}, nil)
+	repo.On("SearchUsers", "pari", 50).Return([]entity.User{
+		{ID: 5, Name: "Jane Doe", Email: "jane@paris.example.com", TeamID: 4},
+		{ID: 6, Name: "Pari Patel", Email: "pari@example.com", TeamID: 4},
+	}, nil)
+
+	results, err := service.Search(SearchQuery{Q: "pari"})
+	assert.NoError(t, err)
+	assert.Equal(t, "pari", results.Query)
+	if assert.Len(t, results.Hubs, 2) {
+		assert.Equal(t, SearchHit{ID: 1, Name: "Paris Hub", Detail: "Paris", Score: 0.95}, results.Hubs[0])
+		assert.Equal(t, uint(2), results.Hubs[1].ID) // Matches on the location only
+		assert.Equal(t, 0.855, results.Hubs[1].Score)
+	}
+	if assert.Len(t, results.Teams, 1) {
+		assert.Equal(t, uint(1), results.Teams[0].HubID)
+	}
+	if assert.Len(t, results.Users, 2) {
+		assert.Equal(t, uint(6), results.Users[0].ID)
+		assert.Equal(t, "jane@paris.example.com", results.Users[1].Detail)
+	}
+}
+
+// TestSearch_TypesAndLimit tests that only the requested types are searched and each is cut at the limit
+func TestSearch_TypesAndLimit(t *testing.T) {
+	repo := new(mocks.SearchRepository)
+	service := NewSearchService(repo)
+	repo.On("SearchUsers", "jane", 5).Return([]entity.User{
+		{ID: 7, Name: "Janet", Email: "janet@example.com"},
+		{ID: 5, Name: "Jane", Email: "jane@example.com"},
+	}, nil)
+
+	results, err := service.Search(SearchQuery{Q: "jane", Types: []SearchType{SearchUsers}, Limit: 1})
+	assert.NoError(t, err)
+	assert.Empty(t, results.Hubs)
+	assert.Empty(t, results.Teams)
+	if assert.Len(t, results.Users, 1) {
+		assert.Equal(t, uint(5), results.Users[0].ID)
+		assert.Equal(t, 1.0, results.Users[0].Score)
+	}
+	repo.AssertNotCalled(t, "SearchHubs", "jane", 5)
+}
+
+// TestSearch_Invalid tests that queries without words, too long or with unknown types are rejected
+func TestSearch_Invalid(t *testing.T) {
+	repo := new(mocks.SearchRepository)
+	service := NewSearchService(repo)
+	repo.On("SearchHubs", "paris", 50).Return(nil, errors.New("database down"))
+
+	for _, query := range []SearchQuery{
+		{Q: " - "},
+		{Q: string(make([]byte, MaxSearchQueryLength+1)) + "a"},
+		{Q: "paris", Limit: MaxSearchLimit + 1},
+		{Q: "paris", Types: []SearchType{"regions"}},
+	} {
+		_, err := service.Search(query)
+		assert.ErrorIs(t, err, ErrInvalidSearch)
+	}
+
+	_, err := service.Search(SearchQuery{Q: "paris", Types: []SearchType{SearchHubs}})
+	assert.EqualError(t, err, "database down")
+}
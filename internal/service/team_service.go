@@ -2,30 +2,79 @@ package service
 
 import (
 	"errors"
+	"fmt"
 	"hub_management_service/internal/entity"
 	"hub_management_service/internal/repository"
 )
 
+var (
+	ErrParentTeamNotFound = errors.New("parent team does not exist")
+	ErrParentHubMismatch  = errors.New("parent team must belong to the same hub")
+	ErrTeamCycle          = errors.New("parent team would create a cycle")
+	ErrTeamNotEmpty       = errors.New("team still has users or sub-teams")
+)
+
 type TeamService interface {
 	CreateTeam(team *entity.Team) error
 	FindTeamsByHubID(hubID uint) ([]entity.Team, error)
 	FindByID(id uint) (*entity.Team, error)
+	SetParent(id, version uint, parentID *uint) (*entity.Team, error)
+	PatchTeam(id, version uint, format PatchFormat, patch []byte) (*entity.Team, error)
+	DeleteTeam(id, version uint) error
+	FindSubtree(id uint) (*TeamNode, error)
+	FindAncestors(id uint) ([]entity.Team, error)
+	FindHeadcount(id uint) (*Headcount, error)
 }
+
+// TeamNode is a team along with its sub-teams and the number of people in them
+type TeamNode struct {
+	entity.Team
+	Headcount      int64       `json:"headcount"`       // Users whose primary team is this team
+	TotalHeadcount int64       `json:"total_headcount"` // Headcount including all sub-teams
+	Children       []*TeamNode `json:"children"`
+}
+
+// Headcount is the number of people in a team, on its own and including its sub-teams
+type Headcount struct {
+	TeamID         uint  `json:"team_id"`
+	Headcount      int64 `json:"headcount"`
+	TotalHeadcount int64 `json:"total_headcount"`
+	SubTeams       int   `json:"sub_teams"`
+}
+
 type teamService struct {
+	tx      repository.Transactor
 	repo    repository.TeamRepository
 	hubRepo repository.HubRepository // Add HubRepository to check Hub existence
 
 }
 
-func NewTeamService(repo repository.TeamRepository, hubRepo repository.HubRepository) TeamService {
-	return &teamService{repo: repo, hubRepo: hubRepo}
+func NewTeamService(tx repository.Transactor, repo repository.TeamRepository, hubRepo repository.HubRepository) TeamService {
+	return &teamService{tx: tx, repo: repo, hubRepo: hubRepo}
+}
+
+// bind returns the service working with the repositories of a transaction
+func (s *teamService) bind(repos repository.Repositories) *teamService {
+	return &teamService{tx: repos, repo: repos.Teams, hubRepo: repos.Hubs}
 }
 
+// CreateTeam checks the hub and parent team and creates the team in one transaction
 func (s *teamService) CreateTeam(team *entity.Team) error {
+	return inTransaction(s.tx, s, s.bind, func(s *teamService) error {
+		return s.createTeam(team)
+	})
+}
+
+func (s *teamService) createTeam(team *entity.Team) error {
 	// Check if the Hub exists
 	hub, err := s.hubRepo.FindByID(team.HubID)
 	if err != nil || hub == nil {
-		return errors.New("hub does not exist") // Return an error if the Hub does not exist
+		return ErrHubNotFound // Return an error if the Hub does not exist
+	}
+	if team.ParentID != nil {
+		if _, err := s.findParent(*team.ParentID, team.HubID); err != nil {
+			return err
+		}
 	}
 
 	// Create the team if Hub exists
@@ -39,3 +88,221 @@ func (s *teamService) FindTeamsByHubID(hubID uint) ([]entity.Team, error) {
 func (s *teamService) FindByID(id uint) (*entity.Team, error) {
 	return s.repo.FindByID(id) // Call the repository method
 }
+
+// SetParent moves a team at version under another team of the same hub or, with a nil parentID, to the top level
+func (s *teamService) SetParent(id, version uint, parentID *uint) (*entity.Team, error) {
+	var team *entity.Team
+	err := inTransaction(s.tx, s, s.bind, func(s *teamService) (err error) {
+		team, err = s.setParent(id, version, parentID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return team, nil
+}
+
+func (s *teamService) setParent(id, version uint, parentID *uint) (*entity.Team, error) {
+	team, err := s.findTeam(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkVersion("team", team.Version, version); err != nil {
+		return nil, err
+	}
+	if err := s.checkParent(team, parentID); err != nil {
+		return nil, err
+	}
+
+	team.ParentID = parentID
+	if err := s.repo.Update(team); err != nil {
+		return nil, versionError(err)
+	}
+	return team, nil
+}
+
+// checkParent checks that a team can move under parentID, a nil parentID being the top level
+func (s *teamService) checkParent(team *entity.Team, parentID *uint) error {
+	if parentID == nil {
+		return nil
+	}
+	if *parentID == team.ID {
+		return ErrTeamCycle
+	}
+	if _, err := s.findParent(*parentID, team.HubID); err != nil {
+		return err
+	}
+	// The new parent must not be a sub-team of the team, directly or indirectly
+	ancestors, err := s.repo.FindAncestors(*parentID)
+	if err != nil {
+		return err
+	}
+	for _, ancestor := range ancestors {
+		if ancestor.ID == team.ID {
+			return ErrTeamCycle
+		}
+	}
+	return nil
+}
+
+// PatchTeam applies a JSON Merge Patch or JSON Patch document to the name and parent of a team at version, a team
+// cannot move to another hub
+func (s *teamService) PatchTeam(id, version uint, format PatchFormat, patch []byte) (*entity.Team, error) {
+	var team *entity.Team
+	err := inTransaction(s.tx, s, s.bind, func(s *teamService) (err error) {
+		if team, err = s.findTeam(id); err != nil {
+			return err
+		}
+		if err := checkVersion("team", team.Version, version); err != nil {
+			return err
+		}
+		current := *team
+		current.Hub, current.Users = nil, nil
+		var update entity.Team
+		if err := applyPatch(format, &current, patch, &update, "id", "hub_id", "hub", "users"); err != nil {
+			return err
+		}
+		if !sameID(update.ParentID, team.ParentID) {
+			if err := s.checkParent(team, update.ParentID); err != nil {
+				return err
+			}
+		}
+
+		team.Name = update.Name
+		team.ParentID = update.ParentID
+		team.Hub = nil
+		return versionError(s.repo.Update(team))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return team, nil
+}
+
+// DeleteTeam deletes a team at version. Users whose primary team it is would be deleted with it and its sub-teams
+// would move to the top level, so they must be moved first.
+func (s *teamService) DeleteTeam(id, version uint) error {
+	return inTransaction(s.tx, s, s.bind, func(s *teamService) error {
+		team, err := s.findTeam(id)
+		if err != nil {
+			return err
+		}
+		if err := checkVersion("team", team.Version, version); err != nil {
+			return err
+		}
+		counts, err := s.repo.CountUsers([]uint{id})
+		if err != nil {
+			return err
+		}
+		children, err := s.repo.FindChildren(id)
+		if err != nil {
+			return err
+		}
+		if counts[id] > 0 || len(children) > 0 {
+			return fmt.Errorf("%w: team %d has %d users and %d sub-teams", ErrTeamNotEmpty, id, counts[id], len(children))
+		}
+		return versionError(s.repo.Delete(id, team.Version))
+	})
+}
+
+// FindSubtree finds a team with all of its sub-teams nested below it, along with their headcounts
+func (s *teamService) FindSubtree(id uint) (*TeamNode, error) {
+	team, err := s.findTeam(id)
+	if err != nil {
+		return nil, err
+	}
+	descendants, err := s.repo.FindDescendants(id)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := []uint{team.ID}
+	for _, descendant := range descendants {
+		ids = append(ids, descendant.ID)
+	}
+	counts, err := s.repo.CountUsers(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	// Descendants come ordered by depth, so every parent has a node before its children
+	root := &TeamNode{Team: *team, Headcount: counts[team.ID], Children: []*TeamNode{}}
+	nodes := map[uint]*TeamNode{team.ID: root}
+	for _, descendant := range descendants {
+		parent, ok := nodes[*descendant.ParentID]
+		if !ok {
+			continue
+		}
+		node := &TeamNode{Team: descendant, Headcount: counts[descendant.ID], Children: []*TeamNode{}}
+		parent.Children = append(parent.Children, node)
+		nodes[descendant.ID] = node
+	}
+	sumHeadcount(root)
+	return root, nil
+}
+
+// FindAncestors finds the parent teams above a team, nearest first
+func (s *teamService) FindAncestors(id uint) ([]entity.Team, error) {
+	if _, err := s.findTeam(id); err != nil {
+		return nil, err
+	}
+	return s.repo.FindAncestors(id)
+}
+
+// FindHeadcount counts the people in a team and in all of its sub-teams
+func (s *teamService) FindHeadcount(id uint) (*Headcount, error) {
+	if _, err := s.findTeam(id); err != nil {
+		return nil, err
+	}
+	descendants, err := s.repo.FindDescendants(id)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := []uint{id}
+	for _, descendant := range descendants {
+		ids = append(ids, descendant.ID)
+	}
+	counts, err := s.repo.CountUsers(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	headcount := &Headcount{TeamID: id, Headcount: counts[id], SubTeams: len(descendants)}
+	for _, count := range counts {
+		headcount.TotalHeadcount += count
+	}
+	return headcount, nil
+}
+
+func (s *teamService) findTeam(id uint) (*entity.Team, error) {
+	team, err := s.repo.FindByID(id)
+	if repository.IsNotFound(err) || (err == nil && team == nil) {
+		return nil, ErrTeamNotFound
+	}
+	return team, err
+}
+
+// findParent finds a team to nest another team of the given hub under
+func (s *teamService) findParent(id, hubID uint) (*entity.Team, error) {
+	parent, err := s.findTeam(id)
+	if errors.Is(err, ErrTeamNotFound) {
+		return nil, ErrParentTeamNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if parent.HubID != hubID {
+		return nil, ErrParentHubMismatch
+	}
+	return parent, nil
+}
+
+// sumHeadcount fills in the total headcount of a node and all nodes below it
+func sumHeadcount(node *TeamNode) int64 {
+	node.TotalHeadcount = node.Headcount
+	for _, child := range node.Children {
+		node.TotalHeadcount += sumHeadcount(child)
+	}
+	return node.TotalHeadcount
+}
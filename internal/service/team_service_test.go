@@ -3,18 +3,20 @@ package service
 import (
 	"errors"
 	"hub_management_service/internal/entity"
+	"hub_management_service/internal/repository"
 	"hub_management_service/internal/repository/mocks"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
 )
 
 // TestCreateTeam tests the CreateTeam service method when the Hub exists
 func TestCreateTeam_Success(t *testing.T) {
 	mockTeamRepo := new(mocks.TeamRepository)
 	mockHubRepo := new(mocks.HubRepository)
-	service := NewTeamService(mockTeamRepo, mockHubRepo)
+	service := NewTeamService(nil, mockTeamRepo, mockHubRepo)
 
 	// Mock the FindByID method of HubRepository to return a hub
 	mockHubRepo.On("FindByID", uint(1)).Return(&entity.Hub{
@@ -40,11 +42,33 @@ func TestCreateTeam_Success(t *testing.T) {
 	mockTeamRepo.AssertExpectations(t)
 }
 
+// TestCreateTeam_InTransaction tests that the hub check and the insert both use the repositories of the transaction
+func TestCreateTeam_InTransaction(t *testing.T) {
+	transactor := &testTransactor{repos: repository.Repositories{
+		Hubs:  new(mocks.HubRepository),
+		Teams: new(mocks.TeamRepository),
+	}}
+	txHubRepo := transactor.repos.Hubs.(*mocks.HubRepository)
+	txTeamRepo := transactor.repos.Teams.(*mocks.TeamRepository)
+	// The repositories outside the transaction have no expectations and fail the test when used
+	service := NewTeamService(transactor, new(mocks.TeamRepository), new(mocks.HubRepository))
+
+	txHubRepo.On("FindByID", uint(1)).Return(&entity.Hub{ID: 1, Name: "Test Hub"}, nil)
+	txTeamRepo.On("Create", mock.AnythingOfType("*entity.Team")).Return(errors.New("database error"))
+
+	err := service.CreateTeam(&entity.Team{Name: "Test Team", HubID: 1})
+
+	assert.EqualError(t, err, "database error")
+	assert.EqualError(t, transactor.err, "database error") // The transaction is rolled back
+	txHubRepo.AssertExpectations(t)
+	txTeamRepo.AssertExpectations(t)
+}
+
 // TestCreateTeam_HubNotFound tests the CreateTeam service method when the Hub does not exist
 func TestCreateTeam_HubNotFound(t *testing.T) {
 	mockTeamRepo := new(mocks.TeamRepository)
 	mockHubRepo := new(mocks.HubRepository)
-	service := NewTeamService(mockTeamRepo, mockHubRepo)
+	service := NewTeamService(nil, mockTeamRepo, mockHubRepo)
 
 	// Mock the FindByID method of HubRepository to return nil (hub not found)
 	mockHubRepo.On("FindByID", uint(1)).Return(nil, nil)
@@ -69,7 +93,7 @@ func TestCreateTeam_HubNotFound(t *testing.T) {
 func TestCreateTeam_HubError(t *testing.T) {
 	mockTeamRepo := new(mocks.TeamRepository)
 	mockHubRepo := new(mocks.HubRepository)
-	service := NewTeamService(mockTeamRepo, mockHubRepo)
+	service := NewTeamService(nil, mockTeamRepo, mockHubRepo)
 
 	// Mock the FindByID method of HubRepository to return an error
 	mockHubRepo.On("FindByID", uint(1)).Return(nil, errors.New("hub does not exist"))
@@ -94,7 +118,7 @@ func TestCreateTeam_HubError(t *testing.T) {
 func TestFindTeamsByHubID(t *testing.T) {
 	mockTeamRepo := new(mocks.TeamRepository)
 	mockHubRepo := new(mocks.HubRepository)
-	service := NewTeamService(mockTeamRepo, mockHubRepo)
+	service := NewTeamService(nil, mockTeamRepo, mockHubRepo)
 
 	// Mock the FindByHubID method of TeamRepository to return a list of teams
 	mockTeamRepo.On("FindByHubID", uint(1)).Return([]entity.Team{
@@ -118,7 +142,7 @@ func TestFindTeamsByHubID(t *testing.T) {
 func TestFindTeamsByHubID_NoResults(t *testing.T) {
 	mockTeamRepo := new(mocks.TeamRepository)
 	mockHubRepo := new(mocks.HubRepository)
-	service := NewTeamService(mockTeamRepo, mockHubRepo)
+	service := NewTeamService(nil, mockTeamRepo, mockHubRepo)
 
 	// Mock the FindByHubID method of TeamRepository to return an empty list
 	mockTeamRepo.On("FindByHubID", uint(1)).Return([]entity.Team{}, nil)
@@ -137,7 +161,7 @@ func TestFindTeamsByHubID_NoResults(t *testing.T) {
 func TestFindTeamsByHubID_Error(t *testing.T) {
 	mockTeamRepo := new(mocks.TeamRepository)
 	mockHubRepo := new(mocks.HubRepository)
-	service := NewTeamService(mockTeamRepo, mockHubRepo)
+	service := NewTeamService(nil, mockTeamRepo, mockHubRepo)
 
 	// Mock the FindByHubID method of TeamRepository to return an error
 	mockTeamRepo.On("FindByHubID", uint(1)).Return(nil, errors.New("unable to find teams"))
@@ -157,7 +181,7 @@ func TestFindTeamsByHubID_Error(t *testing.T) {
 func TestFindByID_Success(t *testing.T) {
 	mockTeamRepo := new(mocks.TeamRepository)
 	mockHubRepo := new(mocks.HubRepository)
-	service := NewTeamService(mockTeamRepo, mockHubRepo)
+	service := NewTeamService(nil, mockTeamRepo, mockHubRepo)
 
 	// Mock the FindByID method of TeamRepository to return a team
 	mockTeamRepo.On("FindByID", uint(1)).Return(&entity.Team{
@@ -181,7 +205,7 @@ func TestFindByID_Success(t *testing.T) {
 func TestFindByID_NotFound(t *testing.T) {
 	mockTeamRepo := new(mocks.TeamRepository)
 	mockHubRepo := new(mocks.HubRepository)
-	service := NewTeamService(mockTeamRepo, mockHubRepo)
+	service := NewTeamService(nil, mockTeamRepo, mockHubRepo)
 
 	// Mock the FindByID method of TeamRepository to return nil (team not found)
 	mockTeamRepo.On("FindByID", uint(1)).Return(nil, nil)
@@ -200,7 +224,7 @@ func TestFindByID_NotFound(t *testing.T) {
 func TestFindByID_Error(t *testing.T) {
 	mockTeamRepo := new(mocks.TeamRepository)
 	mockHubRepo := new(mocks.HubRepository)
-	service := NewTeamService(mockTeamRepo, mockHubRepo)
+	service := NewTeamService(nil, mockTeamRepo, mockHubRepo)
 
 	// Mock the FindByID method of TeamRepository to return an error
 	mockTeamRepo.On("FindByID", uint(1)).Return(nil, errors.New("unable to find team"))
@@ -215,3 +239,144 @@ func TestFindByID_Error(t *testing.T) {
 	mockHubRepo.AssertExpectations(t)
 	mockTeamRepo.AssertExpectations(t)
 }
+
+// TestCreateTeam_ParentInOtherHub tests that a parent team must belong to the same hub
+func TestCreateTeam_ParentInOtherHub(t *testing.T) {
+	mockTeamRepo := new(mocks.TeamRepository)
+	mockHubRepo := new(mocks.HubRepository)
+	service := NewTeamService(nil, mockTeamRepo, mockHubRepo)
+
+	mockHubRepo.On("FindByID", uint(1)).Return(&entity.Hub{ID: 1, Name: "Test Hub"}, nil)
+	mockTeamRepo.On("FindByID", uint(5)).Return(&entity.Team{ID: 5, Name: "Tribe", HubID: 2}, nil)
+
+	parentID := uint(5)
+	err := service.CreateTeam(&entity.Team{Name: "Squad", HubID: 1, ParentID: &parentID})
+
+	assert.ErrorIs(t, err, ErrParentHubMismatch)
+	mockTeamRepo.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+// TestSetParent_Success tests moving a team under another team of the same hub
+func TestSetParent_Success(t *testing.T) {
+	mockTeamRepo := new(mocks.TeamRepository)
+	service := NewTeamService(nil, mockTeamRepo, new(mocks.HubRepository))
+
+	mockTeamRepo.On("FindByID", uint(2)).Return(&entity.Team{ID: 2, Name: "Squad", HubID: 1}, nil)
+	mockTeamRepo.On("FindByID", uint(1)).Return(&entity.Team{ID: 1, Name: "Tribe", HubID: 1}, nil)
+	mockTeamRepo.On("FindAncestors", uint(1)).Return([]entity.Team{}, nil)
+	mockTeamRepo.On("Update", mock.AnythingOfType("*entity.Team")).Return(nil)
+
+	parentID := uint(1)
+	team, err := service.SetParent(2, AnyVersion, &parentID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, &parentID, team.ParentID)
+	mockTeamRepo.AssertExpectations(t)
+}
+
+// TestSetParent_Cycle tests that a team cannot be moved below one of its own sub-teams
+func TestSetParent_Cycle(t *testing.T) {
+	mockTeamRepo := new(mocks.TeamRepository)
+	service := NewTeamService(nil, mockTeamRepo, new(mocks.HubRepository))
+
+	mockTeamRepo.On("FindByID", uint(1)).Return(&entity.Team{ID: 1, Name: "Tribe", HubID: 1}, nil)
+	mockTeamRepo.On("FindByID", uint(3)).Return(&entity.Team{ID: 3, Name: "Chapter", HubID: 1}, nil)
+	mockTeamRepo.On("FindAncestors", uint(3)).Return([]entity.Team{{ID: 2, HubID: 1}, {ID: 1, HubID: 1}}, nil)
+
+	parentID := uint(3)
+	_, err := service.SetParent(1, AnyVersion, &parentID)
+	assert.ErrorIs(t, err, ErrTeamCycle)
+
+	selfID := uint(1)
+	_, err = service.SetParent(1, AnyVersion, &selfID)
+	assert.ErrorIs(t, err, ErrTeamCycle)
+	mockTeamRepo.AssertNotCalled(t, "Update", mock.Anything)
+}
+
+// TestPatchTeam tests that a patch can rename a team and change its parent, but not move it to another hub
+func TestPatchTeam(t *testing.T) {
+	mockTeamRepo := new(mocks.TeamRepository)
+	service := NewTeamService(nil, mockTeamRepo, new(mocks.HubRepository))
+
+	mockTeamRepo.On("FindByID", uint(2)).Return(func(uint) *entity.Team {
+		return &entity.Team{ID: 2, Name: "Squad", HubID: 1, ParentID: uintPtr(1)}
+	}, nil)
+	mockTeamRepo.On("FindByID", uint(3)).Return(&entity.Team{ID: 3, Name: "Chapter", HubID: 1}, nil)
+	mockTeamRepo.On("FindAncestors", uint(3)).Return([]entity.Team{}, nil)
+	mockTeamRepo.On("Update", mock.AnythingOfType("*entity.Team")).Return(nil)
+
+	team, err := service.PatchTeam(2, AnyVersion, MergePatch, []byte(`{"name": "Platform", "parent_id": null}`))
+	assert.NoError(t, err)
+	assert.Equal(t, "Platform", team.Name)
+	assert.Nil(t, team.ParentID)
+
+	team, err = service.PatchTeam(2, AnyVersion, JSONPatch, []byte(`[{"op": "replace", "path": "/parent_id", "value": 3}]`))
+	assert.NoError(t, err)
+	assert.Equal(t, uint(3), *team.ParentID)
+	mockTeamRepo.AssertCalled(t, "FindAncestors", uint(3))
+
+	_, err = service.PatchTeam(2, AnyVersion, MergePatch, []byte(`{"hub_id": 4}`))
+	assert.ErrorIs(t, err, ErrInvalidPatchResult)
+	_, err = service.PatchTeam(2, AnyVersion, MergePatch, []byte(`{"parent_id": 2}`))
+	assert.ErrorIs(t, err, ErrTeamCycle)
+	mockTeamRepo.AssertNumberOfCalls(t, "Update", 2)
+}
+
+// TestDeleteTeam tests that teams with users or sub-teams are not deleted
+func TestDeleteTeam(t *testing.T) {
+	mockTeamRepo := new(mocks.TeamRepository)
+	service := NewTeamService(nil, mockTeamRepo, new(mocks.HubRepository))
+
+	mockTeamRepo.On("FindByID", uint(1)).Return(&entity.Team{ID: 1, HubID: 1}, nil)
+	mockTeamRepo.On("FindByID", uint(2)).Return(&entity.Team{ID: 2, HubID: 1}, nil)
+	mockTeamRepo.On("FindByID", uint(3)).Return(&entity.Team{ID: 3, HubID: 1, Version: 2}, nil)
+	mockTeamRepo.On("CountUsers", []uint{1}).Return(map[uint]int64{1: 4}, nil)
+	mockTeamRepo.On("CountUsers", []uint{2}).Return(map[uint]int64{}, nil)
+	mockTeamRepo.On("CountUsers", []uint{3}).Return(map[uint]int64{}, nil)
+	mockTeamRepo.On("FindChildren", uint(1)).Return([]entity.Team{}, nil)
+	mockTeamRepo.On("FindChildren", uint(2)).Return([]entity.Team{{ID: 5, ParentID: uintPtr(2)}}, nil)
+	mockTeamRepo.On("FindChildren", uint(3)).Return([]entity.Team{}, nil)
+	mockTeamRepo.On("Delete", uint(3), uint(2)).Return(nil)
+
+	assert.ErrorIs(t, service.DeleteTeam(1, AnyVersion), ErrTeamNotEmpty)
+	assert.ErrorIs(t, service.DeleteTeam(2, AnyVersion), ErrTeamNotEmpty)
+	assert.NoError(t, service.DeleteTeam(3, AnyVersion))
+	mockTeamRepo.AssertNumberOfCalls(t, "Delete", 1)
+}
+
+// TestFindSubtree tests that sub-teams are nested under their parents with aggregated headcounts
+func TestFindSubtree(t *testing.T) {
+	mockTeamRepo := new(mocks.TeamRepository)
+	service := NewTeamService(nil, mockTeamRepo, new(mocks.HubRepository))
+
+	tribeID, squadID := uint(1), uint(2)
+	mockTeamRepo.On("FindByID", tribeID).Return(&entity.Team{ID: tribeID, Name: "Tribe", HubID: 1}, nil)
+	mockTeamRepo.On("FindDescendants", tribeID).Return([]entity.Team{
+		{ID: 2, Name: "Squad A", HubID: 1, ParentID: &tribeID},
+		{ID: 3, Name: "Squad B", HubID: 1, ParentID: &tribeID},
+		{ID: 4, Name: "Chapter", HubID: 1, ParentID: &squadID},
+	}, nil)
+	mockTeamRepo.On("CountUsers", []uint{1, 2, 3, 4}).Return(map[uint]int64{1: 1, 2: 2, 4: 3}, nil)
+
+	tree, err := service.FindSubtree(tribeID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), tree.Headcount)
+	assert.Equal(t, int64(6), tree.TotalHeadcount)
+	if assert.Len(t, tree.Children, 2) {
+		assert.Equal(t, int64(5), tree.Children[0].TotalHeadcount)
+		assert.Equal(t, uint(4), tree.Children[0].Children[0].ID)
+		assert.Empty(t, tree.Children[1].Children)
+	}
+}
+
+// TestFindHeadcount_TeamNotFound tests that a missing team is reported
+func TestFindHeadcount_TeamNotFound(t *testing.T) {
+	mockTeamRepo := new(mocks.TeamRepository)
+	service := NewTeamService(nil, mockTeamRepo, new(mocks.HubRepository))
+
+	mockTeamRepo.On("FindByID", uint(9)).Return(nil, gorm.ErrRecordNotFound)
+
+	_, err := service.FindHeadcount(9)
+	assert.ErrorIs(t, err, ErrTeamNotFound)
+}
@@ -0,0 +1,15 @@
+package service
+
+import "hub_management_service/internal/repository"
+
+// inTransaction runs the steps of an operation atomically: fn gets the service rebound by bind to the repositories
+// of a new transaction, so that every check and write in fn sees and commits the same state. Without a Transactor,
+// as with mock repositories, fn runs with the service as is.
+func inTransaction[S any](tx repository.Transactor, s S, bind func(repos repository.Repositories) S, fn func(s S) error) error {
+	if tx == nil {
+		return fn(s)
+	}
+	return tx.Transaction(func(repos repository.Repositories) error {
+		return fn(bind(repos))
+	})
+}
@@ -0,0 +1,187 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"hub_management_service/internal/entity"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+var ErrInvalidProfile = errors.New("invalid user profile")
+
+var ErrEmployeeNumberTaken = errors.New("employee number is already in use")
+
+// ProfileFieldError reports the profile field that failed validation, it matches ErrInvalidProfile
+type ProfileFieldError struct {
+	Field  string
+	Reason string
+}
+
+func (e *ProfileFieldError) Error() string {
+	return fmt.Sprintf("invalid %s: %s", e.Field, e.Reason)
+}
+
+func (e *ProfileFieldError) Is(target error) bool {
+	return target == ErrInvalidProfile
+}
+
+var (
+	phonePattern          = regexp.MustCompile(`^\+[1-9][0-9]{6,14}$`)
+	phoneSeparators       = strings.NewReplacer(" ", "", "-", "", ".", "", "(", "", ")", "")
+	employeeNumberPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9-]{0,63}$`)
+	localePattern         = regexp.MustCompile(`^[A-Za-z]{2,3}(-[A-Za-z0-9]{2,8})*$`)
+	employmentTypes       = map[string]bool{
+		entity.EmploymentFullTime:   true,
+		entity.EmploymentPartTime:   true,
+		entity.EmploymentContractor: true,
+		entity.EmploymentIntern:     true,
+		entity.EmploymentTemporary:  true,
+	}
+)
+
+// UserPatch holds the fields of a partial user update, nil fields are left unchanged.
+// An empty string clears an optional field, as does a zero start date.
+type UserPatch struct {
+	Name           *string      `json:"name"`
+	Email          *string      `json:"email"`
+	TeamID         *uint        `json:"team_id"`
+	JobTitle       *string      `json:"job_title"`
+	Phone          *string      `json:"phone"`
+	EmployeeNumber *string      `json:"employee_number"`
+	StartDate      *entity.Date `json:"start_date"`
+	AvatarURL      *string      `json:"avatar_url"`
+	Locale         *string      `json:"locale"`
+	TimeZone       *string      `json:"time_zone"`
+	EmploymentType *string      `json:"employment_type"`
+}
+
+// apply copies the set fields of the patch onto the user
+func (p *UserPatch) apply(user *entity.User) {
+	if p.Name != nil {
+		user.Name = *p.Name
+	}
+	if p.Email != nil {
+		user.Email = *p.Email
+	}
+	if p.TeamID != nil {
+		user.TeamID = *p.TeamID
+	}
+	if p.JobTitle != nil {
+		user.JobTitle = *p.JobTitle
+	}
+	if p.Phone != nil {
+		user.Phone = *p.Phone
+	}
+	if p.EmployeeNumber != nil {
+		user.EmployeeNumber = p.EmployeeNumber
+	}
+	if p.StartDate != nil {
+		user.StartDate = p.StartDate
+	}
+	if p.AvatarURL != nil {
+		user.AvatarURL = *p.AvatarURL
+	}
+	if p.Locale != nil {
+		user.Locale = *p.Locale
+	}
+	if p.TimeZone != nil {
+		user.TimeZone = *p.TimeZone
+	}
+	if p.EmploymentType != nil {
+		user.EmploymentType = *p.EmploymentType
+	}
+}
+
+// userPatchOf returns a patch setting every field of a UserPatch to its value in user, so that applying it clears
+// the optional fields user leaves empty
+func userPatchOf(user *entity.User) *UserPatch {
+	var employeeNumber string
+	if user.EmployeeNumber != nil {
+		employeeNumber = *user.EmployeeNumber
+	}
+	var startDate entity.Date
+	if user.StartDate != nil {
+		startDate = *user.StartDate
+	}
+	return &UserPatch{
+		Name:           &user.Name,
+		Email:          &user.Email,
+		TeamID:         &user.TeamID,
+		JobTitle:       &user.JobTitle,
+		Phone:          &user.Phone,
+		EmployeeNumber: &employeeNumber,
+		StartDate:      &startDate,
+		AvatarURL:      &user.AvatarURL,
+		Locale:         &user.Locale,
+		TimeZone:       &user.TimeZone,
+		EmploymentType: &user.EmploymentType,
+	}
+}
+
+// normalizeProfile trims and validates the optional profile fields of a user in place
+func normalizeProfile(user *entity.User) error {
+	user.Name = strings.TrimSpace(user.Name)
+	if user.Name == "" {
+		return &ProfileFieldError{Field: "name", Reason: "must not be empty"}
+	}
+
+	user.JobTitle = strings.TrimSpace(user.JobTitle)
+	if len(user.JobTitle) > 255 {
+		return &ProfileFieldError{Field: "job_title", Reason: "must be at most 255 characters"}
+	}
+
+	user.Phone = phoneSeparators.Replace(strings.TrimSpace(user.Phone))
+	if user.Phone != "" && !phonePattern.MatchString(user.Phone) {
+		return &ProfileFieldError{Field: "phone", Reason: "must be in international format, e.g. +4930123456"}
+	}
+
+	if user.EmployeeNumber != nil {
+		employeeNumber := strings.TrimSpace(*user.EmployeeNumber)
+		if employeeNumber == "" {
+			user.EmployeeNumber = nil
+		} else if !employeeNumberPattern.MatchString(employeeNumber) {
+			return &ProfileFieldError{Field: "employee_number", Reason: "must contain only letters, digits and dashes"}
+		} else {
+			user.EmployeeNumber = &employeeNumber
+		}
+	}
+
+	if user.StartDate != nil && user.StartDate.IsZero() {
+		user.StartDate = nil
+	}
+
+	user.AvatarURL = strings.TrimSpace(user.AvatarURL)
+	if user.AvatarURL != "" {
+		u, err := url.Parse(user.AvatarURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" || len(user.AvatarURL) > 2048 {
+			return &ProfileFieldError{Field: "avatar_url", Reason: "must be an absolute http or https URL"}
+		}
+	}
+
+	user.Locale = strings.TrimSpace(user.Locale)
+	if user.Locale != "" && !localePattern.MatchString(user.Locale) {
+		return &ProfileFieldError{Field: "locale", Reason: "must be a BCP 47 language tag, e.g. en-GB"}
+	}
+
+	user.TimeZone = strings.TrimSpace(user.TimeZone)
+	if user.TimeZone != "" {
+		if !validTimeZone(user.TimeZone) {
+			return &ProfileFieldError{Field: "time_zone", Reason: "must be an IANA time zone, e.g. Europe/Berlin"}
+		}
+	}
+
+	user.EmploymentType = strings.TrimSpace(user.EmploymentType)
+	if user.EmploymentType != "" && !employmentTypes[user.EmploymentType] {
+		return &ProfileFieldError{Field: "employment_type", Reason: "must be one of full_time, part_time, contractor, intern or temporary"}
+	}
+	return nil
+}
+
+// validTimeZone reports whether name is an IANA time zone, the process-dependent "Local" is not accepted
+func validTimeZone(name string) bool {
+	_, err := time.LoadLocation(name)
+	return err == nil && name != "Local"
+}
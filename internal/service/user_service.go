@@ -2,36 +2,448 @@ package service
 
 import (
 	"errors"
+	"fmt"
 	"hub_management_service/internal/entity"
 	"hub_management_service/internal/repository"
+	"strings"
 )
 
+var (
+	ErrUserNotFound = errors.New("user not found")
+	ErrTeamNotFound = errors.New("team does not exist")
+	ErrEmailTaken   = errors.New("email is already in use")
+
+	ErrManagerNotFound = errors.New("manager does not exist")
+	ErrManagerCycle    = errors.New("manager would create a reporting cycle")
+)
+
+// Page size limits of SearchUsers
+const (
+	defaultUserSearchLimit = 50
+	maxUserSearchLimit     = 200
+)
+
+// UserQuery filters a user search, empty fields are ignored
+type UserQuery struct {
+	Query          string // Case-insensitive substring of the name, email or job title
+	TeamID         uint
+	JobTitle       string // Case-insensitive substring of the job title
+	EmploymentType string
+	EmployeeNumber string
+	Locale         string
+	TimeZone       string
+	Offset         int
+	Limit          int
+}
+
+// EmailConflictError reports the user that already owns an email address, it matches ErrEmailTaken
+type EmailConflictError struct {
+	UserID uint
+}
+
+func (e *EmailConflictError) Error() string {
+	return fmt.Sprintf("%s by user %d", ErrEmailTaken, e.UserID)
+}
+
+func (e *EmailConflictError) Is(target error) bool {
+	return target == ErrEmailTaken
+}
+
 type UserService interface {
-	CreateUser(user *entity.User) error
+	CreateUser(user *entity.User, opts WriteOptions) error
 	FindUserByID(id uint) (*entity.User, error)
 	FindUserByTeamID(teamID uint) ([]entity.User, error)
+	FindUserByEmail(email string) (*entity.User, error)
+	UpdateUser(id, version uint, patch *UserPatch, opts WriteOptions) (*entity.User, error)
+	SearchUsers(query UserQuery) ([]entity.User, int64, error)
+	PatchUser(id, version uint, format PatchFormat, patch []byte, opts WriteOptions) (*entity.User, error)
+	SetManager(id, version uint, managerID *uint) (*entity.User, error)
+	DeleteUser(id, version uint) error
+	FindDirectReports(id uint) ([]entity.User, error)
+	FindReportingChain(id uint) ([]entity.User, error)
+	FindSkipLevel(id uint) (*SkipLevel, error)
+}
+
+// SkipLevel holds the manager two levels above a user and the reports two levels below
+type SkipLevel struct {
+	Manager *entity.User  `json:"manager"`
+	Reports []entity.User `json:"reports"`
 }
 type userService struct {
+	tx       repository.Transactor
 	repo     repository.UserRepository
 	teamRepo repository.TeamRepository // Add team repository to check if a team exists
+	capacity *CapacityPolicy           // Limits moves into full hubs, nil for no limits
+}
+
+func NewUserService(tx repository.Transactor, repo repository.UserRepository, teamRepo repository.TeamRepository, capacity *CapacityPolicy) UserService {
+	return &userService{tx: tx, repo: repo, teamRepo: teamRepo, capacity: capacity}
+}
+
+// bind returns the service working with the repositories of a transaction
+func (s *userService) bind(repos repository.Repositories) *userService {
+	return &userService{tx: repos, repo: repos.Users, teamRepo: repos.Teams, capacity: s.capacity.bind(repos)}
 }
 
-func NewUserService(repo repository.UserRepository, teamRepo repository.TeamRepository) UserService {
-	return &userService{repo: repo, teamRepo: teamRepo}
+// CreateUser checks the team, uniqueness, manager and hub capacity and creates the user in one transaction
+func (s *userService) CreateUser(user *entity.User, opts WriteOptions) error {
+	var writeErr error
+	err := inTransaction(s.tx, s, s.bind, func(s *userService) error {
+		if err := s.checkNewUser(user, opts); err != nil {
+			return err
+		}
+		writeErr = s.repo.Create(user)
+		return writeErr
+	})
+	if writeErr != nil {
+		// A concurrent insert of the same email fails on the unique index, report it as a conflict.
+		// The check runs after the rollback, a failed statement aborts the transaction on Postgres.
+		if conflictErr := s.checkUnique(user); conflictErr != nil {
+			return conflictErr
+		}
+	}
+	return err
 }
 
-func (s *userService) CreateUser(user *entity.User) error {
+// checkNewUser normalizes a new user and checks that it can be created
+func (s *userService) checkNewUser(user *entity.User, opts WriteOptions) error {
+	email, err := NormalizeEmail(user.Email)
+	if err != nil {
+		return err
+	}
+	user.Email = email
+	if err := normalizeProfile(user); err != nil {
+		return err
+	}
+
 	// Check if the team exists before creating the user
 	team, err := s.teamRepo.FindByID(user.TeamID)
 	if err != nil {
 		return err
 	}
 	if team == nil {
-		return errors.New("team does not exist")
+		return ErrTeamNotFound
+	}
+
+	if err := s.checkUnique(user); err != nil {
+		return err
+	}
+	if user.ManagerID != nil {
+		if _, err := s.findManager(*user.ManagerID); err != nil {
+			return err
+		}
+	}
+	return s.capacity.CheckRoom(user.TeamID, 0, opts)
+}
+
+// UpdateUser applies a partial update to a user at version and returns the updated user, in one transaction
+func (s *userService) UpdateUser(id, version uint, patch *UserPatch, opts WriteOptions) (*entity.User, error) {
+	return s.saveUser(func(s *userService) (*entity.User, error) {
+		return s.patchUser(id, version, patch, opts)
+	})
+}
+
+// PatchUser applies a JSON Merge Patch or JSON Patch document to a user at version, with the checks of UpdateUser
+// and SetManager, in one transaction
+func (s *userService) PatchUser(id, version uint, format PatchFormat, patch []byte, opts WriteOptions) (*entity.User, error) {
+	return s.saveUser(func(s *userService) (*entity.User, error) {
+		user, err := s.findUser(id)
+		if err != nil {
+			return nil, err
+		}
+		if err := checkVersion("user", user.Version, version); err != nil {
+			return nil, err
+		}
+		current := *user
+		current.Team = nil
+		var update entity.User
+		if err := applyPatch(format, &current, patch, &update, "id", "deactivated_at", "team"); err != nil {
+			return nil, err
+		}
+
+		if err := s.changeUser(user, userPatchOf(&update), opts); err != nil {
+			return nil, err
+		}
+		if !sameID(update.ManagerID, user.ManagerID) {
+			if err := s.checkManager(user, update.ManagerID); err != nil {
+				return nil, err
+			}
+			user.ManagerID = update.ManagerID
+		}
+		return user, nil
+	})
+}
+
+// saveUser saves the user returned by change in the same transaction, a unique field taken concurrently is
+// reported as a conflict
+func (s *userService) saveUser(change func(s *userService) (*entity.User, error)) (*entity.User, error) {
+	var user *entity.User
+	var writeErr error
+	err := inTransaction(s.tx, s, s.bind, func(s *userService) (err error) {
+		if user, err = change(s); err != nil {
+			return err
+		}
+		writeErr = versionError(s.repo.Update(user))
+		return writeErr
+	})
+	if writeErr != nil {
+		if conflictErr := s.checkUnique(user); conflictErr != nil {
+			return nil, conflictErr
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// patchUser applies a partial update to a user and checks that the result can be saved
+func (s *userService) patchUser(id, version uint, patch *UserPatch, opts WriteOptions) (*entity.User, error) {
+	user, err := s.findUser(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkVersion("user", user.Version, version); err != nil {
+		return nil, err
+	}
+	return user, s.changeUser(user, patch, opts)
+}
+
+// changeUser applies a partial update to a user in place and checks that the result can be saved
+func (s *userService) changeUser(user *entity.User, patch *UserPatch, opts WriteOptions) error {
+	teamID := user.TeamID
+	patch.apply(user)
+	if patch.Email != nil {
+		email, err := NormalizeEmail(user.Email)
+		if err != nil {
+			return err
+		}
+		user.Email = email
+	}
+	if err := normalizeProfile(user); err != nil {
+		return err
+	}
+
+	if user.TeamID != teamID {
+		team, err := s.teamRepo.FindByID(user.TeamID)
+		if repository.IsNotFound(err) || (err == nil && team == nil) {
+			return ErrTeamNotFound
+		}
+		if err != nil {
+			return err
+		}
+		if err := s.capacity.CheckRoom(user.TeamID, teamID, opts); err != nil {
+			return err
+		}
+	}
+
+	return s.checkUnique(user)
+}
+
+// SearchUsers returns a page of users matching the query, along with the total number of matches
+func (s *userService) SearchUsers(query UserQuery) ([]entity.User, int64, error) {
+	var conditions []string
+	var args []interface{}
+	if q := strings.TrimSpace(query.Query); q != "" {
+		pattern := containsPattern(q)
+		conditions = append(conditions, `(LOWER(name) LIKE LOWER(?) ESCAPE '\' OR LOWER(email) LIKE LOWER(?) ESCAPE '\' OR LOWER(job_title) LIKE LOWER(?) ESCAPE '\')`)
+		args = append(args, pattern, pattern, pattern)
+	}
+	if query.TeamID != 0 {
+		conditions = append(conditions, "team_id = ?")
+		args = append(args, query.TeamID)
+	}
+	if jobTitle := strings.TrimSpace(query.JobTitle); jobTitle != "" {
+		conditions = append(conditions, `LOWER(job_title) LIKE LOWER(?) ESCAPE '\'`)
+		args = append(args, containsPattern(jobTitle))
+	}
+	for _, exact := range []struct{ column, value string }{
+		{"employment_type", query.EmploymentType},
+		{"employee_number", query.EmployeeNumber},
+		{"locale", query.Locale},
+		{"time_zone", query.TimeZone},
+	} {
+		if value := strings.TrimSpace(exact.value); value != "" {
+			conditions = append(conditions, exact.column+" = ?")
+			args = append(args, value)
+		}
+	}
+
+	limit := query.Limit
+	if limit <= 0 {
+		limit = defaultUserSearchLimit
+	}
+	if limit > maxUserSearchLimit {
+		limit = maxUserSearchLimit
+	}
+	return s.repo.List(repository.ListOptions{
+		Where:  strings.Join(conditions, " AND "),
+		Args:   args,
+		Offset: query.Offset,
+		Limit:  limit,
+	})
+}
+
+// FindUserByEmail finds a user by email address regardless of case
+func (s *userService) FindUserByEmail(email string) (*entity.User, error) {
+	email, err := NormalizeEmail(email)
+	if err != nil {
+		return nil, err
+	}
+	user, err := s.repo.FindByEmail(email)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, ErrUserNotFound
+	}
+	return user, nil
+}
+
+// SetManager sets or, with a nil managerID, clears the manager of a user at version
+func (s *userService) SetManager(id, version uint, managerID *uint) (*entity.User, error) {
+	var user *entity.User
+	err := inTransaction(s.tx, s, s.bind, func(s *userService) (err error) {
+		user, err = s.setManager(id, version, managerID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+func (s *userService) setManager(id, version uint, managerID *uint) (*entity.User, error) {
+	user, err := s.findUser(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkVersion("user", user.Version, version); err != nil {
+		return nil, err
+	}
+	if err := s.checkManager(user, managerID); err != nil {
+		return nil, err
+	}
+
+	user.ManagerID = managerID
+	if err := s.repo.Update(user); err != nil {
+		return nil, versionError(err)
+	}
+	return user, nil
+}
+
+// checkManager checks that managerID can become the manager of a user, a nil managerID removing the manager
+func (s *userService) checkManager(user *entity.User, managerID *uint) error {
+	if managerID == nil {
+		return nil
+	}
+	if *managerID == user.ID {
+		return ErrManagerCycle
+	}
+	if _, err := s.findManager(*managerID); err != nil {
+		return err
+	}
+	// The new manager must not report to the user, directly or indirectly
+	chain, err := s.repo.FindReportingChain(*managerID)
+	if err != nil {
+		return err
+	}
+	for _, manager := range chain {
+		if manager.ID == user.ID {
+			return ErrManagerCycle
+		}
+	}
+	return nil
+}
+
+// DeleteUser deletes a user at version along with their memberships, their reports are left without a manager
+func (s *userService) DeleteUser(id, version uint) error {
+	return inTransaction(s.tx, s, s.bind, func(s *userService) error {
+		user, err := s.findUser(id)
+		if err != nil {
+			return err
+		}
+		if err := checkVersion("user", user.Version, version); err != nil {
+			return err
+		}
+		return versionError(s.repo.Delete(id, user.Version))
+	})
+}
+
+// FindDirectReports returns the users reporting to a manager
+func (s *userService) FindDirectReports(id uint) ([]entity.User, error) {
+	if _, err := s.findUser(id); err != nil {
+		return nil, err
+	}
+	return s.repo.FindDirectReports(id)
+}
+
+// FindReportingChain returns the managers above a user, nearest first
+func (s *userService) FindReportingChain(id uint) ([]entity.User, error) {
+	if _, err := s.findUser(id); err != nil {
+		return nil, err
+	}
+	return s.repo.FindReportingChain(id)
+}
+
+// FindSkipLevel returns the manager's manager of a user and the reports of their direct reports
+func (s *userService) FindSkipLevel(id uint) (*SkipLevel, error) {
+	if _, err := s.findUser(id); err != nil {
+		return nil, err
+	}
+	chain, err := s.repo.FindReportingChain(id)
+	if err != nil {
+		return nil, err
+	}
+	reports, err := s.repo.FindSkipLevelReports(id)
+	if err != nil {
+		return nil, err
+	}
+
+	skipLevel := &SkipLevel{Reports: reports}
+	if len(chain) > 1 {
+		skipLevel.Manager = &chain[1]
+	}
+	return skipLevel, nil
+}
+
+func (s *userService) findUser(id uint) (*entity.User, error) {
+	user, err := s.repo.FindByID(id)
+	if repository.IsNotFound(err) || (err == nil && user == nil) {
+		return nil, ErrUserNotFound
+	}
+	return user, err
+}
+
+func (s *userService) findManager(id uint) (*entity.User, error) {
+	manager, err := s.findUser(id)
+	if errors.Is(err, ErrUserNotFound) {
+		return nil, ErrManagerNotFound
+	}
+	return manager, err
+}
+
+// checkUnique returns a conflict error if another user already owns the email or employee number
+func (s *userService) checkUnique(user *entity.User) error {
+	existing, err := s.repo.FindByEmail(user.Email)
+	if err != nil {
+		return err
+	}
+	if existing != nil && existing.ID != user.ID {
+		return &EmailConflictError{UserID: existing.ID}
 	}
 
-	// Proceed to create the user if the team exists
-	return s.repo.Create(user)
+	if user.EmployeeNumber == nil {
+		return nil
+	}
+	existing, err = s.repo.FindByEmployeeNumber(*user.EmployeeNumber)
+	if err != nil {
+		return err
+	}
+	if existing != nil && existing.ID != user.ID {
+		return fmt.Errorf("%w by user %d", ErrEmployeeNumberTaken, existing.ID)
+	}
+	return nil
 }
 
 func (s *userService) FindUserByID(id uint) (*entity.User, error) {
@@ -40,6 +452,11 @@ func (s *userService) FindUserByID(id uint) (*entity.User, error) {
 
 // FindUserByTeamID New method to find users by TeamID
 func (s *userService) FindUserByTeamID(teamID uint) ([]entity.User, error) {
-	// Find users by TeamID using the repository
-	return s.repo.FindUserByTeamID(teamID)
+	// Find the current members of the team, whether it is their primary team or not
+	return s.repo.FindMembersByTeamID(teamID)
+}
+
+// containsPattern builds a LIKE pattern matching the value anywhere, escaping LIKE wildcards
+func containsPattern(value string) string {
+	return "%" + strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(value) + "%"
 }
@@ -3,18 +3,20 @@ package service
 import (
 	"errors"
 	"hub_management_service/internal/entity"
+	"hub_management_service/internal/repository"
 	"hub_management_service/internal/repository/mocks"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
 )
 
 // TestCreateUser_Success tests the CreateUser service method when the Team exists
 func TestCreateUser_Success(t *testing.T) {
 	mockUserRepo := new(mocks.UserRepository)
 	mockTeamRepo := new(mocks.TeamRepository)
-	service := NewUserService(mockUserRepo, mockTeamRepo)
+	service := NewUserService(nil, mockUserRepo, mockTeamRepo, nil)
 
 	// Mock the FindByID method of TeamRepository to return a team
 	mockTeamRepo.On("FindByID", uint(1)).Return(&entity.Team{
@@ -22,17 +24,21 @@ func TestCreateUser_Success(t *testing.T) {
 		Name: "Test Team",
 	}, nil)
 
+	// Mock the FindByEmail method of UserRepository to return no existing user
+	mockUserRepo.On("FindByEmail", "testuser@example.com").Return(nil, nil)
+
 	// Mock the Create method of UserRepository to return nil (no error)
 	mockUserRepo.On("Create", mock.AnythingOfType("*entity.User")).Return(nil)
 
 	// Create a new User entity
 	user := &entity.User{
 		Name:   "Test User",
+		Email:  "testuser@example.com",
 		TeamID: 1,
 	}
 
 	// Call the CreateUser service method
-	err := service.CreateUser(user)
+	err := service.CreateUser(user, WriteOptions{})
 
 	// Assert that there is no error
 	assert.NoError(t, err)
@@ -44,7 +50,7 @@ func TestCreateUser_Success(t *testing.T) {
 func TestCreateUser_TeamNotFound(t *testing.T) {
 	mockUserRepo := new(mocks.UserRepository)
 	mockTeamRepo := new(mocks.TeamRepository)
-	service := NewUserService(mockUserRepo, mockTeamRepo)
+	service := NewUserService(nil, mockUserRepo, mockTeamRepo, nil)
 
 	// Mock the FindByID method of TeamRepository to return nil (team not found)
 	mockTeamRepo.On("FindByID", uint(1)).Return(nil, nil)
@@ -52,11 +58,12 @@ func TestCreateUser_TeamNotFound(t *testing.T) {
 	// Create a new User entity
 	user := &entity.User{
 		Name:   "Test User",
+		Email:  "testuser@example.com",
 		TeamID: 1,
 	}
 
 	// Call the CreateUser service method
-	err := service.CreateUser(user)
+	err := service.CreateUser(user, WriteOptions{})
 
 	// Assert that an error is returned
 	assert.Error(t, err)
@@ -69,7 +76,7 @@ func TestCreateUser_TeamNotFound(t *testing.T) {
 func TestCreateUser_TeamError(t *testing.T) {
 	mockUserRepo := new(mocks.UserRepository)
 	mockTeamRepo := new(mocks.TeamRepository)
-	service := NewUserService(mockUserRepo, mockTeamRepo)
+	service := NewUserService(nil, mockUserRepo, mockTeamRepo, nil)
 
 	// Mock the FindByID method of TeamRepository to return an error
 	mockTeamRepo.On("FindByID", uint(1)).Return(nil, errors.New("unable to find team"))
@@ -77,11 +84,12 @@ func TestCreateUser_TeamError(t *testing.T) {
 	// Create a new User entity
 	user := &entity.User{
 		Name:   "Test User",
+		Email:  "testuser@example.com",
 		TeamID: 1,
 	}
 
 	// Call the CreateUser service method
-	err := service.CreateUser(user)
+	err := service.CreateUser(user, WriteOptions{})
 
 	// Assert that an error is returned
 	assert.Error(t, err)
@@ -94,7 +102,7 @@ func TestCreateUser_TeamError(t *testing.T) {
 func TestFindUserByID_Success(t *testing.T) {
 	mockUserRepo := new(mocks.UserRepository)
 	mockTeamRepo := new(mocks.TeamRepository)
-	service := NewUserService(mockUserRepo, mockTeamRepo)
+	service := NewUserService(nil, mockUserRepo, mockTeamRepo, nil)
 
 	// Mock the FindByID method of UserRepository to return a user
 	mockUserRepo.On("FindByID", uint(1)).Return(&entity.User{
@@ -118,7 +126,7 @@ func TestFindUserByID_Success(t *testing.T) {
 func TestFindUserByID_NotFound(t *testing.T) {
 	mockUserRepo := new(mocks.UserRepository)
 	mockTeamRepo := new(mocks.TeamRepository)
-	service := NewUserService(mockUserRepo, mockTeamRepo)
+	service := NewUserService(nil, mockUserRepo, mockTeamRepo, nil)
 
 	// Mock the FindByID method of UserRepository to return nil (user not found)
 	mockUserRepo.On("FindByID", uint(1)).Return(nil, nil)
@@ -137,7 +145,7 @@ func TestFindUserByID_NotFound(t *testing.T) {
 func TestFindUserByID_Error(t *testing.T) {
 	mockUserRepo := new(mocks.UserRepository)
 	mockTeamRepo := new(mocks.TeamRepository)
-	service := NewUserService(mockUserRepo, mockTeamRepo)
+	service := NewUserService(nil, mockUserRepo, mockTeamRepo, nil)
 
 	// Mock the FindByID method of UserRepository to return an error
 	mockUserRepo.On("FindByID", uint(1)).Return(nil, errors.New("unable to find user"))
@@ -157,10 +165,10 @@ func TestFindUserByID_Error(t *testing.T) {
 func TestFindUserByTeamID_Success(t *testing.T) {
 	mockUserRepo := new(mocks.UserRepository)
 	mockTeamRepo := new(mocks.TeamRepository)
-	service := NewUserService(mockUserRepo, mockTeamRepo)
+	service := NewUserService(nil, mockUserRepo, mockTeamRepo, nil)
 
-	// Mock the FindUserByTeamID method of UserRepository to return a list of users
-	mockUserRepo.On("FindUserByTeamID", uint(1)).Return([]entity.User{
+	// Mock the FindMembersByTeamID method of UserRepository to return a list of users
+	mockUserRepo.On("FindMembersByTeamID", uint(1)).Return([]entity.User{
 		{ID: 1, Name: "User 1", TeamID: 1},
 		{ID: 2, Name: "User 2", TeamID: 1},
 	}, nil)
@@ -181,10 +189,10 @@ func TestFindUserByTeamID_Success(t *testing.T) {
 func TestFindUserByTeamID_NoResults(t *testing.T) {
 	mockUserRepo := new(mocks.UserRepository)
 	mockTeamRepo := new(mocks.TeamRepository)
-	service := NewUserService(mockUserRepo, mockTeamRepo)
+	service := NewUserService(nil, mockUserRepo, mockTeamRepo, nil)
 
-	// Mock the FindUserByTeamID method of UserRepository to return an empty list
-	mockUserRepo.On("FindUserByTeamID", uint(1)).Return([]entity.User{}, nil)
+	// Mock the FindMembersByTeamID method of UserRepository to return an empty list
+	mockUserRepo.On("FindMembersByTeamID", uint(1)).Return([]entity.User{}, nil)
 
 	// Call the FindUserByTeamID service method
 	users, err := service.FindUserByTeamID(1)
@@ -200,10 +208,10 @@ func TestFindUserByTeamID_NoResults(t *testing.T) {
 func TestFindUserByTeamID_Error(t *testing.T) {
 	mockUserRepo := new(mocks.UserRepository)
 	mockTeamRepo := new(mocks.TeamRepository)
-	service := NewUserService(mockUserRepo, mockTeamRepo)
+	service := NewUserService(nil, mockUserRepo, mockTeamRepo, nil)
 
-	// Mock the FindUserByTeamID method of UserRepository to return an error
-	mockUserRepo.On("FindUserByTeamID", uint(1)).Return(nil, errors.New("unable to find users"))
+	// Mock the FindMembersByTeamID method of UserRepository to return an error
+	mockUserRepo.On("FindMembersByTeamID", uint(1)).Return(nil, errors.New("unable to find users"))
 
 	// Call the FindUserByTeamID service method
 	users, err := service.FindUserByTeamID(1)
@@ -215,3 +223,299 @@ func TestFindUserByTeamID_Error(t *testing.T) {
 	mockTeamRepo.AssertExpectations(t)
 	mockUserRepo.AssertExpectations(t)
 }
+
+// TestCreateUser_NormalizesEmail tests that the email is trimmed and lower-cased before it is stored
+func TestCreateUser_NormalizesEmail(t *testing.T) {
+	mockUserRepo := new(mocks.UserRepository)
+	mockTeamRepo := new(mocks.TeamRepository)
+	service := NewUserService(nil, mockUserRepo, mockTeamRepo, nil)
+
+	mockTeamRepo.On("FindByID", uint(1)).Return(&entity.Team{ID: 1}, nil)
+	mockUserRepo.On("FindByEmail", "jane.doe@example.com").Return(nil, nil)
+	mockUserRepo.On("Create", mock.MatchedBy(func(u *entity.User) bool { return u.Email == "jane.doe@example.com" })).Return(nil)
+
+	err := service.CreateUser(&entity.User{Name: "Jane", Email: "  Jane.Doe@Example.COM ", TeamID: 1}, WriteOptions{})
+
+	assert.NoError(t, err)
+	mockUserRepo.AssertExpectations(t)
+}
+
+// TestCreateUser_InvalidEmail tests that malformed emails are rejected before any lookup
+func TestCreateUser_InvalidEmail(t *testing.T) {
+	mockUserRepo := new(mocks.UserRepository)
+	mockTeamRepo := new(mocks.TeamRepository)
+	service := NewUserService(nil, mockUserRepo, mockTeamRepo, nil)
+
+	for _, email := range []string{"", "not-an-email", "jane@", "Jane <jane@example.com>", "jane@localhost"} {
+		err := service.CreateUser(&entity.User{Name: "Jane", Email: email, TeamID: 1}, WriteOptions{})
+		assert.ErrorIs(t, err, ErrInvalidEmail, email)
+	}
+	mockTeamRepo.AssertNotCalled(t, "FindByID", mock.Anything)
+}
+
+// TestCreateUser_EmailTaken tests that a duplicate email reports the conflicting user
+func TestCreateUser_EmailTaken(t *testing.T) {
+	mockUserRepo := new(mocks.UserRepository)
+	mockTeamRepo := new(mocks.TeamRepository)
+	service := NewUserService(nil, mockUserRepo, mockTeamRepo, nil)
+
+	mockTeamRepo.On("FindByID", uint(1)).Return(&entity.Team{ID: 1}, nil)
+	mockUserRepo.On("FindByEmail", "jane@example.com").Return(&entity.User{ID: 7, Email: "jane@example.com"}, nil)
+
+	err := service.CreateUser(&entity.User{Name: "Jane", Email: "JANE@example.com", TeamID: 1}, WriteOptions{})
+
+	var conflictErr *EmailConflictError
+	assert.ErrorIs(t, err, ErrEmailTaken)
+	if assert.ErrorAs(t, err, &conflictErr) {
+		assert.Equal(t, uint(7), conflictErr.UserID)
+	}
+	mockUserRepo.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+// TestCreateUser_ConcurrentDuplicate tests that a unique index violation is reported as a conflict
+func TestCreateUser_ConcurrentDuplicate(t *testing.T) {
+	mockUserRepo := new(mocks.UserRepository)
+	mockTeamRepo := new(mocks.TeamRepository)
+	service := NewUserService(nil, mockUserRepo, mockTeamRepo, nil)
+
+	mockTeamRepo.On("FindByID", uint(1)).Return(&entity.Team{ID: 1}, nil)
+	mockUserRepo.On("FindByEmail", "jane@example.com").Return(nil, nil).Once()
+	mockUserRepo.On("Create", mock.AnythingOfType("*entity.User")).Return(errors.New("duplicate key value violates unique constraint"))
+	mockUserRepo.On("FindByEmail", "jane@example.com").Return(&entity.User{ID: 8}, nil).Once()
+
+	err := service.CreateUser(&entity.User{Name: "Jane", Email: "jane@example.com", TeamID: 1}, WriteOptions{})
+
+	var conflictErr *EmailConflictError
+	if assert.ErrorAs(t, err, &conflictErr) {
+		assert.Equal(t, uint(8), conflictErr.UserID)
+	}
+}
+
+// TestFindUserByEmail tests the case-insensitive lookup by email
+func TestFindUserByEmail(t *testing.T) {
+	mockUserRepo := new(mocks.UserRepository)
+	mockTeamRepo := new(mocks.TeamRepository)
+	service := NewUserService(nil, mockUserRepo, mockTeamRepo, nil)
+
+	mockUserRepo.On("FindByEmail", "jane@example.com").Return(&entity.User{ID: 7, Email: "jane@example.com"}, nil)
+	mockUserRepo.On("FindByEmail", "john@example.com").Return(nil, nil)
+
+	user, err := service.FindUserByEmail("Jane@Example.com")
+	assert.NoError(t, err)
+	assert.Equal(t, uint(7), user.ID)
+
+	_, err = service.FindUserByEmail("john@example.com")
+	assert.ErrorIs(t, err, ErrUserNotFound)
+
+	_, err = service.FindUserByEmail("john")
+	assert.ErrorIs(t, err, ErrInvalidEmail)
+}
+
+// TestCreateUser_InvalidProfile tests that each profile field is validated
+func TestCreateUser_InvalidProfile(t *testing.T) {
+	mockUserRepo := new(mocks.UserRepository)
+	mockTeamRepo := new(mocks.TeamRepository)
+	service := NewUserService(nil, mockUserRepo, mockTeamRepo, nil)
+
+	employeeNumber := "E 1"
+	for field, user := range map[string]*entity.User{
+		"phone":           {Phone: "12345"},
+		"employee_number": {EmployeeNumber: &employeeNumber},
+		"avatar_url":      {AvatarURL: "javascript:alert(1)"},
+		"locale":          {Locale: "english"},
+		"time_zone":       {TimeZone: "Mars/Olympus"},
+		"employment_type": {EmploymentType: "freelance"},
+	} {
+		user.Name, user.Email, user.TeamID = "Jane", "jane@example.com", 1
+		err := service.CreateUser(user, WriteOptions{})
+
+		var fieldErr *ProfileFieldError
+		assert.ErrorIs(t, err, ErrInvalidProfile, field)
+		if assert.ErrorAs(t, err, &fieldErr) {
+			assert.Equal(t, field, fieldErr.Field)
+		}
+	}
+	mockUserRepo.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+// TestUpdateUser tests that only the patched fields change and are normalized
+func TestUpdateUser(t *testing.T) {
+	mockUserRepo := new(mocks.UserRepository)
+	mockTeamRepo := new(mocks.TeamRepository)
+	service := NewUserService(nil, mockUserRepo, mockTeamRepo, nil)
+
+	mockUserRepo.On("FindByID", uint(7)).Return(&entity.User{ID: 7, Name: "Jane", Email: "jane@example.com", TeamID: 1, JobTitle: "Engineer"}, nil)
+	mockUserRepo.On("FindByEmail", "jane@example.com").Return(&entity.User{ID: 7}, nil)
+	mockUserRepo.On("FindByEmployeeNumber", "E-100").Return(nil, nil)
+	mockUserRepo.On("Update", mock.AnythingOfType("*entity.User")).Return(nil)
+
+	phone, employeeNumber, timeZone := "+49 (30) 123-456", " E-100 ", "Europe/Berlin"
+	user, err := service.UpdateUser(7, AnyVersion, &UserPatch{Phone: &phone, EmployeeNumber: &employeeNumber, TimeZone: &timeZone}, WriteOptions{})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Engineer", user.JobTitle)
+	assert.Equal(t, "+4930123456", user.Phone)
+	assert.Equal(t, "E-100", *user.EmployeeNumber)
+	assert.Equal(t, "Europe/Berlin", user.TimeZone)
+	mockUserRepo.AssertExpectations(t)
+	mockTeamRepo.AssertNotCalled(t, "FindByID", mock.Anything)
+}
+
+// TestUpdateUser_EmployeeNumberTaken tests that employee numbers are unique
+func TestUpdateUser_EmployeeNumberTaken(t *testing.T) {
+	mockUserRepo := new(mocks.UserRepository)
+	mockTeamRepo := new(mocks.TeamRepository)
+	service := NewUserService(nil, mockUserRepo, mockTeamRepo, nil)
+
+	mockUserRepo.On("FindByID", uint(7)).Return(&entity.User{ID: 7, Name: "Jane", Email: "jane@example.com", TeamID: 1}, nil)
+	mockUserRepo.On("FindByEmail", "jane@example.com").Return(&entity.User{ID: 7}, nil)
+	mockUserRepo.On("FindByEmployeeNumber", "E-100").Return(&entity.User{ID: 8}, nil)
+
+	employeeNumber := "E-100"
+	_, err := service.UpdateUser(7, AnyVersion, &UserPatch{EmployeeNumber: &employeeNumber}, WriteOptions{})
+
+	assert.ErrorIs(t, err, ErrEmployeeNumberTaken)
+	mockUserRepo.AssertNotCalled(t, "Update", mock.Anything)
+}
+
+// TestUpdateUser_NotFound tests that updating a missing user returns ErrUserNotFound
+func TestUpdateUser_NotFound(t *testing.T) {
+	mockUserRepo := new(mocks.UserRepository)
+	mockTeamRepo := new(mocks.TeamRepository)
+	service := NewUserService(nil, mockUserRepo, mockTeamRepo, nil)
+
+	mockUserRepo.On("FindByID", uint(7)).Return(nil, gorm.ErrRecordNotFound)
+
+	_, err := service.UpdateUser(7, AnyVersion, &UserPatch{}, WriteOptions{})
+
+	assert.ErrorIs(t, err, ErrUserNotFound)
+}
+
+// TestPatchUser tests that a patch goes through the profile and manager checks and saves the user once
+func TestPatchUser(t *testing.T) {
+	mockUserRepo := new(mocks.UserRepository)
+	service := NewUserService(nil, mockUserRepo, new(mocks.TeamRepository), nil)
+
+	employeeNumber := "E-100"
+	mockUserRepo.On("FindByID", uint(7)).Return(func(uint) *entity.User {
+		return &entity.User{ID: 7, Name: "Jane", Email: "jane@example.com", TeamID: 1, EmployeeNumber: &employeeNumber, ManagerID: uintPtr(3)}
+	}, nil)
+	mockUserRepo.On("FindByID", uint(5)).Return(&entity.User{ID: 5, Name: "Ann", Email: "ann@example.com", TeamID: 1}, nil)
+	mockUserRepo.On("FindReportingChain", uint(5)).Return([]entity.User{}, nil)
+	mockUserRepo.On("FindByEmail", "jane@example.com").Return(&entity.User{ID: 7}, nil)
+	mockUserRepo.On("FindByEmployeeNumber", "E-100").Return(&entity.User{ID: 7}, nil)
+	mockUserRepo.On("Update", mock.AnythingOfType("*entity.User")).Return(nil)
+
+	user, err := service.PatchUser(7, AnyVersion, MergePatch, []byte(`{"phone": "+49 30 123456", "employee_number": null, "manager_id": 5}`), WriteOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, "+4930123456", user.Phone)
+	assert.Nil(t, user.EmployeeNumber)
+	assert.Equal(t, uint(5), *user.ManagerID)
+	mockUserRepo.AssertNumberOfCalls(t, "Update", 1)
+
+	user, err = service.PatchUser(7, AnyVersion, JSONPatch, []byte(`[{"op": "replace", "path": "/manager_id", "value": null}]`), WriteOptions{})
+	assert.NoError(t, err)
+	assert.Nil(t, user.ManagerID)
+	assert.Equal(t, "E-100", *user.EmployeeNumber)
+
+	_, err = service.PatchUser(7, AnyVersion, MergePatch, []byte(`{"phone": "call me"}`), WriteOptions{})
+	assert.ErrorIs(t, err, ErrInvalidProfile)
+	_, err = service.PatchUser(7, AnyVersion, MergePatch, []byte(`{"email": null}`), WriteOptions{})
+	assert.ErrorIs(t, err, ErrInvalidPatchResult)
+	_, err = service.PatchUser(7, AnyVersion, MergePatch, []byte(`{"manager_id": 7}`), WriteOptions{})
+	assert.ErrorIs(t, err, ErrManagerCycle)
+}
+
+// TestDeleteUser tests that a user is only deleted at the expected version, and not when they were updated after
+// being read
+func TestDeleteUser(t *testing.T) {
+	mockUserRepo := new(mocks.UserRepository)
+	service := NewUserService(nil, mockUserRepo, new(mocks.TeamRepository), nil)
+
+	mockUserRepo.On("FindByID", uint(7)).Return(&entity.User{ID: 7, Version: 4}, nil)
+	mockUserRepo.On("Delete", uint(7), uint(4)).Return(nil).Once()
+	mockUserRepo.On("Delete", uint(7), uint(4)).Return(repository.ErrStaleVersion).Once()
+
+	assert.ErrorIs(t, service.DeleteUser(7, 3), ErrVersionMismatch)
+	assert.NoError(t, service.DeleteUser(7, 4))
+	assert.ErrorIs(t, service.DeleteUser(7, AnyVersion), ErrVersionMismatch)
+	mockUserRepo.AssertNumberOfCalls(t, "Delete", 2)
+}
+
+// TestSearchUsers tests that the query is translated into repository options
+func TestSearchUsers(t *testing.T) {
+	mockUserRepo := new(mocks.UserRepository)
+	mockTeamRepo := new(mocks.TeamRepository)
+	service := NewUserService(nil, mockUserRepo, mockTeamRepo, nil)
+
+	mockUserRepo.On("List", repository.ListOptions{
+		Where:  `LOWER(job_title) LIKE LOWER(?) ESCAPE '\' AND employment_type = ?`,
+		Args:   []interface{}{`%100\%%`, "contractor"},
+		Offset: 20,
+		Limit:  200,
+	}).Return([]entity.User{{ID: 7}}, int64(21), nil)
+
+	users, total, err := service.SearchUsers(UserQuery{JobTitle: "100%", EmploymentType: "contractor", Offset: 20, Limit: 1000})
+
+	assert.NoError(t, err)
+	assert.Len(t, users, 1)
+	assert.Equal(t, int64(21), total)
+	mockUserRepo.AssertExpectations(t)
+}
+
+// TestSetManager tests that a manager is set when it creates no cycle
+func TestSetManager(t *testing.T) {
+	mockUserRepo := new(mocks.UserRepository)
+	mockTeamRepo := new(mocks.TeamRepository)
+	service := NewUserService(nil, mockUserRepo, mockTeamRepo, nil)
+
+	mockUserRepo.On("FindByID", uint(3)).Return(&entity.User{ID: 3, Name: "Engineer"}, nil)
+	mockUserRepo.On("FindByID", uint(2)).Return(&entity.User{ID: 2, Name: "Lead"}, nil)
+	mockUserRepo.On("FindReportingChain", uint(2)).Return([]entity.User{{ID: 1}}, nil)
+	mockUserRepo.On("Update", mock.MatchedBy(func(u *entity.User) bool { return *u.ManagerID == 2 })).Return(nil)
+
+	managerID := uint(2)
+	user, err := service.SetManager(3, AnyVersion, &managerID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, uint(2), *user.ManagerID)
+	mockUserRepo.AssertExpectations(t)
+}
+
+// TestSetManager_Cycle tests that a user cannot report to themselves or to one of their reports
+func TestSetManager_Cycle(t *testing.T) {
+	mockUserRepo := new(mocks.UserRepository)
+	mockTeamRepo := new(mocks.TeamRepository)
+	service := NewUserService(nil, mockUserRepo, mockTeamRepo, nil)
+
+	mockUserRepo.On("FindByID", uint(1)).Return(&entity.User{ID: 1, Name: "CEO"}, nil)
+	mockUserRepo.On("FindByID", uint(3)).Return(&entity.User{ID: 3, Name: "Engineer"}, nil)
+	mockUserRepo.On("FindReportingChain", uint(3)).Return([]entity.User{{ID: 2}, {ID: 1}}, nil)
+	mockUserRepo.On("FindByID", uint(9)).Return(nil, gorm.ErrRecordNotFound)
+
+	self, report, missing := uint(1), uint(3), uint(9)
+	_, err := service.SetManager(1, AnyVersion, &self)
+	assert.ErrorIs(t, err, ErrManagerCycle)
+	_, err = service.SetManager(1, AnyVersion, &report)
+	assert.ErrorIs(t, err, ErrManagerCycle)
+	_, err = service.SetManager(1, AnyVersion, &missing)
+	assert.ErrorIs(t, err, ErrManagerNotFound)
+	mockUserRepo.AssertNotCalled(t, "Update", mock.Anything)
+}
+
+// TestFindSkipLevel tests that the skip-level manager is the second manager in the chain
+func TestFindSkipLevel(t *testing.T) {
+	mockUserRepo := new(mocks.UserRepository)
+	mockTeamRepo := new(mocks.TeamRepository)
+	service := NewUserService(nil, mockUserRepo, mockTeamRepo, nil)
+
+	mockUserRepo.On("FindByID", uint(3)).Return(&entity.User{ID: 3}, nil)
+	mockUserRepo.On("FindReportingChain", uint(3)).Return([]entity.User{{ID: 2}, {ID: 1, Name: "CEO"}}, nil)
+	mockUserRepo.On("FindSkipLevelReports", uint(3)).Return([]entity.User{{ID: 5}}, nil)
+
+	skipLevel, err := service.FindSkipLevel(3)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "CEO", skipLevel.Manager.Name)
+	assert.Len(t, skipLevel.Reports, 1)
+}
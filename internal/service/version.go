@@ -0,0 +1,29 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"hub_management_service/internal/repository"
+)
+
+// ErrVersionMismatch is returned when a write expects another version of a hub, team or user than the current one
+var ErrVersionMismatch = errors.New("version mismatch")
+
+// AnyVersion lets a write apply to whatever version is current
+const AnyVersion uint = 0
+
+// checkVersion rejects a write expecting another version of a record than the current one
+func checkVersion(kind string, current, expected uint) error {
+	if expected != AnyVersion && expected != current {
+		return fmt.Errorf("%w: %s is at version %d, not %d", ErrVersionMismatch, kind, current, expected)
+	}
+	return nil
+}
+
+// versionError reports a write rejected because the record was changed after it was read as ErrVersionMismatch
+func versionError(err error) error {
+	if errors.Is(err, repository.ErrStaleVersion) {
+		return fmt.Errorf("%w: %v", ErrVersionMismatch, err)
+	}
+	return err
+}
@@ -0,0 +1,5 @@
+-- Down: Drop recovery_codes table
+DROP TABLE IF EXISTS recovery_codes;
+
+-- Down: Drop mfa_enrollments table
+DROP TABLE IF EXISTS mfa_enrollments;
@@ -0,0 +1,19 @@
+-- Up: Create mfa_enrollments table
+CREATE TABLE mfa_enrollments (
+                                 id SERIAL PRIMARY KEY,
+                                 username VARCHAR(255) UNIQUE NOT NULL,
+                                 secret VARCHAR(64) NOT NULL,
+                                 confirmed BOOLEAN NOT NULL DEFAULT FALSE,
+                                 created_at TIMESTAMP DEFAULT NOW()
+);
+
+-- Up: Create recovery_codes table
+CREATE TABLE recovery_codes (
+                                id SERIAL PRIMARY KEY,
+                                enrollment_id INT NOT NULL,
+                                code_hash VARCHAR(64) NOT NULL,
+                                used_at TIMESTAMP,
+                                FOREIGN KEY (enrollment_id) REFERENCES mfa_enrollments (id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_recovery_codes_enrollment_id ON recovery_codes (enrollment_id);
@@ -0,0 +1,2 @@
+-- Down: Drop oidc_subject column from users table
+ALTER TABLE users DROP COLUMN IF EXISTS oidc_subject;
@@ -0,0 +1,2 @@
+-- Up: Link users to their subject at the OpenID Connect provider
+ALTER TABLE users ADD COLUMN oidc_subject VARCHAR(255) UNIQUE;
@@ -0,0 +1,2 @@
+-- Down: Drop deactivated_at column from users table
+ALTER TABLE users DROP COLUMN IF EXISTS deactivated_at;
@@ -0,0 +1,2 @@
+-- Up: Track users deactivated by the provisioning system
+ALTER TABLE users ADD COLUMN deactivated_at TIMESTAMP;
@@ -0,0 +1,2 @@
+-- Down: Drop case-insensitive email index
+DROP INDEX IF EXISTS users_email_lower_key;
@@ -0,0 +1,4 @@
+-- Up: Normalise stored emails and enforce case-insensitive uniqueness
+-- Fails if existing emails only differ by case, such duplicates must be merged first
+UPDATE users SET email = LOWER(TRIM(email));
+CREATE UNIQUE INDEX users_email_lower_key ON users (LOWER(email));
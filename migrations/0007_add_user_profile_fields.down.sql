@@ -0,0 +1,12 @@
+-- Down: Drop profile fields from users table
+DROP INDEX IF EXISTS users_employment_type_idx;
+DROP INDEX IF EXISTS users_job_title_idx;
+ALTER TABLE users
+    DROP COLUMN IF EXISTS job_title,
+    DROP COLUMN IF EXISTS phone,
+    DROP COLUMN IF EXISTS employee_number,
+    DROP COLUMN IF EXISTS start_date,
+    DROP COLUMN IF EXISTS avatar_url,
+    DROP COLUMN IF EXISTS locale,
+    DROP COLUMN IF EXISTS time_zone,
+    DROP COLUMN IF EXISTS employment_type;
@@ -0,0 +1,13 @@
+-- Up: Add profile fields to users table
+ALTER TABLE users
+    ADD COLUMN job_title VARCHAR(255) NOT NULL DEFAULT '',
+    ADD COLUMN phone VARCHAR(32) NOT NULL DEFAULT '',
+    ADD COLUMN employee_number VARCHAR(64) UNIQUE,
+    ADD COLUMN start_date DATE,
+    ADD COLUMN avatar_url VARCHAR(2048) NOT NULL DEFAULT '',
+    ADD COLUMN locale VARCHAR(35) NOT NULL DEFAULT '',
+    ADD COLUMN time_zone VARCHAR(64) NOT NULL DEFAULT '',
+    ADD COLUMN employment_type VARCHAR(32) NOT NULL DEFAULT '';
+
+CREATE INDEX users_job_title_idx ON users (LOWER(job_title));
+CREATE INDEX users_employment_type_idx ON users (employment_type);
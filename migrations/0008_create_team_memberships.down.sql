@@ -0,0 +1,2 @@
+-- Down: Drop team_memberships table, users keep their primary team in users.team_id
+DROP TABLE IF EXISTS team_memberships;
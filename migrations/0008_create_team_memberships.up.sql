@@ -0,0 +1,23 @@
+-- Up: Create team_memberships table
+CREATE TABLE team_memberships (
+                                  id SERIAL PRIMARY KEY,
+                                  user_id INT NOT NULL,
+                                  team_id INT NOT NULL,
+                                  role VARCHAR(32) NOT NULL,
+                                  start_date DATE,
+                                  end_date DATE,
+                                  is_primary BOOLEAN NOT NULL DEFAULT FALSE,
+                                  created_at TIMESTAMP DEFAULT NOW(),
+                                  FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE,
+                                  FOREIGN KEY (team_id) REFERENCES teams (id) ON DELETE CASCADE,
+                                  CONSTRAINT team_memberships_user_team_key UNIQUE (user_id, team_id)
+);
+
+CREATE INDEX team_memberships_team_id_idx ON team_memberships (team_id);
+
+-- At most one primary membership per user
+CREATE UNIQUE INDEX team_memberships_primary_key ON team_memberships (user_id) WHERE is_primary;
+
+-- Up: Convert the existing team of every user into their primary membership
+INSERT INTO team_memberships (user_id, team_id, role, start_date, is_primary)
+SELECT id, team_id, 'member', created_at::date, TRUE FROM users;
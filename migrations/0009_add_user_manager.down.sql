@@ -0,0 +1,4 @@
+-- Down: Drop reporting lines from users table
+DROP INDEX IF EXISTS users_manager_id_idx;
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_manager_not_self;
+ALTER TABLE users DROP COLUMN IF EXISTS manager_id;
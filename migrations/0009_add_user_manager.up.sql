@@ -0,0 +1,6 @@
+-- Up: Add reporting lines, removing a manager leaves their reports without one
+ALTER TABLE users
+    ADD COLUMN manager_id INT REFERENCES users (id) ON DELETE SET NULL,
+    ADD CONSTRAINT users_manager_not_self CHECK (manager_id <> id);
+
+CREATE INDEX users_manager_id_idx ON users (manager_id);
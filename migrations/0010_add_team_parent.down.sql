@@ -0,0 +1,4 @@
+-- Down: Drop team nesting
+DROP INDEX IF EXISTS teams_parent_id_idx;
+ALTER TABLE teams DROP CONSTRAINT IF EXISTS teams_parent_not_self;
+ALTER TABLE teams DROP COLUMN IF EXISTS parent_id;
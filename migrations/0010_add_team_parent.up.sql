@@ -0,0 +1,6 @@
+-- Up: Allow teams to be nested, removing a parent team moves its sub-teams to the top level
+ALTER TABLE teams
+    ADD COLUMN parent_id INT REFERENCES teams (id) ON DELETE SET NULL,
+    ADD CONSTRAINT teams_parent_not_self CHECK (parent_id <> id);
+
+CREATE INDEX teams_parent_id_idx ON teams (parent_id);
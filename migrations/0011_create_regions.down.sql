@@ -0,0 +1,6 @@
+-- Down: Drop the region hierarchy, hubs keep their free-text location
+DROP INDEX IF EXISTS hubs_country_id_idx;
+ALTER TABLE hubs DROP COLUMN IF EXISTS city;
+ALTER TABLE hubs DROP COLUMN IF EXISTS country_id;
+DROP TABLE IF EXISTS countries;
+DROP TABLE IF EXISTS regions;
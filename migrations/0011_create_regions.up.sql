@@ -0,0 +1,23 @@
+-- Up: Create regions table
+CREATE TABLE regions (
+                         id SERIAL PRIMARY KEY,
+                         name VARCHAR(255) UNIQUE NOT NULL
+);
+
+-- Up: Create countries table, a region cannot be deleted while it has countries
+CREATE TABLE countries (
+                           id SERIAL PRIMARY KEY,
+                           code CHAR(2) UNIQUE NOT NULL,
+                           name VARCHAR(255) NOT NULL,
+                           region_id INT NOT NULL,
+                           FOREIGN KEY (region_id) REFERENCES regions (id) ON DELETE RESTRICT
+);
+
+CREATE INDEX countries_region_id_idx ON countries (region_id);
+
+-- Up: Place hubs in a country and city, existing hubs keep only their free-text location until assigned
+ALTER TABLE hubs
+    ADD COLUMN country_id INT REFERENCES countries (id) ON DELETE RESTRICT,
+    ADD COLUMN city VARCHAR(255);
+
+CREATE INDEX hubs_country_id_idx ON hubs (country_id);
@@ -0,0 +1,9 @@
+-- Down: Drop hub addresses and coordinates
+DROP INDEX IF EXISTS hubs_coordinates_idx;
+ALTER TABLE hubs
+    DROP COLUMN IF EXISTS longitude,
+    DROP COLUMN IF EXISTS latitude,
+    DROP COLUMN IF EXISTS address_state,
+    DROP COLUMN IF EXISTS address_postal_code,
+    DROP COLUMN IF EXISTS address_street2,
+    DROP COLUMN IF EXISTS address_street;
@@ -0,0 +1,14 @@
+-- Up: Add a street address and coordinates to hubs
+ALTER TABLE hubs
+    ADD COLUMN address_street VARCHAR(255),
+    ADD COLUMN address_street2 VARCHAR(255),
+    ADD COLUMN address_postal_code VARCHAR(32),
+    ADD COLUMN address_state VARCHAR(255),
+    ADD COLUMN latitude DOUBLE PRECISION,
+    ADD COLUMN longitude DOUBLE PRECISION,
+    ADD CONSTRAINT hubs_latitude_range CHECK (latitude BETWEEN -90 AND 90),
+    ADD CONSTRAINT hubs_longitude_range CHECK (longitude BETWEEN -180 AND 180),
+    ADD CONSTRAINT hubs_coordinates_pair CHECK ((latitude IS NULL) = (longitude IS NULL));
+
+-- Nearby searches filter on a bounding box before computing exact distances
+CREATE INDEX hubs_coordinates_idx ON hubs (latitude, longitude);
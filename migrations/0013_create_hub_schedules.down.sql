@@ -0,0 +1,4 @@
+-- Down: Drop hub schedules
+DROP TABLE IF EXISTS hub_holidays;
+DROP TABLE IF EXISTS hub_opening_hours;
+ALTER TABLE hubs DROP COLUMN IF EXISTS time_zone;
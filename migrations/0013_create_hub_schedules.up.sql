@@ -0,0 +1,25 @@
+-- Up: Add the time zone that hub opening hours are expressed in
+ALTER TABLE hubs ADD COLUMN time_zone VARCHAR(64);
+
+-- Up: Create hub_opening_hours table, weekday 0 is Sunday
+CREATE TABLE hub_opening_hours (
+                                   id SERIAL PRIMARY KEY,
+                                   hub_id INT NOT NULL,
+                                   weekday SMALLINT NOT NULL CHECK (weekday BETWEEN 0 AND 6),
+                                   opens VARCHAR(5) NOT NULL,
+                                   closes VARCHAR(5) NOT NULL,
+                                   FOREIGN KEY (hub_id) REFERENCES hubs (id) ON DELETE CASCADE,
+                                   CHECK (opens < closes)
+);
+
+CREATE INDEX hub_opening_hours_hub_id_idx ON hub_opening_hours (hub_id);
+
+-- Up: Create hub_holidays table
+CREATE TABLE hub_holidays (
+                              id SERIAL PRIMARY KEY,
+                              hub_id INT NOT NULL,
+                              date DATE NOT NULL,
+                              name VARCHAR(255) NOT NULL,
+                              FOREIGN KEY (hub_id) REFERENCES hubs (id) ON DELETE CASCADE,
+                              CONSTRAINT hub_holidays_hub_date_key UNIQUE (hub_id, date)
+);
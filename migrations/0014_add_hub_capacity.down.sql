@@ -0,0 +1,2 @@
+-- Down: Drop hub capacity
+ALTER TABLE hubs DROP COLUMN IF EXISTS capacity;
@@ -0,0 +1,4 @@
+-- Up: Add a seat capacity to hubs, hubs without one are never full
+ALTER TABLE hubs
+    ADD COLUMN capacity INT,
+    ADD CONSTRAINT hubs_capacity_non_negative CHECK (capacity >= 0);
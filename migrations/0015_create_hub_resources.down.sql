@@ -0,0 +1,3 @@
+-- Down: Drop hub resources
+DROP TABLE IF EXISTS hub_resource_amenities;
+DROP TABLE IF EXISTS hub_resources;
@@ -0,0 +1,23 @@
+-- Up: Create hub_resources table for the desks and meeting rooms of a hub
+CREATE TABLE hub_resources (
+                               id SERIAL PRIMARY KEY,
+                               hub_id INT NOT NULL,
+                               type VARCHAR(16) NOT NULL CHECK (type IN ('desk', 'room')),
+                               name VARCHAR(255) NOT NULL,
+                               floor VARCHAR(32),
+                               capacity INT NOT NULL DEFAULT 1 CHECK (capacity >= 0),
+                               FOREIGN KEY (hub_id) REFERENCES hubs (id) ON DELETE CASCADE,
+                               CONSTRAINT hub_resources_hub_name_key UNIQUE (hub_id, name)
+);
+
+CREATE INDEX hub_resources_type_idx ON hub_resources (type);
+
+-- Up: Create hub_resource_amenities table, one row per amenity of a resource
+CREATE TABLE hub_resource_amenities (
+                                        resource_id INT NOT NULL,
+                                        name VARCHAR(64) NOT NULL,
+                                        PRIMARY KEY (resource_id, name),
+                                        FOREIGN KEY (resource_id) REFERENCES hub_resources (id) ON DELETE CASCADE
+);
+
+CREATE INDEX hub_resource_amenities_name_idx ON hub_resource_amenities (name);
@@ -0,0 +1,2 @@
+-- Down: Drop bookings, btree_gist is left installed as other schemas may use it
+DROP TABLE IF EXISTS bookings;
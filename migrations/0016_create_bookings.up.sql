@@ -0,0 +1,24 @@
+-- Up: btree_gist lets the exclusion constraint compare resource_id with = alongside the time range
+CREATE EXTENSION IF NOT EXISTS btree_gist;
+
+-- Up: Create bookings table, active bookings of a resource must not overlap
+CREATE TABLE bookings (
+                          id SERIAL PRIMARY KEY,
+                          resource_id INT NOT NULL,
+                          user_id INT NOT NULL,
+                          starts_at TIMESTAMPTZ NOT NULL,
+                          ends_at TIMESTAMPTZ NOT NULL,
+                          title VARCHAR(255),
+                          cancelled_at TIMESTAMPTZ,
+                          created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+                          FOREIGN KEY (resource_id) REFERENCES hub_resources (id) ON DELETE CASCADE,
+                          FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE,
+                          CHECK (starts_at < ends_at),
+                          CONSTRAINT bookings_no_overlap EXCLUDE USING gist (
+                              resource_id WITH =,
+                              tstzrange(starts_at, ends_at, '[)') WITH &&
+                          ) WHERE (cancelled_at IS NULL)
+);
+
+CREATE INDEX bookings_resource_time_idx ON bookings (resource_id, starts_at);
+CREATE INDEX bookings_user_id_idx ON bookings (user_id);
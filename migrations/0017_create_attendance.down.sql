@@ -0,0 +1,3 @@
+-- Down: Drop attendance
+DROP TABLE IF EXISTS attendance_daily;
+DROP TABLE IF EXISTS visits;
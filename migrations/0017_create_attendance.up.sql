@@ -0,0 +1,27 @@
+-- Up: Create visits table, one row per check-in, purged after the retention period
+CREATE TABLE visits (
+                        id SERIAL PRIMARY KEY,
+                        user_id INT NOT NULL,
+                        hub_id INT NOT NULL,
+                        team_id INT NOT NULL,
+                        date DATE NOT NULL,
+                        checked_in_at TIMESTAMPTZ NOT NULL,
+                        checked_out_at TIMESTAMPTZ,
+                        FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE,
+                        FOREIGN KEY (hub_id) REFERENCES hubs (id) ON DELETE CASCADE,
+                        CHECK (checked_out_at IS NULL OR checked_out_at >= checked_in_at)
+);
+
+CREATE INDEX visits_user_id_idx ON visits (user_id);
+CREATE INDEX visits_hub_date_idx ON visits (hub_id, date);
+CREATE INDEX visits_checked_in_at_idx ON visits (checked_in_at);
+
+-- Up: Create attendance_daily table, the distinct users of a team at a hub per day, kept after visits are purged
+CREATE TABLE attendance_daily (
+                                  date DATE NOT NULL,
+                                  hub_id INT NOT NULL,
+                                  team_id INT NOT NULL,
+                                  users BIGINT NOT NULL,
+                                  PRIMARY KEY (date, hub_id, team_id),
+                                  FOREIGN KEY (hub_id) REFERENCES hubs (id) ON DELETE CASCADE
+);
@@ -0,0 +1,9 @@
+-- Down: Drop the search indexes, the pg_trgm extension is left installed
+DROP INDEX IF EXISTS users_search_idx;
+DROP INDEX IF EXISTS users_email_trgm_idx;
+DROP INDEX IF EXISTS users_name_trgm_idx;
+DROP INDEX IF EXISTS teams_search_idx;
+DROP INDEX IF EXISTS teams_name_trgm_idx;
+DROP INDEX IF EXISTS hubs_search_idx;
+DROP INDEX IF EXISTS hubs_location_trgm_idx;
+DROP INDEX IF EXISTS hubs_name_trgm_idx;
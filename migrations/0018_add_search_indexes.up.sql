@@ -0,0 +1,14 @@
+-- Up: Index names, locations and emails for fuzzy search with trigrams and for full-text search
+-- The full-text expressions must match the documents built by the search repository
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE INDEX hubs_name_trgm_idx ON hubs USING GIN (name gin_trgm_ops);
+CREATE INDEX hubs_location_trgm_idx ON hubs USING GIN (location gin_trgm_ops);
+CREATE INDEX hubs_search_idx ON hubs USING GIN (to_tsvector('simple', COALESCE(name, '') || ' ' || COALESCE(location, '')));
+
+CREATE INDEX teams_name_trgm_idx ON teams USING GIN (name gin_trgm_ops);
+CREATE INDEX teams_search_idx ON teams USING GIN (to_tsvector('simple', COALESCE(name, '')));
+
+CREATE INDEX users_name_trgm_idx ON users USING GIN (name gin_trgm_ops);
+CREATE INDEX users_email_trgm_idx ON users USING GIN (email gin_trgm_ops);
+CREATE INDEX users_search_idx ON users USING GIN (to_tsvector('simple', COALESCE(name, '') || ' ' || COALESCE(email, '')));
@@ -0,0 +1,4 @@
+-- Down: Drop versions
+ALTER TABLE users DROP COLUMN IF EXISTS version;
+ALTER TABLE teams DROP COLUMN IF EXISTS version;
+ALTER TABLE hubs DROP COLUMN IF EXISTS version;
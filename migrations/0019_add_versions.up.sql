@@ -0,0 +1,4 @@
+-- Up: Version hubs, teams and users for optimistic concurrency, every update increments the version
+ALTER TABLE hubs ADD COLUMN version INT NOT NULL DEFAULT 1;
+ALTER TABLE teams ADD COLUMN version INT NOT NULL DEFAULT 1;
+ALTER TABLE users ADD COLUMN version INT NOT NULL DEFAULT 1;
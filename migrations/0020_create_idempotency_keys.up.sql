@@ -0,0 +1,14 @@
+-- Up: Create idempotency_keys table, the responses of create requests replayed on retry until they expire
+CREATE TABLE idempotency_keys (
+                                  scope VARCHAR(255) NOT NULL,
+                                  key VARCHAR(255) NOT NULL,
+                                  fingerprint VARCHAR(64) NOT NULL,
+                                  status_code INT NOT NULL DEFAULT 0,
+                                  header TEXT,
+                                  body BYTEA,
+                                  created_at TIMESTAMPTZ NOT NULL,
+                                  expires_at TIMESTAMPTZ NOT NULL,
+                                  PRIMARY KEY (scope, key)
+);
+
+CREATE INDEX idempotency_keys_expires_at_idx ON idempotency_keys (expires_at);
@@ -0,0 +1,4 @@
+-- Down: Drop MFA replay and lockout tracking
+ALTER TABLE mfa_enrollments DROP COLUMN IF EXISTS locked_until;
+ALTER TABLE mfa_enrollments DROP COLUMN IF EXISTS failed_codes;
+ALTER TABLE mfa_enrollments DROP COLUMN IF EXISTS last_used_step;
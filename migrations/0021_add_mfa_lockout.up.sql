@@ -0,0 +1,4 @@
+-- Up: Track the last accepted TOTP step against replays, and invalid codes to lock out guessing
+ALTER TABLE mfa_enrollments ADD COLUMN last_used_step BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE mfa_enrollments ADD COLUMN failed_codes INT NOT NULL DEFAULT 0;
+ALTER TABLE mfa_enrollments ADD COLUMN locked_until TIMESTAMPTZ;
@@ -0,0 +1,2 @@
+-- Down: Drop the lease of idempotency keys
+ALTER TABLE idempotency_keys DROP COLUMN IF EXISTS locked_until;
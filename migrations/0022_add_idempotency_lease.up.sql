@@ -0,0 +1,2 @@
+-- Up: Lease the keys of requests being handled, so that a retry can take over the key of a request that never finished
+ALTER TABLE idempotency_keys ADD COLUMN locked_until TIMESTAMPTZ;
@@ -0,0 +1,2 @@
+-- Down: Drop attendance_visitors
+DROP TABLE IF EXISTS attendance_visitors;
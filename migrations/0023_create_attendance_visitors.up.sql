@@ -0,0 +1,13 @@
+-- Up: Create attendance_visitors table, the users who checked in at a hub per day, so that each is counted once in
+-- attendance_daily however their check-ins race
+CREATE TABLE attendance_visitors (
+                                     date DATE NOT NULL,
+                                     hub_id INT NOT NULL,
+                                     user_id INT NOT NULL,
+                                     PRIMARY KEY (date, hub_id, user_id),
+                                     FOREIGN KEY (hub_id) REFERENCES hubs (id) ON DELETE CASCADE,
+                                     FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
+);
+
+INSERT INTO attendance_visitors (date, hub_id, user_id)
+SELECT DISTINCT date, hub_id, user_id FROM visits;
@@ -0,0 +1,3 @@
+-- Down: Make resource names unique per hub with case
+DROP INDEX IF EXISTS hub_resources_hub_name_lower_key;
+ALTER TABLE hub_resources ADD CONSTRAINT hub_resources_hub_name_key UNIQUE (hub_id, name);
@@ -0,0 +1,3 @@
+-- Up: Make resource names unique per hub regardless of case, as the service checks them
+ALTER TABLE hub_resources DROP CONSTRAINT IF EXISTS hub_resources_hub_name_key;
+CREATE UNIQUE INDEX hub_resources_hub_name_lower_key ON hub_resources (hub_id, LOWER(name));
@@ -0,0 +1,2 @@
+-- Down: Drop the lease token of idempotency keys
+ALTER TABLE idempotency_keys DROP COLUMN IF EXISTS lease_token;
@@ -0,0 +1,3 @@
+-- Up: Record which request holds the lease of an idempotency key, so that a request that outlived its lease can no
+-- longer record a response or free the key once a retry took it over
+ALTER TABLE idempotency_keys ADD COLUMN lease_token VARCHAR(32) NOT NULL DEFAULT '';
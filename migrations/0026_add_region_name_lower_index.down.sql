@@ -0,0 +1,3 @@
+-- Down: Make region names unique with case
+DROP INDEX IF EXISTS regions_name_lower_key;
+ALTER TABLE regions ADD CONSTRAINT regions_name_key UNIQUE (name);
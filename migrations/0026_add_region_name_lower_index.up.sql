@@ -0,0 +1,3 @@
+-- Up: Make region names unique regardless of case, as the service checks them
+ALTER TABLE regions DROP CONSTRAINT IF EXISTS regions_name_key;
+CREATE UNIQUE INDEX regions_name_lower_key ON regions (LOWER(name));
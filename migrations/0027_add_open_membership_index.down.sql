@@ -0,0 +1,5 @@
+-- Down: Keep only the latest membership of a user per team, which is unique again
+DROP INDEX IF EXISTS team_memberships_open_key;
+DELETE FROM team_memberships m
+WHERE EXISTS (SELECT 1 FROM team_memberships n WHERE n.user_id = m.user_id AND n.team_id = m.team_id AND n.id > m.id);
+ALTER TABLE team_memberships ADD CONSTRAINT team_memberships_user_team_key UNIQUE (user_id, team_id);
@@ -0,0 +1,3 @@
+-- Up: Allow users to rejoin teams, keeping ended memberships as history, with at most one open membership per team
+ALTER TABLE team_memberships DROP CONSTRAINT IF EXISTS team_memberships_user_team_key;
+CREATE UNIQUE INDEX team_memberships_open_key ON team_memberships (user_id, team_id) WHERE end_date IS NULL;
@@ -0,0 +1,152 @@
+// Package fieldset selects the members of JSON records named by a sparse fieldset such as name,teams.name.
+package fieldset
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// ErrInvalidFields is returned for malformed field lists and for names the records do not have
+var ErrInvalidFields = errors.New("invalid fields")
+
+// Set is a tree of field names, a name without children selects its whole value
+type Set map[string]Set
+
+// Parse reads a comma-separated list of field names, dots select the fields of nested records
+func Parse(list string) (Set, error) {
+	set := Set{}
+	for _, path := range strings.Split(list, ",") {
+		path = strings.TrimSpace(path)
+		if path == "" {
+			continue
+		}
+		node := set
+		for _, name := range strings.Split(path, ".") {
+			if name == "" {
+				return nil, fmt.Errorf("%w: %q has an empty field name", ErrInvalidFields, path)
+			}
+			if node[name] == nil {
+				node[name] = Set{}
+			}
+			node = node[name]
+		}
+	}
+	return set, nil
+}
+
+// Validate checks that every name of the set is a JSON field of record, a struct or a pointer or slice of structs
+func (s Set) Validate(record reflect.Type) error {
+	return s.validate(record, "")
+}
+
+func (s Set) validate(record reflect.Type, prefix string) error {
+	fields := jsonFields(record)
+	if fields == nil {
+		return fmt.Errorf("%w: %s has no fields", ErrInvalidFields, strings.TrimSuffix(prefix, "."))
+	}
+	for name, children := range s {
+		field, ok := fields[name]
+		if !ok {
+			names := make([]string, 0, len(fields))
+			for known := range fields {
+				names = append(names, known)
+			}
+			sort.Strings(names)
+			return fmt.Errorf("%w: unknown field %q, expected one of: %s", ErrInvalidFields, prefix+name, strings.Join(names, ", "))
+		}
+		if len(children) > 0 {
+			if err := children.validate(field, prefix+name+"."); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+var marshaler = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+
+// jsonFields maps the JSON names of the fields of a struct to their types, following embedded structs. It returns nil
+// for values that are not structs or that encode themselves.
+func jsonFields(t reflect.Type) map[string]reflect.Type {
+	for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct || t.Implements(marshaler) || reflect.PtrTo(t).Implements(marshaler) {
+		return nil
+	}
+	fields := map[string]reflect.Type{}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" || (!field.IsExported() && !field.Anonymous) {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+		if field.Anonymous && name == "" {
+			for embedded, typ := range jsonFields(field.Type) {
+				if _, ok := fields[embedded]; !ok {
+					fields[embedded] = typ
+				}
+			}
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		fields[name] = field.Type
+	}
+	return fields
+}
+
+// Select encodes value, a record or a slice of records, and keeps the fields of the set in each record along with
+// the always fields, such as the ID. An empty set keeps everything.
+func (s Set) Select(value interface{}, always ...string) (interface{}, error) {
+	if len(s) == 0 {
+		return value, nil
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	var decoded interface{}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return nil, err
+	}
+	return s.filter(decoded, always), nil
+}
+
+func (s Set) filter(value interface{}, always []string) interface{} {
+	switch v := value.(type) {
+	case []interface{}:
+		for i := range v {
+			v[i] = s.filter(v[i], always)
+		}
+		return v
+	case map[string]interface{}:
+		for name, member := range v {
+			children, ok := s[name]
+			switch {
+			case ok && len(children) > 0:
+				v[name] = children.filter(member, always)
+			case !ok && !contains(always, name):
+				delete(v, name)
+			}
+		}
+		return v
+	default:
+		return value
+	}
+}
+
+func contains(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
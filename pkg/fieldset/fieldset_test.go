@@ -0,0 +1,84 @@
+package fieldset
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type date struct{ time.Time }
+
+func (d date) MarshalJSON() ([]byte, error) { return json.Marshal(d.Format("2006-01-02")) }
+
+type member struct {
+	ID    uint   `json:"id"`
+	Name  string `json:"name"`
+	Email string `json:"email"`
+	Start *date  `json:"start,omitempty"`
+}
+
+type group struct {
+	ID      uint      `json:"id"`
+	Name    string    `json:"name"`
+	Secret  string    `json:"-"`
+	Members *[]member `json:"members,omitempty"`
+}
+
+type rankedGroup struct {
+	group
+	Score float64 `json:"score"`
+}
+
+// TestParse tests that dotted names build a tree and empty names are rejected
+func TestParse(t *testing.T) {
+	set, err := Parse(" name, members.name,members.email,,members")
+	assert.NoError(t, err)
+	assert.Equal(t, Set{"name": {}, "members": {"name": {}, "email": {}}}, set)
+
+	_, err = Parse("members..name")
+	assert.ErrorIs(t, err, ErrInvalidFields)
+}
+
+// TestValidate tests that names are checked against the JSON fields, through pointers, slices and embedded structs
+func TestValidate(t *testing.T) {
+	for list, valid := range map[string]bool{
+		"name,members":       true,
+		"members.email":      true,
+		"score,id":           true,
+		"secret":             false,
+		"Secret":             false,
+		"members.phone":      false,
+		"name.first":         false,
+		"members.start.year": false,
+	} {
+		set, err := Parse(list)
+		assert.NoError(t, err)
+		err = set.Validate(reflect.TypeOf(rankedGroup{}))
+		if valid {
+			assert.NoError(t, err, list)
+		} else {
+			assert.ErrorIs(t, err, ErrInvalidFields, list)
+		}
+	}
+}
+
+// TestSelect tests that only the selected fields and the ID are kept, in records and slices of records
+func TestSelect(t *testing.T) {
+	groups := []rankedGroup{
+		{group: group{ID: 1, Name: "Core", Members: &[]member{{ID: 7, Name: "Ada", Email: "ada@example.com"}}}, Score: 0.5},
+		{group: group{ID: 2, Name: "Edge"}, Score: 0.1},
+	}
+
+	set, _ := Parse("score,members.name")
+	selected, err := set.Select(groups, "id")
+	assert.NoError(t, err)
+	data, _ := json.Marshal(selected)
+	assert.JSONEq(t, `[{"id":1,"score":0.5,"members":[{"id":7,"name":"Ada"}]},{"id":2,"score":0.1}]`, string(data))
+
+	selected, err = Set{}.Select(groups[1], "id")
+	assert.NoError(t, err)
+	assert.Equal(t, groups[1], selected)
+}
@@ -0,0 +1,109 @@
+// Package fuzzy scores how well a search query matches a text, tolerating typos,
+// so that results ranked by different databases come out in the same order.
+package fuzzy
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Tokens splits a text into lower-case words of letters and digits, so that an email splits at its dots and @
+func Tokens(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// MaxTypos is the number of edits a word of n letters may differ by and still match: none up to 3 letters,
+// one up to 6 and two above
+func MaxTypos(n int) int {
+	switch {
+	case n <= 3:
+		return 0
+	case n <= 6:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// Distance is the number of insertions, deletions, substitutions and swaps of adjacent letters between two words
+func Distance(a, b string) int {
+	s, t := []rune(a), []rune(b)
+	// Three rows are enough, swaps look two letters back
+	prev2 := make([]int, len(t)+1)
+	prev := make([]int, len(t)+1)
+	row := make([]int, len(t)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(s); i++ {
+		row[0] = i
+		for j := 1; j <= len(t); j++ {
+			cost := 1
+			if s[i-1] == t[j-1] {
+				cost = 0
+			}
+			row[j] = min(prev[j]+1, row[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && s[i-1] == t[j-2] && s[i-2] == t[j-1] {
+				row[j] = min(row[j], prev2[j-2]+1)
+			}
+		}
+		prev2, prev, row = prev, row, prev2
+	}
+	return prev[len(t)]
+}
+
+// Score rates how well a query matches a text between 0 and 1. The whole text equal to the query scores 1,
+// starting with it 0.95 and containing it 0.85. Otherwise every word of the query must match a word of the
+// text, exactly, as a prefix or within MaxTypos, and the score is at most 0.8.
+func Score(query, text string) float64 {
+	q, t := Tokens(query), Tokens(text)
+	if len(q) == 0 || len(t) == 0 {
+		return 0
+	}
+	joinedQuery, joinedText := strings.Join(q, " "), strings.Join(t, " ")
+	switch {
+	case joinedText == joinedQuery:
+		return 1
+	case strings.HasPrefix(joinedText, joinedQuery):
+		return 0.95
+	case strings.Contains(joinedText, joinedQuery):
+		return 0.85
+	}
+
+	total := 0.0
+	for _, word := range q {
+		best := 0.0
+		for _, candidate := range t {
+			best = max(best, wordScore(word, candidate))
+		}
+		if best == 0 {
+			return 0
+		}
+		total += best
+	}
+	return 0.8 * total / float64(len(q))
+}
+
+// wordScore rates a query word against a word of the text, comparing against the start of longer words
+// so that a half-typed word with a typo still matches
+func wordScore(word, candidate string) float64 {
+	if word == candidate {
+		return 1
+	}
+	n := len([]rune(word))
+	if strings.HasPrefix(candidate, word) {
+		return 0.9
+	}
+	best := 0.0
+	if d := Distance(word, candidate); d <= MaxTypos(n) {
+		best = 1 - float64(d)/float64(n+1)
+	}
+	if prefix := []rune(candidate); len(prefix) > n {
+		if d := Distance(word, string(prefix[:n])); d <= MaxTypos(n) {
+			best = max(best, 0.9*(1-float64(d)/float64(n+1)))
+		}
+	}
+	return best
+}
@@ -0,0 +1,39 @@
+package fuzzy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokens(t *testing.T) {
+	assert.Equal(t, []string{"jane", "doe", "example", "com"}, Tokens("Jane.Doe@example.com"))
+	assert.Equal(t, []string{"zürich", "hub"}, Tokens("  Zürich - Hub "))
+	assert.Empty(t, Tokens(" %_ "))
+}
+
+func TestDistance(t *testing.T) {
+	assert.Equal(t, 0, Distance("paris", "paris"))
+	assert.Equal(t, 1, Distance("pairs", "paris")) // Swapped letters count once
+	assert.Equal(t, 1, Distance("londn", "london"))
+	assert.Equal(t, 1, Distance("zurich", "zürich"))
+	assert.Equal(t, 3, Distance("", "abc"))
+	assert.Equal(t, 3, Distance("kitten", "sitting"))
+}
+
+func TestScore(t *testing.T) {
+	assert.Equal(t, 1.0, Score("Paris Hub", "paris  hub"))
+	assert.Equal(t, 0.95, Score("par", "Paris Hub"))
+	assert.Equal(t, 0.85, Score("hub", "Paris Hub"))
+	assert.InDelta(t, 0.8, Score("hub paris", "Paris Hub"), 0.001)
+	assert.InDelta(t, 0.667, Score("pairs", "Paris Hub"), 0.001)
+	assert.InDelta(t, 0.64, Score("jnae", "Jane Doe"), 0.001)   // A typo in a word
+	assert.InDelta(t, 0.68, Score("jnae d", "Jane Doe"), 0.001) // and a half-typed word
+	assert.Zero(t, Score("pairs london", "Paris Hub"))          // Every word must match
+	assert.Zero(t, Score("par", "Bar"))                         // Short words allow no typo
+	assert.Zero(t, Score("", "Paris"))
+
+	// Closer matches rank higher
+	assert.Greater(t, Score("paris", "Paris"), Score("paris", "Paris Hub"))
+	assert.Greater(t, Score("londn", "London"), Score("londn", "Lyon Dunes"))
+}
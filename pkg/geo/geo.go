@@ -0,0 +1,86 @@
+// Package geo computes great-circle distances and bounding boxes on a spherical earth,
+// so that nearby searches need no database extension such as PostGIS.
+package geo
+
+import "math"
+
+// EarthRadiusKm is the mean radius of the earth
+const EarthRadiusKm = 6371.0088
+
+// MaxDistanceKm is half the circumference of the earth, no two points are further apart
+const MaxDistanceKm = math.Pi * EarthRadiusKm
+
+// Point is a position in decimal degrees
+type Point struct {
+	Lat float64
+	Lng float64
+}
+
+// Valid reports whether the point lies within the latitude and longitude ranges
+func (p Point) Valid() bool {
+	return p.Lat >= -90 && p.Lat <= 90 && p.Lng >= -180 && p.Lng <= 180
+}
+
+// Distance returns the haversine distance between two points in kilometres
+func Distance(a, b Point) float64 {
+	lat1, lat2 := radians(a.Lat), radians(b.Lat)
+	dLat := lat2 - lat1
+	dLng := radians(b.Lng - a.Lng)
+
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLng/2)*math.Sin(dLng/2)
+	return 2 * EarthRadiusKm * math.Asin(math.Min(1, math.Sqrt(h)))
+}
+
+// Box is a latitude and longitude range. When MinLng > MaxLng the box crosses the antimeridian.
+type Box struct {
+	MinLat, MaxLat float64
+	MinLng, MaxLng float64
+}
+
+// WrapsLng reports whether the longitude range crosses the antimeridian
+func (b Box) WrapsLng() bool {
+	return b.MinLng > b.MaxLng
+}
+
+// BoundingBox returns a box containing every point within radiusKm of the center.
+// Near the poles, or for very large radii, the box spans all longitudes.
+func BoundingBox(center Point, radiusKm float64) Box {
+	dLat := degrees(radiusKm / EarthRadiusKm)
+	box := Box{
+		MinLat: math.Max(-90, center.Lat-dLat),
+		MaxLat: math.Min(90, center.Lat+dLat),
+		MinLng: -180,
+		MaxLng: 180,
+	}
+	if box.MinLat == -90 || box.MaxLat == 90 {
+		return box
+	}
+
+	// Widest longitude offset of the circle, reached at the latitude where it touches the box
+	ratio := math.Sin(radiusKm/EarthRadiusKm) / math.Cos(radians(center.Lat))
+	if ratio >= 1 {
+		return box
+	}
+	dLng := degrees(math.Asin(ratio))
+	box.MinLng = normalizeLng(center.Lng - dLng)
+	box.MaxLng = normalizeLng(center.Lng + dLng)
+	return box
+}
+
+func normalizeLng(lng float64) float64 {
+	if lng < -180 {
+		return lng + 360
+	}
+	if lng > 180 {
+		return lng - 360
+	}
+	return lng
+}
+
+func radians(deg float64) float64 {
+	return deg * math.Pi / 180
+}
+
+func degrees(rad float64) float64 {
+	return rad * 180 / math.Pi
+}
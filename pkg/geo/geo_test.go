@@ -0,0 +1,52 @@
+package geo
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestDistance checks the haversine distance against known city distances
+func TestDistance(t *testing.T) {
+	paris := Point{Lat: 48.8566, Lng: 2.3522}
+	london := Point{Lat: 51.5074, Lng: -0.1278}
+
+	assert.InDelta(t, 343.6, Distance(paris, london), 1)
+	assert.Equal(t, 0.0, Distance(paris, paris))
+	assert.InDelta(t, MaxDistanceKm, Distance(Point{0, 0}, Point{0, 180}), 1e-6)
+}
+
+// TestBoundingBox tests that points at the radius in every direction are inside the box
+func TestBoundingBox(t *testing.T) {
+	center := Point{Lat: 48.8566, Lng: 2.3522}
+	box := BoundingBox(center, 100)
+
+	for _, bearing := range []float64{0, 90, 180, 270} {
+		p := destination(center, 100, bearing)
+		assert.True(t, p.Lat >= box.MinLat-1e-9 && p.Lat <= box.MaxLat+1e-9, "latitude at bearing %.0f", bearing)
+		assert.True(t, p.Lng >= box.MinLng-1e-9 && p.Lng <= box.MaxLng+1e-9, "longitude at bearing %.0f", bearing)
+	}
+	assert.False(t, box.WrapsLng())
+}
+
+// TestBoundingBox_Antimeridian tests that a box around Fiji wraps around the antimeridian
+func TestBoundingBox_Antimeridian(t *testing.T) {
+	box := BoundingBox(Point{Lat: -17.7, Lng: 179.9}, 50)
+	assert.True(t, box.WrapsLng())
+}
+
+// TestBoundingBox_Pole tests that a box reaching the pole spans all longitudes
+func TestBoundingBox_Pole(t *testing.T) {
+	box := BoundingBox(Point{Lat: 89.9, Lng: 10}, 50)
+	assert.Equal(t, Box{MinLat: box.MinLat, MaxLat: 90, MinLng: -180, MaxLng: 180}, box)
+}
+
+// destination returns the point reached by travelling distanceKm from start along a bearing in degrees
+func destination(start Point, distanceKm, bearing float64) Point {
+	lat1, lng1, brg := radians(start.Lat), radians(start.Lng), radians(bearing)
+	d := distanceKm / EarthRadiusKm
+	lat2 := math.Asin(math.Sin(lat1)*math.Cos(d) + math.Cos(lat1)*math.Sin(d)*math.Cos(brg))
+	lng2 := lng1 + math.Atan2(math.Sin(brg)*math.Sin(d)*math.Cos(lat1), math.Cos(d)-math.Sin(lat1)*math.Sin(lat2))
+	return Point{Lat: degrees(lat2), Lng: degrees(lng2)}
+}
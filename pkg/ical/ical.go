@@ -0,0 +1,86 @@
+// Package ical writes iCalendar (RFC 5545) files of all-day events, such as holiday calendars.
+package ical
+
+import (
+	"bytes"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// ContentType is the media type of iCalendar files
+const ContentType = "text/calendar; charset=utf-8"
+
+// maxLineOctets is the longest content line allowed before folding
+const maxLineOctets = 75
+
+// Calendar is a named collection of events
+type Calendar struct {
+	ProdID   string // Identifies the product that created the calendar
+	Name     string // Display name, written as X-WR-CALNAME
+	TimeZone string // IANA time zone, written as X-WR-TIMEZONE when set
+	Events   []Event
+}
+
+// Event is an all-day event from Start up to, but excluding, End
+type Event struct {
+	UID     string
+	Summary string
+	Start   time.Time
+	End     time.Time
+}
+
+// Marshal encodes the calendar, stamped with the given time
+func (c *Calendar) Marshal(stamp time.Time) []byte {
+	var buf bytes.Buffer
+	line := func(name, value string) {
+		writeFolded(&buf, name+":"+value)
+	}
+
+	line("BEGIN", "VCALENDAR")
+	line("VERSION", "2.0")
+	line("PRODID", c.ProdID)
+	line("CALSCALE", "GREGORIAN")
+	line("METHOD", "PUBLISH")
+	if c.Name != "" {
+		line("X-WR-CALNAME", escapeText(c.Name))
+	}
+	if c.TimeZone != "" {
+		line("X-WR-TIMEZONE", c.TimeZone)
+	}
+	for _, event := range c.Events {
+		line("BEGIN", "VEVENT")
+		line("UID", event.UID)
+		line("DTSTAMP", stamp.UTC().Format("20060102T150405Z"))
+		line("DTSTART;VALUE=DATE", event.Start.Format("20060102"))
+		line("DTEND;VALUE=DATE", event.End.Format("20060102"))
+		line("SUMMARY", escapeText(event.Summary))
+		line("TRANSP", "TRANSPARENT")
+		line("END", "VEVENT")
+	}
+	line("END", "VCALENDAR")
+	return buf.Bytes()
+}
+
+// escapeText escapes a TEXT value as required by RFC 5545 section 3.3.11
+func escapeText(s string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`).Replace(s)
+}
+
+// writeFolded writes a content line, folding it after 75 octets without splitting UTF-8 sequences
+func writeFolded(buf *bytes.Buffer, line string) {
+	limit := maxLineOctets
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		buf.WriteString(line[:cut])
+		buf.WriteString("\r\n ")
+		line = line[cut:]
+		// Continuation lines start with a space, which counts towards their length
+		limit = maxLineOctets - 1
+	}
+	buf.WriteString(line)
+	buf.WriteString("\r\n")
+}
@@ -0,0 +1,46 @@
+package ical
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestMarshal tests the structure of an encoded calendar
+func TestMarshal(t *testing.T) {
+	cal := &Calendar{
+		ProdID:   "-//Example//Holidays//EN",
+		Name:     "Paris Hub holidays",
+		TimeZone: "Europe/Paris",
+		Events: []Event{{
+			UID:     "holiday-1@example.com",
+			Summary: "Fête nationale; bank holiday, closed",
+			Start:   time.Date(2024, 7, 14, 0, 0, 0, 0, time.UTC),
+			End:     time.Date(2024, 7, 15, 0, 0, 0, 0, time.UTC),
+		}},
+	}
+
+	out := string(cal.Marshal(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)))
+
+	assert.True(t, strings.HasPrefix(out, "BEGIN:VCALENDAR\r\nVERSION:2.0\r\n"))
+	assert.True(t, strings.HasSuffix(out, "END:VEVENT\r\nEND:VCALENDAR\r\n"))
+	assert.Contains(t, out, "DTSTAMP:20240102T030405Z\r\n")
+	assert.Contains(t, out, "DTSTART;VALUE=DATE:20240714\r\nDTEND;VALUE=DATE:20240715\r\n")
+	assert.Contains(t, out, `SUMMARY:Fête nationale\; bank holiday\, closed`)
+	assert.Contains(t, out, "X-WR-TIMEZONE:Europe/Paris\r\n")
+}
+
+// TestWriteFolded tests that long lines are folded at 75 octets without splitting characters
+func TestWriteFolded(t *testing.T) {
+	cal := &Calendar{ProdID: "-//Example//EN", Name: strings.Repeat("é", 60)}
+	out := string(cal.Marshal(time.Now()))
+
+	for _, line := range strings.Split(strings.TrimSuffix(out, "\r\n"), "\r\n") {
+		assert.LessOrEqual(t, len(line), 75)
+		assert.True(t, strings.ToValidUTF8(line, "?") == line, "line %q splits a character", line)
+	}
+	unfolded := strings.ReplaceAll(out, "\r\n ", "")
+	assert.Contains(t, unfolded, "X-WR-CALNAME:"+strings.Repeat("é", 60)+"\r\n")
+}
@@ -0,0 +1,90 @@
+package jsonpatch
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestMergePatch checks the examples of RFC 7396 appendix A
+func TestMergePatch(t *testing.T) {
+	for _, example := range []struct{ doc, patch, result string }{
+		{`{"a":"b"}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"b"}`, `{"b":"c"}`, `{"a":"b","b":"c"}`},
+		{`{"a":"b"}`, `{"a":null}`, `{}`},
+		{`{"a":"b","b":"c"}`, `{"a":null}`, `{"b":"c"}`},
+		{`{"a":["b"]}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"c"}`, `{"a":["b"]}`, `{"a":["b"]}`},
+		{`{"a":{"b":"c"}}`, `{"a":{"b":"d","c":null}}`, `{"a":{"b":"d"}}`},
+		{`{"a":[{"b":"c"}]}`, `{"a":[1]}`, `{"a":[1]}`},
+		{`["a","b"]`, `["c","d"]`, `["c","d"]`},
+		{`{"a":"b"}`, `["c"]`, `["c"]`},
+		{`{"a":"foo"}`, `null`, `null`},
+		{`{"a":"foo"}`, `"bar"`, `"bar"`},
+		{`{"e":null}`, `{"a":1}`, `{"a":1,"e":null}`},
+		{`[1,2]`, `{"a":"b","c":null}`, `{"a":"b"}`},
+		{`{}`, `{"a":{"bb":{"ccc":null}}}`, `{"a":{"bb":{}}}`},
+	} {
+		result, err := MergePatch([]byte(example.doc), []byte(example.patch))
+		assert.NoError(t, err)
+		assert.JSONEq(t, example.result, string(result), example.doc+" + "+example.patch)
+	}
+
+	_, err := MergePatch([]byte(`{}`), []byte(`{"a":`))
+	assert.ErrorIs(t, err, ErrInvalidPatch)
+	_, err = MergePatch([]byte(`{} {}`), []byte(`{}`))
+	assert.ErrorIs(t, err, ErrInvalidDocument)
+}
+
+// TestApply checks the examples of RFC 6902 appendix A
+func TestApply(t *testing.T) {
+	for name, example := range map[string]struct{ doc, patch, result string }{
+		"add member":        {`{"foo":"bar"}`, `[{"op":"add","path":"/baz","value":"qux"}]`, `{"baz":"qux","foo":"bar"}`},
+		"add array element": {`{"foo":["bar","baz"]}`, `[{"op":"add","path":"/foo/1","value":"qux"}]`, `{"foo":["bar","qux","baz"]}`},
+		"remove member":     {`{"baz":"qux","foo":"bar"}`, `[{"op":"remove","path":"/baz"}]`, `{"foo":"bar"}`},
+		"remove element":    {`{"foo":["bar","qux","baz"]}`, `[{"op":"remove","path":"/foo/1"}]`, `{"foo":["bar","baz"]}`},
+		"replace":           {`{"baz":"qux","foo":"bar"}`, `[{"op":"replace","path":"/baz","value":"boo"}]`, `{"baz":"boo","foo":"bar"}`},
+		"move member": {`{"foo":{"bar":"baz","waldo":"fred"},"qux":{"corge":"grault"}}`, `[{"op":"move","from":"/foo/waldo","path":"/qux/thud"}]`,
+			`{"foo":{"bar":"baz"},"qux":{"corge":"grault","thud":"fred"}}`},
+		"move element":    {`{"foo":["all","grass","cows","eat"]}`, `[{"op":"move","from":"/foo/1","path":"/foo/3"}]`, `{"foo":["all","cows","eat","grass"]}`},
+		"test":            {`{"baz":"qux","foo":["a",2,"c"]}`, `[{"op":"test","path":"/baz","value":"qux"},{"op":"test","path":"/foo/1","value":2.0}]`, `{"baz":"qux","foo":["a",2,"c"]}`},
+		"add nested":      {`{"foo":"bar"}`, `[{"op":"add","path":"/child","value":{"grandchild":{}}}]`, `{"foo":"bar","child":{"grandchild":{}}}`},
+		"ignore unknown":  {`{"foo":"bar"}`, `[{"op":"add","path":"/baz","value":"qux","xyz":123}]`, `{"foo":"bar","baz":"qux"}`},
+		"escaped":         {`{"/":9,"~1":10}`, `[{"op":"test","path":"/~01","value":10},{"op":"remove","path":"/~1"}]`, `{"~1":10}`},
+		"add array value": {`{"foo":["bar"]}`, `[{"op":"add","path":"/foo/-","value":["abc","def"]}]`, `{"foo":["bar",["abc","def"]]}`},
+		"add null":        {`{"foo":"bar"}`, `[{"op":"add","path":"/foo","value":null}]`, `{"foo":null}`},
+		"copy":            {`{"a":{"b":[1]}}`, `[{"op":"copy","from":"/a","path":"/c"},{"op":"add","path":"/c/b/-","value":2}]`, `{"a":{"b":[1]},"c":{"b":[1,2]}}`},
+		"replace root":    {`{"a":1}`, `[{"op":"replace","path":"","value":[1]}]`, `[1]`},
+	} {
+		result, err := Apply([]byte(example.doc), []byte(example.patch))
+		if assert.NoError(t, err, name) {
+			assert.JSONEq(t, example.result, string(result), name)
+		}
+	}
+}
+
+// TestApply_Errors tests that malformed patches, missing locations and failed tests are told apart
+func TestApply_Errors(t *testing.T) {
+	for name, example := range map[string]struct {
+		doc, patch string
+		err        error
+	}{
+		"not an array":       {`{}`, `{"op":"add"}`, ErrInvalidPatch},
+		"unknown op":         {`{}`, `[{"op":"merge","path":"/a","value":1}]`, ErrInvalidPatch},
+		"no path":            {`{}`, `[{"op":"add","value":1}]`, ErrInvalidPatch},
+		"no value":           {`{}`, `[{"op":"add","path":"/a"}]`, ErrInvalidPatch},
+		"relative pointer":   {`{}`, `[{"op":"add","path":"a","value":1}]`, ErrInvalidPatch},
+		"bad escape":         {`{}`, `[{"op":"add","path":"/a~2","value":1}]`, ErrInvalidPatch},
+		"leading zero":       {`{"a":[1,2]}`, `[{"op":"remove","path":"/a/01"}]`, ErrInvalidPatch},
+		"move into child":    {`{"a":{"b":1}}`, `[{"op":"move","from":"/a","path":"/a/c"}]`, ErrInvalidPatch},
+		"missing parent":     {`{"q":{"bar":2}}`, `[{"op":"add","path":"/a/b","value":1}]`, ErrPathNotFound},
+		"remove missing":     {`{"a":1}`, `[{"op":"remove","path":"/b"}]`, ErrPathNotFound},
+		"replace missing":    {`{"a":1}`, `[{"op":"replace","path":"/b","value":2}]`, ErrPathNotFound},
+		"index out of range": {`{"a":[1]}`, `[{"op":"add","path":"/a/2","value":2}]`, ErrPathNotFound},
+		"test failed":        {`{"baz":"qux"}`, `[{"op":"test","path":"/baz","value":"bar"}]`, ErrTestFailed},
+		"test type":          {`{"a":"1"}`, `[{"op":"test","path":"/a","value":1}]`, ErrTestFailed},
+	} {
+		_, err := Apply([]byte(example.doc), []byte(example.patch))
+		assert.ErrorIs(t, err, example.err, name)
+	}
+}
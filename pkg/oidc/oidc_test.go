@@ -0,0 +1,102 @@
+package oidc_test
+
+import (
+	"context"
+	"hub_management_service/pkg/oidc"
+	"hub_management_service/pkg/oidc/oidctest"
+	"testing"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func newClient(idp *oidctest.Server) *oidc.Client {
+	return oidc.NewClient(oidc.Config{
+		Issuer:      idp.URL,
+		ClientID:    idp.ClientID,
+		RedirectURL: "http://localhost:8080/auth/oidc/callback",
+	}, nil)
+}
+
+// TestAuthorizationCodeFlow tests the full flow against the mock provider
+func TestAuthorizationCodeFlow(t *testing.T) {
+	idp := oidctest.NewServer("hub-service")
+	defer idp.Close()
+	client := newClient(idp)
+	ctx := context.Background()
+
+	verifier, _ := oidc.RandomString()
+	authURL, err := client.AuthCodeURL(ctx, "state-1", "nonce-1", oidc.CodeChallenge(verifier))
+	assert.NoError(t, err)
+
+	redirect, err := idp.Authorize(authURL)
+	assert.NoError(t, err)
+	assert.Equal(t, "state-1", redirect.Query().Get("state"))
+
+	claims, err := client.Exchange(ctx, redirect.Query().Get("code"), verifier, "nonce-1")
+	assert.NoError(t, err)
+	assert.Equal(t, "user-1", claims.Subject)
+	assert.Equal(t, "user@example.com", claims.Email)
+	assert.True(t, claims.EmailVerified)
+}
+
+// TestExchange_WrongVerifier tests that the provider rejects a code redeemed without the matching PKCE verifier
+func TestExchange_WrongVerifier(t *testing.T) {
+	idp := oidctest.NewServer("hub-service")
+	defer idp.Close()
+	client := newClient(idp)
+	ctx := context.Background()
+
+	authURL, _ := client.AuthCodeURL(ctx, "state", "nonce", oidc.CodeChallenge("right-verifier"))
+	redirect, _ := idp.Authorize(authURL)
+
+	_, err := client.Exchange(ctx, redirect.Query().Get("code"), "wrong-verifier", "nonce")
+	assert.Error(t, err)
+}
+
+// TestVerifyIDToken_Rejections tests the claim checks on ID tokens
+func TestVerifyIDToken_Rejections(t *testing.T) {
+	idp := oidctest.NewServer("hub-service")
+	defer idp.Close()
+	client := newClient(idp)
+	ctx := context.Background()
+
+	valid := func() jwt.MapClaims {
+		return jwt.MapClaims{
+			"iss":   idp.URL,
+			"sub":   "user-1",
+			"aud":   "hub-service",
+			"exp":   time.Now().Add(time.Minute).Unix(),
+			"nonce": "nonce",
+		}
+	}
+
+	_, err := client.VerifyIDToken(ctx, idp.SignIDToken(valid()), "nonce")
+	assert.NoError(t, err)
+
+	// Audience given as an array is accepted
+	claims := valid()
+	claims["aud"] = []string{"other", "hub-service"}
+	_, err = client.VerifyIDToken(ctx, idp.SignIDToken(claims), "nonce")
+	assert.NoError(t, err)
+
+	cases := map[string]func(jwt.MapClaims){
+		"wrong issuer":   func(c jwt.MapClaims) { c["iss"] = "https://evil.example.com" },
+		"wrong audience": func(c jwt.MapClaims) { c["aud"] = "other" },
+		"expired":        func(c jwt.MapClaims) { c["exp"] = time.Now().Add(-time.Minute).Unix() },
+		"wrong nonce":    func(c jwt.MapClaims) { c["nonce"] = "replayed" },
+		"no subject":     func(c jwt.MapClaims) { delete(c, "sub") },
+	}
+	for name, mutate := range cases {
+		claims := valid()
+		mutate(claims)
+		_, err := client.VerifyIDToken(ctx, idp.SignIDToken(claims), "nonce")
+		assert.Error(t, err, name)
+	}
+}
+
+// TestCodeChallenge tests the S256 challenge against the RFC 7636 example
+func TestCodeChallenge(t *testing.T) {
+	assert.Equal(t, "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM", oidc.CodeChallenge("dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"))
+}
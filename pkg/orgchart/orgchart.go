@@ -0,0 +1,145 @@
+// Package orgchart renders organisation charts as Graphviz DOT, Mermaid flowcharts or SVG.
+//
+// A chart is a tree of nodes. Children are drawn side by side on the level below their parent, while
+// members, such as the people of a team, are stacked directly under it. Lines connect nodes anywhere
+// in the tree, such as managers to their reports, and are drawn dashed.
+package orgchart
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// Content types of the rendered charts
+const (
+	DOTContentType     = "text/vnd.graphviz; charset=utf-8"
+	MermaidContentType = "text/plain; charset=utf-8"
+	SVGContentType     = "image/svg+xml"
+)
+
+// Kind is the type of a node, which sets its shape and colour
+type Kind string
+
+const (
+	KindHub  Kind = "hub"
+	KindTeam Kind = "team"
+	KindUser Kind = "user"
+)
+
+// Node is a box of the chart
+type Node struct {
+	ID       string // Unique within the chart, letters, digits and underscores only
+	Kind     Kind
+	Label    string
+	Detail   string // Optional second line
+	Members  []*Node
+	Children []*Node
+}
+
+// Line connects two nodes outside of the tree, From and To are node IDs
+type Line struct {
+	From string
+	To   string
+}
+
+// Chart is a tree of nodes with additional lines between them
+type Chart struct {
+	Root  *Node
+	Lines []Line
+}
+
+// walk visits the nodes of the chart depth first with their parent, members before children
+func (c *Chart) walk(fn func(node, parent *Node)) {
+	var visit func(node, parent *Node)
+	visit = func(node, parent *Node) {
+		fn(node, parent)
+		for _, member := range node.Members {
+			visit(member, node)
+		}
+		for _, child := range node.Children {
+			visit(child, node)
+		}
+	}
+	if c.Root != nil {
+		visit(c.Root, nil)
+	}
+}
+
+// DOT renders the chart as a Graphviz digraph
+func (c *Chart) DOT() []byte {
+	var buf bytes.Buffer
+	buf.WriteString("digraph orgchart {\n")
+	buf.WriteString("  rankdir=TB;\n")
+	buf.WriteString("  node [fontname=\"Helvetica\", fontsize=11];\n")
+	c.walk(func(node, parent *Node) {
+		label := dotQuote(node.Label)
+		if node.Detail != "" {
+			label = dotQuote(node.Label + "\n" + node.Detail)
+		}
+		fmt.Fprintf(&buf, "  %s [label=%s, %s];\n", node.ID, label, dotStyles[node.Kind])
+	})
+	c.walk(func(node, parent *Node) {
+		if parent != nil {
+			fmt.Fprintf(&buf, "  %s -> %s;\n", parent.ID, node.ID)
+		}
+	})
+	for _, line := range c.Lines {
+		fmt.Fprintf(&buf, "  %s -> %s [style=dashed, color=\"#c0392b\", constraint=false];\n", line.From, line.To)
+	}
+	buf.WriteString("}\n")
+	return buf.Bytes()
+}
+
+var dotStyles = map[Kind]string{
+	KindHub:  `shape=box, style="filled", fillcolor="#1f4e79", fontcolor="white"`,
+	KindTeam: `shape=box, style="rounded,filled", fillcolor="#dbe9f6"`,
+	KindUser: `shape=box, style="filled", fillcolor="white"`,
+}
+
+// dotQuote quotes a DOT string, newlines become centred line breaks
+func dotQuote(s string) string {
+	s = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\r", "", "\n", `\n`).Replace(s)
+	return `"` + s + `"`
+}
+
+// Mermaid renders the chart as a Mermaid flowchart
+func (c *Chart) Mermaid() []byte {
+	var buf bytes.Buffer
+	buf.WriteString("flowchart TD\n")
+	c.walk(func(node, parent *Node) {
+		label := mermaidEscape(node.Label)
+		if node.Detail != "" {
+			label += "<br/>" + mermaidEscape(node.Detail)
+		}
+		shape := mermaidShapes[node.Kind]
+		fmt.Fprintf(&buf, "  %s%s\"%s\"%s\n", node.ID, shape[0], label, shape[1])
+	})
+	c.walk(func(node, parent *Node) {
+		if parent != nil {
+			fmt.Fprintf(&buf, "  %s --> %s\n", parent.ID, node.ID)
+		}
+	})
+	for _, line := range c.Lines {
+		fmt.Fprintf(&buf, "  %s -.-> %s\n", line.From, line.To)
+	}
+	buf.WriteString("  classDef hub fill:#1f4e79,color:#fff\n")
+	buf.WriteString("  classDef team fill:#dbe9f6\n")
+	c.walk(func(node, parent *Node) {
+		if node.Kind == KindHub || node.Kind == KindTeam {
+			fmt.Fprintf(&buf, "  class %s %s\n", node.ID, node.Kind)
+		}
+	})
+	return buf.Bytes()
+}
+
+var mermaidShapes = map[Kind][2]string{
+	KindHub:  {"[[", "]]"},
+	KindTeam: {"(", ")"},
+	KindUser: {"[", "]"},
+}
+
+// mermaidEscape escapes the characters that end or break a quoted Mermaid label
+func mermaidEscape(s string) string {
+	return strings.NewReplacer(`"`, "#quot;", "<", "#lt;", ">", "#gt;", "\r", "", "\n", " ").Replace(s)
+}
@@ -0,0 +1,77 @@
+package orgchart
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testChart is a hub with a team of two people, one managing the other, and an empty sub-team
+func testChart() *Chart {
+	return &Chart{
+		Root: &Node{ID: "hub_1", Kind: KindHub, Label: "Paris Hub", Detail: "Paris", Children: []*Node{
+			{ID: "team_2", Kind: KindTeam, Label: `Backend "Core"`, Detail: "2 people",
+				Members: []*Node{
+					{ID: "user_7", Kind: KindUser, Label: "Jane <Doe>", Detail: "Engineering Manager"},
+					{ID: "user_8", Kind: KindUser, Label: "John"},
+				},
+				Children: []*Node{{ID: "team_3", Kind: KindTeam, Label: "Payments"}},
+			},
+		}},
+		Lines: []Line{{From: "user_7", To: "user_8"}},
+	}
+}
+
+func TestDOT(t *testing.T) {
+	dot := string(testChart().DOT())
+	assert.True(t, strings.HasPrefix(dot, "digraph orgchart {\n"))
+	assert.Contains(t, dot, `team_2 [label="Backend \"Core\"\n2 people", shape=box, style="rounded,filled"`)
+	assert.Contains(t, dot, "  hub_1 -> team_2;\n")
+	assert.Contains(t, dot, "  team_2 -> user_8;\n")
+	assert.Contains(t, dot, "  team_2 -> team_3;\n")
+	assert.Contains(t, dot, "  user_7 -> user_8 [style=dashed")
+}
+
+func TestMermaid(t *testing.T) {
+	mermaid := string(testChart().Mermaid())
+	assert.True(t, strings.HasPrefix(mermaid, "flowchart TD\n"))
+	assert.Contains(t, mermaid, `  hub_1[["Paris Hub<br/>Paris"]]`)
+	assert.Contains(t, mermaid, `  team_2("Backend #quot;Core#quot;<br/>2 people")`)
+	assert.Contains(t, mermaid, `  user_7["Jane #lt;Doe#gt;<br/>Engineering Manager"]`)
+	assert.Contains(t, mermaid, "  user_7 -.-> user_8\n")
+	assert.Contains(t, mermaid, "  class team_3 team\n")
+}
+
+// TestSVG tests that the image is well-formed, with members stacked under their team and children below them
+func TestSVG(t *testing.T) {
+	svg := testChart().SVG()
+	decoder := xml.NewDecoder(bytes.NewReader(svg))
+	for {
+		_, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if !assert.NoError(t, err) {
+			return
+		}
+	}
+	assert.Contains(t, string(svg), "Jane &lt;Doe&gt;")
+	assert.Contains(t, string(svg), `stroke-dasharray="5 4"`)
+
+	layout := newSVGLayout(testChart().Root)
+	team, jane, john, payments := layout.positions["team_2"], layout.positions["user_7"], layout.positions["user_8"], layout.positions["team_3"]
+	assert.Equal(t, team.X, jane.X)
+	assert.Equal(t, team.Y+boxHeight+memberGap, jane.Y)
+	assert.Equal(t, jane.Y+boxHeight+memberGap, john.Y)
+	assert.Equal(t, john.Y+boxHeight+levelGap, payments.Y)
+	assert.Equal(t, payments.Y+boxHeight+margin, layout.height)
+}
+
+func TestTruncate(t *testing.T) {
+	assert.Equal(t, "Short", truncate("Short"))
+	assert.Equal(t, maxLabelLen, len([]rune(truncate(strings.Repeat("x", 40)))))
+}
@@ -0,0 +1,167 @@
+package orgchart
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"strings"
+)
+
+// Dimensions of the SVG layout, in pixels
+const (
+	boxWidth    = 180.0
+	boxHeight   = 44.0
+	columnGap   = 24.0 // Between the subtrees of siblings
+	levelGap    = 48.0 // Between a node, or its last member, and its children
+	memberGap   = 12.0 // Between stacked members
+	margin      = 20.0
+	maxLabelLen = 26 // Longer labels are cut to fit the boxes
+)
+
+var svgFills = map[Kind][2]string{ // Box fill and text colour
+	KindHub:  {"#1f4e79", "#ffffff"},
+	KindTeam: {"#dbe9f6", "#1a1a1a"},
+	KindUser: {"#ffffff", "#1a1a1a"},
+}
+
+// point is the top left corner of a box
+type point struct {
+	X, Y float64
+}
+
+// svgLayout places every node of a chart: members stacked under their node, children side by side below
+type svgLayout struct {
+	widths    map[*Node]float64
+	positions map[string]point
+	edges     []string // Path data of the tree connectors
+	width     float64
+	height    float64
+}
+
+func newSVGLayout(root *Node) *svgLayout {
+	l := &svgLayout{widths: make(map[*Node]float64), positions: make(map[string]point)}
+	l.measure(root)
+	l.place(root, margin, margin)
+	l.width = l.widths[root] + 2*margin
+	return l
+}
+
+// measure computes the width of the subtree of a node
+func (l *svgLayout) measure(node *Node) float64 {
+	children := 0.0
+	for i, child := range node.Children {
+		if i > 0 {
+			children += columnGap
+		}
+		children += l.measure(child)
+	}
+	width := boxWidth
+	if children > width {
+		width = children
+	}
+	l.widths[node] = width
+	return width
+}
+
+// place positions a node at the top centre of the area starting at left and top, then its members and children
+func (l *svgLayout) place(node *Node, left, top float64) {
+	centre := left + l.widths[node]/2
+	l.positions[node.ID] = point{centre - boxWidth/2, top}
+	bottom := top + boxHeight
+
+	for _, member := range node.Members {
+		memberTop := bottom + memberGap
+		l.edges = append(l.edges, fmt.Sprintf("M%g %gV%g", centre, bottom, memberTop))
+		l.positions[member.ID] = point{centre - boxWidth/2, memberTop}
+		bottom = memberTop + boxHeight
+	}
+	l.grow(bottom)
+	if len(node.Children) == 0 {
+		return
+	}
+
+	childrenWidth := -columnGap
+	for _, child := range node.Children {
+		childrenWidth += l.widths[child] + columnGap
+	}
+	childTop := bottom + levelGap
+	x := left + (l.widths[node]-childrenWidth)/2
+	for _, child := range node.Children {
+		childCentre := x + l.widths[child]/2
+		l.edges = append(l.edges, fmt.Sprintf("M%g %gV%gH%gV%g", centre, bottom, bottom+levelGap/2, childCentre, childTop))
+		l.place(child, x, childTop)
+		x += l.widths[child] + columnGap
+	}
+}
+
+func (l *svgLayout) grow(bottom float64) {
+	if bottom+margin > l.height {
+		l.height = bottom + margin
+	}
+}
+
+// SVG renders the chart as a standalone SVG image
+func (c *Chart) SVG() []byte {
+	var buf bytes.Buffer
+	if c.Root == nil {
+		buf.WriteString(`<svg xmlns="http://www.w3.org/2000/svg" width="0" height="0"/>` + "\n")
+		return buf.Bytes()
+	}
+	layout := newSVGLayout(c.Root)
+
+	fmt.Fprintf(&buf, `<svg xmlns="http://www.w3.org/2000/svg" width="%g" height="%g" viewBox="0 0 %g %g" font-family="Helvetica, Arial, sans-serif">`+"\n",
+		layout.width, layout.height, layout.width, layout.height)
+	fmt.Fprintf(&buf, `<rect width="100%%" height="100%%" fill="#ffffff"/>`+"\n")
+	buf.WriteString(`<g fill="none" stroke="#7f8c8d" stroke-width="1.5">` + "\n")
+	for _, edge := range layout.edges {
+		fmt.Fprintf(&buf, `<path d="%s"/>`+"\n", edge)
+	}
+	buf.WriteString("</g>\n")
+
+	c.walk(func(node, parent *Node) {
+		p := layout.positions[node.ID]
+		colours := svgFills[node.Kind]
+		fmt.Fprintf(&buf, `<g id="%s"><rect x="%g" y="%g" width="%g" height="%g" rx="6" fill="%s" stroke="#5d6d7e"/>`,
+			svgEscape(node.ID), p.X, p.Y, boxWidth, boxHeight, colours[0])
+		labelY := p.Y + boxHeight/2 + 4
+		if node.Detail != "" {
+			labelY = p.Y + 18
+		}
+		fmt.Fprintf(&buf, `<text x="%g" y="%g" text-anchor="middle" font-size="13" font-weight="bold" fill="%s">%s</text>`,
+			p.X+boxWidth/2, labelY, colours[1], svgEscape(truncate(node.Label)))
+		if node.Detail != "" {
+			fmt.Fprintf(&buf, `<text x="%g" y="%g" text-anchor="middle" font-size="11" fill="%s">%s</text>`,
+				p.X+boxWidth/2, p.Y+34, colours[1], svgEscape(truncate(node.Detail)))
+		}
+		buf.WriteString("</g>\n")
+	})
+
+	// Lines run between the right edges of their boxes, bending outwards
+	buf.WriteString(`<g fill="none" stroke="#c0392b" stroke-width="1.5" stroke-dasharray="5 4">` + "\n")
+	for _, line := range c.Lines {
+		from, ok := layout.positions[line.From]
+		to, found := layout.positions[line.To]
+		if !ok || !found {
+			continue
+		}
+		x1, y1 := from.X+boxWidth, from.Y+boxHeight/2
+		x2, y2 := to.X+boxWidth, to.Y+boxHeight/2
+		fmt.Fprintf(&buf, `<path d="M%g %gC%g %g %g %g %g %g"/>`+"\n", x1, y1, x1+columnGap, y1, x2+columnGap, y2, x2, y2)
+	}
+	buf.WriteString("</g>\n</svg>\n")
+	return buf.Bytes()
+}
+
+// truncate cuts a label to fit a box
+func truncate(s string) string {
+	if runes := []rune(s); len(runes) > maxLabelLen {
+		return strings.TrimSpace(string(runes[:maxLabelLen-1])) + "…"
+	}
+	return s
+}
+
+func svgEscape(s string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
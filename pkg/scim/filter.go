@@ -0,0 +1,387 @@
+package scim
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Expression is a node of a parsed filter, see RFC 7644 section 3.4.2.2
+type Expression interface {
+	isExpression()
+}
+
+// AttributeExpression compares an attribute with a value, Value is nil for the "pr" operator
+type AttributeExpression struct {
+	Path     string
+	Operator string
+	Value    interface{}
+}
+
+// LogicalExpression combines two expressions with "and" or "or"
+type LogicalExpression struct {
+	Operator string
+	Left     Expression
+	Right    Expression
+}
+
+type NotExpression struct {
+	Expression Expression
+}
+
+func (AttributeExpression) isExpression() {}
+func (LogicalExpression) isExpression()   {}
+func (NotExpression) isExpression()       {}
+
+var comparisonOperators = map[string]bool{
+	"eq": true, "ne": true, "co": true, "sw": true, "ew": true,
+	"gt": true, "ge": true, "lt": true, "le": true,
+}
+
+// ParseFilter parses a filter expression. Attribute paths are lower-cased and stripped of core schema URNs,
+// and value paths such as emails[type eq "work"] are flattened to emails.type eq "work".
+func ParseFilter(filter string) (Expression, error) {
+	tokens, err := tokenize(filter)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens}
+	expr, err := p.parseOr("")
+	if err != nil {
+		return nil, err
+	}
+	if !p.done() {
+		return nil, invalidFilter("unexpected %q", p.peek().text)
+	}
+	return expr, nil
+}
+
+type tokenKind int
+
+const (
+	tokenWord tokenKind = iota
+	tokenString
+	tokenOpenParen
+	tokenCloseParen
+	tokenOpenBracket
+	tokenCloseBracket
+)
+
+type token struct {
+	kind tokenKind
+	text string
+}
+
+func tokenize(input string) ([]token, error) {
+	var tokens []token
+	runes := []rune(input)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			tokens = append(tokens, token{tokenOpenParen, "("})
+			i++
+		case r == ')':
+			tokens = append(tokens, token{tokenCloseParen, ")"})
+			i++
+		case r == '[':
+			tokens = append(tokens, token{tokenOpenBracket, "["})
+			i++
+		case r == ']':
+			tokens = append(tokens, token{tokenCloseBracket, "]"})
+			i++
+		case r == '"':
+			// JSON string, including escapes
+			j := i + 1
+			for j < len(runes) && runes[j] != '"' {
+				if runes[j] == '\\' {
+					j++
+				}
+				j++
+			}
+			if j >= len(runes) {
+				return nil, invalidFilter("unterminated string")
+			}
+			value, err := strconv.Unquote(string(runes[i : j+1]))
+			if err != nil {
+				return nil, invalidFilter("invalid string %s", string(runes[i:j+1]))
+			}
+			tokens = append(tokens, token{tokenString, value})
+			i = j + 1
+		default:
+			j := i
+			for j < len(runes) && !unicode.IsSpace(runes[j]) && !strings.ContainsRune("()[]\"", runes[j]) {
+				j++
+			}
+			tokens = append(tokens, token{tokenWord, string(runes[i:j])})
+			i = j
+		}
+	}
+	return tokens, nil
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) done() bool {
+	return p.pos >= len(p.tokens)
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() (token, error) {
+	if p.done() {
+		return token{}, invalidFilter("unexpected end of filter")
+	}
+	t := p.tokens[p.pos]
+	p.pos++
+	return t, nil
+}
+
+func (p *parser) peekKeyword(keyword string) bool {
+	return !p.done() && p.peek().kind == tokenWord && strings.EqualFold(p.peek().text, keyword)
+}
+
+func (p *parser) parseOr(prefix string) (Expression, error) {
+	left, err := p.parseAnd(prefix)
+	if err != nil {
+		return nil, err
+	}
+	for p.peekKeyword("or") {
+		p.pos++
+		right, err := p.parseAnd(prefix)
+		if err != nil {
+			return nil, err
+		}
+		left = LogicalExpression{Operator: "or", Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd(prefix string) (Expression, error) {
+	left, err := p.parseUnary(prefix)
+	if err != nil {
+		return nil, err
+	}
+	for p.peekKeyword("and") {
+		p.pos++
+		right, err := p.parseUnary(prefix)
+		if err != nil {
+			return nil, err
+		}
+		left = LogicalExpression{Operator: "and", Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseUnary(prefix string) (Expression, error) {
+	if p.peekKeyword("not") {
+		p.pos++
+		inner, err := p.parseGroup(prefix)
+		if err != nil {
+			return nil, err
+		}
+		return NotExpression{Expression: inner}, nil
+	}
+	if !p.done() && p.peek().kind == tokenOpenParen {
+		return p.parseGroup(prefix)
+	}
+	return p.parseAttribute(prefix)
+}
+
+func (p *parser) parseGroup(prefix string) (Expression, error) {
+	if t, err := p.next(); err != nil || t.kind != tokenOpenParen {
+		return nil, invalidFilter("expected (")
+	}
+	expr, err := p.parseOr(prefix)
+	if err != nil {
+		return nil, err
+	}
+	if t, err := p.next(); err != nil || t.kind != tokenCloseParen {
+		return nil, invalidFilter("expected )")
+	}
+	return expr, nil
+}
+
+func (p *parser) parseAttribute(prefix string) (Expression, error) {
+	t, err := p.next()
+	if err != nil {
+		return nil, err
+	}
+	if t.kind != tokenWord {
+		return nil, invalidFilter("expected attribute, got %q", t.text)
+	}
+	path := prefix + NormalizePath(t.text)
+
+	// Value path filter, e.g. emails[type eq "work"]
+	if !p.done() && p.peek().kind == tokenOpenBracket {
+		p.pos++
+		inner, err := p.parseOr(path + ".")
+		if err != nil {
+			return nil, err
+		}
+		if t, err := p.next(); err != nil || t.kind != tokenCloseBracket {
+			return nil, invalidFilter("expected ]")
+		}
+		return inner, nil
+	}
+
+	op, err := p.next()
+	if err != nil {
+		return nil, err
+	}
+	operator := strings.ToLower(op.text)
+	if op.kind == tokenWord && operator == "pr" {
+		return AttributeExpression{Path: path, Operator: operator}, nil
+	}
+	if op.kind != tokenWord || !comparisonOperators[operator] {
+		return nil, invalidFilter("unknown operator %q", op.text)
+	}
+
+	valueToken, err := p.next()
+	if err != nil {
+		return nil, err
+	}
+	value, err := parseValue(valueToken)
+	if err != nil {
+		return nil, err
+	}
+	return AttributeExpression{Path: path, Operator: operator, Value: value}, nil
+}
+
+func parseValue(t token) (interface{}, error) {
+	if t.kind == tokenString {
+		return t.text, nil
+	}
+	if t.kind != tokenWord {
+		return nil, invalidFilter("expected value, got %q", t.text)
+	}
+	switch strings.ToLower(t.text) {
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	case "null":
+		return nil, nil
+	}
+	if n, err := strconv.ParseFloat(t.text, 64); err == nil {
+		return n, nil
+	}
+	return nil, invalidFilter("invalid value %q", t.text)
+}
+
+// NormalizePath lower-cases an attribute path and strips core schema URN prefixes
+func NormalizePath(path string) string {
+	lower := strings.ToLower(path)
+	for _, schema := range []string{UserSchema, GroupSchema} {
+		prefix := strings.ToLower(schema) + ":"
+		if strings.HasPrefix(lower, prefix) {
+			return strings.TrimPrefix(lower, prefix)
+		}
+	}
+	return lower
+}
+
+func invalidFilter(format string, args ...interface{}) *Error {
+	return NewError(http.StatusBadRequest, "invalidFilter", format, args...)
+}
+
+// Column maps a filterable attribute to a database column
+type Column struct {
+	Name      string
+	CaseExact bool
+}
+
+// ToSQL translates a filter into a WHERE clause using the given attribute to column mapping.
+// Attributes missing from the mapping are rejected with an invalidFilter error.
+func ToSQL(expr Expression, columns map[string]Column) (string, []interface{}, error) {
+	switch e := expr.(type) {
+	case LogicalExpression:
+		left, leftArgs, err := ToSQL(e.Left, columns)
+		if err != nil {
+			return "", nil, err
+		}
+		right, rightArgs, err := ToSQL(e.Right, columns)
+		if err != nil {
+			return "", nil, err
+		}
+		return fmt.Sprintf("(%s %s %s)", left, strings.ToUpper(e.Operator), right), append(leftArgs, rightArgs...), nil
+	case NotExpression:
+		inner, args, err := ToSQL(e.Expression, columns)
+		if err != nil {
+			return "", nil, err
+		}
+		return "NOT (" + inner + ")", args, nil
+	case AttributeExpression:
+		return attributeToSQL(e, columns)
+	}
+	return "", nil, invalidFilter("unsupported expression")
+}
+
+func attributeToSQL(e AttributeExpression, columns map[string]Column) (string, []interface{}, error) {
+	column, ok := columns[e.Path]
+	if !ok {
+		return "", nil, invalidFilter("filtering on %q is not supported", e.Path)
+	}
+
+	if e.Operator == "pr" {
+		return fmt.Sprintf("(%s IS NOT NULL)", column.Name), nil, nil
+	}
+	if e.Value == nil {
+		switch e.Operator {
+		case "eq":
+			return fmt.Sprintf("(%s IS NULL)", column.Name), nil, nil
+		case "ne":
+			return fmt.Sprintf("(%s IS NOT NULL)", column.Name), nil, nil
+		}
+		return "", nil, invalidFilter("null can only be compared with eq or ne")
+	}
+
+	str, isString := e.Value.(string)
+	lhs, placeholder := column.Name, "?"
+	if isString && !column.CaseExact {
+		lhs, placeholder = "LOWER("+column.Name+")", "LOWER(?)"
+	}
+
+	switch e.Operator {
+	case "eq":
+		return fmt.Sprintf("%s = %s", lhs, placeholder), []interface{}{e.Value}, nil
+	case "ne":
+		return fmt.Sprintf("%s <> %s", lhs, placeholder), []interface{}{e.Value}, nil
+	case "gt":
+		return fmt.Sprintf("%s > %s", lhs, placeholder), []interface{}{e.Value}, nil
+	case "ge":
+		return fmt.Sprintf("%s >= %s", lhs, placeholder), []interface{}{e.Value}, nil
+	case "lt":
+		return fmt.Sprintf("%s < %s", lhs, placeholder), []interface{}{e.Value}, nil
+	case "le":
+		return fmt.Sprintf("%s <= %s", lhs, placeholder), []interface{}{e.Value}, nil
+	}
+
+	// co, sw and ew only apply to strings
+	if !isString {
+		return "", nil, invalidFilter("operator %q requires a string value", e.Operator)
+	}
+	pattern := escapeLike(str)
+	switch e.Operator {
+	case "co":
+		pattern = "%" + pattern + "%"
+	case "sw":
+		pattern = pattern + "%"
+	case "ew":
+		pattern = "%" + pattern
+	}
+	return fmt.Sprintf("%s LIKE %s ESCAPE '\\'", lhs, placeholder), []interface{}{pattern}, nil
+}
+
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
@@ -0,0 +1,78 @@
+package scim
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var testColumns = map[string]Column{
+	"id":           {Name: "id", CaseExact: true},
+	"username":     {Name: "email"},
+	"emails.value": {Name: "email"},
+	"displayname":  {Name: "name"},
+}
+
+// TestParseFilter tests parsing of comparison, logical and grouping expressions
+func TestParseFilter(t *testing.T) {
+	expr, err := ParseFilter(`userName eq "bjensen" and (displayName co "Jen" or not (id pr))`)
+
+	assert.NoError(t, err)
+	assert.Equal(t, LogicalExpression{
+		Operator: "and",
+		Left:     AttributeExpression{Path: "username", Operator: "eq", Value: "bjensen"},
+		Right: LogicalExpression{
+			Operator: "or",
+			Left:     AttributeExpression{Path: "displayname", Operator: "co", Value: "Jen"},
+			Right:    NotExpression{Expression: AttributeExpression{Path: "id", Operator: "pr"}},
+		},
+	}, expr)
+}
+
+// TestParseFilter_ValuePathAndURN tests that value paths are flattened and schema URNs stripped
+func TestParseFilter_ValuePathAndURN(t *testing.T) {
+	expr, err := ParseFilter(`emails[value ew "@example.com"]`)
+	assert.NoError(t, err)
+	assert.Equal(t, AttributeExpression{Path: "emails.value", Operator: "ew", Value: "@example.com"}, expr)
+
+	expr, err = ParseFilter(`urn:ietf:params:scim:schemas:core:2.0:User:userName EQ "x"`)
+	assert.NoError(t, err)
+	assert.Equal(t, AttributeExpression{Path: "username", Operator: "eq", Value: "x"}, expr)
+}
+
+// TestParseFilter_Invalid tests that malformed filters are rejected with invalidFilter
+func TestParseFilter_Invalid(t *testing.T) {
+	for _, filter := range []string{
+		`userName`,
+		`userName xx "a"`,
+		`userName eq "unterminated`,
+		`(userName eq "a"`,
+		`userName eq "a" extra`,
+	} {
+		_, err := ParseFilter(filter)
+		if assert.Error(t, err, filter) {
+			assert.Equal(t, "invalidFilter", err.(*Error).ScimType)
+		}
+	}
+}
+
+// TestToSQL tests the translation of filters into WHERE clauses
+func TestToSQL(t *testing.T) {
+	expr, _ := ParseFilter(`userName eq "A@x.com" or (displayName sw "50%" and id ne "3")`)
+
+	where, args, err := ToSQL(expr, testColumns)
+
+	assert.NoError(t, err)
+	assert.Equal(t, `(LOWER(email) = LOWER(?) OR (LOWER(name) LIKE LOWER(?) ESCAPE '\' AND id <> ?))`, where)
+	assert.Equal(t, []interface{}{"A@x.com", `50\%%`, "3"}, args)
+}
+
+// TestToSQL_UnknownAttribute tests that unmapped attributes are rejected
+func TestToSQL_UnknownAttribute(t *testing.T) {
+	expr, _ := ParseFilter(`title eq "Tour Guide"`)
+
+	_, _, err := ToSQL(expr, testColumns)
+
+	assert.Error(t, err)
+	assert.Equal(t, "invalidFilter", err.(*Error).ScimType)
+}
@@ -0,0 +1,136 @@
+package scim
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// Schema URNs defined by RFC 7643 and RFC 7644
+const (
+	UserSchema         = "urn:ietf:params:scim:schemas:core:2.0:User"
+	GroupSchema        = "urn:ietf:params:scim:schemas:core:2.0:Group"
+	ListResponseSchema = "urn:ietf:params:scim:api:messages:2.0:ListResponse"
+	PatchOpSchema      = "urn:ietf:params:scim:api:messages:2.0:PatchOp"
+	ErrorSchema        = "urn:ietf:params:scim:api:messages:2.0:Error"
+)
+
+// Extension schemas carrying the attributes this service requires but SCIM does not define
+const (
+	UserExtensionSchema  = "urn:hub-management:params:scim:schemas:extension:2.0:User"
+	GroupExtensionSchema = "urn:hub-management:params:scim:schemas:extension:2.0:Group"
+)
+
+// ContentType is the media type of SCIM requests and responses
+const ContentType = "application/scim+json"
+
+// Meta is the resource metadata returned with every resource
+type Meta struct {
+	ResourceType string `json:"resourceType"`
+	Location     string `json:"location,omitempty"`
+	Version      string `json:"version,omitempty"`
+}
+
+type Name struct {
+	Formatted  string `json:"formatted,omitempty"`
+	GivenName  string `json:"givenName,omitempty"`
+	FamilyName string `json:"familyName,omitempty"`
+}
+
+type Email struct {
+	Value   string `json:"value"`
+	Type    string `json:"type,omitempty"`
+	Primary bool   `json:"primary,omitempty"`
+}
+
+// Reference is a multi-valued attribute pointing at another resource, such as group members
+type Reference struct {
+	Value   string `json:"value"`
+	Display string `json:"display,omitempty"`
+	Ref     string `json:"$ref,omitempty"`
+}
+
+type UserExtension struct {
+	TeamID string `json:"teamId,omitempty"`
+}
+
+type GroupExtension struct {
+	HubID string `json:"hubId,omitempty"`
+}
+
+type User struct {
+	Schemas     []string       `json:"schemas"`
+	ID          string         `json:"id,omitempty"`
+	ExternalID  string         `json:"externalId,omitempty"`
+	UserName    string         `json:"userName"`
+	Name        *Name          `json:"name,omitempty"`
+	DisplayName string         `json:"displayName,omitempty"`
+	Emails      []Email        `json:"emails,omitempty"`
+	Active      *bool          `json:"active,omitempty"`
+	Groups      []Reference    `json:"groups,omitempty"`
+	Extension   *UserExtension `json:"urn:hub-management:params:scim:schemas:extension:2.0:User,omitempty"`
+	Meta        *Meta          `json:"meta,omitempty"`
+}
+
+type Group struct {
+	Schemas     []string        `json:"schemas"`
+	ID          string          `json:"id,omitempty"`
+	ExternalID  string          `json:"externalId,omitempty"`
+	DisplayName string          `json:"displayName"`
+	Members     []Reference     `json:"members,omitempty"`
+	Extension   *GroupExtension `json:"urn:hub-management:params:scim:schemas:extension:2.0:Group,omitempty"`
+	Meta        *Meta           `json:"meta,omitempty"`
+}
+
+type ListResponse struct {
+	Schemas      []string    `json:"schemas"`
+	TotalResults int64       `json:"totalResults"`
+	StartIndex   int         `json:"startIndex"`
+	ItemsPerPage int         `json:"itemsPerPage"`
+	Resources    interface{} `json:"Resources"`
+}
+
+// PatchOperation is a single operation of a PATCH request, see RFC 7644 section 3.5.2
+type PatchOperation struct {
+	Op    string      `json:"op"`
+	Path  string      `json:"path,omitempty"`
+	Value interface{} `json:"value,omitempty"`
+}
+
+type PatchRequest struct {
+	Schemas    []string         `json:"schemas"`
+	Operations []PatchOperation `json:"Operations" binding:"required,min=1"`
+}
+
+// Error is both a Go error and the SCIM error response body
+type Error struct {
+	Schemas  []string `json:"schemas"`
+	Status   string   `json:"status"`
+	ScimType string   `json:"scimType,omitempty"`
+	Detail   string   `json:"detail,omitempty"`
+	code     int
+}
+
+func (e *Error) Error() string {
+	return e.Detail
+}
+
+// StatusCode returns the HTTP status of the error
+func (e *Error) StatusCode() int {
+	return e.code
+}
+
+// NewError creates an error response with the given status, scimType and detail
+func NewError(status int, scimType, format string, args ...interface{}) *Error {
+	return &Error{
+		Schemas:  []string{ErrorSchema},
+		Status:   fmt.Sprint(status),
+		ScimType: scimType,
+		Detail:   fmt.Sprintf(format, args...),
+		code:     status,
+	}
+}
+
+// ErrNotFound creates a 404 error for a missing resource
+func ErrNotFound(resourceType, id string) *Error {
+	return NewError(http.StatusNotFound, "", "%s %s not found", resourceType, id)
+}
@@ -0,0 +1,74 @@
+package totp
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// rfcSecret is the base32 encoding of the RFC 6238 SHA1 test key "12345678901234567890"
+const rfcSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+// TestGenerateCode checks generated codes against the RFC 6238 test vectors (truncated to 6 digits)
+func TestGenerateCode(t *testing.T) {
+	vectors := map[int64]string{
+		59:         "287082",
+		1111111109: "081804",
+		1234567890: "005924",
+		2000000000: "279037",
+	}
+
+	for unix, expected := range vectors {
+		code, err := GenerateCode(rfcSecret, time.Unix(unix, 0))
+		assert.NoError(t, err)
+		assert.Equal(t, expected, code)
+	}
+}
+
+// TestValidate tests that codes are accepted within the allowed skew only
+func TestValidate(t *testing.T) {
+	now := time.Unix(1234567890, 0)
+	code, _ := GenerateCode(rfcSecret, now)
+
+	assert.True(t, Validate(rfcSecret, code, now))
+	assert.True(t, Validate(rfcSecret, code, now.Add(Period*time.Second)))
+	assert.False(t, Validate(rfcSecret, code, now.Add(3*Period*time.Second)))
+	assert.False(t, Validate(rfcSecret, "12345", now))
+	assert.False(t, Validate("not base32!", code, now))
+}
+
+// TestValidateStep tests that the matched step is returned and that steps up to the last accepted one are refused
+func TestValidateStep(t *testing.T) {
+	now := time.Unix(1234567890, 0)
+	code, _ := GenerateCode(rfcSecret, now)
+	step := now.Unix() / Period
+
+	matched, ok := ValidateStep(rfcSecret, code, now, step-1)
+	assert.True(t, ok)
+	assert.Equal(t, step, matched)
+
+	_, ok = ValidateStep(rfcSecret, code, now, step)
+	assert.False(t, ok)
+}
+
+// TestGenerateSecret tests that generated secrets are usable for code generation
+func TestGenerateSecret(t *testing.T) {
+	secret, err := GenerateSecret()
+	assert.NoError(t, err)
+	assert.Len(t, secret, 32)
+
+	code, err := GenerateCode(secret, time.Now())
+	assert.NoError(t, err)
+	assert.True(t, Validate(secret, code, time.Now()))
+}
+
+// TestProvisioningURI tests the otpauth URI format
+func TestProvisioningURI(t *testing.T) {
+	uri := ProvisioningURI("Hub Management", "admin", rfcSecret)
+
+	assert.True(t, strings.HasPrefix(uri, "otpauth://totp/Hub%20Management:admin?"))
+	assert.Contains(t, uri, "secret="+rfcSecret)
+	assert.Contains(t, uri, "issuer=Hub+Management")
+}
@@ -0,0 +1,46 @@
+package xlsx
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestColumnName(t *testing.T) {
+	for index, name := range map[int]string{0: "A", 25: "Z", 26: "AA", 51: "AZ", 701: "ZZ", 702: "AAA"} {
+		assert.Equal(t, name, ColumnName(index))
+	}
+}
+
+// TestWriter tests that the workbook holds the expected parts and that cells are typed and escaped
+func TestWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := NewWriter(&buf, "Users: all")
+	assert.NoError(t, err)
+	assert.NoError(t, w.WriteRow([]interface{}{"id", "name", "team"}))
+	assert.NoError(t, w.WriteRow([]interface{}{uint(7), "Jane <Doe> & Co", nil, 1.5}))
+	assert.NoError(t, w.Close())
+
+	archive, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	assert.NoError(t, err)
+	parts := make(map[string]string)
+	for _, file := range archive.File {
+		r, _ := file.Open()
+		content, _ := io.ReadAll(r)
+		parts[file.Name] = string(content)
+	}
+	assert.Contains(t, parts, "[Content_Types].xml")
+	assert.Contains(t, parts, "_rels/.rels")
+	assert.Contains(t, parts["xl/workbook.xml"], `<sheet name="Users_ all"`)
+
+	sheet := parts["xl/worksheets/sheet1.xml"]
+	assert.Contains(t, sheet, `<c r="A2"><v>7</v></c>`)
+	assert.Contains(t, sheet, `<c r="B2" t="inlineStr"><is><t xml:space="preserve">Jane &lt;Doe&gt; &amp; Co</t></is></c>`)
+	assert.NotContains(t, sheet, `r="C2"`)
+	assert.Contains(t, sheet, `<c r="D2"><v>1.5</v></c>`)
+	assert.True(t, strings.HasSuffix(sheet, `</sheetData></worksheet>`))
+}